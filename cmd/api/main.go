@@ -2,8 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
@@ -12,6 +18,7 @@ import (
 	"renderowl-api/internal/config"
 	"renderowl-api/internal/domain"
 	socialdomain "renderowl-api/internal/domain/social"
+	"renderowl-api/internal/events"
 	"renderowl-api/internal/handlers"
 	"renderowl-api/internal/middleware"
 	"renderowl-api/internal/repository"
@@ -49,7 +56,10 @@ func main() {
 	analyticsRepo := repository.NewAnalyticsRepository(db)
 	socialAccountRepo := repository.NewSocialAccountRepository(db)
 	socialPostRepo := repository.NewSocialPostRepository(db)
+	socialCampaignRepo := repository.NewSocialCampaignRepository(db)
 	socialAnalyticsRepo := repository.NewSocialAnalyticsRepository(db)
+	userDataRepo := repository.NewUserDataRepository(db)
+	voiceRepo := repository.NewVoiceRepository(db)
 
 	// Seed default templates
 	if err := templateRepo.SeedDefaultTemplates(); err != nil {
@@ -67,29 +77,121 @@ func main() {
 	socialRegistry := social.NewPlatformRegistry()
 	socialService := social.NewService(socialRegistry, socialAccountRepo, socialPostRepo, socialAnalyticsRepo)
 	socialService.InitializePlatforms()
+	socialService.SetMinScheduleLead(cfg.ScheduleMinLead)
+	socialService.SetAccountMetrics(
+		social.NewPlatformAccountMetrics(socialRegistry),
+		repository.NewAccountMetricsRepository(db),
+	)
+	if cfg.ModerationEnabled {
+		moderationService := service.NewModerationService()
+		moderationService.SetOpenAIAccount(cfg.OpenAIOrganization, cfg.OpenAIProject)
+		socialService.SetModerator(moderationService, cfg.ModerationEnforce)
+	}
 
 	// Initialize publisher
-	publisher := service.NewPublisher(socialService, sched, socialPostRepo)
+	analyticsService := service.NewAnalyticsService(analyticsRepo)
+	if key := os.Getenv("YOUTUBE_API_KEY"); key != "" {
+		// YouTube push notifications only name the videos that changed
+		analyticsService.SetVideoStatsSource("youtube", service.NewYouTubeStatsFetcher(key))
+	}
+	publisher := service.NewPublisher(socialService, sched, socialPostRepo, analyticsService)
 	publisher.Initialize()
 
+	// Initialize campaigns, which publish variations through the publisher
+	storage := service.NewLocalStorage(cfg.StorageDir, cfg.StorageBaseURL)
+	variationsService := service.NewVariationsService(storage)
+	campaignService := service.NewCampaignService(socialCampaignRepo, variationsService, publisher)
+	campaignService.Initialize()
+
+	// Files uploaded for publishing are kept in storage for platforms to fetch
+	mediaUploads := service.NewMediaUploadService(storage, sched, cfg.UploadMaxBytes, cfg.UploadRetention)
+	mediaUploads.Initialize()
+	socialService.SetMediaFiles(mediaUploads)
+	mediaProber := service.NewMediaProbeService(storage, cfg.FFprobePath, cfg.MediaProbeMaxBytes)
+	if cfg.DuplicateWindow > 0 {
+		fingerprinter := service.NewVideoFingerprintService(storage, cfg.FFprobePath, cfg.FFmpegPath)
+		socialService.SetDuplicateDetection(fingerprinter, repository.NewVideoFingerprintRepository(db), cfg.DuplicateWindow)
+	}
+
+	// Generated artifacts are deleted from storage after their retention
+	artifactRetention := service.NewArtifactRetentionService(storage, repository.NewArtifactRepository(db), sched, cfg.ArtifactRetention)
+	artifactRetention.Initialize()
+
+	// Cancelled on SIGINT/SIGTERM to begin graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Start scheduler in background
-	go sched.ProcessJobs(context.Background())
+	if err := publisher.ScheduleAnalyticsSync(ctx); err != nil {
+		log.Printf("Warning: Failed to schedule analytics sync: %v", err)
+	}
+	if err := publisher.ReconcileRecurringPosts(ctx); err != nil {
+		log.Printf("Warning: Failed to reconcile recurring posts: %v", err)
+	}
+	if err := artifactRetention.ScheduleCleanup(ctx); err != nil {
+		log.Printf("Warning: Failed to schedule artifact cleanup: %v", err)
+	}
 
 	// Initialize services
+	timelineNoteRepo := repository.NewTimelineNoteRepository(db)
 	timelineService := service.NewTimelineService(timelineRepo)
+	timelineService.SetNoteStore(timelineNoteRepo)
 	clipService := service.NewClipService(clipRepo, timelineRepo)
 	trackService := service.NewTrackService(trackRepo, timelineRepo)
 	templateService := service.NewTemplateService(templateRepo, timelineRepo, trackRepo, clipRepo)
+	// Provider usage is written in the background so it doesn't slow requests
+	usageService := service.NewUsageService(repository.NewUsageRepository(db))
+	usageService.Start()
 	aiScriptService := service.NewAIScriptService()
+	aiScriptService.SetUsage(usageService)
+	aiScriptService.SetTimeouts(cfg.AITimeouts)
+	aiScriptService.SetOpenAIAccount(cfg.OpenAIOrganization, cfg.OpenAIProject)
 	aiSceneService := service.NewAISceneService()
+	aiSceneService.SetUsage(usageService)
+	aiSceneService.SetTimeouts(cfg.AITimeouts)
+	aiSceneService.SetOpenAIAccount(cfg.OpenAIOrganization, cfg.OpenAIProject)
+	socialService.SetAltTextGenerator(aiSceneService)
 	ttsService := service.NewTTSService()
-	analyticsService := service.NewAnalyticsService(analyticsRepo)
+	ttsService.SetUsage(usageService)
+	ttsService.SetTimeouts(cfg.AITimeouts)
+	ttsService.SetOpenAIAccount(cfg.OpenAIOrganization, cfg.OpenAIProject)
+	ttsService.SetVoiceStore(voiceRepo)
+	var promptTemplates *service.PromptTemplateService
+	if cfg.AIPromptOverrides {
+		promptTemplates = service.NewPromptTemplateService(repository.NewPromptTemplateRepository(db))
+		aiScriptService.SetPromptTemplates(promptTemplates)
+		aiSceneService.SetPromptTemplates(promptTemplates)
+	}
+	transcriptionService := service.NewTranscriptionService()
+	transcriptionService.SetTimeouts(cfg.AITimeouts)
+	transcriptionService.SetOpenAIAccount(cfg.OpenAIOrganization, cfg.OpenAIProject)
+	variationsService.SetTranscriber(transcriptionService)
+	socialService.SetPostCopyWriter(service.NewPostCopyService(aiScriptService, transcriptionService))
+	variationsService.SetWinningContent(analyticsRepo)
+	variationsService.SetTitleCritic(aiScriptService)
+	variationsService.SetThumbnailBackgrounds(aiSceneService)
+	variationsService.SetMediaProber(mediaProber)
+	variationsService.SetJobStore(repository.NewVariationJobRepository(db))
+	publisher.SetVariations(variationsService)
+	userDataService := service.NewUserDataService(userDataRepo, socialService)
+	userDataService.SetVoices(ttsService)
 
 	// Initialize Content Factory services
 	batchRepo := repository.NewBatchRepository(db)
+	batchTemplateRepo := repository.NewBatchTemplateRepository(db)
 	ideationService := service.NewIdeationService()
+	ideationService.SetWinningContent(analyticsRepo)
+	ideationService.SetAPIKey("youtube", os.Getenv("YOUTUBE_API_KEY"))
+	ideationService.SetAPIKey("twitter", os.Getenv("TWITTER_BEARER_TOKEN"))
+	if blocklist := os.Getenv("TRENDING_BLOCKLIST"); blocklist != "" {
+		ideationService.SetBlocklist(strings.Split(blocklist, ","))
+	}
+	if threshold, err := strconv.ParseFloat(os.Getenv("TRENDING_SIMILARITY_THRESHOLD"), 64); err == nil {
+		ideationService.SetSimilarityThreshold(threshold)
+	}
 	batchService, err := service.NewBatchService(
 		batchRepo,
+		batchTemplateRepo,
 		redisAddr,
 		os.Getenv("REDIS_PASSWORD"),
 		timelineService,
@@ -101,10 +203,28 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize batch service: %v", err)
 	}
-	defer batchService.Close()
-	variationsService := service.NewVariationsService(nil) // Storage provider would be initialized here
-	// optimizerService := service.NewOptimizerService(analyticsRepo, timelineRepo, socialService, aiScriptService)
-	_ = socialService // Used for future optimizer service integration
+	batchService.SetWorkerConfig(cfg.BatchWorkerConcurrency, cfg.BatchQueueWeights)
+	musicLibrary := service.NewMusicLibrary(cfg.StorageBaseURL)
+	batchService.SetMusicLibrary(musicLibrary, trackService)
+	batchService.SetMediaProber(mediaProber)
+	// Renderers subscribe to timeline.ready to pick up assembled timelines
+	eventBus := events.NewBus(redisAddr, os.Getenv("REDIS_PASSWORD"), 0)
+	batchService.SetEventPublisher(eventBus)
+	if err := batchService.StartWorkers(); err != nil {
+		log.Fatalf("Failed to start batch workers: %v", err)
+	}
+	contentStatusService := service.NewContentStatusService(batchRepo, socialPostRepo, socialCampaignRepo)
+	shareService := service.NewShareService(repository.NewShareLinkRepository(db), timelineRepo, cfg.ShareSigningSecret, cfg.ShareBaseURL)
+	shareService.SetTimelineNotes(timelineNoteRepo)
+	optimizerService := service.NewOptimizerService(analyticsService, aiScriptService)
+	optimizerService.SetWinningContentCache(analyticsRepo)
+	optimizerService.SetSuggestionStore(analyticsRepo)
+	optimizerService.SetThumbnailGenerator(variationsService)
+	optimizerService.RegisterJobs(sched)
+	shareService.SetReportGenerator(optimizerService)
+
+	// Start processing jobs once every handler is registered
+	go sched.ProcessJobs(ctx)
 
 	// Initialize handlers
 	timelineHandler := handlers.NewTimelineHandler(timelineService)
@@ -112,14 +232,26 @@ func main() {
 	trackHandler := handlers.NewTrackHandler(trackService)
 	templateHandler := handlers.NewTemplateHandler(templateService)
 	healthHandler := handlers.NewHealthHandler(db)
-	aiHandler := handlers.NewAIHandler(aiScriptService, aiSceneService, ttsService)
+	aiHandler := handlers.NewAIHandler(aiScriptService, aiSceneService, ttsService, transcriptionService)
+	aiHandler.SetPromptTemplates(promptTemplates)
 	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	userDataHandler := handlers.NewUserDataHandler(userDataService)
+	storageHandler := handlers.NewStorageHandler(storage)
+	mediaHandler := handlers.NewMediaHandler(mediaProber)
+	searchHandler := handlers.NewSearchHandler(service.NewSearchService(repository.NewSearchRepository(db)))
+	usageHandler := handlers.NewUsageHandler(usageService)
+	musicHandler := handlers.NewMusicHandler(musicLibrary)
+	contentStatusHandler := handlers.NewContentStatusHandler(contentStatusService)
+	shareHandler := handlers.NewShareHandler(shareService)
 	socialHandler := socialhandlers.NewSocialHandler(socialService, publisher, sched)
+	socialHandler.SetMediaUploads(mediaUploads)
+	campaignHandler := socialhandlers.NewCampaignHandler(campaignService)
+	adminHandler := handlers.NewAdminHandler(batchService, sched)
 	contentFactoryHandler := handlers.NewContentFactoryHandler(
 		ideationService,
 		batchService,
 		variationsService,
-		nil, // optimizerService - disabled due to interface mismatch
+		optimizerService,
 	)
 
 	// Setup router
@@ -133,22 +265,50 @@ func main() {
 	r.GET("/health/ready", healthHandler.ReadinessCheck)
 	r.GET("/health/live", healthHandler.LivenessCheck)
 
+	// Stored files (keys are unguessable IDs; players can't send auth headers)
+	r.GET("/files/*key", storageHandler.Serve)
+	r.HEAD("/files/*key", storageHandler.Serve)
+
+	// Share links (the signed token is the credential)
+	r.GET("/share/:token", shareHandler.Open)
+
 	// Webhook routes (public but with platform-specific validation)
+	r.GET("/webhooks/:platform", analyticsHandler.VerifyWebhook)
 	r.POST("/webhooks/:platform", analyticsHandler.ReceiveWebhook)
 
 	// Protected API routes
 	api := r.Group("/api/v1")
-	api.Use(middleware.Auth(cfg))
+	api.Use(middleware.Auth(cfg), middleware.Locale(cfg))
 	{
+		// Search across timelines, batches and scheduled posts
+		api.GET("/search", searchHandler.Search)
+
+		// AI provider usage and estimated cost
+		api.GET("/usage", usageHandler.GetUsage)
+
+		// Account data endpoints (export / erasure)
+		api.GET("/me/export", userDataHandler.Export)
+		api.DELETE("/me", userDataHandler.Delete)
+
 		// Timeline endpoints
 		api.GET("/timelines", timelineHandler.List)
 		api.POST("/timelines", timelineHandler.Create)
+		api.POST("/timelines/merge", timelineHandler.Merge)
 		api.GET("/timelines/:id", timelineHandler.Get)
+		api.GET("/timelines/:id/audio-mix", timelineHandler.AudioMix)
 		api.PUT("/timelines/:id", timelineHandler.Update)
 		api.DELETE("/timelines/:id", timelineHandler.Delete)
+		api.POST("/timelines/:id/share", shareHandler.ShareTimeline)
+		api.GET("/timelines/:id/notes", timelineHandler.ListNotes)
+		api.POST("/timelines/:id/notes", timelineHandler.AddNote)
+
+		// Share link endpoints
+		api.GET("/shares", shareHandler.List)
+		api.DELETE("/shares/:id", shareHandler.Revoke)
 
 		// Clip endpoints
 		api.POST("/timelines/:id/clips", clipHandler.Create)
+		api.POST("/timelines/:id/clips/bulk", clipHandler.BulkCreate)
 		api.GET("/timelines/:id/clips", clipHandler.List)
 		api.GET("/clips/:clipId", clipHandler.Get)
 		api.PUT("/clips/:clipId", clipHandler.Update)
@@ -159,7 +319,10 @@ func main() {
 		api.GET("/templates/categories", templateHandler.GetCategories)
 		api.GET("/templates/stats", templateHandler.GetStats)
 		api.GET("/templates/:id", templateHandler.Get)
+		api.GET("/templates/:id/preview", templateHandler.Preview)
 		api.POST("/templates/:id/use", templateHandler.Use)
+		api.PUT("/templates/:id/favorite", templateHandler.AddFavorite)
+		api.DELETE("/templates/:id/favorite", templateHandler.RemoveFavorite)
 		api.GET("/timelines/:id/tracks", trackHandler.List)
 		api.PUT("/tracks/:trackId", trackHandler.Update)
 		api.DELETE("/tracks/:trackId", trackHandler.Delete)
@@ -168,17 +331,38 @@ func main() {
 		api.PATCH("/tracks/:trackId/solo", trackHandler.ToggleSolo)
 
 		// AI endpoints
-		api.POST("/ai/script", aiHandler.GenerateScript)
-		api.POST("/ai/script/enhance", aiHandler.EnhanceScript)
+		// Interactive AI calls give up after the request timeout rather
+		// than keep the client waiting
+		aiDeadline := middleware.Deadline(cfg.AIRequestTimeout)
+		api.POST("/ai/script", aiDeadline, aiHandler.GenerateScript)
+		api.POST("/ai/script/enhance", aiDeadline, aiHandler.EnhanceScript)
 		api.GET("/ai/script-styles", aiHandler.GetScriptStyles)
-		api.POST("/ai/scenes", aiHandler.GenerateScenes)
+		api.GET("/ai/script-presets", aiHandler.GetScriptPresets)
+		api.GET("/ai/prompts", aiHandler.ListPromptTemplates)
+		api.PUT("/ai/prompts/:kind", aiHandler.SavePromptTemplate)
+		api.DELETE("/ai/prompts/:kind", aiHandler.DeletePromptTemplate)
+		api.POST("/ai/scenes", aiDeadline, aiHandler.GenerateScenes)
+		api.POST("/ai/scenes/restyle", aiDeadline, aiHandler.RestyleScenes)
+		api.POST("/ai/scenes/reorder", aiHandler.ReorderScenes)
+		api.POST("/ai/scenes/:sceneNumber/regenerate-image", aiDeadline, aiHandler.RegenerateSceneImage)
 		api.GET("/ai/image-sources", aiHandler.GetImageSources)
-		api.POST("/ai/voice", aiHandler.GenerateVoice)
+		api.GET("/ai/image-styles", aiHandler.GetImageStyles)
+		api.POST("/ai/voice", aiDeadline, aiHandler.GenerateVoice)
 		api.GET("/ai/voices", aiHandler.ListVoices)
+		api.POST("/ai/voices/clone", aiHandler.CloneVoice)
+		api.DELETE("/ai/voices/:id", aiHandler.DeleteVoice)
+		api.POST("/ai/transcribe", aiHandler.Transcribe)
+
+		// Media inspection
+		api.POST("/media/probe", mediaHandler.Probe)
+
+		// Music library
+		api.GET("/music", musicHandler.ListTracks)
 
 		// Analytics endpoints
 		api.GET("/analytics/overview", analyticsHandler.GetOverview)
 		api.GET("/analytics/dashboard", analyticsHandler.GetDashboardSummary)
+		api.GET("/analytics/views", analyticsHandler.GetViewsOverTime)
 		api.GET("/analytics/videos", analyticsHandler.GetVideoPerformance)
 		api.GET("/analytics/platforms", analyticsHandler.GetPlatformBreakdown)
 		api.GET("/analytics/engagement", analyticsHandler.GetEngagementMetrics)
@@ -189,28 +373,49 @@ func main() {
 		api.POST("/analytics/track/view", analyticsHandler.TrackView)
 		api.POST("/analytics/track/engagement", analyticsHandler.TrackEngagement)
 
+		// Analytics alert endpoints
+		api.GET("/analytics/alerts", analyticsHandler.ListAlerts)
+		api.POST("/analytics/alerts", analyticsHandler.CreateAlert)
+		api.PUT("/analytics/alerts/:id", analyticsHandler.UpdateAlert)
+		api.DELETE("/analytics/alerts/:id", analyticsHandler.DeleteAlert)
+
 		// Social Media endpoints
 		api.GET("/social/platforms", socialHandler.GetPlatforms)
 		api.GET("/social/accounts", socialHandler.GetAccounts)
 		api.GET("/social/accounts/:id", socialHandler.GetAccount)
 		api.DELETE("/social/accounts/:id", socialHandler.DisconnectAccount)
+		api.PUT("/social/accounts/:id/scheduling-rules", socialHandler.UpdateSchedulingRules)
+		api.POST("/social/accounts/:id/refresh", socialHandler.RefreshAccount)
+		api.GET("/social/accounts/:id/metrics", socialHandler.GetAccountMetrics)
 		api.GET("/social/connect/:platform", socialHandler.GetAuthURL)
 		api.POST("/social/callback/:platform", socialHandler.HandleCallback)
 		api.POST("/social/upload", socialHandler.UploadVideo)
+		api.POST("/social/upload/file", socialHandler.UploadFile)
 		api.POST("/social/crosspost", socialHandler.CrossPost)
+		api.POST("/social/quick-publish", socialHandler.QuickPublish)
 		api.POST("/social/schedule", socialHandler.SchedulePost)
+		api.POST("/social/validate", socialHandler.ValidatePost)
+		api.POST("/social/schedule/variations", socialHandler.ScheduleVariations)
 		api.GET("/social/schedule", socialHandler.GetScheduledPosts)
 		api.DELETE("/social/schedule/:id", socialHandler.CancelScheduledPost)
+		api.POST("/social/schedule/:id/clone", socialHandler.CloneScheduledPost)
+		api.GET("/social/schedule/:id/analytics", socialHandler.GetPostAnalytics)
 		api.POST("/social/publish/:id", socialHandler.PublishNow)
 		api.POST("/social/retry/:id", socialHandler.RetryPost)
 		api.GET("/social/queue", socialHandler.GetPublishingQueue)
+		api.GET("/social/analytics", socialHandler.GetCombinedAnalytics)
 		api.GET("/social/analytics/:accountId", socialHandler.GetAnalytics)
 		api.GET("/social/trends/:accountId", socialHandler.GetTrends)
 		api.GET("/social/stats", socialHandler.GetQueueStats)
 
+		// Publishing campaigns (variations, validation and scheduling in one go)
+		api.POST("/publish/campaign", campaignHandler.CreateCampaign)
+		api.GET("/publish/campaign/:id", campaignHandler.GetCampaign)
+
 		// Content Factory - Ideation endpoints
 		api.POST("/ideation/topics", contentFactoryHandler.GetTrendingTopics)
 		api.POST("/ideation/suggestions", contentFactoryHandler.GetContentSuggestions)
+		api.POST("/ideation/estimate-reach", contentFactoryHandler.EstimateReach)
 		api.POST("/ideation/competitor-analysis", contentFactoryHandler.AnalyzeCompetitor)
 		api.POST("/ideation/calendar", contentFactoryHandler.GenerateContentCalendar)
 
@@ -220,19 +425,40 @@ func main() {
 		api.POST("/batch/:id/start", contentFactoryHandler.StartBatch)
 		api.GET("/batch/:id/status", contentFactoryHandler.GetBatchStatus)
 		api.GET("/batch/:id/results", contentFactoryHandler.GetBatchResults)
+		api.GET("/batch/:id/videos/:videoId", contentFactoryHandler.GetBatchVideo)
 		api.POST("/batch/:id/cancel", contentFactoryHandler.CancelBatch)
 		api.POST("/batch/:id/retry", contentFactoryHandler.RetryFailedVideos)
 		api.GET("/batch/queue/stats", contentFactoryHandler.GetQueueStats)
+		api.GET("/batch/templates", contentFactoryHandler.ListBatchTemplates)
+		api.POST("/batch/templates", contentFactoryHandler.SaveBatchTemplate)
+		api.POST("/batch/templates/:id/apply", contentFactoryHandler.CreateBatchFromTemplate)
+		api.DELETE("/batch/templates/:id", contentFactoryHandler.DeleteBatchTemplate)
+
+		// Content pipeline status, from ideation to publishing
+		api.GET("/content/:id/status", contentStatusHandler.GetStatus)
 
 		// Content Factory - Variations endpoints
 		api.POST("/variations/create", contentFactoryHandler.CreateVariations)
 		api.GET("/variations/platforms", contentFactoryHandler.GetPlatformSpecs)
+		api.POST("/variations/caption-preview", contentFactoryHandler.PreviewCaptions)
+		api.POST("/variations/:jobId/titles", contentFactoryHandler.RegenerateTitles)
+		api.POST("/variations/:jobId/thumbnails", contentFactoryHandler.RegenerateThumbnails)
 
 		// Content Factory - Optimizer endpoints
 		api.POST("/optimizer/analyze", contentFactoryHandler.AnalyzeVideo)
+		api.POST("/optimizer/suggestions/:id/dismiss", contentFactoryHandler.DismissSuggestion)
+		api.POST("/optimizer/suggestions/:id/feedback", contentFactoryHandler.RateSuggestion)
+		api.POST("/optimizer/suggestions/:id/apply", contentFactoryHandler.ApplySuggestion)
+		api.GET("/optimizer/videos/:id/suggestions", contentFactoryHandler.GetSuggestionHistory)
 		api.POST("/optimizer/report", contentFactoryHandler.GeneratePerformanceReport)
+		api.GET("/optimizer/report.pdf", contentFactoryHandler.GeneratePerformanceReportPDF)
+		api.POST("/optimizer/report/share", shareHandler.ShareReport)
 		api.GET("/optimizer/winning-content", contentFactoryHandler.GetWinningContent)
 		api.POST("/optimizer/auto-title", contentFactoryHandler.AutoOptimizeTitle)
+
+		// Admin endpoints
+		admin := api.Group("/admin", middleware.RequireAdmin())
+		admin.GET("/queues", adminHandler.GetQueues)
 	}
 
 	// Start server
@@ -240,34 +466,99 @@ func main() {
 	if port == "" {
 		port = "8080"
 	}
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: r,
+	}
+
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Printf("Shutting down (timeout %s)...", cfg.ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting requests and wait for in-flight ones
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Warning: HTTP server shutdown: %v", err)
 	}
+
+	// Let running scheduler jobs finish before closing their connections
+	if err := sched.Wait(shutdownCtx); err != nil {
+		log.Printf("Warning: scheduler jobs still running at shutdown: %v", err)
+	}
+
+	if err := batchService.Close(); err != nil {
+		log.Printf("Warning: Failed to close batch queue: %v", err)
+	}
+	if err := sched.Close(); err != nil {
+		log.Printf("Warning: Failed to close scheduler: %v", err)
+	}
+	if err := eventBus.Close(); err != nil {
+		log.Printf("Warning: Failed to close event bus: %v", err)
+	}
+	if err := usageService.Close(shutdownCtx); err != nil {
+		log.Printf("Warning: Failed to write remaining usage events: %v", err)
+	}
+
+	log.Println("Server stopped")
 }
 
 func migrateDB(db *gorm.DB) error {
+	// The engagement and open suggestion unique indexes can't be created over
+	// duplicate rows
+	if err := repository.DedupeEngagement(db); err != nil {
+		return err
+	}
+	if err := repository.DedupeOpenSuggestions(db); err != nil {
+		return err
+	}
 	return db.AutoMigrate(
 		&repository.TimelineModel{},
 		&repository.ClipModel{},
 		&repository.TrackModel{},
 		&repository.TemplateModel{},
+		&repository.TemplateFavoriteModel{},
 		// Batch models
 		&repository.BatchModel{},
 		&repository.BatchVideoModel{},
+		&repository.BatchTemplateModel{},
 		// Analytics models
 		&domain.AnalyticsView{},
+		&domain.AnalyticsViewHourly{},
 		&domain.AnalyticsEngagement{},
 		&domain.UserGrowth{},
 		&domain.Revenue{},
 		&domain.VideoPerformance{},
 		&domain.PlatformStats{},
 		&domain.WebhookEvent{},
+		&domain.WinningContentCache{},
+		&domain.OptimizationSuggestionRecord{},
+		&domain.AnalyticsAlert{},
+		&domain.AnalyticsAlertFiring{},
+		&domain.ClonedVoice{},
+		&domain.ShareLink{},
+		&domain.TimelineNote{},
+		&domain.TimelineActivity{},
+		&domain.UsageEvent{},
+		&domain.VariationJob{},
+		&domain.PromptTemplate{},
 		// Social media models
 		&socialdomain.SocialAccount{},
 		&socialdomain.ScheduledPost{},
 		&socialdomain.PlatformPost{},
+		&socialdomain.PostOccurrence{},
 		&socialdomain.AnalyticsData{},
 		&socialdomain.PlatformTrend{},
+		&socialdomain.Campaign{},
+		&socialdomain.VideoFingerprint{},
+		&socialdomain.AccountMetricsSnapshot{},
 	)
 }
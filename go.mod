@@ -5,11 +5,13 @@ go 1.25.0
 require (
 	github.com/fogleman/gg v1.3.0
 	github.com/gin-gonic/gin v1.9.1
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/go-playground/validator/v10 v10.16.0
 	github.com/go-redis/redis/v8 v8.11.5
 	github.com/golang-jwt/jwt/v5 v5.2.0
 	github.com/google/uuid v1.6.0
 	github.com/hibiken/asynq v0.26.0
+	golang.org/x/image v0.36.0
 	golang.org/x/oauth2 v0.35.0
 	google.golang.org/api v0.269.0
 	gorm.io/driver/postgres v1.5.4
@@ -60,7 +62,6 @@ require (
 	go.opentelemetry.io/otel/trace v1.39.0 // indirect
 	golang.org/x/arch v0.3.0 // indirect
 	golang.org/x/crypto v0.48.0 // indirect
-	golang.org/x/image v0.36.0 // indirect
 	golang.org/x/net v0.50.0 // indirect
 	golang.org/x/sys v0.41.0 // indirect
 	golang.org/x/text v0.34.0 // indirect
@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds application configuration
@@ -13,6 +16,7 @@ type Config struct {
 	ClerkSecretKey     string
 	FrontendURL        string
 	RemotionURL        string
+	ShutdownTimeout    time.Duration
 	// AI Service Keys
 	OpenAIAPIKey       string
 	TogetherAPIKey     string
@@ -21,6 +25,55 @@ type Config struct {
 	UnsplashAccessKey  string
 	PexelsAPIKey       string
 	OpenAIBaseURL      string
+	TogetherBaseURL    string
+	StabilityBaseURL   string
+	ElevenLabsBaseURL  string
+	// OpenAI organization and project requests are billed to; unset uses the key's defaults
+	OpenAIOrganization string
+	OpenAIProject      string
+	// How long each kind of AI call may take, by operation (script, image,
+	// tts, ...), and how long interactive AI endpoints may take in all
+	AITimeouts       map[string]time.Duration
+	AIRequestTimeout time.Duration
+	// Whether users may replace the built-in AI system prompts with their
+	// own templates, per request or saved
+	AIPromptOverrides bool
+	// Batch workers
+	BatchWorkerConcurrency int
+	BatchQueueWeights      map[string]int
+	// Pre-publish moderation
+	ModerationEnabled bool
+	ModerationEnforce bool
+	// Users allowed to force-publish flagged content
+	AdminUserIDs []string
+	// Local file storage, served from /files
+	StorageDir     string
+	StorageBaseURL string
+	// Files uploaded for publishing: size limit and how long they are kept
+	// after publishing, so platforms that fetch by URL have time to do so
+	UploadMaxBytes  int64
+	UploadRetention time.Duration
+	// ffprobe binary used to read media metadata, and the largest file it is
+	// run on
+	FFprobePath        string
+	MediaProbeMaxBytes int64
+	// ffmpeg binary used to fingerprint videos, and how far back a video
+	// posted to an account counts as a duplicate; 0 turns the check off
+	FFmpegPath      string
+	DuplicateWindow time.Duration
+	// How long generated artifacts (thumbnails) are kept in storage, by
+	// type; types left out are kept forever
+	ArtifactRetention map[string]time.Duration
+	// Language and region used when neither the user's profile nor the
+	// Accept-Language header gives one
+	DefaultLanguage string
+	DefaultRegion   string
+	// Share links: the key their tokens are signed with and the public URL
+	// they're served under
+	ShareSigningSecret string
+	ShareBaseURL       string
+	// How far ahead of now posts must be scheduled
+	ScheduleMinLead time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -33,6 +86,7 @@ func Load() *Config {
 		ClerkSecretKey:    getEnv("CLERK_SECRET_KEY", ""),
 		FrontendURL:       getEnv("FRONTEND_URL", "http://localhost:3000"),
 		RemotionURL:       getEnv("REMOTION_URL", "http://localhost:3001"),
+		ShutdownTimeout:   getDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
 		// AI Service Keys
 		OpenAIAPIKey:      getEnv("OPENAI_API_KEY", ""),
 		TogetherAPIKey:    getEnv("TOGETHER_API_KEY", ""),
@@ -41,6 +95,46 @@ func Load() *Config {
 		UnsplashAccessKey: getEnv("UNSPLASH_ACCESS_KEY", ""),
 		PexelsAPIKey:      getEnv("PEXELS_API_KEY", ""),
 		OpenAIBaseURL:     getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		TogetherBaseURL:   getEnv("TOGETHER_BASE_URL", "https://api.together.xyz/v1"),
+		StabilityBaseURL:  getEnv("STABILITY_BASE_URL", "https://api.stability.ai"),
+		ElevenLabsBaseURL: getEnv("ELEVENLABS_BASE_URL", "https://api.elevenlabs.io/v1"),
+		// OpenAI billing attribution
+		OpenAIOrganization: getEnv("OPENAI_ORGANIZATION", ""),
+		OpenAIProject:      getEnv("OPENAI_PROJECT", ""),
+		// AI call timeouts
+		AITimeouts:       getDurations("AI_TIMEOUTS"),
+		AIRequestTimeout: getDuration("AI_REQUEST_TIMEOUT", 90*time.Second),
+		// System prompt overrides
+		AIPromptOverrides: getBool("AI_PROMPT_OVERRIDES", false),
+		// Batch workers
+		BatchWorkerConcurrency: getInt("BATCH_WORKER_CONCURRENCY", 3),
+		BatchQueueWeights:      getWeights("BATCH_QUEUE_WEIGHTS"),
+		// Pre-publish moderation
+		ModerationEnabled: getBool("MODERATION_ENABLED", false),
+		ModerationEnforce: getBool("MODERATION_ENFORCE", true),
+		AdminUserIDs:      getList("ADMIN_USER_IDS"),
+		// Local file storage
+		StorageDir:     getEnv("STORAGE_DIR", "./storage"),
+		StorageBaseURL: getEnv("STORAGE_BASE_URL", "http://localhost:"+getEnv("PORT", "8080")+"/files"),
+		// Direct uploads
+		UploadMaxBytes:  int64(getInt("UPLOAD_MAX_MB", 512)) << 20,
+		UploadRetention: getDuration("UPLOAD_RETENTION", 24*time.Hour),
+		// Media probing
+		FFprobePath:        getEnv("FFPROBE_PATH", "ffprobe"),
+		MediaProbeMaxBytes: int64(getInt("MEDIA_PROBE_MAX_MB", 2048)) << 20,
+		// Duplicate video detection
+		FFmpegPath:      getEnv("FFMPEG_PATH", "ffmpeg"),
+		DuplicateWindow: getDuration("DUPLICATE_WINDOW", 30*24*time.Hour),
+		// Generated artifact retention
+		ArtifactRetention: getDurations("ARTIFACT_RETENTION"),
+		// Locale defaults
+		DefaultLanguage: strings.ToLower(getEnv("DEFAULT_LANGUAGE", "en")),
+		DefaultRegion:   strings.ToUpper(getEnv("DEFAULT_REGION", "US")),
+		// Share links
+		ShareSigningSecret: getEnv("SHARE_SIGNING_SECRET", ""),
+		ShareBaseURL:       getEnv("SHARE_BASE_URL", "http://localhost:"+getEnv("PORT", "8080")+"/share"),
+		// Social scheduling
+		ScheduleMinLead: getDuration("SCHEDULE_MIN_LEAD", time.Minute),
 	}
 }
 
@@ -50,3 +144,77 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getList parses a comma-separated list, dropping empty entries
+func getList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getWeights parses a comma-separated list of name=weight pairs, e.g.
+// "interactive=6,bulk=1". Malformed pairs are skipped.
+func getWeights(key string) map[string]int {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight <= 0 {
+			continue
+		}
+		weights[strings.TrimSpace(name)] = weight
+	}
+	return weights
+}
+
+// getDurations parses a comma-separated list of name=duration pairs, e.g.
+// "images=720h,audio=168h". Malformed pairs are skipped.
+func getDurations(key string) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || duration <= 0 {
+			continue
+		}
+		durations[strings.TrimSpace(name)] = duration
+	}
+	return durations
+}
@@ -1,9 +1,13 @@
 package domain
 
 import (
+	"errors"
 	"time"
 )
 
+// ErrVideoPerformanceNotFound is returned when a user has no performance data for a video
+var ErrVideoPerformanceNotFound = errors.New("video performance not found")
+
 // AnalyticsView represents a video view record
 type AnalyticsView struct {
 	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
@@ -22,16 +26,35 @@ func (AnalyticsView) TableName() string {
 	return "analytics_views"
 }
 
+// AnalyticsViewHourly holds the views a video gained on a platform within one
+// hour, for intra-day charts
+type AnalyticsViewHourly struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	VideoID   string    `gorm:"uniqueIndex:idx_views_hourly_video_platform_hour;not null"`
+	UserID    string    `gorm:"index"`
+	Platform  string    `gorm:"uniqueIndex:idx_views_hourly_video_platform_hour;not null"`
+	Hour      time.Time `gorm:"index;uniqueIndex:idx_views_hourly_video_platform_hour;not null"`
+	Views     int64     `gorm:"default:0"` // Views gained within the hour
+	Total     int64     `gorm:"default:0"` // Running total last reported by the platform, if any
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName specifies the table name for AnalyticsViewHourly
+func (AnalyticsViewHourly) TableName() string {
+	return "analytics_views_hourly"
+}
+
 // AnalyticsEngagement represents engagement metrics (likes, comments, shares)
 type AnalyticsEngagement struct {
 	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	VideoID   string    `gorm:"index;not null"`
-	Platform  string    `gorm:"index;not null"`
+	VideoID   string    `gorm:"index;uniqueIndex:idx_engagement_video_platform_date;not null"`
+	Platform  string    `gorm:"index;uniqueIndex:idx_engagement_video_platform_date;not null"`
 	Likes     int64     `gorm:"default:0"`
 	Comments  int64     `gorm:"default:0"`
 	Shares    int64     `gorm:"default:0"`
 	Saves     int64     `gorm:"default:0"`
-	Date      time.Time `gorm:"index;not null"`
+	Date      time.Time `gorm:"index;uniqueIndex:idx_engagement_video_platform_date;not null"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -119,13 +142,16 @@ func (PlatformStats) TableName() string {
 
 // WebhookEvent stores incoming analytics webhooks
 type WebhookEvent struct {
-	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	Platform    string    `gorm:"not null"` // youtube, tiktok, instagram
-	EventType   string    `gorm:"not null"` // view, like, share, etc.
-	VideoID     string    `gorm:"index"`
-	Payload     JSON      `gorm:"type:jsonb"`
-	Processed   bool      `gorm:"default:false"`
+	ID          string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Platform    string `gorm:"not null"` // youtube, tiktok, instagram
+	EventType   string `gorm:"not null"` // view, like, share, etc.
+	VideoID     string `gorm:"index"`
+	Payload     JSON   `gorm:"type:jsonb"`
+	Processed   bool   `gorm:"default:false"`
 	ProcessedAt *time.Time
+	Attempts    int        `gorm:"default:0"` // failed processing attempts
+	LastError   string     // why the last attempt failed
+	FailedAt    *time.Time // set once processing is given up on
 	CreatedAt   time.Time
 }
 
@@ -134,5 +160,93 @@ func (WebhookEvent) TableName() string {
 	return "analytics_webhook_events"
 }
 
+// WinningContentCache stores the last computed winning content analysis for a user
+type WinningContentCache struct {
+	ID         string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID     string    `gorm:"uniqueIndex;not null"`
+	Analysis   []byte    `gorm:"type:jsonb"`
+	ComputedAt time.Time `gorm:"not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TableName specifies the table name for WinningContentCache
+func (WinningContentCache) TableName() string {
+	return "analytics_winning_content_cache"
+}
+
+// OptimizationSuggestionRecord persists an optimizer suggestion so it keeps its
+// ID across analyses and remembers whether the user dismissed, rated or applied
+// it. Key identifies the suggestion within a video, e.g. "title:Add Power Words";
+// once a suggestion is applied, suggesting it again starts a new record, so
+// each key has at most one record that isn't applied.
+type OptimizationSuggestionRecord struct {
+	ID              string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID          string `gorm:"not null;index;uniqueIndex:idx_open_suggestion_key,where:NOT applied"`
+	VideoID         string `gorm:"not null;uniqueIndex:idx_open_suggestion_key"`
+	Key             string `gorm:"not null;uniqueIndex:idx_open_suggestion_key"`
+	Type            string `gorm:"not null"`
+	Suggestion      []byte `gorm:"type:jsonb"`
+	Dismissed       bool   `gorm:"default:false"`
+	DismissedAt     *time.Time
+	Feedback        int  `gorm:"default:0"` // 1 helpful, -1 not helpful, 0 unrated
+	Applied         bool `gorm:"default:false"`
+	AppliedAt       *time.Time
+	LastSuggestedAt *time.Time // last analysis that produced it
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// TableName specifies the table name for OptimizationSuggestionRecord
+func (OptimizationSuggestionRecord) TableName() string {
+	return "analytics_optimization_suggestions"
+}
+
+// AnalyticsAlert is a user's threshold on a video metric; when a video's
+// performance reaches it a webhook signed with Secret is sent
+type AnalyticsAlert struct {
+	ID         string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID     string `gorm:"index;not null"`
+	Name       string
+	Metric     string  `gorm:"not null"` // views, likes, comments, shares or engagement_rate
+	Threshold  float64 `gorm:"not null"`
+	WebhookURL string  `gorm:"not null"`
+	Secret     string  `gorm:"not null"`
+	Enabled    bool    `gorm:"not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TableName specifies the table name for AnalyticsAlert
+func (AnalyticsAlert) TableName() string {
+	return "analytics_alerts"
+}
+
+// AnalyticsAlertFiring records that an alert fired for a video, so each video
+// crossing a threshold is only reported once. Baseline firings mark videos
+// that were already past the threshold when the alert was set up; they are
+// never reported.
+type AnalyticsAlertFiring struct {
+	ID       string  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	AlertID  string  `gorm:"not null;uniqueIndex:idx_alert_firing"`
+	VideoID  string  `gorm:"not null;uniqueIndex:idx_alert_firing"`
+	Value    float64 // the metric's value when it fired
+	Baseline bool    `gorm:"not null;default:false"`
+	FiredAt  time.Time
+}
+
+// TableName specifies the table name for AnalyticsAlertFiring
+func (AnalyticsAlertFiring) TableName() string {
+	return "analytics_alert_firings"
+}
+
+// SuggestionTypeFeedback aggregates a user's reactions to one suggestion type
+type SuggestionTypeFeedback struct {
+	Type      string
+	Total     int64
+	Helpful   int64
+	Unhelpful int64 // rated unhelpful, or dismissed without a rating
+}
+
 // JSON is a custom type for JSON fields
 type JSON map[string]interface{}
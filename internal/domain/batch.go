@@ -1,6 +1,18 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrBatchNotFound is returned when a batch doesn't exist or belongs to another user
+var ErrBatchNotFound = errors.New("batch not found")
+
+// ErrBatchVideoNotFound is returned when a batch has no video with the given ID
+var ErrBatchVideoNotFound = errors.New("batch video not found")
+
+// ErrBatchTemplateNotFound is returned when a batch template doesn't exist or belongs to another user
+var ErrBatchTemplateNotFound = errors.New("batch template not found")
 
 // Batch represents a batch video generation job
 type Batch struct {
@@ -39,20 +51,25 @@ const (
 
 // BatchVideo represents a single video in a batch
 type BatchVideo struct {
-	ID          string            `json:"id"`
-	BatchID     string            `json:"batchId"`
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Status      VideoStatus       `json:"status"`
-	TimelineID  string            `json:"timelineId,omitempty"`
-	Error       string            `json:"error,omitempty"`
-	Progress    float64           `json:"progress"`
-	Config      VideoConfig       `json:"config"`
-	Result      *VideoResult      `json:"result,omitempty"`
-	CreatedAt   time.Time         `json:"createdAt"`
-	UpdatedAt   time.Time         `json:"updatedAt"`
-	StartedAt   *time.Time        `json:"startedAt,omitempty"`
-	CompletedAt *time.Time        `json:"completedAt,omitempty"`
+	ID             string       `json:"id"`
+	BatchID        string       `json:"batchId"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	Status         VideoStatus  `json:"status"`
+	TimelineID     string       `json:"timelineId,omitempty"`
+	TaskID         string       `json:"taskId,omitempty"` // asynq task currently processing the video
+	Error          string       `json:"error,omitempty"`
+	FailedStage    string       `json:"failedStage,omitempty"`    // pipeline stage a failed video stopped at
+	FailedProvider string       `json:"failedProvider,omitempty"` // AI provider behind the failure, if any
+	Retryable      bool         `json:"retryable,omitempty"`      // whether retrying the failure may succeed
+	Progress       float64      `json:"progress"`
+	Config         VideoConfig  `json:"config"`
+	Result         *VideoResult `json:"result,omitempty"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	UpdatedAt      time.Time    `json:"updatedAt"`
+	StartedAt      *time.Time   `json:"startedAt,omitempty"`
+	CompletedAt    *time.Time   `json:"completedAt,omitempty"`
+	Logs           []string     `json:"-"` // tail of the processing log, returned with the video's detail
 }
 
 // VideoStatus represents the status of a single video
@@ -71,16 +88,27 @@ const (
 // BatchConfig contains configuration for the batch
 type BatchConfig struct {
 	TemplateID             string                 `json:"templateId,omitempty"`
+	BatchTemplateID        string                 `json:"batchTemplateId,omitempty"` // saved batch template the batch was created from
 	ScriptStyle            string                 `json:"scriptStyle"`
+	ImageStyle             string                 `json:"imageStyle,omitempty"` // scene image style preset ID
+	Language               string                 `json:"language,omitempty"`   // ISO language code
+	TargetAudience         string                 `json:"targetAudience,omitempty"`
 	Duration               int                    `json:"duration"`
 	VoiceID                string                 `json:"voiceId,omitempty"`
 	BackgroundMusic        bool                   `json:"backgroundMusic"`
+	MusicTrackID           string                 `json:"musicTrackId,omitempty"` // music library track mixed under narration
 	AutoGenerateThumbnails bool                   `json:"autoGenerateThumbnails"`
 	Platforms              []string               `json:"platforms,omitempty"`
+	Platform               string                 `json:"platform,omitempty"` // PlatformSpecs key the output is sized for; defaults to the first of Platforms with a spec
+	Width                  int                    `json:"width,omitempty"`    // output resolution, defaults to the platform's
+	Height                 int                    `json:"height,omitempty"`
+	FPS                    int                    `json:"fps,omitempty"`   // defaults to the platform's recommended rate
+	Codec                  string                 `json:"codec,omitempty"` // H.264, H.265 or VP9; defaults to H.264
 	ParallelProcessing     bool                   `json:"parallelProcessing"`
 	MaxConcurrent          int                    `json:"maxConcurrent"`
 	RetryAttempts          int                    `json:"retryAttempts"`
 	CustomSettings         map[string]interface{} `json:"customSettings,omitempty"`
+	CompletionWebhook      string                 `json:"completionWebhook,omitempty" binding:"omitempty,url"` // POSTed a summary once the batch finishes
 }
 
 // VideoConfig contains configuration for a single video
@@ -90,6 +118,19 @@ type VideoConfig struct {
 	Keywords       []string `json:"keywords,omitempty"`
 	Tone           string   `json:"tone,omitempty"`
 	TargetDuration int      `json:"targetDuration,omitempty"`
+	// Scenes already generated, e.g. as put in order by a scene reorder.
+	// A video given scenes plays them in this order and skips script and
+	// scene generation.
+	Scenes []VideoScene `json:"scenes,omitempty"`
+}
+
+// VideoScene is a generated scene a video is made from. Its fields take the
+// names scene generation returns them under.
+type VideoScene struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url,omitempty"`
+	Duration    int    `json:"duration,omitempty"` // seconds
 }
 
 // VideoResult contains the result of video generation
@@ -107,7 +148,33 @@ type VideoResult struct {
 type BatchRepository interface {
 	Create(batch *Batch) error
 	Get(id string) (*Batch, error)
+	GetByIDAndUser(id, userID string) (*Batch, error)
+	GetByVideoIDAndUser(videoID, userID string) (*Batch, error)
 	Update(batch *Batch) error
+	UpdateVideo(video *BatchVideo) error
+	ReleaseInProgress(batchID string, count int) error
 	List(userID string, limit, offset int) ([]*Batch, error)
+	ListWithCount(userID string, limit, offset int) ([]*Batch, int64, error)
+	ListByStatus(statuses ...BatchStatus) ([]*Batch, error)
 	Delete(id string) error
 }
+
+// BatchTemplate is a saved batch configuration that can be reused for recurring batches
+type BatchTemplate struct {
+	ID            string      `json:"id"`
+	UserID        string      `json:"userId"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description,omitempty"`
+	Config        BatchConfig `json:"config"`
+	VideoDefaults VideoConfig `json:"videoDefaults"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
+}
+
+// BatchTemplateRepository defines the interface for batch template storage
+type BatchTemplateRepository interface {
+	Create(template *BatchTemplate) error
+	GetByIDAndUser(id, userID string) (*BatchTemplate, error)
+	ListByUser(userID string) ([]*BatchTemplate, error)
+	Delete(id, userID string) error
+}
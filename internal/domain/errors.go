@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is a stable, machine-readable error identifier clients can switch on
+type ErrorCode string
+
+const (
+	CodeValidation            ErrorCode = "VALIDATION_ERROR"
+	CodeNotFound              ErrorCode = "NOT_FOUND"
+	CodeForbidden             ErrorCode = "FORBIDDEN"
+	CodeConflict              ErrorCode = "CONFLICT"
+	CodeRateLimited           ErrorCode = "RATE_LIMITED"
+	CodePlatformExpired       ErrorCode = "PLATFORM_EXPIRED"
+	CodePlatformError         ErrorCode = "PLATFORM_ERROR"
+	CodePlatformNotConfigured ErrorCode = "PLATFORM_NOT_CONFIGURED"
+	CodeUnsupportedMediaType  ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	CodeContentFlagged        ErrorCode = "CONTENT_FLAGGED"
+	CodeDuplicateContent      ErrorCode = "DUPLICATE_CONTENT"
+	CodeInternal              ErrorCode = "INTERNAL_ERROR"
+)
+
+// AppError is an error carrying the code and HTTP status it should be reported with
+type AppError struct {
+	Code       ErrorCode
+	Message    string
+	HTTPStatus int
+	Err        error
+}
+
+// NewAppError creates an application error
+func NewAppError(code ErrorCode, httpStatus int, message string) *AppError {
+	return &AppError{Code: code, Message: message, HTTPStatus: httpStatus}
+}
+
+// NewValidationError creates a VALIDATION_ERROR
+func NewValidationError(message string) *AppError {
+	return NewAppError(CodeValidation, http.StatusBadRequest, message)
+}
+
+// WrapError attaches a code and status to an existing error, keeping its message
+func WrapError(err error, code ErrorCode, httpStatus int) *AppError {
+	return &AppError{Code: code, Message: err.Error(), HTTPStatus: httpStatus, Err: err}
+}
+
+func (e *AppError) Error() string {
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// AsAppError returns the first AppError in err's chain, if any
+func AsAppError(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Locale is the language and region a request is made for
+type Locale struct {
+	Language string `json:"language"`         // ISO 639-1 code, e.g. "en"
+	Region   string `json:"region,omitempty"` // ISO 3166-1 alpha-2 code, e.g. "GB"
+}
+
+// localeKey is the context key of the request locale
+type localeKey struct{}
+
+// ParseLocale parses a language tag such as "en-GB", "pt_BR" or "fr". Script
+// subtags ("zh-Hant-TW") are skipped; anything unparseable gives a zero Locale.
+func ParseLocale(tag string) Locale {
+	parts := strings.FieldsFunc(strings.TrimSpace(tag), func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 || !isLetters(parts[0], 2, 3) {
+		return Locale{}
+	}
+	locale := Locale{Language: strings.ToLower(parts[0])}
+	for _, part := range parts[1:] {
+		if isLetters(part, 2, 2) {
+			locale.Region = strings.ToUpper(part)
+			break
+		}
+	}
+	return locale
+}
+
+// ParseAcceptLanguage returns the most preferred locale of an Accept-Language
+// header, skipping the "*" wildcard
+func ParseAcceptLanguage(header string) Locale {
+	var best Locale
+	bestQ := 0.0
+	for _, entry := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		locale := ParseLocale(tag)
+		if locale.Language != "" && q > bestQ {
+			best, bestQ = locale, q
+		}
+	}
+	return best
+}
+
+// WithLocale returns a copy of ctx carrying the request locale
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the request locale, if one was set
+func LocaleFromContext(ctx context.Context) (Locale, bool) {
+	locale, ok := ctx.Value(localeKey{}).(Locale)
+	return locale, ok
+}
+
+func isLetters(s string, minLen, maxLen int) bool {
+	if len(s) < minLen || len(s) > maxLen {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -17,18 +17,37 @@ type Timeline struct {
 	Tracks      []Track   `json:"tracks,omitempty"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
+
+	// Recent activity, newest first; only filled in when a timeline is fetched on its own
+	Activity []TimelineActivity `json:"activity,omitempty"`
 }
 
 // Track represents a track in a timeline
 type Track struct {
-	ID         string  `json:"id"`
-	TimelineID string  `json:"timelineId"`
-	Name       string  `json:"name"`
-	Type       string  `json:"type"` // video, audio, text, effect
-	Order      int     `json:"order"`
-	Muted      bool    `json:"muted"`
-	Solo       bool    `json:"solo"`
-	Clips      []Clip  `json:"clips,omitempty"`
+	ID         string   `json:"id"`
+	TimelineID string   `json:"timelineId"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"` // video, audio, text, effect
+	Order      int      `json:"order"`
+	Muted      bool     `json:"muted"`
+	Solo       bool     `json:"solo"`
+	Role       string   `json:"role,omitempty"` // audio tracks only: music, narration, sfx
+	Ducking    *Ducking `json:"ducking,omitempty"`
+	Clips      []Clip   `json:"clips,omitempty"`
+}
+
+// Audio track roles
+const (
+	TrackRoleMusic     = "music"
+	TrackRoleNarration = "narration"
+	TrackRoleSFX       = "sfx"
+)
+
+// Ducking lowers a track's volume while narration plays over it
+type Ducking struct {
+	AmountDB  float64 `json:"amountDb"`  // gain reduction under narration
+	AttackMs  int     `json:"attackMs"`  // fade down before narration starts
+	ReleaseMs int     `json:"releaseMs"` // fade back up after narration ends
 }
 
 // Clip represents a media clip on a track
@@ -44,15 +63,39 @@ type Clip struct {
 	Duration     float64 `json:"duration"`
 	TrimStart    float64 `json:"trimStart"`
 	TrimEnd      float64 `json:"trimEnd"`
+	SourceStart  float64 `json:"sourceStart"`            // where in the source media the clip starts, in seconds
+	SourceEnd    float64 `json:"sourceEnd,omitempty"`    // where it stops; 0 plays on for the clip's length
+	SourceLength float64 `json:"sourceLength,omitempty"` // length of the source media, when known
 	PositionX    float64 `json:"positionX"`
 	PositionY    float64 `json:"positionY"`
 	Scale        float64 `json:"scale"`
 	Rotation     float64 `json:"rotation"`
 	Opacity      float64 `json:"opacity"`
+	Volume       float64 `json:"volume"`  // gain on the clip's audio, 1 leaves it unchanged
+	FadeIn       float64 `json:"fadeIn"`  // seconds
+	FadeOut      float64 `json:"fadeOut"` // seconds
 	TextContent  string  `json:"textContent,omitempty"`
 	TextStyle    *Style  `json:"textStyle,omitempty"`
 }
 
+// SourceIn returns where in the source media the clip starts. Clips made
+// before source trims fall back to their trim start.
+func (c *Clip) SourceIn() float64 {
+	if c.SourceStart > 0 {
+		return c.SourceStart
+	}
+	return c.TrimStart
+}
+
+// SourceOut returns where in the source media the clip stops, defaulting to
+// as much of the source as fills the clip's place on the timeline
+func (c *Clip) SourceOut() float64 {
+	if c.SourceEnd > 0 {
+		return c.SourceEnd
+	}
+	return c.SourceIn() + c.EndTime - c.StartTime
+}
+
 // Style represents styling for text clips
 type Style struct {
 	FontSize   int     `json:"fontSize"`
@@ -66,6 +109,8 @@ type Style struct {
 
 // UserContext holds authenticated user info
 type UserContext struct {
-	ID    string
-	Email string
+	ID      string
+	Email   string
+	IsAdmin bool
+	Locale  string // profile language tag, e.g. "en-GB"; empty when not set
 }
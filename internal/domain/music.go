@@ -0,0 +1,14 @@
+package domain
+
+// MusicTrack is a background music track from the music library
+type MusicTrack struct {
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Artist   string  `json:"artist"`
+	Genre    string  `json:"genre"`
+	Mood     string  `json:"mood"` // upbeat, calm, dramatic, inspiring, playful
+	BPM      int     `json:"bpm"`
+	Duration float64 `json:"duration"` // seconds
+	URL      string  `json:"url"`
+	License  string  `json:"license"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// PromptTemplate is a user's own system prompt for one kind of AI
+// generation, used instead of the built-in one
+type PromptTemplate struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID    string    `gorm:"uniqueIndex:idx_prompt_templates_user_kind;not null" json:"-"`
+	Kind      string    `gorm:"uniqueIndex:idx_prompt_templates_user_kind;not null" json:"kind"`
+	Template  string    `gorm:"type:text;not null" json:"template"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName specifies the table name for PromptTemplate
+func (PromptTemplate) TableName() string {
+	return "prompt_templates"
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Search result types
+const (
+	SearchTypeTimeline      = "timeline"
+	SearchTypeBatch         = "batch"
+	SearchTypeScheduledPost = "scheduled_post"
+)
+
+// SearchResult is one item of a user's library matching a search
+type SearchResult struct {
+	Type        string    `json:"type"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Relevance   float64   `json:"relevance"` // 1 for an exact title match down to 0.3 for a description match
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Shared resource types
+const (
+	ShareTimeline = "timeline"
+	ShareReport   = "report"
+)
+
+// Share scopes. A read share shows the resource's outline; a preview share
+// also includes the media URLs needed to play it back, and a timeline's notes.
+const (
+	ShareScopeRead    = "read"
+	ShareScopePreview = "preview"
+)
+
+// ShareLink records a link to one of a user's resources that people without
+// an account can open until it expires or is revoked. The link's token is
+// signed, so only its ID is stored.
+type ShareLink struct {
+	ID           string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID       string     `json:"userId" gorm:"index;not null"`
+	ResourceType string     `json:"resourceType" gorm:"not null"`
+	ResourceID   string     `json:"resourceId,omitempty"`
+	ReportDays   int        `json:"reportDays,omitempty"` // period of a shared report
+	Report       []byte     `json:"-" gorm:"type:jsonb"`  // shared report, as generated when the link was created
+	Scope        string     `json:"scope" gorm:"not null"`
+	ExpiresAt    time.Time  `json:"expiresAt"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// TableName specifies the table name for ShareLink
+func (ShareLink) TableName() string {
+	return "share_links"
+}
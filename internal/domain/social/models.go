@@ -40,18 +40,35 @@ const (
 
 // SocialAccount represents a connected social media account
 type SocialAccount struct {
-	ID           string         `json:"id" gorm:"primaryKey"`
-	UserID       string         `json:"userId" gorm:"index"`
-	Platform     SocialPlatform `json:"platform"`
-	AccountID    string         `json:"accountId"`
-	AccountName  string         `json:"accountName"`
-	AccessToken  string         `json:"-" gorm:"column:access_token"`
-	RefreshToken string         `json:"-" gorm:"column:refresh_token"`
-	TokenExpiry  *time.Time     `json:"tokenExpiry"`
-	Status       PlatformStatus `json:"status"`
-	Metadata     JSON           `json:"metadata" gorm:"type:jsonb"`
-	CreatedAt    time.Time      `json:"createdAt"`
-	UpdatedAt    time.Time      `json:"updatedAt"`
+	ID              string           `json:"id" gorm:"primaryKey"`
+	UserID          string           `json:"userId" gorm:"index"`
+	Platform        SocialPlatform   `json:"platform"`
+	AccountID       string           `json:"accountId"`
+	AccountName     string           `json:"accountName"`
+	AccessToken     string           `json:"-" gorm:"column:access_token"`
+	RefreshToken    string           `json:"-" gorm:"column:refresh_token"`
+	TokenExpiry     *time.Time       `json:"tokenExpiry"`
+	Status          PlatformStatus   `json:"status"`
+	Metadata        JSON             `json:"metadata" gorm:"type:jsonb"`
+	SchedulingRules *SchedulingRules `json:"schedulingRules,omitempty" gorm:"serializer:json;type:jsonb"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
+}
+
+// SchedulingRules limit when an account may publish. Hours and days are in
+// the rules' timezone; nil fields don't restrict anything.
+type SchedulingRules struct {
+	Timezone     string      `json:"timezone,omitempty"` // IANA name, UTC when empty
+	AllowedHours *HourWindow `json:"allowedHours,omitempty"`
+	BlackoutDays []int       `json:"blackoutDays,omitempty"` // 0 = Sunday
+	Reject       bool        `json:"reject,omitempty"`       // reject out-of-window times instead of shifting them
+}
+
+// HourWindow is the span of hours posting is allowed, from Start up to but
+// not including End. A window with End before Start wraps past midnight.
+type HourWindow struct {
+	Start int `json:"start"` // 0-23
+	End   int `json:"end"`   // 1-24
 }
 
 // ScheduledPost represents a post scheduled for publishing
@@ -65,7 +82,7 @@ type ScheduledPost struct {
 	ScheduledAt time.Time      `json:"scheduledAt"`
 	Timezone    string         `json:"timezone"`
 	Status      PostStatus     `json:"status"`
-	Recurring   *RecurringRule `json:"recurring,omitempty" gorm:"-"`
+	Recurring   *RecurringRule `json:"recurring,omitempty" gorm:"type:jsonb;serializer:json"`
 	Metadata    JSON           `json:"metadata" gorm:"type:jsonb"`
 	CreatedAt   time.Time      `json:"createdAt"`
 	UpdatedAt   time.Time      `json:"updatedAt"`
@@ -93,6 +110,17 @@ type PlatformPost struct {
 	PublishedAt     *time.Time     `json:"publishedAt"`
 }
 
+// PostFilter narrows a listing of a user's scheduled posts. Zero values
+// don't filter; From and To bound ScheduledAt.
+type PostFilter struct {
+	Status   PostStatus
+	Platform SocialPlatform
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+}
+
 // RecurringRule defines how a post should repeat
 type RecurringRule struct {
 	Frequency  string   `json:"frequency"` // daily, weekly, monthly
@@ -102,6 +130,17 @@ type RecurringRule struct {
 	EndAfter   *int     `json:"endAfter,omitempty"`
 }
 
+// PostOccurrence records a run of a recurring post once it is enqueued. The
+// post ID and scheduled instant form its key, so an occurrence is enqueued
+// at most once however often the publisher restarts.
+type PostOccurrence struct {
+	PostID      string     `json:"postId" gorm:"primaryKey"`
+	ScheduledAt time.Time  `json:"scheduledAt" gorm:"primaryKey"`
+	Status      PostStatus `json:"status"` // scheduled until every platform has published or failed
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
 // AnalyticsData represents platform analytics for a post
 type AnalyticsData struct {
 	ID           string         `json:"id" gorm:"primaryKey"`
@@ -118,6 +157,43 @@ type AnalyticsData struct {
 	RecordedAt   time.Time      `json:"recordedAt"`
 }
 
+// PublishedPostAnalytics pairs a published platform post with its latest synced analytics
+type PublishedPostAnalytics struct {
+	PlatformPostID string         `json:"platformPostId"`
+	Platform       SocialPlatform `json:"platform"`
+	AccountID      string         `json:"accountId"`
+	PostURL        string         `json:"postUrl,omitempty"`
+	PublishedAt    *time.Time     `json:"publishedAt"`
+	Analytics      *AnalyticsData `json:"analytics"`
+}
+
+// AccountAnalytics totals an account's analytics over its recent published
+// posts. Error is set when the account's platform couldn't be reached; the
+// totals then cover only the posts that were fetched.
+type AccountAnalytics struct {
+	AccountID   string         `json:"accountId"`
+	Platform    SocialPlatform `json:"platform"`
+	AccountName string         `json:"accountName"`
+	Posts       int            `json:"posts"`
+	Views       int64          `json:"views"`
+	Likes       int64          `json:"likes"`
+	Comments    int64          `json:"comments"`
+	Shares      int64          `json:"shares"`
+	WatchTime   int64          `json:"watchTime"` // in seconds
+	Error       string         `json:"error,omitempty"`
+}
+
+// CombinedAnalytics is a user's analytics across all their connected accounts
+type CombinedAnalytics struct {
+	Accounts  []*AccountAnalytics `json:"accounts"`
+	Views     int64               `json:"views"`
+	Likes     int64               `json:"likes"`
+	Comments  int64               `json:"comments"`
+	Shares    int64               `json:"shares"`
+	WatchTime int64               `json:"watchTime"` // in seconds
+	Failed    int                 `json:"failed"`    // accounts with an error
+}
+
 // PlatformTrend represents trending topics/sounds for a platform
 type PlatformTrend struct {
 	ID          string         `json:"id" gorm:"primaryKey"`
@@ -131,22 +207,276 @@ type PlatformTrend struct {
 	FetchedAt   time.Time      `json:"fetchedAt"`
 }
 
-// UploadRequest represents a video upload request
+// MediaType is the kind of media attached to a post
+type MediaType string
+
+const (
+	MediaTypeVideo    MediaType = "video"
+	MediaTypeImage    MediaType = "image"
+	MediaTypeDocument MediaType = "document"
+)
+
+// UploadRequest represents a media upload request. VideoPath holds the file
+// path or URL for any media type; an empty MediaType means video.
 type UploadRequest struct {
-	VideoPath   string            `json:"videoPath"`
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Tags        []string          `json:"tags"`
-	Privacy     string            `json:"privacy"` // public, unlisted, private
-	Metadata    map[string]string `json:"metadata"`
+	MediaType    MediaType         `json:"mediaType,omitempty"`
+	VideoPath    string            `json:"videoPath"`
+	Title        string            `json:"title"`
+	Description  string            `json:"description"`
+	Tags         []string          `json:"tags"`
+	Privacy      string            `json:"privacy"` // public, unlisted, private
+	CategoryID   string            `json:"categoryId,omitempty"` // YouTube video category; see YouTubeCategories
+	FirstComment string            `json:"firstComment,omitempty"`
+	ThumbnailURL string            `json:"thumbnailUrl,omitempty"` // cover image file path or URL
+	CoverFrameMs *int64            `json:"coverFrameMs,omitempty"` // cover frame offset into the video
+	Metadata     map[string]string `json:"metadata"`
+	// AltText describes the media for screen readers on platforms that take
+	// it. When empty it is built from SceneAltText, the alt text scene
+	// generation produced for the video, or generated from the thumbnail.
+	AltText      string   `json:"altText,omitempty"`
+	SceneAltText []string `json:"sceneAltText,omitempty"`
+	// ModerationOverride is the ID of the admin who chose to publish despite
+	// a moderation flag; empty means flagged content is blocked
+	ModerationOverride string `json:"moderationOverride,omitempty"`
+	// AllowDuplicate publishes the video even when a near-duplicate of it
+	// was recently posted to the account
+	AllowDuplicate bool `json:"allowDuplicate,omitempty"`
+	// ScheduledPostID is set when publishing a scheduled post, whose video
+	// was checked for duplicates and recorded when it was scheduled
+	ScheduledPostID string `json:"-"`
+}
+
+// PostOverride replaces a cross-post's shared copy for one account, or for
+// every account on a platform; empty fields keep the shared values
+type PostOverride struct {
+	AccountID   string         `json:"accountId,omitempty"`
+	Platform    SocialPlatform `json:"platform,omitempty"`
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Privacy     string         `json:"privacy,omitempty"`
+}
+
+// AccountSelector picks accounts by rule instead of by ID: every connected
+// account, or every one on the listed platforms
+type AccountSelector struct {
+	All       bool             `json:"all,omitempty"`
+	Platforms []SocialPlatform `json:"platforms,omitempty"`
+}
+
+// SkippedAccount is an account a selector matched but left out because it
+// can't be posted to
+type SkippedAccount struct {
+	AccountID   string         `json:"accountId"`
+	AccountName string         `json:"accountName"`
+	Platform    SocialPlatform `json:"platform"`
+	Reason      string         `json:"reason"`
+}
+
+// PostCopyRequest asks for a post's copy to be written for a video. The
+// limits are the strictest of the target platforms; zero means no limit.
+type PostCopyRequest struct {
+	VideoURL       string
+	Topic          string
+	Platforms      []SocialPlatform
+	MaxTitle       int
+	MaxDescription int
+	MaxTags        int
+}
+
+// PostCopy is a post's title, description and hashtags
+type PostCopy struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Generated   bool     `json:"generated"` // written by the AI rather than given
+}
+
+// First comment outcomes
+const (
+	CommentStatusPosted      = "posted"
+	CommentStatusFailed      = "failed"
+	CommentStatusUnsupported = "unsupported"
+)
+
+// CommentResult reports how posting the first comment went
+type CommentResult struct {
+	Status    string `json:"status"`
+	CommentID string `json:"commentId,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Thumbnail outcomes
+const (
+	ThumbnailStatusSet         = "set"
+	ThumbnailStatusFailed      = "failed"
+	ThumbnailStatusUnsupported = "unsupported"
+)
+
+// ThumbnailResult reports how setting the video's cover went
+type ThumbnailResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Alt text outcomes
+const (
+	AltTextStatusApplied     = "applied"
+	AltTextStatusUnsupported = "unsupported"
+)
+
+// AltTextResult reports whether the media's alt text reached the platform
+type AltTextResult struct {
+	Status string `json:"status"`
+	Text   string `json:"text,omitempty"`
+}
+
+// ModerationResult reports what the pre-publish moderation check found
+type ModerationResult struct {
+	Flagged    bool     `json:"flagged"`
+	Categories []string `json:"categories,omitempty"`
+	Overridden bool     `json:"overridden,omitempty"` // published anyway by an admin
 }
 
 // UploadResponse represents the result of an upload
 type UploadResponse struct {
-	PlatformPostID string `json:"platformPostId"`
-	PostURL        string `json:"postUrl"`
-	Status         string `json:"status"`
+	PlatformPostID string            `json:"platformPostId"`
+	PostURL        string            `json:"postUrl"`
+	Status         string            `json:"status"`
+	FirstComment   *CommentResult    `json:"firstComment,omitempty"`
+	Thumbnail      *ThumbnailResult  `json:"thumbnail,omitempty"`
+	Category       *CategoryResult   `json:"category,omitempty"`
+	AltText        *AltTextResult    `json:"altText,omitempty"`
+	Moderation     *ModerationResult `json:"moderation,omitempty"`
+}
+
+// CategoryResult is the video category an upload was filed under
+type CategoryResult struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// YouTubeCategories are the video categories YouTube accepts uploads in, by ID
+var YouTubeCategories = map[string]string{
+	"1":  "Film & Animation",
+	"2":  "Autos & Vehicles",
+	"10": "Music",
+	"15": "Pets & Animals",
+	"17": "Sports",
+	"19": "Travel & Events",
+	"20": "Gaming",
+	"22": "People & Blogs",
+	"23": "Comedy",
+	"24": "Entertainment",
+	"25": "News & Politics",
+	"26": "Howto & Style",
+	"27": "Education",
+	"28": "Science & Technology",
+}
+
+// CampaignStatus represents the progress of a publishing campaign
+type CampaignStatus string
+
+const (
+	CampaignStatusPending    CampaignStatus = "pending"    // queued, nothing generated yet
+	CampaignStatusGenerating CampaignStatus = "generating" // building the platform variations
+	CampaignStatusScheduled  CampaignStatus = "scheduled"  // posts scheduled, waiting to publish
+	CampaignStatusCompleted  CampaignStatus = "completed"  // every target finished, at least one published
+	CampaignStatusFailed     CampaignStatus = "failed"     // every target failed
+)
+
+// Campaign publishes one source video to several platforms: it generates a
+// variation per platform, validates it and schedules a post for each target
+type Campaign struct {
+	ID             string           `json:"id" gorm:"primaryKey"`
+	UserID         string           `json:"userId" gorm:"index"`
+	SourceVideoID  string           `json:"sourceVideoId"`
+	SourceVideoURL string           `json:"sourceVideoUrl"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	Tags           []string         `json:"tags,omitempty" gorm:"serializer:json"`
+	Privacy        string           `json:"privacy,omitempty"`
+	ScheduledAt    *time.Time       `json:"scheduledAt,omitempty"` // nil publishes as soon as the posts are ready
+	Timezone       string           `json:"timezone,omitempty"`
+	Targets        []CampaignTarget `json:"targets" gorm:"serializer:json;type:jsonb"`
+	Status         CampaignStatus   `json:"status"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	UpdatedAt      time.Time        `json:"updatedAt"`
 }
 
+// CampaignTarget is one platform and account a campaign publishes to. Status
+// follows the target through generation ("pending", "generated") and then
+// mirrors its post's status.
+type CampaignTarget struct {
+	Platform    string     `json:"platform"` // variation platform, e.g. youtube or instagram_reels
+	AccountID   string     `json:"accountId"`
+	Status      PostStatus `json:"status"`
+	VariationID string     `json:"variationId,omitempty"`
+	VideoURL    string     `json:"videoUrl,omitempty"`
+	PostID      string     `json:"postId,omitempty"`
+	PostURL     string     `json:"postUrl,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// Campaign target statuses before a post exists
+const (
+	CampaignTargetPending   PostStatus = "pending"
+	CampaignTargetGenerated PostStatus = "generated"
+)
+
 // JSON is a custom type for JSONB fields
 type JSON map[string]interface{}
+
+// VideoFingerprint records the perceptual fingerprint of a video posted, or
+// scheduled to be posted, to an account, so the same video going out to it
+// again can be caught
+type VideoFingerprint struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	UserID      string    `json:"userId" gorm:"index"`
+	AccountID   string    `json:"accountId" gorm:"index:idx_fingerprint_account"`
+	Fingerprint string    `json:"fingerprint"`
+	VideoPath   string    `json:"videoPath"`
+	PostID      string    `json:"postId,omitempty"`  // the scheduled post it went out with, if any
+	PostURL     string    `json:"postUrl,omitempty"` // set for direct uploads; scheduled posts have it on their platform post
+	PostedAt    time.Time `json:"postedAt" gorm:"index:idx_fingerprint_account"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// DuplicatePost is a recent post to an account that a video nearly duplicates
+type DuplicatePost struct {
+	AccountID  string    `json:"accountId"`
+	PostID     string    `json:"postId,omitempty"`
+	PostURL    string    `json:"postUrl,omitempty"` // empty until a scheduled post is published
+	PostedAt   time.Time `json:"postedAt"`
+	Similarity float64   `json:"similarity"` // 0 to 1
+}
+
+// AccountMetricsSnapshot is an account's follower, view and video counts as
+// its platform reported them at one time, kept to chart the account's growth
+type AccountMetricsSnapshot struct {
+	ID         string         `json:"-" gorm:"primaryKey"`
+	UserID     string         `json:"-" gorm:"index"`
+	AccountID  string         `json:"-" gorm:"index:idx_account_metrics_account"`
+	Platform   SocialPlatform `json:"-"`
+	Followers  int64          `json:"followers"`
+	TotalViews int64          `json:"totalViews"`
+	VideoCount int64          `json:"videoCount"`
+	RecordedAt time.Time      `json:"recordedAt" gorm:"index:idx_account_metrics_account"`
+}
+
+// AccountMetrics is an account's current follower, view and video counts and
+// their history. When the platform can't be reached the last known counts
+// are returned, marked stale.
+type AccountMetrics struct {
+	AccountID      string                    `json:"accountId"`
+	Platform       SocialPlatform            `json:"platform"`
+	AccountName    string                    `json:"accountName"`
+	Followers      int64                     `json:"followers"`
+	TotalViews     int64                     `json:"totalViews"`
+	VideoCount     int64                     `json:"videoCount"`
+	FetchedAt      time.Time                 `json:"fetchedAt"`
+	Stale          bool                      `json:"stale,omitempty"`
+	Error          string                    `json:"error,omitempty"` // why the platform couldn't be reached, when stale
+	FollowerGrowth int64                     `json:"followerGrowth"`  // change in followers over the history
+	History        []*AccountMetricsSnapshot `json:"history"`         // oldest first
+}
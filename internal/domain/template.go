@@ -2,9 +2,13 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 )
 
+// ErrTemplateNotFound is returned when a template doesn't exist
+var ErrTemplateNotFound = errors.New("template not found")
+
 // TemplateCategory represents template categories
 type TemplateCategory string
 
@@ -42,6 +46,11 @@ type Template struct {
 	Tags         []string        `json:"tags,omitempty"`
 	CreatedAt    time.Time       `json:"createdAt"`
 	UpdatedAt    time.Time       `json:"updatedAt"`
+
+	// UsageCount is how many timelines have been made from the template
+	UsageCount int `json:"usageCount"`
+	// Favorite is whether the user listing templates pinned this one
+	Favorite bool `json:"favorite"`
 }
 
 // TemplateScene represents a scene within a template
@@ -118,8 +127,20 @@ type TemplateFilter struct {
 	Search   string
 	Limit    int
 	Offset   int
+
+	// Sort orders the templates: TemplateSortPopular by how often they're
+	// used, otherwise by their curated popularity
+	Sort string
+	// UserID marks the user's favorites; with FavoritesOnly, only they are listed
+	UserID        string
+	FavoritesOnly bool
 }
 
+// Template sort orders
+const (
+	TemplateSortPopular = "popular"
+)
+
 // ScenesJSON is a helper type for JSON serialization
 type ScenesJSON []TemplateScene
 
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// TimelineNote is a comment left on a timeline, optionally about one of its
+// clips
+type TimelineNote struct {
+	ID         string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	TimelineID string    `json:"timelineId" gorm:"index;not null"`
+	UserID     string    `json:"-" gorm:"index;not null"` // owner of the timeline
+	AuthorID   string    `json:"authorId" gorm:"not null"`
+	Text       string    `json:"text" gorm:"not null"`
+	ClipID     *string   `json:"clipId,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName specifies the table name for TimelineNote
+func (TimelineNote) TableName() string {
+	return "timeline_notes"
+}
+
+// Timeline activity actions
+const (
+	TimelineCreated   = "created"
+	TimelineUpdated   = "updated"
+	TimelineDeleted   = "deleted"
+	TimelineRendered  = "rendered"
+	TimelineNoteAdded = "note_added"
+)
+
+// TimelineActivity is an entry in a timeline's activity log. Entries outlive
+// the timeline, so its deletion stays on record until the owner's data is
+// erased.
+type TimelineActivity struct {
+	ID         string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	TimelineID string    `json:"timelineId" gorm:"index;not null"`
+	UserID     string    `json:"-" gorm:"index;not null"` // owner of the timeline
+	ActorID    string    `json:"actorId"`
+	Action     string    `json:"action" gorm:"not null"`
+	Detail     string    `json:"detail,omitempty"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"index"`
+}
+
+// TableName specifies the table name for TimelineActivity
+func (TimelineActivity) TableName() string {
+	return "timeline_activity"
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UsageEvent records one billable call to an AI provider. Each event holds
+// the units the provider charges for (tokens for chat models, characters
+// for speech, images for image models) and an estimate of its cost in USD.
+type UsageEvent struct {
+	ID               string    `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID           string    `json:"-" gorm:"index"` // empty for calls made outside a request
+	Service          string    `json:"service" gorm:"not null"`
+	Provider         string    `json:"provider" gorm:"index;not null"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"promptTokens"`
+	CompletionTokens int       `json:"completionTokens"`
+	Characters       int       `json:"characters"`
+	Images           int       `json:"images"`
+	EstimatedCost    float64   `json:"estimatedCost"`
+	CreatedAt        time.Time `json:"createdAt" gorm:"index"`
+}
+
+// TableName specifies the table name for UsageEvent
+func (UsageEvent) TableName() string {
+	return "usage_events"
+}
+
+// UsageTotal sums a user's usage of one provider over one period
+type UsageTotal struct {
+	Period           time.Time `json:"period"`
+	Provider         string    `json:"provider"`
+	Calls            int64     `json:"calls"`
+	PromptTokens     int64     `json:"promptTokens"`
+	CompletionTokens int64     `json:"completionTokens"`
+	Characters       int64     `json:"characters"`
+	Images           int64     `json:"images"`
+	EstimatedCost    float64   `json:"estimatedCost"`
+}
+
+type userIDKey struct{}
+
+// WithUserID returns a copy of ctx carrying the ID of the user work is
+// being done for, so provider usage can be attributed to them
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the ID of the user work is being done for, if
+// one was set
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok && userID != ""
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// VariationJob stores a variations run, the request and the result it
+// produced, so parts of the result can be regenerated later without redoing
+// the rest
+type VariationJob struct {
+	ID        string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    string `gorm:"index;not null"`
+	SourceID  string `gorm:"not null"`
+	Request   []byte `gorm:"type:jsonb"`
+	Result    []byte `gorm:"type:jsonb"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName specifies the table name for VariationJob
+func (VariationJob) TableName() string {
+	return "variation_jobs"
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// ClonedVoice records which user cloned a provider voice, so clones are only
+// listed to and deleted by their owner
+type ClonedVoice struct {
+	ID        string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    string `gorm:"index;not null"`
+	VoiceID   string `gorm:"uniqueIndex;not null"` // provider voice ID
+	Provider  string `gorm:"not null"`
+	Name      string
+	CreatedAt time.Time
+}
+
+// TableName specifies the table name for ClonedVoice
+func (ClonedVoice) TableName() string {
+	return "cloned_voices"
+}
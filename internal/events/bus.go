@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Event types
+const (
+	// TypeTimelineReady is published when a batch video's timeline has all
+	// its clips and can be rendered
+	TypeTimelineReady = "timeline.ready"
+)
+
+// channelPrefix namespaces event channels in Redis
+const channelPrefix = "events:"
+
+// Event is a message published on the bus
+type Event struct {
+	Type       string          `json:"type"`
+	Data       json.RawMessage `json:"data"`
+	OccurredAt time.Time       `json:"occurredAt"`
+}
+
+// TimelineReady is the data of a timeline.ready event
+type TimelineReady struct {
+	TimelineID string  `json:"timelineId"`
+	UserID     string  `json:"userId"`
+	BatchID    string  `json:"batchId,omitempty"`
+	VideoID    string  `json:"videoId,omitempty"`
+	Duration   float64 `json:"duration"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	FPS        int     `json:"fps"`
+	Format     string  `json:"format,omitempty"`
+}
+
+// Handler processes an event received by a subscription
+type Handler func(ctx context.Context, event *Event) error
+
+// Bus publishes events over Redis pub/sub so other processes, like render
+// workers, can react to them. Delivery is fire-and-forget: subscribers that
+// aren't connected when an event is published don't receive it.
+type Bus struct {
+	client *redis.Client
+}
+
+// NewBus creates an event bus on a Redis server
+func NewBus(redisAddr string, redisPass string, redisDB int) *Bus {
+	return &Bus{
+		client: redis.NewClient(&redis.Options{
+			Addr:     redisAddr,
+			Password: redisPass,
+			DB:       redisDB,
+		}),
+	}
+}
+
+// Publish sends an event of the given type with data encoded as JSON
+func (b *Bus) Publish(ctx context.Context, eventType string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+	payload, err := json.Marshal(&Event{Type: eventType, Data: raw, OccurredAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := b.client.Publish(ctx, channelPrefix+eventType, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// Subscription delivers events of one type to a handler until closed
+type Subscription struct {
+	pubsub   *redis.PubSub
+	done     chan struct{}
+	once     sync.Once
+	closeErr error
+}
+
+// Subscribe calls handler for each event of the given type published from
+// now on, one at a time, until the subscription is closed or ctx is done.
+// Handler errors are logged; events aren't redelivered.
+func (b *Bus) Subscribe(ctx context.Context, eventType string, handler Handler) (*Subscription, error) {
+	pubsub := b.client.Subscribe(ctx, channelPrefix+eventType)
+	// Wait for the subscription to be confirmed, so no events published
+	// after Subscribe returns are missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", eventType, err)
+	}
+
+	sub := &Subscription{pubsub: pubsub, done: make(chan struct{})}
+	go func() {
+		defer close(sub.done)
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				sub.closePubSub()
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event Event
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					log.Printf("Dropping malformed %s event: %v", eventType, err)
+					continue
+				}
+				if err := handler(ctx, &event); err != nil {
+					log.Printf("Handling %s event failed: %v", eventType, err)
+				}
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// Close stops the subscription and waits for the event being handled, if
+// any, to finish
+func (s *Subscription) Close() error {
+	err := s.closePubSub()
+	<-s.done
+	return err
+}
+
+// closePubSub closes the Redis subscription once, whether the subscription
+// was closed or its context ended first
+func (s *Subscription) closePubSub() error {
+	s.once.Do(func() {
+		s.closeErr = s.pubsub.Close()
+	})
+	return s.closeErr
+}
+
+// Close closes the bus's Redis connection
+func (b *Bus) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/scheduler"
+	"renderowl-api/internal/service"
+)
+
+// AdminHandler handles operations endpoints for admins
+type AdminHandler struct {
+	batchService *service.BatchService
+	scheduler    *scheduler.Scheduler
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(batchService *service.BatchService, sched *scheduler.Scheduler) *AdminHandler {
+	return &AdminHandler{
+		batchService: batchService,
+		scheduler:    sched,
+	}
+}
+
+// GetQueues returns the backlog of the batch queues and the publishing
+// scheduler along with the liveness of the processes working them
+// GET /api/v1/admin/queues
+func (h *AdminHandler) GetQueues(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	batch, err := h.batchService.GetQueueStats(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "QUEUE_STATS_ERROR",
+		})
+		return
+	}
+
+	workers, err := h.batchService.GetWorkerHealth(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "QUEUE_STATS_ERROR",
+		})
+		return
+	}
+
+	sched, err := h.scheduler.Health(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "QUEUE_STATS_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"batch":     batch,
+		"workers":   workers,
+		"scheduler": sched,
+	})
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -14,18 +17,27 @@ type AIHandler struct {
 	scriptService *service.AIScriptService
 	sceneService  *service.AISceneService
 	ttsService    *service.TTSService
+	transcriber   *service.TranscriptionService
+	prompts       *service.PromptTemplateService
 }
 
 // NewAIHandler creates a new AI handler
-func NewAIHandler(scriptService *service.AIScriptService, sceneService *service.AISceneService, ttsService *service.TTSService) *AIHandler {
+func NewAIHandler(scriptService *service.AIScriptService, sceneService *service.AISceneService, ttsService *service.TTSService, transcriber *service.TranscriptionService) *AIHandler {
 	return &AIHandler{
 		scriptService: scriptService,
 		sceneService:  sceneService,
 		ttsService:    ttsService,
+		transcriber:   transcriber,
 	}
 }
 
-// GenerateScript generates a video script from a prompt
+// SetPromptTemplates enables the endpoints users manage their own system
+// prompt templates with; without it they answer 403
+func (h *AIHandler) SetPromptTemplates(prompts *service.PromptTemplateService) {
+	h.prompts = prompts
+}
+
+// GenerateScript generates a video script from a prompt, or one per language when languages is set
 // POST /api/v1/ai/script
 func (h *AIHandler) GenerateScript(c *gin.Context) {
 	user := middleware.GetUser(c)
@@ -43,19 +55,41 @@ func (h *AIHandler) GenerateScript(c *gin.Context) {
 		return
 	}
 
+	if len(req.Languages) > 0 {
+		scripts, err := h.scriptService.GenerateScripts(c.Request.Context(), &req)
+		if errors.Is(err, service.ErrUnknownScriptPreset) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "VALIDATION_ERROR",
+			})
+			return
+		}
+		if err != nil {
+			respondAIError(c, err, "AI_GENERATION_ERROR")
+			return
+		}
+
+		c.JSON(http.StatusOK, scripts)
+		return
+	}
+
 	script, err := h.scriptService.GenerateScript(c.Request.Context(), &req)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+	if errors.Is(err, service.ErrUnknownScriptPreset) {
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
-			"code":  "AI_GENERATION_ERROR",
+			"code":  "VALIDATION_ERROR",
 		})
 		return
 	}
+	if err != nil {
+		respondAIError(c, err, "AI_GENERATION_ERROR")
+		return
+	}
 
 	c.JSON(http.StatusOK, script)
 }
 
-// EnhanceScript enhances an existing script
+// EnhanceScript enhances an existing script, or only one of its scenes when scene_number is set
 // POST /api/v1/ai/script/enhance
 func (h *AIHandler) EnhanceScript(c *gin.Context) {
 	user := middleware.GetUser(c)
@@ -67,6 +101,7 @@ func (h *AIHandler) EnhanceScript(c *gin.Context) {
 	var req struct {
 		Script          *service.Script `json:"script" binding:"required"`
 		EnhancementType string          `json:"enhancement_type" binding:"required"`
+		SceneNumber     int             `json:"scene_number,omitempty"` // Enhance only this scene
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -76,14 +111,24 @@ func (h *AIHandler) EnhanceScript(c *gin.Context) {
 		return
 	}
 
-	script, err := h.scriptService.EnhanceScript(c.Request.Context(), req.Script, req.EnhancementType)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+	var script *service.Script
+	var err error
+	if req.SceneNumber > 0 {
+		script, err = h.scriptService.EnhanceScene(c.Request.Context(), req.Script, req.SceneNumber, req.EnhancementType)
+	} else {
+		script, err = h.scriptService.EnhanceScript(c.Request.Context(), req.Script, req.EnhancementType)
+	}
+	if errors.Is(err, service.ErrSceneNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
-			"code":  "AI_ENHANCEMENT_ERROR",
+			"code":  "VALIDATION_ERROR",
 		})
 		return
 	}
+	if err != nil {
+		respondAIError(c, err, "AI_ENHANCEMENT_ERROR")
+		return
+	}
 
 	c.JSON(http.StatusOK, script)
 }
@@ -107,10 +152,78 @@ func (h *AIHandler) GenerateScenes(c *gin.Context) {
 	}
 
 	result, err := h.sceneService.GenerateScenes(c.Request.Context(), &req)
+	if errors.Is(err, service.ErrUnknownPlatform) || errors.Is(err, service.ErrNoScenes) || errors.Is(err, service.ErrUnknownImageStyle) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		respondAIError(c, err, "AI_GENERATION_ERROR")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RestyleScenes re-enhances generated scenes in a different style
+// POST /api/v1/ai/scenes/restyle
+func (h *AIHandler) RestyleScenes(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.RestyleScenesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	result, err := h.sceneService.RestyleScenes(c.Request.Context(), &req)
+	if errors.Is(err, service.ErrUnknownPlatform) || errors.Is(err, service.ErrNoScenes) || errors.Is(err, service.ErrUnknownImageStyle) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+	if err != nil {
+		respondAIError(c, err, "AI_GENERATION_ERROR")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ReorderScenes puts generated scenes in a new order and reflows their timings
+// POST /api/v1/ai/scenes/reorder
+func (h *AIHandler) ReorderScenes(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.ReorderScenesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	result, err := h.sceneService.ReorderScenes(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
-			"code":  "AI_GENERATION_ERROR",
+			"code":  "VALIDATION_ERROR",
 		})
 		return
 	}
@@ -118,6 +231,50 @@ func (h *AIHandler) GenerateScenes(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// RegenerateSceneImage replaces the image for a single scene
+// POST /api/v1/ai/scenes/:sceneNumber/regenerate-image
+func (h *AIHandler) RegenerateSceneImage(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	sceneNumber, err := strconv.Atoi(c.Param("sceneNumber"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scene number",
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	var req service.RegenerateImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+	req.Scene.Number = sceneNumber
+
+	image, err := h.sceneService.RegenerateSceneImage(c.Request.Context(), &req)
+	if errors.Is(err, service.ErrUnknownPlatform) || errors.Is(err, service.ErrUnknownImageStyle) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+	if err != nil {
+		respondAIError(c, err, "AI_GENERATION_ERROR")
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
 // GenerateVoice generates voice narration from text
 // POST /api/v1/ai/voice
 func (h *AIHandler) GenerateVoice(c *gin.Context) {
@@ -149,14 +306,57 @@ func (h *AIHandler) GenerateVoice(c *gin.Context) {
 
 	result, err := h.ttsService.GenerateVoice(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		respondAIError(c, err, "TTS_GENERATION_ERROR")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Transcribe transcribes an existing video or audio file into timed segments,
+// optionally rendered as SRT or VTT captions
+// POST /api/v1/ai/transcribe
+func (h *AIHandler) Transcribe(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.TranscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
-			"code":  "TTS_GENERATION_ERROR",
+			"code":  "VALIDATION_ERROR",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	transcript, err := h.transcriber.Transcribe(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrMediaURLNotAllowed):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "VALIDATION_ERROR",
+			})
+		case errors.Is(err, service.ErrMediaTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": err.Error(),
+				"code":  "MEDIA_TOO_LARGE",
+			})
+		case errors.Is(err, service.ErrMediaFetch):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+				"code":  "MEDIA_FETCH_ERROR",
+			})
+		default:
+			respondAIError(c, err, "TRANSCRIPTION_ERROR")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, transcript)
 }
 
 // ListVoices returns available TTS voices
@@ -168,7 +368,8 @@ func (h *AIHandler) ListVoices(c *gin.Context) {
 		return
 	}
 
-	voices, err := h.ttsService.ListVoices(c.Request.Context())
+	clonedOnly := c.Query("cloned") == "true"
+	voices, err := h.ttsService.ListUserVoices(c.Request.Context(), user.ID, clonedOnly)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -185,6 +386,165 @@ func (h *AIHandler) ListVoices(c *gin.Context) {
 	})
 }
 
+// maxVoiceSamplesBytes caps the combined size of a clone's audio samples
+const maxVoiceSamplesBytes = 50 << 20
+
+// CloneVoice clones a voice from uploaded audio samples, sent as multipart
+// form data with a name, an optional description and one or more files
+// POST /api/v1/ai/voices/clone
+func (h *AIHandler) CloneVoice(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxVoiceSamplesBytes)
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid multipart form: " + err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	var samples []service.VoiceSample
+	for _, fileHeader := range form.File["files"] {
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read sample", "code": "VALIDATION_ERROR"})
+			return
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read sample", "code": "VALIDATION_ERROR"})
+			return
+		}
+		samples = append(samples, service.VoiceSample{Filename: fileHeader.Filename, Data: data})
+	}
+
+	voice, err := h.ttsService.CloneVoice(c.Request.Context(), user.ID, c.PostForm("name"), c.PostForm("description"), samples)
+	switch {
+	case errors.Is(err, service.ErrVoiceCloneNameRequired), errors.Is(err, service.ErrVoiceSamplesRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+		return
+	case errors.Is(err, service.ErrVoiceCloningDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error(), "code": "VOICE_CLONING_UNAVAILABLE"})
+		return
+	case err != nil:
+		respondAIError(c, err, "VOICE_CLONE_ERROR")
+		return
+	}
+
+	c.JSON(http.StatusCreated, voice)
+}
+
+// DeleteVoice deletes one of the user's cloned voices
+// DELETE /api/v1/ai/voices/:id
+func (h *AIHandler) DeleteVoice(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	err := h.ttsService.DeleteVoice(c.Request.Context(), user.ID, c.Param("id"))
+	switch {
+	case errors.Is(err, service.ErrVoiceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": "NOT_FOUND"})
+		return
+	case errors.Is(err, service.ErrVoiceCloningDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error(), "code": "VOICE_CLONING_UNAVAILABLE"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "code": "VOICE_DELETE_ERROR"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListPromptTemplates returns the user's system prompt templates, the
+// built-in ones they replace, and the placeholders templates can use
+// GET /api/v1/ai/prompts
+func (h *AIHandler) ListPromptTemplates(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templates, err := h.prompts.ListTemplates(c.Request.Context(), user.ID)
+	if err != nil {
+		respondPromptTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// SavePromptTemplate sets the user's system prompt template of a kind
+// PUT /api/v1/ai/prompts/:kind
+func (h *AIHandler) SavePromptTemplate(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Template string `json:"template" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	template, err := h.prompts.SaveTemplate(c.Request.Context(), user.ID, c.Param("kind"), req.Template)
+	if err != nil {
+		respondPromptTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeletePromptTemplate goes back to the built-in system prompt of a kind
+// DELETE /api/v1/ai/prompts/:kind
+func (h *AIHandler) DeletePromptTemplate(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.prompts.DeleteTemplate(c.Request.Context(), user.ID, c.Param("kind")); err != nil {
+		respondPromptTemplateError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondPromptTemplateError maps prompt template errors to responses
+func respondPromptTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPromptOverridesDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "PROMPT_OVERRIDES_DISABLED"})
+	case errors.Is(err, service.ErrUnknownPromptKind), errors.Is(err, service.ErrPromptTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "code": "NOT_FOUND"})
+	case errors.Is(err, service.ErrInvalidPromptTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "code": "PROMPT_TEMPLATE_ERROR"})
+	}
+}
+
 // GetScriptStyles returns available script styles
 // GET /api/v1/ai/script-styles
 func (h *AIHandler) GetScriptStyles(c *gin.Context) {
@@ -232,6 +592,22 @@ func (h *AIHandler) GetScriptStyles(c *gin.Context) {
 	})
 }
 
+// GetScriptPresets returns the named script lengths
+// GET /api/v1/ai/script-presets
+func (h *AIHandler) GetScriptPresets(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": service.ScriptPresets,
+	})
+}
+
+// GetImageStyles returns the scene image style presets
+// GET /api/v1/ai/image-styles
+func (h *AIHandler) GetImageStyles(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": service.ImageStylePresets,
+	})
+}
+
 // GetImageSources returns available image sources
 // GET /api/v1/ai/image-sources
 func (h *AIHandler) GetImageSources(c *gin.Context) {
@@ -273,7 +649,38 @@ func (h *AIHandler) GetImageSources(c *gin.Context) {
 		},
 	}
 
+	defaultSource := h.sceneService.DefaultImageSource()
+	for _, source := range sources {
+		source["default"] = source["id"] == defaultSource
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"data": sources,
 	})
 }
+
+// respondAIError responds to a failed AI call: 400 or 403 when the system
+// prompt template sent is invalid or not allowed, 504 when it ran out of
+// time, otherwise 500 with the endpoint's error code
+func respondAIError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPromptTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "VALIDATION_ERROR"})
+		return
+	case errors.Is(err, service.ErrPromptOverridesDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "PROMPT_OVERRIDES_DISABLED"})
+		return
+	}
+	var timeoutErr *service.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{
+			"error": err.Error(),
+			"code":  "AI_TIMEOUT",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": err.Error(),
+		"code":  code,
+	})
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -77,20 +80,7 @@ func (h *AnalyticsHandler) GetVideoPerformance(c *gin.Context) {
 		return
 	}
 
-	// Pagination
-	limit := 20
-	offset := 0
-	
-	if l := c.Query("limit"); l != "" {
-		if val, err := strconv.Atoi(l); err == nil && val > 0 {
-			limit = val
-		}
-	}
-	if o := c.Query("offset"); o != "" {
-		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
-			offset = val
-		}
-	}
+	limit, offset := parsePagination(c, 20)
 
 	performance, err := h.service.GetVideoPerformance(c.Request.Context(), user.ID, limit, offset)
 	if err != nil {
@@ -103,14 +93,58 @@ func (h *AnalyticsHandler) GetVideoPerformance(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": performance.Videos,
-		"meta": gin.H{
-			"limit":  limit,
-			"offset": offset,
-			"total":  performance.TotalCount,
-		},
+		"meta": pageMeta(limit, offset, len(performance.Videos), performance.TotalCount),
 	})
 }
 
+// GetViewsOverTime returns views bucketed by day (default) or hour
+func (h *AnalyticsHandler) GetViewsOverTime(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// Get days parameter (default 30)
+	days := 30
+	if d := c.Query("days"); d != "" {
+		if val, err := strconv.Atoi(d); err == nil && val > 0 {
+			days = val
+		}
+	}
+
+	granularity := c.DefaultQuery("granularity", service.GranularityDay)
+	switch granularity {
+	case service.GranularityDay:
+	case service.GranularityHour:
+		// Hourly buckets grow quickly; keep responses bounded
+		if days > 31 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Hourly granularity supports at most 31 days",
+				"code":  "VALIDATION_ERROR",
+			})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "granularity must be 'day' or 'hour'",
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	views, err := h.service.GetViewsOverTime(c.Request.Context(), user.ID, days, granularity)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
 // GetPlatformBreakdown returns platform breakdown data
 func (h *AnalyticsHandler) GetPlatformBreakdown(c *gin.Context) {
 	user := middleware.GetUser(c)
@@ -292,6 +326,20 @@ func (h *AnalyticsHandler) ExportAnalytics(c *gin.Context) {
 	c.Data(http.StatusOK, c.Writer.Header().Get("Content-Type"), data)
 }
 
+// VerifyWebhook answers PubSubHubbub subscription verification requests
+func (h *AnalyticsHandler) VerifyWebhook(c *gin.Context) {
+	challenge := c.Query("hub.challenge")
+	if challenge == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "hub.challenge is required",
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	c.String(http.StatusOK, challenge)
+}
+
 // ReceiveWebhook handles incoming webhooks from platforms
 func (h *AnalyticsHandler) ReceiveWebhook(c *gin.Context) {
 	platform := c.Param("platform")
@@ -303,8 +351,8 @@ func (h *AnalyticsHandler) ReceiveWebhook(c *gin.Context) {
 		return
 	}
 
-	var payload map[string]interface{}
-	if err := c.ShouldBindJSON(&payload); err != nil {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 			"code":  "VALIDATION_ERROR",
@@ -312,10 +360,28 @@ func (h *AnalyticsHandler) ReceiveWebhook(c *gin.Context) {
 		return
 	}
 
-	// Determine event type from payload
+	var payload map[string]interface{}
 	eventType := "unknown"
-	if et, ok := payload["event_type"].(string); ok {
-		eventType = et
+
+	if platform == "youtube" && strings.Contains(c.ContentType(), "xml") {
+		// YouTube PubSubHubbub notifications are Atom documents
+		payload = map[string]interface{}{"atom": string(body)}
+		eventType = "notification"
+	} else {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "VALIDATION_ERROR",
+			})
+			return
+		}
+
+		// Determine event type from payload
+		if et, ok := payload["event_type"].(string); ok {
+			eventType = et
+		} else if et, ok := payload["event"].(string); ok {
+			eventType = et
+		}
 	}
 
 	// Get video ID from payload
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// ListAlerts returns the user's analytics alerts
+// GET /api/v1/analytics/alerts
+func (h *AnalyticsHandler) ListAlerts(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	alerts, err := h.service.ListAlerts(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": alerts})
+}
+
+// CreateAlert registers a threshold alert that fires a signed webhook
+// POST /api/v1/analytics/alerts
+func (h *AnalyticsHandler) CreateAlert(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.AnalyticsAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	alert, err := h.service.CreateAlert(c.Request.Context(), user.ID, &req)
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, alert)
+}
+
+// UpdateAlert replaces an analytics alert's rule
+// PUT /api/v1/analytics/alerts/:id
+func (h *AnalyticsHandler) UpdateAlert(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.AnalyticsAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	alert, err := h.service.UpdateAlert(c.Request.Context(), user.ID, c.Param("id"), &req)
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// DeleteAlert deletes an analytics alert
+// DELETE /api/v1/analytics/alerts/:id
+func (h *AnalyticsHandler) DeleteAlert(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.service.DeleteAlert(c.Request.Context(), user.ID, c.Param("id")); err != nil {
+		respondAlertError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondAlertError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAlertNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+		return
+	case errors.Is(err, service.ErrInvalidWebhookURL):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": err.Error(),
+		"code":  "INTERNAL_ERROR",
+	})
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -50,6 +52,44 @@ func (h *ClipHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, clip)
 }
 
+// BulkCreate creates several clips atomically
+func (h *ClipHandler) BulkCreate(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	timelineID := c.Param("id")
+
+	// Fields are validated by the service; binding's required tag would reject a zero startTime
+	var reqs []service.CreateClipRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&reqs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	clips, err := h.service.CreateBulk(user.ID, timelineID, reqs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "BAD_REQUEST",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": clips,
+		"meta": gin.H{
+			"timelineId": timelineID,
+			"total":      len(clips),
+		},
+	})
+}
+
 // Get retrieves a clip by ID
 func (h *ClipHandler) Get(c *gin.Context) {
 	user := middleware.GetUser(c)
@@ -120,6 +160,13 @@ func (h *ClipHandler) Update(c *gin.Context) {
 	}
 
 	clip, err := h.service.Update(user.ID, clipID, &req)
+	if errors.Is(err, service.ErrInvalidClipAudio) || errors.Is(err, service.ErrInvalidClipSource) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
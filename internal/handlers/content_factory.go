@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"renderowl-api/internal/domain"
 	"renderowl-api/internal/middleware"
 	"renderowl-api/internal/service"
 )
@@ -98,7 +102,7 @@ func (h *ContentFactoryHandler) GetContentSuggestions(c *gin.Context) {
 		return
 	}
 
-	suggestions, err := h.ideationService.GetContentSuggestions(c.Request.Context(), &req)
+	suggestions, err := h.ideationService.GetContentSuggestions(c.Request.Context(), user.ID, &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -116,6 +120,36 @@ func (h *ContentFactoryHandler) GetContentSuggestions(c *gin.Context) {
 	})
 }
 
+// EstimateReach estimates the views of a content idea and explains how
+// POST /api/v1/ideation/estimate-reach
+func (h *ContentFactoryHandler) EstimateReach(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.EstimateReachRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	estimate, err := h.ideationService.EstimateReach(c.Request.Context(), user.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
 // AnalyzeCompetitor analyzes a competitor channel
 // POST /api/v1/ideation/competitor-analysis
 func (h *ContentFactoryHandler) AnalyzeCompetitor(c *gin.Context) {
@@ -135,6 +169,20 @@ func (h *ContentFactoryHandler) AnalyzeCompetitor(c *gin.Context) {
 	}
 
 	analysis, err := h.ideationService.AnalyzeCompetitor(c.Request.Context(), &req)
+	if errors.Is(err, service.ErrInvalidChannel) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrChannelNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -164,7 +212,7 @@ func (h *ContentFactoryHandler) GenerateContentCalendar(c *gin.Context) {
 		return
 	}
 
-	calendar, err := h.ideationService.GenerateContentCalendar(c.Request.Context(), &req)
+	calendar, err := h.ideationService.GenerateContentCalendar(c.Request.Context(), user.ID, &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -200,6 +248,14 @@ func (h *ContentFactoryHandler) CreateBatch(c *gin.Context) {
 
 	batch, err := h.batchService.CreateBatch(c.Request.Context(), user.ID, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrMusicTrackNotFound) || errors.Is(err, service.ErrUnknownImageStyle) || errors.Is(err, service.ErrInvalidOutputFormat) ||
+			errors.Is(err, service.ErrInvalidWebhookURL) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "VALIDATION_ERROR",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 			"code":  "BATCH_CREATE_ERROR",
@@ -221,7 +277,14 @@ func (h *ContentFactoryHandler) StartBatch(c *gin.Context) {
 
 	batchID := c.Param("id")
 
-	if err := h.batchService.StartBatch(c.Request.Context(), batchID); err != nil {
+	if err := h.batchService.StartBatch(c.Request.Context(), batchID, user.ID); err != nil {
+		if errors.Is(err, domain.ErrBatchNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+				"code":  "NOT_FOUND",
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 			"code":  "START_ERROR",
@@ -246,7 +309,7 @@ func (h *ContentFactoryHandler) GetBatchStatus(c *gin.Context) {
 
 	batchID := c.Param("id")
 
-	progress, err := h.batchService.GetBatchProgress(c.Request.Context(), batchID)
+	progress, err := h.batchService.GetBatchProgress(c.Request.Context(), batchID, user.ID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
@@ -269,7 +332,7 @@ func (h *ContentFactoryHandler) GetBatchResults(c *gin.Context) {
 
 	batchID := c.Param("id")
 
-	results, err := h.batchService.GetBatchResults(c.Request.Context(), batchID)
+	results, err := h.batchService.GetBatchResults(c.Request.Context(), batchID, user.ID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
@@ -281,6 +344,35 @@ func (h *ContentFactoryHandler) GetBatchResults(c *gin.Context) {
 	c.JSON(http.StatusOK, results)
 }
 
+// GetBatchVideo returns one video of a batch whatever its status, with its
+// config, error, stage progress, timing and processing log
+// GET /api/v1/batch/:id/videos/:videoId
+func (h *ContentFactoryHandler) GetBatchVideo(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	video, err := h.batchService.GetBatchVideo(c.Request.Context(), c.Param("id"), c.Param("videoId"), user.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrBatchNotFound) || errors.Is(err, domain.ErrBatchVideoNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+				"code":  "NOT_FOUND",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, video)
+}
+
 // ListBatches lists all batches for the user
 // GET /api/v1/batch
 func (h *ContentFactoryHandler) ListBatches(c *gin.Context) {
@@ -290,10 +382,9 @@ func (h *ContentFactoryHandler) ListBatches(c *gin.Context) {
 		return
 	}
 
-	limit := 20
-	offset := 0
+	limit, offset := parsePagination(c, 20)
 
-	batches, err := h.batchService.ListBatches(c.Request.Context(), user.ID, limit, offset)
+	batches, total, err := h.batchService.ListBatches(c.Request.Context(), user.ID, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -304,11 +395,7 @@ func (h *ContentFactoryHandler) ListBatches(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": batches,
-		"meta": gin.H{
-			"limit":  limit,
-			"offset": offset,
-			"total":  len(batches),
-		},
+		"meta": pageMeta(limit, offset, len(batches), total),
 	})
 }
 
@@ -323,7 +410,14 @@ func (h *ContentFactoryHandler) CancelBatch(c *gin.Context) {
 
 	batchID := c.Param("id")
 
-	if err := h.batchService.CancelBatch(c.Request.Context(), batchID); err != nil {
+	if err := h.batchService.CancelBatch(c.Request.Context(), batchID, user.ID); err != nil {
+		if errors.Is(err, domain.ErrBatchNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+				"code":  "NOT_FOUND",
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 			"code":  "CANCEL_ERROR",
@@ -348,7 +442,14 @@ func (h *ContentFactoryHandler) RetryFailedVideos(c *gin.Context) {
 
 	batchID := c.Param("id")
 
-	if err := h.batchService.RetryFailedVideos(c.Request.Context(), batchID); err != nil {
+	if err := h.batchService.RetryFailedVideos(c.Request.Context(), batchID, user.ID); err != nil {
+		if errors.Is(err, domain.ErrBatchNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+				"code":  "NOT_FOUND",
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 			"code":  "RETRY_ERROR",
@@ -383,6 +484,134 @@ func (h *ContentFactoryHandler) GetQueueStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// SaveBatchTemplate saves a reusable batch configuration
+// POST /api/v1/batch/templates
+func (h *ContentFactoryHandler) SaveBatchTemplate(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.SaveBatchTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	template, err := h.batchService.SaveTemplate(c.Request.Context(), user.ID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "TEMPLATE_SAVE_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListBatchTemplates lists the user's saved batch templates
+// GET /api/v1/batch/templates
+func (h *ContentFactoryHandler) ListBatchTemplates(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	templates, err := h.batchService.ListTemplates(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "LIST_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": templates,
+		"meta": gin.H{
+			"total": len(templates),
+		},
+	})
+}
+
+// CreateBatchFromTemplate creates a batch from a saved template and a list of videos
+// POST /api/v1/batch/templates/:id/apply
+func (h *ContentFactoryHandler) CreateBatchFromTemplate(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.CreateBatchFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	batch, err := h.batchService.CreateBatchFromTemplate(c.Request.Context(), user.ID, c.Param("id"), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrBatchTemplateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+				"code":  "NOT_FOUND",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrMusicTrackNotFound) || errors.Is(err, service.ErrUnknownImageStyle) || errors.Is(err, service.ErrInvalidOutputFormat) ||
+			errors.Is(err, service.ErrInvalidWebhookURL) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "VALIDATION_ERROR",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "BATCH_CREATE_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, batch)
+}
+
+// DeleteBatchTemplate deletes a saved batch template
+// DELETE /api/v1/batch/templates/:id
+func (h *ContentFactoryHandler) DeleteBatchTemplate(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.batchService.DeleteTemplate(c.Request.Context(), c.Param("id"), user.ID); err != nil {
+		if errors.Is(err, domain.ErrBatchTemplateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+				"code":  "NOT_FOUND",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "TEMPLATE_DELETE_ERROR",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // ============================================
 // VARIATIONS ENDPOINTS
 // ============================================
@@ -405,8 +634,11 @@ func (h *ContentFactoryHandler) CreateVariations(c *gin.Context) {
 		return
 	}
 
-	result, err := h.variationsService.CreateVariations(c.Request.Context(), &req)
+	result, err := h.variationsService.CreateVariations(c.Request.Context(), user.ID, &req)
 	if err != nil {
+		if respondProbeError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 			"code":  "VARIATION_ERROR",
@@ -417,6 +649,134 @@ func (h *ContentFactoryHandler) CreateVariations(c *gin.Context) {
 	c.JSON(http.StatusCreated, result)
 }
 
+// PreviewCaptions returns the caption cues a short would be rendered with
+// POST /api/v1/variations/caption-preview
+func (h *ContentFactoryHandler) PreviewCaptions(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.CaptionPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	preview, err := h.variationsService.PreviewCaptions(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoCaptionSource):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "VALIDATION_ERROR",
+			})
+		case errors.Is(err, service.ErrMediaURLNotAllowed):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "VALIDATION_ERROR",
+			})
+		case errors.Is(err, service.ErrMediaTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": err.Error(),
+				"code":  "MEDIA_TOO_LARGE",
+			})
+		case errors.Is(err, service.ErrMediaFetch):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+				"code":  "MEDIA_FETCH_ERROR",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+				"code":  "TRANSCRIPTION_ERROR",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// RegenerateTitles replaces the titles of a variations run with new ones
+// POST /api/v1/variations/:jobId/titles
+func (h *ContentFactoryHandler) RegenerateTitles(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.RegenerateVariationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	titles, err := h.variationsService.RegenerateTitles(c.Request.Context(), user.ID, c.Param("jobId"), &req)
+	if err != nil {
+		respondRegenerateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"titles": titles})
+}
+
+// RegenerateThumbnails replaces the thumbnails of a variations run with new ones
+// POST /api/v1/variations/:jobId/thumbnails
+func (h *ContentFactoryHandler) RegenerateThumbnails(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.RegenerateVariationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	thumbnails, err := h.variationsService.RegenerateThumbnails(c.Request.Context(), user.ID, c.Param("jobId"), &req)
+	if err != nil {
+		respondRegenerateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"thumbnails": thumbnails})
+}
+
+// respondRegenerateError answers a failed title or thumbnail regeneration
+func respondRegenerateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrVariationJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+	case errors.Is(err, service.ErrUnknownVariationStyle):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "VARIATION_ERROR",
+		})
+	}
+}
+
 // GetPlatformSpecs returns platform specifications
 // GET /api/v1/variations/platforms
 func (h *ContentFactoryHandler) GetPlatformSpecs(c *gin.Context) {
@@ -448,6 +808,9 @@ func (h *ContentFactoryHandler) AnalyzeVideo(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
+	if !h.optimizerAvailable(c) {
+		return
+	}
 
 	var req struct {
 		VideoID string `json:"videoId" binding:"required"`
@@ -460,7 +823,14 @@ func (h *ContentFactoryHandler) AnalyzeVideo(c *gin.Context) {
 		return
 	}
 
-	suggestions, err := h.optimizerService.AnalyzeVideo(c.Request.Context(), req.VideoID)
+	suggestions, err := h.optimizerService.AnalyzeVideo(c.Request.Context(), user.ID, req.VideoID)
+	if errors.Is(err, domain.ErrVideoPerformanceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -469,11 +839,156 @@ func (h *ContentFactoryHandler) AnalyzeVideo(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"data": suggestions,
 		"meta": gin.H{
 			"total": len(suggestions),
 		},
+	}
+
+	// Sentiment is best-effort: not every platform exposes comments
+	if sentiment, err := h.optimizerService.GetVideoSentiment(c.Request.Context(), user.ID, req.VideoID); err == nil {
+		response["sentiment"] = sentiment
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DismissSuggestion hides a suggestion from future analyses
+// POST /api/v1/optimizer/suggestions/:id/dismiss
+func (h *ContentFactoryHandler) DismissSuggestion(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.optimizerAvailable(c) {
+		return
+	}
+
+	suggestion, err := h.optimizerService.DismissSuggestion(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		respondSuggestionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// RateSuggestion records whether a suggestion was helpful
+// POST /api/v1/optimizer/suggestions/:id/feedback
+func (h *ContentFactoryHandler) RateSuggestion(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.optimizerAvailable(c) {
+		return
+	}
+
+	var req struct {
+		Helpful *bool `json:"helpful" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	suggestion, err := h.optimizerService.RateSuggestion(c.Request.Context(), user.ID, c.Param("id"), *req.Helpful)
+	if err != nil {
+		respondSuggestionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// ApplySuggestion marks a suggestion as applied, applying it automatically where possible
+// POST /api/v1/optimizer/suggestions/:id/apply
+func (h *ContentFactoryHandler) ApplySuggestion(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.optimizerAvailable(c) {
+		return
+	}
+
+	suggestion, err := h.optimizerService.MarkSuggestionApplied(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		respondSuggestionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// GetSuggestionHistory returns a video's current and past suggestions
+// GET /api/v1/optimizer/videos/:id/suggestions
+func (h *ContentFactoryHandler) GetSuggestionHistory(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.optimizerAvailable(c) {
+		return
+	}
+
+	history, err := h.optimizerService.GetSuggestionHistory(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "FETCH_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// optimizerAvailable responds 503 when the optimizer isn't configured
+func (h *ContentFactoryHandler) optimizerAvailable(c *gin.Context) bool {
+	if h.optimizerService != nil {
+		return true
+	}
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error": "optimizer is not available",
+		"code":  "OPTIMIZER_UNAVAILABLE",
+	})
+	return false
+}
+
+func respondSuggestionError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrSuggestionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrSuggestionApplied) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+			"code":  "ALREADY_APPLIED",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrThumbnailsUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": err.Error(),
+			"code":  "THUMBNAILS_UNAVAILABLE",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": err.Error(),
+		"code":  "FEEDBACK_ERROR",
 	})
 }
 
@@ -485,6 +1000,9 @@ func (h *ContentFactoryHandler) GeneratePerformanceReport(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
+	if !h.optimizerAvailable(c) {
+		return
+	}
 
 	var req struct {
 		Days int `json:"days,omitempty"`
@@ -513,16 +1031,63 @@ func (h *ContentFactoryHandler) GeneratePerformanceReport(c *gin.Context) {
 	c.JSON(http.StatusOK, report)
 }
 
-// GetWinningContent identifies winning content patterns
-// GET /api/v1/optimizer/winning-content
+// GeneratePerformanceReportPDF renders the performance report as a PDF download
+// GET /api/v1/optimizer/report.pdf
+func (h *ContentFactoryHandler) GeneratePerformanceReportPDF(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !h.optimizerAvailable(c) {
+		return
+	}
+
+	// Get days parameter (default 30)
+	days := 30
+	if d := c.Query("days"); d != "" {
+		if val, err := strconv.Atoi(d); err == nil && val > 0 {
+			days = val
+		}
+	}
+
+	report, err := h.optimizerService.GeneratePerformanceReport(c.Request.Context(), user.ID, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "REPORT_ERROR",
+		})
+		return
+	}
+
+	data, err := service.RenderReportPDF(report)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "REPORT_ERROR",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=performance-report.pdf")
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// GetWinningContent returns winning content patterns
+// GET /api/v1/optimizer/winning-content?force=true
 func (h *ContentFactoryHandler) GetWinningContent(c *gin.Context) {
 	user := middleware.GetUser(c)
 	if user == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
+	if !h.optimizerAvailable(c) {
+		return
+	}
 
-	analysis, err := h.optimizerService.GetWinningContent(c.Request.Context(), user.ID)
+	// Served from the cache unless ?force=true asks for a live recompute
+	force := c.Query("force") == "true"
+	analysis, err := h.optimizerService.GetWinningContent(c.Request.Context(), user.ID, force)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -542,6 +1107,9 @@ func (h *ContentFactoryHandler) AutoOptimizeTitle(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
+	if !h.optimizerAvailable(c) {
+		return
+	}
 
 	var req struct {
 		VideoID      string `json:"videoId" binding:"required"`
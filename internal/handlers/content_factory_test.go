@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// fakeBatches holds batches by ID. Methods that change batches aren't
+// implemented, so reaching one fails the test.
+type fakeBatches struct {
+	domain.BatchRepository
+	batches map[string]*domain.Batch
+}
+
+func (f *fakeBatches) GetByIDAndUser(id, userID string) (*domain.Batch, error) {
+	batch, ok := f.batches[id]
+	if !ok || batch.UserID != userID {
+		return nil, domain.ErrBatchNotFound
+	}
+	return batch, nil
+}
+
+func TestOtherUsersBatchesAreNotFound(t *testing.T) {
+	repo := &fakeBatches{batches: map[string]*domain.Batch{
+		"batch-a": {
+			ID:          "batch-a",
+			UserID:      "user-a",
+			Status:      domain.BatchStatusFailed,
+			TotalVideos: 1,
+			Videos:      []domain.BatchVideo{{ID: "video-a", BatchID: "batch-a", Status: domain.VideoStatusFailed}},
+		},
+	}}
+	batchService, err := service.NewBatchService(repo, nil, "127.0.0.1:0", "", nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBatchService: %v", err)
+	}
+	h := NewContentFactoryHandler(nil, batchService, nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, &domain.UserContext{ID: c.GetHeader("X-User-ID")})
+	})
+	r.POST("/batch/:id/start", h.StartBatch)
+	r.GET("/batch/:id/status", h.GetBatchStatus)
+	r.GET("/batch/:id/results", h.GetBatchResults)
+	r.GET("/batch/:id/videos/:videoId", h.GetBatchVideo)
+	r.POST("/batch/:id/cancel", h.CancelBatch)
+	r.POST("/batch/:id/retry", h.RetryFailedVideos)
+
+	requests := []struct {
+		method, path string
+	}{
+		{"POST", "/batch/batch-a/start"},
+		{"GET", "/batch/batch-a/status"},
+		{"GET", "/batch/batch-a/results"},
+		{"GET", "/batch/batch-a/videos/video-a"},
+		{"POST", "/batch/batch-a/cancel"},
+		{"POST", "/batch/batch-a/retry"},
+	}
+	for _, req := range requests {
+		httpReq := httptest.NewRequest(req.method, req.path, nil)
+		httpReq.Header.Set("X-User-ID", "user-b")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httpReq)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s %s as user-b: status %d, want 404: %s", req.method, req.path, w.Code, w.Body)
+		}
+	}
+
+	for _, path := range []string{"/batch/batch-a/status", "/batch/batch-a/videos/video-a"} {
+		httpReq := httptest.NewRequest("GET", path, nil)
+		httpReq.Header.Set("X-User-ID", "user-a")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httpReq)
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s as user-a: status %d, want 200: %s", path, w.Code, w.Body)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// ContentStatusHandler handles content pipeline status HTTP requests
+type ContentStatusHandler struct {
+	service *service.ContentStatusService
+}
+
+// NewContentStatusHandler creates a new content status handler
+func NewContentStatusHandler(service *service.ContentStatusService) *ContentStatusHandler {
+	return &ContentStatusHandler{service: service}
+}
+
+// GetStatus returns where a video is in the content pipeline, with the
+// status and artifacts of every stage
+// GET /api/v1/content/:id/status
+func (h *ContentStatusHandler) GetStatus(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	status, err := h.service.GetStatus(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrContentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+				"code":  "NOT_FOUND",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
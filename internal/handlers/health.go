@@ -8,6 +8,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+
+	"renderowl-api/internal/service"
 )
 
 // HealthHandler handles health check requests
@@ -74,6 +76,9 @@ func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
 		"message": "Remotion check not implemented",
 	}
 
+	// AI provider circuit breakers; an open breaker degrades but doesn't fail readiness
+	checks["aiProviders"] = service.ProviderBreakers()
+
 	response := gin.H{
 		"status":   "ready",
 		"checks":   checks,
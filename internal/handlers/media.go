@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// MediaHandler handles media inspection HTTP requests
+type MediaHandler struct {
+	prober *service.MediaProbeService
+}
+
+// NewMediaHandler creates a new media handler
+func NewMediaHandler(prober *service.MediaProbeService) *MediaHandler {
+	return &MediaHandler{prober: prober}
+}
+
+// Probe returns a video's or audio file's duration, resolution, codecs and
+// size, given its URL or storage key
+// POST /api/v1/media/probe
+func (h *MediaHandler) Probe(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.ProbeMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	metadata, err := h.prober.Probe(c.Request.Context(), &req)
+	if err != nil {
+		if !respondProbeError(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+				"code":  "PROBE_ERROR",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, metadata)
+}
+
+// respondProbeError answers errors caused by the media given to probe,
+// reporting whether err was one
+func respondProbeError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrProbeInput), errors.Is(err, service.ErrUnknownSourceDuration):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+	case errors.Is(err, service.ErrFileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+	case errors.Is(err, service.ErrProbeTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": err.Error(),
+			"code":  "MEDIA_TOO_LARGE",
+		})
+	case errors.Is(err, service.ErrMediaFetch):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"code":  "MEDIA_FETCH_ERROR",
+		})
+	case errors.Is(err, service.ErrNotMedia):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_MEDIA",
+		})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// MusicHandler handles music library HTTP requests
+type MusicHandler struct {
+	library *service.MusicLibrary
+}
+
+// NewMusicHandler creates a new music handler
+func NewMusicHandler(library *service.MusicLibrary) *MusicHandler {
+	return &MusicHandler{library: library}
+}
+
+// ListTracks returns the background music library, optionally filtered by
+// genre and mood
+// GET /api/v1/music
+func (h *MusicHandler) ListTracks(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tracks := h.library.List(service.MusicFilter{
+		Genre: c.Query("genre"),
+		Mood:  c.Query("mood"),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tracks,
+		"meta": gin.H{
+			"total": len(tracks),
+		},
+	})
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPageLimit caps the page size list endpoints accept
+const maxPageLimit = 100
+
+// parsePagination reads the limit and offset query params, falling back to
+// defaultLimit and 0 when they are missing or invalid
+func parsePagination(c *gin.Context, defaultLimit int) (limit, offset int) {
+	limit = defaultLimit
+	if val, err := strconv.Atoi(c.Query("limit")); err == nil && val > 0 {
+		limit = min(val, maxPageLimit)
+	}
+	if val, err := strconv.Atoi(c.Query("offset")); err == nil && val >= 0 {
+		offset = val
+	}
+	return limit, offset
+}
+
+// pageMeta describes a page of count items out of total
+func pageMeta(limit, offset, count int, total int64) gin.H {
+	return gin.H{
+		"limit":   limit,
+		"offset":  offset,
+		"total":   total,
+		"hasMore": int64(offset+count) < total,
+	}
+}
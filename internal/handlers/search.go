@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// SearchHandler handles library search HTTP requests
+type SearchHandler struct {
+	service *service.SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(service *service.SearchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+// Search finds the user's timelines, batches and scheduled posts by name.
+// The types query param narrows it, e.g. types=timeline,batch.
+// GET /api/v1/search?q=
+func (h *SearchHandler) Search(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	limit, offset := parsePagination(c, 20)
+	var types []string
+	for _, t := range strings.Split(c.Query("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+
+	results, total, err := h.service.Search(c.Request.Context(), user.ID, c.Query("q"), types, limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrSearchTooShort) || errors.Is(err, service.ErrUnknownSearchType) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "VALIDATION_ERROR",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": results,
+		"meta": pageMeta(limit, offset, len(results), total),
+	})
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// ShareHandler handles share link HTTP requests
+type ShareHandler struct {
+	service *service.ShareService
+}
+
+// NewShareHandler creates a new share handler
+func NewShareHandler(service *service.ShareService) *ShareHandler {
+	return &ShareHandler{service: service}
+}
+
+// ShareTimeline creates a share link to a timeline
+// POST /api/v1/timelines/:id/share
+func (h *ShareHandler) ShareTimeline(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.ShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	link, err := h.service.ShareTimeline(c.Request.Context(), user.ID, c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// ShareReport creates a share link to the user's performance report
+// POST /api/v1/optimizer/report/share
+func (h *ShareHandler) ShareReport(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.ShareReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	link, err := h.service.ShareReport(c.Request.Context(), user.ID, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// List returns the user's active share links
+// GET /api/v1/shares
+func (h *ShareHandler) List(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	links, err := h.service.ListShares(c.Request.Context(), user.ID)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shares": links})
+}
+
+// Revoke revokes a share link
+// DELETE /api/v1/shares/:id
+func (h *ShareHandler) Revoke(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.service.RevokeShare(c.Request.Context(), user.ID, c.Param("id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Open serves the reduced view of whatever a share token links to. It is
+// public: the signed token is the credential.
+// GET /share/:token
+func (h *ShareHandler) Open(c *gin.Context) {
+	view, err := h.service.OpenShare(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *ShareHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrShareNotFound), errors.Is(err, service.ErrShareTargetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+	case errors.Is(err, service.ErrShareExpired):
+		c.JSON(http.StatusGone, gin.H{
+			"error": err.Error(),
+			"code":  "SHARE_EXPIRED",
+		})
+	case errors.Is(err, service.ErrInvalidShareExpiry):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+	case errors.Is(err, service.ErrReportSharesOff):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": err.Error(),
+			"code":  "SERVICE_UNAVAILABLE",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package social
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// CampaignHandler handles publishing campaign HTTP requests
+type CampaignHandler struct {
+	campaignService *service.CampaignService
+}
+
+// NewCampaignHandler creates a new campaign handler
+func NewCampaignHandler(campaignService *service.CampaignService) *CampaignHandler {
+	return &CampaignHandler{campaignService: campaignService}
+}
+
+// CreateCampaign starts publishing a source video to every target platform
+func (h *CampaignHandler) CreateCampaign(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req service.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
+		return
+	}
+
+	campaign, err := h.campaignService.CreateCampaign(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, campaign)
+}
+
+// GetCampaign returns a campaign with the status of each target
+func (h *CampaignHandler) GetCampaign(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	campaign, err := h.campaignService.GetCampaign(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, campaign)
+}
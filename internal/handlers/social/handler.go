@@ -1,12 +1,17 @@
 package social
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"renderowl-api/internal/domain"
 	socialdomain "renderowl-api/internal/domain/social"
+	"renderowl-api/internal/middleware"
 	"renderowl-api/internal/scheduler"
 	"renderowl-api/internal/service"
 	socialsvc "renderowl-api/internal/service/social"
@@ -17,6 +22,7 @@ type Handler struct {
 	socialService *socialsvc.Service
 	publisher     *service.Publisher
 	scheduler     *scheduler.Scheduler
+	uploads       *service.MediaUploadService
 }
 
 // NewSocialHandler creates a new social media handler
@@ -46,7 +52,7 @@ func (h *Handler) GetAccounts(c *gin.Context) {
 
 	accounts, err := h.socialService.GetAccounts(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
 		return
 	}
 
@@ -57,11 +63,59 @@ func (h *Handler) GetAccounts(c *gin.Context) {
 
 // GetAccount returns a specific account
 func (h *Handler) GetAccount(c *gin.Context) {
+	userID := c.GetString("userID")
+	accountID := c.Param("id")
+
+	account, err := h.socialService.GetAccount(c.Request.Context(), accountID, userID)
+	if err != nil {
+		middleware.RespondError(c, socialsvc.ErrAccountNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// GetAccountMetrics returns an account's follower, view and video counts and
+// their history over the last ?days (30 by default)
+func (h *Handler) GetAccountMetrics(c *gin.Context) {
+	userID := c.GetString("userID")
+	accountID := c.Param("id")
+
+	days := socialsvc.DefaultMetricsHistoryDays
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > socialsvc.MaxMetricsHistoryDays {
+			middleware.RespondError(c, domain.NewValidationError(fmt.Sprintf("days must be between 1 and %d", socialsvc.MaxMetricsHistoryDays)))
+			return
+		}
+		days = parsed
+	}
+
+	metrics, err := h.socialService.GetAccountMetrics(c.Request.Context(), accountID, userID, days)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// UpdateSchedulingRules sets an account's posting window; null schedulingRules clears it
+func (h *Handler) UpdateSchedulingRules(c *gin.Context) {
+	userID := c.GetString("userID")
 	accountID := c.Param("id")
 
-	account, err := h.socialService.GetAccount(c.Request.Context(), accountID)
+	var req struct {
+		SchedulingRules *socialdomain.SchedulingRules `json:"schedulingRules"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
+		return
+	}
+
+	account, err := h.socialService.UpdateSchedulingRules(c.Request.Context(), accountID, userID, req.SchedulingRules)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
+		middleware.RespondError(c, err)
 		return
 	}
 
@@ -70,16 +124,46 @@ func (h *Handler) GetAccount(c *gin.Context) {
 
 // DisconnectAccount removes a connected account
 func (h *Handler) DisconnectAccount(c *gin.Context) {
+	userID := c.GetString("userID")
 	accountID := c.Param("id")
 
-	if err := h.socialService.DisconnectAccount(c.Request.Context(), accountID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.socialService.DisconnectAccount(c.Request.Context(), accountID, userID); err != nil {
+		middleware.RespondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Account disconnected"})
 }
 
+// RefreshAccount forces a token refresh so expired accounts can recover without reconnecting
+func (h *Handler) RefreshAccount(c *gin.Context) {
+	userID := c.GetString("userID")
+	accountID := c.Param("id")
+
+	account, err := h.socialService.RefreshAccountToken(c.Request.Context(), accountID, userID)
+	if errors.Is(err, socialsvc.ErrReconnectRequired) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":             err.Error(),
+			"code":              domain.CodePlatformExpired,
+			"reconnectRequired": true,
+			"platform":          account.Platform,
+			"status":            account.Status,
+			"tokenExpiry":       account.TokenExpiry,
+		})
+		return
+	}
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":      account.Status,
+		"tokenExpiry": account.TokenExpiry,
+		"account":     account,
+	})
+}
+
 // GetAuthURL returns OAuth URL for a platform
 func (h *Handler) GetAuthURL(c *gin.Context) {
 	platform := socialdomain.SocialPlatform(c.Param("platform"))
@@ -90,7 +174,7 @@ func (h *Handler) GetAuthURL(c *gin.Context) {
 
 	authURL, err := h.socialService.GetAuthURL(platform, state)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
 		return
 	}
 
@@ -110,46 +194,79 @@ func (h *Handler) HandleCallback(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
 		return
 	}
 
 	account, err := h.socialService.ConnectAccount(c.Request.Context(), platform, req.Code, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, account)
 }
 
-// UploadVideo uploads a video immediately
+// UploadVideo uploads a video, image or document immediately
 func (h *Handler) UploadVideo(c *gin.Context) {
+	userID := c.GetString("userID")
+
 	var req struct {
-		AccountID   string   `json:"accountId"`
-		VideoPath   string   `json:"videoPath"`
-		Title       string   `json:"title"`
-		Description string   `json:"description"`
-		Tags        []string `json:"tags"`
-		Privacy     string   `json:"privacy"`
+		AccountID    string                 `json:"accountId"`
+		MediaType    socialdomain.MediaType `json:"mediaType"`
+		VideoPath    string                 `json:"videoPath"`
+		Title        string                 `json:"title"`
+		Description  string                 `json:"description"`
+		Tags         []string               `json:"tags"`
+		Privacy      string                 `json:"privacy"`
+		CategoryID   string                 `json:"categoryId"` // YouTube only
+		FirstComment string                 `json:"firstComment"`
+		ThumbnailURL string                 `json:"thumbnailUrl"`
+		CoverFrameMs *int64                 `json:"coverFrameMs"`
+		AltText      string                 `json:"altText"`
+		SceneAltText []string               `json:"sceneAltText"`
+		ForcePublish bool                   `json:"forcePublish"`
+		// AllowDuplicate posts the video even when a near-duplicate of it
+		// was recently posted to the account
+		AllowDuplicate bool `json:"allowDuplicate"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
+		return
+	}
+
+	override, err := moderationOverride(c, req.ForcePublish)
+	if err != nil {
+		middleware.RespondError(c, err)
 		return
 	}
 
 	uploadReq := &socialdomain.UploadRequest{
-		VideoPath:   req.VideoPath,
-		Title:       req.Title,
-		Description: req.Description,
-		Tags:        req.Tags,
-		Privacy:     req.Privacy,
+		MediaType:          req.MediaType,
+		VideoPath:          req.VideoPath,
+		Title:              req.Title,
+		Description:        req.Description,
+		Tags:               req.Tags,
+		Privacy:            req.Privacy,
+		CategoryID:         req.CategoryID,
+		FirstComment:       req.FirstComment,
+		ThumbnailURL:       req.ThumbnailURL,
+		CoverFrameMs:       req.CoverFrameMs,
+		AltText:            req.AltText,
+		SceneAltText:       req.SceneAltText,
+		ModerationOverride: override,
+		AllowDuplicate:     req.AllowDuplicate,
+	}
+
+	if err := h.socialService.VerifyAccountOwner(c.Request.Context(), userID, req.AccountID); err != nil {
+		middleware.RespondError(c, err)
+		return
 	}
 
-	resp, err := h.socialService.UploadVideo(c.Request.Context(), req.AccountID, uploadReq)
+	resp, err := h.socialService.Upload(c.Request.Context(), req.AccountID, uploadReq)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondPostError(c, err)
 		return
 	}
 
@@ -158,115 +275,374 @@ func (h *Handler) UploadVideo(c *gin.Context) {
 
 // CrossPost uploads to multiple platforms
 func (h *Handler) CrossPost(c *gin.Context) {
+	userID := c.GetString("userID")
+
 	var req struct {
-		AccountIDs  []string `json:"accountIds"`
-		VideoPath   string   `json:"videoPath"`
-		Title       string   `json:"title"`
-		Description string   `json:"description"`
-		Tags        []string `json:"tags"`
-		Privacy     string   `json:"privacy"`
+		AccountIDs   []string               `json:"accountIds"`
+		MediaType    socialdomain.MediaType `json:"mediaType"`
+		VideoPath    string                 `json:"videoPath"`
+		Title        string                 `json:"title"`
+		Description  string                 `json:"description"`
+		Tags         []string               `json:"tags"`
+		Privacy      string                 `json:"privacy"`
+		CategoryID   string                 `json:"categoryId"` // YouTube only
+		FirstComment string                 `json:"firstComment"`
+		ThumbnailURL string                 `json:"thumbnailUrl"`
+		CoverFrameMs *int64                 `json:"coverFrameMs"`
+		AltText      string                 `json:"altText"`
+		SceneAltText []string               `json:"sceneAltText"`
+		ForcePublish bool                   `json:"forcePublish"`
+		// AllowDuplicate posts the video even when a near-duplicate of it
+		// was recently posted to the account
+		AllowDuplicate bool `json:"allowDuplicate"`
+		// Platforms tailor the copy per account, or per platform when the
+		// accountId is left out
+		Platforms []PlatformScheduleReq `json:"platforms"`
+		// Select adds accounts by rule, e.g. every connected YouTube account
+		Select *socialdomain.AccountSelector `json:"select"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
+		return
+	}
+
+	override, err := moderationOverride(c, req.ForcePublish)
+	if err != nil {
+		middleware.RespondError(c, err)
 		return
 	}
 
 	uploadReq := &socialdomain.UploadRequest{
-		VideoPath:   req.VideoPath,
-		Title:       req.Title,
-		Description: req.Description,
-		Tags:        req.Tags,
-		Privacy:     req.Privacy,
+		MediaType:          req.MediaType,
+		VideoPath:          req.VideoPath,
+		Title:              req.Title,
+		Description:        req.Description,
+		Tags:               req.Tags,
+		Privacy:            req.Privacy,
+		CategoryID:         req.CategoryID,
+		FirstComment:       req.FirstComment,
+		ThumbnailURL:       req.ThumbnailURL,
+		CoverFrameMs:       req.CoverFrameMs,
+		AltText:            req.AltText,
+		SceneAltText:       req.SceneAltText,
+		ModerationOverride: override,
+		AllowDuplicate:     req.AllowDuplicate,
 	}
 
-	results, err := h.socialService.CrossPost(c.Request.Context(), req.AccountIDs, uploadReq)
+	if err := h.socialService.VerifyAccountOwner(c.Request.Context(), userID, req.AccountIDs...); err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	skipped := []socialdomain.SkippedAccount{}
+	if req.Select != nil {
+		accounts, skippedAccounts, err := h.socialService.SelectAccounts(c.Request.Context(), userID, req.Select)
+		if err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		skipped = skippedAccounts
+		for _, account := range accounts {
+			if !slices.Contains(req.AccountIDs, account.ID) {
+				req.AccountIDs = append(req.AccountIDs, account.ID)
+			}
+		}
+	}
+
+	overrides := make([]socialdomain.PostOverride, 0, len(req.Platforms))
+	for _, p := range req.Platforms {
+		overrides = append(overrides, socialdomain.PostOverride{
+			AccountID:   p.AccountID,
+			Platform:    socialdomain.SocialPlatform(p.Platform),
+			Title:       p.Title,
+			Description: p.Description,
+			Tags:        p.Tags,
+			Privacy:     p.Privacy,
+		})
+	}
+
+	results, err := h.socialService.CrossPost(c.Request.Context(), req.AccountIDs, uploadReq, overrides)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondPostError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"results": results,
+		"skipped": skipped,
 	})
 }
 
-// SchedulePost schedules a post for later
-func (h *Handler) SchedulePost(c *gin.Context) {
+// QuickPublish posts a video to accounts straight away, writing its title,
+// description and hashtags when they aren't given
+func (h *Handler) QuickPublish(c *gin.Context) {
 	userID := c.GetString("userID")
 
 	var req struct {
-		VideoID     string                   `json:"videoId"`
-		Title       string                   `json:"title"`
-		Description string                   `json:"description"`
-		Platforms   []PlatformScheduleReq    `json:"platforms"`
-		ScheduledAt string                   `json:"scheduledAt"`
-		Timezone    string                   `json:"timezone"`
-		Recurring   *socialdomain.RecurringRule `json:"recurring,omitempty"`
+		AccountIDs   []string `json:"accountIds" binding:"required,min=1"`
+		VideoURL     string   `json:"videoUrl" binding:"required"`
+		Topic        string   `json:"topic"` // seeds the written copy
+		Title        string   `json:"title"`
+		Description  string   `json:"description"`
+		Tags         []string `json:"tags"`
+		Privacy      string   `json:"privacy"`
+		CategoryID   string   `json:"categoryId"` // YouTube only
+		ForcePublish bool     `json:"forcePublish"`
+		// AllowDuplicate posts the video even when a near-duplicate of it
+		// was recently posted to an account
+		AllowDuplicate bool `json:"allowDuplicate"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
+		return
+	}
+
+	override, err := moderationOverride(c, req.ForcePublish)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	uploadReq := &socialdomain.UploadRequest{
+		MediaType:          socialdomain.MediaTypeVideo,
+		VideoPath:          req.VideoURL,
+		Title:              req.Title,
+		Description:        req.Description,
+		Tags:               req.Tags,
+		Privacy:            req.Privacy,
+		CategoryID:         req.CategoryID,
+		ModerationOverride: override,
+		AllowDuplicate:     req.AllowDuplicate,
+	}
+
+	result, err := h.socialService.QuickPublish(c.Request.Context(), userID, req.AccountIDs, uploadReq, req.Topic)
+	if err != nil {
+		respondPostError(c, err)
+		return
 	}
 
+	c.JSON(http.StatusOK, result)
+}
+
+// SchedulePost schedules a post for later, or with publishNow set publishes
+// it straight away. With dryRun set it only validates the post and returns a
+// per-platform readiness report.
+func (h *Handler) SchedulePost(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req ScheduleReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
 		return
 	}
 
-	scheduledAt, err := parseTime(req.ScheduledAt)
+	var scheduledAt time.Time
+	var optimalFrom, optimalTo time.Time
+	if req.OptimalTime != nil {
+		if req.ScheduledAt != "" || req.PublishNow {
+			middleware.RespondError(c, domain.NewValidationError("Set only one of scheduledAt, publishNow and optimalTime"))
+			return
+		}
+		var err error
+		optimalFrom, optimalTo, err = parseOptimalWindow(req.OptimalTime, req.Timezone)
+		if err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		if err := h.socialService.CheckScheduledTime(optimalTo); err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		if now := time.Now(); optimalFrom.Before(now) {
+			optimalFrom = now
+		}
+		scheduledAt = optimalFrom
+	} else if req.PublishNow {
+		if req.ScheduledAt != "" {
+			middleware.RespondError(c, domain.NewValidationError("Set either scheduledAt or publishNow, not both"))
+			return
+		}
+		scheduledAt = time.Now()
+	} else {
+		var err error
+		scheduledAt, err = parseScheduledAt(req.ScheduledAt, req.Timezone)
+		if err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		if err := h.socialService.CheckScheduledTime(scheduledAt); err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+	}
+
+	override, err := moderationOverride(c, req.ForcePublish)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled time"})
+		middleware.RespondError(c, err)
 		return
 	}
 
-	post := &socialdomain.ScheduledPost{
-		UserID:      userID,
-		VideoID:     req.VideoID,
-		Title:       req.Title,
-		Description: req.Description,
-		ScheduledAt: scheduledAt,
-		Timezone:    req.Timezone,
-		Recurring:   req.Recurring,
-		Metadata: socialdomain.JSON{
-			"videoPath": req.VideoID, // Would be resolved from video service
-		},
+	post := req.toScheduledPost(userID, scheduledAt)
+	if err := h.addSelectedAccounts(c, &req, post); err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+	if override != "" {
+		post.Metadata["moderationOverride"] = override
+	}
+	if req.AllowDuplicate {
+		post.Metadata["allowDuplicate"] = true
+	}
+	if req.OptimalTime != nil {
+		if err := socialsvc.SetOptimalWindow(post, optimalFrom, optimalTo); err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
 	}
 
-	// Convert platform requests
-	for _, p := range req.Platforms {
-		post.Platforms = append(post.Platforms, socialdomain.PlatformPost{
-			AccountID:   p.AccountID,
-			Platform:    socialdomain.SocialPlatform(p.Platform),
-			CustomTitle: p.Title,
-			CustomDesc:  p.Description,
-			Tags:        p.Tags,
-			Privacy:     p.Privacy,
-		})
+	if req.DryRun {
+		h.respondReadiness(c, post)
+		return
 	}
 
 	if err := h.socialService.SchedulePost(c.Request.Context(), post); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondPostError(c, err)
 		return
 	}
 
 	// Schedule with publisher
 	if err := h.publisher.SchedulePublish(c.Request.Context(), post); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, post)
 }
 
-// GetScheduledPosts returns scheduled posts
+// ScheduleVariations schedules generated platform variations to the matching accounts
+func (h *Handler) ScheduleVariations(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req service.ScheduleVariationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
+		return
+	}
+
+	posts, err := h.publisher.ScheduleVariations(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"posts": posts,
+	})
+}
+
+// ValidatePost dry-runs a post against every target platform without publishing
+func (h *Handler) ValidatePost(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req ScheduleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
+		return
+	}
+
+	// Scheduled time is optional here; validate as if publishing now
+	var scheduledAt time.Time
+	if req.ScheduledAt != "" {
+		t, err := parseScheduledAt(req.ScheduledAt, req.Timezone)
+		if err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		scheduledAt = t
+	}
+
+	post := req.toScheduledPost(userID, scheduledAt)
+	if err := h.addSelectedAccounts(c, &req, post); err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+	h.respondReadiness(c, post)
+}
+
+// addSelectedAccounts adds a platform post for every account the request's
+// selector matches that the post doesn't already target, tailored by the
+// platform entry without an accountId. Skipped accounts are noted in the
+// post's metadata.
+func (h *Handler) addSelectedAccounts(c *gin.Context, req *ScheduleReq, post *socialdomain.ScheduledPost) error {
+	if req.Select == nil {
+		return nil
+	}
+	accounts, skipped, err := h.socialService.SelectAccounts(c.Request.Context(), post.UserID, req.Select)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		if slices.ContainsFunc(post.Platforms, func(p socialdomain.PlatformPost) bool { return p.AccountID == account.ID }) {
+			continue
+		}
+		platformPost := socialdomain.PlatformPost{
+			AccountID: account.ID,
+			Platform:  account.Platform,
+		}
+		for _, p := range req.Platforms {
+			if p.AccountID == "" && socialdomain.SocialPlatform(p.Platform) == account.Platform {
+				platformPost.CustomTitle = p.Title
+				platformPost.CustomDesc = p.Description
+				platformPost.Tags = p.Tags
+				platformPost.Privacy = p.Privacy
+				break
+			}
+		}
+		post.Platforms = append(post.Platforms, platformPost)
+	}
+	if len(skipped) > 0 {
+		post.Metadata["skippedAccounts"] = skipped
+	}
+	return nil
+}
+
+func (h *Handler) respondReadiness(c *gin.Context, post *socialdomain.ScheduledPost) {
+	report, err := h.socialService.ValidateScheduledPost(c.Request.Context(), post)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetScheduledPosts returns a page of scheduled posts, filtered by the
+// status, platform, from and to query params
 func (h *Handler) GetScheduledPosts(c *gin.Context) {
 	userID := c.GetString("userID")
 
-	posts, err := h.socialService.GetScheduledPosts(c.Request.Context(), userID, 100, 0)
+	filter, err := parsePostFilter(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
+		return
+	}
+
+	posts, total, err := h.socialService.GetScheduledPosts(c.Request.Context(), userID, filter)
+	if err != nil {
+		middleware.RespondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"posts": posts,
+		"meta": gin.H{
+			"limit":   filter.Limit,
+			"offset":  filter.Offset,
+			"total":   total,
+			"hasMore": int64(filter.Offset+len(posts)) < total,
+		},
 	})
 }
 
@@ -276,19 +652,78 @@ func (h *Handler) CancelScheduledPost(c *gin.Context) {
 	postID := c.Param("id")
 
 	if err := h.socialService.CancelScheduledPost(c.Request.Context(), postID, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Post cancelled"})
 }
 
+// CloneScheduledPost schedules fresh copies of a post, one per time given,
+// to its own accounts or the ones given
+func (h *Handler) CloneScheduledPost(c *gin.Context) {
+	userID := c.GetString("userID")
+	postID := c.Param("id")
+
+	var req struct {
+		ScheduledAt []string `json:"scheduledAt" binding:"required,min=1,max=50"`
+		Timezone    string   `json:"timezone"`
+		AccountIDs  []string `json:"accountIds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondError(c, domain.NewValidationError("Invalid request"))
+		return
+	}
+
+	times := make([]time.Time, 0, len(req.ScheduledAt))
+	for _, value := range req.ScheduledAt {
+		scheduledAt, err := parseScheduledAt(value, req.Timezone)
+		if err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		if err := h.socialService.CheckScheduledTime(scheduledAt); err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		times = append(times, scheduledAt)
+	}
+
+	if err := h.socialService.VerifyAccountOwner(c.Request.Context(), userID, req.AccountIDs...); err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	posts := make([]*socialdomain.ScheduledPost, 0, len(times))
+	for _, scheduledAt := range times {
+		post, err := h.socialService.ClonePost(c.Request.Context(), postID, userID, scheduledAt, req.AccountIDs)
+		if err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		if err := h.publisher.SchedulePublish(c.Request.Context(), post); err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		posts = append(posts, post)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"posts": posts})
+}
+
 // PublishNow publishes a scheduled post immediately
 func (h *Handler) PublishNow(c *gin.Context) {
+	userID := c.GetString("userID")
 	postID := c.Param("id")
 
-	if err := h.publisher.PublishNow(c.Request.Context(), postID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	override, err := moderationOverride(c, c.Query("force") == "true")
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	if err := h.publisher.PublishNow(c.Request.Context(), postID, userID, override); err != nil {
+		middleware.RespondError(c, err)
 		return
 	}
 
@@ -297,10 +732,11 @@ func (h *Handler) PublishNow(c *gin.Context) {
 
 // RetryPost retries a failed post
 func (h *Handler) RetryPost(c *gin.Context) {
+	userID := c.GetString("userID")
 	postID := c.Param("id")
 
-	if err := h.publisher.RetryFailedPost(c.Request.Context(), postID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.publisher.RetryFailedPost(c.Request.Context(), postID, userID); err != nil {
+		middleware.RespondError(c, err)
 		return
 	}
 
@@ -313,7 +749,7 @@ func (h *Handler) GetPublishingQueue(c *gin.Context) {
 
 	posts, err := h.publisher.GetPublishingQueue(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
 		return
 	}
 
@@ -324,26 +760,58 @@ func (h *Handler) GetPublishingQueue(c *gin.Context) {
 
 // GetAnalytics returns analytics for an account
 func (h *Handler) GetAnalytics(c *gin.Context) {
+	userID := c.GetString("userID")
 	accountID := c.Param("accountId")
 	postID := c.Query("postId")
 
-	analytics, err := h.socialService.GetAnalytics(c.Request.Context(), accountID, postID)
+	analytics, err := h.socialService.GetAnalytics(c.Request.Context(), accountID, userID, postID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, analytics)
 }
 
+// GetCombinedAnalytics returns analytics across all of the user's connected accounts
+func (h *Handler) GetCombinedAnalytics(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	analytics, err := h.socialService.GetCombinedAnalytics(c.Request.Context(), userID)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// GetPostAnalytics returns analytics for each published instance of a scheduled post
+func (h *Handler) GetPostAnalytics(c *gin.Context) {
+	userID := c.GetString("userID")
+	postID := c.Param("id")
+
+	analytics, err := h.socialService.GetPostAnalytics(c.Request.Context(), postID, userID)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"postId":    postID,
+		"platforms": analytics,
+	})
+}
+
 // GetTrends returns trends for a platform
 func (h *Handler) GetTrends(c *gin.Context) {
+	userID := c.GetString("userID")
 	accountID := c.Param("accountId")
 	region := c.DefaultQuery("region", "US")
 
-	trends, err := h.socialService.GetTrends(c.Request.Context(), accountID, region)
+	trends, err := h.socialService.GetTrends(c.Request.Context(), accountID, userID, region)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
 		return
 	}
 
@@ -356,7 +824,7 @@ func (h *Handler) GetTrends(c *gin.Context) {
 func (h *Handler) GetQueueStats(c *gin.Context) {
 	stats, err := h.scheduler.GetQueueStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondError(c, err)
 		return
 	}
 
@@ -365,6 +833,84 @@ func (h *Handler) GetQueueStats(c *gin.Context) {
 
 // Helper types and functions
 
+type ScheduleReq struct {
+	VideoID      string                      `json:"videoId"`
+	MediaType    socialdomain.MediaType      `json:"mediaType"`
+	Title        string                      `json:"title"`
+	Description  string                      `json:"description"`
+	Platforms    []PlatformScheduleReq       `json:"platforms"`
+	ScheduledAt  string                      `json:"scheduledAt"` // RFC 3339, or a local time in Timezone without an offset
+	Timezone     string                      `json:"timezone"`
+	CategoryID   string                      `json:"categoryId"` // YouTube only
+	PublishNow   bool                        `json:"publishNow"` // publish straight away instead of at ScheduledAt
+	Recurring    *socialdomain.RecurringRule `json:"recurring,omitempty"`
+	FirstComment string                      `json:"firstComment"`
+	ThumbnailURL string                      `json:"thumbnailUrl"`
+	CoverFrameMs *int64                      `json:"coverFrameMs"`
+	ForcePublish bool                        `json:"forcePublish"`
+	DryRun       bool                        `json:"dryRun"`
+
+	// Select adds accounts by rule; platform entries without an accountId
+	// then tailor the selected accounts on that platform
+	Select *socialdomain.AccountSelector `json:"select,omitempty"`
+
+	// OptimalTime publishes at the best time within a day or range instead
+	// of at ScheduledAt, picked per account when the post comes due
+	OptimalTime *OptimalTimeReq `json:"optimalTime,omitempty"`
+
+	// AllowDuplicate schedules the video even when a near-duplicate of it
+	// was recently posted to an account
+	AllowDuplicate bool `json:"allowDuplicate"`
+}
+
+// OptimalTimeReq is the window an optimal posting time is picked from:
+// either a whole day, or from and to. Times without an offset are read in
+// the request's timezone.
+type OptimalTimeReq struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (r *ScheduleReq) toScheduledPost(userID string, scheduledAt time.Time) *socialdomain.ScheduledPost {
+	post := &socialdomain.ScheduledPost{
+		UserID:      userID,
+		VideoID:     r.VideoID,
+		Title:       r.Title,
+		Description: r.Description,
+		ScheduledAt: scheduledAt,
+		Timezone:    r.Timezone,
+		Recurring:   r.Recurring,
+		Metadata: socialdomain.JSON{
+			"videoPath":    r.VideoID, // Would be resolved from video service
+			"mediaType":    string(r.MediaType),
+			"firstComment": r.FirstComment,
+			"thumbnailUrl": r.ThumbnailURL,
+			"categoryId":   r.CategoryID,
+		},
+	}
+	if r.CoverFrameMs != nil {
+		post.Metadata["coverFrameMs"] = *r.CoverFrameMs
+	}
+
+	// Convert platform requests
+	for _, p := range r.Platforms {
+		if p.AccountID == "" && r.Select != nil {
+			continue // tailors the selected accounts instead
+		}
+		post.Platforms = append(post.Platforms, socialdomain.PlatformPost{
+			AccountID:   p.AccountID,
+			Platform:    socialdomain.SocialPlatform(p.Platform),
+			CustomTitle: p.Title,
+			CustomDesc:  p.Description,
+			Tags:        p.Tags,
+			Privacy:     p.Privacy,
+		})
+	}
+
+	return post
+}
+
 type PlatformScheduleReq struct {
 	AccountID   string   `json:"accountId"`
 	Platform    string   `json:"platform"`
@@ -374,6 +920,37 @@ type PlatformScheduleReq struct {
 	Privacy     string   `json:"privacy"`
 }
 
+// errForcePublishForbidden is returned when a non-admin asks to skip moderation
+var errForcePublishForbidden = domain.NewAppError(domain.CodeForbidden, http.StatusForbidden, "only admins can force-publish flagged content")
+
+// moderationOverride returns the admin's user ID when an admin asks to publish
+// despite moderation flags, and an empty string when force isn't set
+func moderationOverride(c *gin.Context, force bool) (string, error) {
+	if !force {
+		return "", nil
+	}
+	user := middleware.GetUser(c)
+	if user == nil || !user.IsAdmin {
+		return "", errForcePublishForbidden
+	}
+	return user.ID, nil
+}
+
+// respondPostError answers a failed publish or schedule, giving the recent
+// post a near-duplicate video was caught against
+func respondPostError(c *gin.Context, err error) {
+	var duplicate *socialsvc.DuplicateError
+	if errors.As(err, &duplicate) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     err.Error(),
+			"code":      domain.CodeDuplicateContent,
+			"duplicate": duplicate.Duplicate,
+		})
+		return
+	}
+	middleware.RespondError(c, err)
+}
+
 func generateState() string {
 	// Generate random state string
 	return "state_" + generateID()
@@ -384,7 +961,136 @@ func generateID() string {
 	return fmt.Sprintf("%d", time.Now().UnixNano())
 }
 
+// Scheduled post listing page sizes
+const (
+	defaultPostLimit = 20
+	maxPostLimit     = 100
+)
+
+// parsePostFilter reads the scheduled post listing query params. "pending"
+// is accepted as an alias for posts still waiting to go out.
+func parsePostFilter(c *gin.Context) (socialdomain.PostFilter, error) {
+	filter := socialdomain.PostFilter{
+		Platform: socialdomain.SocialPlatform(c.Query("platform")),
+		Limit:    defaultPostLimit,
+	}
+
+	switch status := socialdomain.PostStatus(c.Query("status")); status {
+	case "":
+	case "pending":
+		filter.Status = socialdomain.PostStatusScheduled
+	case socialdomain.PostStatusDraft, socialdomain.PostStatusScheduled, socialdomain.PostStatusPublishing,
+		socialdomain.PostStatusPublished, socialdomain.PostStatusFailed, socialdomain.PostStatusCancelled:
+		filter.Status = status
+	default:
+		return filter, domain.NewValidationError("Invalid status")
+	}
+
+	for param, bound := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := c.Query(param); value != "" {
+			t, err := parseTime(value)
+			if err != nil {
+				return filter, domain.NewValidationError(fmt.Sprintf("Invalid %s: expected an RFC 3339 time", param))
+			}
+			*bound = &t
+		}
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return filter, domain.NewValidationError("Invalid limit")
+		}
+		filter.Limit = min(limit, maxPostLimit)
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return filter, domain.NewValidationError("Invalid offset")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
+
 func parseTime(s string) (time.Time, error) {
 	// Parse ISO 8601 time
 	return time.Parse(time.RFC3339, s)
 }
+
+// scheduleLocalLayouts are the wall-clock layouts a scheduled time may use
+// when it comes with a timezone instead of an offset
+var scheduleLocalLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+
+// parseOptimalWindow parses the window an optimal posting time is picked
+// from. A date covers that whole day in timezone.
+func parseOptimalWindow(req *OptimalTimeReq, timezone string) (time.Time, time.Time, error) {
+	if req.Date != "" {
+		if req.From != "" || req.To != "" {
+			return time.Time{}, time.Time{}, domain.NewValidationError("Set either optimalTime.date or optimalTime.from and to, not both")
+		}
+		loc := time.UTC
+		if timezone != "" {
+			l, err := time.LoadLocation(timezone)
+			if err != nil {
+				return time.Time{}, time.Time{}, domain.NewValidationError(fmt.Sprintf("unknown timezone %q", timezone))
+			}
+			loc = l
+		}
+		day, err := time.ParseInLocation("2006-01-02", req.Date, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, domain.NewValidationError("optimalTime.date must be YYYY-MM-DD")
+		}
+		return day, day.AddDate(0, 0, 1), nil
+	}
+
+	if req.From == "" || req.To == "" {
+		return time.Time{}, time.Time{}, domain.NewValidationError("optimalTime needs a date, or from and to")
+	}
+	from, err := parseScheduledAt(req.From, timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := parseScheduledAt(req.To, timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+// parseScheduledAt parses a post's scheduled time. A time with an offset is
+// taken as is; one without is read as wall-clock time in timezone. Wall
+// times skipped by a daylight saving change are moved forward by the gap,
+// and repeated ones are taken at their first occurrence.
+func parseScheduledAt(value, timezone string) (time.Time, error) {
+	loc := time.UTC
+	if timezone != "" {
+		l, err := time.LoadLocation(timezone)
+		if err != nil {
+			return time.Time{}, domain.NewValidationError(fmt.Sprintf("unknown timezone %q", timezone))
+		}
+		loc = l
+	}
+
+	if t, err := parseTime(value); err == nil {
+		return t, nil
+	}
+	if timezone != "" {
+		for _, layout := range scheduleLocalLayouts {
+			t, err := time.ParseInLocation(layout, value, loc)
+			if err != nil {
+				continue
+			}
+			if t.Format(layout) != value {
+				// The wall time falls in a gap, which Go resolves with the
+				// offset from after it, landing before the gap
+				_, before := t.Zone()
+				_, after := t.Add(12 * time.Hour).Zone()
+				t = t.Add(time.Duration(after-before) * time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, domain.NewValidationError("Invalid scheduled time")
+}
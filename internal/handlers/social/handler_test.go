@@ -0,0 +1,147 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	socialdomain "renderowl-api/internal/domain/social"
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+	socialsvc "renderowl-api/internal/service/social"
+)
+
+// fakeAccounts holds social accounts by ID. Methods that change accounts
+// aren't implemented, so reaching one fails the test.
+type fakeAccounts struct {
+	socialsvc.AccountRepository
+	accounts map[string]*socialdomain.SocialAccount
+}
+
+func (f *fakeAccounts) GetByID(ctx context.Context, id string) (*socialdomain.SocialAccount, error) {
+	if account, ok := f.accounts[id]; ok {
+		return account, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+// fakePosts holds scheduled posts by ID, for the social service
+type fakePosts struct {
+	socialsvc.PostRepository
+	posts map[string]*socialdomain.ScheduledPost
+}
+
+func (f *fakePosts) GetByID(ctx context.Context, id string) (*socialdomain.ScheduledPost, error) {
+	return findPost(f.posts, id)
+}
+
+// fakePublisherPosts holds scheduled posts by ID, for the publisher
+type fakePublisherPosts struct {
+	service.PostRepository
+	posts map[string]*socialdomain.ScheduledPost
+}
+
+func (f *fakePublisherPosts) GetByID(ctx context.Context, id string) (*socialdomain.ScheduledPost, error) {
+	return findPost(f.posts, id)
+}
+
+func findPost(posts map[string]*socialdomain.ScheduledPost, id string) (*socialdomain.ScheduledPost, error) {
+	if post, ok := posts[id]; ok {
+		return post, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+// newOwnershipRouter serves the social routes for the user named in the
+// X-User-ID header, over user-a's account and failed post
+func newOwnershipRouter() *gin.Engine {
+	accounts := &fakeAccounts{accounts: map[string]*socialdomain.SocialAccount{
+		"account-a": {ID: "account-a", UserID: "user-a", Platform: socialdomain.PlatformYouTube},
+	}}
+	posts := map[string]*socialdomain.ScheduledPost{
+		"post-a": {ID: "post-a", UserID: "user-a", Status: socialdomain.PostStatusFailed},
+	}
+	socialService := socialsvc.NewService(socialsvc.NewPlatformRegistry(), accounts, &fakePosts{posts: posts}, nil)
+	publisher := service.NewPublisher(socialService, nil, &fakePublisherPosts{posts: posts}, nil)
+	h := NewSocialHandler(socialService, publisher, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, c.GetHeader("X-User-ID"))
+	})
+	r.GET("/social/accounts/:id", h.GetAccount)
+	r.DELETE("/social/accounts/:id", h.DisconnectAccount)
+	r.GET("/social/accounts/:id/metrics", h.GetAccountMetrics)
+	r.DELETE("/social/schedule/:id", h.CancelScheduledPost)
+	r.POST("/social/schedule/:id/clone", h.CloneScheduledPost)
+	r.GET("/social/schedule/:id/analytics", h.GetPostAnalytics)
+	r.POST("/social/publish/:id", h.PublishNow)
+	r.POST("/social/retry/:id", h.RetryPost)
+	return r
+}
+
+func TestOtherUsersAccountsAndPostsAreNotFound(t *testing.T) {
+	r := newOwnershipRouter()
+
+	requests := []struct {
+		method, path, body string
+	}{
+		{"GET", "/social/accounts/account-a", ""},
+		{"DELETE", "/social/accounts/account-a", ""},
+		{"GET", "/social/accounts/account-a/metrics", ""},
+		{"DELETE", "/social/schedule/post-a", ""},
+		{"POST", "/social/schedule/post-a/clone", `{"scheduledAt":["2099-01-01T10:00:00Z"]}`},
+		{"GET", "/social/schedule/post-a/analytics", ""},
+		{"POST", "/social/publish/post-a", ""},
+		{"POST", "/social/retry/post-a", ""},
+	}
+	for _, req := range requests {
+		httpReq := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("X-User-ID", "user-b")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httpReq)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s %s as user-b: status %d, want 404: %s", req.method, req.path, w.Code, w.Body)
+		}
+	}
+
+	httpReq := httptest.NewRequest("GET", "/social/accounts/account-a", nil)
+	httpReq.Header.Set("X-User-ID", "user-a")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httpReq)
+	if w.Code != http.StatusOK {
+		t.Errorf("GET own account: status %d, want 200", w.Code)
+	}
+}
+
+func TestValidatePostReadsLocalTimesInTheTimezone(t *testing.T) {
+	socialService := socialsvc.NewService(socialsvc.NewPlatformRegistry(), &fakeAccounts{}, nil, nil)
+	h := NewSocialHandler(socialService, nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/social/validate", h.ValidatePost)
+
+	bodies := map[string]int{
+		`{"scheduledAt":"2099-01-01T10:00","timezone":"Europe/Amsterdam"}`: http.StatusOK,
+		`{"scheduledAt":"2099-01-01T10:00:00Z"}`:                           http.StatusOK,
+		`{"scheduledAt":"2099-01-01T10:00","timezone":"Mars/Olympus"}`:     http.StatusBadRequest,
+		`{"scheduledAt":"2099-01-01T10:00"}`:                               http.StatusBadRequest,
+	}
+	for body, want := range bodies {
+		httpReq := httptest.NewRequest("POST", "/social/validate", strings.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httpReq)
+		if w.Code != want {
+			t.Errorf("validate %s: status %d, want %d: %s", body, w.Code, want, w.Body)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package social
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"renderowl-api/internal/domain"
+	socialdomain "renderowl-api/internal/domain/social"
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// uploadFormOverhead is room for the multipart form's other fields on top of
+// the largest accepted file
+const uploadFormOverhead = 1 << 20
+
+// SetMediaUploads enables publishing files uploaded with the request
+func (h *Handler) SetMediaUploads(uploads *service.MediaUploadService) {
+	h.uploads = uploads
+}
+
+// UploadFile publishes a file sent as multipart form data. The file is kept
+// in storage so platforms that pull media by URL can fetch it, and deleted
+// once the upload retention is over. Set accountId to publish to one account
+// or accountIds to cross-post.
+func (h *Handler) UploadFile(c *gin.Context) {
+	if h.uploads == nil {
+		middleware.RespondError(c, domain.NewAppError(domain.CodeInternal, http.StatusServiceUnavailable, "file uploads are not enabled"))
+		return
+	}
+	userID := c.GetString("userID")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.uploads.MaxBytes()+uploadFormOverhead)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			middleware.RespondError(c, service.ErrUploadTooLarge)
+			return
+		}
+		middleware.RespondError(c, domain.NewValidationError("file is required"))
+		return
+	}
+
+	accountID := c.PostForm("accountId")
+	accountIDs := formList(c, "accountIds")
+	if (accountID == "") == (len(accountIDs) == 0) {
+		middleware.RespondError(c, domain.NewValidationError("set either accountId or accountIds"))
+		return
+	}
+	if accountID != "" {
+		accountIDs = []string{accountID}
+	}
+
+	override, err := moderationOverride(c, c.PostForm("forcePublish") == "true")
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	if err := h.socialService.VerifyAccountOwner(c.Request.Context(), userID, accountIDs...); err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		middleware.RespondError(c, domain.NewValidationError("failed to read file"))
+		return
+	}
+	defer file.Close()
+
+	stored, err := h.uploads.Store(c.Request.Context(), userID, fileHeader.Filename, fileHeader.Header.Get("Content-Type"), file)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+	defer h.uploads.Release(c.Request.Context(), stored.Key)
+
+	uploadReq := &socialdomain.UploadRequest{
+		MediaType:          stored.MediaType,
+		VideoPath:          stored.URL,
+		Title:              c.PostForm("title"),
+		Description:        c.PostForm("description"),
+		Tags:               formList(c, "tags"),
+		Privacy:            c.PostForm("privacy"),
+		CategoryID:         c.PostForm("categoryId"),
+		FirstComment:       c.PostForm("firstComment"),
+		ThumbnailURL:       c.PostForm("thumbnailUrl"),
+		AltText:            c.PostForm("altText"),
+		ModerationOverride: override,
+	}
+
+	if accountID != "" {
+		resp, err := h.socialService.Upload(c.Request.Context(), accountID, uploadReq)
+		if err != nil {
+			middleware.RespondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	results, err := h.socialService.CrossPost(c.Request.Context(), accountIDs, uploadReq, nil)
+	if err != nil {
+		middleware.RespondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+	})
+}
+
+// formList reads a form field sent either repeated or comma separated
+func formList(c *gin.Context, key string) []string {
+	var values []string
+	for _, field := range c.PostFormArray(key) {
+		for _, value := range strings.Split(field, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
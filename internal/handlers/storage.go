@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/service"
+)
+
+// StorageHandler serves files from local storage
+type StorageHandler struct {
+	storage *service.LocalStorage
+}
+
+// NewStorageHandler creates a new storage handler
+func NewStorageHandler(storage *service.LocalStorage) *StorageHandler {
+	return &StorageHandler{storage: storage}
+}
+
+// Serve serves a stored file. Range requests get 206 Partial Content so
+// players can seek without downloading the whole video.
+// GET /files/*key
+func (h *StorageHandler) Serve(c *gin.Context) {
+	key := c.Param("key")
+	file, err := h.storage.Open(key)
+	if err != nil {
+		if errors.Is(err, service.ErrFileNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+				"code":  "NOT_FOUND",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "STORAGE_ERROR",
+		})
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": service.ErrFileNotFound.Error(),
+			"code":  "NOT_FOUND",
+		})
+		return
+	}
+
+	// ServeContent handles Range and If-Range, answering 206 or 416, and sets
+	// Accept-Ranges, Content-Type and Last-Modified
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime(), file)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -26,16 +27,23 @@ func (h *TemplateHandler) List(c *gin.Context) {
 	filter := domain.TemplateFilter{
 		Category: c.Query("category"),
 		Search:   c.Query("search"),
+		Sort:     c.Query("sort"),
 	}
-
-	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
-		filter.Limit = limit
+	if filter.Sort != "" && filter.Sort != domain.TemplateSortPopular {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "sort must be popular",
+			"code":  "VALIDATION_ERROR",
+		})
+		return
 	}
-	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
-		filter.Offset = offset
+	if user := middleware.GetUser(c); user != nil {
+		filter.UserID = user.ID
+		filter.FavoritesOnly, _ = strconv.ParseBool(c.Query("favorites"))
 	}
 
-	templates, err := h.service.ListTemplates(filter)
+	filter.Limit, filter.Offset = parsePagination(c, 50)
+
+	templates, total, err := h.service.ListTemplates(filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -46,11 +54,7 @@ func (h *TemplateHandler) List(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": templates,
-		"meta": gin.H{
-			"limit":  filter.Limit,
-			"offset": filter.Offset,
-			"total":  len(templates),
-		},
+		"meta": pageMeta(filter.Limit, filter.Offset, len(templates), total),
 	})
 }
 
@@ -70,6 +74,30 @@ func (h *TemplateHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, template)
 }
 
+// Preview returns a PNG montage of a template's scenes
+func (h *TemplateHandler) Preview(c *gin.Context) {
+	id := c.Param("id")
+
+	preview, err := h.service.GetTemplatePreview(id)
+	if errors.Is(err, service.ErrTemplateNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "image/png", preview)
+}
+
 // GetCategories retrieves all template categories
 func (h *TemplateHandler) GetCategories(c *gin.Context) {
 	categories, err := h.service.GetCategories()
@@ -117,6 +145,54 @@ func (h *TemplateHandler) Use(c *gin.Context) {
 	c.JSON(http.StatusCreated, response)
 }
 
+// AddFavorite pins a template for the user
+// PUT /api/v1/templates/:id/favorite
+func (h *TemplateHandler) AddFavorite(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	err := h.service.AddFavorite(user.ID, c.Param("id"))
+	if errors.Is(err, service.ErrTemplateNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveFavorite unpins a template for the user
+// DELETE /api/v1/templates/:id/favorite
+func (h *TemplateHandler) RemoveFavorite(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.service.RemoveFavorite(user.ID, c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // GetStats retrieves template statistics
 func (h *TemplateHandler) GetStats(c *gin.Context) {
 	stats, err := h.service.GetTemplateStats()
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -69,6 +70,77 @@ func (h *TimelineHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, timeline)
 }
 
+// AudioMix returns the ffmpeg audio mix for a timeline's audio tracks
+func (h *TimelineHandler) AudioMix(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id := c.Param("id")
+	mix, err := h.service.GetAudioMix(id, user.ID)
+	if errors.Is(err, service.ErrNoAudio) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+			"code":  "NO_AUDIO",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, mix)
+}
+
+// Merge concatenates several of the user's timelines into a new one
+// POST /api/v1/timelines/merge
+func (h *TimelineHandler) Merge(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.MergeTimelinesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	result, err := h.service.MergeTimelines(c.Request.Context(), user.ID, &req)
+	switch {
+	case errors.Is(err, service.ErrTimelineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+		return
+	case errors.Is(err, service.ErrTooFewTimelines):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
 // List lists all timelines for the authenticated user
 func (h *TimelineHandler) List(c *gin.Context) {
 	user := middleware.GetUser(c)
@@ -77,10 +149,9 @@ func (h *TimelineHandler) List(c *gin.Context) {
 		return
 	}
 
-	limit := 20
-	offset := 0
+	limit, offset := parsePagination(c, 20)
 
-	timelines, err := h.service.List(user.ID, limit, offset)
+	timelines, total, err := h.service.List(user.ID, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -91,11 +162,7 @@ func (h *TimelineHandler) List(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": timelines,
-		"meta": gin.H{
-			"limit":  limit,
-			"offset": offset,
-			"total":  len(timelines),
-		},
+		"meta": pageMeta(limit, offset, len(timelines), total),
 	})
 }
 
@@ -150,3 +217,73 @@ func (h *TimelineHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// AddNote adds a note to a timeline
+// POST /api/v1/timelines/:id/notes
+func (h *TimelineHandler) AddNote(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req service.AddNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
+
+	note, err := h.service.AddNote(c.Request.Context(), c.Param("id"), user.ID, &req)
+	if err != nil {
+		h.respondNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// ListNotes lists a timeline's notes, newest first
+// GET /api/v1/timelines/:id/notes
+func (h *TimelineHandler) ListNotes(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	limit, offset := parsePagination(c, 50)
+
+	notes, total, err := h.service.ListNotes(c.Request.Context(), c.Param("id"), user.ID, limit, offset)
+	if err != nil {
+		h.respondNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": notes,
+		"meta": pageMeta(limit, offset, len(notes), total),
+	})
+}
+
+func (h *TimelineHandler) respondNoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTimelineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+			"code":  "NOT_FOUND",
+		})
+	case errors.Is(err, service.ErrNoteClipNotFound), errors.Is(err, service.ErrEmptyNote):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -120,6 +121,13 @@ func (h *TrackHandler) Update(c *gin.Context) {
 	}
 
 	track, err := h.service.Update(user.ID, trackID, &req)
+	if errors.Is(err, service.ErrInvalidTrackSettings) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  "VALIDATION_ERROR",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// UsageHandler handles provider usage HTTP requests
+type UsageHandler struct {
+	service *service.UsageService
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(service *service.UsageService) *UsageHandler {
+	return &UsageHandler{service: service}
+}
+
+// GetUsage totals the user's AI provider usage and its estimated cost per
+// provider and per period. start_date and end_date are inclusive dates and
+// default to the last 30 days; period is day, week or month.
+// GET /api/v1/usage?period=day&start_date=&end_date=
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	startDate := today.AddDate(0, 0, -29)
+	endDate := today
+	for param, date := range map[string]*time.Time{"start_date": &startDate, "end_date": &endDate} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": param + " must be a date like 2006-01-02",
+				"code":  "VALIDATION_ERROR",
+			})
+			return
+		}
+		*date = t
+	}
+
+	summary, err := h.service.Summarize(c.Request.Context(), user.ID, c.DefaultQuery("period", "day"), startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUsagePeriod) || errors.Is(err, service.ErrInvalidUsageRange) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"code":  "VALIDATION_ERROR",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/middleware"
+	"renderowl-api/internal/service"
+)
+
+// UserDataHandler handles data export and deletion requests for the current user
+type UserDataHandler struct {
+	service *service.UserDataService
+}
+
+// NewUserDataHandler creates a new user data handler
+func NewUserDataHandler(service *service.UserDataService) *UserDataHandler {
+	return &UserDataHandler{service: service}
+}
+
+// Export returns all of the current user's data
+// GET /api/v1/me/export?format=json|zip
+func (h *UserDataHandler) Export(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if c.DefaultQuery("format", "json") == "zip" {
+		data, err := h.service.ExportUserDataArchive(c.Request.Context(), user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+				"code":  "EXPORT_ERROR",
+			})
+			return
+		}
+
+		c.Header("Content-Disposition", "attachment; filename=renderowl-export.zip")
+		c.Data(http.StatusOK, "application/zip", data)
+		return
+	}
+
+	export, err := h.service.ExportUserData(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "EXPORT_ERROR",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=renderowl-export.json")
+	c.JSON(http.StatusOK, export)
+}
+
+// Delete erases the current user's data and revokes connected platform tokens
+// DELETE /api/v1/me
+func (h *UserDataHandler) Delete(c *gin.Context) {
+	user := middleware.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.service.DeleteUserData(c.Request.Context(), user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"code":  "DELETE_ERROR",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
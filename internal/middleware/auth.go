@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -13,6 +14,7 @@ import (
 
 const (
 	UserContextKey = "user"
+	UserIDKey      = "userID" // Plain user ID for handlers that read c.GetString
 )
 
 // Auth middleware validates Clerk JWT tokens
@@ -84,12 +86,19 @@ func Auth(cfg *config.Config) gin.HandlerFunc {
 			email = emailClaim
 		}
 
+		// Profile locale, when the session token carries one
+		locale, _ := claims["locale"].(string)
+
 		// Set user context
 		user := &domain.UserContext{
-			ID:    userID,
-			Email: email,
+			ID:      userID,
+			Email:   email,
+			IsAdmin: slices.Contains(cfg.AdminUserIDs, userID),
+			Locale:  locale,
 		}
 		c.Set(UserContextKey, user)
+		c.Set(UserIDKey, userID)
+		c.Request = c.Request.WithContext(domain.WithUserID(c.Request.Context(), userID))
 
 		c.Next()
 	}
@@ -103,3 +112,18 @@ func GetUser(c *gin.Context) *domain.UserContext {
 	}
 	return user.(*domain.UserContext)
 }
+
+// RequireAdmin rejects requests from users who aren't admins. It must run after Auth.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := GetUser(c)
+		if user == nil || !user.IsAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Admin access required",
+				"code":  "FORBIDDEN",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+
+	"renderowl-api/internal/domain"
 )
 
 // ErrorResponse represents a structured error response
@@ -25,6 +27,9 @@ func ErrorHandler() gin.HandlerFunc {
 
 			var response ErrorResponse
 			switch {
+			case isAppError(err.Err):
+				RespondError(c, err.Err)
+
 			case errors.Is(err.Err, validator.ValidationErrors{}):
 				response = ErrorResponse{
 					Error: "Validation failed",
@@ -65,6 +70,28 @@ func ErrorHandler() gin.HandlerFunc {
 	}
 }
 
+// RespondError renders err in the structured {error, code} shape. AppErrors
+// keep their code and status; anything else is reported as INTERNAL_ERROR.
+func RespondError(c *gin.Context, err error) {
+	if appErr, ok := domain.AsAppError(err); ok {
+		c.JSON(appErr.HTTPStatus, ErrorResponse{
+			Error: err.Error(),
+			Code:  string(appErr.Code),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error: err.Error(),
+		Code:  string(domain.CodeInternal),
+	})
+}
+
+func isAppError(err error) bool {
+	_, ok := domain.AsAppError(err)
+	return ok
+}
+
 // Custom error types
 var (
 	ErrNotFound  = errors.New("resource not found")
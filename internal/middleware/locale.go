@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"renderowl-api/internal/config"
+	"renderowl-api/internal/domain"
+)
+
+// Locale resolves the request locale and stores it on the request context,
+// where services read their default language and region from. The user's
+// profile locale wins over the Accept-Language header; whatever neither sets
+// comes from the configured defaults. It must run after Auth.
+func Locale(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var locale domain.Locale
+		if user := GetUser(c); user != nil && user.Locale != "" {
+			locale = domain.ParseLocale(user.Locale)
+		}
+		if locale.Language == "" {
+			locale = domain.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+		}
+		if locale.Language == "" {
+			locale.Language = cfg.DefaultLanguage
+		}
+		if locale.Region == "" {
+			locale.Region = cfg.DefaultRegion
+		}
+
+		c.Request = c.Request.WithContext(domain.WithLocale(c.Request.Context(), locale))
+		c.Next()
+	}
+}
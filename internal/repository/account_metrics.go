@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain/social"
+)
+
+// AccountMetricsRepository stores snapshots of social accounts' follower,
+// view and video counts
+type AccountMetricsRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountMetricsRepository creates a new account metrics repository
+func NewAccountMetricsRepository(db *gorm.DB) *AccountMetricsRepository {
+	return &AccountMetricsRepository{db: db}
+}
+
+// Create records a snapshot of an account's metrics
+func (r *AccountMetricsRepository) Create(ctx context.Context, snapshot *social.AccountMetricsSnapshot) error {
+	if snapshot.ID == "" {
+		snapshot.ID = uuid.New().String()
+	}
+	return r.db.WithContext(ctx).Create(snapshot).Error
+}
+
+// GetLatest gets an account's most recent metrics snapshot
+func (r *AccountMetricsRepository) GetLatest(ctx context.Context, accountID string) (*social.AccountMetricsSnapshot, error) {
+	var snapshot social.AccountMetricsSnapshot
+	err := r.db.WithContext(ctx).Where("account_id = ?", accountID).Order("recorded_at DESC").First(&snapshot).Error
+	return &snapshot, err
+}
+
+// ListSince gets an account's metrics snapshots since a time, oldest first
+func (r *AccountMetricsRepository) ListSince(ctx context.Context, accountID string, since time.Time) ([]*social.AccountMetricsSnapshot, error) {
+	var snapshots []*social.AccountMetricsSnapshot
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND recorded_at >= ?", accountID, since).
+		Order("recorded_at").
+		Find(&snapshots).Error
+	return snapshots, err
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"renderowl-api/internal/domain"
 )
@@ -21,20 +23,102 @@ func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
 
 // TrackView records a video view
 func (r *AnalyticsRepository) TrackView(ctx context.Context, videoID, userID, platform string) error {
+	now := time.Now().UTC()
 	view := domain.AnalyticsView{
 		VideoID:  videoID,
 		UserID:   userID,
 		Platform: platform,
-		Date:     time.Now().UTC().Truncate(24 * time.Hour),
+		Date:     now.Truncate(24 * time.Hour),
+	}
+	hourly := domain.AnalyticsViewHourly{
+		VideoID:  videoID,
+		UserID:   userID,
+		Platform: platform,
+		Hour:     now.Truncate(time.Hour),
+		Views:    1,
 	}
-	return r.db.WithContext(ctx).Create(&view).Error
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&view).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "video_id"}, {Name: "platform"}, {Name: "hour"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"views":      gorm.Expr("analytics_views_hourly.views + 1"),
+				"updated_at": now,
+			}),
+		}).Create(&hourly).Error
+	})
 }
 
-// GetViewsByDateRange gets views for a date range
+// RecordViews sets the view count reported by a platform for a video today.
+// The hour's row gets the views gained since the last report of an earlier
+// hour; the first report only sets the baseline to compare against.
+func (r *AnalyticsRepository) RecordViews(ctx context.Context, videoID, platform string, count int64) error {
+	now := time.Now().UTC()
+	view := domain.AnalyticsView{
+		VideoID:  videoID,
+		Platform: platform,
+		Count:    count,
+		Date:     now.Truncate(24 * time.Hour),
+	}
+	hourly := domain.AnalyticsViewHourly{
+		VideoID:  videoID,
+		Platform: platform,
+		Hour:     now.Truncate(time.Hour),
+		Total:    count,
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Upsert: platforms report running totals, so keep one row per video/platform/date
+		err := tx.Where(
+			"video_id = ? AND platform = ? AND date = ?",
+			videoID, platform, view.Date,
+		).Assign(domain.AnalyticsView{
+			Count: count,
+		}).FirstOrCreate(&view).Error
+		if err != nil {
+			return err
+		}
+
+		var previous domain.AnalyticsViewHourly
+		err = tx.Where("video_id = ? AND platform = ? AND hour < ? AND total > 0", videoID, platform, hourly.Hour).
+			Order("hour DESC").
+			First(&previous).Error
+		switch {
+		case err == nil:
+			hourly.Views = max(count-previous.Total, 0)
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "video_id"}, {Name: "platform"}, {Name: "hour"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"views":      hourly.Views,
+				"total":      hourly.Total,
+				"updated_at": now,
+			}),
+		}).Create(&hourly).Error
+	})
+}
+
+// ownedViews limits view rows to the user's: the ones tracked for them and
+// the ones platforms reported for their videos
+func (r *AnalyticsRepository) ownedViews(userID string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		videos := r.db.Model(&domain.VideoPerformance{}).Select("video_id").Where("user_id = ?", userID)
+		return db.Where("user_id = ? OR video_id IN (?)", userID, videos)
+	}
+}
+
+// GetViewsByDateRange gets a user's views for a date range
 func (r *AnalyticsRepository) GetViewsByDateRange(ctx context.Context, userID string, startDate, endDate time.Time) ([]ViewAggregate, error) {
 	var results []ViewAggregate
 	
 	err := r.db.WithContext(ctx).Model(&domain.AnalyticsView{}).
+		Scopes(r.ownedViews(userID)).
 		Select("date, platform, SUM(count) as total_views").
 		Where("date >= ? AND date <= ?", startDate, endDate).
 		Group("date, platform").
@@ -44,6 +128,21 @@ func (r *AnalyticsRepository) GetViewsByDateRange(ctx context.Context, userID st
 	return results, err
 }
 
+// GetViewsByHourRange gets a user's views for a time range bucketed by hour
+func (r *AnalyticsRepository) GetViewsByHourRange(ctx context.Context, userID string, start, end time.Time) ([]ViewAggregate, error) {
+	var results []ViewAggregate
+	
+	err := r.db.WithContext(ctx).Model(&domain.AnalyticsViewHourly{}).
+		Scopes(r.ownedViews(userID)).
+		Select("hour as date, platform, SUM(views) as total_views").
+		Where("hour >= ? AND hour <= ?", start, end).
+		Group("hour, platform").
+		Order("date DESC").
+		Find(&results).Error
+	
+	return results, err
+}
+
 // ViewAggregate represents aggregated view data
 type ViewAggregate struct {
 	Date        time.Time `json:"date"`
@@ -92,17 +191,104 @@ func (r *AnalyticsRepository) TrackEngagement(ctx context.Context, videoID, plat
 		Date:     time.Now().UTC().Truncate(24 * time.Hour),
 	}
 	
-	// Upsert: update if exists for this video/platform/date
+	// Upsert: update if exists for this video/platform/date. A map, unlike a
+	// struct, also assigns counts that dropped to zero.
 	return r.db.WithContext(ctx).Where(
 		"video_id = ? AND platform = ? AND date = ?",
 		videoID, platform, engagement.Date,
-	).Assign(domain.AnalyticsEngagement{
-		Likes:    likes,
-		Comments: comments,
-		Shares:   shares,
+	).Assign(map[string]interface{}{
+		"likes":    likes,
+		"comments": comments,
+		"shares":   shares,
 	}).FirstOrCreate(&engagement).Error
 }
 
+// engagementUpsertBatchSize is how many rows go in one INSERT statement,
+// well under Postgres' limit on bind parameters
+const engagementUpsertBatchSize = 1000
+
+// BatchTrackEngagement records the engagement of many videos in multi-row
+// upserts. Only each record's VideoID, Platform, Likes,
+// Comments and Shares are used. It leaves the same rows as calling
+// TrackEngagement for each record in turn: a video and platform listed twice
+// keeps its last counts.
+func (r *AnalyticsRepository) BatchTrackEngagement(ctx context.Context, records []domain.AnalyticsEngagement) error {
+	if len(records) == 0 {
+		return nil
+	}
+	rows := engagementRows(records, time.Now().UTC().Truncate(24*time.Hour))
+
+	// CreateInBatches runs all the batches in one transaction
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "video_id"}, {Name: "platform"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"likes", "comments", "shares", "updated_at"}),
+	}).CreateInBatches(&rows, engagementUpsertBatchSize).Error
+}
+
+// engagementRows turns records into the rows to upsert for date, one per
+// video and platform with the last counts listed for it: Postgres refuses
+// to upsert the same row twice in one statement
+func engagementRows(records []domain.AnalyticsEngagement, date time.Time) []domain.AnalyticsEngagement {
+	rows := make([]domain.AnalyticsEngagement, 0, len(records))
+	index := make(map[[2]string]int, len(records))
+	for _, record := range records {
+		row := domain.AnalyticsEngagement{
+			VideoID:  record.VideoID,
+			Platform: record.Platform,
+			Likes:    record.Likes,
+			Comments: record.Comments,
+			Shares:   record.Shares,
+			Date:     date,
+		}
+		key := [2]string{record.VideoID, record.Platform}
+		if i, ok := index[key]; ok {
+			rows[i] = row
+			continue
+		}
+		index[key] = len(rows)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// DedupeEngagement removes all but the latest row of each video, platform
+// and date from a table created before they had a unique index, so the
+// migration can create it
+func DedupeEngagement(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&domain.AnalyticsEngagement{}) ||
+		migrator.HasIndex(&domain.AnalyticsEngagement{}, "idx_engagement_video_platform_date") {
+		return nil
+	}
+	return db.Exec(`DELETE FROM analytics_engagement a
+		USING analytics_engagement b
+		WHERE a.video_id = b.video_id AND a.platform = b.platform AND a.date = b.date
+		AND (a.updated_at, a.id) < (b.updated_at, b.id)`).Error
+}
+
+// DedupeOpenSuggestions removes all but the latest open record of each
+// suggestion key from a table created before open suggestions had a unique
+// index, and drops the plain index it replaces, so the migration can create it
+func DedupeOpenSuggestions(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&domain.OptimizationSuggestionRecord{}) ||
+		migrator.HasIndex(&domain.OptimizationSuggestionRecord{}, "idx_open_suggestion_key") {
+		return nil
+	}
+	err := db.Exec(`DELETE FROM analytics_optimization_suggestions a
+		USING analytics_optimization_suggestions b
+		WHERE a.user_id = b.user_id AND a.video_id = b.video_id AND a.key = b.key
+		AND NOT a.applied AND NOT b.applied
+		AND (a.updated_at, a.id) < (b.updated_at, b.id)`).Error
+	if err != nil {
+		return err
+	}
+	if migrator.HasIndex(&domain.OptimizationSuggestionRecord{}, "idx_suggestion_key") {
+		return migrator.DropIndex(&domain.OptimizationSuggestionRecord{}, "idx_suggestion_key")
+	}
+	return nil
+}
+
 // GetEngagementByVideo gets engagement metrics for a video
 func (r *AnalyticsRepository) GetEngagementByVideo(ctx context.Context, videoID string) (*EngagementSummary, error) {
 	var result EngagementSummary
@@ -234,6 +420,52 @@ func (r *AnalyticsRepository) GetVideoPerformance(ctx context.Context, userID st
 	return results, err
 }
 
+// CountVideoPerformance counts a user's videos with performance data
+func (r *AnalyticsRepository) CountVideoPerformance(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.VideoPerformance{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// GetVideoPerformanceByVideo gets the performance data of one of a user's videos
+func (r *AnalyticsRepository) GetVideoPerformanceByVideo(ctx context.Context, userID, videoID string) (*VideoPerformanceData, error) {
+	var result VideoPerformanceData
+	err := r.db.WithContext(ctx).Model(&domain.VideoPerformance{}).
+		Where("user_id = ? AND video_id = ?", userID, videoID).
+		Take(&result).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrVideoPerformanceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetLowestPerformingVideos gets a user's videos with the fewest views
+func (r *AnalyticsRepository) GetLowestPerformingVideos(ctx context.Context, userID string, limit int) ([]VideoPerformanceData, error) {
+	var results []VideoPerformanceData
+	err := r.db.WithContext(ctx).Model(&domain.VideoPerformance{}).
+		Where("user_id = ?", userID).
+		Order("total_views ASC").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
+
+// GetDailyViewsByVideo gets the views a video gained per day since a time,
+// oldest first
+func (r *AnalyticsRepository) GetDailyViewsByVideo(ctx context.Context, videoID string, since time.Time) ([]ViewAggregate, error) {
+	var results []ViewAggregate
+	err := r.db.WithContext(ctx).Model(&domain.AnalyticsViewHourly{}).
+		Select("date_trunc('day', hour) as date, SUM(views) as total_views").
+		Where("video_id = ? AND hour >= ?", videoID, since).
+		Group("date_trunc('day', hour)").
+		Order("date").
+		Find(&results).Error
+	return results, err
+}
+
 // VideoPerformanceData represents video performance metrics
 type VideoPerformanceData struct {
 	VideoID        string    `json:"video_id"`
@@ -263,6 +495,71 @@ func (r *AnalyticsRepository) UpdateVideoPerformance(ctx context.Context, perfor
 	}).FirstOrCreate(performance).Error
 }
 
+// GetWinningContentCache gets the cached winning content analysis for a user
+func (r *AnalyticsRepository) GetWinningContentCache(ctx context.Context, userID string) (*domain.WinningContentCache, error) {
+	var cache domain.WinningContentCache
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&cache).Error
+	return &cache, err
+}
+
+// SaveWinningContentCache updates or creates a user's cached winning content analysis
+func (r *AnalyticsRepository) SaveWinningContentCache(ctx context.Context, cache *domain.WinningContentCache) error {
+	return r.db.WithContext(ctx).Where(
+		"user_id = ?", cache.UserID,
+	).Assign(domain.WinningContentCache{
+		Analysis:   cache.Analysis,
+		ComputedAt: cache.ComputedAt,
+	}).FirstOrCreate(cache).Error
+}
+
+// ListSuggestions gets the stored optimizer suggestions for one of a user's videos
+func (r *AnalyticsRepository) ListSuggestions(ctx context.Context, userID, videoID string) ([]*domain.OptimizationSuggestionRecord, error) {
+	var records []*domain.OptimizationSuggestionRecord
+	err := r.db.WithContext(ctx).Where("user_id = ? AND video_id = ?", userID, videoID).
+		Order("created_at DESC").
+		Find(&records).Error
+	return records, err
+}
+
+// CreateSuggestions stores newly generated optimizer suggestions. A suggestion
+// a concurrent analysis already stored as open is left as it is.
+func (r *AnalyticsRepository) CreateSuggestions(ctx context.Context, records []*domain.OptimizationSuggestionRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&records).Error
+}
+
+// GetSuggestion gets one of a user's stored optimizer suggestions
+func (r *AnalyticsRepository) GetSuggestion(ctx context.Context, userID, id string) (*domain.OptimizationSuggestionRecord, error) {
+	var record domain.OptimizationSuggestionRecord
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&record).Error
+	return &record, err
+}
+
+// UpdateSuggestion saves a suggestion's state: dismissal, feedback, whether it
+// was applied and when it was last suggested
+func (r *AnalyticsRepository) UpdateSuggestion(ctx context.Context, record *domain.OptimizationSuggestionRecord) error {
+	return r.db.WithContext(ctx).Model(record).
+		Select("suggestion", "dismissed", "dismissed_at", "feedback", "applied", "applied_at", "last_suggested_at").
+		Updates(record).Error
+}
+
+// GetSuggestionFeedback aggregates a user's ratings per suggestion type, counting
+// a dismissal without a rating as unhelpful
+func (r *AnalyticsRepository) GetSuggestionFeedback(ctx context.Context, userID string) ([]*domain.SuggestionTypeFeedback, error) {
+	var results []*domain.SuggestionTypeFeedback
+	err := r.db.WithContext(ctx).Model(&domain.OptimizationSuggestionRecord{}).
+		Select(`type,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE feedback > 0) AS helpful,
+			COUNT(*) FILTER (WHERE feedback < 0 OR (dismissed AND feedback = 0)) AS unhelpful`).
+		Where("user_id = ?", userID).
+		Group("type").
+		Scan(&results).Error
+	return results, err
+}
+
 // GetPlatformStats gets aggregated stats for all platforms
 func (r *AnalyticsRepository) GetPlatformStats(ctx context.Context) ([]PlatformStatData, error) {
 	var results []PlatformStatData
@@ -288,17 +585,34 @@ func (r *AnalyticsRepository) StoreWebhookEvent(ctx context.Context, event *doma
 	return r.db.WithContext(ctx).Create(event).Error
 }
 
-// GetUnprocessedWebhookEvents gets unprocessed webhook events
+// GetUnprocessedWebhookEvents gets unprocessed webhook events that haven't
+// failed for good, oldest first
 func (r *AnalyticsRepository) GetUnprocessedWebhookEvents(ctx context.Context, limit int) ([]domain.WebhookEvent, error) {
 	var events []domain.WebhookEvent
 	
-	err := r.db.WithContext(ctx).Where("processed = ?", false).
+	err := r.db.WithContext(ctx).Where("processed = ? AND failed_at IS NULL", false).
+		Order("created_at").
 		Limit(limit).
 		Find(&events).Error
 	
 	return events, err
 }
 
+// RecordWebhookEventFailure counts a failed attempt at processing a webhook
+// event. A final failure stops the event being picked up again.
+func (r *AnalyticsRepository) RecordWebhookEventFailure(ctx context.Context, eventID, reason string, final bool) error {
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+	}
+	if final {
+		updates["failed_at"] = time.Now().UTC()
+	}
+	return r.db.WithContext(ctx).Model(&domain.WebhookEvent{}).
+		Where("id = ?", eventID).
+		Updates(updates).Error
+}
+
 // MarkWebhookEventProcessed marks a webhook event as processed
 func (r *AnalyticsRepository) MarkWebhookEventProcessed(ctx context.Context, eventID string) error {
 	now := time.Now().UTC()
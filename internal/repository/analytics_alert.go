@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"renderowl-api/internal/domain"
+)
+
+// ListAlerts gets a user's analytics alerts, oldest first
+func (r *AnalyticsRepository) ListAlerts(ctx context.Context, userID string) ([]*domain.AnalyticsAlert, error) {
+	var alerts []*domain.AnalyticsAlert
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&alerts).Error
+	return alerts, err
+}
+
+// ListEnabledAlerts gets the enabled analytics alerts of the given users
+func (r *AnalyticsRepository) ListEnabledAlerts(ctx context.Context, userIDs []string) ([]*domain.AnalyticsAlert, error) {
+	var alerts []*domain.AnalyticsAlert
+	err := r.db.WithContext(ctx).Where("user_id IN ? AND enabled = ?", userIDs, true).Find(&alerts).Error
+	return alerts, err
+}
+
+// GetAlert gets one of a user's analytics alerts
+func (r *AnalyticsRepository) GetAlert(ctx context.Context, userID, id string) (*domain.AnalyticsAlert, error) {
+	var alert domain.AnalyticsAlert
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&alert).Error
+	return &alert, err
+}
+
+// CreateAlert stores a new analytics alert with the baseline firings of the
+// videos already past its threshold
+func (r *AnalyticsRepository) CreateAlert(ctx context.Context, alert *domain.AnalyticsAlert, baseline []*domain.AnalyticsAlertFiring) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(alert).Error; err != nil {
+			return err
+		}
+		return createBaseline(tx, alert, baseline)
+	})
+}
+
+// UpdateAlert saves an analytics alert's rule. When resetFirings is set the
+// videos it already fired for are forgotten and baseline is recorded in
+// their place, so only videos that cross the new rule later are reported.
+func (r *AnalyticsRepository) UpdateAlert(ctx context.Context, alert *domain.AnalyticsAlert, resetFirings bool, baseline []*domain.AnalyticsAlertFiring) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(alert).
+			Select("name", "metric", "threshold", "webhook_url", "enabled").
+			Updates(alert).Error; err != nil {
+			return err
+		}
+		if !resetFirings {
+			return nil
+		}
+		if err := tx.Where("alert_id = ?", alert.ID).Delete(&domain.AnalyticsAlertFiring{}).Error; err != nil {
+			return err
+		}
+		return createBaseline(tx, alert, baseline)
+	})
+}
+
+// alertBaselineBatchSize is how many baseline firings go in one INSERT
+const alertBaselineBatchSize = 1000
+
+// createBaseline records an alert's baseline firings
+func createBaseline(tx *gorm.DB, alert *domain.AnalyticsAlert, baseline []*domain.AnalyticsAlertFiring) error {
+	if len(baseline) == 0 {
+		return nil
+	}
+	for _, firing := range baseline {
+		firing.AlertID = alert.ID
+		firing.Baseline = true
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(baseline, alertBaselineBatchSize).Error
+}
+
+// DeleteAlert deletes one of a user's analytics alerts and its firings
+func (r *AnalyticsRepository) DeleteAlert(ctx context.Context, userID, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&domain.AnalyticsAlert{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("alert_id = ?", id).Delete(&domain.AnalyticsAlertFiring{}).Error
+	})
+}
+
+// ListAlertFirings gets the firings of the given alerts
+func (r *AnalyticsRepository) ListAlertFirings(ctx context.Context, alertIDs []string) ([]*domain.AnalyticsAlertFiring, error) {
+	var firings []*domain.AnalyticsAlertFiring
+	err := r.db.WithContext(ctx).Where("alert_id IN ?", alertIDs).Find(&firings).Error
+	return firings, err
+}
+
+// RecordAlertFiring marks an alert as fired for a video
+func (r *AnalyticsRepository) RecordAlertFiring(ctx context.Context, firing *domain.AnalyticsAlertFiring) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(firing).Error
+}
+
+// ListVideoPerformanceByUsers gets the performance rows of the given users' videos
+func (r *AnalyticsRepository) ListVideoPerformanceByUsers(ctx context.Context, userIDs []string) ([]*domain.VideoPerformance, error) {
+	var videos []*domain.VideoPerformance
+	err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&videos).Error
+	return videos, err
+}
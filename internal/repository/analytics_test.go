@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain"
+)
+
+func TestEngagementRowsKeepTheLastCounts(t *testing.T) {
+	date := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	records := []domain.AnalyticsEngagement{
+		{VideoID: "video-1", Platform: "youtube", Likes: 10, Comments: 2, Shares: 1},
+		{VideoID: "video-1", Platform: "tiktok", Likes: 50, Comments: 5, Shares: 3},
+		{VideoID: "video-2", Platform: "youtube", Likes: 7},
+		{VideoID: "video-1", Platform: "youtube", Likes: 12, Comments: 0, Shares: 4, Saves: 9},
+		{VideoID: "video-2", Platform: "youtube", Likes: 0},
+	}
+
+	rows := engagementRows(records, date)
+	want := []domain.AnalyticsEngagement{
+		{VideoID: "video-1", Platform: "youtube", Likes: 12, Comments: 0, Shares: 4, Date: date},
+		{VideoID: "video-1", Platform: "tiktok", Likes: 50, Comments: 5, Shares: 3, Date: date},
+		{VideoID: "video-2", Platform: "youtube", Likes: 0, Date: date},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+}
+
+func TestEngagementRowsWithoutDuplicates(t *testing.T) {
+	date := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	records := []domain.AnalyticsEngagement{
+		{ID: "ignored", VideoID: "video-1", Platform: "youtube", Likes: 1},
+		{VideoID: "video-2", Platform: "youtube", Likes: 2},
+	}
+
+	rows := engagementRows(records, date)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if rows[0].ID != "" {
+		t.Errorf("row keeps the record's ID %q", rows[0].ID)
+	}
+}
+
+func TestBatchEngagementUpsertsOnTheEngagementIndex(t *testing.T) {
+	db, statements := dryRunDB(t)
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&domain.AnalyticsEngagement{}); err != nil {
+		t.Fatalf("parse engagement schema: %v", err)
+	}
+	index, ok := stmt.Schema.ParseIndexes()["idx_engagement_video_platform_date"]
+	if !ok || index.Class != "UNIQUE" {
+		t.Fatal("no unique idx_engagement_video_platform_date index to upsert on")
+	}
+	var columns []string
+	for _, field := range index.Fields {
+		columns = append(columns, `"`+field.DBName+`"`)
+	}
+
+	r := NewAnalyticsRepository(db)
+	records := []domain.AnalyticsEngagement{
+		{VideoID: "video-1", Platform: "youtube", Likes: 10},
+		{VideoID: "video-2", Platform: "youtube", Likes: 7},
+		{VideoID: "video-1", Platform: "youtube", Likes: 12},
+	}
+	if err := r.BatchTrackEngagement(context.Background(), records); err != nil {
+		t.Fatalf("BatchTrackEngagement: %v", err)
+	}
+	if len(*statements) != 1 {
+		t.Fatalf("ran %d statements, want 1: %q", len(*statements), *statements)
+	}
+
+	statement := (*statements)[0]
+	if rows := strings.Count(statement, "),(") + 1; rows != 2 {
+		t.Errorf("inserted %d rows, want one per video and platform: %s", rows, statement)
+	}
+	conflict := "ON CONFLICT (" + strings.Join(columns, ",") + ") DO UPDATE SET " +
+		`"likes"="excluded"."likes","comments"="excluded"."comments","shares"="excluded"."shares","updated_at"="excluded"."updated_at"`
+	if !strings.Contains(statement, conflict) {
+		t.Errorf("upsert is %s\nwant it to end %s", statement, conflict)
+	}
+}
+
+func TestOpenSuggestionsAreUniquePerKey(t *testing.T) {
+	db, statements := dryRunDB(t)
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&domain.OptimizationSuggestionRecord{}); err != nil {
+		t.Fatalf("parse suggestion schema: %v", err)
+	}
+	index, ok := stmt.Schema.ParseIndexes()["idx_open_suggestion_key"]
+	if !ok {
+		t.Fatal("no idx_open_suggestion_key index")
+	}
+	var columns []string
+	for _, field := range index.Fields {
+		columns = append(columns, field.DBName)
+	}
+	if index.Class != "UNIQUE" || index.Where != "NOT applied" || !reflect.DeepEqual(columns, []string{"user_id", "video_id", "key"}) {
+		t.Errorf("index is %s (%v) WHERE %q, want UNIQUE (user_id, video_id, key) WHERE NOT applied", index.Class, columns, index.Where)
+	}
+
+	r := NewAnalyticsRepository(db)
+	records := []*domain.OptimizationSuggestionRecord{{UserID: "user-1", VideoID: "video-1", Key: "title:Add Power Words", Type: "title"}}
+	if err := r.CreateSuggestions(context.Background(), records); err != nil {
+		t.Fatalf("CreateSuggestions: %v", err)
+	}
+	if len(*statements) != 1 || !strings.Contains((*statements)[0], "ON CONFLICT DO NOTHING") {
+		t.Errorf("suggestions stored with %q, want an insert that leaves existing open suggestions", *statements)
+	}
+}
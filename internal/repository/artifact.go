@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain/social"
+)
+
+// ArtifactRepository finds and clears the references to generated artifacts
+// that other records hold by URL
+type ArtifactRepository struct {
+	db *gorm.DB
+}
+
+// NewArtifactRepository creates a new artifact repository
+func NewArtifactRepository(db *gorm.DB) *ArtifactRepository {
+	return &ArtifactRepository{db: db}
+}
+
+// ReferencedArtifacts returns which of the URLs a published post or a saved
+// template still uses
+func (r *ArtifactRepository) ReferencedArtifacts(ctx context.Context, urls []string) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	if len(urls) == 0 {
+		return referenced, nil
+	}
+	wanted := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		wanted[url] = true
+	}
+
+	var posts []struct {
+		ThumbnailURL string
+		VideoPath    string
+	}
+	err := r.db.WithContext(ctx).Model(&social.ScheduledPost{}).
+		Select("COALESCE(metadata->>'thumbnailUrl', '') AS thumbnail_url, COALESCE(metadata->>'videoPath', '') AS video_path").
+		Where("status = ?", social.PostStatusPublished).
+		Where("metadata->>'thumbnailUrl' IN ? OR metadata->>'videoPath' IN ?", urls, urls).
+		Scan(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		for _, url := range []string{post.ThumbnailURL, post.VideoPath} {
+			if wanted[url] {
+				referenced[url] = true
+			}
+		}
+	}
+
+	// Template clips keep their sources inside the scenes JSON, so templates
+	// are checked here rather than in SQL
+	var templates []TemplateModel
+	if err := r.db.WithContext(ctx).Select("thumbnail", "scenes").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		if wanted[template.Thumbnail] {
+			referenced[template.Thumbnail] = true
+		}
+		for _, scene := range template.Scenes {
+			for _, clip := range scene.Clips {
+				if wanted[clip.SourceURL] {
+					referenced[clip.SourceURL] = true
+				}
+			}
+		}
+	}
+
+	return referenced, nil
+}
+
+// ClearArtifactURLs blanks the URLs of deleted artifacts on the clips and
+// unpublished posts that point at them, returning how many records changed
+func (r *ArtifactRepository) ClearArtifactURLs(ctx context.Context, urls []string) (int64, error) {
+	if len(urls) == 0 {
+		return 0, nil
+	}
+	var cleared int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		clips := tx.Model(&ClipModel{}).Where("source_url IN ?", urls).Update("source_url", "")
+		if clips.Error != nil {
+			return clips.Error
+		}
+		posts := tx.Model(&social.ScheduledPost{}).
+			Where("status <> ?", social.PostStatusPublished).
+			Where("metadata->>'thumbnailUrl' IN ?", urls).
+			Update("metadata", gorm.Expr("metadata - 'thumbnailUrl'"))
+		if posts.Error != nil {
+			return posts.Error
+		}
+		cleared = clips.RowsAffected + posts.RowsAffected
+		return nil
+	})
+	return cleared, err
+}
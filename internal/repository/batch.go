@@ -2,7 +2,6 @@ package repository
 
 import (
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -43,20 +42,25 @@ func (BatchModel) TableName() string {
 
 // BatchVideoModel is the database model for batch videos
 type BatchVideoModel struct {
-	ID          string  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	BatchID     string  `gorm:"index;not null"`
-	Title       string  `gorm:"not null"`
-	Description string
-	Status      string  `gorm:"not null;default:'pending'"`
-	TimelineID  string
-	Error       string
-	Progress    float64 `gorm:"default:0"`
-	ConfigJSON  string  `gorm:"type:jsonb"`
-	ResultJSON  string  `gorm:"type:jsonb"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	StartedAt   *time.Time
-	CompletedAt *time.Time
+	ID             string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	BatchID        string `gorm:"index;not null"`
+	Title          string `gorm:"not null"`
+	Description    string
+	Status         string `gorm:"not null;default:'pending'"`
+	TimelineID     string
+	TaskID         string
+	Error          string
+	FailedStage    string
+	FailedProvider string
+	Retryable      bool
+	Progress       float64 `gorm:"default:0"`
+	ConfigJSON     string  `gorm:"type:jsonb"`
+	ResultJSON     string  `gorm:"type:jsonb"`
+	LogsJSON       string  `gorm:"type:jsonb"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	StartedAt      *time.Time
+	CompletedAt    *time.Time
 }
 
 // TableName specifies the table name
@@ -132,21 +136,31 @@ func (r *BatchRepository) createVideo(video *domain.BatchVideo) error {
 		}
 	}
 
+	logsJSON, err := json.Marshal(video.Logs)
+	if err != nil {
+		return err
+	}
+
 	model := &BatchVideoModel{
-		ID:          video.ID,
-		BatchID:     video.BatchID,
-		Title:       video.Title,
-		Description: video.Description,
-		Status:      string(video.Status),
-		TimelineID:  video.TimelineID,
-		Error:       video.Error,
-		Progress:    video.Progress,
-		ConfigJSON:  string(configJSON),
-		ResultJSON:  string(resultJSON),
-		CreatedAt:   video.CreatedAt,
-		UpdatedAt:   video.UpdatedAt,
-		StartedAt:   video.StartedAt,
-		CompletedAt: video.CompletedAt,
+		ID:             video.ID,
+		BatchID:        video.BatchID,
+		Title:          video.Title,
+		Description:    video.Description,
+		Status:         string(video.Status),
+		TimelineID:     video.TimelineID,
+		TaskID:         video.TaskID,
+		Error:          video.Error,
+		FailedStage:    video.FailedStage,
+		FailedProvider: video.FailedProvider,
+		Retryable:      video.Retryable,
+		Progress:       video.Progress,
+		ConfigJSON:     string(configJSON),
+		ResultJSON:     string(resultJSON),
+		LogsJSON:       string(logsJSON),
+		CreatedAt:      video.CreatedAt,
+		UpdatedAt:      video.UpdatedAt,
+		StartedAt:      video.StartedAt,
+		CompletedAt:    video.CompletedAt,
 	}
 
 	return r.db.Create(model).Error
@@ -157,7 +171,36 @@ func (r *BatchRepository) Get(id string) (*domain.Batch, error) {
 	var model BatchModel
 	if err := r.db.Preload("Videos").First(&model, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("batch not found")
+			return nil, domain.ErrBatchNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+// GetByIDAndUser retrieves a batch by ID, scoped to its owner
+func (r *BatchRepository) GetByIDAndUser(id, userID string) (*domain.Batch, error) {
+	var model BatchModel
+	if err := r.db.Preload("Videos").Where("id = ? AND user_id = ?", id, userID).First(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrBatchNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+// GetByVideoIDAndUser retrieves the batch holding a video, scoped to its owner
+func (r *BatchRepository) GetByVideoIDAndUser(videoID, userID string) (*domain.Batch, error) {
+	var model BatchModel
+	err := r.db.Preload("Videos").
+		Where("user_id = ? AND id = (?)", userID, r.db.Model(&BatchVideoModel{}).Select("batch_id").Where("id = ?", videoID)).
+		First(&model).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrBatchNotFound
 		}
 		return nil, err
 	}
@@ -210,6 +253,20 @@ func (r *BatchRepository) Update(batch *domain.Batch) error {
 	return nil
 }
 
+// UpdateVideo updates one video of a batch, leaving the batch and its other
+// videos as stored
+func (r *BatchRepository) UpdateVideo(video *domain.BatchVideo) error {
+	return r.updateVideo(video)
+}
+
+// ReleaseInProgress takes count videos off a batch's in-progress count
+func (r *BatchRepository) ReleaseInProgress(batchID string, count int) error {
+	return r.db.Model(&BatchModel{}).Where("id = ?", batchID).Updates(map[string]interface{}{
+		"in_progress": gorm.Expr("GREATEST(in_progress - ?, 0)", count),
+		"updated_at":  time.Now(),
+	}).Error
+}
+
 // updateVideo updates a batch video
 func (r *BatchRepository) updateVideo(video *domain.BatchVideo) error {
 	configJSON, err := json.Marshal(video.Config)
@@ -225,20 +282,30 @@ func (r *BatchRepository) updateVideo(video *domain.BatchVideo) error {
 		}
 	}
 
+	logsJSON, err := json.Marshal(video.Logs)
+	if err != nil {
+		return err
+	}
+
 	model := &BatchVideoModel{
-		ID:          video.ID,
-		BatchID:     video.BatchID,
-		Title:       video.Title,
-		Description: video.Description,
-		Status:      string(video.Status),
-		TimelineID:  video.TimelineID,
-		Error:       video.Error,
-		Progress:    video.Progress,
-		ConfigJSON:  string(configJSON),
-		ResultJSON:  string(resultJSON),
-		UpdatedAt:   video.UpdatedAt,
-		StartedAt:   video.StartedAt,
-		CompletedAt: video.CompletedAt,
+		ID:             video.ID,
+		BatchID:        video.BatchID,
+		Title:          video.Title,
+		Description:    video.Description,
+		Status:         string(video.Status),
+		TimelineID:     video.TimelineID,
+		TaskID:         video.TaskID,
+		Error:          video.Error,
+		FailedStage:    video.FailedStage,
+		FailedProvider: video.FailedProvider,
+		Retryable:      video.Retryable,
+		Progress:       video.Progress,
+		ConfigJSON:     string(configJSON),
+		ResultJSON:     string(resultJSON),
+		LogsJSON:       string(logsJSON),
+		UpdatedAt:      video.UpdatedAt,
+		StartedAt:      video.StartedAt,
+		CompletedAt:    video.CompletedAt,
 	}
 
 	return r.db.Save(model).Error
@@ -264,6 +331,38 @@ func (r *BatchRepository) List(userID string, limit, offset int) ([]*domain.Batc
 	return batches, nil
 }
 
+// ListWithCount lists a page of a user's batches along with how many
+// batches they have in total
+func (r *BatchRepository) ListWithCount(userID string, limit, offset int) ([]*domain.Batch, int64, error) {
+	var total int64
+	if err := r.db.Model(&BatchModel{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	batches, err := r.List(userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return batches, total, nil
+}
+
+// ListByStatus lists the batches of every user that are in one of the statuses
+func (r *BatchRepository) ListByStatus(statuses ...domain.BatchStatus) ([]*domain.Batch, error) {
+	var models []BatchModel
+	if err := r.db.Where("status IN ?", statuses).
+		Order("created_at").
+		Preload("Videos").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	var batches []*domain.Batch
+	for _, model := range models {
+		batches = append(batches, r.toDomain(&model))
+	}
+
+	return batches, nil
+}
+
 // Delete deletes a batch
 func (r *BatchRepository) Delete(id string) error {
 	return r.db.Delete(&BatchModel{}, "id = ?", id).Error
@@ -319,21 +418,32 @@ func (r *BatchRepository) videoToDomain(model *BatchVideoModel) *domain.BatchVid
 		json.Unmarshal([]byte(model.ResultJSON), &result)
 	}
 
+	// Deserialize logs
+	var logs []string
+	if model.LogsJSON != "" {
+		json.Unmarshal([]byte(model.LogsJSON), &logs)
+	}
+
 	return &domain.BatchVideo{
-		ID:          model.ID,
-		BatchID:     model.BatchID,
-		Title:       model.Title,
-		Description: model.Description,
-		Status:      domain.VideoStatus(model.Status),
-		TimelineID:  model.TimelineID,
-		Error:       model.Error,
-		Progress:    model.Progress,
-		Config:      config,
-		Result:      result,
-		CreatedAt:   model.CreatedAt,
-		UpdatedAt:   model.UpdatedAt,
-		StartedAt:   model.StartedAt,
-		CompletedAt: model.CompletedAt,
+		ID:             model.ID,
+		BatchID:        model.BatchID,
+		Title:          model.Title,
+		Description:    model.Description,
+		Status:         domain.VideoStatus(model.Status),
+		TimelineID:     model.TimelineID,
+		TaskID:         model.TaskID,
+		Error:          model.Error,
+		FailedStage:    model.FailedStage,
+		FailedProvider: model.FailedProvider,
+		Retryable:      model.Retryable,
+		Progress:       model.Progress,
+		Config:         config,
+		Result:         result,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+		StartedAt:      model.StartedAt,
+		CompletedAt:    model.CompletedAt,
+		Logs:           logs,
 	}
 }
 
@@ -341,8 +451,10 @@ func (r *BatchRepository) videoToDomain(model *BatchVideoModel) *domain.BatchVid
 type BatchRepositoryInterface interface {
 	Create(batch *domain.Batch) error
 	Get(id string) (*domain.Batch, error)
+	GetByIDAndUser(id, userID string) (*domain.Batch, error)
 	Update(batch *domain.Batch) error
 	List(userID string, limit, offset int) ([]*domain.Batch, error)
+	ListByStatus(statuses ...domain.BatchStatus) ([]*domain.Batch, error)
 	Delete(id string) error
 }
 
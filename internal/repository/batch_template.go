@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"renderowl-api/internal/domain"
+)
+
+// BatchTemplateRepository implements the batch template repository interface
+type BatchTemplateRepository struct {
+	db *gorm.DB
+}
+
+// BatchTemplateModel is the database model for batch templates
+type BatchTemplateModel struct {
+	ID                string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID            string `gorm:"index;not null"`
+	Name              string `gorm:"not null"`
+	Description       string
+	ConfigJSON        string `gorm:"type:jsonb"`
+	VideoDefaultsJSON string `gorm:"type:jsonb"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// TableName specifies the table name
+func (BatchTemplateModel) TableName() string {
+	return "batch_templates"
+}
+
+// NewBatchTemplateRepository creates a new batch template repository
+func NewBatchTemplateRepository(db *gorm.DB) *BatchTemplateRepository {
+	return &BatchTemplateRepository{db: db}
+}
+
+// Create creates a new batch template
+func (r *BatchTemplateRepository) Create(template *domain.BatchTemplate) error {
+	configJSON, err := json.Marshal(template.Config)
+	if err != nil {
+		return err
+	}
+
+	defaultsJSON, err := json.Marshal(template.VideoDefaults)
+	if err != nil {
+		return err
+	}
+
+	model := &BatchTemplateModel{
+		ID:                template.ID,
+		UserID:            template.UserID,
+		Name:              template.Name,
+		Description:       template.Description,
+		ConfigJSON:        string(configJSON),
+		VideoDefaultsJSON: string(defaultsJSON),
+		CreatedAt:         template.CreatedAt,
+		UpdatedAt:         template.UpdatedAt,
+	}
+
+	return r.db.Create(model).Error
+}
+
+// GetByIDAndUser retrieves a batch template by ID and user ID
+func (r *BatchTemplateRepository) GetByIDAndUser(id, userID string) (*domain.BatchTemplate, error) {
+	var model BatchTemplateModel
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrBatchTemplateNotFound
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&model), nil
+}
+
+// ListByUser lists all batch templates for a user
+func (r *BatchTemplateRepository) ListByUser(userID string) ([]*domain.BatchTemplate, error) {
+	var models []BatchTemplateModel
+	if err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	templates := make([]*domain.BatchTemplate, 0, len(models))
+	for i := range models {
+		templates = append(templates, r.toDomain(&models[i]))
+	}
+
+	return templates, nil
+}
+
+// Delete deletes a batch template owned by the user
+func (r *BatchTemplateRepository) Delete(id, userID string) error {
+	result := r.db.Delete(&BatchTemplateModel{}, "id = ? AND user_id = ?", id, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrBatchTemplateNotFound
+	}
+	return nil
+}
+
+// toDomain converts a model to domain object
+func (r *BatchTemplateRepository) toDomain(model *BatchTemplateModel) *domain.BatchTemplate {
+	var config domain.BatchConfig
+	json.Unmarshal([]byte(model.ConfigJSON), &config)
+
+	var defaults domain.VideoConfig
+	json.Unmarshal([]byte(model.VideoDefaultsJSON), &defaults)
+
+	return &domain.BatchTemplate{
+		ID:            model.ID,
+		UserID:        model.UserID,
+		Name:          model.Name,
+		Description:   model.Description,
+		Config:        config,
+		VideoDefaults: defaults,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+	}
+}
+
+var _ domain.BatchTemplateRepository = (*BatchTemplateRepository)(nil)
@@ -28,6 +28,20 @@ func (r *ClipRepository) Create(clip *domain.Clip) error {
 	return nil
 }
 
+// CreateMany creates several clips in a single transaction
+func (r *ClipRepository) CreateMany(clips []*domain.Clip) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, clip := range clips {
+			model := toClipModel(clip)
+			if err := tx.Create(model).Error; err != nil {
+				return err
+			}
+			*clip = *fromClipModel(model)
+		}
+		return nil
+	})
+}
+
 // GetByID retrieves a clip by ID
 func (r *ClipRepository) GetByID(id string) (*domain.Clip, error) {
 	var model ClipModel
@@ -82,23 +96,29 @@ func (r *ClipRepository) Delete(id string) error {
 // Helper functions
 func toClipModel(c *domain.Clip) *ClipModel {
 	m := &ClipModel{
-		ID:          c.ID,
-		TimelineID:  c.TimelineID,
-		TrackID:     c.TrackID,
-		Name:        c.Name,
-		Type:        c.Type,
-		SourceURL:   c.SourceURL,
-		StartTime:   c.StartTime,
-		EndTime:     c.EndTime,
-		Duration:    c.Duration,
-		TrimStart:   c.TrimStart,
-		TrimEnd:     c.TrimEnd,
-		PositionX:   c.PositionX,
-		PositionY:   c.PositionY,
-		Scale:       c.Scale,
-		Rotation:    c.Rotation,
-		Opacity:     c.Opacity,
-		TextContent: c.TextContent,
+		ID:           c.ID,
+		TimelineID:   c.TimelineID,
+		TrackID:      c.TrackID,
+		Name:         c.Name,
+		Type:         c.Type,
+		SourceURL:    c.SourceURL,
+		StartTime:    c.StartTime,
+		EndTime:      c.EndTime,
+		Duration:     c.Duration,
+		TrimStart:    c.TrimStart,
+		TrimEnd:      c.TrimEnd,
+		SourceStart:  c.SourceStart,
+		SourceEnd:    c.SourceEnd,
+		SourceLength: c.SourceLength,
+		PositionX:    c.PositionX,
+		PositionY:    c.PositionY,
+		Scale:        c.Scale,
+		Rotation:     c.Rotation,
+		Opacity:      c.Opacity,
+		Volume:       c.Volume,
+		FadeIn:       c.FadeIn,
+		FadeOut:      c.FadeOut,
+		TextContent:  c.TextContent,
 	}
 	if c.TextStyle != nil {
 		m.TextStyle = &TextStyleModel{
@@ -116,23 +136,29 @@ func toClipModel(c *domain.Clip) *ClipModel {
 
 func fromClipModel(m *ClipModel) *domain.Clip {
 	c := &domain.Clip{
-		ID:          m.ID,
-		TimelineID:  m.TimelineID,
-		TrackID:     m.TrackID,
-		Name:        m.Name,
-		Type:        m.Type,
-		SourceURL:   m.SourceURL,
-		StartTime:   m.StartTime,
-		EndTime:     m.EndTime,
-		Duration:    m.Duration,
-		TrimStart:   m.TrimStart,
-		TrimEnd:     m.TrimEnd,
-		PositionX:   m.PositionX,
-		PositionY:   m.PositionY,
-		Scale:       m.Scale,
-		Rotation:    m.Rotation,
-		Opacity:     m.Opacity,
-		TextContent: m.TextContent,
+		ID:           m.ID,
+		TimelineID:   m.TimelineID,
+		TrackID:      m.TrackID,
+		Name:         m.Name,
+		Type:         m.Type,
+		SourceURL:    m.SourceURL,
+		StartTime:    m.StartTime,
+		EndTime:      m.EndTime,
+		Duration:     m.Duration,
+		TrimStart:    m.TrimStart,
+		TrimEnd:      m.TrimEnd,
+		SourceStart:  m.SourceStart,
+		SourceEnd:    m.SourceEnd,
+		SourceLength: m.SourceLength,
+		PositionX:    m.PositionX,
+		PositionY:    m.PositionY,
+		Scale:        m.Scale,
+		Rotation:     m.Rotation,
+		Opacity:      m.Opacity,
+		Volume:       m.Volume,
+		FadeIn:       m.FadeIn,
+		FadeOut:      m.FadeOut,
+		TextContent:  m.TextContent,
 	}
 	if m.TextStyle != nil {
 		c.TextStyle = &domain.Style{
@@ -33,9 +33,14 @@ type TrackModel struct {
 	Order      int    `gorm:"not null;default:0"`
 	Muted      bool   `gorm:"default:false"`
 	Solo       bool   `gorm:"default:false"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	Clips      []ClipModel `gorm:"foreignKey:TrackID;constraint:OnDelete:CASCADE;"`
+	Role       string `gorm:"default:''"`
+	// Ducking is off while DuckAmountDB is zero
+	DuckAmountDB  float64 `gorm:"default:0"`
+	DuckAttackMs  int     `gorm:"default:0"`
+	DuckReleaseMs int     `gorm:"default:0"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Clips         []ClipModel `gorm:"foreignKey:TrackID;constraint:OnDelete:CASCADE;"`
 }
 
 // TableName specifies the table name for TrackModel
@@ -56,11 +61,18 @@ type ClipModel struct {
 	Duration    float64
 	TrimStart   float64 `gorm:"default:0"`
 	TrimEnd     float64
+	// Source in/out points into SourceURL; SourceEnd 0 plays to the clip's end
+	SourceStart float64 `gorm:"default:0"`
+	SourceEnd   float64 `gorm:"default:0"`
+	SourceLength float64 `gorm:"default:0"`
 	PositionX   float64 `gorm:"default:0"`
 	PositionY   float64 `gorm:"default:0"`
 	Scale       float64 `gorm:"default:1"`
 	Rotation    float64 `gorm:"default:0"`
 	Opacity     float64 `gorm:"default:1"`
+	Volume      float64 `gorm:"default:1"`
+	FadeIn      float64 `gorm:"default:0"`
+	FadeOut     float64 `gorm:"default:0"`
 	TextContent string
 	TextStyle   *TextStyleModel `gorm:"embedded;embeddedPrefix:text_"`
 	CreatedAt   time.Time
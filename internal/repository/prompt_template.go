@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"renderowl-api/internal/domain"
+)
+
+// PromptTemplateRepository stores users' own system prompts
+type PromptTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewPromptTemplateRepository creates a new prompt template repository
+func NewPromptTemplateRepository(db *gorm.DB) *PromptTemplateRepository {
+	return &PromptTemplateRepository{db: db}
+}
+
+// ListPromptTemplates gets all of a user's prompt templates
+func (r *PromptTemplateRepository) ListPromptTemplates(ctx context.Context, userID string) ([]*domain.PromptTemplate, error) {
+	var templates []*domain.PromptTemplate
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("kind").Find(&templates).Error
+	return templates, err
+}
+
+// GetPromptTemplate gets a user's prompt template of one kind
+func (r *PromptTemplateRepository) GetPromptTemplate(ctx context.Context, userID, kind string) (*domain.PromptTemplate, error) {
+	var template domain.PromptTemplate
+	err := r.db.WithContext(ctx).Where("user_id = ? AND kind = ?", userID, kind).First(&template).Error
+	return &template, err
+}
+
+// SavePromptTemplate creates a user's prompt template of its kind, or
+// replaces the one they have
+func (r *PromptTemplateRepository) SavePromptTemplate(ctx context.Context, template *domain.PromptTemplate) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "kind"}},
+		DoUpdates: clause.AssignmentColumns([]string{"template", "updated_at"}),
+	}).Create(template).Error
+}
+
+// DeletePromptTemplate removes a user's prompt template of one kind,
+// reporting whether there was one
+func (r *PromptTemplateRepository) DeletePromptTemplate(ctx context.Context, userID, kind string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND kind = ?", userID, kind).Delete(&domain.PromptTemplate{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain"
+)
+
+// searchQueries select each searchable table's matches as search results,
+// ranked by how well the title matches
+var searchQueries = map[string]string{
+	domain.SearchTypeTimeline: `SELECT 'timeline' AS type, id, name AS title, COALESCE(description, '') AS description, '' AS status, updated_at,
+	` + titleRelevance("name") + ` AS relevance
+	FROM timelines WHERE user_id = @user AND (name ILIKE @pattern OR description ILIKE @pattern)`,
+	domain.SearchTypeBatch: `SELECT 'batch' AS type, id, name AS title, COALESCE(description, '') AS description, status, updated_at,
+	` + titleRelevance("name") + ` AS relevance
+	FROM batches WHERE user_id = @user AND (name ILIKE @pattern OR description ILIKE @pattern)`,
+	domain.SearchTypeScheduledPost: `SELECT 'scheduled_post' AS type, id, title, COALESCE(description, '') AS description, status, updated_at,
+	` + titleRelevance("title") + ` AS relevance
+	FROM scheduled_posts WHERE user_id = @user AND (title ILIKE @pattern OR description ILIKE @pattern)`,
+}
+
+// titleRelevance scores a row: an exact title match, then a title prefix,
+// then anywhere in the title, then only in the description
+func titleRelevance(column string) string {
+	return `CASE WHEN lower(` + column + `) = lower(@term) THEN 1.0
+		WHEN ` + column + ` ILIKE @prefix THEN 0.8
+		WHEN ` + column + ` ILIKE @pattern THEN 0.6
+		ELSE 0.3 END`
+}
+
+// SearchRepository searches a user's timelines, batches and scheduled posts
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository creates a new search repository
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// Search returns a page of the user's items of the given types whose title
+// or description contains term, best matches first, and the total
+func (r *SearchRepository) Search(ctx context.Context, userID, term string, types []string, limit, offset int) ([]domain.SearchResult, int64, error) {
+	parts := make([]string, 0, len(types))
+	for _, t := range types {
+		if query, ok := searchQueries[t]; ok {
+			parts = append(parts, query)
+		}
+	}
+	if len(parts) == 0 {
+		return []domain.SearchResult{}, 0, nil
+	}
+	union := strings.Join(parts, "\nUNION ALL\n")
+
+	escaped := escapeLike(term)
+	args := []interface{}{
+		sql.Named("user", userID),
+		sql.Named("term", term),
+		sql.Named("prefix", escaped+"%"),
+		sql.Named("pattern", "%"+escaped+"%"),
+	}
+	db := r.db.WithContext(ctx)
+
+	var total int64
+	if err := db.Raw("SELECT COUNT(*) FROM ("+union+") results", args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := []domain.SearchResult{}
+	page := append(args, sql.Named("limit", limit), sql.Named("offset", offset))
+	err := db.Raw("SELECT * FROM ("+union+") results ORDER BY relevance DESC, updated_at DESC LIMIT @limit OFFSET @offset", page...).
+		Scan(&results).Error
+	return results, total, err
+}
+
+// escapeLike escapes LIKE wildcards so the term matches literally
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
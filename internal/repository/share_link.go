@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain"
+)
+
+// ShareLinkRepository stores share links so they can be revoked
+type ShareLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewShareLinkRepository creates a new share link repository
+func NewShareLinkRepository(db *gorm.DB) *ShareLinkRepository {
+	return &ShareLinkRepository{db: db}
+}
+
+// CreateShareLink records a new share link
+func (r *ShareLinkRepository) CreateShareLink(ctx context.Context, link *domain.ShareLink) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+// GetShareLink gets a share link by ID
+func (r *ShareLinkRepository) GetShareLink(ctx context.Context, id string) (*domain.ShareLink, error) {
+	var link domain.ShareLink
+	err := r.db.WithContext(ctx).First(&link, "id = ?", id).Error
+	return &link, err
+}
+
+// ListShareLinks gets a user's share links that haven't expired or been
+// revoked, newest first
+func (r *ShareLinkRepository) ListShareLinks(ctx context.Context, userID string) ([]*domain.ShareLink, error) {
+	var links []*domain.ShareLink
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&links).Error
+	return links, err
+}
+
+// RevokeShareLink revokes one of a user's share links. It returns
+// gorm.ErrRecordNotFound when the user has no such link.
+func (r *ShareLinkRepository) RevokeShareLink(ctx context.Context, userID, id string) error {
+	result := r.db.WithContext(ctx).Model(&domain.ShareLink{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
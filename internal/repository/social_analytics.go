@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"renderowl-api/internal/domain/social"
 )
@@ -19,6 +20,9 @@ func NewSocialAnalyticsRepository(db *gorm.DB) *SocialAnalyticsRepository {
 
 // Create creates analytics data
 func (r *SocialAnalyticsRepository) Create(ctx context.Context, data *social.AnalyticsData) error {
+	if data.ID == "" {
+		data.ID = uuid.New().String()
+	}
 	return r.db.WithContext(ctx).Create(data).Error
 }
 
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"renderowl-api/internal/domain/social"
+)
+
+// SocialCampaignRepository implements service.CampaignRepository
+type SocialCampaignRepository struct {
+	db *gorm.DB
+}
+
+// NewSocialCampaignRepository creates a new repository
+func NewSocialCampaignRepository(db *gorm.DB) *SocialCampaignRepository {
+	return &SocialCampaignRepository{db: db}
+}
+
+// Create creates a new campaign
+func (r *SocialCampaignRepository) Create(ctx context.Context, campaign *social.Campaign) error {
+	if campaign.ID == "" {
+		campaign.ID = uuid.New().String()
+	}
+	return r.db.WithContext(ctx).Create(campaign).Error
+}
+
+// GetByID gets campaign by ID
+func (r *SocialCampaignRepository) GetByID(ctx context.Context, id string) (*social.Campaign, error) {
+	var campaign social.Campaign
+	err := r.db.WithContext(ctx).First(&campaign, "id = ?", id).Error
+	return &campaign, err
+}
+
+// Update updates a campaign
+func (r *SocialCampaignRepository) Update(ctx context.Context, campaign *social.Campaign) error {
+	return r.db.WithContext(ctx).Save(campaign).Error
+}
+
+// ListBySourceVideo gets a user's campaigns for a source video, oldest first
+func (r *SocialCampaignRepository) ListBySourceVideo(ctx context.Context, userID, videoID string) ([]*social.Campaign, error) {
+	var campaigns []*social.Campaign
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND source_video_id = ?", userID, videoID).
+		Order("created_at ASC").
+		Find(&campaigns).Error
+	return campaigns, err
+}
@@ -3,7 +3,11 @@ package repository
 import (
 	"context"
 
+	"time"
+
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"renderowl-api/internal/domain/social"
 )
 
@@ -17,9 +21,17 @@ func NewSocialPostRepository(db *gorm.DB) *SocialPostRepository {
 	return &SocialPostRepository{db: db}
 }
 
-// Create creates a new scheduled post
+// Create creates a new scheduled post along with its platform posts
 func (r *SocialPostRepository) Create(ctx context.Context, post *social.ScheduledPost) error {
-	return r.db.WithContext(ctx).Create(post).Error
+	if post.ID == "" {
+		post.ID = uuid.New().String()
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		return savePlatformPosts(tx, post)
+	})
 }
 
 // GetByID gets post by ID
@@ -49,6 +61,141 @@ func (r *SocialPostRepository) GetByUser(ctx context.Context, userID string, lim
 	return posts, err
 }
 
+// List gets a user's posts matching the filter, newest first, along with the
+// total number of matches ignoring limit and offset
+func (r *SocialPostRepository) List(ctx context.Context, userID string, filter social.PostFilter) ([]*social.ScheduledPost, int64, error) {
+	query := r.db.WithContext(ctx).Model(&social.ScheduledPost{}).Where("user_id = ?", userID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Platform != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM platform_posts WHERE platform_posts.scheduled_post_id = scheduled_posts.id AND platform_posts.platform = ?)", filter.Platform)
+	}
+	if filter.From != nil {
+		query = query.Where("scheduled_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("scheduled_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var posts []*social.ScheduledPost
+	err := query.
+		Order("scheduled_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&posts).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadPlatformPosts(ctx, posts); err != nil {
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+// ListByVideo gets a user's posts of a video along with their platform posts,
+// oldest first
+func (r *SocialPostRepository) ListByVideo(ctx context.Context, userID, videoID string) ([]*social.ScheduledPost, error) {
+	var posts []*social.ScheduledPost
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND video_id = ?", userID, videoID).
+		Order("scheduled_at ASC").
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadPlatformPosts(ctx, posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// loadPlatformPosts fills in the platform posts of each post with a single query
+func (r *SocialPostRepository) loadPlatformPosts(ctx context.Context, posts []*social.ScheduledPost) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(posts))
+	byID := make(map[string]*social.ScheduledPost, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+		byID[post.ID] = post
+	}
+
+	var platformPosts []social.PlatformPost
+	if err := r.db.WithContext(ctx).Where("scheduled_post_id IN ?", ids).Find(&platformPosts).Error; err != nil {
+		return err
+	}
+	for _, platformPost := range platformPosts {
+		if post, ok := byID[platformPost.ScheduledPostID]; ok {
+			post.Platforms = append(post.Platforms, platformPost)
+		}
+	}
+	return nil
+}
+
+// GetRecurring gets every recurring post that hasn't been drafted or
+// cancelled, along with its platform posts
+func (r *SocialPostRepository) GetRecurring(ctx context.Context) ([]*social.ScheduledPost, error) {
+	var posts []*social.ScheduledPost
+	err := r.db.WithContext(ctx).
+		Where("recurring IS NOT NULL AND recurring::text <> 'null' AND status NOT IN ?", []social.PostStatus{social.PostStatusDraft, social.PostStatusCancelled}).
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadPlatformPosts(ctx, posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ClaimOccurrence records an occurrence of a recurring post. It reports
+// false when the occurrence was already recorded, meaning it has been
+// enqueued before and must not be again.
+func (r *SocialPostRepository) ClaimOccurrence(ctx context.Context, postID string, scheduledAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&social.PostOccurrence{
+		PostID:      postID,
+		ScheduledAt: scheduledAt,
+		Status:      social.PostStatusScheduled,
+	})
+	return result.RowsAffected == 1, result.Error
+}
+
+// LatestOccurrence gets the most recently scheduled occurrence of a post
+func (r *SocialPostRepository) LatestOccurrence(ctx context.Context, postID string) (*social.PostOccurrence, error) {
+	var occurrence social.PostOccurrence
+	err := r.db.WithContext(ctx).
+		Where("post_id = ?", postID).
+		Order("scheduled_at DESC").
+		First(&occurrence).Error
+	return &occurrence, err
+}
+
+// CountOccurrences counts the occurrences of a post recorded so far
+func (r *SocialPostRepository) CountOccurrences(ctx context.Context, postID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&social.PostOccurrence{}).Where("post_id = ?", postID).Count(&count).Error
+	return count, err
+}
+
+// UpdateOccurrenceStatus sets the status of an occurrence once it has run
+func (r *SocialPostRepository) UpdateOccurrenceStatus(ctx context.Context, postID string, scheduledAt time.Time, status social.PostStatus) error {
+	return r.db.WithContext(ctx).
+		Model(&social.PostOccurrence{}).
+		Where("post_id = ? AND scheduled_at = ?", postID, scheduledAt).
+		Update("status", status).Error
+}
+
 // GetPending gets posts scheduled before a certain time
 func (r *SocialPostRepository) GetPending(ctx context.Context, before string) ([]*social.ScheduledPost, error) {
 	var posts []*social.ScheduledPost
@@ -58,9 +205,60 @@ func (r *SocialPostRepository) GetPending(ctx context.Context, before string) ([
 	return posts, err
 }
 
-// Update updates a post
+// Update updates a post and its platform posts, so publish results such as
+// the platform post ID are persisted
 func (r *SocialPostRepository) Update(ctx context.Context, post *social.ScheduledPost) error {
-	return r.db.WithContext(ctx).Save(post).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(post).Error; err != nil {
+			return err
+		}
+		return savePlatformPosts(tx, post)
+	})
+}
+
+// RecordOptimalTime stores the time picked for one account of a post and
+// moves the post's scheduled time to the earliest picked so far. Only those
+// columns are written, under a lock on the post, so accounts resolving at
+// once neither overwrite each other nor the post's platform statuses.
+func (r *SocialPostRepository) RecordOptimalTime(ctx context.Context, postID, accountID string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var post social.ScheduledPost
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&post, "id = ?", postID).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&social.PlatformPost{}).
+			Where("scheduled_post_id = ? AND account_id = ?", postID, accountID).
+			Update("metadata", gorm.Expr("COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('optimalAt', ?::text)", at.Format(time.RFC3339))).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&social.ScheduledPost{}).
+			Where("id = ?", postID).
+			Update("scheduled_at", gorm.Expr("(SELECT MIN((metadata->>'optimalAt')::timestamptz) FROM platform_posts WHERE scheduled_post_id = ? AND metadata->>'optimalAt' IS NOT NULL)", postID)).Error
+	})
+}
+
+// GetPublishedPlatformPosts gets every platform post that went live, for analytics sync
+func (r *SocialPostRepository) GetPublishedPlatformPosts(ctx context.Context) ([]*social.PlatformPost, error) {
+	var platformPosts []*social.PlatformPost
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND platform_post_id <> ''", social.PostStatusPublished).
+		Find(&platformPosts).Error
+	return platformPosts, err
+}
+
+func savePlatformPosts(tx *gorm.DB, post *social.ScheduledPost) error {
+	for i := range post.Platforms {
+		platformPost := &post.Platforms[i]
+		if platformPost.ID == "" {
+			platformPost.ID = uuid.New().String()
+		}
+		platformPost.ScheduledPostID = post.ID
+		if err := tx.Save(platformPost).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // UpdateStatus updates post status
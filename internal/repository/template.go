@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"renderowl-api/internal/domain"
 )
@@ -38,6 +39,11 @@ type TemplateModel struct {
 	Version     int            `gorm:"default:1"`
 	IsActive    bool           `gorm:"default:true;index"`
 	Tags        TagsJSON       `gorm:"type:jsonb"`
+	// Rendered preview montage, valid while PreviewVersion matches Version
+	Preview        []byte `gorm:"type:bytea"`
+	PreviewVersion int    `gorm:"default:0"`
+	// How many timelines have been made from the template
+	UsageCount int `gorm:"default:0;index"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -47,6 +53,18 @@ func (TemplateModel) TableName() string {
 	return "templates"
 }
 
+// TemplateFavoriteModel is a template a user pinned as a favorite
+type TemplateFavoriteModel struct {
+	UserID     string `gorm:"primaryKey"`
+	TemplateID string `gorm:"primaryKey;index"`
+	CreatedAt  time.Time
+}
+
+// TableName specifies the table name for TemplateFavoriteModel
+func (TemplateFavoriteModel) TableName() string {
+	return "template_favorites"
+}
+
 // ScenesJSON is a helper type for JSON serialization
 type ScenesJSON []domain.TemplateScene
 
@@ -102,9 +120,9 @@ func (r *TemplateRepository) Create(template *domain.Template) error {
 // GetByID retrieves a template by ID
 func (r *TemplateRepository) GetByID(id string) (*domain.Template, error) {
 	var model TemplateModel
-	if err := r.db.First(&model, "id = ?", id).Error; err != nil {
+	if err := r.db.Omit("preview").First(&model, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("template not found")
+			return nil, domain.ErrTemplateNotFound
 		}
 		return nil, err
 	}
@@ -113,27 +131,20 @@ func (r *TemplateRepository) GetByID(id string) (*domain.Template, error) {
 
 // List retrieves all templates with optional filtering
 func (r *TemplateRepository) List(filter domain.TemplateFilter) ([]*domain.Template, error) {
-	query := r.db.Where("is_active = ?", true)
-
-	if filter.Category != "" {
-		query = query.Where("category = ?", filter.Category)
-	}
-
-	if filter.Search != "" {
-		search := "%" + filter.Search + "%"
-		query = query.Where(
-			"name ILIKE ? OR description ILIKE ? OR tags @> ?",
-			search, search, "[\""+filter.Search+"\"]",
-		)
-	}
+	query := r.filtered(filter)
 
 	limit := filter.Limit
 	if limit == 0 {
 		limit = 50
 	}
 
+	order := "popularity DESC, created_at DESC"
+	if filter.Sort == domain.TemplateSortPopular {
+		order = "usage_count DESC, " + order
+	}
+
 	var models []TemplateModel
-	if err := query.Order("popularity DESC, created_at DESC").
+	if err := query.Omit("preview").Order(order).
 		Limit(limit).
 		Offset(filter.Offset).
 		Find(&models).Error; err != nil {
@@ -144,9 +155,98 @@ func (r *TemplateRepository) List(filter domain.TemplateFilter) ([]*domain.Templ
 	for i, m := range models {
 		templates[i] = fromTemplateModel(&m)
 	}
+	if filter.UserID != "" {
+		if err := r.markFavorites(filter.UserID, templates); err != nil {
+			return nil, err
+		}
+	}
 	return templates, nil
 }
 
+// markFavorites sets Favorite on the templates the user pinned
+func (r *TemplateRepository) markFavorites(userID string, templates []*domain.Template) error {
+	if len(templates) == 0 {
+		return nil
+	}
+	ids := make([]string, len(templates))
+	for i, template := range templates {
+		ids[i] = template.ID
+	}
+
+	var favorites []string
+	if err := r.db.Model(&TemplateFavoriteModel{}).
+		Where("user_id = ? AND template_id IN ?", userID, ids).
+		Pluck("template_id", &favorites).Error; err != nil {
+		return err
+	}
+	favorite := make(map[string]bool, len(favorites))
+	for _, id := range favorites {
+		favorite[id] = true
+	}
+	for _, template := range templates {
+		template.Favorite = favorite[template.ID]
+	}
+	return nil
+}
+
+// ListWithCount retrieves a page of templates along with how many templates
+// match the filter in total
+func (r *TemplateRepository) ListWithCount(filter domain.TemplateFilter) ([]*domain.Template, int64, error) {
+	var total int64
+	if err := r.filtered(filter).Model(&TemplateModel{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	templates, err := r.List(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return templates, total, nil
+}
+
+// filtered returns a query for the active templates matching the filter's
+// category and search
+func (r *TemplateRepository) filtered(filter domain.TemplateFilter) *gorm.DB {
+	query := r.db.Where("is_active = ?", true)
+
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+
+	if filter.Search != "" {
+		search := "%" + filter.Search + "%"
+		query = query.Where(
+			"name ILIKE ? OR description ILIKE ? OR tags @> ?",
+			search, search, "[\""+filter.Search+"\"]",
+		)
+	}
+	if filter.FavoritesOnly {
+		favorites := r.db.Model(&TemplateFavoriteModel{}).Select("template_id").Where("user_id = ?", filter.UserID)
+		query = query.Where("id IN (?)", favorites)
+	}
+	return query
+}
+
+// IncrementUsage counts one more timeline made from a template
+func (r *TemplateRepository) IncrementUsage(id string) error {
+	return r.db.Model(&TemplateModel{}).
+		Where("id = ?", id).
+		UpdateColumn("usage_count", gorm.Expr("usage_count + 1")).Error
+}
+
+// AddFavorite pins a template for a user; pinning it again does nothing
+func (r *TemplateRepository) AddFavorite(userID, templateID string) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&TemplateFavoriteModel{
+		UserID:     userID,
+		TemplateID: templateID,
+	}).Error
+}
+
+// RemoveFavorite unpins a template for a user
+func (r *TemplateRepository) RemoveFavorite(userID, templateID string) error {
+	return r.db.Where("user_id = ? AND template_id = ?", userID, templateID).
+		Delete(&TemplateFavoriteModel{}).Error
+}
+
 // ListCategories retrieves all unique categories
 func (r *TemplateRepository) ListCategories() ([]string, error) {
 	var categories []string
@@ -163,7 +263,23 @@ func (r *TemplateRepository) ListCategories() ([]string, error) {
 func (r *TemplateRepository) Update(template *domain.Template) error {
 	model := toTemplateModel(template)
 	model.UpdatedAt = time.Now()
-	return r.db.Save(model).Error
+	return r.db.Omit("preview", "preview_version", "usage_count").Save(model).Error
+}
+
+// GetPreview retrieves a template's cached preview and the version it was rendered from
+func (r *TemplateRepository) GetPreview(id string) ([]byte, int, error) {
+	var model TemplateModel
+	if err := r.db.Select("preview", "preview_version").First(&model, "id = ?", id).Error; err != nil {
+		return nil, 0, err
+	}
+	return model.Preview, model.PreviewVersion, nil
+}
+
+// SavePreview caches a template's rendered preview
+func (r *TemplateRepository) SavePreview(id string, preview []byte, version int) error {
+	return r.db.Model(&TemplateModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"preview": preview, "preview_version": version}).Error
 }
 
 // Delete soft-deletes a template (marks as inactive)
@@ -193,11 +309,11 @@ func (r *TemplateRepository) SeedDefaultTemplates() error {
 		} else {
 			// Template exists, update version if needed
 			var existing TemplateModel
-			r.db.First(&existing, "id = ?", template.ID)
+			r.db.Omit("preview").First(&existing, "id = ?", template.ID)
 			if existing.Version < template.Version {
 				model := toTemplateModel(template)
 				model.UpdatedAt = time.Now()
-				r.db.Save(model)
+				r.db.Omit("preview", "preview_version", "usage_count").Save(model)
 			}
 		}
 	}
@@ -256,5 +372,6 @@ func fromTemplateModel(m *TemplateModel) *domain.Template {
 		Tags:        []string(m.Tags),
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
+		UsageCount:  m.UsageCount,
 	}
 }
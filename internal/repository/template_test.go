@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain"
+)
+
+// dryRunDB returns a database that builds statements without running them,
+// and the SQL of each query it was asked to run
+func dryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("open dry run database: %v", err)
+	}
+	var statements []string
+	record := func(tx *gorm.DB) { statements = append(statements, tx.Statement.SQL.String()) }
+	for _, processor := range []interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}{
+		db.Callback().Query().After("gorm:query"),
+		db.Callback().Create().After("gorm:create"),
+		db.Callback().Update().After("gorm:update"),
+	} {
+		if err := processor.Register("test:record_sql", record); err != nil {
+			t.Fatalf("record dry run statements: %v", err)
+		}
+	}
+	return db, &statements
+}
+
+func TestTemplateReadsLeaveThePreviewUnloaded(t *testing.T) {
+	db, statements := dryRunDB(t)
+	r := NewTemplateRepository(db)
+
+	if _, err := r.GetByID("template-1"); err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if _, err := r.List(domain.TemplateFilter{}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	if len(*statements) != 2 {
+		t.Fatalf("ran %d queries, want 2: %q", len(*statements), *statements)
+	}
+	for _, statement := range *statements {
+		if strings.Contains(statement, `"templates"."preview"`) {
+			t.Errorf("query loads the preview montage: %s", statement)
+		}
+	}
+}
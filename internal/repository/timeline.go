@@ -28,6 +28,44 @@ func (r *TimelineRepository) Create(timeline *domain.Timeline) error {
 	return nil
 }
 
+// CreateWithTracks creates a timeline along with its tracks and their clips
+// in a single transaction, giving each of them a new ID
+func (r *TimelineRepository) CreateWithTracks(timeline *domain.Timeline) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		model := toTimelineModel(timeline)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		for i := range timeline.Tracks {
+			track := &timeline.Tracks[i]
+			track.ID = ""
+			track.TimelineID = model.ID
+			trackModel := toTrackModel(track)
+			if err := tx.Create(trackModel).Error; err != nil {
+				return err
+			}
+			for j := range track.Clips {
+				clip := &track.Clips[j]
+				clip.ID = ""
+				clip.TimelineID = model.ID
+				clip.TrackID = trackModel.ID
+				clipModel := toClipModel(clip)
+				if err := tx.Create(clipModel).Error; err != nil {
+					return err
+				}
+				*clip = *fromClipModel(clipModel)
+			}
+			clips := track.Clips
+			*track = *fromTrackModel(trackModel)
+			track.Clips = clips
+		}
+		tracks := timeline.Tracks
+		*timeline = *fromTimelineModel(model)
+		timeline.Tracks = tracks
+		return nil
+	})
+}
+
 // GetByID retrieves a timeline by ID
 func (r *TimelineRepository) GetByID(id string) (*domain.Timeline, error) {
 	var model TimelineModel
@@ -66,6 +104,20 @@ func (r *TimelineRepository) ListByUser(userID string, limit, offset int) ([]*do
 	return timelines, nil
 }
 
+// ListByUserWithCount lists a page of a user's timelines along with how
+// many timelines they have in total
+func (r *TimelineRepository) ListByUserWithCount(userID string, limit, offset int) ([]*domain.Timeline, int64, error) {
+	var total int64
+	if err := r.db.Model(&TimelineModel{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	timelines, err := r.ListByUser(userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return timelines, total, nil
+}
+
 // Update updates a timeline
 func (r *TimelineRepository) Update(timeline *domain.Timeline) error {
 	model := toTimelineModel(timeline)
@@ -114,27 +166,35 @@ func fromTimelineModel(m *TimelineModel) *domain.Timeline {
 			Order:      trackModel.Order,
 			Muted:      trackModel.Muted,
 			Solo:       trackModel.Solo,
+			Role:       trackModel.Role,
+			Ducking:    trackDucking(&trackModel),
 		}
 
 		for _, clipModel := range trackModel.Clips {
 			clip := domain.Clip{
-				ID:          clipModel.ID,
-				TimelineID:  clipModel.TimelineID,
-				TrackID:     clipModel.TrackID,
-				Name:        clipModel.Name,
-				Type:        clipModel.Type,
-				SourceURL:   clipModel.SourceURL,
-				StartTime:   clipModel.StartTime,
-				EndTime:     clipModel.EndTime,
-				Duration:    clipModel.Duration,
-				TrimStart:   clipModel.TrimStart,
-				TrimEnd:     clipModel.TrimEnd,
-				PositionX:   clipModel.PositionX,
-				PositionY:   clipModel.PositionY,
-				Scale:       clipModel.Scale,
-				Rotation:    clipModel.Rotation,
-				Opacity:     clipModel.Opacity,
-				TextContent: clipModel.TextContent,
+				ID:           clipModel.ID,
+				TimelineID:   clipModel.TimelineID,
+				TrackID:      clipModel.TrackID,
+				Name:         clipModel.Name,
+				Type:         clipModel.Type,
+				SourceURL:    clipModel.SourceURL,
+				StartTime:    clipModel.StartTime,
+				EndTime:      clipModel.EndTime,
+				Duration:     clipModel.Duration,
+				TrimStart:    clipModel.TrimStart,
+				TrimEnd:      clipModel.TrimEnd,
+				SourceStart:  clipModel.SourceStart,
+				SourceEnd:    clipModel.SourceEnd,
+				SourceLength: clipModel.SourceLength,
+				PositionX:    clipModel.PositionX,
+				PositionY:    clipModel.PositionY,
+				Scale:        clipModel.Scale,
+				Rotation:     clipModel.Rotation,
+				Opacity:      clipModel.Opacity,
+				Volume:       clipModel.Volume,
+				FadeIn:       clipModel.FadeIn,
+				FadeOut:      clipModel.FadeOut,
+				TextContent:  clipModel.TextContent,
 			}
 			if clipModel.TextStyle != nil {
 				clip.TextStyle = &domain.Style{
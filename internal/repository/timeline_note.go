@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain"
+)
+
+// TimelineNoteRepository stores timeline notes and activity
+type TimelineNoteRepository struct {
+	db *gorm.DB
+}
+
+// NewTimelineNoteRepository creates a new timeline note repository
+func NewTimelineNoteRepository(db *gorm.DB) *TimelineNoteRepository {
+	return &TimelineNoteRepository{db: db}
+}
+
+// CreateNote records a new note
+func (r *TimelineNoteRepository) CreateNote(ctx context.Context, note *domain.TimelineNote) error {
+	return r.db.WithContext(ctx).Create(note).Error
+}
+
+// ListNotes gets a page of a timeline's notes, newest first, and the total
+func (r *TimelineNoteRepository) ListNotes(ctx context.Context, timelineID string, limit, offset int) ([]*domain.TimelineNote, int64, error) {
+	db := r.db.WithContext(ctx).Model(&domain.TimelineNote{}).Where("timeline_id = ?", timelineID)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var notes []*domain.TimelineNote
+	err := db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&notes).Error
+	return notes, total, err
+}
+
+// DeleteNotes deletes all of a timeline's notes
+func (r *TimelineNoteRepository) DeleteNotes(ctx context.Context, timelineID string) error {
+	return r.db.WithContext(ctx).Where("timeline_id = ?", timelineID).Delete(&domain.TimelineNote{}).Error
+}
+
+// RecordActivity adds an entry to a timeline's activity log
+func (r *TimelineNoteRepository) RecordActivity(ctx context.Context, activity *domain.TimelineActivity) error {
+	return r.db.WithContext(ctx).Create(activity).Error
+}
+
+// ListActivity gets a timeline's latest activity, newest first
+func (r *TimelineNoteRepository) ListActivity(ctx context.Context, timelineID string, limit int) ([]domain.TimelineActivity, error) {
+	var activity []domain.TimelineActivity
+	err := r.db.WithContext(ctx).
+		Where("timeline_id = ?", timelineID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&activity).Error
+	return activity, err
+}
@@ -105,7 +105,7 @@ func (r *TrackRepository) ToggleSolo(id string) (*domain.Track, error) {
 
 // Helper functions
 func toTrackModel(t *domain.Track) *TrackModel {
-	return &TrackModel{
+	model := &TrackModel{
 		ID:         t.ID,
 		TimelineID: t.TimelineID,
 		Name:       t.Name,
@@ -113,7 +113,14 @@ func toTrackModel(t *domain.Track) *TrackModel {
 		Order:      t.Order,
 		Muted:      t.Muted,
 		Solo:       t.Solo,
+		Role:       t.Role,
 	}
+	if t.Ducking != nil {
+		model.DuckAmountDB = t.Ducking.AmountDB
+		model.DuckAttackMs = t.Ducking.AttackMs
+		model.DuckReleaseMs = t.Ducking.ReleaseMs
+	}
+	return model
 }
 
 func fromTrackModel(m *TrackModel) *domain.Track {
@@ -125,5 +132,19 @@ func fromTrackModel(m *TrackModel) *domain.Track {
 		Order:      m.Order,
 		Muted:      m.Muted,
 		Solo:       m.Solo,
+		Role:       m.Role,
+		Ducking:    trackDucking(m),
+	}
+}
+
+// trackDucking returns a track model's ducking settings, or nil when ducking is off
+func trackDucking(m *TrackModel) *domain.Ducking {
+	if m.DuckAmountDB <= 0 {
+		return nil
+	}
+	return &domain.Ducking{
+		AmountDB:  m.DuckAmountDB,
+		AttackMs:  m.DuckAttackMs,
+		ReleaseMs: m.DuckReleaseMs,
 	}
 }
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain"
+)
+
+// UsageRepository stores provider usage events
+type UsageRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(db *gorm.DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// CreateUsageEvents records a batch of usage events
+func (r *UsageRepository) CreateUsageEvents(ctx context.Context, events []*domain.UsageEvent) error {
+	return r.db.WithContext(ctx).Create(events).Error
+}
+
+// SummarizeUsage totals a user's usage in [from, to) per provider and per
+// period, where period is a Postgres date_trunc unit such as "day"
+func (r *UsageRepository) SummarizeUsage(ctx context.Context, userID, period string, from, to time.Time) ([]domain.UsageTotal, error) {
+	var totals []domain.UsageTotal
+	err := r.db.WithContext(ctx).Model(&domain.UsageEvent{}).
+		Select(`date_trunc(?, created_at) AS period, provider, COUNT(*) AS calls,
+			COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+			COALESCE(SUM(completion_tokens), 0) AS completion_tokens,
+			COALESCE(SUM(characters), 0) AS characters,
+			COALESCE(SUM(images), 0) AS images,
+			COALESCE(SUM(estimated_cost), 0) AS estimated_cost`, period).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Group("1, 2").
+		Order("1, 2").
+		Scan(&totals).Error
+	return totals, err
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// UserDataRepository gathers and erases everything stored for a user across tables
+type UserDataRepository struct {
+	db *gorm.DB
+}
+
+// NewUserDataRepository creates a new user data repository
+func NewUserDataRepository(db *gorm.DB) *UserDataRepository {
+	return &UserDataRepository{db: db}
+}
+
+// UserDataExport is a complete copy of a user's stored data
+type UserDataExport struct {
+	UserID         string                  `json:"userId"`
+	ExportedAt     time.Time               `json:"exportedAt"`
+	Timelines      []*domain.Timeline      `json:"timelines"`
+	TimelineNotes  []*domain.TimelineNote  `json:"timelineNotes"`
+	Batches        []*domain.Batch         `json:"batches"`
+	BatchTemplates []*domain.BatchTemplate `json:"batchTemplates"`
+	SocialAccounts []*social.SocialAccount `json:"socialAccounts"`
+	ScheduledPosts []*social.ScheduledPost `json:"scheduledPosts"`
+	Campaigns      []*social.Campaign      `json:"campaigns"`
+	ClonedVoices   []*domain.ClonedVoice   `json:"clonedVoices"`
+	ShareLinks     []*domain.ShareLink     `json:"shareLinks"`
+	Analytics      *UserAnalyticsExport    `json:"analytics"`
+}
+
+// UserAnalyticsExport holds the analytics rows tied to a user or their videos
+type UserAnalyticsExport struct {
+	Views            []domain.AnalyticsView        `json:"views"`
+	HourlyViews      []domain.AnalyticsViewHourly  `json:"hourlyViews"`
+	Engagement       []domain.AnalyticsEngagement  `json:"engagement"`
+	VideoPerformance []domain.VideoPerformance     `json:"videoPerformance"`
+	Revenue          []domain.Revenue              `json:"revenue"`
+	Alerts           []domain.AnalyticsAlert       `json:"alerts"`
+	AlertFirings     []domain.AnalyticsAlertFiring `json:"alertFirings"`
+	WinningContent   *WinningContentExport         `json:"winningContent,omitempty"`
+	Suggestions      []SuggestionExport            `json:"suggestions"`
+}
+
+// WinningContentExport is the user's cached winning content analysis
+type WinningContentExport struct {
+	Analysis   json.RawMessage `json:"analysis"`
+	ComputedAt time.Time       `json:"computedAt"`
+}
+
+// SuggestionExport is an optimizer suggestion with what the user did with it
+type SuggestionExport struct {
+	ID              string          `json:"id"`
+	VideoID         string          `json:"videoId"`
+	Type            string          `json:"type"`
+	Suggestion      json.RawMessage `json:"suggestion"`
+	Dismissed       bool            `json:"dismissed"`
+	DismissedAt     *time.Time      `json:"dismissedAt,omitempty"`
+	Feedback        int             `json:"feedback"`
+	Applied         bool            `json:"applied"`
+	AppliedAt       *time.Time      `json:"appliedAt,omitempty"`
+	LastSuggestedAt *time.Time      `json:"lastSuggestedAt,omitempty"`
+	CreatedAt       time.Time       `json:"createdAt"`
+}
+
+// Export collects all data stored for a user. OAuth tokens and webhook
+// signing secrets are never included.
+func (r *UserDataRepository) Export(ctx context.Context, userID string) (*UserDataExport, error) {
+	db := r.db.WithContext(ctx)
+	export := &UserDataExport{
+		UserID:     userID,
+		ExportedAt: time.Now().UTC(),
+		Analytics:  &UserAnalyticsExport{},
+	}
+
+	var timelines []TimelineModel
+	if err := db.Preload("Tracks.Clips").Where("user_id = ?", userID).Find(&timelines).Error; err != nil {
+		return nil, err
+	}
+	for i := range timelines {
+		export.Timelines = append(export.Timelines, fromTimelineModel(&timelines[i]))
+	}
+
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&export.TimelineNotes).Error; err != nil {
+		return nil, err
+	}
+
+	batchRepo := &BatchRepository{db: db}
+	var batches []BatchModel
+	if err := db.Preload("Videos").Where("user_id = ?", userID).Find(&batches).Error; err != nil {
+		return nil, err
+	}
+	for i := range batches {
+		export.Batches = append(export.Batches, batchRepo.toDomain(&batches[i]))
+	}
+
+	templateRepo := &BatchTemplateRepository{db: db}
+	var templates []BatchTemplateModel
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	for i := range templates {
+		export.BatchTemplates = append(export.BatchTemplates, templateRepo.toDomain(&templates[i]))
+	}
+
+	if err := db.Where("user_id = ?", userID).Find(&export.SocialAccounts).Error; err != nil {
+		return nil, err
+	}
+	for _, account := range export.SocialAccounts {
+		account.AccessToken = ""
+		account.RefreshToken = ""
+		account.Metadata = social.JSON(redactTokens(account.Metadata))
+	}
+
+	if err := db.Where("user_id = ?", userID).Find(&export.ScheduledPosts).Error; err != nil {
+		return nil, err
+	}
+	for _, post := range export.ScheduledPosts {
+		if err := db.Where("scheduled_post_id = ?", post.ID).Find(&post.Platforms).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&export.Campaigns).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Where("user_id = ?", userID).Find(&export.ClonedVoices).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&export.ShareLinks).Error; err != nil {
+		return nil, err
+	}
+
+	videoIDs, err := r.videoIDs(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ? OR video_id IN ?", userID, videoIDs).Find(&export.Analytics.Views).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ? OR video_id IN ?", userID, videoIDs).Find(&export.Analytics.HourlyViews).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("video_id IN ?", videoIDs).Find(&export.Analytics.Engagement).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Find(&export.Analytics.VideoPerformance).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Find(&export.Analytics.Revenue).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Find(&export.Analytics.Alerts).Error; err != nil {
+		return nil, err
+	}
+	for i := range export.Analytics.Alerts {
+		export.Analytics.Alerts[i].Secret = ""
+	}
+	alertIDs := db.Model(&domain.AnalyticsAlert{}).Select("id").Where("user_id = ?", userID)
+	if err := db.Where("alert_id IN (?)", alertIDs).Find(&export.Analytics.AlertFirings).Error; err != nil {
+		return nil, err
+	}
+
+	var caches []domain.WinningContentCache
+	if err := db.Where("user_id = ?", userID).Find(&caches).Error; err != nil {
+		return nil, err
+	}
+	for _, cache := range caches {
+		export.Analytics.WinningContent = &WinningContentExport{Analysis: rawJSON(cache.Analysis), ComputedAt: cache.ComputedAt}
+	}
+	var suggestions []domain.OptimizationSuggestionRecord
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&suggestions).Error; err != nil {
+		return nil, err
+	}
+	for _, record := range suggestions {
+		export.Analytics.Suggestions = append(export.Analytics.Suggestions, SuggestionExport{
+			ID:              record.ID,
+			VideoID:         record.VideoID,
+			Type:            record.Type,
+			Suggestion:      rawJSON(record.Suggestion),
+			Dismissed:       record.Dismissed,
+			DismissedAt:     record.DismissedAt,
+			Feedback:        record.Feedback,
+			Applied:         record.Applied,
+			AppliedAt:       record.AppliedAt,
+			LastSuggestedAt: record.LastSuggestedAt,
+			CreatedAt:       record.CreatedAt,
+		})
+	}
+
+	return export, nil
+}
+
+// Delete erases a user's data in a single transaction. Revenue records are
+// kept for accounting but anonymized.
+func (r *UserDataRepository) Delete(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		videoIDs, err := r.videoIDs(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		timelineIDs := tx.Model(&TimelineModel{}).Select("id").Where("user_id = ?", userID)
+		batchIDs := tx.Model(&BatchModel{}).Select("id").Where("user_id = ?", userID)
+		postIDs := tx.Model(&social.ScheduledPost{}).Select("id").Where("user_id = ?", userID)
+		platformPostIDs := tx.Model(&social.PlatformPost{}).Select("platform_post_id").Where("scheduled_post_id IN (?)", postIDs)
+		alertIDs := tx.Model(&domain.AnalyticsAlert{}).Select("id").Where("user_id = ?", userID)
+
+		steps := []func() error{
+			// Editor data
+			func() error { return tx.Where("timeline_id IN (?)", timelineIDs).Delete(&ClipModel{}).Error },
+			func() error { return tx.Where("timeline_id IN (?)", timelineIDs).Delete(&TrackModel{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&TimelineModel{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&domain.TimelineNote{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&domain.TimelineActivity{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&TemplateFavoriteModel{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&domain.PromptTemplate{}).Error },
+			// Voice clones, already deleted from the provider
+			func() error { return tx.Where("user_id = ?", userID).Delete(&domain.ClonedVoice{}).Error },
+			// Content factory
+			func() error { return tx.Where("batch_id IN (?)", batchIDs).Delete(&BatchVideoModel{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&BatchModel{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&BatchTemplateModel{}).Error },
+			// Social
+			func() error {
+				return tx.Where("post_id IN (?) OR post_id IN (?)", postIDs, platformPostIDs).Delete(&social.AnalyticsData{}).Error
+			},
+			func() error {
+				return tx.Where("scheduled_post_id IN (?)", postIDs).Delete(&social.PlatformPost{}).Error
+			},
+			func() error { return tx.Where("post_id IN (?)", postIDs).Delete(&social.PostOccurrence{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&social.ScheduledPost{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&social.Campaign{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&social.SocialAccount{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&social.VideoFingerprint{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&social.AccountMetricsSnapshot{}).Error },
+			// Analytics
+			func() error {
+				return tx.Where("user_id = ? OR video_id IN ?", userID, videoIDs).Delete(&domain.AnalyticsView{}).Error
+			},
+			func() error {
+				return tx.Where("user_id = ? OR video_id IN ?", userID, videoIDs).Delete(&domain.AnalyticsViewHourly{}).Error
+			},
+			func() error { return tx.Where("video_id IN ?", videoIDs).Delete(&domain.AnalyticsEngagement{}).Error },
+			func() error { return tx.Where("video_id IN ?", videoIDs).Delete(&domain.WebhookEvent{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&domain.VideoPerformance{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&domain.VariationJob{}).Error },
+			func() error { return tx.Where("user_id = ?", userID).Delete(&domain.WinningContentCache{}).Error },
+			func() error {
+				return tx.Where("user_id = ?", userID).Delete(&domain.OptimizationSuggestionRecord{}).Error
+			},
+			// Shared links stop working once erased
+			func() error { return tx.Where("user_id = ?", userID).Delete(&domain.ShareLink{}).Error },
+			func() error {
+				return tx.Where("alert_id IN (?)", alertIDs).Delete(&domain.AnalyticsAlertFiring{}).Error
+			},
+			func() error { return tx.Where("user_id = ?", userID).Delete(&domain.AnalyticsAlert{}).Error },
+			func() error {
+				return tx.Model(&domain.Revenue{}).Where("user_id = ?", userID).Update("user_id", "").Error
+			},
+			// Provider usage stays on record for cost accounting, detached from the user
+			func() error {
+				return tx.Model(&domain.UsageEvent{}).Where("user_id = ?", userID).Update("user_id", "").Error
+			},
+		}
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// videoIDs returns the IDs of every video owned by the user
+func (r *UserDataRepository) videoIDs(db *gorm.DB, userID string) ([]string, error) {
+	var timelineIDs, performanceIDs []string
+	if err := db.Model(&TimelineModel{}).Where("user_id = ?", userID).Pluck("id", &timelineIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&domain.VideoPerformance{}).Where("user_id = ?", userID).Pluck("video_id", &performanceIDs).Error; err != nil {
+		return nil, err
+	}
+
+	// An empty IN list is invalid SQL, so always include a placeholder
+	return append(append([]string{""}, timelineIDs...), performanceIDs...), nil
+}
+
+// rawJSON passes stored JSON through to an export as it is
+func rawJSON(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.RawMessage(data)
+}
+
+// redactTokens blanks any metadata value whose key looks like a credential
+func redactTokens(data map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if strings.Contains(strings.ToLower(key), "token") || strings.Contains(strings.ToLower(key), "secret") {
+			redacted[key] = "[redacted]"
+			continue
+		}
+		redacted[key] = redactValue(value)
+	}
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactTokens(v)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = redactValue(v[i])
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = redactTokens(v[i])
+		}
+		return out
+	}
+	return value
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain"
+)
+
+// VariationJobRepository stores variations runs so their results can be
+// partly regenerated
+type VariationJobRepository struct {
+	db *gorm.DB
+}
+
+// NewVariationJobRepository creates a new variation job repository
+func NewVariationJobRepository(db *gorm.DB) *VariationJobRepository {
+	return &VariationJobRepository{db: db}
+}
+
+// CreateVariationJob records a variations run
+func (r *VariationJobRepository) CreateVariationJob(ctx context.Context, job *domain.VariationJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// GetVariationJob gets one of a user's variations runs
+func (r *VariationJobRepository) GetVariationJob(ctx context.Context, userID, id string) (*domain.VariationJob, error) {
+	var job domain.VariationJob
+	err := r.db.WithContext(ctx).First(&job, "id = ? AND user_id = ?", id, userID).Error
+	return &job, err
+}
+
+// UpdateVariationJobResult saves a variations run's updated result
+func (r *VariationJobRepository) UpdateVariationJobResult(ctx context.Context, job *domain.VariationJob) error {
+	return r.db.WithContext(ctx).Model(job).Update("result", job.Result).Error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain/social"
+)
+
+// VideoFingerprintRepository stores the fingerprints of videos posted to
+// social accounts
+type VideoFingerprintRepository struct {
+	db *gorm.DB
+}
+
+// NewVideoFingerprintRepository creates a new video fingerprint repository
+func NewVideoFingerprintRepository(db *gorm.DB) *VideoFingerprintRepository {
+	return &VideoFingerprintRepository{db: db}
+}
+
+// Create records a video's fingerprint
+func (r *VideoFingerprintRepository) Create(ctx context.Context, fingerprint *social.VideoFingerprint) error {
+	if fingerprint.ID == "" {
+		fingerprint.ID = uuid.New().String()
+	}
+	return r.db.WithContext(ctx).Create(fingerprint).Error
+}
+
+// ListRecent gets the fingerprints of videos posted to an account since a
+// time, including those scheduled for later, newest first
+func (r *VideoFingerprintRepository) ListRecent(ctx context.Context, accountID string, since time.Time) ([]*social.VideoFingerprint, error) {
+	var fingerprints []*social.VideoFingerprint
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND posted_at >= ?", accountID, since).
+		Order("posted_at DESC").
+		Find(&fingerprints).Error
+	return fingerprints, err
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"renderowl-api/internal/domain"
+)
+
+// VoiceRepository stores which users own cloned voices
+type VoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewVoiceRepository creates a new voice repository
+func NewVoiceRepository(db *gorm.DB) *VoiceRepository {
+	return &VoiceRepository{db: db}
+}
+
+// CreateClonedVoice records a user's new voice clone
+func (r *VoiceRepository) CreateClonedVoice(ctx context.Context, voice *domain.ClonedVoice) error {
+	return r.db.WithContext(ctx).Create(voice).Error
+}
+
+// ListClonedVoices gets every recorded voice clone, of all users
+func (r *VoiceRepository) ListClonedVoices(ctx context.Context) ([]*domain.ClonedVoice, error) {
+	var voices []*domain.ClonedVoice
+	err := r.db.WithContext(ctx).Find(&voices).Error
+	return voices, err
+}
+
+// ListUserClonedVoices gets a user's voice clones
+func (r *VoiceRepository) ListUserClonedVoices(ctx context.Context, userID string) ([]*domain.ClonedVoice, error) {
+	var voices []*domain.ClonedVoice
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&voices).Error
+	return voices, err
+}
+
+// GetClonedVoice gets one of a user's voice clones by its provider voice ID
+func (r *VoiceRepository) GetClonedVoice(ctx context.Context, userID, voiceID string) (*domain.ClonedVoice, error) {
+	var voice domain.ClonedVoice
+	err := r.db.WithContext(ctx).Where("voice_id = ? AND user_id = ?", voiceID, userID).First(&voice).Error
+	return &voice, err
+}
+
+// DeleteClonedVoice forgets a voice clone
+func (r *VoiceRepository) DeleteClonedVoice(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.ClonedVoice{}).Error
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// heartbeatKey holds the Unix time of the last processing tick
+const heartbeatKey = "scheduler:heartbeat"
+
+// heartbeatTimeout is how long without a tick before the scheduler counts as
+// down; it ticks every 5 seconds
+const heartbeatTimeout = 30 * time.Second
+
+// Health reports whether the scheduler is processing and how far behind it is
+type Health struct {
+	Alive            bool       `json:"alive"`
+	LastHeartbeat    *time.Time `json:"lastHeartbeat,omitempty"`
+	Delayed          int64      `json:"delayed"`
+	Due              int64      `json:"due"` // delayed jobs past their run time
+	Active           int64      `json:"active"`
+	OldestDueSeconds float64    `json:"oldestDueSeconds"`
+}
+
+// heartbeat records that the scheduler is processing
+func (s *Scheduler) heartbeat(ctx context.Context) {
+	s.client.Set(ctx, heartbeatKey, time.Now().Unix(), 0)
+}
+
+// Health returns the scheduler's liveness and backlog. Every instance shares
+// the heartbeat, so it is alive while any instance is processing.
+func (s *Scheduler) Health(ctx context.Context) (*Health, error) {
+	health := &Health{}
+
+	beat, err := s.client.Get(ctx, heartbeatKey).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	if err == nil {
+		if unix, err := strconv.ParseInt(beat, 10, 64); err == nil {
+			last := time.Unix(unix, 0).UTC()
+			health.LastHeartbeat = &last
+			health.Alive = time.Since(last) < heartbeatTimeout
+		}
+	}
+
+	if health.Delayed, err = s.client.ZCard(ctx, "scheduler:delayed").Result(); err != nil {
+		return nil, err
+	}
+	if health.Active, err = s.client.LLen(ctx, "scheduler:active").Result(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dueBy := fmt.Sprintf("%d", now.Unix())
+	if health.Due, err = s.client.ZCount(ctx, "scheduler:delayed", "0", dueBy).Result(); err != nil {
+		return nil, err
+	}
+	oldest, err := s.client.ZRangeByScoreWithScores(ctx, "scheduler:delayed", &redis.ZRangeBy{Min: "0", Max: dueBy, Count: 1}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(oldest) > 0 {
+		health.OldestDueSeconds = now.Sub(time.Unix(int64(oldest[0].Score), 0)).Seconds()
+	}
+
+	return health, nil
+}
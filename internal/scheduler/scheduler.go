@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -44,6 +45,7 @@ type Scheduler struct {
 	client   *redis.Client
 	handlers map[string]JobHandler
 	quit     chan bool
+	inFlight sync.WaitGroup
 }
 
 // NewScheduler creates a new scheduler instance
@@ -66,6 +68,12 @@ func (s *Scheduler) RegisterHandler(name string, handler JobHandler) {
 	s.handlers[name] = handler
 }
 
+// HasHandler reports whether a handler is registered for a job type
+func (s *Scheduler) HasHandler(name string) bool {
+	_, ok := s.handlers[name]
+	return ok
+}
+
 // AddJob adds a job to the queue
 func (s *Scheduler) AddJob(ctx context.Context, job *Job) error {
 	if job.ID == "" {
@@ -95,14 +103,31 @@ func (s *Scheduler) AddJob(ctx context.Context, job *Job) error {
 	}).Err()
 }
 
-// AddRecurringJob adds a recurring job
+// AddRecurringJob adds a recurring job. A job already stored under the name
+// keeps its last run, so restarts do not push its next run back.
 func (s *Scheduler) AddRecurringJob(ctx context.Context, name string, data interface{}, rule *social.RecurringRule, handler JobHandler) error {
+	key := "scheduler:recurring:" + name
+	lastRun := time.Now()
+
+	existing, err := s.client.Get(ctx, key).Result()
+	switch {
+	case err == nil:
+		var stored struct {
+			LastRun time.Time `json:"last_run"`
+		}
+		if json.Unmarshal([]byte(existing), &stored) == nil && !stored.LastRun.IsZero() {
+			lastRun = stored.LastRun
+		}
+	case err != redis.Nil:
+		return err
+	}
+
 	// Store recurring job definition
 	recurringData := map[string]interface{}{
 		"name":     name,
 		"data":     data,
 		"rule":     rule,
-		"last_run": time.Now(),
+		"last_run": lastRun,
 	}
 
 	jsonData, err := json.Marshal(recurringData)
@@ -110,19 +135,23 @@ func (s *Scheduler) AddRecurringJob(ctx context.Context, name string, data inter
 		return err
 	}
 
-	return s.client.Set(ctx, "scheduler:recurring:"+name, jsonData, 0).Err()
+	return s.client.Set(ctx, key, jsonData, 0).Err()
 }
 
-// ProcessJobs starts processing jobs (blocking)
+// ProcessJobs starts processing jobs (blocking) until ctx is cancelled or Stop is called
 func (s *Scheduler) ProcessJobs(ctx context.Context) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
+	s.heartbeat(ctx)
 
 	for {
 		select {
+		case <-ctx.Done():
+			return
 		case <-s.quit:
 			return
 		case <-ticker.C:
+			s.heartbeat(ctx)
 			s.processPendingJobs(ctx)
 			s.processRecurringJobs(ctx)
 		}
@@ -134,6 +163,27 @@ func (s *Scheduler) Stop() {
 	close(s.quit)
 }
 
+// Wait blocks until in-flight jobs have finished or ctx expires
+func (s *Scheduler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close closes the underlying Redis connection
+func (s *Scheduler) Close() error {
+	return s.client.Close()
+}
+
 // GetJobStatus returns the status of a job
 func (s *Scheduler) GetJobStatus(ctx context.Context, jobID string) (*Job, error) {
 	// Check in delayed set
@@ -229,8 +279,12 @@ func (s *Scheduler) processPendingJobs(ctx context.Context) {
 		// Add to active queue
 		s.client.RPush(ctx, "scheduler:active", jobData)
 
-		// Process job
-		go s.executeJob(ctx, &job)
+		// Process job detached from ctx so a shutdown lets it finish instead of aborting mid-publish
+		s.inFlight.Add(1)
+		go func(job *Job) {
+			defer s.inFlight.Done()
+			s.executeJob(context.WithoutCancel(ctx), job)
+		}(&job)
 	}
 }
 
@@ -4,12 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"math/rand"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"renderowl-api/internal/domain"
 )
 
 // AISceneService handles AI-powered scene generation and image search
@@ -19,10 +27,23 @@ type AISceneService struct {
 	stabilityKey   string
 	unsplashKey    string
 	pexelsKey      string
-	openAIBaseURL  string
-	httpClient     *http.Client
+	openAIBaseURL    string
+	togetherBaseURL  string
+	stabilityBaseURL string
+	defaultImageSource ImageSource // DEFAULT_IMAGE_SOURCE; empty picks from the configured keys
+	httpClient         *http.Client
+	usage              *UsageService
+	prompts            *PromptTemplateService
+
+	// timeouts bounds each provider call; nil uses the defaults
+	timeouts aiTimeouts
+	// openAIAccount is who OpenAI calls are billed to
+	openAIAccount openAIAccount
 }
 
+// maxImageSeed is the largest seed accepted by the Stability API (2^32 - 1)
+const maxImageSeed = 4294967295
+
 // ImageSource represents the source of an image
 type ImageSource string
 
@@ -34,22 +55,41 @@ const (
 	SourceTogether      ImageSource = "together"
 )
 
+// defaultImageSourceOrder is the precedence for picking a default image
+// source from the configured keys: generated images first, then stock
+var defaultImageSourceOrder = []ImageSource{SourceDALLE, SourceStability, SourceTogether, SourceUnsplash, SourcePexels}
+
 // GenerateScenesRequest represents a scene generation request
 type GenerateScenesRequest struct {
 	ScriptID      string      `json:"script_id,omitempty"`
 	ScriptTitle   string      `json:"script_title,omitempty"`
-	Scenes        []SceneInfo `json:"scenes" binding:"required"`
+	Scenes        []SceneInfo `json:"scenes"`
+	Script         string     `json:"script,omitempty"`          // Narration to split into scenes when no scenes are given
+	TargetScenes   int        `json:"target_scenes,omitempty"`   // Scenes to split the script into; 0 picks a count from the duration
+	TargetDuration int        `json:"target_duration,omitempty"` // Seconds the split scenes should add up to
 	Style         string      `json:"style,omitempty"` // cinematic, animated, realistic, etc.
 	ImageSource   ImageSource `json:"image_source,omitempty"`
 	GenerateImages bool       `json:"generate_images,omitempty"`
+	NegativePrompt string     `json:"negative_prompt,omitempty"` // Stability/Together only
+	Seed           int64      `json:"seed,omitempty"`            // Stability/Together only; 0 picks a random seed
+	Language       string     `json:"language,omitempty"`        // ISO language code of the script; defaults to the request locale's
+	TargetAudience string     `json:"target_audience,omitempty"`
+	ImageCount     int        `json:"image_count,omitempty"`     // Stills per scene for a b-roll sequence; defaults to 1
+	Platform       string     `json:"platform,omitempty"`        // PlatformSpecs key; AI images are sized for its aspect ratio
+	StylePreset    string     `json:"style_preset,omitempty"`    // ImageStylePresets ID, applied to every scene's image prompt
+	SystemPrompt   string     `json:"system_prompt,omitempty"`   // Template replacing the built-in enhancement system prompt, see PromptTemplateService
 }
 
+// maxSceneImages caps ImageCount so one scene can't fan out into dozens of generations
+const maxSceneImages = 6
+
 // SceneInfo represents basic scene information for generation
 type SceneInfo struct {
 	Number      int      `json:"number"`
 	Title       string   `json:"title"`
 	Description string   `json:"description"`
 	Keywords    []string `json:"keywords,omitempty"`
+	Duration    int      `json:"duration,omitempty"` // in seconds
 }
 
 // GeneratedScene represents a fully generated scene
@@ -65,13 +105,25 @@ type GeneratedScene struct {
 	AltText       string      `json:"alt_text,omitempty"`
 	ColorPalette  []string    `json:"color_palette,omitempty"`
 	Mood          string      `json:"mood,omitempty"`
+	Seed          int64       `json:"seed,omitempty"`   // Seed used for AI image generation
+	Images        []string    `json:"images,omitempty"` // All stills for the scene, ImageURL first
+	ImageSize     string      `json:"image_size,omitempty"` // Generation size of AI images, e.g. 1024x1792
+
+	// Timing within the video, in seconds; StartTime follows from the
+	// durations of the scenes before it
+	Duration  int `json:"duration,omitempty"`
+	StartTime int `json:"start_time"`
 }
 
 // SceneGenerationResult represents the complete result
 type SceneGenerationResult struct {
-	ScriptID    string           `json:"script_id,omitempty"`
-	Scenes      []GeneratedScene `json:"scenes"`
-	TotalScenes int              `json:"total_scenes"`
+	ScriptID                string           `json:"script_id,omitempty"`
+	Scenes                  []GeneratedScene `json:"scenes"`
+	TotalScenes             int              `json:"total_scenes"`
+	ImageSource             ImageSource      `json:"image_source,omitempty"`
+	ImageSourceAutoSelected bool             `json:"image_source_auto_selected,omitempty"` // No source was requested, so the default was used
+	StylePreset             string           `json:"style_preset,omitempty"`
+	TotalDuration           int              `json:"total_duration,omitempty"` // in seconds
 }
 
 // NewAISceneService creates a new AI scene service
@@ -82,25 +134,107 @@ func NewAISceneService() *AISceneService {
 		stabilityKey:  os.Getenv("STABILITY_API_KEY"),
 		unsplashKey:   os.Getenv("UNSPLASH_ACCESS_KEY"),
 		pexelsKey:     os.Getenv("PEXELS_API_KEY"),
-		openAIBaseURL: getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		openAIBaseURL:    getBaseURL("OPENAI_BASE_URL", defaultOpenAIBaseURL),
+		togetherBaseURL:  getBaseURL("TOGETHER_BASE_URL", defaultTogetherBaseURL),
+		stabilityBaseURL: getBaseURL("STABILITY_BASE_URL", defaultStabilityBaseURL),
+		defaultImageSource: parseImageSource(os.Getenv("DEFAULT_IMAGE_SOURCE")),
 		httpClient: &http.Client{
 			Timeout: 120 * time.Second,
 		},
 	}
 }
 
+// SetUsage records the tokens and images each provider call uses
+func (s *AISceneService) SetUsage(usage *UsageService) {
+	s.usage = usage
+}
+
+// SetPromptTemplates lets users replace the built-in scene enhancement
+// system prompt with their own template
+func (s *AISceneService) SetPromptTemplates(prompts *PromptTemplateService) {
+	s.prompts = prompts
+}
+
+// parseImageSource reads the DEFAULT_IMAGE_SOURCE override, ignoring unknown sources
+func parseImageSource(value string) ImageSource {
+	if value == "" {
+		return ""
+	}
+	source := ImageSource(strings.ToLower(strings.TrimSpace(value)))
+	for _, known := range defaultImageSourceOrder {
+		if source == known {
+			return source
+		}
+	}
+	log.Printf("Warning: ignoring unknown DEFAULT_IMAGE_SOURCE %q", value)
+	return ""
+}
+
+// DefaultImageSource returns the image source used when a request doesn't
+// name one: DEFAULT_IMAGE_SOURCE if set, otherwise the first source with a
+// key configured, preferring generated images over stock photos
+func (s *AISceneService) DefaultImageSource() ImageSource {
+	if s.defaultImageSource != "" {
+		return s.defaultImageSource
+	}
+	configured := map[ImageSource]bool{
+		SourceDALLE:     s.openAIKey != "",
+		SourceStability: s.stabilityKey != "",
+		SourceTogether:  s.togetherKey != "",
+		SourceUnsplash:  s.unsplashKey != "",
+		SourcePexels:    s.pexelsKey != "",
+	}
+	for _, source := range defaultImageSourceOrder {
+		if configured[source] {
+			return source
+		}
+	}
+	return SourceUnsplash
+}
+
 // GenerateScenes generates enhanced scenes with images
 func (s *AISceneService) GenerateScenes(ctx context.Context, req *GenerateScenesRequest) (*SceneGenerationResult, error) {
 	if req.Style == "" {
 		req.Style = "cinematic"
 	}
-	if req.ImageSource == "" {
-		req.ImageSource = SourceUnsplash
+	autoSelected := req.ImageSource == ""
+	if autoSelected {
+		req.ImageSource = s.DefaultImageSource()
+	}
+	if req.Language == "" {
+		req.Language = requestLocale(ctx).Language
+	}
+	if req.ImageCount < 1 {
+		req.ImageCount = 1
+	}
+	if req.ImageCount > maxSceneImages {
+		req.ImageCount = maxSceneImages
+	}
+	if err := validateImagePlatform(req.Platform); err != nil {
+		return nil, err
+	}
+	preset, err := findImageStyle(req.StylePreset)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.prompts.template(ctx, PromptKindScene, req.SystemPrompt)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Scenes) == 0 {
+		// A bare script is split deterministically so this works offline
+		req.Scenes = scenesFromScript(req)
+	}
+	if len(req.Scenes) == 0 {
+		return nil, ErrNoScenes
 	}
 
 	result := &SceneGenerationResult{
-		ScriptID: req.ScriptID,
-		Scenes:   make([]GeneratedScene, 0, len(req.Scenes)),
+		ScriptID:                req.ScriptID,
+		Scenes:                  make([]GeneratedScene, 0, len(req.Scenes)),
+		ImageSource:             req.ImageSource,
+		ImageSourceAutoSelected: autoSelected,
+		StylePreset:             req.StylePreset,
 	}
 
 	for _, sceneInfo := range req.Scenes {
@@ -108,104 +242,399 @@ func (s *AISceneService) GenerateScenes(ctx context.Context, req *GenerateScenes
 			Number:      sceneInfo.Number,
 			Title:       sceneInfo.Title,
 			Description: sceneInfo.Description,
+			Duration:    sceneInfo.Duration,
 		}
 
 		// Enhance scene description with AI
-		enhancedDesc, imagePrompt, err := s.enhanceSceneDescription(ctx, sceneInfo, req.Style)
+		audience := sceneAudience{Language: req.Language, TargetAudience: req.TargetAudience}
+		enhancement, err := s.enhanceSceneDescription(ctx, sceneInfo, req.Style, template, preset, audience)
 		if err == nil {
-			scene.EnhancedDesc = enhancedDesc
-			scene.ImagePrompt = imagePrompt
-			scene.Mood = s.extractMood(enhancedDesc)
-			scene.ColorPalette = s.extractColorPalette(enhancedDesc)
+			scene.EnhancedDesc = enhancement.EnhancedDesc
+			scene.ImagePrompt = enhancement.ImagePrompt
+			scene.Mood = s.extractMood(enhancement.EnhancedDesc)
+			scene.ColorPalette = s.extractColorPalette(enhancement.EnhancedDesc)
 		}
 
+		// The style preset goes on every prompt, whatever the enhancer wrote
+		opts := imageOptions{NegativePrompt: req.NegativePrompt, Seed: req.Seed, Platform: req.Platform}
+		scene.ImagePrompt, opts = styleImagePrompt(preset, scene.ImagePrompt, opts)
+
 		// Get image based on source
 		if req.GenerateImages {
-			switch req.ImageSource {
-			case SourceDALLE:
-				if s.openAIKey != "" {
-					imageURL, err := s.generateImageWithDALLE(ctx, scene.ImagePrompt)
-					if err == nil {
-						scene.ImageURL = imageURL
-						scene.ThumbnailURL = imageURL
-						scene.ImageSource = SourceDALLE
-					}
-				}
-			case SourceStability:
-				if s.stabilityKey != "" {
-					imageURL, err := s.generateImageWithStability(ctx, scene.ImagePrompt)
-					if err == nil {
-						scene.ImageURL = imageURL
-						scene.ThumbnailURL = imageURL
-						scene.ImageSource = SourceStability
-					}
-				}
-			case SourceTogether:
-				if s.togetherKey != "" {
-					imageURL, err := s.generateImageWithTogether(ctx, scene.ImagePrompt)
-					if err == nil {
-						scene.ImageURL = imageURL
-						scene.ThumbnailURL = imageURL
-						scene.ImageSource = SourceTogether
-					}
-				}
-			case SourceUnsplash:
-				imageURL, thumbnailURL, altText, err := s.searchUnsplash(ctx, sceneInfo.Keywords)
-				if err == nil {
-					scene.ImageURL = imageURL
-					scene.ThumbnailURL = thumbnailURL
-					scene.AltText = altText
-					scene.ImageSource = SourceUnsplash
-				}
-			case SourcePexels:
-				imageURL, thumbnailURL, altText, err := s.searchPexels(ctx, sceneInfo.Keywords)
-				if err == nil {
-					scene.ImageURL = imageURL
-					scene.ThumbnailURL = thumbnailURL
-					scene.AltText = altText
-					scene.ImageSource = SourcePexels
+			images, err := s.generateSceneImages(ctx, req.ImageSource, scene.ImagePrompt, sceneInfo.Keywords, opts, req.ImageCount)
+			if err == nil {
+				image := images[0]
+				scene.ImageURL = image.ImageURL
+				scene.ThumbnailURL = image.ThumbnailURL
+				scene.AltText = image.AltText
+				scene.ImageSource = image.ImageSource
+				scene.Seed = image.Seed
+				scene.ImageSize = image.ImageSize
+				for _, image := range images {
+					scene.Images = append(scene.Images, image.ImageURL)
 				}
 			}
+			// AI images have no alt text and stock photo alt text is English
+			if enhancement != nil && enhancement.AltText != "" && (scene.AltText == "" || !audience.isEnglish()) {
+				scene.AltText = enhancement.AltText
+			}
 		}
 
 		result.Scenes = append(result.Scenes, scene)
 	}
 
 	result.TotalScenes = len(result.Scenes)
+	result.TotalDuration = reflowScenes(result.Scenes)
 	return result, nil
 }
 
+// RegenerateImageRequest represents a request to replace a single scene's image
+type RegenerateImageRequest struct {
+	Scene       SceneInfo   `json:"scene" binding:"required"`
+	ImagePrompt string      `json:"image_prompt,omitempty"` // Leave empty to derive a prompt from the scene
+	Style          string      `json:"style,omitempty"`
+	ImageSource    ImageSource `json:"image_source,omitempty"`
+	NegativePrompt string      `json:"negative_prompt,omitempty"`
+	Seed           int64       `json:"seed,omitempty"`
+	Language       string      `json:"language,omitempty"`
+	TargetAudience string      `json:"target_audience,omitempty"`
+	Platform       string      `json:"platform,omitempty"`
+	StylePreset    string      `json:"style_preset,omitempty"` // ImageStylePresets ID
+	SystemPrompt   string      `json:"system_prompt,omitempty"` // Template replacing the built-in enhancement system prompt
+}
+
+// SceneImage represents an image produced for a scene
+type SceneImage struct {
+	SceneNumber             int         `json:"scene_number"`
+	ImageURL                string      `json:"image_url"`
+	ThumbnailURL            string      `json:"thumbnail_url,omitempty"`
+	AltText                 string      `json:"alt_text,omitempty"`
+	ImageSource             ImageSource `json:"image_source"`
+	ImagePrompt             string      `json:"image_prompt,omitempty"`
+	Seed                    int64       `json:"seed,omitempty"`
+	ImageSize               string      `json:"image_size,omitempty"`
+	ImageSourceAutoSelected bool        `json:"image_source_auto_selected,omitempty"`
+}
+
+// sceneAudience carries the script's locale and audience into scene prompts
+type sceneAudience struct {
+	Language       string
+	TargetAudience string
+}
+
+func (a sceneAudience) isEnglish() bool {
+	return a.Language == "" || strings.HasPrefix(strings.ToLower(a.Language), "en")
+}
+
+// sceneEnhancement is the structured output of scene enhancement
+type sceneEnhancement struct {
+	EnhancedDesc string   `json:"enhanced_description"`
+	ImagePrompt  string   `json:"image_prompt"`
+	AltText      string   `json:"alt_text"`
+	Mood         string   `json:"mood"`
+	ColorPalette []string `json:"color_palette"`
+}
+
+// imageOptions carries optional steering parameters for AI image providers
+type imageOptions struct {
+	NegativePrompt string
+	Seed           int64
+	Platform       string // PlatformSpecs key to size the image for; square when empty
+}
+
+// RegenerateSceneImage produces a new image for one scene without regenerating the rest
+func (s *AISceneService) RegenerateSceneImage(ctx context.Context, req *RegenerateImageRequest) (*SceneImage, error) {
+	if req.Style == "" {
+		req.Style = "cinematic"
+	}
+	autoSelected := req.ImageSource == ""
+	if autoSelected {
+		req.ImageSource = s.DefaultImageSource()
+	}
+	if err := validateImagePlatform(req.Platform); err != nil {
+		return nil, err
+	}
+	preset, err := findImageStyle(req.StylePreset)
+	if err != nil {
+		return nil, err
+	}
+	if req.Language == "" {
+		req.Language = requestLocale(ctx).Language
+	}
+
+	audience := sceneAudience{Language: req.Language, TargetAudience: req.TargetAudience}
+	prompt := req.ImagePrompt
+	var enhancement *sceneEnhancement
+	if prompt == "" {
+		template, err := s.prompts.template(ctx, PromptKindScene, req.SystemPrompt)
+		if err != nil {
+			return nil, err
+		}
+		enhancement, err = s.enhanceSceneDescription(ctx, req.Scene, req.Style, template, preset, audience)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build image prompt: %w", err)
+		}
+		prompt = enhancement.ImagePrompt
+	}
+
+	opts := imageOptions{NegativePrompt: req.NegativePrompt, Seed: req.Seed, Platform: req.Platform}
+	prompt, opts = styleImagePrompt(preset, prompt, opts)
+	image, err := s.generateSceneImage(ctx, req.ImageSource, prompt, req.Scene.Keywords, opts)
+	if err != nil {
+		return nil, err
+	}
+	image.SceneNumber = req.Scene.Number
+	image.ImageSourceAutoSelected = autoSelected
+	if enhancement != nil && enhancement.AltText != "" && (image.AltText == "" || !audience.isEnglish()) {
+		image.AltText = enhancement.AltText
+	}
+
+	return image, nil
+}
+
+// generateSceneImage gets an image from the given source. AI sources use the
+// prompt; stock photo sources search by keywords.
+func (s *AISceneService) generateSceneImage(ctx context.Context, source ImageSource, prompt string, keywords []string, opts imageOptions) (*SceneImage, error) {
+	image := &SceneImage{
+		ImageSource: source,
+		ImagePrompt: prompt,
+	}
+
+	// Pick the seed here rather than letting the provider randomize, so it can be echoed back
+	if opts.Seed == 0 {
+		opts.Seed = rand.Int63n(maxImageSeed) + 1
+	}
+
+	var err error
+	switch source {
+	case SourceDALLE, SourceStability, SourceTogether:
+		// Try the requested provider first, then any other configured AI provider
+		err = fmt.Errorf("%s not configured", source)
+		for _, provider := range s.imageProviderChain(source) {
+			var imageURL string
+			imageURL, err = withBreaker(imageBreakerProvider(provider), func() (string, error) {
+				return withImageRetry(ctx, provider, func() (string, error) {
+					return s.generateAIImage(ctx, provider, prompt, opts)
+				})
+			})
+			if err == nil {
+				image.ImageURL = imageURL
+				image.ThumbnailURL = imageURL
+				image.ImageSource = provider
+				image.ImageSize = imageSize(opts.Platform, provider).String()
+				if provider != SourceDALLE {
+					image.Seed = opts.Seed
+				}
+				break
+			}
+			log.Printf("Image provider %s failed, trying next: %v", provider, err)
+		}
+	case SourceUnsplash, SourcePexels:
+		var photos []stockPhoto
+		photos, err = s.searchStock(ctx, source, keywords, 1)
+		if err == nil {
+			image.ImageURL = photos[0].ImageURL
+			image.ThumbnailURL = photos[0].ThumbnailURL
+			image.AltText = photos[0].AltText
+		}
+	default:
+		return nil, fmt.Errorf("unsupported image source: %s", source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// generateSceneImages gets count images for a scene, the first being the
+// primary. Stock sources return the top search results; AI sources generate
+// the extra images from consecutive seeds so the set is reproducible.
+// Extra images are best effort, so fewer than count may come back.
+func (s *AISceneService) generateSceneImages(ctx context.Context, source ImageSource, prompt string, keywords []string, opts imageOptions, count int) ([]*SceneImage, error) {
+	if count <= 1 {
+		image, err := s.generateSceneImage(ctx, source, prompt, keywords, opts)
+		if err != nil {
+			return nil, err
+		}
+		return []*SceneImage{image}, nil
+	}
+
+	if source == SourceUnsplash || source == SourcePexels {
+		photos, err := s.searchStock(ctx, source, keywords, count)
+		if err != nil {
+			return nil, err
+		}
+		images := make([]*SceneImage, 0, len(photos))
+		for _, photo := range photos {
+			images = append(images, &SceneImage{
+				ImageURL:     photo.ImageURL,
+				ThumbnailURL: photo.ThumbnailURL,
+				AltText:      photo.AltText,
+				ImageSource:  source,
+			})
+		}
+		return images, nil
+	}
+
+	primary, err := s.generateSceneImage(ctx, source, prompt, keywords, opts)
+	if err != nil {
+		return nil, err
+	}
+	images := []*SceneImage{primary}
+	for i := 1; i < count; i++ {
+		extra := opts
+		// DALL-E takes no seed and varies on its own
+		if primary.Seed != 0 {
+			extra.Seed = (primary.Seed-1+int64(i))%maxImageSeed + 1
+		}
+		image, err := s.generateSceneImage(ctx, source, prompt, keywords, extra)
+		if err != nil {
+			log.Printf("Extra scene image %d/%d failed: %v", i+1, count, err)
+			continue
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// stockPhoto is a single stock photo search result
+type stockPhoto struct {
+	ImageURL     string
+	ThumbnailURL string
+	AltText      string
+}
+
+// searchStock searches a stock photo source for up to count images
+func (s *AISceneService) searchStock(ctx context.Context, source ImageSource, keywords []string, count int) ([]stockPhoto, error) {
+	if source == SourcePexels {
+		return s.searchPexels(ctx, keywords, count)
+	}
+	return s.searchUnsplash(ctx, keywords, count)
+}
+
+// imageProviderChain returns the configured AI image providers, starting with the preferred one
+func (s *AISceneService) imageProviderChain(preferred ImageSource) []ImageSource {
+	configured := map[ImageSource]bool{
+		SourceDALLE:     s.openAIKey != "",
+		SourceStability: s.stabilityKey != "",
+		SourceTogether:  s.togetherKey != "",
+	}
+
+	var chain []ImageSource
+	if configured[preferred] {
+		chain = append(chain, preferred)
+	}
+	for _, provider := range []ImageSource{SourceDALLE, SourceStability, SourceTogether} {
+		if provider != preferred && configured[provider] {
+			chain = append(chain, provider)
+		}
+	}
+	return chain
+}
+
+// imageBreakerProvider returns the circuit breaker an image source shares with the provider's other APIs
+func imageBreakerProvider(source ImageSource) string {
+	switch source {
+	case SourceDALLE:
+		return providerOpenAI
+	case SourceStability:
+		return providerStability
+	}
+	return providerTogether
+}
+
+// generateAIImage makes a single generation request to an AI image provider
+func (s *AISceneService) generateAIImage(ctx context.Context, provider ImageSource, prompt string, opts imageOptions) (string, error) {
+	return withTimeout(ctx, s.timeouts, AIOpImage, func(ctx context.Context) (string, error) {
+		return s.requestAIImage(ctx, provider, prompt, opts)
+	})
+}
+
+// requestAIImage sends the generation request to the provider's API
+func (s *AISceneService) requestAIImage(ctx context.Context, provider ImageSource, prompt string, opts imageOptions) (string, error) {
+	switch provider {
+	case SourceDALLE:
+		return s.generateImageWithDALLE(ctx, prompt, opts)
+	case SourceStability:
+		return s.generateImageWithStability(ctx, prompt, opts)
+	case SourceTogether:
+		return s.generateImageWithTogether(ctx, prompt, opts)
+	}
+	return "", fmt.Errorf("unsupported image source: %s", provider)
+}
+
 // enhanceSceneDescription uses AI to enhance scene descriptions
-func (s *AISceneService) enhanceSceneDescription(ctx context.Context, scene SceneInfo, style string) (enhancedDesc, imagePrompt string, err error) {
-	systemPrompt := fmt.Sprintf(`You are an expert cinematographer and visual designer specializing in %s style.
+func (s *AISceneService) enhanceSceneDescription(ctx context.Context, scene SceneInfo, style, template string, preset *ImageStylePreset, audience sceneAudience) (*sceneEnhancement, error) {
+	systemPrompt, userPrompt := buildScenePrompts(scene, style, template, preset, audience)
 
-Enhance the scene description and create a detailed image generation prompt.
+	// Try OpenAI first, skipping any provider whose breaker is open
+	var calls []providerCall[*sceneEnhancement]
+	if s.openAIKey != "" {
+		calls = append(calls, providerCall[*sceneEnhancement]{providerOpenAI, func() (*sceneEnhancement, error) {
+			return withTimeout(ctx, s.timeouts, AIOpScene, func(ctx context.Context) (*sceneEnhancement, error) {
+				return s.enhanceWithOpenAI(ctx, systemPrompt, userPrompt)
+			})
+		}})
+	}
+	if s.togetherKey != "" {
+		calls = append(calls, providerCall[*sceneEnhancement]{providerTogether, func() (*sceneEnhancement, error) {
+			return withTimeout(ctx, s.timeouts, AIOpScene, func(ctx context.Context) (*sceneEnhancement, error) {
+				return s.enhanceWithTogether(ctx, systemPrompt, userPrompt)
+			})
+		}})
+	}
+	if len(calls) > 0 {
+		return withFallback(calls...)
+	}
+
+	// Fallback to basic enhancement
+	return &sceneEnhancement{
+		EnhancedDesc: scene.Description,
+		ImagePrompt:  fmt.Sprintf("%s style scene: %s", style, scene.Description),
+	}, nil
+}
+
+// buildScenePrompts builds the enhancement prompts from the system prompt
+// template. Image prompts stay in English since image models handle it best;
+// descriptions and alt text follow the script's language.
+func buildScenePrompts(scene SceneInfo, style, template string, preset *ImageStylePreset, audience sceneAudience) (systemPrompt, userPrompt string) {
+	systemPrompt = renderPromptTemplate(template, map[string]string{
+		"style":    style,
+		"language": audience.Language,
+		"audience": audience.TargetAudience,
+	}) + `
 
 Respond ONLY with a JSON object:
 {
   "enhanced_description": "Detailed visual description with mood, lighting, composition",
   "image_prompt": "Detailed prompt for AI image generation, 100-200 words, describing the visual scene",
+  "alt_text": "One-sentence description of the image for screen readers",
   "mood": "emotional tone",
   "color_palette": ["#hex1", "#hex2", "#hex3"]
-}`, style)
+}`
 
-	userPrompt := fmt.Sprintf("Scene %d: %s\nOriginal description: %s\nKeywords: %v",
-		scene.Number, scene.Title, scene.Description, scene.Keywords)
-
-	// Try OpenAI first
-	if s.openAIKey != "" {
-		return s.enhanceWithOpenAI(ctx, systemPrompt, userPrompt)
+	if preset != nil {
+		systemPrompt += fmt.Sprintf("\n\nEvery image in this video shares one visual style: %s (%s). Write image_prompt for that style and don't describe a different medium.", preset.Name, preset.PromptFragment)
 	}
-	if s.togetherKey != "" {
-		return s.enhanceWithTogether(ctx, systemPrompt, userPrompt)
+	if audience.TargetAudience != "" {
+		systemPrompt += fmt.Sprintf("\n\nThe video is made for this audience: %s. Choose settings, people and visual references that resonate with them.", audience.TargetAudience)
+	}
+	if !audience.isEnglish() {
+		systemPrompt += fmt.Sprintf("\n\nThe script is in language %q. Write enhanced_description and alt_text in that language and reflect its locale in the visuals. Keep image_prompt in English.", audience.Language)
 	}
 
-	// Fallback to basic enhancement
-	return scene.Description, fmt.Sprintf("%s style scene: %s", style, scene.Description), nil
+	userPrompt = fmt.Sprintf("Scene %d: %s\nOriginal description: %s\nKeywords: %v",
+		scene.Number, scene.Title, scene.Description, scene.Keywords)
+	if audience.Language != "" {
+		userPrompt += "\nLanguage: " + audience.Language
+	}
+
+	return systemPrompt, userPrompt
 }
 
 // enhanceWithOpenAI enhances scene using OpenAI
-func (s *AISceneService) enhanceWithOpenAI(ctx context.Context, systemPrompt, userPrompt string) (string, string, error) {
+func (s *AISceneService) enhanceWithOpenAI(ctx context.Context, systemPrompt, userPrompt string) (*sceneEnhancement, error) {
 	requestBody := map[string]interface{}{
 		"model": "gpt-4o-mini",
 		"messages": []map[string]string{
@@ -220,17 +649,17 @@ func (s *AISceneService) enhanceWithOpenAI(ctx context.Context, systemPrompt, us
 	jsonBody, _ := json.Marshal(requestBody)
 	httpReq, _ := http.NewRequestWithContext(ctx, "POST", s.openAIBaseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+s.openAIKey)
+	setOpenAIAuth(httpReq, s.openAIKey, s.openAIAccount)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", "", fmt.Errorf("API error: %s", string(body))
+		return nil, &ProviderError{StatusCode: resp.StatusCode, Err: fmt.Errorf("API error: %s", string(body))}
 	}
 
 	var result struct {
@@ -239,30 +668,26 @@ func (s *AISceneService) enhanceWithOpenAI(ctx context.Context, systemPrompt, us
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage chatUsage `json:"usage"`
 	}
 
 	json.NewDecoder(resp.Body).Decode(&result)
+	s.usage.recordChat(ctx, usageServiceScene, providerOpenAI, "gpt-4o-mini", result.Usage)
 
 	if len(result.Choices) == 0 {
-		return "", "", fmt.Errorf("no response")
-	}
-
-	var enhancement struct {
-		EnhancedDesc string   `json:"enhanced_description"`
-		ImagePrompt  string   `json:"image_prompt"`
-		Mood         string   `json:"mood"`
-		ColorPalette []string `json:"color_palette"`
+		return nil, fmt.Errorf("no response")
 	}
 
+	var enhancement sceneEnhancement
 	if err := json.Unmarshal([]byte(result.Choices[0].Message.Content), &enhancement); err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	return enhancement.EnhancedDesc, enhancement.ImagePrompt, nil
+	return &enhancement, nil
 }
 
 // enhanceWithTogether enhances scene using Together AI
-func (s *AISceneService) enhanceWithTogether(ctx context.Context, systemPrompt, userPrompt string) (string, string, error) {
+func (s *AISceneService) enhanceWithTogether(ctx context.Context, systemPrompt, userPrompt string) (*sceneEnhancement, error) {
 	requestBody := map[string]interface{}{
 		"model": "meta-llama/Llama-3.3-70B-Instruct-Turbo",
 		"messages": []map[string]string{
@@ -275,19 +700,19 @@ func (s *AISceneService) enhanceWithTogether(ctx context.Context, systemPrompt,
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
-	httpReq, _ := http.NewRequestWithContext(ctx, "POST", "https://api.together.xyz/v1/chat/completions", bytes.NewBuffer(jsonBody))
+	httpReq, _ := http.NewRequestWithContext(ctx, "POST", s.togetherBaseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+s.togetherKey)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", "", fmt.Errorf("API error: %s", string(body))
+		return nil, &ProviderError{StatusCode: resp.StatusCode, Err: fmt.Errorf("API error: %s", string(body))}
 	}
 
 	var result struct {
@@ -296,34 +721,30 @@ func (s *AISceneService) enhanceWithTogether(ctx context.Context, systemPrompt,
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage chatUsage `json:"usage"`
 	}
 
 	json.NewDecoder(resp.Body).Decode(&result)
+	s.usage.recordChat(ctx, usageServiceScene, providerTogether, "meta-llama/Llama-3.3-70B-Instruct-Turbo", result.Usage)
 
 	if len(result.Choices) == 0 {
-		return "", "", fmt.Errorf("no response")
-	}
-
-	var enhancement struct {
-		EnhancedDesc string   `json:"enhanced_description"`
-		ImagePrompt  string   `json:"image_prompt"`
-		Mood         string   `json:"mood"`
-		ColorPalette []string `json:"color_palette"`
+		return nil, fmt.Errorf("no response")
 	}
 
+	var enhancement sceneEnhancement
 	if err := json.Unmarshal([]byte(result.Choices[0].Message.Content), &enhancement); err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	return enhancement.EnhancedDesc, enhancement.ImagePrompt, nil
+	return &enhancement, nil
 }
 
 // generateImageWithDALLE generates an image using DALL-E
-func (s *AISceneService) generateImageWithDALLE(ctx context.Context, prompt string) (string, error) {
+func (s *AISceneService) generateImageWithDALLE(ctx context.Context, prompt string, opts imageOptions) (string, error) {
 	requestBody := map[string]interface{}{
 		"model": "dall-e-3",
 		"prompt": prompt,
-		"size": "1024x1024",
+		"size": imageSize(opts.Platform, SourceDALLE).String(),
 		"quality": "standard",
 		"n": 1,
 	}
@@ -331,7 +752,7 @@ func (s *AISceneService) generateImageWithDALLE(ctx context.Context, prompt stri
 	jsonBody, _ := json.Marshal(requestBody)
 	httpReq, _ := http.NewRequestWithContext(ctx, "POST", s.openAIBaseURL+"/images/generations", bytes.NewBuffer(jsonBody))
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+s.openAIKey)
+	setOpenAIAuth(httpReq, s.openAIKey, s.openAIAccount)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
@@ -340,8 +761,7 @@ func (s *AISceneService) generateImageWithDALLE(ctx context.Context, prompt stri
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("DALL-E error: %s", string(body))
+		return "", newImageProviderError("DALL-E", resp)
 	}
 
 	var result struct {
@@ -357,24 +777,41 @@ func (s *AISceneService) generateImageWithDALLE(ctx context.Context, prompt stri
 	if len(result.Data) == 0 {
 		return "", fmt.Errorf("no image generated")
 	}
+	s.usage.Record(ctx, domain.UsageEvent{Service: usageServiceImage, Provider: providerOpenAI, Model: "dall-e-3", Images: len(result.Data)})
 
 	return result.Data[0].URL, nil
 }
 
-// generateImageWithStability generates an image using Stability AI
-func (s *AISceneService) generateImageWithStability(ctx context.Context, prompt string) (string, error) {
-	requestBody := map[string]interface{}{
-		"text_prompts": []map[string]interface{}{
-			{"text": prompt, "weight": 1.0},
-		},
-		"cfg_scale": 7,
-		"samples": 1,
-		"steps": 30,
+// generateImageWithStability generates an image using Stability AI. The SD3
+// endpoint takes form fields and sizes images by aspect ratio.
+func (s *AISceneService) generateImageWithStability(ctx context.Context, prompt string, opts imageOptions) (string, error) {
+	size := imageSize(opts.Platform, SourceStability)
+	fields := map[string]string{
+		"prompt":        prompt,
+		"aspect_ratio":  stabilityAspectRatios[size],
+		"output_format": "png",
+		"seed":          strconv.FormatInt(opts.Seed, 10),
+	}
+	if opts.NegativePrompt != "" {
+		fields["negative_prompt"] = opts.NegativePrompt
 	}
 
-	jsonBody, _ := json.Marshal(requestBody)
-	httpReq, _ := http.NewRequestWithContext(ctx, "POST", "https://api.stability.ai/v2beta/stable-image/generate/sd3", bytes.NewBuffer(jsonBody))
-	httpReq.Header.Set("Content-Type", "application/json")
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return "", fmt.Errorf("failed to write form field: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to close form: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.stabilityBaseURL+"/v2beta/stable-image/generate/sd3", &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 	httpReq.Header.Set("Authorization", "Bearer "+s.stabilityKey)
 	httpReq.Header.Set("Accept", "application/json")
 
@@ -385,8 +822,7 @@ func (s *AISceneService) generateImageWithStability(ctx context.Context, prompt
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("Stability error: %s", string(body))
+		return "", newImageProviderError("Stability", resp)
 	}
 
 	var result struct {
@@ -397,23 +833,30 @@ func (s *AISceneService) generateImageWithStability(ctx context.Context, prompt
 		return "", err
 	}
 
+	s.usage.Record(ctx, domain.UsageEvent{Service: usageServiceImage, Provider: providerStability, Model: "sd3", Images: 1})
+
 	// Note: In production, you'd upload this to S3 and return the URL
 	return "data:image/png;base64," + result.Image, nil
 }
 
 // generateImageWithTogether generates an image using Together AI
-func (s *AISceneService) generateImageWithTogether(ctx context.Context, prompt string) (string, error) {
+func (s *AISceneService) generateImageWithTogether(ctx context.Context, prompt string, opts imageOptions) (string, error) {
+	size := imageSize(opts.Platform, SourceTogether)
 	requestBody := map[string]interface{}{
 		"model": "black-forest-labs/FLUX.1-schnell",
 		"prompt": prompt,
-		"width": 1024,
-		"height": 1024,
+		"width": size.Width,
+		"height": size.Height,
 		"steps": 4,
 		"n": 1,
+		"seed": opts.Seed,
+	}
+	if opts.NegativePrompt != "" {
+		requestBody["negative_prompt"] = opts.NegativePrompt
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
-	httpReq, _ := http.NewRequestWithContext(ctx, "POST", "https://api.together.xyz/v1/images/generations", bytes.NewBuffer(jsonBody))
+	httpReq, _ := http.NewRequestWithContext(ctx, "POST", s.togetherBaseURL+"/images/generations", bytes.NewBuffer(jsonBody))
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+s.togetherKey)
 
@@ -424,8 +867,7 @@ func (s *AISceneService) generateImageWithTogether(ctx context.Context, prompt s
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("Together error: %s", string(body))
+		return "", newImageProviderError("Together", resp)
 	}
 
 	var result struct {
@@ -441,30 +883,117 @@ func (s *AISceneService) generateImageWithTogether(ctx context.Context, prompt s
 	if len(result.Data) == 0 {
 		return "", fmt.Errorf("no image generated")
 	}
+	s.usage.Record(ctx, domain.UsageEvent{Service: usageServiceImage, Provider: providerTogether, Model: "black-forest-labs/FLUX.1-schnell", Images: len(result.Data)})
 
 	return result.Data[0].URL, nil
 }
 
-// searchUnsplash searches for images on Unsplash
-func (s *AISceneService) searchUnsplash(ctx context.Context, keywords []string) (imageURL, thumbnailURL, altText string, err error) {
+// Retry policy for AI image providers
+const (
+	imageMaxAttempts   = 3
+	imageRetryBaseWait = 2 * time.Second
+	imageRetryMaxWait  = 30 * time.Second
+)
+
+// imageProviderError is returned when an image provider responds with a non-200 status
+type imageProviderError struct {
+	Provider   string
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *imageProviderError) Error() string {
+	return fmt.Sprintf("%s error (status %d): %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// retryable reports whether the request may succeed if repeated
+func (e *imageProviderError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// newImageProviderError builds an imageProviderError from a failed response
+func newImageProviderError(provider string, resp *http.Response) *imageProviderError {
+	body, _ := io.ReadAll(resp.Body)
+	return &imageProviderError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Body:       string(body),
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(t); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// withImageRetry calls fn, retrying with exponential backoff on 429 and 5xx responses
+func withImageRetry(ctx context.Context, provider ImageSource, fn func() (string, error)) (string, error) {
+	wait := imageRetryBaseWait
+
+	for attempt := 1; ; attempt++ {
+		imageURL, err := fn()
+		if err == nil {
+			return imageURL, nil
+		}
+
+		var providerErr *imageProviderError
+		if !errors.As(err, &providerErr) || !providerErr.retryable() || attempt >= imageMaxAttempts {
+			return "", err
+		}
+
+		delay := wait
+		if providerErr.RetryAfter > 0 {
+			delay = providerErr.RetryAfter
+		}
+		if delay > imageRetryMaxWait {
+			delay = imageRetryMaxWait
+		}
+
+		log.Printf("Image provider %s returned %d, retrying in %s (attempt %d/%d)",
+			provider, providerErr.StatusCode, delay, attempt+1, imageMaxAttempts)
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(delay):
+		}
+		wait *= 2
+	}
+}
+
+// searchUnsplash searches for up to count images on Unsplash
+func (s *AISceneService) searchUnsplash(ctx context.Context, keywords []string, count int) ([]stockPhoto, error) {
 	if s.unsplashKey == "" {
-		return "", "", "", fmt.Errorf("unsplash key not configured")
+		return nil, fmt.Errorf("unsplash key not configured")
 	}
 
 	query := url.QueryEscape(joinKeywords(keywords))
-	searchURL := fmt.Sprintf("https://api.unsplash.com/search/photos?query=%s&per_page=1&orientation=landscape", query)
+	searchURL := fmt.Sprintf("https://api.unsplash.com/search/photos?query=%s&per_page=%d&orientation=landscape", query, count)
 
 	httpReq, _ := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	httpReq.Header.Set("Authorization", "Client-ID "+s.unsplashKey)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
-		return "", "", "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", "", "", fmt.Errorf("unsplash error: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unsplash error: %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -479,42 +1008,45 @@ func (s *AISceneService) searchUnsplash(ctx context.Context, keywords []string)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", "", "", err
+		return nil, err
 	}
 
 	if len(result.Results) == 0 {
-		return "", "", "", fmt.Errorf("no images found")
+		return nil, fmt.Errorf("no images found")
 	}
 
-	photo := result.Results[0]
-	alt := photo.AltDescription
-	if alt == "" {
-		alt = photo.Description
+	photos := make([]stockPhoto, 0, len(result.Results))
+	for _, photo := range result.Results {
+		alt := photo.AltDescription
+		if alt == "" {
+			alt = photo.Description
+		}
+		photos = append(photos, stockPhoto{ImageURL: photo.URLs.Regular, ThumbnailURL: photo.URLs.Small, AltText: alt})
 	}
 
-	return photo.URLs.Regular, photo.URLs.Small, alt, nil
+	return photos, nil
 }
 
-// searchPexels searches for images on Pexels
-func (s *AISceneService) searchPexels(ctx context.Context, keywords []string) (imageURL, thumbnailURL, altText string, err error) {
+// searchPexels searches for up to count images on Pexels
+func (s *AISceneService) searchPexels(ctx context.Context, keywords []string, count int) ([]stockPhoto, error) {
 	if s.pexelsKey == "" {
-		return "", "", "", fmt.Errorf("pexels key not configured")
+		return nil, fmt.Errorf("pexels key not configured")
 	}
 
 	query := url.QueryEscape(joinKeywords(keywords))
-	searchURL := fmt.Sprintf("https://api.pexels.com/v1/search?query=%s&per_page=1&orientation=landscape", query)
+	searchURL := fmt.Sprintf("https://api.pexels.com/v1/search?query=%s&per_page=%d&orientation=landscape", query, count)
 
 	httpReq, _ := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	httpReq.Header.Set("Authorization", s.pexelsKey)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
-		return "", "", "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", "", "", fmt.Errorf("pexels error: %d", resp.StatusCode)
+		return nil, fmt.Errorf("pexels error: %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -529,15 +1061,19 @@ func (s *AISceneService) searchPexels(ctx context.Context, keywords []string) (i
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", "", "", err
+		return nil, err
 	}
 
 	if len(result.Photos) == 0 {
-		return "", "", "", fmt.Errorf("no images found")
+		return nil, fmt.Errorf("no images found")
+	}
+
+	photos := make([]stockPhoto, 0, len(result.Photos))
+	for _, photo := range result.Photos {
+		photos = append(photos, stockPhoto{ImageURL: photo.Src.Large, ThumbnailURL: photo.Src.Medium, AltText: photo.Alt})
 	}
 
-	photo := result.Photos[0]
-	return photo.Src.Large, photo.Src.Medium, photo.Alt, nil
+	return photos, nil
 }
 
 // extractMood extracts mood from enhanced description
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuildScenePromptsCarryTheLanguage(t *testing.T) {
+	scene := SceneInfo{Number: 2, Title: "Market", Description: "A busy market at dawn", Keywords: []string{"market"}}
+	defaultTemplate := defaultPromptTemplates[PromptKindScene]
+
+	tests := []struct {
+		name       string
+		language   string
+		template   string
+		wantSystem []string
+		wantUser   string
+		localized  bool
+	}{
+		{name: "spanish", language: "es", template: defaultTemplate, wantSystem: []string{`language "es"`}, wantUser: "Language: es", localized: true},
+		{name: "regional tag", language: "pt-BR", template: defaultTemplate, wantSystem: []string{`language "pt-BR"`}, wantUser: "Language: pt-BR", localized: true},
+		{name: "english", language: "en-GB", template: defaultTemplate, wantUser: "Language: en-GB"},
+		{name: "no language", template: defaultTemplate},
+		{
+			name:       "custom template placeholder",
+			language:   "de",
+			template:   "Design {{style}} scenes in {{language}}.",
+			wantSystem: []string{"Design cinematic scenes in de."},
+			wantUser:   "Language: de",
+			localized:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			system, user := buildScenePrompts(scene, "cinematic", tt.template, nil, sceneAudience{Language: tt.language})
+			for _, want := range tt.wantSystem {
+				if !strings.Contains(system, want) {
+					t.Errorf("system prompt lacks %q:\n%s", want, system)
+				}
+			}
+			if localized := strings.Contains(system, "Write enhanced_description and alt_text in that language"); localized != tt.localized {
+				t.Errorf("localization instruction present = %v, want %v", localized, tt.localized)
+			}
+			if tt.wantUser != "" && !strings.Contains(user, tt.wantUser) {
+				t.Errorf("user prompt lacks %q:\n%s", tt.wantUser, user)
+			}
+			if tt.wantUser == "" && strings.Contains(user, "Language:") {
+				t.Errorf("user prompt names a language:\n%s", user)
+			}
+			if !strings.Contains(user, "A busy market at dawn") {
+				t.Errorf("user prompt lacks the scene:\n%s", user)
+			}
+		})
+	}
+}
+
+func TestStabilityImagesAreRequestedAsSD3Forms(t *testing.T) {
+	var form map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("request is not a multipart form: %v", err)
+		}
+		form = r.MultipartForm.Value
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"image": "aW1hZ2U="}`))
+	}))
+	defer server.Close()
+
+	s := &AISceneService{stabilityKey: "key", stabilityBaseURL: server.URL, httpClient: server.Client()}
+	for platform, want := range map[string]string{"": "1:1", "youtube": "16:9", "youtube_shorts": "9:16"} {
+		image, err := s.generateImageWithStability(context.Background(), "a lighthouse", imageOptions{Platform: platform, Seed: 42, NegativePrompt: "blurry, text"})
+		if err != nil {
+			t.Fatalf("%q: %v", platform, err)
+		}
+		if image != "data:image/png;base64,aW1hZ2U=" {
+			t.Errorf("%q: image = %q", platform, image)
+		}
+		if got := form["aspect_ratio"]; len(got) != 1 || got[0] != want {
+			t.Errorf("%q: aspect_ratio = %v, want %s", platform, got, want)
+		}
+		if got := form["prompt"]; len(got) != 1 || got[0] != "a lighthouse" {
+			t.Errorf("%q: prompt = %v", platform, got)
+		}
+		if got := form["seed"]; len(got) != 1 || got[0] != "42" {
+			t.Errorf("%q: seed = %v", platform, got)
+		}
+		if got := form["negative_prompt"]; len(got) != 1 || got[0] != "blurry, text" {
+			t.Errorf("%q: negative_prompt = %v", platform, got)
+		}
+	}
+
+	if _, err := s.generateImageWithStability(context.Background(), "a lighthouse", imageOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := form["negative_prompt"]; ok {
+		t.Errorf("negative_prompt = %v sent without one", got)
+	}
+}
@@ -4,19 +4,30 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
 // AIScriptService handles AI-powered script generation
 type AIScriptService struct {
-	openAIKey     string
-	togetherKey   string
-	openAIBaseURL string
-	httpClient    *http.Client
+	openAIKey       string
+	togetherKey     string
+	openAIBaseURL   string
+	togetherBaseURL string
+	httpClient      *http.Client
+	usage           *UsageService
+	prompts         *PromptTemplateService
+
+	// timeouts bounds each provider call; nil uses the defaults
+	timeouts aiTimeouts
+	// openAIAccount is who OpenAI calls are billed to
+	openAIAccount openAIAccount
 }
 
 // ScriptStyle represents different script styles
@@ -35,11 +46,70 @@ const (
 type GenerateScriptRequest struct {
 	Prompt      string      `json:"prompt" binding:"required"`
 	Style       ScriptStyle `json:"style,omitempty"`
+	Preset      string      `json:"preset,omitempty"`   // Fills unset Duration and MaxScenes, see ScriptPresets
 	Duration    int         `json:"duration,omitempty"` // Target duration in seconds
 	MaxScenes   int         `json:"max_scenes,omitempty"`
-	Language    string      `json:"language,omitempty"` // ISO language code
+	Language    string      `json:"language,omitempty"` // ISO language code; defaults to the request locale's
 	Tone        string      `json:"tone,omitempty"`
 	TargetAudience string   `json:"target_audience,omitempty"`
+	Languages   []string    `json:"languages,omitempty"` // Generate one script per language; the first is the source
+	SystemPrompt string     `json:"system_prompt,omitempty"` // Template replacing the built-in system prompt, see PromptTemplateService
+}
+
+// ScriptPreset is a named script length
+type ScriptPreset struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Duration    int    `json:"duration"` // in seconds
+	MaxScenes   int    `json:"max_scenes"`
+}
+
+// ScriptPresets are the script lengths callers can pick instead of setting
+// Duration and MaxScenes themselves, shortest first
+var ScriptPresets = []ScriptPreset{
+	{ID: "short", Name: "Short", Description: "Quick hook for Shorts, Reels and TikTok", Duration: 30, MaxScenes: 3},
+	{ID: "standard", Name: "Standard", Description: "A one-minute explainer", Duration: 60, MaxScenes: 5},
+	{ID: "long", Name: "Long", Description: "A few minutes covering a topic in depth", Duration: 180, MaxScenes: 8},
+	{ID: "extended", Name: "Extended", Description: "A full walkthrough or story", Duration: 300, MaxScenes: 12},
+}
+
+// defaultScriptPreset fills Duration and MaxScenes when neither a preset nor
+// explicit values are given
+const defaultScriptPreset = "standard"
+
+// ErrUnknownScriptPreset is returned when a request names a preset that doesn't exist
+var ErrUnknownScriptPreset = errors.New("unknown script preset")
+
+// applyScriptPreset fills the request's unset Duration and MaxScenes from its
+// preset, or the default preset; explicit values always win
+func applyScriptPreset(req *GenerateScriptRequest) error {
+	id := req.Preset
+	if id == "" {
+		id = defaultScriptPreset
+	}
+	for _, preset := range ScriptPresets {
+		if preset.ID != id {
+			continue
+		}
+		if req.Duration == 0 {
+			req.Duration = preset.Duration
+		}
+		if req.MaxScenes == 0 {
+			req.MaxScenes = preset.MaxScenes
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownScriptPreset, req.Preset)
+}
+
+// maxScriptLanguages caps how many translations one request can fan out into
+const maxScriptLanguages = 10
+
+// MultiLanguageScripts holds one script per requested language
+type MultiLanguageScripts struct {
+	Scripts map[string]*Script `json:"scripts"`
+	Errors  map[string]string  `json:"errors,omitempty"` // Languages whose translation failed
 }
 
 // Script represents a generated video script
@@ -67,57 +137,167 @@ type Scene struct {
 // NewAIScriptService creates a new AI script service
 func NewAIScriptService() *AIScriptService {
 	return &AIScriptService{
-		openAIKey:     os.Getenv("OPENAI_API_KEY"),
-		togetherKey:   os.Getenv("TOGETHER_API_KEY"),
-		openAIBaseURL: getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		openAIKey:       os.Getenv("OPENAI_API_KEY"),
+		togetherKey:     os.Getenv("TOGETHER_API_KEY"),
+		openAIBaseURL:   getBaseURL("OPENAI_BASE_URL", defaultOpenAIBaseURL),
+		togetherBaseURL: getBaseURL("TOGETHER_BASE_URL", defaultTogetherBaseURL),
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
 	}
 }
 
+// SetUsage records the tokens each completion uses
+func (s *AIScriptService) SetUsage(usage *UsageService) {
+	s.usage = usage
+}
+
+// SetPromptTemplates lets users replace the built-in system prompt with
+// their own template
+func (s *AIScriptService) SetPromptTemplates(prompts *PromptTemplateService) {
+	s.prompts = prompts
+}
+
 // GenerateScript generates a video script from a prompt
 func (s *AIScriptService) GenerateScript(ctx context.Context, req *GenerateScriptRequest) (*Script, error) {
 	if req.Style == "" {
 		req.Style = StyleEducational
 	}
-	if req.Duration == 0 {
-		req.Duration = 60
-	}
-	if req.MaxScenes == 0 {
-		req.MaxScenes = 5
+	if err := applyScriptPreset(req); err != nil {
+		return nil, err
 	}
 	if req.Language == "" {
-		req.Language = "en"
+		req.Language = requestLocale(ctx).Language
 	}
 
 	// Build the system prompt
-	systemPrompt := s.buildSystemPrompt(req)
+	template, err := s.prompts.template(ctx, PromptKindScript, req.SystemPrompt)
+	if err != nil {
+		return nil, err
+	}
+	systemPrompt := s.buildSystemPrompt(req, template)
 	
 	// Build the user prompt
 	userPrompt := fmt.Sprintf("Create a video script about: %s", req.Prompt)
 
-	// Try OpenAI first, fall back to Together
-	if s.openAIKey != "" {
-		return s.generateWithOpenAI(ctx, systemPrompt, userPrompt, req)
+	return s.generate(ctx, systemPrompt, userPrompt, req)
+}
+
+// GenerateScripts generates a script in the first of req.Languages and
+// translates it into the others concurrently. Translations keep the source
+// scene structure and timings. A failed translation is reported in Errors
+// rather than failing the request; only a failed source script does that.
+func (s *AIScriptService) GenerateScripts(ctx context.Context, req *GenerateScriptRequest) (*MultiLanguageScripts, error) {
+	var languages []string
+	seen := make(map[string]bool)
+	for _, language := range req.Languages {
+		language = strings.TrimSpace(language)
+		if language != "" && !seen[language] {
+			seen[language] = true
+			languages = append(languages, language)
+		}
+	}
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("no languages requested")
+	}
+	if len(languages) > maxScriptLanguages {
+		return nil, fmt.Errorf("at most %d languages can be requested at once", maxScriptLanguages)
+	}
+
+	sourceReq := *req
+	sourceReq.Language = languages[0]
+	source, err := s.GenerateScript(ctx, &sourceReq)
+	if err != nil {
+		return nil, err
 	}
-	if s.togetherKey != "" {
-		return s.generateWithTogether(ctx, systemPrompt, userPrompt, req)
+
+	result := &MultiLanguageScripts{
+		Scripts: map[string]*Script{languages[0]: source},
+		Errors:  make(map[string]string),
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, language := range languages[1:] {
+		wg.Add(1)
+		go func(language string) {
+			defer wg.Done()
+
+			script, err := s.TranslateScript(ctx, source, language)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Errors[language] = err.Error()
+				return
+			}
+			result.Scripts[language] = script
+		}(language)
 	}
+	wg.Wait()
 
-	return nil, fmt.Errorf("no AI API key configured")
+	return result, nil
 }
 
-// buildSystemPrompt creates the system prompt for script generation
-func (s *AIScriptService) buildSystemPrompt(req *GenerateScriptRequest) string {
-	return fmt.Sprintf(`You are an expert video scriptwriter specializing in %s content.
+// TranslateScript translates a script's text into another language, keeping
+// its scenes, their order and their timings exactly as they are
+func (s *AIScriptService) TranslateScript(ctx context.Context, script *Script, language string) (*Script, error) {
+	systemPrompt := fmt.Sprintf(`You are an expert video script translator. Translate the provided script into the language with ISO code %q.
 
-Create a detailed video script with the following specifications:
-- Target Duration: %d seconds
-- Maximum Scenes: %d
-- Style: %s
-- Language: %s
-- Tone: %s
+Translate the title, description, and each scene's title, description, narration, visual notes and keywords. Adapt idioms so the narration sounds natural when spoken, but keep it a similar length so it fits the scene's duration.
+Do not add, remove, merge or reorder scenes, and do not change any numbers or durations.
+
+Respond with the complete translated script in the same JSON format.`, language)
+
+	scriptJSON, _ := json.Marshal(script)
+	userPrompt := fmt.Sprintf("Translate this script:\n%s", string(scriptJSON))
+
+	translated, err := s.generate(ctx, systemPrompt, userPrompt, &GenerateScriptRequest{
+		Style:    script.Style,
+		Language: language,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(translated.Scenes) != len(script.Scenes) {
+		return nil, fmt.Errorf("translation to %s returned %d scenes, expected %d", language, len(translated.Scenes), len(script.Scenes))
+	}
+
+	// Timings come from the source, whatever the model returned
+	for i := range translated.Scenes {
+		translated.Scenes[i].Number = script.Scenes[i].Number
+		translated.Scenes[i].Duration = script.Scenes[i].Duration
+	}
+	translated.TotalDuration = script.TotalDuration
+	translated.Style = script.Style
+	translated.Language = language
+
+	return translated, nil
+}
+
+// generate generates a script, parsing the provider's reply as one
+func (s *AIScriptService) generate(ctx context.Context, systemPrompt, userPrompt string, req *GenerateScriptRequest) (*Script, error) {
+	return completeJSON(ctx, s, AIOpScript, systemPrompt, userPrompt, 0.7, func(content string) (*Script, error) {
+		var script Script
+		if err := json.Unmarshal([]byte(content), &script); err != nil {
+			return nil, fmt.Errorf("failed to parse script JSON: %w", err)
+		}
+
+		// Ensure language is set
+		if script.Language == "" {
+			script.Language = req.Language
+		}
+		if script.Style == "" {
+			script.Style = req.Style
+		}
+		return &script, nil
+	})
+}
+
+// buildSystemPrompt creates the system prompt for script generation: the
+// template filled in for the request, followed by the response format
+func (s *AIScriptService) buildSystemPrompt(req *GenerateScriptRequest, template string) string {
+	return renderPromptTemplate(template, scriptPromptValues(req)) + fmt.Sprintf(`
 
 Respond ONLY with a valid JSON object in this exact format:
 {
@@ -145,12 +325,6 @@ Important:
 - Make narration engaging and natural-sounding
 - Include specific visual directions
 - Ensure the script flows logically`,
-		req.Style,
-		req.Duration,
-		req.MaxScenes,
-		req.Style,
-		req.Language,
-		req.Tone,
 		req.Duration,
 		req.Style,
 		req.Language,
@@ -158,110 +332,159 @@ Important:
 	)
 }
 
-// generateWithOpenAI generates a script using OpenAI API
-func (s *AIScriptService) generateWithOpenAI(ctx context.Context, systemPrompt, userPrompt string, req *GenerateScriptRequest) (*Script, error) {
-	requestBody := map[string]interface{}{
-		"model": "gpt-4o-mini",
-		"messages": []map[string]string{
-			{"role": "system", "content": systemPrompt},
-			{"role": "user", "content": userPrompt},
-		},
-		"temperature": 0.7,
-		"max_tokens": 4000,
-		"response_format": map[string]string{"type": "json_object"},
-	}
+// EnhanceScript takes an existing script and enhances it
+func (s *AIScriptService) EnhanceScript(ctx context.Context, script *Script, enhancementType string) (*Script, error) {
+	systemPrompt := fmt.Sprintf(`You are an expert script editor. Enhance the provided script by %s.
 
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
+Respond with the complete enhanced script in the same JSON format.`, enhancementType)
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.openAIBaseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	scriptJSON, _ := json.Marshal(script)
+	userPrompt := fmt.Sprintf("Enhance this script:\n%s", string(scriptJSON))
+
+	return s.generate(ctx, systemPrompt, userPrompt, &GenerateScriptRequest{
+		Style:    script.Style,
+		Language: script.Language,
+	})
+}
+
+// ErrSceneNotFound is returned when a script has no scene with the requested number
+var ErrSceneNotFound = errors.New("scene not found in script")
+
+// EnhanceScene rewrites a single scene of a script and returns the updated
+// script. The other scenes are kept verbatim; the rest of the script is only
+// sent to the model as context.
+func (s *AIScriptService) EnhanceScene(ctx context.Context, script *Script, sceneNumber int, enhancementType string) (*Script, error) {
+	index := -1
+	for i, scene := range script.Scenes {
+		if scene.Number == sceneNumber {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrSceneNotFound, sceneNumber)
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+s.openAIKey)
+	systemPrompt := fmt.Sprintf(`You are an expert script editor. Enhance one scene of the provided script by %s.
+Keep it consistent with the scenes around it and close to its current duration.
 
-	resp, err := s.httpClient.Do(httpReq)
+Respond with only the enhanced scene as a JSON object with the same fields as the scenes in the script.`, enhancementType)
+
+	scriptJSON, _ := json.Marshal(script)
+	sceneJSON, _ := json.Marshal(script.Scenes[index])
+	userPrompt := fmt.Sprintf("Full script for context:\n%s\n\nEnhance scene %d:\n%s", string(scriptJSON), sceneNumber, string(sceneJSON))
+
+	content, err := s.CompleteJSON(ctx, systemPrompt, userPrompt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(body))
+	var scene Scene
+	if err := json.Unmarshal([]byte(content), &scene); err != nil {
+		return nil, fmt.Errorf("failed to parse scene JSON: %w", err)
 	}
 
-	var result struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
+	original := script.Scenes[index]
+	scene.Number = original.Number
+	if scene.Duration <= 0 {
+		scene.Duration = original.Duration
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+	enhanced := *script
+	enhanced.Scenes = append([]Scene(nil), script.Scenes...)
+	enhanced.Scenes[index] = scene
+	enhanced.TotalDuration += scene.Duration - original.Duration
 
-	if len(result.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI")
-	}
+	return &enhanced, nil
+}
 
-	var script Script
-	if err := json.Unmarshal([]byte(result.Choices[0].Message.Content), &script); err != nil {
-		return nil, fmt.Errorf("failed to parse script JSON: %w", err)
-	}
+// CompleteJSON sends a prompt to the configured AI provider and returns the raw JSON content of the reply
+func (s *AIScriptService) CompleteJSON(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	return completeJSON(ctx, s, AIOpCompletion, systemPrompt, userPrompt, 0.2, func(content string) (string, error) {
+		return content, nil
+	})
+}
+
+// chatProvider is an OpenAI-compatible chat completion API
+type chatProvider struct {
+	name    string // breaker and usage key
+	label   string // for error messages
+	model   string
+	baseURL string
+	apiKey  string
+}
 
-	// Ensure language is set
-	if script.Language == "" {
-		script.Language = req.Language
+// chatProviders lists the configured providers in fallback order
+func (s *AIScriptService) chatProviders() []chatProvider {
+	var providers []chatProvider
+	if s.openAIKey != "" {
+		providers = append(providers, chatProvider{providerOpenAI, "OpenAI", "gpt-4o-mini", s.openAIBaseURL, s.openAIKey})
 	}
-	if script.Style == "" {
-		script.Style = req.Style
+	if s.togetherKey != "" {
+		providers = append(providers, chatProvider{providerTogether, "Together", "meta-llama/Llama-3.3-70B-Instruct-Turbo", s.togetherBaseURL, s.togetherKey})
 	}
+	return providers
+}
 
-	return &script, nil
+// completeJSON tries OpenAI first and falls back to Together, skipping a
+// provider straight away while its circuit breaker is open. parse turns the
+// reply into the result; a reply it can't parse counts as the provider failing.
+func completeJSON[T any](ctx context.Context, s *AIScriptService, op, systemPrompt, userPrompt string, temperature float64, parse func(content string) (T, error)) (T, error) {
+	var calls []providerCall[T]
+	for _, provider := range s.chatProviders() {
+		calls = append(calls, providerCall[T]{provider.name, func() (T, error) {
+			return withTimeout(ctx, s.timeouts, op, func(ctx context.Context) (T, error) {
+				content, err := s.chatJSON(ctx, provider, systemPrompt, userPrompt, temperature)
+				if err != nil {
+					var zero T
+					return zero, err
+				}
+				return parse(content)
+			})
+		}})
+	}
+	return withFallback(calls...)
 }
 
-// generateWithTogether generates a script using Together AI API
-func (s *AIScriptService) generateWithTogether(ctx context.Context, systemPrompt, userPrompt string, req *GenerateScriptRequest) (*Script, error) {
+// chatJSON makes a single JSON-mode chat completion request to a provider
+func (s *AIScriptService) chatJSON(ctx context.Context, provider chatProvider, systemPrompt, userPrompt string, temperature float64) (string, error) {
 	requestBody := map[string]interface{}{
-		"model": "meta-llama/Llama-3.3-70B-Instruct-Turbo",
+		"model": provider.model,
 		"messages": []map[string]string{
 			{"role": "system", "content": systemPrompt},
 			{"role": "user", "content": userPrompt},
 		},
-		"temperature": 0.7,
-		"max_tokens": 4000,
+		"temperature":     temperature,
+		"max_tokens":      4000,
 		"response_format": map[string]string{"type": "json_object"},
 	}
 
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.together.xyz/v1/chat/completions", bytes.NewBuffer(jsonBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", provider.baseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+s.togetherKey)
+	if provider.name == providerOpenAI {
+		setOpenAIAuth(httpReq, provider.apiKey, s.openAIAccount)
+	} else {
+		httpReq.Header.Set("Authorization", "Bearer "+provider.apiKey)
+	}
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return "", fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Together API error (status %d): %s", resp.StatusCode, string(body))
+		return "", &ProviderError{StatusCode: resp.StatusCode, Err: fmt.Errorf("%s API error (status %d): %s", provider.label, resp.StatusCode, string(body))}
 	}
 
 	var result struct {
@@ -270,65 +493,93 @@ func (s *AIScriptService) generateWithTogether(ctx context.Context, systemPrompt
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage chatUsage `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
+	s.usage.recordChat(ctx, usageServiceScript, provider.name, provider.model, result.Usage)
 
 	if len(result.Choices) == 0 {
-		return nil, fmt.Errorf("no response from Together AI")
+		return "", fmt.Errorf("no response from %s", provider.label)
 	}
 
-	var script Script
-	if err := json.Unmarshal([]byte(result.Choices[0].Message.Content), &script); err != nil {
-		return nil, fmt.Errorf("failed to parse script JSON: %w", err)
-	}
+	return result.Choices[0].Message.Content, nil
+}
 
-	// Ensure language is set
-	if script.Language == "" {
-		script.Language = req.Language
-	}
-	if script.Style == "" {
-		script.Style = req.Style
-	}
+// EstimateDuration estimates the duration of narration text
+func (s *AIScriptService) EstimateDuration(text string, wpm float64) int {
+	return estimateDuration(text, wpm)
+}
 
-	return &script, nil
+// estimateDuration estimates narration seconds at wpm words per minute
+func estimateDuration(text string, wpm float64) int {
+	if wpm == 0 {
+		wpm = 150 // Average speaking rate
+	}
+	wordCount := len(bytes.Fields([]byte(text)))
+	seconds := float64(wordCount) / wpm * 60
+	return int(seconds + 0.5) // Round to nearest second
 }
 
-// EnhanceScript takes an existing script and enhances it
-func (s *AIScriptService) EnhanceScript(ctx context.Context, script *Script, enhancementType string) (*Script, error) {
-	systemPrompt := fmt.Sprintf(`You are an expert script editor. Enhance the provided script by %s.
+// Default AI provider API base URLs, overridable to route traffic through a gateway
+const (
+	defaultOpenAIBaseURL     = "https://api.openai.com/v1"
+	defaultTogetherBaseURL   = "https://api.together.xyz/v1"
+	defaultStabilityBaseURL  = "https://api.stability.ai"
+	defaultElevenLabsBaseURL = "https://api.elevenlabs.io/v1"
+)
 
-Respond with the complete enhanced script in the same JSON format.`, enhancementType)
+// getBaseURL reads a provider base URL from the environment without a trailing slash
+func getBaseURL(key, defaultValue string) string {
+	return strings.TrimRight(getEnv(key, defaultValue), "/")
+}
 
-	scriptJSON, _ := json.Marshal(script)
-	userPrompt := fmt.Sprintf("Enhance this script:\n%s", string(scriptJSON))
+// openAIAccount is the organization and project OpenAI spend is billed to;
+// empty fields leave the API key's defaults
+type openAIAccount struct {
+	organization string
+	project      string
+}
 
-	if s.openAIKey != "" {
-		return s.generateWithOpenAI(ctx, systemPrompt, userPrompt, &GenerateScriptRequest{
-			Style:    script.Style,
-			Language: script.Language,
-		})
+// setOpenAIAuth authenticates a request to the OpenAI API, attributing it to
+// the account's organization and project when they are set so its spend is
+// billed to the right project
+func setOpenAIAuth(httpReq *http.Request, apiKey string, account openAIAccount) {
+	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	if account.organization != "" {
+		httpReq.Header.Set("OpenAI-Organization", account.organization)
 	}
-	if s.togetherKey != "" {
-		return s.generateWithTogether(ctx, systemPrompt, userPrompt, &GenerateScriptRequest{
-			Style:    script.Style,
-			Language: script.Language,
-		})
+	if account.project != "" {
+		httpReq.Header.Set("OpenAI-Project", account.project)
 	}
+}
 
-	return nil, fmt.Errorf("no AI API key configured")
+// SetOpenAIAccount bills OpenAI calls to an organization and project
+func (s *AIScriptService) SetOpenAIAccount(organization, project string) {
+	s.openAIAccount = openAIAccount{organization: organization, project: project}
 }
 
-// EstimateDuration estimates the duration of narration text
-func (s *AIScriptService) EstimateDuration(text string, wpm float64) int {
-	if wpm == 0 {
-		wpm = 150 // Average speaking rate
-	}
-	wordCount := len(bytes.Fields([]byte(text)))
-	seconds := float64(wordCount) / wpm * 60
-	return int(seconds + 0.5) // Round to nearest second
+// SetOpenAIAccount bills OpenAI calls to an organization and project
+func (s *AISceneService) SetOpenAIAccount(organization, project string) {
+	s.openAIAccount = openAIAccount{organization: organization, project: project}
+}
+
+// SetOpenAIAccount bills OpenAI calls to an organization and project
+func (s *TTSService) SetOpenAIAccount(organization, project string) {
+	s.openAIAccount = openAIAccount{organization: organization, project: project}
+}
+
+// SetOpenAIAccount bills OpenAI calls to an organization and project
+func (s *TranscriptionService) SetOpenAIAccount(organization, project string) {
+	s.openAIAccount = openAIAccount{organization: organization, project: project}
+}
+
+// SetOpenAIAccount bills moderation calls made with the OpenAI API to an
+// organization and project
+func (s *ModerationService) SetOpenAIAccount(organization, project string) {
+	s.openAIAccount = openAIAccount{organization: organization, project: project}
 }
 
 func getEnv(key, defaultValue string) string {
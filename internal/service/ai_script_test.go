@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	socialdomain "renderowl-api/internal/domain/social"
+)
+
+// chatServer answers chat completions with a fixed status and content,
+// counting the requests it gets
+func chatServer(t *testing.T, status int, content string, requests *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("request to %s, want /chat/completions", r.URL.Path)
+		}
+		if status != http.StatusOK {
+			http.Error(w, "unavailable", status)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": content}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestScriptService(openAI, together *httptest.Server) *AIScriptService {
+	return &AIScriptService{
+		openAIKey:       "openai-key",
+		togetherKey:     "together-key",
+		openAIBaseURL:   openAI.URL,
+		togetherBaseURL: together.URL,
+		httpClient:      http.DefaultClient,
+	}
+}
+
+func TestCompleteJSONFallsBackToTogether(t *testing.T) {
+	var openAIRequests, togetherRequests int
+	openAI := chatServer(t, http.StatusServiceUnavailable, "", &openAIRequests)
+	together := chatServer(t, http.StatusOK, `{"ok":true}`, &togetherRequests)
+
+	content, err := newTestScriptService(openAI, together).CompleteJSON(context.Background(), "system", "user")
+	if err != nil {
+		t.Fatalf("CompleteJSON: %v", err)
+	}
+	if content != `{"ok":true}` {
+		t.Errorf("content = %q, want Together's reply", content)
+	}
+	if openAIRequests != 1 || togetherRequests != 1 {
+		t.Errorf("got %d OpenAI and %d Together requests, want 1 each", openAIRequests, togetherRequests)
+	}
+}
+
+func TestGenerateFallsBackOnUnparseableScript(t *testing.T) {
+	var openAIRequests, togetherRequests int
+	openAI := chatServer(t, http.StatusOK, "not json", &openAIRequests)
+	together := chatServer(t, http.StatusOK, `{"title":"From Together","scenes":[]}`, &togetherRequests)
+
+	req := &GenerateScriptRequest{Style: StyleEducational, Language: "fr"}
+	script, err := newTestScriptService(openAI, together).generate(context.Background(), "system", "user", req)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if script.Title != "From Together" {
+		t.Errorf("title = %q, want Together's script", script.Title)
+	}
+	if script.Language != "fr" || script.Style != StyleEducational {
+		t.Errorf("language %q and style %q not defaulted from the request", script.Language, script.Style)
+	}
+}
+
+func TestOpenAIRequestsAreBilledToTheConfiguredAccount(t *testing.T) {
+	headers := map[string]http.Header{}
+	record := func(name string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers[name] = r.Header.Clone()
+			http.Error(w, "unavailable", http.StatusBadRequest)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	openAI, together, moderation := record("openai"), record("together"), record("moderation")
+
+	script := newTestScriptService(openAI, together)
+	script.SetOpenAIAccount("org-1", "proj-1")
+	script.CompleteJSON(context.Background(), "system", "user")
+
+	moderator := &ModerationService{apiKey: "moderation-key", baseURL: moderation.URL, httpClient: http.DefaultClient}
+	moderator.SetOpenAIAccount("org-1", "proj-1")
+	moderator.Moderate(context.Background(), &socialdomain.UploadRequest{Title: "A title"})
+
+	for _, name := range []string{"openai", "moderation"} {
+		if got := headers[name].Get("OpenAI-Organization"); got != "org-1" {
+			t.Errorf("%s OpenAI-Organization = %q, want org-1", name, got)
+		}
+		if got := headers[name].Get("OpenAI-Project"); got != "proj-1" {
+			t.Errorf("%s OpenAI-Project = %q, want proj-1", name, got)
+		}
+	}
+	if got := headers["together"].Get("OpenAI-Organization"); got != "" {
+		t.Errorf("Together was sent OpenAI-Organization %q", got)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AI operations that can be given their own timeout, named as in the
+// AI_TIMEOUTS setting
+const (
+	AIOpScript        = "script"
+	AIOpCompletion    = "completion"
+	AIOpScene         = "scene"
+	AIOpImage         = "image"
+	AIOpAltText       = "alt_text"
+	AIOpTTS           = "tts"
+	AIOpTranscription = "transcription"
+	AIOpVoiceClone    = "voice_clone"
+)
+
+// defaultAITimeouts bound each AI call unless overridden
+var defaultAITimeouts = map[string]time.Duration{
+	AIOpScript:        60 * time.Second,
+	AIOpCompletion:    60 * time.Second,
+	AIOpScene:         60 * time.Second,
+	AIOpImage:         120 * time.Second,
+	AIOpAltText:       30 * time.Second,
+	AIOpTTS:           120 * time.Second,
+	AIOpTranscription: 300 * time.Second,
+	AIOpVoiceClone:    120 * time.Second,
+}
+
+// TimeoutError is returned when an AI call runs out of time, either its own
+// timeout or the deadline of the request it was made for. Find it with
+// errors.As, even inside a *ProviderError, to tell a slow call from one the
+// provider failed.
+type TimeoutError struct {
+	Operation string
+	Timeout   time.Duration
+	// RequestDeadline is set when the caller's deadline, not the
+	// operation's own timeout, ran out
+	RequestDeadline bool
+	Err             error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s call timed out after %s", e.Operation, e.Timeout.Round(time.Millisecond))
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// aiTimeouts maps AI operations to how long one call may take. A nil map
+// uses the defaults.
+type aiTimeouts map[string]time.Duration
+
+// newAITimeouts returns the default timeouts with overrides applied
+func newAITimeouts(overrides map[string]time.Duration) aiTimeouts {
+	timeouts := make(aiTimeouts, len(defaultAITimeouts))
+	for op, timeout := range defaultAITimeouts {
+		timeouts[op] = timeout
+	}
+	for op, timeout := range overrides {
+		if timeout > 0 {
+			timeouts[op] = timeout
+		}
+	}
+	return timeouts
+}
+
+func (t aiTimeouts) get(op string) time.Duration {
+	if timeout, ok := t[op]; ok {
+		return timeout
+	}
+	return defaultAITimeouts[op]
+}
+
+// longest returns the longest timeout of any operation
+func (t aiTimeouts) longest() time.Duration {
+	var longest time.Duration
+	for op := range defaultAITimeouts {
+		longest = max(longest, t.get(op))
+	}
+	return longest
+}
+
+// start bounds ctx by the operation's timeout, keeping any earlier deadline
+// ctx already has. The returned function releases the context and turns an
+// error caused by the deadline into a *TimeoutError.
+func (t aiTimeouts) start(ctx context.Context, op string) (context.Context, func(error) error) {
+	timeout := t.get(op)
+	requestDeadline := false
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout, requestDeadline = time.Until(deadline), true
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func(err error) error {
+		cancel()
+		if err != nil && errors.Is(err, context.DeadlineExceeded) {
+			return &TimeoutError{Operation: op, Timeout: timeout, RequestDeadline: requestDeadline, Err: err}
+		}
+		return err
+	}
+}
+
+// withTimeout makes one AI call bounded by the operation's timeout
+func withTimeout[T any](ctx context.Context, timeouts aiTimeouts, op string, call func(ctx context.Context) (T, error)) (T, error) {
+	ctx, done := timeouts.start(ctx, op)
+	result, err := call(ctx)
+	return result, done(err)
+}
+
+// applyAITimeouts sets a service's timeouts, making sure its HTTP client's
+// own timeout, a backstop for calls without one, doesn't cut them short
+func applyAITimeouts(client *http.Client, overrides map[string]time.Duration) aiTimeouts {
+	timeouts := newAITimeouts(overrides)
+	if longest := timeouts.longest(); client.Timeout != 0 && client.Timeout < longest {
+		client.Timeout = longest
+	}
+	return timeouts
+}
+
+// SetTimeouts overrides how long each kind of AI call may take
+func (s *AIScriptService) SetTimeouts(overrides map[string]time.Duration) {
+	s.timeouts = applyAITimeouts(s.httpClient, overrides)
+}
+
+// SetTimeouts overrides how long each kind of AI call may take
+func (s *AISceneService) SetTimeouts(overrides map[string]time.Duration) {
+	s.timeouts = applyAITimeouts(s.httpClient, overrides)
+}
+
+// SetTimeouts overrides how long each kind of AI call may take
+func (s *TTSService) SetTimeouts(overrides map[string]time.Duration) {
+	s.timeouts = applyAITimeouts(s.httpClient, overrides)
+}
+
+// SetTimeouts overrides how long each kind of AI call may take
+func (s *TranscriptionService) SetTimeouts(overrides map[string]time.Duration) {
+	s.timeouts = applyAITimeouts(s.httpClient, overrides)
+}
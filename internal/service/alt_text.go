@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	socialdomain "renderowl-api/internal/domain/social"
+)
+
+// GenerateAltText describes a post's thumbnail, or the image itself for image
+// posts, with a vision model. It returns an empty string when the post has no
+// image URL to look at or OpenAI isn't configured.
+func (s *AISceneService) GenerateAltText(ctx context.Context, req *socialdomain.UploadRequest) (string, error) {
+	return withTimeout(ctx, s.timeouts, AIOpAltText, func(ctx context.Context) (string, error) {
+		return s.generateAltText(ctx, req)
+	})
+}
+
+// generateAltText asks the vision model to describe the post's image
+func (s *AISceneService) generateAltText(ctx context.Context, req *socialdomain.UploadRequest) (string, error) {
+	imageURL := req.ThumbnailURL
+	if req.MediaType == socialdomain.MediaTypeImage && isHTTPURL(req.VideoPath) {
+		imageURL = req.VideoPath
+	}
+	if !isHTTPURL(imageURL) || s.openAIKey == "" {
+		return "", nil
+	}
+
+	prompt := "Write alt text for this image for screen reader users: one or two plain sentences describing what it shows, without starting with \"Image of\"."
+	if req.Title != "" {
+		prompt += "\nIt belongs to a post titled: " + req.Title
+	}
+
+	requestBody := map[string]interface{}{
+		"model": "gpt-4o-mini",
+		"messages": []map[string]interface{}{
+			{
+				"role": "user",
+				"content": []map[string]interface{}{
+					{"type": "text", "text": prompt},
+					{"type": "image_url", "image_url": map[string]string{"url": imageURL}},
+				},
+			},
+		},
+		"temperature": 0.3,
+		"max_tokens":  200,
+	}
+
+	jsonBody, _ := json.Marshal(requestBody)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.openAIBaseURL+"/chat/completions", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	setOpenAIAuth(httpReq, s.openAIKey, s.openAIAccount)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API error: %s", string(body))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage chatUsage `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	s.usage.recordChat(ctx, usageServiceAltText, providerOpenAI, "gpt-4o-mini", result.Usage)
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("no response")
+	}
+
+	return strings.TrimSpace(result.Choices[0].Message.Content), nil
+}
+
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
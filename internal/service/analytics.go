@@ -4,21 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"net/http"
 	"time"
 
 	"renderowl-api/internal/domain"
 	"renderowl-api/internal/repository"
+	"renderowl-api/internal/safehttp"
 )
 
 // AnalyticsService handles analytics business logic
 type AnalyticsService struct {
 	analyticsRepo *repository.AnalyticsRepository
+	httpClient    *http.Client // delivers alert webhooks, to public addresses only
+	statsSources  map[string]VideoStatsSource
 }
 
 // NewAnalyticsService creates a new analytics service
 func NewAnalyticsService(analyticsRepo *repository.AnalyticsRepository) *AnalyticsService {
 	return &AnalyticsService{
 		analyticsRepo: analyticsRepo,
+		httpClient:    safehttp.NewClient(10 * time.Second),
 	}
 }
 
@@ -46,8 +52,18 @@ type DailyViews struct {
 	ByPlatform map[string]int64 `json:"by_platform"`
 }
 
-// GetViewsOverTime gets views over time for a user
-func (s *AnalyticsService) GetViewsOverTime(ctx context.Context, userID string, days int) (*ViewsOverTimeResponse, error) {
+// View bucket granularities for GetViewsOverTime
+const (
+	GranularityDay  = "day"
+	GranularityHour = "hour"
+)
+
+// GetViewsOverTime gets views over time for a user, bucketed by day or hour
+func (s *AnalyticsService) GetViewsOverTime(ctx context.Context, userID string, days int, granularity string) (*ViewsOverTimeResponse, error) {
+	if granularity == GranularityHour {
+		return s.getHourlyViews(ctx, userID, days)
+	}
+
 	startDate := time.Now().UTC().AddDate(0, 0, -days).Truncate(24 * time.Hour)
 	endDate := time.Now().UTC().Truncate(24 * time.Hour)
 	
@@ -90,6 +106,49 @@ func (s *AnalyticsService) GetViewsOverTime(ctx context.Context, userID string,
 	return &ViewsOverTimeResponse{Data: result}, nil
 }
 
+// getHourlyViews gets views per hour for the last N days, newest first
+func (s *AnalyticsService) getHourlyViews(ctx context.Context, userID string, days int) (*ViewsOverTimeResponse, error) {
+	endHour := time.Now().UTC().Truncate(time.Hour)
+	startHour := endHour.Add(-time.Duration(days*24-1) * time.Hour)
+	
+	aggregates, err := s.analyticsRepo.GetViewsByHourRange(ctx, userID, startHour, endHour)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Group by hour
+	hourMap := make(map[string]*DailyViews)
+	for _, agg := range aggregates {
+		hourStr := agg.Date.UTC().Truncate(time.Hour).Format(time.RFC3339)
+		if _, exists := hourMap[hourStr]; !exists {
+			hourMap[hourStr] = &DailyViews{
+				Date:       hourStr,
+				ByPlatform: make(map[string]int64),
+			}
+		}
+		hourMap[hourStr].Total += agg.TotalViews
+		hourMap[hourStr].ByPlatform[agg.Platform] += agg.TotalViews
+	}
+	
+	// Convert to slice and fill missing hours
+	result := make([]DailyViews, 0, days*24)
+	for i := 0; i < days*24; i++ {
+		hourStr := endHour.Add(-time.Duration(i) * time.Hour).Format(time.RFC3339)
+		
+		if data, exists := hourMap[hourStr]; exists {
+			result = append(result, *data)
+		} else {
+			result = append(result, DailyViews{
+				Date:       hourStr,
+				Total:      0,
+				ByPlatform: make(map[string]int64),
+			})
+		}
+	}
+	
+	return &ViewsOverTimeResponse{Data: result}, nil
+}
+
 // PlatformBreakdownResponse represents platform breakdown data
 type PlatformBreakdownResponse struct {
 	Platforms []PlatformData `json:"platforms"`
@@ -181,6 +240,12 @@ func (s *AnalyticsService) TrackEngagement(ctx context.Context, req *TrackEngage
 	return s.analyticsRepo.TrackEngagement(ctx, req.VideoID, req.Platform, req.Likes, req.Comments, req.Shares)
 }
 
+// BatchTrackEngagement records the engagement of many videos at once, for
+// syncs that would otherwise make one upsert per video
+func (s *AnalyticsService) BatchTrackEngagement(ctx context.Context, records []domain.AnalyticsEngagement) error {
+	return s.analyticsRepo.BatchTrackEngagement(ctx, records)
+}
+
 // UserGrowthResponse represents user growth data
 type UserGrowthResponse struct {
 	Data []DailyGrowth `json:"data"`
@@ -307,6 +372,10 @@ func (s *AnalyticsService) GetVideoPerformance(ctx context.Context, userID strin
 	if err != nil {
 		return nil, err
 	}
+	total, err := s.analyticsRepo.CountVideoPerformance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 	
 	videos := make([]VideoMetrics, 0, len(performanceData))
 	for _, p := range performanceData {
@@ -331,7 +400,7 @@ func (s *AnalyticsService) GetVideoPerformance(ctx context.Context, userID strin
 	
 	return &VideoPerformanceResponse{
 		Videos:     videos,
-		TotalCount: int64(len(videos)),
+		TotalCount: total,
 	}, nil
 }
 
@@ -370,6 +439,88 @@ func (s *AnalyticsService) UpdateVideoPerformance(ctx context.Context, req *Upda
 	return s.analyticsRepo.UpdateVideoPerformance(ctx, performance)
 }
 
+// videoAnalyticsDays is how many days of daily views the optimizer gets with
+// each of a user's videos in a list
+const videoAnalyticsDays = 30
+
+// GetVideoAnalytics gets one of a user's videos as the optimizer sees it,
+// with the views it gained on each of the last days
+func (s *AnalyticsService) GetVideoAnalytics(ctx context.Context, userID, videoID string, days int) (*VideoAnalytics, error) {
+	performance, err := s.analyticsRepo.GetVideoPerformanceByVideo(ctx, userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return s.videoAnalytics(ctx, performance, days)
+}
+
+// GetTopPerforming gets a user's most viewed videos as the optimizer sees them
+func (s *AnalyticsService) GetTopPerforming(ctx context.Context, userID string, limit int) ([]*VideoAnalytics, error) {
+	performance, err := s.analyticsRepo.GetVideoPerformance(ctx, userID, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+	return s.videoAnalyticsList(ctx, performance)
+}
+
+// GetUnderperforming gets a user's least viewed videos as the optimizer sees them
+func (s *AnalyticsService) GetUnderperforming(ctx context.Context, userID string, limit int) ([]*VideoAnalytics, error) {
+	performance, err := s.analyticsRepo.GetLowestPerformingVideos(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.videoAnalyticsList(ctx, performance)
+}
+
+func (s *AnalyticsService) videoAnalyticsList(ctx context.Context, performance []repository.VideoPerformanceData) ([]*VideoAnalytics, error) {
+	videos := make([]*VideoAnalytics, 0, len(performance))
+	for i := range performance {
+		video, err := s.videoAnalytics(ctx, &performance[i], videoAnalyticsDays)
+		if err != nil {
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+	return videos, nil
+}
+
+// videoAnalytics turns a video's stored performance into the optimizer's
+// analytics. Only the totals and daily views are tracked; CTR, retention and
+// watch time stay zero.
+func (s *AnalyticsService) videoAnalytics(ctx context.Context, p *repository.VideoPerformanceData, days int) (*VideoAnalytics, error) {
+	analytics := &VideoAnalytics{
+		VideoID:        p.VideoID,
+		Title:          p.Title,
+		Views:          int(p.TotalViews),
+		Likes:          int(p.TotalLikes),
+		Comments:       int(p.TotalComments),
+		Shares:         int(p.TotalShares),
+		EngagementRate: p.EngagementRate,
+	}
+	if len(p.Platforms) > 0 {
+		analytics.Platform = p.Platforms[0]
+	}
+	if p.PublishedAt != nil {
+		analytics.PublishDate = *p.PublishedAt
+		analytics.DaysSincePublish = int(time.Since(*p.PublishedAt).Hours() / 24)
+	}
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	daily, err := s.analyticsRepo.GetDailyViewsByVideo(ctx, p.VideoID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily views: %w", err)
+	}
+	byDay := make(map[string]int64, len(daily))
+	for _, d := range daily {
+		byDay[d.Date.UTC().Format("2006-01-02")] = d.TotalViews
+	}
+	analytics.DailyViews = make([]int, days)
+	for i := range days {
+		analytics.DailyViews[i] = int(byDay[since.AddDate(0, 0, i).Format("2006-01-02")])
+	}
+
+	return analytics, nil
+}
+
 // DashboardSummaryResponse represents the dashboard summary
 type DashboardSummaryResponse struct {
 	TotalViews       int64                `json:"total_views"`
@@ -453,7 +604,7 @@ func (s *AnalyticsService) GetAnalyticsOverview(ctx context.Context, userID stri
 	}
 	
 	// Get views over time
-	viewsOverTime, err := s.GetViewsOverTime(ctx, userID, days)
+	viewsOverTime, err := s.GetViewsOverTime(ctx, userID, days, GranularityDay)
 	if err != nil {
 		viewsOverTime = &ViewsOverTimeResponse{Data: []DailyViews{}}
 	}
@@ -482,6 +633,10 @@ func (s *AnalyticsService) GetAnalyticsOverview(ctx context.Context, userID stri
 	}, nil
 }
 
+// webhookEventMaxAttempts is how many times processing a webhook event is
+// tried before it is marked failed
+const webhookEventMaxAttempts = 5
+
 // WebhookEventRequest represents a webhook event request
 type WebhookEventRequest struct {
 	Platform  string                 `json:"platform" binding:"required"` // youtube, tiktok, instagram
@@ -515,7 +670,26 @@ func (s *AnalyticsService) ProcessWebhookEvents(ctx context.Context, limit int)
 	}
 	
 	for _, event := range events {
-		// Process based on event type and platform
+		// Platforms with a native webhook format have their own parser
+		if parse, ok := webhookParsers[event.Platform]; ok {
+			updates, retry, err := s.webhookUpdates(ctx, &event, parse)
+			if err != nil {
+				// A payload that didn't parse never will; a failed fetch is
+				// tried again a few times. Either way the event is kept with
+				// its error so it can be inspected.
+				log.Printf("Failed to process %s webhook event %s: %v", event.Platform, event.ID, err)
+				final := !retry || event.Attempts+1 >= webhookEventMaxAttempts
+				s.analyticsRepo.RecordWebhookEventFailure(ctx, event.ID, err.Error(), final)
+				continue
+			}
+			for _, update := range updates {
+				s.applyWebhookUpdate(ctx, event.Platform, update)
+			}
+			s.analyticsRepo.MarkWebhookEventProcessed(ctx, event.ID)
+			continue
+		}
+
+		// Generic path for custom integrations
 		switch event.EventType {
 		case "view":
 			if videoID, ok := event.Payload["video_id"].(string); ok {
@@ -551,6 +725,64 @@ func (s *AnalyticsService) ProcessWebhookEvents(ctx context.Context, limit int)
 	return nil
 }
 
+// webhookUpdates turns a webhook event into the updates to record: parsed
+// from its payload and, for videos it only names, built from statistics
+// fetched from the platform. retry reports whether a failure is worth trying
+// again, as a failed fetch is but a payload that didn't parse isn't.
+func (s *AnalyticsService) webhookUpdates(ctx context.Context, event *domain.WebhookEvent, parse webhookParser) (updates []*WebhookUpdate, retry bool, err error) {
+	parsed, err := parse(event)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var fetch []string
+	seen := make(map[string]bool)
+	for _, update := range parsed {
+		if update.Kind != "stats" {
+			updates = append(updates, update)
+			continue
+		}
+		if !seen[update.VideoID] {
+			seen[update.VideoID] = true
+			fetch = append(fetch, update.VideoID)
+		}
+	}
+	if len(fetch) == 0 {
+		return updates, false, nil
+	}
+	source, ok := s.statsSources[event.Platform]
+	if !ok {
+		log.Printf("No statistics source for %s, skipping %d videos of webhook event %s", event.Platform, len(fetch), event.ID)
+		return updates, false, nil
+	}
+
+	stats, err := source.VideoStats(ctx, fetch)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to fetch video statistics: %w", err)
+	}
+	for _, videoID := range fetch {
+		videoStats, ok := stats[videoID]
+		if !ok {
+			continue
+		}
+		updates = append(updates,
+			&WebhookUpdate{Kind: "view", VideoID: videoID, Views: videoStats.Views},
+			&WebhookUpdate{Kind: "engagement", VideoID: videoID, Likes: videoStats.Likes, Comments: videoStats.Comments},
+		)
+	}
+	return updates, false, nil
+}
+
+// applyWebhookUpdate records a normalized webhook update
+func (s *AnalyticsService) applyWebhookUpdate(ctx context.Context, platform string, update *WebhookUpdate) {
+	switch update.Kind {
+	case "view":
+		s.analyticsRepo.RecordViews(ctx, update.VideoID, platform, update.Views)
+	case "engagement":
+		s.analyticsRepo.TrackEngagement(ctx, update.VideoID, platform, update.Likes, update.Comments, update.Shares)
+	}
+}
+
 // ExportAnalyticsRequest represents an analytics export request
 type ExportAnalyticsRequest struct {
 	UserID    string    `json:"user_id" binding:"required"`
@@ -0,0 +1,414 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/safehttp"
+)
+
+// Analytics alert errors
+var (
+	// ErrAlertNotFound is returned when an alert doesn't exist or belongs to another user
+	ErrAlertNotFound = errors.New("alert not found")
+	// ErrInvalidWebhookURL is returned for webhook URLs that aren't on a public host
+	ErrInvalidWebhookURL = errors.New("webhook URL must be an http or https URL on a public host")
+)
+
+// Alert webhook headers. The signature is the hex HMAC-SHA256, keyed with the
+// alert's secret, of the timestamp, a dot and the request body.
+const (
+	AlertSignatureHeader = "X-Renderowl-Signature"
+	AlertTimestampHeader = "X-Renderowl-Timestamp"
+)
+
+// alertEvent is the event name of threshold webhooks
+const alertEvent = "analytics.threshold_crossed"
+
+// alertWebhookAttempts is how many times a threshold webhook is tried before
+// it is given up on
+const alertWebhookAttempts = 3
+
+// alertRetryWait is how long to wait after a failed delivery, times the
+// attempt number
+var alertRetryWait = 5 * time.Second
+
+// alertMetrics reads each alertable metric off a video's performance
+var alertMetrics = map[string]func(*domain.VideoPerformance) float64{
+	"views":           func(v *domain.VideoPerformance) float64 { return float64(v.TotalViews) },
+	"likes":           func(v *domain.VideoPerformance) float64 { return float64(v.TotalLikes) },
+	"comments":        func(v *domain.VideoPerformance) float64 { return float64(v.TotalComments) },
+	"shares":          func(v *domain.VideoPerformance) float64 { return float64(v.TotalShares) },
+	"engagement_rate": func(v *domain.VideoPerformance) float64 { return v.EngagementRate },
+}
+
+// AnalyticsAlertRequest creates or replaces an analytics alert
+type AnalyticsAlertRequest struct {
+	Name       string  `json:"name"`
+	Metric     string  `json:"metric" binding:"required,oneof=views likes comments shares engagement_rate"`
+	Threshold  float64 `json:"threshold" binding:"gt=0"` // engagement_rate is in percent, e.g. 10 for 10%
+	WebhookURL string  `json:"webhook_url" binding:"required,url"`
+	Enabled    *bool   `json:"enabled"` // defaults to true
+}
+
+// AnalyticsAlertResponse represents an analytics alert. Secret signs its
+// webhooks so receivers can verify them.
+type AnalyticsAlertResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Metric      string     `json:"metric"`
+	Threshold   float64    `json:"threshold"`
+	WebhookURL  string     `json:"webhook_url"`
+	Secret      string     `json:"secret"`
+	Enabled     bool       `json:"enabled"`
+	FiredCount  int        `json:"fired_count"` // videos it has reported
+	LastFiredAt *time.Time `json:"last_fired_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// AlertWebhookPayload is the body of a threshold webhook
+type AlertWebhookPayload struct {
+	Event     string            `json:"event"`
+	AlertID   string            `json:"alert_id"`
+	AlertName string            `json:"alert_name,omitempty"`
+	Metric    string            `json:"metric"`
+	Threshold float64           `json:"threshold"`
+	Value     float64           `json:"value"`
+	Video     AlertWebhookVideo `json:"video"`
+	FiredAt   time.Time         `json:"fired_at"`
+}
+
+// AlertWebhookVideo is the video a threshold webhook reports on
+type AlertWebhookVideo struct {
+	ID             string  `json:"id"`
+	Title          string  `json:"title"`
+	Views          int64   `json:"views"`
+	Likes          int64   `json:"likes"`
+	Comments       int64   `json:"comments"`
+	Shares         int64   `json:"shares"`
+	EngagementRate float64 `json:"engagement_rate"`
+}
+
+// ListAlerts returns a user's analytics alerts with when they last fired
+func (s *AnalyticsService) ListAlerts(ctx context.Context, userID string) ([]*AnalyticsAlertResponse, error) {
+	alerts, err := s.analyticsRepo.ListAlerts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	if len(alerts) == 0 {
+		return []*AnalyticsAlertResponse{}, nil
+	}
+
+	alertIDs := make([]string, len(alerts))
+	for i, alert := range alerts {
+		alertIDs[i] = alert.ID
+	}
+	firings, err := s.analyticsRepo.ListAlertFirings(ctx, alertIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert firings: %w", err)
+	}
+	byAlert := make(map[string][]*domain.AnalyticsAlertFiring)
+	for _, firing := range firings {
+		byAlert[firing.AlertID] = append(byAlert[firing.AlertID], firing)
+	}
+
+	responses := make([]*AnalyticsAlertResponse, len(alerts))
+	for i, alert := range alerts {
+		responses[i] = toAlertResponse(alert, byAlert[alert.ID])
+	}
+	return responses, nil
+}
+
+// CreateAlert registers an analytics alert with a fresh signing secret. Videos
+// already past the threshold are recorded as its baseline and not reported.
+func (s *AnalyticsService) CreateAlert(ctx context.Context, userID string, req *AnalyticsAlertRequest) (*AnalyticsAlertResponse, error) {
+	if err := checkWebhookURL(ctx, req.WebhookURL); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	alert := &domain.AnalyticsAlert{
+		UserID:     userID,
+		Name:       req.Name,
+		Metric:     req.Metric,
+		Threshold:  req.Threshold,
+		WebhookURL: req.WebhookURL,
+		Secret:     hex.EncodeToString(secret),
+		Enabled:    req.Enabled == nil || *req.Enabled,
+	}
+	baseline, err := s.alertBaseline(ctx, alert)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.analyticsRepo.CreateAlert(ctx, alert, baseline); err != nil {
+		return nil, fmt.Errorf("failed to create alert: %w", err)
+	}
+	return toAlertResponse(alert, nil), nil
+}
+
+// UpdateAlert replaces an alert's rule. Changing the metric or threshold
+// forgets the videos it fired for and takes a new baseline, so only videos
+// that cross the new rule afterwards are reported.
+func (s *AnalyticsService) UpdateAlert(ctx context.Context, userID, id string, req *AnalyticsAlertRequest) (*AnalyticsAlertResponse, error) {
+	alert, err := s.analyticsRepo.GetAlert(ctx, userID, id)
+	if err != nil {
+		return nil, ErrAlertNotFound
+	}
+	if err := checkWebhookURL(ctx, req.WebhookURL); err != nil {
+		return nil, err
+	}
+
+	reset := alert.Metric != req.Metric || alert.Threshold != req.Threshold
+	alert.Name = req.Name
+	alert.Metric = req.Metric
+	alert.Threshold = req.Threshold
+	alert.WebhookURL = req.WebhookURL
+	if req.Enabled != nil {
+		alert.Enabled = *req.Enabled
+	}
+	var baseline []*domain.AnalyticsAlertFiring
+	if reset {
+		if baseline, err = s.alertBaseline(ctx, alert); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.analyticsRepo.UpdateAlert(ctx, alert, reset, baseline); err != nil {
+		return nil, fmt.Errorf("failed to update alert: %w", err)
+	}
+
+	var firings []*domain.AnalyticsAlertFiring
+	if !reset {
+		if firings, err = s.analyticsRepo.ListAlertFirings(ctx, []string{alert.ID}); err != nil {
+			return nil, fmt.Errorf("failed to list alert firings: %w", err)
+		}
+	}
+	return toAlertResponse(alert, firings), nil
+}
+
+// DeleteAlert deletes one of a user's analytics alerts
+func (s *AnalyticsService) DeleteAlert(ctx context.Context, userID, id string) error {
+	if err := s.analyticsRepo.DeleteAlert(ctx, userID, id); err != nil {
+		return ErrAlertNotFound
+	}
+	return nil
+}
+
+// alertBaseline returns firings for the user's videos that have already
+// reached the alert's threshold
+func (s *AnalyticsService) alertBaseline(ctx context.Context, alert *domain.AnalyticsAlert) ([]*domain.AnalyticsAlertFiring, error) {
+	videos, err := s.analyticsRepo.ListVideoPerformanceByUsers(ctx, []string{alert.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list video performance: %w", err)
+	}
+	return baselineFirings(alert, videos), nil
+}
+
+// baselineFirings returns baseline firings for the videos that have reached
+// the alert's threshold
+func baselineFirings(alert *domain.AnalyticsAlert, videos []*domain.VideoPerformance) []*domain.AnalyticsAlertFiring {
+	metric, ok := alertMetrics[alert.Metric]
+	if !ok {
+		return nil
+	}
+	now := time.Now().UTC()
+	var baseline []*domain.AnalyticsAlertFiring
+	for _, video := range videos {
+		if value := metric(video); value >= alert.Threshold {
+			baseline = append(baseline, &domain.AnalyticsAlertFiring{
+				AlertID:  alert.ID,
+				VideoID:  video.VideoID,
+				Value:    value,
+				Baseline: true,
+				FiredAt:  now,
+			})
+		}
+	}
+	return baseline
+}
+
+// checkWebhookURL refuses webhook URLs that would be sent to the server's own
+// network. Deliveries are checked again when sent, as DNS can change.
+func checkWebhookURL(ctx context.Context, rawURL string) error {
+	if err := safehttp.CheckURL(ctx, rawURL); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookURL, err)
+	}
+	return nil
+}
+
+// CheckAlerts fires the webhooks of the users' enabled alerts for every video
+// that has reached the alert's threshold and not been reported yet. A firing
+// is recorded before its webhook is sent, so each video is reported once;
+// delivery runs in the background and is retried a few times.
+func (s *AnalyticsService) CheckAlerts(ctx context.Context, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	alerts, err := s.analyticsRepo.ListEnabledAlerts(ctx, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to list alerts: %w", err)
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	alertIDs := make([]string, len(alerts))
+	for i, alert := range alerts {
+		alertIDs[i] = alert.ID
+	}
+	firings, err := s.analyticsRepo.ListAlertFirings(ctx, alertIDs)
+	if err != nil {
+		return fmt.Errorf("failed to list alert firings: %w", err)
+	}
+	fired := make(map[string]bool, len(firings))
+	for _, firing := range firings {
+		fired[firing.AlertID+"/"+firing.VideoID] = true
+	}
+
+	videos, err := s.analyticsRepo.ListVideoPerformanceByUsers(ctx, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to list video performance: %w", err)
+	}
+
+	for _, alert := range alerts {
+		metric, ok := alertMetrics[alert.Metric]
+		if !ok {
+			continue
+		}
+		for _, video := range videos {
+			if video.UserID != alert.UserID || fired[alert.ID+"/"+video.VideoID] {
+				continue
+			}
+			value := metric(video)
+			if value < alert.Threshold {
+				continue
+			}
+
+			firing := &domain.AnalyticsAlertFiring{
+				AlertID: alert.ID,
+				VideoID: video.VideoID,
+				Value:   value,
+				FiredAt: time.Now().UTC(),
+			}
+			if err := s.analyticsRepo.RecordAlertFiring(ctx, firing); err != nil {
+				log.Printf("Failed to record firing of alert %s for video %s: %v", alert.ID, video.VideoID, err)
+				continue
+			}
+			go s.deliverAlertWebhook(context.WithoutCancel(ctx), alert, video, firing)
+		}
+	}
+	return nil
+}
+
+// deliverAlertWebhook sends a threshold webhook, trying a few times before
+// giving up. Failures only log: the firing is already recorded.
+func (s *AnalyticsService) deliverAlertWebhook(ctx context.Context, alert *domain.AnalyticsAlert, video *domain.VideoPerformance, firing *domain.AnalyticsAlertFiring) error {
+	for attempt := 1; ; attempt++ {
+		err := s.sendAlertWebhook(ctx, alert, video, firing)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, safehttp.ErrBlockedAddress) || attempt >= alertWebhookAttempts {
+			log.Printf("Failed to deliver alert %s for video %s: %v", alert.ID, video.VideoID, err)
+			return err
+		}
+		log.Printf("Alert %s webhook for video %s failed (attempt %d/%d): %v", alert.ID, video.VideoID, attempt, alertWebhookAttempts, err)
+		time.Sleep(time.Duration(attempt) * alertRetryWait)
+	}
+}
+
+// sendAlertWebhook posts a signed threshold event to the alert's webhook
+func (s *AnalyticsService) sendAlertWebhook(ctx context.Context, alert *domain.AnalyticsAlert, video *domain.VideoPerformance, firing *domain.AnalyticsAlertFiring) error {
+	body, err := json.Marshal(AlertWebhookPayload{
+		Event:     alertEvent,
+		AlertID:   alert.ID,
+		AlertName: alert.Name,
+		Metric:    alert.Metric,
+		Threshold: alert.Threshold,
+		Value:     firing.Value,
+		Video: AlertWebhookVideo{
+			ID:             video.VideoID,
+			Title:          video.Title,
+			Views:          video.TotalViews,
+			Likes:          video.TotalLikes,
+			Comments:       video.TotalComments,
+			Shares:         video.TotalShares,
+			EngagementRate: video.EngagementRate,
+		},
+		FiredAt: firing.FiredAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(firing.FiredAt.Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, "POST", alert.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(AlertTimestampHeader, timestamp)
+	req.Header.Set(AlertSignatureHeader, "sha256="+signAlertPayload(alert.Secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// signAlertPayload computes a webhook's signature
+func signAlertPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func toAlertResponse(alert *domain.AnalyticsAlert, firings []*domain.AnalyticsAlertFiring) *AnalyticsAlertResponse {
+	resp := &AnalyticsAlertResponse{
+		ID:         alert.ID,
+		Name:       alert.Name,
+		Metric:     alert.Metric,
+		Threshold:  alert.Threshold,
+		WebhookURL: alert.WebhookURL,
+		Secret:     alert.Secret,
+		Enabled:    alert.Enabled,
+		CreatedAt:  alert.CreatedAt,
+	}
+	for _, firing := range firings {
+		if firing.Baseline {
+			continue
+		}
+		resp.FiredCount++
+		if resp.LastFiredAt == nil || firing.FiredAt.After(*resp.LastFiredAt) {
+			firedAt := firing.FiredAt
+			resp.LastFiredAt = &firedAt
+		}
+	}
+	return resp
+}
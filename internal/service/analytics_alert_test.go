@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/safehttp"
+)
+
+func TestAlertWebhooksOnlyGoToPublicHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook reached the internal server")
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	s := NewAnalyticsService(nil)
+	for _, webhookURL := range []string{server.URL, "http://169.254.169.254/latest/meta-data/", "file:///etc/passwd"} {
+		if _, err := s.CreateAlert(ctx, "user-a", &AnalyticsAlertRequest{Metric: "views", Threshold: 1, WebhookURL: webhookURL}); !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Errorf("CreateAlert with %s = %v, want ErrInvalidWebhookURL", webhookURL, err)
+		}
+	}
+
+	// An alert saved with a host that later resolves internally is still
+	// refused when it fires
+	alert := &domain.AnalyticsAlert{ID: "alert-1", Metric: "views", WebhookURL: server.URL, Secret: "secret"}
+	firing := &domain.AnalyticsAlertFiring{Value: 10, FiredAt: time.Now()}
+	err := s.sendAlertWebhook(ctx, alert, &domain.VideoPerformance{VideoID: "video-1"}, firing)
+	if !errors.Is(err, safehttp.ErrBlockedAddress) {
+		t.Errorf("sendAlertWebhook = %v, want ErrBlockedAddress", err)
+	}
+}
+
+func TestAlertBaselineSkipsVideosAlreadyPastTheThreshold(t *testing.T) {
+	alert := &domain.AnalyticsAlert{ID: "alert-1", Metric: "views", Threshold: 1000}
+	videos := []*domain.VideoPerformance{
+		{VideoID: "video-1", TotalViews: 5000},
+		{VideoID: "video-2", TotalViews: 999},
+		{VideoID: "video-3", TotalViews: 1000},
+	}
+
+	baseline := baselineFirings(alert, videos)
+	if len(baseline) != 2 || baseline[0].VideoID != "video-1" || baseline[1].VideoID != "video-3" {
+		t.Fatalf("baseline = %+v, want video-1 and video-3", baseline)
+	}
+	for _, firing := range baseline {
+		if !firing.Baseline {
+			t.Errorf("firing for %s is not marked as baseline", firing.VideoID)
+		}
+	}
+
+	firedAt := time.Now()
+	firings := append(baseline, &domain.AnalyticsAlertFiring{AlertID: "alert-1", VideoID: "video-2", Value: 1200, FiredAt: firedAt})
+	resp := toAlertResponse(alert, firings)
+	if resp.FiredCount != 1 || resp.LastFiredAt == nil || !resp.LastFiredAt.Equal(firedAt) {
+		t.Errorf("fired %d times, last at %v, want only the firing after the baseline", resp.FiredCount, resp.LastFiredAt)
+	}
+}
+
+func TestAlertWebhooksAreRetried(t *testing.T) {
+	defer func(wait time.Duration) { alertRetryWait = wait }(alertRetryWait)
+	alertRetryWait = 0
+
+	attempts, failures := 0, alertWebhookAttempts-1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	s := NewAnalyticsService(nil)
+	s.httpClient = server.Client()
+	alert := &domain.AnalyticsAlert{ID: "alert-1", Metric: "views", WebhookURL: server.URL, Secret: "secret"}
+	firing := &domain.AnalyticsAlertFiring{Value: 10, FiredAt: time.Now()}
+	if err := s.deliverAlertWebhook(context.Background(), alert, &domain.VideoPerformance{VideoID: "video-1"}, firing); err != nil {
+		t.Fatalf("deliverAlertWebhook: %v", err)
+	}
+	if attempts != alertWebhookAttempts {
+		t.Errorf("delivered after %d attempts, want %d", attempts, alertWebhookAttempts)
+	}
+
+	attempts, failures = 0, alertWebhookAttempts
+	if err := s.deliverAlertWebhook(context.Background(), alert, &domain.VideoPerformance{VideoID: "video-2"}, firing); err == nil {
+		t.Error("deliverAlertWebhook succeeded against a failing webhook")
+	}
+	if attempts != alertWebhookAttempts {
+		t.Errorf("gave up after %d attempts, want %d", attempts, alertWebhookAttempts)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	socialdomain "renderowl-api/internal/domain/social"
+	"renderowl-api/internal/scheduler"
+)
+
+// JobCleanupArtifacts deletes generated artifacts past their retention
+const JobCleanupArtifacts = "cleanup-artifacts"
+
+// artifactPrefixes maps each generated artifact type to the storage prefix
+// it is kept under. Generated images and speech are returned inline rather
+// than stored, so thumbnails are the only type kept in storage.
+var artifactPrefixes = map[string]string{
+	"thumbnails": "thumbnails/",
+}
+
+// ArtifactStorage lists, serves and deletes stored artifacts
+type ArtifactStorage interface {
+	MediaStorage
+	List(ctx context.Context, prefix string) ([]StoredFile, error)
+}
+
+// ArtifactReferenceStore tracks which records point at artifacts by URL
+type ArtifactReferenceStore interface {
+	ReferencedArtifacts(ctx context.Context, urls []string) (map[string]bool, error)
+	ClearArtifactURLs(ctx context.Context, urls []string) (int64, error)
+}
+
+// ArtifactRetentionService deletes generated thumbnails once they are older
+// than their type's retention. Artifacts a published post or a saved
+// template uses are kept.
+type ArtifactRetentionService struct {
+	storage   ArtifactStorage
+	refs      ArtifactReferenceStore
+	scheduler *scheduler.Scheduler
+	retention map[string]time.Duration
+}
+
+// NewArtifactRetentionService creates an artifact retention service.
+// Retention is keyed by artifact type; types left out are kept forever.
+func NewArtifactRetentionService(storage ArtifactStorage, refs ArtifactReferenceStore, sched *scheduler.Scheduler, retention map[string]time.Duration) *ArtifactRetentionService {
+	for artifactType := range retention {
+		if _, ok := artifactPrefixes[artifactType]; !ok {
+			log.Printf("Warning: ignoring retention for unknown artifact type %q", artifactType)
+		}
+	}
+	return &ArtifactRetentionService{
+		storage:   storage,
+		refs:      refs,
+		scheduler: sched,
+		retention: retention,
+	}
+}
+
+// Initialize registers the cleanup job handler
+func (s *ArtifactRetentionService) Initialize() {
+	s.scheduler.RegisterHandler(JobCleanupArtifacts, s.handleCleanupJob)
+}
+
+// ScheduleCleanup sets up the daily artifact cleanup job
+func (s *ArtifactRetentionService) ScheduleCleanup(ctx context.Context) error {
+	rule := &socialdomain.RecurringRule{Frequency: "daily", Interval: 1}
+	return s.scheduler.AddRecurringJob(ctx, JobCleanupArtifacts, nil, rule, s.handleCleanupJob)
+}
+
+func (s *ArtifactRetentionService) handleCleanupJob(ctx context.Context, job *scheduler.Job) error {
+	return s.Cleanup(ctx)
+}
+
+// Cleanup deletes every artifact type's expired artifacts and clears the
+// URLs that pointed at them. A type that fails doesn't stop the others.
+func (s *ArtifactRetentionService) Cleanup(ctx context.Context) error {
+	types := make([]string, 0, len(s.retention))
+	for artifactType := range s.retention {
+		types = append(types, artifactType)
+	}
+	slices.Sort(types)
+
+	var errs []error
+	for _, artifactType := range types {
+		prefix, ok := artifactPrefixes[artifactType]
+		if !ok || s.retention[artifactType] <= 0 {
+			continue
+		}
+		if err := s.cleanupType(ctx, artifactType, prefix, time.Now().Add(-s.retention[artifactType])); err != nil {
+			log.Printf("Artifact cleanup of %s failed: %v", artifactType, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cleanupType deletes one artifact type's unreferenced artifacts stored
+// before cutoff
+func (s *ArtifactRetentionService) cleanupType(ctx context.Context, artifactType, prefix string, cutoff time.Time) error {
+	files, err := s.storage.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]string)
+	urls := make([]string, 0)
+	for _, file := range files {
+		if file.ModTime.Before(cutoff) {
+			url := s.storage.GetURL(file.Key)
+			keys[url] = file.Key
+			urls = append(urls, url)
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	referenced, err := s.refs.ReferencedArtifacts(ctx, urls)
+	if err != nil {
+		return err
+	}
+
+	deleted := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if referenced[url] {
+			continue
+		}
+		if err := s.storage.Delete(ctx, keys[url]); err != nil && !errors.Is(err, ErrFileNotFound) {
+			log.Printf("Failed to delete expired artifact %s: %v", keys[url], err)
+			continue
+		}
+		deleted = append(deleted, url)
+	}
+
+	cleared, err := s.refs.ClearArtifactURLs(ctx, deleted)
+	log.Printf("Artifact cleanup: deleted %d of %d expired %s (%d kept for published posts or templates), cleared %d references",
+		len(deleted), len(urls), artifactType, len(referenced), cleared)
+	return err
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"renderowl-api/internal/domain"
+)
+
+// ErrNoAudio is returned when a timeline has no audible audio clips to mix
+var ErrNoAudio = errors.New("timeline has no audible audio")
+
+// AudioMix is an ffmpeg audio mix of a timeline's audio tracks. Inputs are
+// passed in order as -i arguments, each preceded by its InputOptions (the
+// -ss/-to of the clip's source range); FilterComplex reads them as [0:a],
+// [1:a]... and writes the final mix to OutputLabel.
+type AudioMix struct {
+	Inputs        []string   `json:"inputs"`
+	InputOptions  [][]string `json:"inputOptions"`
+	FilterComplex string     `json:"filterComplex"`
+	OutputLabel   string     `json:"outputLabel"`
+}
+
+// Args returns the ffmpeg arguments that render the mix to output
+func (m *AudioMix) Args(output string) []string {
+	args := make([]string, 0, len(m.Inputs)*6+6)
+	for i, input := range m.Inputs {
+		if i < len(m.InputOptions) {
+			args = append(args, m.InputOptions[i]...)
+		}
+		args = append(args, "-i", input)
+	}
+	return append(args, "-filter_complex", m.FilterComplex, "-map", "["+m.OutputLabel+"]", output)
+}
+
+// mixInterval is a span of timeline time, in seconds
+type mixInterval struct {
+	start, end float64
+}
+
+// BuildAudioMix builds the audio mix for a timeline. Muted tracks are left
+// out, and when any track is soloed only soloed tracks play. Music and other
+// tracks with ducking set are lowered while a narration track is playing.
+func BuildAudioMix(timeline *domain.Timeline) (*AudioMix, error) {
+	var tracks []domain.Track
+	soloed := false
+	for _, track := range timeline.Tracks {
+		if track.Type == "audio" && track.Solo {
+			soloed = true
+		}
+	}
+	for _, track := range timeline.Tracks {
+		if track.Type != "audio" || track.Muted || (soloed && !track.Solo) {
+			continue
+		}
+		tracks = append(tracks, track)
+	}
+	sort.SliceStable(tracks, func(i, j int) bool { return tracks[i].Order < tracks[j].Order })
+
+	narration := narrationIntervals(tracks)
+
+	mix := &AudioMix{OutputLabel: "aout"}
+	var filters, trackLabels []string
+	for t, track := range tracks {
+		var clipLabels []string
+		for _, clip := range track.Clips {
+			// A source range shorter than the clip's place on the timeline
+			// leaves silence after it rather than stretching
+			in, out := clip.SourceIn(), clip.SourceOut()
+			duration := math.Min(clip.EndTime-clip.StartTime, out-in)
+			if clip.SourceURL == "" || duration <= 0 {
+				continue
+			}
+
+			input := len(mix.Inputs)
+			mix.Inputs = append(mix.Inputs, clip.SourceURL)
+			mix.InputOptions = append(mix.InputOptions, []string{"-ss", formatSeconds(in), "-to", formatSeconds(out)})
+			label := fmt.Sprintf("c%d", input)
+			delay := int64(math.Round(clip.StartTime * 1000))
+			filters = append(filters, fmt.Sprintf("[%d:a]atrim=duration=%s,asetpts=PTS-STARTPTS%s,adelay=%d:all=1[%s]",
+				input, formatSeconds(duration), clipAudioFilters(clip, duration), delay, label))
+			clipLabels = append(clipLabels, "["+label+"]")
+		}
+		if len(clipLabels) == 0 {
+			continue
+		}
+
+		var chain string
+		if len(clipLabels) == 1 {
+			chain = clipLabels[0] + "anull"
+		} else {
+			chain = fmt.Sprintf("%samix=inputs=%d:normalize=0", strings.Join(clipLabels, ""), len(clipLabels))
+		}
+		if track.Role != domain.TrackRoleNarration && track.Ducking != nil {
+			if volume := duckingVolume(track.Ducking, narration); volume != "" {
+				chain += "," + volume
+			}
+		}
+		label := fmt.Sprintf("t%d", t)
+		filters = append(filters, chain+"["+label+"]")
+		trackLabels = append(trackLabels, "["+label+"]")
+	}
+
+	switch len(trackLabels) {
+	case 0:
+		return nil, ErrNoAudio
+	case 1:
+		filters = append(filters, trackLabels[0]+"anull["+mix.OutputLabel+"]")
+	default:
+		filters = append(filters, fmt.Sprintf("%samix=inputs=%d:normalize=0[%s]",
+			strings.Join(trackLabels, ""), len(trackLabels), mix.OutputLabel))
+	}
+
+	mix.FilterComplex = strings.Join(filters, ";")
+	return mix, nil
+}
+
+// clipAudioFilters returns the volume and fade filters for a clip, each with
+// a leading comma, or "" when the clip plays as is. Fade times are relative to
+// the trimmed clip.
+func clipAudioFilters(clip domain.Clip, duration float64) string {
+	var filters strings.Builder
+	if clip.Volume != 1 {
+		fmt.Fprintf(&filters, ",volume=%s", formatSeconds(clip.Volume))
+	}
+	if clip.FadeIn > 0 {
+		fmt.Fprintf(&filters, ",afade=t=in:st=0:d=%s", formatSeconds(clip.FadeIn))
+	}
+	if clip.FadeOut > 0 {
+		fmt.Fprintf(&filters, ",afade=t=out:st=%s:d=%s", formatSeconds(math.Max(duration-clip.FadeOut, 0)), formatSeconds(clip.FadeOut))
+	}
+	return filters.String()
+}
+
+// narrationIntervals returns when narration tracks are playing, with
+// overlapping and touching clips merged
+func narrationIntervals(tracks []domain.Track) []mixInterval {
+	var intervals []mixInterval
+	for _, track := range tracks {
+		if track.Role != domain.TrackRoleNarration {
+			continue
+		}
+		for _, clip := range track.Clips {
+			if clip.SourceURL != "" && clip.EndTime > clip.StartTime {
+				intervals = append(intervals, mixInterval{clip.StartTime, clip.EndTime})
+			}
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start < intervals[j].start })
+
+	var merged []mixInterval
+	for _, iv := range intervals {
+		if n := len(merged); n > 0 && iv.start <= merged[n-1].end {
+			merged[n-1].end = math.Max(merged[n-1].end, iv.end)
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// duckingVolume returns a volume filter that ramps a track down to the ducked
+// gain over the attack before each narration interval and back up over the
+// release after it. It returns "" when there is no narration to duck under.
+func duckingVolume(d *domain.Ducking, narration []mixInterval) string {
+	if len(narration) == 0 || d.AmountDB <= 0 {
+		return ""
+	}
+
+	attack := math.Max(float64(d.AttackMs)/1000, 0.001)
+	release := math.Max(float64(d.ReleaseMs)/1000, 0.001)
+	gain := math.Pow(10, -d.AmountDB/20)
+
+	// Each envelope is 1 while narration plays and falls to 0 outside the
+	// attack and release ramps; the loudest one decides how far to duck
+	envelopes := make([]string, len(narration))
+	for i, iv := range narration {
+		envelopes[i] = fmt.Sprintf("clip(min((t-(%s))/%s,(%s-t)/%s),0,1)",
+			formatSeconds(iv.start-attack), formatSeconds(attack), formatSeconds(iv.end+release), formatSeconds(release))
+	}
+	envelope := envelopes[0]
+	for _, e := range envelopes[1:] {
+		envelope = fmt.Sprintf("max(%s,%s)", envelope, e)
+	}
+
+	return fmt.Sprintf("volume='1-%s*%s':eval=frame", formatSeconds(1-gain), envelope)
+}
+
+// formatSeconds formats a time or factor for a filter graph without trailing zeros
+func formatSeconds(v float64) string {
+	return fmt.Sprintf("%g", math.Round(v*1e6)/1e6)
+}
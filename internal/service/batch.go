@@ -3,13 +3,17 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"renderowl-api/internal/domain"
+	"renderowl-api/internal/events"
 )
 
 // Task types for batch processing
@@ -21,14 +25,29 @@ const (
 // BatchService manages batch video generation with queue processing
 type BatchService struct {
 	repo            domain.BatchRepository
+	templateRepo    domain.BatchTemplateRepository
 	queue           *asynq.Client
 	inspector       *asynq.Inspector
+	server          *asynq.Server
+	redisOpt        asynq.RedisClientOpt
 	timelineService *TimelineService
 	clipService     *ClipService
 	aiScriptService *AIScriptService
 	aiSceneService  *AISceneService
 	ttsService      *TTSService
+	trackService    *TrackService
+	music           *MusicLibrary
+	prober          MediaProber
 	workerCount     int
+	queueWeights    map[string]int
+
+	events EventPublisher
+}
+
+// EventPublisher publishes events for other processes, such as renderers,
+// to pick up
+type EventPublisher interface {
+	Publish(ctx context.Context, eventType string, data interface{}) error
 }
 
 // CreateBatchRequest represents a request to create a batch
@@ -46,6 +65,21 @@ type VideoInput struct {
 	Config      domain.VideoConfig `json:"config,omitempty"`
 }
 
+// SaveBatchTemplateRequest represents a request to save a batch template
+type SaveBatchTemplateRequest struct {
+	Name          string             `json:"name" binding:"required"`
+	Description   string             `json:"description,omitempty"`
+	Config        domain.BatchConfig `json:"config" binding:"required"`
+	VideoDefaults domain.VideoConfig `json:"videoDefaults,omitempty"`
+}
+
+// CreateBatchFromTemplateRequest represents a request to create a batch from a saved template
+type CreateBatchFromTemplateRequest struct {
+	Name        string       `json:"name" binding:"required"`
+	Description string       `json:"description,omitempty"`
+	Videos      []VideoInput `json:"videos" binding:"required,min=1,max=30"`
+}
+
 // BatchProgress represents the progress of a batch
 type BatchProgress struct {
 	BatchID      string  `json:"batchId"`
@@ -61,17 +95,21 @@ type BatchProgress struct {
 
 // QueueStats represents queue statistics
 type QueueStats struct {
-	Pending   int `json:"pending"`
-	Active    int `json:"active"`
-	Completed int `json:"completed"`
-	Failed    int `json:"failed"`
-	Scheduled int `json:"scheduled"`
-	Retry     int `json:"retry"`
+	Pending              int                    `json:"pending"`
+	Active               int                    `json:"active"`
+	Completed            int                    `json:"completed"`
+	Failed               int                    `json:"failed"`
+	Scheduled            int                    `json:"scheduled"`
+	Retry                int                    `json:"retry"`
+	OldestPendingSeconds float64                `json:"oldestPendingSeconds"` // How long the oldest pending task has waited
+	Queues               map[string]*QueueStats `json:"queues,omitempty"`     // Per-queue breakdown of the totals
+	Workers              *WorkerConfig          `json:"workers,omitempty"`
 }
 
 // NewBatchService creates a new batch service
 func NewBatchService(
 	repo domain.BatchRepository,
+	templateRepo domain.BatchTemplateRepository,
 	redisAddr string,
 	redisPassword string,
 	timelineService *TimelineService,
@@ -80,33 +118,70 @@ func NewBatchService(
 	aiSceneService *AISceneService,
 	ttsService *TTSService,
 ) (*BatchService, error) {
-	queue := asynq.NewClient(asynq.RedisClientOpt{
+	redisOpt := asynq.RedisClientOpt{
 		Addr:     redisAddr,
 		Password: redisPassword,
 		DB:       0,
-	})
+	}
 
-	inspector := asynq.NewInspector(asynq.RedisClientOpt{
-		Addr:     redisAddr,
-		Password: redisPassword,
-		DB:       0,
-	})
+	queue := asynq.NewClient(redisOpt)
+	inspector := asynq.NewInspector(redisOpt)
 
 	return &BatchService{
 		repo:            repo,
+		templateRepo:    templateRepo,
 		queue:           queue,
 		inspector:       inspector,
+		redisOpt:        redisOpt,
 		timelineService: timelineService,
 		clipService:     clipService,
 		aiScriptService: aiScriptService,
 		aiSceneService:  aiSceneService,
 		ttsService:      ttsService,
-		workerCount:     3,
+		workerCount:     defaultWorkerCount,
+		queueWeights:    defaultQueueWeights,
 	}, nil
 }
 
+// SetMusicLibrary lets batches pick background music, laid on its own
+// track of each video's timeline
+func (s *BatchService) SetMusicLibrary(library *MusicLibrary, trackService *TrackService) {
+	s.music = library
+	s.trackService = trackService
+}
+
+// SetEventPublisher enables publishing a timeline.ready event for each
+// batch video whose timeline is assembled
+func (s *BatchService) SetEventPublisher(events EventPublisher) {
+	s.events = events
+}
+
 // CreateBatch creates a new batch job
 func (s *BatchService) CreateBatch(ctx context.Context, userID string, req *CreateBatchRequest) (*domain.Batch, error) {
+	if req.Config.MusicTrackID != "" {
+		if s.music == nil {
+			return nil, ErrMusicTrackNotFound
+		}
+		if _, err := s.music.Get(req.Config.MusicTrackID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := findImageStyle(req.Config.ImageStyle); err != nil {
+		return nil, err
+	}
+	if err := resolveBatchOutput(&req.Config); err != nil {
+		return nil, err
+	}
+	if req.Config.CompletionWebhook != "" {
+		if err := checkWebhookURL(ctx, req.Config.CompletionWebhook); err != nil {
+			return nil, err
+		}
+	}
+	// Workers have no request locale, so it is resolved now
+	if req.Config.Language == "" {
+		req.Config.Language = requestLocale(ctx).Language
+	}
+
 	batch := &domain.Batch{
 		ID:          uuid.New().String(),
 		UserID:      userID,
@@ -159,9 +234,88 @@ func (s *BatchService) CreateBatch(ctx context.Context, userID string, req *Crea
 	return batch, nil
 }
 
+// SaveTemplate saves a reusable batch configuration for the user
+func (s *BatchService) SaveTemplate(ctx context.Context, userID string, req *SaveBatchTemplateRequest) (*domain.BatchTemplate, error) {
+	template := &domain.BatchTemplate{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		Name:          req.Name,
+		Description:   req.Description,
+		Config:        req.Config,
+		VideoDefaults: req.VideoDefaults,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, fmt.Errorf("failed to save batch template: %w", err)
+	}
+
+	return template, nil
+}
+
+// ListTemplates lists the user's saved batch templates
+func (s *BatchService) ListTemplates(ctx context.Context, userID string) ([]*domain.BatchTemplate, error) {
+	return s.templateRepo.ListByUser(userID)
+}
+
+// DeleteTemplate deletes one of the user's batch templates
+func (s *BatchService) DeleteTemplate(ctx context.Context, templateID, userID string) error {
+	return s.templateRepo.Delete(templateID, userID)
+}
+
+// CreateBatchFromTemplate creates a batch using a saved template's config.
+// Each video's config starts from the template defaults; fields set on the video override them.
+func (s *BatchService) CreateBatchFromTemplate(ctx context.Context, userID, templateID string, req *CreateBatchFromTemplateRequest) (*domain.Batch, error) {
+	template, err := s.templateRepo.GetByIDAndUser(templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	config := template.Config
+	config.BatchTemplateID = template.ID
+
+	videos := make([]VideoInput, len(req.Videos))
+	for i, input := range req.Videos {
+		videos[i] = input
+		videos[i].Config = mergeVideoConfig(template.VideoDefaults, input.Config)
+	}
+
+	return s.CreateBatch(ctx, userID, &CreateBatchRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Videos:      videos,
+		Config:      config,
+	})
+}
+
+// mergeVideoConfig overlays the non-empty fields of override onto base
+func mergeVideoConfig(base, override domain.VideoConfig) domain.VideoConfig {
+	merged := base
+	if override.Topic != "" {
+		merged.Topic = override.Topic
+	}
+	if override.Script != "" {
+		merged.Script = override.Script
+	}
+	if len(override.Keywords) > 0 {
+		merged.Keywords = override.Keywords
+	}
+	if override.Tone != "" {
+		merged.Tone = override.Tone
+	}
+	if override.TargetDuration != 0 {
+		merged.TargetDuration = override.TargetDuration
+	}
+	if len(override.Scenes) > 0 {
+		merged.Scenes = override.Scenes
+	}
+	return merged
+}
+
 // StartBatch starts processing a batch
-func (s *BatchService) StartBatch(ctx context.Context, batchID string) error {
-	batch, err := s.repo.Get(batchID)
+func (s *BatchService) StartBatch(ctx context.Context, batchID, userID string) error {
+	batch, err := s.repo.GetByIDAndUser(batchID, userID)
 	if err != nil {
 		return err
 	}
@@ -180,19 +334,21 @@ func (s *BatchService) StartBatch(ctx context.Context, batchID string) error {
 	}
 
 	// Queue videos for processing
+	queueName := queueFor(batch)
 	for i := range batch.Videos {
-		if err := s.queueVideo(&batch.Videos[i]); err != nil {
+		if err := s.queueVideo(&batch.Videos[i], queueName); err != nil {
 			log.Printf("Failed to queue video %s: %v", batch.Videos[i].ID, err)
 			batch.Videos[i].Status = domain.VideoStatusFailed
 			batch.Videos[i].Error = "Failed to queue"
 		}
 	}
 
-	return nil
+	// Save the task IDs so stalled videos can be found after a restart
+	return s.repo.Update(batch)
 }
 
-// queueVideo adds a video to the processing queue
-func (s *BatchService) queueVideo(video *domain.BatchVideo) error {
+// queueVideo adds a video to the named processing queue
+func (s *BatchService) queueVideo(video *domain.BatchVideo, queueName string) error {
 	payload, err := json.Marshal(video)
 	if err != nil {
 		return err
@@ -202,7 +358,7 @@ func (s *BatchService) queueVideo(video *domain.BatchVideo) error {
 
 	// Configure task options
 	opts := []asynq.Option{
-		asynq.Queue("batch"),
+		asynq.Queue(queueName),
 		asynq.MaxRetry(3),
 		asynq.Timeout(30 * time.Minute),
 		asynq.Retention(24 * time.Hour),
@@ -214,19 +370,20 @@ func (s *BatchService) queueVideo(video *domain.BatchVideo) error {
 	}
 
 	video.Status = domain.VideoStatusQueued
+	video.TaskID = info.ID
 	log.Printf("Queued video %s with task ID %s", video.ID, info.ID)
 
 	return nil
 }
 
-// GetBatch retrieves a batch by ID
-func (s *BatchService) GetBatch(ctx context.Context, batchID string) (*domain.Batch, error) {
-	return s.repo.Get(batchID)
+// GetBatch retrieves a batch by ID for its owner
+func (s *BatchService) GetBatch(ctx context.Context, batchID, userID string) (*domain.Batch, error) {
+	return s.repo.GetByIDAndUser(batchID, userID)
 }
 
 // GetBatchProgress retrieves the current progress of a batch
-func (s *BatchService) GetBatchProgress(ctx context.Context, batchID string) (*BatchProgress, error) {
-	batch, err := s.repo.Get(batchID)
+func (s *BatchService) GetBatchProgress(ctx context.Context, batchID, userID string) (*BatchProgress, error) {
+	batch, err := s.repo.GetByIDAndUser(batchID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -261,12 +418,20 @@ func (s *BatchService) GetBatchProgress(ctx context.Context, batchID string) (*B
 	return progress, nil
 }
 
-// GetBatchResults retrieves the results of a completed batch
-func (s *BatchService) GetBatchResults(ctx context.Context, batchID string) (*domain.Batch, error) {
-	batch, err := s.repo.Get(batchID)
+// BatchResults is a batch's completed videos along with how the rest failed
+type BatchResults struct {
+	*domain.Batch
+	Failures []BatchFailureGroup `json:"failures"`
+}
+
+// GetBatchResults retrieves the results of a completed batch, grouping its
+// failed videos by the stage and provider they failed at
+func (s *BatchService) GetBatchResults(ctx context.Context, batchID, userID string) (*BatchResults, error) {
+	batch, err := s.repo.GetByIDAndUser(batchID, userID)
 	if err != nil {
 		return nil, err
 	}
+	failures := groupBatchFailures(batch.Videos)
 
 	// Filter to only completed videos with results
 	var completedVideos []domain.BatchVideo
@@ -277,20 +442,20 @@ func (s *BatchService) GetBatchResults(ctx context.Context, batchID string) (*do
 	}
 
 	batch.Videos = completedVideos
-	return batch, nil
+	return &BatchResults{Batch: batch, Failures: failures}, nil
 }
 
 // ListBatches lists all batches for a user
-func (s *BatchService) ListBatches(ctx context.Context, userID string, limit, offset int) ([]*domain.Batch, error) {
+func (s *BatchService) ListBatches(ctx context.Context, userID string, limit, offset int) ([]*domain.Batch, int64, error) {
 	if limit == 0 {
 		limit = 20
 	}
-	return s.repo.List(userID, limit, offset)
+	return s.repo.ListWithCount(userID, limit, offset)
 }
 
 // CancelBatch cancels a batch and all pending videos
-func (s *BatchService) CancelBatch(ctx context.Context, batchID string) error {
-	batch, err := s.repo.Get(batchID)
+func (s *BatchService) CancelBatch(ctx context.Context, batchID, userID string) error {
+	batch, err := s.repo.GetByIDAndUser(batchID, userID)
 	if err != nil {
 		return err
 	}
@@ -313,8 +478,8 @@ func (s *BatchService) CancelBatch(ctx context.Context, batchID string) error {
 }
 
 // PauseBatch pauses batch processing
-func (s *BatchService) PauseBatch(ctx context.Context, batchID string) error {
-	batch, err := s.repo.Get(batchID)
+func (s *BatchService) PauseBatch(ctx context.Context, batchID, userID string) error {
+	batch, err := s.repo.GetByIDAndUser(batchID, userID)
 	if err != nil {
 		return err
 	}
@@ -330,8 +495,8 @@ func (s *BatchService) PauseBatch(ctx context.Context, batchID string) error {
 }
 
 // ResumeBatch resumes a paused batch
-func (s *BatchService) ResumeBatch(ctx context.Context, batchID string) error {
-	batch, err := s.repo.Get(batchID)
+func (s *BatchService) ResumeBatch(ctx context.Context, batchID, userID string) error {
+	batch, err := s.repo.GetByIDAndUser(batchID, userID)
 	if err != nil {
 		return err
 	}
@@ -346,22 +511,28 @@ func (s *BatchService) ResumeBatch(ctx context.Context, batchID string) error {
 	return s.repo.Update(batch)
 }
 
-// RetryFailedVideos retries all failed videos in a batch
-func (s *BatchService) RetryFailedVideos(ctx context.Context, batchID string) error {
-	batch, err := s.repo.Get(batchID)
+// RetryFailedVideos retries the failed videos in a batch, leaving out those
+// whose failure retrying can't fix
+func (s *BatchService) RetryFailedVideos(ctx context.Context, batchID, userID string) error {
+	batch, err := s.repo.GetByIDAndUser(batchID, userID)
 	if err != nil {
 		return err
 	}
 
 	retryCount := 0
+	queueName := queueFor(batch)
 	for i := range batch.Videos {
-		if batch.Videos[i].Status == domain.VideoStatusFailed {
+		// Failures recorded before stages were tracked are retried
+		if batch.Videos[i].Status == domain.VideoStatusFailed && (batch.Videos[i].Retryable || batch.Videos[i].FailedStage == "") {
 			batch.Videos[i].Status = domain.VideoStatusPending
 			batch.Videos[i].Error = ""
+			batch.Videos[i].FailedStage = ""
+			batch.Videos[i].FailedProvider = ""
+			batch.Videos[i].Retryable = false
 			batch.Videos[i].Progress = 0
 			batch.Videos[i].UpdatedAt = time.Now()
 
-			if err := s.queueVideo(&batch.Videos[i]); err != nil {
+			if err := s.queueVideo(&batch.Videos[i], queueName); err != nil {
 				log.Printf("Failed to requeue video %s: %v", batch.Videos[i].ID, err)
 			} else {
 				retryCount++
@@ -375,24 +546,58 @@ func (s *BatchService) RetryFailedVideos(ctx context.Context, batchID string) er
 		return s.repo.Update(batch)
 	}
 
-	return fmt.Errorf("no failed videos to retry")
+	return fmt.Errorf("no retryable failed videos")
 }
 
-// GetQueueStats retrieves queue statistics
+// GetQueueStats retrieves statistics for every batch queue, along with the
+// worker settings they are processed with
 func (s *BatchService) GetQueueStats(ctx context.Context) (*QueueStats, error) {
-	stats, err := s.inspector.GetQueueInfo("batch")
+	existing, err := s.inspector.Queues()
 	if err != nil {
 		return nil, err
 	}
+	known := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		known[name] = true
+	}
 
-	return &QueueStats{
-		Pending:   stats.Pending,
-		Active:    stats.Active,
-		Completed: stats.Completed,
-		Failed:    stats.Failed,
-		Scheduled: stats.Scheduled,
-		Retry:     stats.Retry,
-	}, nil
+	workers := s.WorkerConfig()
+	total := &QueueStats{
+		Queues:  make(map[string]*QueueStats, len(s.queueWeights)),
+		Workers: &workers,
+	}
+	for name := range s.queueWeights {
+		// asynq only knows a queue once something has been enqueued on it
+		if !known[name] {
+			total.Queues[name] = &QueueStats{}
+			continue
+		}
+
+		info, err := s.inspector.GetQueueInfo(name)
+		if err != nil {
+			return nil, err
+		}
+		stats := &QueueStats{
+			Pending:              info.Pending,
+			Active:               info.Active,
+			Completed:            info.Completed,
+			Failed:               info.Failed,
+			Scheduled:            info.Scheduled,
+			Retry:                info.Retry,
+			OldestPendingSeconds: info.Latency.Seconds(),
+		}
+		total.Queues[name] = stats
+
+		total.Pending += stats.Pending
+		total.Active += stats.Active
+		total.Completed += stats.Completed
+		total.Failed += stats.Failed
+		total.Scheduled += stats.Scheduled
+		total.Retry += stats.Retry
+		total.OldestPendingSeconds = math.Max(total.OldestPendingSeconds, stats.OldestPendingSeconds)
+	}
+
+	return total, nil
 }
 
 // ProcessVideo processes a single video (called by worker)
@@ -407,11 +612,19 @@ func (s *BatchService) ProcessVideo(ctx context.Context, video *domain.BatchVide
 	if err != nil {
 		return fmt.Errorf("failed to get batch: %w", err)
 	}
+	// The task payload doesn't carry the log, so earlier attempts' lines are kept from the stored video
+	for _, stored := range batch.Videos {
+		if stored.ID == video.ID {
+			video.Logs = stored.Logs
+		}
+	}
+	logVideo(video, "processing started")
 
 	// Update batch progress
 	batch.InProgress++
 	batch.Status = domain.BatchStatusProcessing
 	batch.UpdatedAt = time.Now()
+	setBatchVideo(batch, video)
 
 	if err := s.repo.Update(batch); err != nil {
 		return fmt.Errorf("failed to update batch: %w", err)
@@ -420,21 +633,37 @@ func (s *BatchService) ProcessVideo(ctx context.Context, video *domain.BatchVide
 	// Process the video
 	result, err := s.generateVideo(ctx, video, batch)
 	if err != nil {
+		logVideo(video, "failed: %v", err)
 		video.Status = domain.VideoStatusFailed
 		video.Error = err.Error()
 		video.Progress = 0
+		video.FailedStage, video.FailedProvider, video.Retryable = "", "", true
+		var stageErr *BatchStageError
+		if errors.As(err, &stageErr) {
+			video.FailedStage = string(stageErr.Stage)
+			video.FailedProvider = stageErr.Provider
+			video.Retryable = stageErr.Retryable
+		}
 
 		// Update batch
 		batch.InProgress--
 		batch.Failed++
 		batch.UpdatedAt = time.Now()
+		setBatchVideo(batch, video)
+		finished := finishBatchIfDone(batch)
 		s.repo.Update(batch)
+		if finished {
+			s.notifyBatchComplete(batch)
+		}
 
 		return err
 	}
 
 	// Success
+	logVideo(video, "completed in %s", time.Since(now).Round(time.Second))
 	video.Status = domain.VideoStatusCompleted
+	video.Error = ""
+	video.FailedStage, video.FailedProvider, video.Retryable = "", "", false
 	video.Result = result
 	video.Progress = 100
 	completedAt := time.Now()
@@ -445,51 +674,159 @@ func (s *BatchService) ProcessVideo(ctx context.Context, video *domain.BatchVide
 	batch.InProgress--
 	batch.Completed++
 	batch.UpdatedAt = time.Now()
-
-	// Calculate overall progress
-	batch.Progress = float64(batch.Completed+batch.Failed) / float64(batch.TotalVideos) * 100
+	setBatchVideo(batch, video)
 
 	// Check if batch is complete
-	if batch.Completed+batch.Failed >= batch.TotalVideos {
-		batch.CompletedAt = &completedAt
-		if batch.Failed == 0 {
-			batch.Status = domain.BatchStatusCompleted
-		} else if batch.Completed > 0 {
-			batch.Status = domain.BatchStatusCompleted // Partial success
-		} else {
-			batch.Status = domain.BatchStatusFailed
-		}
+	finished := finishBatchIfDone(batch)
+	if err := s.repo.Update(batch); err != nil {
+		return err
 	}
-
-	return s.repo.Update(batch)
+	if finished {
+		s.notifyBatchComplete(batch)
+	}
+	return nil
 }
 
 // generateVideo generates a single video
 func (s *BatchService) generateVideo(ctx context.Context, video *domain.BatchVideo, batch *domain.Batch) (*domain.VideoResult, error) {
 	startTime := time.Now()
+	// Workers run outside a request, so attribute provider usage to the batch owner
+	ctx = domain.WithUserID(ctx, batch.UserID)
+
+	// Steps 1 and 2: Use the video's own scenes, or generate a script and
+	// scenes for it
+	var scenes *SceneGenerationResult
+	var narration string
+	if len(video.Config.Scenes) > 0 {
+		scenes, narration = givenScenes(video.Config.Scenes)
+		logVideo(video, "using %d given scenes", len(scenes.Scenes))
+		video.Progress = 50
+		s.repo.Update(batch)
+	} else {
+		var err error
+		scenes, narration, err = s.generateScenes(ctx, video, batch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 3: Generate voice if enabled
+	if batch.Config.VoiceID != "" {
+		ttsReq := &GenerateVoiceRequest{
+			Text:           narration,
+			VoiceID:        batch.Config.VoiceID,
+			Provider:       ProviderElevenLabs,
+			Speed:          1.0,
+			ResponseFormat: "mp3",
+		}
+
+		_, err := s.ttsService.GenerateVoice(ctx, ttsReq)
+		if err != nil {
+			logVideo(video, "voice generation failed, continuing without voice: %v", err)
+			// Continue without voice - non-critical
+		} else {
+			logVideo(video, "voice generated")
+		}
+	}
+
+	// Update progress
+	video.Progress = 75
+	s.repo.Update(batch)
+
+	// Step 4: Create timeline
+	width, height, fps := batchOutput(batch.Config)
+	timelineReq := &CreateTimelineRequest{
+		Name:        video.Title,
+		Description: video.Description,
+		Duration:    float64(batch.Config.Duration),
+		Width:       width,
+		Height:      height,
+		FPS:         fps,
+	}
+
+	timeline, err := s.timelineService.Create(batch.UserID, timelineReq)
+	if err != nil {
+		return nil, newBatchStageError(StageTimeline, "timeline creation", err)
+	}
+
+	// Step 5: Add scenes as clips
+	// Create a default track first or use the timeline ID as track reference
+	clipReqs := sceneClipRequests(timeline.ID, scenes, float64(batch.Config.Duration))
+
+	if len(clipReqs) > 0 {
+		if _, err := s.clipService.CreateBulk(batch.UserID, timeline.ID, clipReqs); err != nil {
+			// Don't leave an empty timeline behind
+			if delErr := s.timelineService.Delete(timeline.ID, batch.UserID); delErr != nil {
+				logVideo(video, "failed to remove timeline %s: %v", timeline.ID, delErr)
+			}
+			return nil, newBatchStageError(StageTimeline, "clip creation", err)
+		}
+	}
+
+	if batch.Config.MusicTrackID != "" {
+		if err := s.addMusic(batch.UserID, timeline.ID, batch.Config.MusicTrackID, float64(batch.Config.Duration)); err != nil {
+			logVideo(video, "adding music failed, continuing without music: %v", err)
+			// Continue without music - non-critical
+		}
+	}
+	logVideo(video, "timeline %s created with %d clips", timeline.ID, len(clipReqs))
+	s.timelineService.RecordRender(ctx, timeline, fmt.Sprintf("batch %s", batch.ID))
+
+	renderTime := int(time.Since(startTime).Seconds())
+
+	result := &domain.VideoResult{
+		TimelineID: timeline.ID,
+		Duration:   float64(batch.Config.Duration),
+		Format:     batch.Config.Codec,
+		Size:       0,
+		Metadata: map[string]string{
+			"renderTime": fmt.Sprintf("%d", renderTime),
+			"resolution": fmt.Sprintf("%dx%d", width, height),
+			"fps":        fmt.Sprintf("%d", fps),
+		},
+	}
+	s.probeResult(ctx, video, result)
+	s.publishTimelineReady(ctx, video, batch, result, width, height, fps)
 
+	return result, nil
+}
+
+// generateScenes writes the video's script, unless it came with one, and
+// generates its scenes. It returns the scenes and the text to narrate.
+func (s *BatchService) generateScenes(ctx context.Context, video *domain.BatchVideo, batch *domain.Batch) (*SceneGenerationResult, string, error) {
 	// Step 1: Generate script if not provided
 	var script *Script
 	if video.Config.Script != "" {
+		duration := video.Config.TargetDuration
+		if duration == 0 {
+			duration = batch.Config.Duration
+		}
 		script = &Script{
-			Title:       video.Title,
-			Description: video.Config.Script,
+			Title:         video.Title,
+			Description:   video.Config.Script,
+			Language:      batch.Config.Language,
+			Scenes:        SplitScriptIntoScenes(video.Config.Script, 0, duration),
+			TotalDuration: duration,
 		}
 	} else {
 		scriptReq := &GenerateScriptRequest{
-			Prompt:   video.Config.Topic,
-			Style:    ScriptStyle(batch.Config.ScriptStyle),
-			Tone:     video.Config.Tone,
-			Duration: batch.Config.Duration,
+			Prompt:         video.Config.Topic,
+			Style:          ScriptStyle(batch.Config.ScriptStyle),
+			Tone:           video.Config.Tone,
+			Duration:       batch.Config.Duration,
+			Language:       batch.Config.Language,
+			TargetAudience: batch.Config.TargetAudience,
 		}
 
 		var err error
 		script, err = s.aiScriptService.GenerateScript(ctx, scriptReq)
 		if err != nil {
-			return nil, fmt.Errorf("script generation failed: %w", err)
+			return nil, "", newBatchStageError(StageScript, "script generation", err)
 		}
 	}
 
+	logVideo(video, "script ready with %d scenes", len(script.Scenes))
+
 	// Update progress
 	video.Progress = 25
 	s.repo.Update(batch)
@@ -502,6 +839,7 @@ func (s *BatchService) generateVideo(ctx context.Context, video *domain.BatchVid
 			Title:       scene.Title,
 			Description: scene.Description,
 			Keywords:    scene.Keywords,
+			Duration:    scene.Duration,
 		})
 	}
 
@@ -509,94 +847,148 @@ func (s *BatchService) generateVideo(ctx context.Context, video *domain.BatchVid
 		ScriptID:       script.Title,
 		Scenes:         sceneInfos,
 		Style:          string(script.Style),
-		ImageSource:    SourceUnsplash,
 		GenerateImages: true,
+		Language:       script.Language,
+		TargetAudience: batch.Config.TargetAudience,
+		StylePreset:    batch.Config.ImageStyle,
 	}
 
 	scenes, err := s.aiSceneService.GenerateScenes(ctx, sceneReq)
 	if err != nil {
-		return nil, fmt.Errorf("scene generation failed: %w", err)
+		return nil, "", newBatchStageError(StageScenes, "scene generation", err)
 	}
 
+	logVideo(video, "generated %d scenes", len(scenes.Scenes))
+
 	// Update progress
 	video.Progress = 50
 	s.repo.Update(batch)
 
-	// Step 3: Generate voice if enabled
-	if batch.Config.VoiceID != "" {
-		ttsReq := &GenerateVoiceRequest{
-			Text:           script.Description,
-			VoiceID:        batch.Config.VoiceID,
-			Provider:       ProviderElevenLabs,
-			Speed:          1.0,
-			ResponseFormat: "mp3",
-		}
+	return scenes, script.Description, nil
+}
 
-		_, err := s.ttsService.GenerateVoice(ctx, ttsReq)
-		if err != nil {
-			log.Printf("Voice generation failed for video %s: %v", video.ID, err)
-			// Continue without voice - non-critical
-		}
+// givenScenes lays out the scenes a video came with, in their given order,
+// and returns them with the text to narrate
+func givenScenes(videoScenes []domain.VideoScene) (*SceneGenerationResult, string) {
+	result := &SceneGenerationResult{
+		Scenes:      make([]GeneratedScene, 0, len(videoScenes)),
+		TotalScenes: len(videoScenes),
+	}
+	descriptions := make([]string, 0, len(videoScenes))
+	for i, scene := range videoScenes {
+		result.Scenes = append(result.Scenes, GeneratedScene{
+			Number:      i + 1,
+			Title:       scene.Title,
+			Description: scene.Description,
+			ImageURL:    scene.ImageURL,
+			Duration:    scene.Duration,
+		})
+		descriptions = append(descriptions, scene.Description)
 	}
+	result.TotalDuration = reflowScenes(result.Scenes)
+	return result, strings.Join(descriptions, " ")
+}
 
-	// Update progress
-	video.Progress = 75
-	s.repo.Update(batch)
-
-	// Step 4: Create timeline
-	timelineReq := &CreateTimelineRequest{
-		Name:        video.Title,
-		Description: video.Description,
-		Duration:    float64(batch.Config.Duration),
-		Width:       1920,
-		Height:      1080,
-		FPS:         30,
+// publishTimelineReady tells renderers a video's timeline is ready. The
+// video is done either way, so a failure to publish is only logged.
+func (s *BatchService) publishTimelineReady(ctx context.Context, video *domain.BatchVideo, batch *domain.Batch, result *domain.VideoResult, width, height, fps int) {
+	if s.events == nil {
+		return
 	}
-
-	timeline, err := s.timelineService.Create(batch.UserID, timelineReq)
+	err := s.events.Publish(ctx, events.TypeTimelineReady, &events.TimelineReady{
+		TimelineID: result.TimelineID,
+		UserID:     batch.UserID,
+		BatchID:    batch.ID,
+		VideoID:    video.ID,
+		Duration:   result.Duration,
+		Width:      width,
+		Height:     height,
+		FPS:        fps,
+		Format:     result.Format,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("timeline creation failed: %w", err)
+		logVideo(video, "failed to publish timeline ready event: %v", err)
 	}
+}
 
-	// Step 5: Add scenes as clips
-	// Create a default track first or use the timeline ID as track reference
-	currentTime := 0.0
-	sceneDuration := float64(batch.Config.Duration) / float64(len(scenes.Scenes))
-	if len(scenes.Scenes) == 0 {
-		sceneDuration = 5.0 // Default 5 seconds per scene if no scenes
+// defaultSceneDuration is how many seconds a scene plays when there is no
+// video duration to split between the scenes
+const defaultSceneDuration = 5.0
+
+// sceneClipRequests lays scenes out as image clips in the result's order.
+// Scenes play for their own durations, with the timings a reorder reflowed;
+// unless every scene has one, duration is split evenly between them, or each
+// plays for defaultSceneDuration when there is no duration to split.
+func sceneClipRequests(trackID string, scenes *SceneGenerationResult, duration float64) []CreateClipRequest {
+	timed := len(scenes.Scenes) > 0
+	for _, scene := range scenes.Scenes {
+		timed = timed && scene.Duration > 0
+	}
+	sceneDuration := defaultSceneDuration
+	if len(scenes.Scenes) > 0 && duration > 0 {
+		sceneDuration = duration / float64(len(scenes.Scenes))
 	}
 
+	clipReqs := make([]CreateClipRequest, 0, len(scenes.Scenes))
+	currentTime := 0.0
 	for i, scene := range scenes.Scenes {
-		clipReq := &CreateClipRequest{
-			TrackID:     timeline.ID, // Using timeline ID as track reference
+		if timed {
+			currentTime = float64(scene.StartTime)
+			sceneDuration = float64(scene.Duration)
+		}
+		clipReqs = append(clipReqs, CreateClipRequest{
+			TrackID:     trackID, // Using timeline ID as track reference
 			Name:        fmt.Sprintf("Scene %d: %s", i+1, scene.Title),
 			Type:        "image",
 			SourceURL:   scene.ImageURL,
 			StartTime:   currentTime,
 			EndTime:     currentTime + sceneDuration,
 			TextContent: scene.Description,
-		}
-
-		_, err := s.clipService.Create(batch.UserID, timeline.ID, clipReq)
-		if err != nil {
-			log.Printf("Failed to add clip: %v", err)
-		}
+		})
 		currentTime += sceneDuration
 	}
+	return clipReqs
+}
 
-	renderTime := int(time.Since(startTime).Seconds())
+// addMusic lays a library track on a new music track of the timeline,
+// looped to the video's length and ducked under narration
+func (s *BatchService) addMusic(userID, timelineID, musicTrackID string, duration float64) error {
+	if s.music == nil {
+		return ErrMusicTrackNotFound
+	}
+	music, err := s.music.Get(musicTrackID)
+	if err != nil {
+		return err
+	}
 
-	result := &domain.VideoResult{
-		TimelineID: timeline.ID,
-		Duration:   float64(batch.Config.Duration),
-		Size:       0,
-		Metadata:   map[string]string{"renderTime": fmt.Sprintf("%d", renderTime)},
+	track, err := s.trackService.Create(userID, timelineID, &CreateTrackRequest{Name: "Music", Type: "audio"})
+	if err != nil {
+		return fmt.Errorf("failed to create music track: %w", err)
+	}
+	role := domain.TrackRoleMusic
+	ducking := musicDucking
+	if _, err := s.trackService.Update(userID, track.ID, &UpdateTrackRequest{Role: &role, Ducking: &ducking}); err != nil {
+		return fmt.Errorf("failed to set up music track: %w", err)
 	}
 
-	return result, nil
+	clips := musicClips(music, track.ID, duration)
+	if len(clips) == 0 {
+		return nil
+	}
+	if _, err := s.clipService.CreateBulk(userID, timelineID, clips); err != nil {
+		return fmt.Errorf("failed to add music clips: %w", err)
+	}
+	return nil
 }
 
-// Close closes the batch service
+// Close stops the workers, waiting for in-flight videos, and closes the batch service
 func (s *BatchService) Close() error {
-	return s.queue.Close()
+	if s.server != nil {
+		s.server.Shutdown()
+	}
+	queueErr := s.queue.Close()
+	if err := s.inspector.Close(); err != nil {
+		return err
+	}
+	return queueErr
 }
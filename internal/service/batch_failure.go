@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+
+	"renderowl-api/internal/domain"
+)
+
+// BatchStageError is a batch video failure attributed to the pipeline stage
+// it happened in and, when an AI provider was at fault, to the provider
+type BatchStageError struct {
+	Stage     PipelineStage
+	Op        string // what failed, e.g. "script generation"
+	Provider  string
+	Retryable bool
+	Err       error
+}
+
+func (e *BatchStageError) Error() string {
+	return fmt.Sprintf("%s failed: %v", e.Op, e.Err)
+}
+
+func (e *BatchStageError) Unwrap() error {
+	return e.Err
+}
+
+// newBatchStageError wraps a stage's error, working out which provider failed
+// and whether retrying may help
+func newBatchStageError(stage PipelineStage, op string, err error) *BatchStageError {
+	stageErr := &BatchStageError{
+		Stage:     stage,
+		Op:        op,
+		Retryable: retryableFailure(err),
+		Err:       err,
+	}
+	var providerErr *ProviderError
+	var imageErr *imageProviderError
+	switch {
+	case errors.As(err, &providerErr):
+		stageErr.Provider = providerErr.Provider
+	case errors.As(err, &imageErr):
+		stageErr.Provider = imageErr.Provider
+	}
+	return stageErr
+}
+
+// retryableFailure reports whether a failed step may succeed if run again.
+// Outages, rate limits, timeouts and server errors are retryable; requests a
+// provider rejected and input that can't be generated from are not. Failures
+// that can't be told apart are retried.
+func retryableFailure(err error) bool {
+	var providerErr *ProviderError
+	var imageErr *imageProviderError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrNoScenes), errors.Is(err, ErrUnknownScriptPreset), errors.Is(err, ErrUnknownImageStyle):
+		return false
+	case errors.Is(err, ErrCircuitOpen), errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return true
+	case errors.As(err, &imageErr):
+		return imageErr.retryable()
+	case errors.As(err, &providerErr) && providerErr.StatusCode != 0:
+		status := providerErr.StatusCode
+		return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+	}
+	return true
+}
+
+// BatchFailureGroup counts a batch's failed videos that failed the same way
+type BatchFailureGroup struct {
+	Stage     PipelineStage `json:"stage,omitempty"`
+	Provider  string        `json:"provider,omitempty"`
+	Retryable bool          `json:"retryable"`
+	Count     int           `json:"count"`
+	Summary   string        `json:"summary"` // e.g. "12 failed at scene generation (openai outage)"
+}
+
+// stageDescriptions names stages in failure summaries
+var stageDescriptions = map[PipelineStage]string{
+	StageScript:   "script generation",
+	StageScenes:   "scene generation",
+	StageTTS:      "voice generation",
+	StageTimeline: "timeline creation",
+}
+
+// groupBatchFailures groups a batch's failed videos by stage, provider and
+// whether they can be retried, largest group first
+func groupBatchFailures(videos []domain.BatchVideo) []BatchFailureGroup {
+	groups := []BatchFailureGroup{}
+	index := make(map[BatchFailureGroup]int)
+	for _, video := range videos {
+		if video.Status != domain.VideoStatusFailed {
+			continue
+		}
+		key := BatchFailureGroup{
+			Stage:     PipelineStage(video.FailedStage),
+			Provider:  video.FailedProvider,
+			Retryable: video.Retryable || video.FailedStage == "",
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, key)
+		}
+		groups[i].Count++
+	}
+
+	for i := range groups {
+		groups[i].Summary = failureSummary(groups[i])
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Count > groups[j].Count })
+	return groups
+}
+
+// failureSummary describes a failure group in a sentence
+func failureSummary(group BatchFailureGroup) string {
+	where, ok := stageDescriptions[group.Stage]
+	if !ok {
+		where = "an unknown stage"
+	}
+	summary := fmt.Sprintf("%d failed at %s", group.Count, where)
+	switch {
+	case group.Provider != "" && group.Retryable:
+		summary += fmt.Sprintf(" (%s outage)", group.Provider)
+	case group.Provider != "":
+		summary += fmt.Sprintf(" (rejected by %s)", group.Provider)
+	case !group.Retryable:
+		summary += " (not retryable)"
+	}
+	return summary
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/safehttp"
+)
+
+// batchWebhookAttempts is how many times a completion webhook is tried
+// before it is given up on
+const batchWebhookAttempts = 3
+
+// batchWebhookClient delivers batch completion webhooks, to public addresses
+// only
+var batchWebhookClient = safehttp.NewClient(10 * time.Second)
+
+// BatchSummary is the body of a batch's completion webhook
+type BatchSummary struct {
+	Event             string             `json:"event"`
+	BatchID           string             `json:"batchId"`
+	Name              string             `json:"name"`
+	Status            domain.BatchStatus `json:"status"`
+	Total             int                `json:"total"`
+	Completed         int                `json:"completed"`
+	Failed            int                `json:"failed"`
+	FailedTitles      []string           `json:"failedTitles"`
+	RenderTimeSeconds int                `json:"renderTimeSeconds"`
+	CompletedAt       *time.Time         `json:"completedAt,omitempty"`
+}
+
+// setBatchVideo copies a processed video's state into its batch so it is
+// saved with the batch
+func setBatchVideo(batch *domain.Batch, video *domain.BatchVideo) {
+	video.UpdatedAt = time.Now()
+	for i := range batch.Videos {
+		if batch.Videos[i].ID == video.ID {
+			batch.Videos[i] = *video
+			return
+		}
+	}
+}
+
+// finishBatchIfDone updates a batch's progress and, once every video has
+// completed or failed, its final status. It reports whether this call is the
+// one that finished the batch, so completion is only acted on once.
+func finishBatchIfDone(batch *domain.Batch) bool {
+	if batch.TotalVideos > 0 {
+		batch.Progress = float64(batch.Completed+batch.Failed) / float64(batch.TotalVideos) * 100
+	}
+	if batch.Completed+batch.Failed < batch.TotalVideos || batch.CompletedAt != nil {
+		return false
+	}
+
+	completedAt := time.Now()
+	batch.CompletedAt = &completedAt
+	if batch.Completed > 0 {
+		batch.Status = domain.BatchStatusCompleted // Partial success counts as completed
+	} else {
+		batch.Status = domain.BatchStatusFailed
+	}
+	return true
+}
+
+// buildBatchSummary summarizes a finished batch. Render time is the total
+// generation time of its completed videos.
+func buildBatchSummary(batch *domain.Batch) *BatchSummary {
+	summary := &BatchSummary{
+		Event:        "batch.completed",
+		BatchID:      batch.ID,
+		Name:         batch.Name,
+		Status:       batch.Status,
+		Total:        batch.TotalVideos,
+		Completed:    batch.Completed,
+		Failed:       batch.Failed,
+		FailedTitles: []string{},
+		CompletedAt:  batch.CompletedAt,
+	}
+	for _, video := range batch.Videos {
+		switch video.Status {
+		case domain.VideoStatusFailed:
+			summary.FailedTitles = append(summary.FailedTitles, video.Title)
+		case domain.VideoStatusCompleted:
+			if video.Result == nil {
+				continue
+			}
+			if seconds, err := strconv.Atoi(video.Result.Metadata["renderTime"]); err == nil {
+				summary.RenderTimeSeconds += seconds
+			}
+		}
+	}
+	return summary
+}
+
+// notifyBatchComplete posts the batch summary to the batch's completion
+// webhook, if it has one. Delivery is best-effort: it runs in the background
+// and a few failed attempts only log.
+func (s *BatchService) notifyBatchComplete(batch *domain.Batch) {
+	url := batch.Config.CompletionWebhook
+	if url == "" {
+		return
+	}
+
+	body, err := json.Marshal(buildBatchSummary(batch))
+	if err != nil {
+		log.Printf("Failed to marshal summary for batch %s: %v", batch.ID, err)
+		return
+	}
+
+	go func() {
+		for attempt := 1; attempt <= batchWebhookAttempts; attempt++ {
+			err := postBatchWebhook(url, body)
+			if err == nil {
+				return
+			}
+			if errors.Is(err, safehttp.ErrBlockedAddress) {
+				log.Printf("Completion webhook for batch %s refused: %v", batch.ID, err)
+				return
+			}
+			log.Printf("Completion webhook for batch %s failed (attempt %d/%d): %v", batch.ID, attempt, batchWebhookAttempts, err)
+			if attempt < batchWebhookAttempts {
+				time.Sleep(time.Duration(attempt) * 5 * time.Second)
+			}
+		}
+	}()
+}
+
+func postBatchWebhook(url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), batchWebhookClient.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := batchWebhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/safehttp"
+)
+
+func TestCompletionWebhooksOnlyGoToPublicHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook reached the internal server")
+	}))
+	defer server.Close()
+
+	req := &CreateBatchRequest{
+		Name:   "Batch",
+		Videos: []VideoInput{{Title: "Video"}},
+		Config: domain.BatchConfig{CompletionWebhook: server.URL},
+	}
+	if _, err := (&BatchService{}).CreateBatch(context.Background(), "user-a", req); !errors.Is(err, ErrInvalidWebhookURL) {
+		t.Errorf("CreateBatch with an internal webhook = %v, want ErrInvalidWebhookURL", err)
+	}
+
+	if err := postBatchWebhook(server.URL, []byte(`{}`)); !errors.Is(err, safehttp.ErrBlockedAddress) {
+		t.Errorf("postBatchWebhook = %v, want ErrBlockedAddress", err)
+	}
+}
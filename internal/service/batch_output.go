@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"renderowl-api/internal/domain"
+)
+
+// ErrInvalidOutputFormat is returned when a batch asks for a resolution, frame
+// rate or codec its videos can't be made in
+var ErrInvalidOutputFormat = errors.New("invalid output format")
+
+// Output formats batch timelines can be created in
+var (
+	batchResolutions = [][2]int{
+		{3840, 2160}, {2560, 1440}, {1920, 1080}, {1280, 720}, // 16:9
+		{2160, 3840}, {1080, 1920}, {720, 1280}, // 9:16
+		{1080, 1080}, // 1:1
+		{1080, 1350}, // 4:5
+	}
+	batchFrameRates = []int{24, 25, 30, 50, 60}
+	batchCodecs     = []string{"H.264", "H.265", "VP9"}
+)
+
+// Output format of batches that don't set one, and batches created before it
+// could be set
+const (
+	defaultBatchWidth  = 1920
+	defaultBatchHeight = 1080
+	defaultBatchFPS    = 30
+	defaultBatchCodec  = "H.264"
+)
+
+// resolveBatchOutput fills in and validates a batch's output format. Unset
+// fields come from the target platform's spec: Platform, or else the first of
+// Platforms that has one. Without either the output is 1920x1080 at 30fps.
+func resolveBatchOutput(config *domain.BatchConfig) error {
+	if config.Platform == "" {
+		for _, platform := range config.Platforms {
+			if _, ok := PlatformSpecs[platform]; ok {
+				config.Platform = platform
+				break
+			}
+		}
+	}
+
+	var spec *PlatformSpec
+	if config.Platform != "" {
+		s, ok := PlatformSpecs[config.Platform]
+		if !ok {
+			return fmt.Errorf("%w: unknown platform %q", ErrInvalidOutputFormat, config.Platform)
+		}
+		spec = &s
+	}
+
+	if (config.Width == 0) != (config.Height == 0) {
+		return fmt.Errorf("%w: width and height must be set together", ErrInvalidOutputFormat)
+	}
+	if config.Width == 0 {
+		config.Width, config.Height = defaultBatchWidth, defaultBatchHeight
+		if spec != nil {
+			config.Width, config.Height = spec.Width, spec.Height
+		}
+	}
+	if config.FPS == 0 {
+		config.FPS = defaultBatchFPS
+		if spec != nil && spec.RecommendedFPS > 0 {
+			config.FPS = spec.RecommendedFPS
+		}
+	}
+	if config.Codec == "" {
+		config.Codec = defaultBatchCodec
+	}
+
+	if !slices.Contains(batchResolutions, [2]int{config.Width, config.Height}) {
+		return fmt.Errorf("%w: unsupported resolution %dx%d", ErrInvalidOutputFormat, config.Width, config.Height)
+	}
+	if !slices.Contains(batchFrameRates, config.FPS) {
+		return fmt.Errorf("%w: unsupported frame rate %d", ErrInvalidOutputFormat, config.FPS)
+	}
+	if !slices.Contains(batchCodecs, config.Codec) {
+		return fmt.Errorf("%w: unsupported codec %q", ErrInvalidOutputFormat, config.Codec)
+	}
+	if spec != nil && !slices.Contains(spec.SupportedCodecs, config.Codec) {
+		return fmt.Errorf("%w: %s doesn't accept %s", ErrInvalidOutputFormat, spec.Name, config.Codec)
+	}
+	return nil
+}
+
+// batchOutput returns the resolution and frame rate to create a batch's
+// timelines with
+func batchOutput(config domain.BatchConfig) (width, height, fps int) {
+	width, height, fps = config.Width, config.Height, config.FPS
+	if width == 0 || height == 0 {
+		width, height = defaultBatchWidth, defaultBatchHeight
+	}
+	if fps == 0 {
+		fps = defaultBatchFPS
+	}
+	return width, height, fps
+}
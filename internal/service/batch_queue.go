@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"renderowl-api/internal/domain"
+)
+
+// Batch queues. Single-video generations are someone waiting on the result,
+// so they go to the interactive queue ahead of large batches.
+const (
+	QueueInteractive = "interactive"
+	QueueBulk        = "bulk"
+)
+
+// defaultWorkerCount is the worker concurrency when config leaves it unset
+const defaultWorkerCount = 3
+
+// defaultQueueWeights gives interactive work six times the share of bulk work
+var defaultQueueWeights = map[string]int{
+	QueueInteractive: 6,
+	QueueBulk:        1,
+}
+
+// WorkerConfig reports the batch worker settings so ops can tune throughput vs latency
+type WorkerConfig struct {
+	Concurrency  int            `json:"concurrency"`
+	QueueWeights map[string]int `json:"queueWeights"`
+}
+
+// SetWorkerConfig sets worker concurrency and queue weights. It must be
+// called before StartWorkers; invalid values fall back to the defaults.
+func (s *BatchService) SetWorkerConfig(concurrency int, weights map[string]int) {
+	if concurrency > 0 {
+		s.workerCount = concurrency
+	}
+
+	queueWeights := make(map[string]int, len(defaultQueueWeights))
+	for queue, weight := range defaultQueueWeights {
+		queueWeights[queue] = weight
+	}
+	for queue, weight := range weights {
+		if weight > 0 {
+			queueWeights[queue] = weight
+		}
+	}
+	s.queueWeights = queueWeights
+}
+
+// WorkerConfig returns the current worker concurrency and queue weights
+func (s *BatchService) WorkerConfig() WorkerConfig {
+	weights := make(map[string]int, len(s.queueWeights))
+	for queue, weight := range s.queueWeights {
+		weights[queue] = weight
+	}
+	return WorkerConfig{Concurrency: s.workerCount, QueueWeights: weights}
+}
+
+// StartWorkers starts processing queued videos in the background
+func (s *BatchService) StartWorkers() error {
+	s.server = asynq.NewServer(s.redisOpt, asynq.Config{
+		Concurrency: s.workerCount,
+		Queues:      s.queueWeights,
+	})
+
+	mux := asynq.NewServeMux()
+	mux.HandleFunc(TypeBatchVideo, s.handleVideoTask)
+
+	// Pick up videos whose tasks were lost while the workers were down,
+	// before the workers start changing the videos being checked
+	resumed, err := s.ResumeStalled(context.Background())
+	if err != nil {
+		log.Printf("Failed to resume stalled batch videos: %v", err)
+	} else if resumed > 0 {
+		log.Printf("Resumed %d stalled batch videos", resumed)
+	}
+
+	if err := s.server.Start(mux); err != nil {
+		return fmt.Errorf("failed to start batch workers: %w", err)
+	}
+	log.Printf("Batch workers started (concurrency %d, queues %v)", s.workerCount, s.queueWeights)
+	return nil
+}
+
+func (s *BatchService) handleVideoTask(ctx context.Context, task *asynq.Task) error {
+	var video domain.BatchVideo
+	if err := json.Unmarshal(task.Payload(), &video); err != nil {
+		return fmt.Errorf("failed to unmarshal video: %w: %w", err, asynq.SkipRetry)
+	}
+	// The payload was built before the task got its ID
+	if id, ok := asynq.GetTaskID(ctx); ok {
+		video.TaskID = id
+	}
+	err := s.ProcessVideo(ctx, &video)
+	var stageErr *BatchStageError
+	if errors.As(err, &stageErr) && !stageErr.Retryable {
+		return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+	}
+	return err
+}
+
+// WorkerServer is a running batch worker process
+type WorkerServer struct {
+	Host          string    `json:"host"`
+	PID           int       `json:"pid"`
+	Status        string    `json:"status"`
+	Started       time.Time `json:"started"`
+	Concurrency   int       `json:"concurrency"`
+	ActiveWorkers int       `json:"activeWorkers"`
+}
+
+// WorkerHealth reports the batch worker processes that are alive. asynq drops
+// a server from the list once it stops sending heartbeats, so every server
+// listed has a current one.
+type WorkerHealth struct {
+	Servers       []WorkerServer `json:"servers"`
+	Concurrency   int            `json:"concurrency"`
+	ActiveWorkers int            `json:"activeWorkers"`
+}
+
+// GetWorkerHealth lists the live batch worker processes and how busy they are
+func (s *BatchService) GetWorkerHealth(ctx context.Context) (*WorkerHealth, error) {
+	servers, err := s.inspector.Servers()
+	if err != nil {
+		return nil, err
+	}
+
+	health := &WorkerHealth{Servers: []WorkerServer{}}
+	for _, server := range servers {
+		health.Servers = append(health.Servers, WorkerServer{
+			Host:          server.Host,
+			PID:           server.PID,
+			Status:        server.Status,
+			Started:       server.Started,
+			Concurrency:   server.Concurrency,
+			ActiveWorkers: len(server.ActiveWorkers),
+		})
+		health.Concurrency += server.Concurrency
+		health.ActiveWorkers += len(server.ActiveWorkers)
+	}
+	return health, nil
+}
+
+// queueFor picks the queue for a batch's videos
+func queueFor(batch *domain.Batch) string {
+	if len(batch.Videos) == 1 {
+		return QueueInteractive
+	}
+	return QueueBulk
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"renderowl-api/internal/domain"
+)
+
+// stalledVideoTimeout is how long a video may stay processing before it is
+// taken to be stuck. It is longer than the task timeout, so asynq gives up on
+// a hung task before the video is re-queued.
+const stalledVideoTimeout = 45 * time.Minute
+
+// ResumeStalled re-queues the videos of running batches that no worker will
+// pick up: queued or processing videos whose task is gone, as after a crash
+// or a Redis flush, and videos stuck past stalledVideoTimeout. Only the
+// re-queued videos are saved, so progress recorded meanwhile by workers is
+// kept. It returns how many videos were re-queued.
+func (s *BatchService) ResumeStalled(ctx context.Context) (int, error) {
+	batches, err := s.repo.ListByStatus(domain.BatchStatusQueued, domain.BatchStatusProcessing)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list running batches: %w", err)
+	}
+
+	resumed := 0
+	for _, batch := range batches {
+		queueName := queueFor(batch)
+		requeued, released := 0, 0
+		for i := range batch.Videos {
+			video := &batch.Videos[i]
+			stalled, err := s.videoStalled(video, queueName)
+			if err != nil {
+				log.Printf("Failed to check task of video %s: %v", video.ID, err)
+				continue
+			}
+			if !stalled {
+				continue
+			}
+
+			wasProcessing := video.Status == domain.VideoStatusProcessing
+			video.Status = domain.VideoStatusPending
+			video.Progress = 0
+			video.StartedAt = nil
+			video.UpdatedAt = time.Now()
+			if err := s.queueVideo(video, queueName); err != nil {
+				log.Printf("Failed to requeue stalled video %s: %v", video.ID, err)
+				continue
+			}
+			if err := s.repo.UpdateVideo(video); err != nil {
+				log.Printf("Failed to save requeued video %s: %v", video.ID, err)
+			}
+			if wasProcessing {
+				released++
+			}
+			requeued++
+		}
+
+		if released > 0 {
+			if err := s.repo.ReleaseInProgress(batch.ID, released); err != nil {
+				log.Printf("Failed to update progress of resumed batch %s: %v", batch.ID, err)
+			}
+		}
+		if requeued == 0 {
+			continue
+		}
+		log.Printf("Resumed %d stalled videos of batch %s", requeued, batch.ID)
+		resumed += requeued
+	}
+
+	return resumed, nil
+}
+
+// videoStalled reports whether a video is waiting on a task that will never
+// finish it
+func (s *BatchService) videoStalled(video *domain.BatchVideo, queueName string) (bool, error) {
+	switch video.Status {
+	case domain.VideoStatusPending, domain.VideoStatusQueued, domain.VideoStatusProcessing:
+	default:
+		return false, nil
+	}
+	// A video without a task may be one a batch is still queueing, so it is
+	// only taken to be lost once it has gone untouched past the timeout
+	if video.TaskID == "" {
+		return time.Since(video.UpdatedAt) >= stalledVideoTimeout, nil
+	}
+
+	info, err := s.inspector.GetTaskInfo(queueName, video.TaskID)
+	if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch info.State {
+	case asynq.TaskStateCompleted, asynq.TaskStateArchived:
+		// The task is done but never recorded its result on the video
+		return true, nil
+	case asynq.TaskStateActive:
+		if video.StartedAt == nil || time.Since(*video.StartedAt) < stalledVideoTimeout {
+			return false, nil
+		}
+		if err := s.inspector.CancelProcessing(video.TaskID); err != nil {
+			log.Printf("Failed to cancel stuck task %s: %v", video.TaskID, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
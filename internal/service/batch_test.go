@@ -0,0 +1,68 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"renderowl-api/internal/domain"
+)
+
+func TestReorderedScenesAreLaidOutInTheirNewOrder(t *testing.T) {
+	reordered, err := (&AISceneService{}).ReorderScenes(&ReorderScenesRequest{
+		Scenes: []GeneratedScene{
+			{Number: 1, Title: "Intro", ImageURL: "https://img.example.com/1.png", Duration: 4},
+			{Number: 2, Title: "Middle", ImageURL: "https://img.example.com/2.png", Duration: 6},
+			{Number: 3, Title: "Outro", ImageURL: "https://img.example.com/3.png", Duration: 5},
+		},
+		Order: []int{3, 1, 2},
+	})
+	if err != nil {
+		t.Fatalf("ReorderScenes: %v", err)
+	}
+
+	// Clients pass the reorder's scenes straight into the batch video config
+	encoded, err := json.Marshal(reordered.Scenes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config domain.VideoConfig
+	if err := json.Unmarshal([]byte(`{"scenes":`+string(encoded)+`}`), &config); err != nil {
+		t.Fatalf("scenes don't decode into a video config: %v", err)
+	}
+
+	scenes, _ := givenScenes(config.Scenes)
+	clips := sceneClipRequests("track-1", scenes, 60)
+	want := []struct {
+		source     string
+		start, end float64
+	}{
+		{"https://img.example.com/3.png", 0, 5},
+		{"https://img.example.com/1.png", 5, 9},
+		{"https://img.example.com/2.png", 9, 15},
+	}
+	if len(clips) != len(want) {
+		t.Fatalf("got %d clips, want %d", len(clips), len(want))
+	}
+	for i, w := range want {
+		if clips[i].SourceURL != w.source || clips[i].StartTime != w.start || clips[i].EndTime != w.end {
+			t.Errorf("clip %d = %s %v-%v, want %s %v-%v", i, clips[i].SourceURL, clips[i].StartTime, clips[i].EndTime, w.source, w.start, w.end)
+		}
+	}
+}
+
+func TestScenesWithoutADurationStillGetOne(t *testing.T) {
+	scenes := &SceneGenerationResult{Scenes: []GeneratedScene{{Title: "One"}, {Title: "Two", Duration: 3}}}
+	for _, duration := range []float64{0, -10} {
+		clips := sceneClipRequests("track-1", scenes, duration)
+		for i, clip := range clips {
+			if clip.EndTime-clip.StartTime != defaultSceneDuration {
+				t.Errorf("video duration %v, clip %d: %v-%v, want %v seconds", duration, i, clip.StartTime, clip.EndTime, defaultSceneDuration)
+			}
+		}
+	}
+
+	clips := sceneClipRequests("track-1", scenes, 30)
+	if clips[1].StartTime != 15 || clips[1].EndTime != 30 {
+		t.Errorf("clip 2 of a 30 second video: %v-%v, want 15-30", clips[1].StartTime, clips[1].EndTime)
+	}
+}
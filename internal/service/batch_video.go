@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"renderowl-api/internal/domain"
+)
+
+// maxVideoLogLines is how much of a video's processing log is kept
+const maxVideoLogLines = 50
+
+// BatchVideoDetail is a batch video whatever its status, with the stages it
+// went through and the tail of its processing log
+type BatchVideoDetail struct {
+	*domain.BatchVideo
+	Stages            []*ContentStage `json:"stages"`
+	ProcessingSeconds float64         `json:"processingSeconds,omitempty"` // so far, for a video still processing
+	Logs              []string        `json:"logs"`
+}
+
+// GetBatchVideo retrieves one of a batch's videos for debugging
+func (s *BatchService) GetBatchVideo(ctx context.Context, batchID, videoID, userID string) (*BatchVideoDetail, error) {
+	batch, err := s.repo.GetByIDAndUser(batchID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range batch.Videos {
+		video := &batch.Videos[i]
+		if video.ID != videoID {
+			continue
+		}
+
+		detail := &BatchVideoDetail{
+			BatchVideo: video,
+			Stages:     generationStatus(batch, video),
+			Logs:       video.Logs,
+		}
+		if detail.Logs == nil {
+			detail.Logs = []string{}
+		}
+		if video.StartedAt != nil {
+			end := time.Now()
+			if video.CompletedAt != nil {
+				end = *video.CompletedAt
+			} else if video.Status != domain.VideoStatusProcessing {
+				end = video.UpdatedAt
+			}
+			detail.ProcessingSeconds = end.Sub(*video.StartedAt).Seconds()
+		}
+		return detail, nil
+	}
+	return nil, domain.ErrBatchVideoNotFound
+}
+
+// logVideo writes a line to the server log and to the video's processing log,
+// which keeps only its last maxVideoLogLines lines
+func logVideo(video *domain.BatchVideo, format string, args ...any) {
+	line := fmt.Sprintf(format, args...)
+	log.Printf("Batch video %s: %s", video.ID, line)
+
+	video.Logs = append(video.Logs, time.Now().UTC().Format(time.RFC3339)+" "+line)
+	if len(video.Logs) > maxVideoLogLines {
+		video.Logs = video.Logs[len(video.Logs)-maxVideoLogLines:]
+	}
+}
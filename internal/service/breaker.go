@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Circuit breaker policy for AI providers
+const (
+	breakerFailureThreshold = 5
+	breakerCooldown         = 30 * time.Second
+)
+
+// AI provider names, shared by every service that calls them so an outage
+// seen by one trips the breaker for all
+const (
+	providerOpenAI     = "openai"
+	providerTogether   = "together"
+	providerStability  = "stability"
+	providerElevenLabs = "elevenlabs"
+)
+
+// BreakerState is the state of a provider's circuit breaker
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// ErrCircuitOpen is returned without calling the provider while its breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerStatus reports a provider's breaker for health checks
+type BreakerStatus struct {
+	State    BreakerState `json:"state"`
+	Failures int          `json:"failures"`
+	OpenedAt *time.Time   `json:"openedAt,omitempty"`
+}
+
+// circuitBreaker stops calling a provider after repeated failures. After the
+// cooldown it lets a single trial call through; success closes the breaker
+// and failure opens it again.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trial    bool // a half-open trial call is in flight
+}
+
+var providerBreakers = struct {
+	sync.Mutex
+	breakers map[string]*circuitBreaker
+}{breakers: make(map[string]*circuitBreaker)}
+
+// breakerFor returns the provider's breaker, creating it on first use
+func breakerFor(provider string) *circuitBreaker {
+	providerBreakers.Lock()
+	defer providerBreakers.Unlock()
+
+	b, ok := providerBreakers.breakers[provider]
+	if !ok {
+		b = &circuitBreaker{state: BreakerClosed}
+		providerBreakers.breakers[provider] = b
+	}
+	return b
+}
+
+// ProviderBreakers returns the breaker status of every AI provider called so far
+func ProviderBreakers() map[string]BreakerStatus {
+	providerBreakers.Lock()
+	defer providerBreakers.Unlock()
+
+	statuses := make(map[string]BreakerStatus, len(providerBreakers.breakers))
+	for provider, b := range providerBreakers.breakers {
+		statuses[provider] = b.status()
+	}
+	return statuses
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < breakerCooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.trial = true
+		return true
+	case BreakerHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	}
+	return true
+}
+
+func (b *circuitBreaker) record(provider string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+	if !countsAgainstProvider(err) {
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= breakerFailureThreshold) {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+		log.Printf("Circuit breaker for %s opened after %d failures: %v", provider, b.failures, err)
+	}
+}
+
+// countsAgainstProvider reports whether a failed call says the provider is
+// unhealthy: a 5xx or 429 response, no response at all, or the operation's
+// own timeout running out. Rejected requests and unreadable responses are
+// not the provider being down, and the caller giving up, or running out of
+// its own time, says nothing about the provider.
+func countsAgainstProvider(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return !timeoutErr.RequestDeadline
+	}
+
+	status := 0
+	var providerErr *ProviderError
+	var imageErr *imageProviderError
+	if errors.As(err, &providerErr) && providerErr.StatusCode != 0 {
+		status = providerErr.StatusCode
+	} else if errors.As(err, &imageErr) {
+		status = imageErr.StatusCode
+	}
+	if status != 0 {
+		return status == http.StatusTooManyRequests || status >= 500
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+func (b *circuitBreaker) status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{State: b.state, Failures: b.failures}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// ProviderError attributes a failed call to the AI provider it was made to.
+// StatusCode is the provider's HTTP status, 0 when there was no response.
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// withBreaker calls fn unless the provider's breaker is open. Errors are
+// returned as a *ProviderError naming the provider.
+func withBreaker[T any](provider string, fn func() (T, error)) (T, error) {
+	b := breakerFor(provider)
+	if !b.allow() {
+		var zero T
+		return zero, &ProviderError{Provider: provider, Err: fmt.Errorf("%s: %w", provider, ErrCircuitOpen)}
+	}
+
+	result, err := fn()
+	b.record(provider, err)
+	if err != nil {
+		var providerErr *ProviderError
+		if errors.As(err, &providerErr) {
+			providerErr.Provider = provider
+		} else {
+			err = &ProviderError{Provider: provider, Err: err}
+		}
+	}
+	return result, err
+}
+
+// providerCall is one attempt in a provider fallback chain
+type providerCall[T any] struct {
+	provider string
+	call     func() (T, error)
+}
+
+// withFallback tries each provider in order, moving straight to the next one
+// when a provider fails or its breaker is open. It returns the last error.
+func withFallback[T any](calls ...providerCall[T]) (T, error) {
+	var zero T
+	err := errors.New("no AI API key configured")
+	for i, c := range calls {
+		var result T
+		result, err = withBreaker(c.provider, c.call)
+		if err == nil {
+			return result, nil
+		}
+		if i < len(calls)-1 {
+			log.Printf("AI provider %s failed, falling back to %s: %v", c.provider, calls[i+1].provider, err)
+		}
+	}
+	return zero, err
+}
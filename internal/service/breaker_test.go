@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestBreakerCountsOnlyProviderFailures(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"server error", &ProviderError{StatusCode: 503, Err: errors.New("unavailable")}, true},
+		{"rate limited", &ProviderError{StatusCode: 429, Err: errors.New("slow down")}, true},
+		{"image server error", &imageProviderError{Provider: "Stability", StatusCode: 500}, true},
+		{"transport error", fmt.Errorf("failed to make request: %w", &url.Error{Op: "Post", URL: "https://api.example.com", Err: errors.New("connection refused")}), true},
+		{"operation timeout", &TimeoutError{Operation: AIOpScript, Err: context.DeadlineExceeded}, true},
+		{"bad request", &ProviderError{StatusCode: 400, Err: errors.New("invalid prompt")}, false},
+		{"unauthorized image request", &imageProviderError{Provider: "DALL-E", StatusCode: 401}, false},
+		{"unparseable response", fmt.Errorf("failed to parse response: %w", errors.New("unexpected end of JSON input")), false},
+		{"request deadline", &TimeoutError{Operation: AIOpScript, RequestDeadline: true, Err: context.DeadlineExceeded}, false},
+		{"caller cancelled", fmt.Errorf("failed to make request: %w", &url.Error{Op: "Post", URL: "https://api.example.com", Err: context.Canceled}), false},
+	}
+	for _, tc := range cases {
+		if got := countsAgainstProvider(tc.err); got != tc.want {
+			t.Errorf("%s: countsAgainstProvider = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBreakerStaysClosedOnRejectedRequests(t *testing.T) {
+	b := &circuitBreaker{state: BreakerClosed}
+	for range breakerFailureThreshold * 2 {
+		b.record("test", &ProviderError{StatusCode: 400, Err: errors.New("invalid prompt")})
+	}
+	if b.state != BreakerClosed || b.failures != 0 {
+		t.Fatalf("after rejected requests: state %s with %d failures, want closed with none", b.state, b.failures)
+	}
+
+	for range breakerFailureThreshold {
+		b.record("test", &ProviderError{StatusCode: 502, Err: errors.New("bad gateway")})
+	}
+	if b.state != BreakerOpen {
+		t.Errorf("after %d server errors: state %s, want open", breakerFailureThreshold, b.state)
+	}
+}
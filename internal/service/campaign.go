@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"renderowl-api/internal/domain"
+	socialdomain "renderowl-api/internal/domain/social"
+	"renderowl-api/internal/scheduler"
+	socialsvc "renderowl-api/internal/service/social"
+)
+
+// ErrCampaignNotFound is returned when a campaign doesn't exist or belongs to another user
+var ErrCampaignNotFound = domain.NewAppError(domain.CodeNotFound, http.StatusNotFound, "campaign not found")
+
+// Campaign job names; each step of a campaign runs as its own job
+const (
+	jobCampaignVariations = "campaign-variations"
+	jobCampaignPublish    = "campaign-publish"
+)
+
+// CampaignRepository defines campaign storage operations
+type CampaignRepository interface {
+	Create(ctx context.Context, campaign *socialdomain.Campaign) error
+	GetByID(ctx context.Context, id string) (*socialdomain.Campaign, error)
+	Update(ctx context.Context, campaign *socialdomain.Campaign) error
+}
+
+// CampaignService publishes a source video to several platforms in one go,
+// stitching together variations, account checks and scheduling
+type CampaignService struct {
+	campaigns  CampaignRepository
+	variations *VariationsService
+	publisher  *Publisher
+}
+
+// CampaignTargetInput names a platform to publish to. Without an account ID
+// the user's first connected account on that platform is used.
+type CampaignTargetInput struct {
+	Platform  string `json:"platform" binding:"required"` // variation platform, e.g. youtube or instagram_reels
+	AccountID string `json:"accountId,omitempty"`
+}
+
+// CreateCampaignRequest represents a request to publish a video everywhere
+type CreateCampaignRequest struct {
+	SourceVideoID  string                `json:"sourceVideoId" binding:"required"`
+	SourceVideoURL string                `json:"sourceVideoUrl" binding:"required"`
+	Targets        []CampaignTargetInput `json:"targets" binding:"required,min=1,dive"`
+	Title          string                `json:"title" binding:"required"`
+	Description    string                `json:"description"`
+	Tags           []string              `json:"tags"`
+	Privacy        string                `json:"privacy"`
+	ScheduledAt    *time.Time            `json:"scheduledAt,omitempty"` // omit to publish now
+	Timezone       string                `json:"timezone"`
+}
+
+// campaignJobData identifies the campaign a job works on
+type campaignJobData struct {
+	CampaignID string `json:"campaignId"`
+}
+
+// NewCampaignService creates a new campaign service
+func NewCampaignService(campaigns CampaignRepository, variations *VariationsService, publisher *Publisher) *CampaignService {
+	return &CampaignService{
+		campaigns:  campaigns,
+		variations: variations,
+		publisher:  publisher,
+	}
+}
+
+// Initialize registers the campaign job handlers
+func (s *CampaignService) Initialize() {
+	s.publisher.scheduler.RegisterHandler(jobCampaignVariations, s.handleVariationsJob)
+	s.publisher.scheduler.RegisterHandler(jobCampaignPublish, s.handlePublishJob)
+}
+
+// CreateCampaign checks the targets, stores the campaign and queues its
+// first step. Variations are generated and posts scheduled in the background;
+// GetCampaign reports progress.
+func (s *CampaignService) CreateCampaign(ctx context.Context, userID string, req *CreateCampaignRequest) (*socialdomain.Campaign, error) {
+	if req.ScheduledAt != nil && req.ScheduledAt.Before(time.Now()) {
+		return nil, domain.NewValidationError("scheduledAt must be in the future")
+	}
+
+	accounts, err := s.publisher.socialService.GetAccounts(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	targets := make([]socialdomain.CampaignTarget, 0, len(req.Targets))
+	seen := make(map[string]bool)
+	for _, input := range req.Targets {
+		if _, ok := PlatformSpecs[input.Platform]; !ok {
+			return nil, domain.NewValidationError(fmt.Sprintf("unsupported platform %q", input.Platform))
+		}
+		account := campaignAccount(accounts, SocialPlatformFor(input.Platform), input.AccountID)
+		if account == nil {
+			if input.AccountID != "" {
+				return nil, fmt.Errorf("%w: %s", socialsvc.ErrAccountNotFound, input.AccountID)
+			}
+			return nil, domain.NewValidationError(fmt.Sprintf("no connected %s account", SocialPlatformFor(input.Platform)))
+		}
+		if seen[account.ID] {
+			return nil, domain.NewValidationError(fmt.Sprintf("account %s is targeted more than once", account.ID))
+		}
+		seen[account.ID] = true
+
+		targets = append(targets, socialdomain.CampaignTarget{
+			Platform:  input.Platform,
+			AccountID: account.ID,
+			Status:    socialdomain.CampaignTargetPending,
+		})
+	}
+
+	campaign := &socialdomain.Campaign{
+		UserID:         userID,
+		SourceVideoID:  req.SourceVideoID,
+		SourceVideoURL: req.SourceVideoURL,
+		Title:          req.Title,
+		Description:    req.Description,
+		Tags:           req.Tags,
+		Privacy:        req.Privacy,
+		ScheduledAt:    req.ScheduledAt,
+		Timezone:       req.Timezone,
+		Targets:        targets,
+		Status:         socialdomain.CampaignStatusPending,
+	}
+	if err := s.campaigns.Create(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	if err := s.queueStep(ctx, jobCampaignVariations, campaign.ID); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// GetCampaign returns a campaign with each target's status brought up to date
+// with its post
+func (s *CampaignService) GetCampaign(ctx context.Context, id, userID string) (*socialdomain.Campaign, error) {
+	campaign, err := s.campaigns.GetByID(ctx, id)
+	if err != nil || campaign.UserID != userID {
+		return nil, ErrCampaignNotFound
+	}
+
+	// Once scheduled the campaign's jobs are done, so this is the only writer
+	if campaign.Status != socialdomain.CampaignStatusScheduled {
+		return campaign, nil
+	}
+
+	for i := range campaign.Targets {
+		target := &campaign.Targets[i]
+		if target.PostID == "" {
+			continue
+		}
+		post, err := s.publisher.postRepo.GetByID(ctx, target.PostID)
+		if err != nil {
+			continue
+		}
+		for _, platformPost := range post.Platforms {
+			if platformPost.AccountID != target.AccountID {
+				continue
+			}
+			target.Status = platformPost.Status
+			target.PostURL = platformPost.PostURL
+			if platformPost.Status == socialdomain.PostStatusFailed {
+				target.Error = platformPost.ErrorMsg
+			}
+		}
+	}
+
+	if status := campaignStatus(campaign.Targets); status != campaign.Status {
+		campaign.Status = status
+		if err := s.campaigns.Update(ctx, campaign); err != nil {
+			log.Printf("Failed to update campaign %s: %v", campaign.ID, err)
+		}
+	}
+	return campaign, nil
+}
+
+// handleVariationsJob generates the platform variation for every pending
+// target, sharing one variation between targets on the same platform
+func (s *CampaignService) handleVariationsJob(ctx context.Context, job *scheduler.Job) error {
+	campaign, err := s.loadJobCampaign(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	campaign.Status = socialdomain.CampaignStatusGenerating
+	generated := make(map[string]*VideoVariation)
+	for i := range campaign.Targets {
+		target := &campaign.Targets[i]
+		if target.Status != socialdomain.CampaignTargetPending {
+			continue
+		}
+
+		variation, ok := generated[target.Platform]
+		if !ok {
+			variation, err = s.variations.CreatePlatformVersion(ctx, campaign.SourceVideoID, campaign.SourceVideoURL, target.Platform)
+			if err != nil {
+				target.Status = socialdomain.PostStatusFailed
+				target.Error = fmt.Sprintf("variation failed: %v", err)
+				continue
+			}
+			generated[target.Platform] = variation
+		}
+
+		target.Status = socialdomain.CampaignTargetGenerated
+		target.VariationID = variation.ID
+		target.VideoURL = variation.VideoURL
+	}
+
+	if err := s.campaigns.Update(ctx, campaign); err != nil {
+		return fmt.Errorf("failed to update campaign: %w", err)
+	}
+	return s.queueStep(ctx, jobCampaignPublish, campaign.ID)
+}
+
+// handlePublishJob validates each generated target and schedules a post for
+// it. Targets that fail validation are marked failed with the reasons.
+func (s *CampaignService) handlePublishJob(ctx context.Context, job *scheduler.Job) error {
+	campaign, err := s.loadJobCampaign(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	for i := range campaign.Targets {
+		target := &campaign.Targets[i]
+		if target.Status != socialdomain.CampaignTargetGenerated {
+			continue
+		}
+		if err := s.scheduleTarget(ctx, campaign, target); err != nil {
+			target.Status = socialdomain.PostStatusFailed
+			target.Error = err.Error()
+		}
+	}
+
+	// Nothing has published yet, so this is scheduled or, when every target
+	// failed, failed; the status endpoint takes it from here
+	campaign.Status = campaignStatus(campaign.Targets)
+	if err := s.campaigns.Update(ctx, campaign); err != nil {
+		return fmt.Errorf("failed to update campaign: %w", err)
+	}
+	return nil
+}
+
+// scheduleTarget validates a target's post and hands it to the publisher
+func (s *CampaignService) scheduleTarget(ctx context.Context, campaign *socialdomain.Campaign, target *socialdomain.CampaignTarget) error {
+	post, err := s.targetPost(ctx, campaign, target)
+	if err != nil {
+		return err
+	}
+
+	if err := s.publisher.socialService.SchedulePost(ctx, post); err != nil {
+		return err
+	}
+	if err := s.publisher.SchedulePublish(ctx, post); err != nil {
+		return err
+	}
+
+	target.Status = socialdomain.PostStatusScheduled
+	target.PostID = post.ID
+	return nil
+}
+
+// targetPost builds the post publishing a target's variation, and fails
+// unless it passes every readiness check
+func (s *CampaignService) targetPost(ctx context.Context, campaign *socialdomain.Campaign, target *socialdomain.CampaignTarget) (*socialdomain.ScheduledPost, error) {
+	scheduledAt := time.Now()
+	if campaign.ScheduledAt != nil && campaign.ScheduledAt.After(scheduledAt) {
+		scheduledAt = *campaign.ScheduledAt
+	}
+
+	post := &socialdomain.ScheduledPost{
+		UserID:      campaign.UserID,
+		VideoID:     campaign.SourceVideoID,
+		Title:       campaign.Title,
+		Description: campaign.Description,
+		Platforms: []socialdomain.PlatformPost{{
+			AccountID:   target.AccountID,
+			Platform:    SocialPlatformFor(target.Platform),
+			CustomTitle: campaign.Title,
+			CustomDesc:  campaign.Description,
+			Tags:        campaign.Tags,
+			Privacy:     campaign.Privacy,
+		}},
+		ScheduledAt: scheduledAt,
+		Timezone:    campaign.Timezone,
+		Metadata: socialdomain.JSON{
+			"videoPath":         target.VideoURL,
+			"variationId":       target.VariationID,
+			"variationPlatform": target.Platform,
+			"campaignId":        campaign.ID,
+		},
+	}
+
+	report, err := s.publisher.socialService.ValidateScheduledPost(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	if !report.Ready {
+		var problems []string
+		for _, platform := range report.Platforms {
+			for _, check := range platform.Checks {
+				if !check.Passed {
+					problems = append(problems, check.Name+": "+check.Message)
+				}
+			}
+		}
+		return nil, fmt.Errorf("not ready to publish: %s", strings.Join(problems, "; "))
+	}
+	return post, nil
+}
+
+func (s *CampaignService) loadJobCampaign(ctx context.Context, job *scheduler.Job) (*socialdomain.Campaign, error) {
+	var data campaignJobData
+	if err := json.Unmarshal(job.Data, &data); err != nil {
+		return nil, fmt.Errorf("invalid job data: %w", err)
+	}
+	campaign, err := s.campaigns.GetByID(ctx, data.CampaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign %s: %w", data.CampaignID, err)
+	}
+	return campaign, nil
+}
+
+func (s *CampaignService) queueStep(ctx context.Context, name, campaignID string) error {
+	data, _ := json.Marshal(campaignJobData{CampaignID: campaignID})
+	job := &scheduler.Job{
+		Name:       name,
+		Data:       data,
+		MaxRetries: 3,
+	}
+	if err := s.publisher.scheduler.AddJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to queue %s: %w", name, err)
+	}
+	return nil
+}
+
+// campaignAccount picks the account a target publishes from
+func campaignAccount(accounts []*socialdomain.SocialAccount, platform socialdomain.SocialPlatform, accountID string) *socialdomain.SocialAccount {
+	for _, account := range accounts {
+		if account.Platform != platform {
+			continue
+		}
+		if account.ID == accountID || (accountID == "" && account.Status == socialdomain.StatusConnected) {
+			return account
+		}
+	}
+	return nil
+}
+
+// campaignStatus derives a campaign's status from its targets: scheduled
+// while any target is still on its way, then completed if any published
+func campaignStatus(targets []socialdomain.CampaignTarget) socialdomain.CampaignStatus {
+	published := false
+	for _, target := range targets {
+		switch target.Status {
+		case socialdomain.PostStatusPublished:
+			published = true
+		case socialdomain.PostStatusFailed, socialdomain.PostStatusCancelled:
+		default:
+			return socialdomain.CampaignStatusScheduled
+		}
+	}
+	if published {
+		return socialdomain.CampaignStatusCompleted
+	}
+	return socialdomain.CampaignStatusFailed
+}
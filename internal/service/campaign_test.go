@@ -0,0 +1,74 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	socialdomain "renderowl-api/internal/domain/social"
+	socialsvc "renderowl-api/internal/service/social"
+)
+
+// campaignPlatform is a configured platform that is never published to
+type campaignPlatform struct {
+	socialsvc.Platform
+	name socialdomain.SocialPlatform
+}
+
+func (p campaignPlatform) GetName() socialdomain.SocialPlatform { return p.name }
+
+// campaignAccounts holds social accounts by ID
+type campaignAccounts struct {
+	socialsvc.AccountRepository
+	accounts map[string]*socialdomain.SocialAccount
+}
+
+func (a campaignAccounts) GetByID(ctx context.Context, id string) (*socialdomain.SocialAccount, error) {
+	if account, ok := a.accounts[id]; ok {
+		return account, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestCampaignTargetsAcceptUploadedVideos(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir(), "https://files.example.com")
+	uploads := NewMediaUploadService(storage, nil, 1<<20, 0)
+	stored, err := uploads.Store(context.Background(), "user-a", "clip.mp4", "video/mp4", bytes.NewReader([]byte("video")))
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	registry := socialsvc.NewPlatformRegistry()
+	accounts := campaignAccounts{accounts: map[string]*socialdomain.SocialAccount{}}
+	platforms := []socialdomain.SocialPlatform{socialdomain.PlatformYouTube, socialdomain.PlatformTikTok, socialdomain.PlatformTwitter}
+	for _, platform := range platforms {
+		registry.Register(campaignPlatform{name: platform})
+		accounts.accounts[string(platform)] = &socialdomain.SocialAccount{
+			ID:       string(platform),
+			UserID:   "user-a",
+			Platform: platform,
+			Status:   socialdomain.StatusConnected,
+		}
+	}
+	socialService := socialsvc.NewService(registry, accounts, nil, nil)
+	socialService.SetMediaFiles(uploads)
+	campaigns := NewCampaignService(nil, nil, &Publisher{socialService: socialService})
+
+	campaign := &socialdomain.Campaign{ID: "campaign-1", UserID: "user-a", Title: "Launch", Description: "Out now"}
+	for _, platform := range platforms {
+		for _, videoURL := range []string{stored.URL, stored.Key} {
+			target := &socialdomain.CampaignTarget{Platform: string(platform), AccountID: string(platform), VideoURL: videoURL}
+			if _, err := campaigns.targetPost(context.Background(), campaign, target); err != nil {
+				t.Errorf("%s target with upload %s: %v", platform, videoURL, err)
+			}
+		}
+
+		target := &socialdomain.CampaignTarget{Platform: string(platform), AccountID: string(platform), VideoURL: "/tmp/clip.mp4"}
+		_, err := campaigns.targetPost(context.Background(), campaign, target)
+		if err == nil || !strings.Contains(err.Error(), "not ready to publish") {
+			t.Errorf("%s target with a local path: err = %v, want not ready to publish", platform, err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Caption layout defaults, sized for 9:16 shorts
+const (
+	defaultCaptionLineChars = 32
+	defaultCaptionLines     = 2
+)
+
+// ErrNoCaptionSource is returned when a caption preview has neither a transcript nor audio to transcribe
+var ErrNoCaptionSource = errors.New("a transcript or an audioUrl is required")
+
+// CaptionOptions controls how transcript segments are split into cues
+type CaptionOptions struct {
+	MaxLineChars int `json:"maxLineChars,omitempty"` // defaults to 32
+	MaxLines     int `json:"maxLines,omitempty"`     // defaults to 2
+}
+
+// CaptionCue is one caption as it is burned in, in seconds from the start of
+// the video
+type CaptionCue struct {
+	Start float64  `json:"start"`
+	End   float64  `json:"end"`
+	Text  string   `json:"text"`
+	Lines []string `json:"lines"`
+}
+
+// CaptionPreviewRequest represents a request to preview a short's captions
+type CaptionPreviewRequest struct {
+	Transcript *Transcript    `json:"transcript,omitempty"`
+	AudioURL   string         `json:"audioUrl,omitempty" binding:"omitempty,url"` // transcribed when no transcript is given
+	Language   string         `json:"language,omitempty"`
+	Start      float64        `json:"start,omitempty" binding:"min=0"` // the short's span of the source, all of it when End is 0
+	End        float64        `json:"end,omitempty" binding:"min=0"`
+	Options    CaptionOptions `json:"options"`
+}
+
+// CaptionPreview is the caption cues a short would be rendered with
+type CaptionPreview struct {
+	Language string       `json:"language,omitempty"`
+	Cues     []CaptionCue `json:"cues"`
+}
+
+// SetTranscriber enables caption previews from audio rather than a transcript
+func (s *VariationsService) SetTranscriber(transcriber *TranscriptionService) {
+	s.transcriber = transcriber
+}
+
+// PreviewCaptions computes the caption cues for a short without rendering it
+func (s *VariationsService) PreviewCaptions(ctx context.Context, req *CaptionPreviewRequest) (*CaptionPreview, error) {
+	transcript := req.Transcript
+	if transcript == nil {
+		if req.AudioURL == "" {
+			return nil, ErrNoCaptionSource
+		}
+		if s.transcriber == nil {
+			return nil, fmt.Errorf("transcription not configured")
+		}
+		var err error
+		transcript, err = s.transcriber.Transcribe(ctx, &TranscribeRequest{URL: req.AudioURL, Language: req.Language})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	segments := transcript.Segments
+	if req.End > req.Start {
+		segments = transcript.Between(req.Start, req.End)
+	}
+
+	return &CaptionPreview{
+		Language: transcript.Language,
+		Cues:     BuildCaptionCues(segments, req.Options),
+	}, nil
+}
+
+// BuildCaptionCues splits transcript segments into cues of at most MaxLines
+// lines of MaxLineChars characters. A segment that needs several cues shares
+// its time between them by length, so each cue stays up about as long as it
+// takes to read.
+func BuildCaptionCues(segments []TranscriptSegment, opts CaptionOptions) []CaptionCue {
+	if opts.MaxLineChars <= 0 {
+		opts.MaxLineChars = defaultCaptionLineChars
+	}
+	if opts.MaxLines <= 0 {
+		opts.MaxLines = defaultCaptionLines
+	}
+
+	cues := []CaptionCue{}
+	for _, seg := range segments {
+		lines := wrapCaption(seg.Text, opts.MaxLineChars)
+		if len(lines) == 0 || seg.End <= seg.Start {
+			continue
+		}
+
+		total := 0
+		for _, line := range lines {
+			total += utf8.RuneCountInString(line)
+		}
+
+		var groups [][]string
+		for i := 0; i < len(lines); i += opts.MaxLines {
+			end := i + opts.MaxLines
+			if end > len(lines) {
+				end = len(lines)
+			}
+			groups = append(groups, lines[i:end])
+		}
+
+		start, done := seg.Start, 0
+		for i, group := range groups {
+			for _, line := range group {
+				done += utf8.RuneCountInString(line)
+			}
+			end := seg.Start + (seg.End-seg.Start)*float64(done)/float64(total)
+			if i == len(groups)-1 {
+				end = seg.End
+			}
+			cues = append(cues, CaptionCue{
+				Start: start,
+				End:   end,
+				Text:  strings.Join(group, " "),
+				Lines: group,
+			})
+			start = end
+		}
+	}
+	return cues
+}
+
+// wrapCaption breaks text into lines of at most maxChars characters at word
+// boundaries; a word longer than a line gets a line of its own
+func wrapCaption(text string, maxChars int) []string {
+	var lines []string
+	var line strings.Builder
+	for _, word := range strings.Fields(text) {
+		if line.Len() > 0 && utf8.RuneCountInString(line.String())+1+utf8.RuneCountInString(word) > maxChars {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		if line.Len() > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(word)
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
@@ -2,11 +2,21 @@ package service
 
 import (
 	"errors"
+	"fmt"
 
 	"renderowl-api/internal/domain"
 	"renderowl-api/internal/repository"
 )
 
+// ErrInvalidClipAudio is returned when a clip update has an out of range volume or fade
+var ErrInvalidClipAudio = errors.New("invalid clip audio settings")
+
+// ErrInvalidClipSource is returned when a clip's source in/out points don't fit its source media
+var ErrInvalidClipSource = errors.New("invalid clip source range")
+
+// maxClipVolume is the loudest a clip can be boosted to
+const maxClipVolume = 2
+
 // ClipService handles clip business logic
 type ClipService struct {
 	clipRepo     *repository.ClipRepository
@@ -29,37 +39,63 @@ func (s *ClipService) Create(userID string, timelineID string, req *CreateClipRe
 		return nil, errors.New("timeline not found or access denied")
 	}
 
-	clip := &domain.Clip{
-		TimelineID:  timelineID,
-		TrackID:     req.TrackID,
-		Name:        req.Name,
-		Type:        req.Type,
-		SourceURL:   req.SourceURL,
-		StartTime:   req.StartTime,
-		EndTime:     req.EndTime,
-		Duration:    req.EndTime - req.StartTime,
-		TrimStart:   req.TrimStart,
-		TrimEnd:     req.TrimEnd,
-		PositionX:   req.PositionX,
-		PositionY:   req.PositionY,
-		Scale:       req.Scale,
-		Rotation:    req.Rotation,
-		Opacity:     req.Opacity,
-		TextContent: req.TextContent,
-		TextStyle:   req.TextStyle,
+	clip := newClip(timelineID, req)
+	if err := validateClipSource(clip); err != nil {
+		return nil, err
 	}
 
-	if clip.Scale == 0 {
-		clip.Scale = 1
+	if err := s.clipRepo.Create(clip); err != nil {
+		return nil, err
 	}
-	if clip.Opacity == 0 {
-		clip.Opacity = 1
+	return clip, nil
+}
+
+// CreateBulk validates a set of clips as a whole and inserts them in one
+// transaction, so either every clip is created or none are
+func (s *ClipService) CreateBulk(userID string, timelineID string, reqs []CreateClipRequest) ([]*domain.Clip, error) {
+	// Verify timeline belongs to user
+	_, err := s.timelineRepo.GetByIDAndUser(timelineID, userID)
+	if err != nil {
+		return nil, errors.New("timeline not found or access denied")
 	}
 
-	if err := s.clipRepo.Create(clip); err != nil {
+	if len(reqs) == 0 {
+		return nil, errors.New("no clips provided")
+	}
+
+	existing, err := s.clipRepo.ListByTimeline(timelineID)
+	if err != nil {
 		return nil, err
 	}
-	return clip, nil
+
+	clips := make([]*domain.Clip, 0, len(reqs))
+	for i := range reqs {
+		if err := validateClipRequest(&reqs[i]); err != nil {
+			return nil, fmt.Errorf("clip %d: %w", i, err)
+		}
+		clip := newClip(timelineID, &reqs[i])
+		if err := validateClipSource(clip); err != nil {
+			return nil, fmt.Errorf("clip %d: %w", i, err)
+		}
+
+		for _, other := range existing {
+			if clipsOverlap(clip, other) {
+				return nil, fmt.Errorf("clip %d: overlaps existing clip %s on track %s", i, other.ID, clip.TrackID)
+			}
+		}
+		for j, other := range clips {
+			if clipsOverlap(clip, other) {
+				return nil, fmt.Errorf("clip %d: overlaps clip %d on track %s", i, j, clip.TrackID)
+			}
+		}
+
+		clips = append(clips, clip)
+	}
+
+	if err := s.clipRepo.CreateMany(clips); err != nil {
+		return nil, err
+	}
+	return clips, nil
 }
 
 // Get retrieves a clip by ID
@@ -115,6 +151,15 @@ func (s *ClipService) Update(userID, clipID string, req *UpdateClipRequest) (*do
 	if req.TrimEnd > 0 {
 		clip.TrimEnd = req.TrimEnd
 	}
+	if req.SourceStart != nil {
+		clip.SourceStart = *req.SourceStart
+	}
+	if req.SourceEnd != nil {
+		clip.SourceEnd = *req.SourceEnd
+	}
+	if req.SourceLength != nil {
+		clip.SourceLength = *req.SourceLength
+	}
 	if req.PositionX != 0 || req.PositionY != 0 {
 		clip.PositionX = req.PositionX
 		clip.PositionY = req.PositionY
@@ -134,6 +179,21 @@ func (s *ClipService) Update(userID, clipID string, req *UpdateClipRequest) (*do
 	if req.TextStyle != nil {
 		clip.TextStyle = req.TextStyle
 	}
+	if req.Volume != nil {
+		clip.Volume = *req.Volume
+	}
+	if req.FadeIn != nil {
+		clip.FadeIn = *req.FadeIn
+	}
+	if req.FadeOut != nil {
+		clip.FadeOut = *req.FadeOut
+	}
+	if err := validateClipAudio(clip); err != nil {
+		return nil, err
+	}
+	if err := validateClipSource(clip); err != nil {
+		return nil, err
+	}
 
 	if err := s.clipRepo.Update(clip); err != nil {
 		return nil, err
@@ -150,37 +210,139 @@ func (s *ClipService) Delete(userID, clipID string) error {
 	return s.clipRepo.Delete(clipID)
 }
 
+// newClip builds a clip from a create request, applying defaults
+func newClip(timelineID string, req *CreateClipRequest) *domain.Clip {
+	clip := &domain.Clip{
+		TimelineID:  timelineID,
+		TrackID:     req.TrackID,
+		Name:        req.Name,
+		Type:        req.Type,
+		SourceURL:   req.SourceURL,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Duration:    req.EndTime - req.StartTime,
+		TrimStart:   req.TrimStart,
+		TrimEnd:     req.TrimEnd,
+		PositionX:   req.PositionX,
+		PositionY:   req.PositionY,
+		Scale:       req.Scale,
+		Rotation:    req.Rotation,
+		Opacity:     req.Opacity,
+		TextContent: req.TextContent,
+		TextStyle:   req.TextStyle,
+	}
+
+	if clip.Scale == 0 {
+		clip.Scale = 1
+	}
+	if clip.Opacity == 0 {
+		clip.Opacity = 1
+	}
+	clip.Volume = 1
+	clip.SourceStart = req.SourceStart
+	clip.SourceEnd = req.SourceEnd
+	clip.SourceLength = req.SourceLength
+
+	return clip
+}
+
+// validateClipRequest checks the fields binding can't, since a zero start time is valid
+func validateClipRequest(req *CreateClipRequest) error {
+	switch {
+	case req.TrackID == "":
+		return errors.New("trackId is required")
+	case req.Name == "":
+		return errors.New("name is required")
+	case req.Type == "":
+		return errors.New("type is required")
+	case req.StartTime < 0:
+		return errors.New("startTime must not be negative")
+	case req.EndTime <= req.StartTime:
+		return errors.New("endTime must be after startTime")
+	case req.TrimStart < 0 || req.TrimEnd < 0:
+		return errors.New("trim values must not be negative")
+	}
+	return nil
+}
+
+// validateClipAudio checks a clip's volume and that its fades fit inside it.
+// Fades are checked against the clip as it will be once the update is applied,
+// so shortening a clip can't leave its fades overlapping.
+func validateClipAudio(clip *domain.Clip) error {
+	length := clip.EndTime - clip.StartTime
+	switch {
+	case clip.Volume < 0 || clip.Volume > maxClipVolume:
+		return fmt.Errorf("%w: volume must be between 0 and %d", ErrInvalidClipAudio, maxClipVolume)
+	case clip.FadeIn < 0 || clip.FadeOut < 0:
+		return fmt.Errorf("%w: fades must not be negative", ErrInvalidClipAudio)
+	case clip.FadeIn+clip.FadeOut > length:
+		return fmt.Errorf("%w: fadeIn and fadeOut together must fit in the clip's %gs", ErrInvalidClipAudio, length)
+	}
+	return nil
+}
+
+// validateClipSource checks a clip's source in/out points. When the source
+// media's length is known the range has to lie inside it.
+func validateClipSource(clip *domain.Clip) error {
+	in := clip.SourceIn()
+	switch {
+	case clip.SourceStart < 0 || clip.SourceEnd < 0 || clip.SourceLength < 0:
+		return fmt.Errorf("%w: source values must not be negative", ErrInvalidClipSource)
+	case clip.SourceEnd > 0 && clip.SourceEnd <= in:
+		return fmt.Errorf("%w: sourceEnd must be after sourceStart", ErrInvalidClipSource)
+	case clip.SourceLength > 0 && in >= clip.SourceLength:
+		return fmt.Errorf("%w: sourceStart must be before the end of the %gs source", ErrInvalidClipSource, clip.SourceLength)
+	case clip.SourceLength > 0 && clip.SourceEnd > clip.SourceLength:
+		return fmt.Errorf("%w: sourceEnd must not be past the end of the %gs source", ErrInvalidClipSource, clip.SourceLength)
+	}
+	return nil
+}
+
+// clipsOverlap reports whether two clips on the same track overlap in time
+func clipsOverlap(a, b *domain.Clip) bool {
+	return a.TrackID == b.TrackID && a.StartTime < b.EndTime && b.StartTime < a.EndTime
+}
+
 // Request types
 type CreateClipRequest struct {
-	TrackID     string         `json:"trackId" binding:"required"`
-	Name        string         `json:"name" binding:"required"`
-	Type        string         `json:"type" binding:"required"`
-	SourceURL   string         `json:"sourceUrl"`
-	StartTime   float64        `json:"startTime" binding:"required"`
-	EndTime     float64        `json:"endTime" binding:"required"`
-	TrimStart   float64        `json:"trimStart"`
-	TrimEnd     float64        `json:"trimEnd"`
-	PositionX   float64        `json:"positionX"`
-	PositionY   float64        `json:"positionY"`
-	Scale       float64        `json:"scale"`
-	Rotation    float64        `json:"rotation"`
-	Opacity     float64        `json:"opacity"`
-	TextContent string         `json:"textContent"`
-	TextStyle   *domain.Style  `json:"textStyle"`
+	TrackID      string        `json:"trackId" binding:"required"`
+	Name         string        `json:"name" binding:"required"`
+	Type         string        `json:"type" binding:"required"`
+	SourceURL    string        `json:"sourceUrl"`
+	StartTime    float64       `json:"startTime" binding:"required"`
+	EndTime      float64       `json:"endTime" binding:"required"`
+	TrimStart    float64       `json:"trimStart"`
+	TrimEnd      float64       `json:"trimEnd"`
+	SourceStart  float64       `json:"sourceStart"`
+	SourceEnd    float64       `json:"sourceEnd"`    // 0 plays the source on for the clip's length
+	SourceLength float64       `json:"sourceLength"` // length of the source media, checked against when set
+	PositionX    float64       `json:"positionX"`
+	PositionY    float64       `json:"positionY"`
+	Scale        float64       `json:"scale"`
+	Rotation     float64       `json:"rotation"`
+	Opacity      float64       `json:"opacity"`
+	TextContent  string        `json:"textContent"`
+	TextStyle    *domain.Style `json:"textStyle"`
 }
 
 type UpdateClipRequest struct {
-	Name        string        `json:"name"`
-	SourceURL   string        `json:"sourceUrl"`
-	StartTime   float64       `json:"startTime"`
-	EndTime     float64       `json:"endTime"`
-	TrimStart   float64       `json:"trimStart"`
-	TrimEnd     float64       `json:"trimEnd"`
-	PositionX   float64       `json:"positionX"`
-	PositionY   float64       `json:"positionY"`
-	Scale       float64       `json:"scale"`
-	Rotation    float64       `json:"rotation"`
-	Opacity     float64       `json:"opacity"`
-	TextContent string        `json:"textContent"`
-	TextStyle   *domain.Style `json:"textStyle"`
+	Name         string        `json:"name"`
+	SourceURL    string        `json:"sourceUrl"`
+	StartTime    float64       `json:"startTime"`
+	EndTime      float64       `json:"endTime"`
+	TrimStart    float64       `json:"trimStart"`
+	TrimEnd      float64       `json:"trimEnd"`
+	SourceStart  *float64      `json:"sourceStart"`
+	SourceEnd    *float64      `json:"sourceEnd"`    // 0 plays the source on for the clip's length
+	SourceLength *float64      `json:"sourceLength"` // length of the source media, checked against when set
+	PositionX    float64       `json:"positionX"`
+	PositionY    float64       `json:"positionY"`
+	Scale        float64       `json:"scale"`
+	Rotation     float64       `json:"rotation"`
+	Opacity      float64       `json:"opacity"`
+	TextContent  string        `json:"textContent"`
+	TextStyle    *domain.Style `json:"textStyle"`
+	Volume       *float64      `json:"volume"`  // 0-2, 1 leaves the audio unchanged
+	FadeIn       *float64      `json:"fadeIn"`  // seconds
+	FadeOut      *float64      `json:"fadeOut"` // seconds
 }
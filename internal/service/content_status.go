@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"renderowl-api/internal/domain"
+	socialdomain "renderowl-api/internal/domain/social"
+)
+
+// ErrContentNotFound is returned when a content ID doesn't match a video of the user
+var ErrContentNotFound = errors.New("content not found")
+
+// PipelineStage is a step a video goes through on its way to being published
+type PipelineStage string
+
+const (
+	StageIdeation   PipelineStage = "ideation"
+	StageScript     PipelineStage = "script"
+	StageScenes     PipelineStage = "scenes"
+	StageTTS        PipelineStage = "tts"
+	StageTimeline   PipelineStage = "timeline"
+	StageRender     PipelineStage = "render"
+	StageVariations PipelineStage = "variations"
+	StagePublish    PipelineStage = "publish"
+)
+
+// StageStatus is how far a pipeline stage has got
+type StageStatus string
+
+const (
+	StageStatusPending    StageStatus = "pending"
+	StageStatusInProgress StageStatus = "in_progress"
+	StageStatusCompleted  StageStatus = "completed"
+	StageStatusFailed     StageStatus = "failed"
+	StageStatusSkipped    StageStatus = "skipped"
+)
+
+// generationStages are the stages a batch worker runs, in order. The worker
+// reports 25% progress per finished stage.
+var generationStages = []PipelineStage{StageScript, StageScenes, StageTTS, StageTimeline}
+
+// ContentPostStore lists the scheduled posts of a video
+type ContentPostStore interface {
+	ListByVideo(ctx context.Context, userID, videoID string) ([]*socialdomain.ScheduledPost, error)
+}
+
+// ContentCampaignStore lists the publishing campaigns of a video
+type ContentCampaignStore interface {
+	ListBySourceVideo(ctx context.Context, userID, videoID string) ([]*socialdomain.Campaign, error)
+}
+
+// ContentStatusService reports where a video is in the content pipeline,
+// joining its batch generation, campaigns and posts
+type ContentStatusService struct {
+	batchRepo domain.BatchRepository
+	posts     ContentPostStore
+	campaigns ContentCampaignStore
+}
+
+// ContentStatus is a video's progress through the content pipeline
+type ContentStatus struct {
+	ContentID    string          `json:"contentId"`
+	BatchID      string          `json:"batchId"`
+	Title        string          `json:"title"`
+	Status       StageStatus     `json:"status"`
+	CurrentStage PipelineStage   `json:"currentStage"`
+	Stages       []*ContentStage `json:"stages"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+// ContentStage is the status of one pipeline stage and what it produced
+type ContentStage struct {
+	Stage     PipelineStage     `json:"stage"`
+	Status    StageStatus       `json:"status"`
+	Error     string            `json:"error,omitempty"`
+	Artifacts []ContentArtifact `json:"artifacts,omitempty"`
+}
+
+// ContentArtifact is something a stage produced
+type ContentArtifact struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// NewContentStatusService creates a new content status service
+func NewContentStatusService(batchRepo domain.BatchRepository, posts ContentPostStore, campaigns ContentCampaignStore) *ContentStatusService {
+	return &ContentStatusService{
+		batchRepo: batchRepo,
+		posts:     posts,
+		campaigns: campaigns,
+	}
+}
+
+// GetStatus returns the pipeline status of a content item. The content ID is
+// the batch video's ID, which campaigns and posts reference as their video ID.
+func (s *ContentStatusService) GetStatus(ctx context.Context, userID, contentID string) (*ContentStatus, error) {
+	batch, err := s.batchRepo.GetByVideoIDAndUser(contentID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrBatchNotFound) {
+			return nil, ErrContentNotFound
+		}
+		return nil, fmt.Errorf("failed to get batch: %w", err)
+	}
+	var video *domain.BatchVideo
+	for i := range batch.Videos {
+		if batch.Videos[i].ID == contentID {
+			video = &batch.Videos[i]
+		}
+	}
+	if video == nil {
+		return nil, ErrContentNotFound
+	}
+
+	campaigns, err := s.campaigns.ListBySourceVideo(ctx, userID, contentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	posts, err := s.posts.ListByVideo(ctx, userID, contentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	stages := []*ContentStage{ideationStage(video)}
+	stages = append(stages, generationStatus(batch, video)...)
+	stages = append(stages, renderStage(video), variationsStage(campaigns), publishStage(posts))
+
+	status := &ContentStatus{
+		ContentID: video.ID,
+		BatchID:   batch.ID,
+		Title:     video.Title,
+		Stages:    stages,
+		UpdatedAt: video.UpdatedAt,
+	}
+	status.Status, status.CurrentStage = summarizeStages(stages)
+	return status, nil
+}
+
+// ideationStage reports the topic the video was made from. Scripts written
+// by hand skip ideation.
+func ideationStage(video *domain.BatchVideo) *ContentStage {
+	if video.Config.Topic == "" {
+		return &ContentStage{Stage: StageIdeation, Status: StageStatusSkipped}
+	}
+	return &ContentStage{Stage: StageIdeation, Status: StageStatusCompleted}
+}
+
+// generationStatus derives the batch worker's stages from the video's status
+// and progress. A failed video's progress is reset, so the stage recorded
+// with the failure is used instead.
+func generationStatus(batch *domain.Batch, video *domain.BatchVideo) []*ContentStage {
+	reached, failedAt := 0, -1
+	switch video.Status {
+	case domain.VideoStatusCompleted:
+		reached = len(generationStages)
+	case domain.VideoStatusProcessing:
+		reached = min(int(video.Progress/25), len(generationStages)-1)
+	case domain.VideoStatusFailed:
+		failedAt = failedGenerationStage(video.FailedStage)
+		reached = failedAt
+	}
+
+	stages := make([]*ContentStage, len(generationStages))
+	for i, name := range generationStages {
+		stage := &ContentStage{Stage: name, Status: StageStatusPending}
+		switch {
+		case i < reached:
+			stage.Status = StageStatusCompleted
+		case i == failedAt:
+			stage.Status = StageStatusFailed
+			stage.Error = video.Error
+		case i == reached && video.Status == domain.VideoStatusProcessing:
+			stage.Status = StageStatusInProgress
+		case video.Status == domain.VideoStatusCancelled || video.Status == domain.VideoStatusSkipped:
+			stage.Status = StageStatusSkipped
+		}
+		if name == StageTTS && batch.Config.VoiceID == "" && stage.Status != StageStatusFailed {
+			stage.Status = StageStatusSkipped
+		}
+		if name == StageTimeline && video.TimelineID != "" {
+			stage.Artifacts = []ContentArtifact{
+				{Label: "timeline", URL: "/api/v1/timelines/" + video.TimelineID},
+				{Label: "audioMix", URL: "/api/v1/timelines/" + video.TimelineID + "/audio-mix"},
+			}
+		}
+		stages[i] = stage
+	}
+	return stages
+}
+
+// failedGenerationStage returns the index of the stage a video failed at,
+// the first stage for failures recorded without one
+func failedGenerationStage(stage string) int {
+	for i, name := range generationStages {
+		if string(name) == stage {
+			return i
+		}
+	}
+	return 0
+}
+
+// renderStage reports the rendered video once there is one
+func renderStage(video *domain.BatchVideo) *ContentStage {
+	stage := &ContentStage{Stage: StageRender, Status: StageStatusPending}
+	if video.Result == nil || video.Result.VideoURL == "" {
+		if video.Status == domain.VideoStatusCancelled || video.Status == domain.VideoStatusSkipped {
+			stage.Status = StageStatusSkipped
+		}
+		return stage
+	}
+
+	stage.Status = StageStatusCompleted
+	stage.Artifacts = append(stage.Artifacts, ContentArtifact{Label: "video", URL: video.Result.VideoURL})
+	if video.Result.Thumbnail != "" {
+		stage.Artifacts = append(stage.Artifacts, ContentArtifact{Label: "thumbnail", URL: video.Result.Thumbnail})
+	}
+	return stage
+}
+
+// variationsStage reports the platform variations campaigns generated
+func variationsStage(campaigns []*socialdomain.Campaign) *ContentStage {
+	stage := &ContentStage{Stage: StageVariations, Status: StageStatusPending}
+	generating, failed := false, false
+	for _, campaign := range campaigns {
+		switch campaign.Status {
+		case socialdomain.CampaignStatusPending, socialdomain.CampaignStatusGenerating:
+			generating = true
+		}
+		for _, target := range campaign.Targets {
+			if target.VideoURL != "" {
+				stage.Artifacts = append(stage.Artifacts, ContentArtifact{Label: target.Platform, URL: target.VideoURL})
+			} else if target.Error != "" {
+				failed = true
+				stage.Error = target.Error
+			}
+		}
+	}
+
+	switch {
+	case generating:
+		stage.Status = StageStatusInProgress
+	case len(stage.Artifacts) > 0:
+		stage.Status = StageStatusCompleted
+		stage.Error = ""
+	case failed:
+		stage.Status = StageStatusFailed
+	}
+	return stage
+}
+
+// publishStage reports the video's posts, with a link to each published one
+func publishStage(posts []*socialdomain.ScheduledPost) *ContentStage {
+	stage := &ContentStage{Stage: StagePublish, Status: StageStatusPending}
+	var published, outstanding, failed, total int
+	for _, post := range posts {
+		for _, platform := range post.Platforms {
+			total++
+			switch platform.Status {
+			case socialdomain.PostStatusPublished:
+				published++
+				if platform.PostURL != "" {
+					stage.Artifacts = append(stage.Artifacts, ContentArtifact{Label: string(platform.Platform), URL: platform.PostURL})
+				}
+			case socialdomain.PostStatusFailed:
+				failed++
+				stage.Error = platform.ErrorMsg
+			case socialdomain.PostStatusCancelled:
+			default:
+				outstanding++
+			}
+		}
+	}
+
+	switch {
+	case total == 0:
+	case outstanding > 0:
+		stage.Status = StageStatusInProgress
+	case published > 0:
+		stage.Status = StageStatusCompleted
+	case failed > 0:
+		stage.Status = StageStatusFailed
+	default:
+		stage.Status = StageStatusSkipped
+	}
+	return stage
+}
+
+// summarizeStages returns the overall status and the stage the content is at:
+// the first failed or running stage, else the first one still pending, else
+// the last stage. Content with a stage done and one pending is in progress.
+func summarizeStages(stages []*ContentStage) (StageStatus, PipelineStage) {
+	for _, stage := range stages {
+		if stage.Status == StageStatusFailed || stage.Status == StageStatusInProgress {
+			return stage.Status, stage.Stage
+		}
+	}
+	started := false
+	for _, stage := range stages {
+		switch stage.Status {
+		case StageStatusCompleted:
+			started = true
+		case StageStatusPending:
+			if started {
+				return StageStatusInProgress, stage.Stage
+			}
+			return StageStatusPending, stage.Stage
+		}
+	}
+	return StageStatusCompleted, stages[len(stages)-1].Stage
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"renderowl-api/internal/safehttp"
+)
+
+// fingerprintFrames is how many frames, spread evenly over a video, its
+// fingerprint is made of
+const fingerprintFrames = 8
+
+// fingerprintCacheSize bounds the fingerprints kept in memory
+const fingerprintCacheSize = 1024
+
+// fingerprintTimeout bounds fingerprinting a video, which runs while a post
+// is being published or scheduled
+const fingerprintTimeout = time.Minute
+
+// ffmpeg protocols allowed when reading a stored file or a checked URL, so a
+// playlist or reference in the media can't make ffmpeg read anything else
+const (
+	fileProtocols = "file"
+	urlProtocols  = "http,https,tcp,tls"
+)
+
+// VideoFingerprintService computes perceptual fingerprints of videos with
+// ffmpeg: a difference hash of each of a few frames sampled across the
+// video. Re-encoded, resized or recompressed copies of a video get nearly
+// the same fingerprint.
+type VideoFingerprintService struct {
+	files   MediaFileSource
+	ffprobe string
+	ffmpeg  string
+
+	cacheMutex sync.RWMutex
+	cache      map[string]string
+}
+
+// NewVideoFingerprintService creates a video fingerprint service
+func NewVideoFingerprintService(files MediaFileSource, ffprobePath, ffmpegPath string) *VideoFingerprintService {
+	return &VideoFingerprintService{
+		files:   files,
+		ffprobe: ffprobePath,
+		ffmpeg:  ffmpegPath,
+		cache:   make(map[string]string),
+	}
+}
+
+// Fingerprint returns the fingerprint of one of the user's uploads, named by
+// storage key or URL, or of a video at a public http(s) URL
+func (s *VideoFingerprintService) Fingerprint(ctx context.Context, userID, videoPath string) (string, error) {
+	cacheKey := userID + "\x00" + videoPath
+	s.cacheMutex.RLock()
+	cached, ok := s.cache[cacheKey]
+	s.cacheMutex.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fingerprintTimeout)
+	defer cancel()
+
+	input, protocols, err := s.input(ctx, userID, videoPath)
+	if err != nil {
+		return "", err
+	}
+	duration, err := s.duration(ctx, input, protocols)
+	if err != nil {
+		return "", err
+	}
+
+	hashes := make([]byte, 0, fingerprintFrames*8)
+	for i := 0; i < fingerprintFrames; i++ {
+		at := duration * (float64(i) + 0.5) / fingerprintFrames
+		hash, err := s.frameHash(ctx, input, protocols, at)
+		if err != nil {
+			return "", err
+		}
+		hashes = binary.BigEndian.AppendUint64(hashes, hash)
+	}
+	fingerprint := hex.EncodeToString(hashes)
+
+	s.cacheMutex.Lock()
+	if len(s.cache) >= fingerprintCacheSize {
+		// Any entry will do; evicted videos are just fingerprinted again
+		for key := range s.cache {
+			delete(s.cache, key)
+			break
+		}
+	}
+	s.cache[cacheKey] = fingerprint
+	s.cacheMutex.Unlock()
+
+	return fingerprint, nil
+}
+
+// Similarity compares two fingerprints, from 0 for unrelated videos to 1 for
+// the same frames
+func (s *VideoFingerprintService) Similarity(a, b string) float64 {
+	hashA, errA := hex.DecodeString(a)
+	hashB, errB := hex.DecodeString(b)
+	if errA != nil || errB != nil || len(hashA) == 0 || len(hashA) != len(hashB) {
+		return 0
+	}
+	distance := 0
+	for i := range hashA {
+		distance += bits.OnesCount8(hashA[i] ^ hashB[i])
+	}
+	return 1 - float64(distance)/float64(len(hashA)*8)
+}
+
+// input returns what ffmpeg should read and the protocols it may use: the
+// file of one of the user's uploads, or a URL whose host is public. No other
+// file on the server is read.
+func (s *VideoFingerprintService) input(ctx context.Context, userID, videoPath string) (string, string, error) {
+	if key, ok := userUploadKey(s.files, userID, videoPath); ok {
+		file, err := s.files.Open(key)
+		if err != nil {
+			return "", "", err
+		}
+		defer file.Close()
+		return file.Name(), fileProtocols, nil
+	}
+	if _, ok := s.files.KeyForURL(videoPath); ok {
+		return "", "", ErrFileNotFound
+	}
+	if err := safehttp.CheckURL(ctx, videoPath); err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrMediaFetch, err)
+	}
+	return videoPath, urlProtocols, nil
+}
+
+// duration reads a video's length in seconds with ffprobe
+func (s *VideoFingerprintService) duration(ctx context.Context, input, protocols string) (float64, error) {
+	out, err := exec.CommandContext(ctx, s.ffprobe, "-v", "error", "-protocol_whitelist", protocols,
+		"-show_entries", "format=duration", "-of", "default=noprint_wrappers=1:nokey=1", input).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return 0, fmt.Errorf("%w: %s", ErrNotMedia, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return 0, fmt.Errorf("failed to run ffprobe: %w", err)
+	}
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("%w: no duration", ErrNotMedia)
+	}
+	return duration, nil
+}
+
+// frameHash returns the difference hash of the frame at a time: the frame
+// shrunk to 9x8 grey pixels, with a bit per pixel pair set when the left
+// one is brighter
+func (s *VideoFingerprintService) frameHash(ctx context.Context, input, protocols string, at float64) (uint64, error) {
+	out, err := exec.CommandContext(ctx, s.ffmpeg, "-v", "error", "-protocol_whitelist", protocols,
+		"-ss", strconv.FormatFloat(at, 'f', 3, 64), "-i", input,
+		"-frames:v", "1", "-vf", "scale=9:8:flags=area,format=gray", "-f", "rawvideo", "-").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return 0, fmt.Errorf("%w: %s", ErrNotMedia, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return 0, fmt.Errorf("failed to run ffmpeg: %w", err)
+	}
+	if len(out) < 9*8 {
+		return 0, fmt.Errorf("%w: no frame at %.1fs", ErrNotMedia, at)
+	}
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if out[y*9+x] > out[y*9+x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash, nil
+}
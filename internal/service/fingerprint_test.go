@@ -0,0 +1,45 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"renderowl-api/internal/safehttp"
+)
+
+func TestFingerprintInputOnlyReadsOwnUploadsAndPublicURLs(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir(), "https://files.example.com")
+	uploads := NewMediaUploadService(storage, nil, 1<<20, 0)
+	stored, err := uploads.Store(context.Background(), "user-a", "clip.mp4", "video/mp4", bytes.NewReader([]byte("video")))
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	fingerprints := NewVideoFingerprintService(storage, "ffprobe", "ffmpeg")
+	ctx := context.Background()
+
+	for _, ref := range []string{stored.Key, stored.URL} {
+		input, protocols, err := fingerprints.input(ctx, "user-a", ref)
+		if err != nil || input == ref || protocols != fileProtocols {
+			t.Errorf("input(user-a, %s) = %q, %q, %v, want the stored file read with %q", ref, input, protocols, err, fileProtocols)
+		}
+		if _, _, err := fingerprints.input(ctx, "user-b", ref); err == nil {
+			t.Errorf("user-b fingerprinted user-a's upload %s", ref)
+		}
+	}
+
+	for _, path := range []string{"/etc/passwd", "file:///etc/passwd", "-i", "concat:a|b"} {
+		if _, _, err := fingerprints.input(ctx, "user-a", path); !errors.Is(err, ErrMediaFetch) {
+			t.Errorf("input(%s) = %v, want ErrMediaFetch", path, err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	if _, _, err := fingerprints.input(ctx, "user-a", server.URL+"/video.mp4"); !errors.Is(err, safehttp.ErrBlockedAddress) {
+		t.Errorf("internal URL: got %v, want ErrBlockedAddress", err)
+	}
+}
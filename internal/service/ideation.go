@@ -1,31 +1,42 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
 // IdeationService provides content ideation and trending topic discovery
 type IdeationService struct {
-	httpClient  *http.Client
-	apiKeys     map[string]string
-	cache       map[string]*CacheEntry
-	cacheMutex  sync.RWMutex
-	cacheExpiry time.Duration
+	httpClient          *http.Client
+	apiKeys             map[string]string
+	cache               map[string]*CacheEntry
+	cacheMutex          sync.RWMutex
+	cacheExpiry         time.Duration
+	blocklist           []string
+	similarityThreshold float64
+	googleTrendsURL     string
+	winningContent      WinningContentCacheStore
 }
 
 // CacheEntry represents a cached API response
 type CacheEntry struct {
 	Data      interface{}
 	Timestamp time.Time
+	TTL       time.Duration // Overrides the service cache expiry when set
 }
 
 // TrendingTopic represents a trending topic from any platform
@@ -42,6 +53,8 @@ type TrendingTopic struct {
 	URL           string   `json:"url,omitempty"`
 	ThumbnailURL  string   `json:"thumbnailUrl,omitempty"`
 	PublishedAt   *time.Time `json:"publishedAt,omitempty"`
+	NSFW          bool     `json:"nsfw,omitempty"`
+	Sources       []string `json:"sources,omitempty"` // Other platforms where the same topic is trending
 }
 
 // ContentSuggestion represents an AI-generated content suggestion
@@ -51,6 +64,8 @@ type ContentSuggestion struct {
 	Description   string   `json:"description"`
 	Niche         string   `json:"niche"`
 	Format        string   `json:"format"` // short, long, series
+	FormatConfidence float64 `json:"formatConfidence"` // 0-1; 0 when Format is the requested one
+	FormatReason  string   `json:"formatReason,omitempty"`
 	EstimatedViews int     `json:"estimatedViews"`
 	Difficulty    string   `json:"difficulty"` // easy, medium, hard
 	TimeToCreate  int      `json:"timeToCreate"` // minutes
@@ -124,10 +139,22 @@ type CalendarVideo struct {
 
 // GetTrendingTopicsRequest represents a request for trending topics
 type GetTrendingTopicsRequest struct {
-	Platforms  []string `json:"platforms,omitempty"`  // youtube, tiktok, twitter
+	Platforms  []string `json:"platforms,omitempty"`  // youtube, tiktok, twitter, reddit, google
 	Categories []string `json:"categories,omitempty"` // tech, gaming, education, etc.
 	Limit      int      `json:"limit,omitempty"`
-	Region     string   `json:"region,omitempty"`     // US, EU, GLOBAL
+	Region     string   `json:"region,omitempty"`     // US, EU, GLOBAL; defaults to the request locale's region
+	SafeMode   *bool    `json:"safeMode,omitempty"`   // Drops NSFW and blocklisted topics; defaults to true
+}
+
+// defaultBlocklist contains the keywords filtered out of trending topics in safe mode
+var defaultBlocklist = []string{
+	"nsfw",
+	"porn",
+	"onlyfans",
+	"nude",
+	"gore",
+	"fuck",
+	"shit",
 }
 
 // GetContentSuggestionsRequest represents a request for content suggestions
@@ -159,9 +186,12 @@ func NewIdeationService() *IdeationService {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		apiKeys:     make(map[string]string),
-		cache:       make(map[string]*CacheEntry),
-		cacheExpiry: 15 * time.Minute,
+		apiKeys:             make(map[string]string),
+		cache:               make(map[string]*CacheEntry),
+		cacheExpiry:         15 * time.Minute,
+		blocklist:           defaultBlocklist,
+		similarityThreshold: 0.6,
+		googleTrendsURL:     getEnv("GOOGLE_TRENDS_URL", "https://trends.google.com/trends/api/dailytrends"),
 	}
 }
 
@@ -170,11 +200,30 @@ func (s *IdeationService) SetAPIKey(platform, key string) {
 	s.apiKeys[platform] = key
 }
 
+// SetBlocklist replaces the keywords used to filter trending topics in safe mode
+func (s *IdeationService) SetBlocklist(keywords []string) {
+	blocklist := make([]string, 0, len(keywords))
+	for _, k := range keywords {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			blocklist = append(blocklist, k)
+		}
+	}
+	s.blocklist = blocklist
+}
+
+// SetSimilarityThreshold sets the title token overlap (0-1) above which topics are treated as duplicates
+func (s *IdeationService) SetSimilarityThreshold(threshold float64) {
+	s.similarityThreshold = threshold
+}
+
 // GetTrendingTopics retrieves trending topics from multiple platforms
 func (s *IdeationService) GetTrendingTopics(ctx context.Context, req *GetTrendingTopicsRequest) ([]*TrendingTopic, error) {
 	if req.Limit == 0 {
 		req.Limit = 20
 	}
+	if req.Region == "" {
+		req.Region = requestLocale(ctx).Region
+	}
 
 	var wg sync.WaitGroup
 	topicChan := make(chan []*TrendingTopic, len(req.Platforms))
@@ -213,11 +262,19 @@ func (s *IdeationService) GetTrendingTopics(ctx context.Context, req *GetTrendin
 		errors = append(errors, err)
 	}
 
+	// Drop NSFW and off-brand topics before the limit so it isn't eaten by filtered entries
+	if req.SafeMode == nil || *req.SafeMode {
+		allTopics = s.filterUnsafeTopics(allTopics)
+	}
+
 	// Sort by score
 	sort.Slice(allTopics, func(i, j int) bool {
 		return allTopics[i].Score > allTopics[j].Score
 	})
 
+	// Collapse near-duplicates so one story trending everywhere doesn't crowd out the rest
+	allTopics = s.dedupeTopics(allTopics)
+
 	// Apply limit
 	if len(allTopics) > req.Limit {
 		allTopics = allTopics[:req.Limit]
@@ -226,6 +283,137 @@ func (s *IdeationService) GetTrendingTopics(ctx context.Context, req *GetTrendin
 	return allTopics, nil
 }
 
+// filterUnsafeTopics removes topics flagged as NSFW or matching the blocklist
+func (s *IdeationService) filterUnsafeTopics(topics []*TrendingTopic) []*TrendingTopic {
+	filtered := make([]*TrendingTopic, 0, len(topics))
+	for _, topic := range topics {
+		if topic.NSFW || s.isBlocked(topic) {
+			continue
+		}
+		filtered = append(filtered, topic)
+	}
+	return filtered
+}
+
+// isBlocked reports whether a topic's title, description or category contains a blocklisted keyword
+func (s *IdeationService) isBlocked(topic *TrendingTopic) bool {
+	text := strings.ToLower(topic.Title + " " + topic.Description + " " + topic.Category)
+	for _, keyword := range s.blocklist {
+		if containsWord(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWord reports whether keyword appears in text as whole words, not
+// inside a longer word: "gore" matches "gore warning" but not "Gorey"
+func containsWord(text, keyword string) bool {
+	if keyword == "" {
+		return false
+	}
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], keyword)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(keyword)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// dedupeTopics clusters topics with similar titles, keeping the highest-scoring
+// one and recording the other platforms as sources. Topics must be sorted by score.
+func (s *IdeationService) dedupeTopics(topics []*TrendingTopic) []*TrendingTopic {
+	if s.similarityThreshold <= 0 || s.similarityThreshold > 1 {
+		return topics
+	}
+
+	type cluster struct {
+		topic  *TrendingTopic
+		tokens map[string]bool
+	}
+
+	var clusters []*cluster
+	for _, topic := range topics {
+		tokens := titleTokens(topic.Title)
+
+		var match *cluster
+		for _, c := range clusters {
+			if tokenSimilarity(tokens, c.tokens) >= s.similarityThreshold {
+				match = c
+				break
+			}
+		}
+
+		if match == nil {
+			// Copy so attaching sources doesn't mutate cached platform results
+			representative := *topic
+			representative.Sources = nil
+			clusters = append(clusters, &cluster{topic: &representative, tokens: tokens})
+			continue
+		}
+
+		if topic.Platform != match.topic.Platform && !containsPlatform(match.topic.Sources, topic.Platform) {
+			match.topic.Sources = append(match.topic.Sources, topic.Platform)
+		}
+	}
+
+	deduped := make([]*TrendingTopic, len(clusters))
+	for i, c := range clusters {
+		deduped[i] = c.topic
+	}
+	return deduped
+}
+
+// titleTokens normalizes a title into a set of lowercase alphanumeric tokens
+func titleTokens(title string) map[string]bool {
+	tokens := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if len(w) > 1 {
+			tokens[w] = true
+		}
+	}
+	return tokens
+}
+
+// tokenSimilarity returns the Jaccard similarity of two token sets
+func tokenSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if b[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func containsPlatform(platforms []string, platform string) bool {
+	for _, p := range platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
 // fetchPlatformTrends fetches trends from a specific platform
 func (s *IdeationService) fetchPlatformTrends(ctx context.Context, platform string, req *GetTrendingTopicsRequest) ([]*TrendingTopic, error) {
 	switch strings.ToLower(platform) {
@@ -237,6 +425,8 @@ func (s *IdeationService) fetchPlatformTrends(ctx context.Context, platform stri
 		return s.fetchTwitterTrends(ctx, req)
 	case "reddit":
 		return s.fetchRedditTrends(ctx, req)
+	case "google":
+		return s.fetchGoogleTrends(ctx, req)
 	default:
 		return s.generateSimulatedTrends(platform, req)
 	}
@@ -249,16 +439,14 @@ func (s *IdeationService) fetchYouTubeTrends(ctx context.Context, req *GetTrendi
 		return s.generateSimulatedTrends("youtube", req)
 	}
 
-	cacheKey := fmt.Sprintf("youtube_trends_%s", req.Region)
+	regionCode := countryCode(ctx, req.Region)
+	language := requestLocale(ctx).Language
+	// Category names are localized, so trends are cached per language too
+	cacheKey := fmt.Sprintf("youtube_trends_%s_%s", regionCode, language)
 	if cached := s.getCache(cacheKey); cached != nil {
 		return cached.([]*TrendingTopic), nil
 	}
 
-	regionCode := req.Region
-	if regionCode == "" || regionCode == "GLOBAL" {
-		regionCode = "US"
-	}
-
 	u := fmt.Sprintf(
 		"https://www.googleapis.com/youtube/v3/videos?part=snippet,statistics&chart=mostPopular&regionCode=%s&maxResults=50&key=%s",
 		regionCode, apiKey,
@@ -306,6 +494,7 @@ func (s *IdeationService) fetchYouTubeTrends(ctx context.Context, req *GetTrendi
 		return s.generateSimulatedTrends("youtube", req)
 	}
 
+	categories := s.youTubeCategories(ctx, apiKey, regionCode, language)
 	var topics []*TrendingTopic
 	for _, item := range result.Items {
 		publishedAt, _ := time.Parse(time.RFC3339, item.Snippet.PublishedAt)
@@ -315,7 +504,7 @@ func (s *IdeationService) fetchYouTubeTrends(ctx context.Context, req *GetTrendi
 			Title:        item.Snippet.Title,
 			Description:  truncateString(item.Snippet.Description, 200),
 			Platform:     "youtube",
-			Category:     getCategoryName(item.Snippet.CategoryID),
+			Category:     getCategoryName(categories, item.Snippet.CategoryID),
 			RelatedTopics: item.Snippet.Tags[:min(5, len(item.Snippet.Tags))],
 			URL:          fmt.Sprintf("https://youtube.com/watch?v=%s", item.ID),
 			ThumbnailURL: item.Snippet.Thumbnails.High.URL,
@@ -335,6 +524,29 @@ func (s *IdeationService) fetchTikTokTrends(ctx context.Context, req *GetTrendin
 	return s.generateSimulatedTrends("tiktok", req)
 }
 
+// twitterWOEIDs maps request regions to Yahoo WOEIDs used by the Twitter trends endpoint
+var twitterWOEIDs = map[string]int{
+	"GLOBAL": 1,
+	"EU":     1, // No EU-wide location, fall back to worldwide
+	"US":     23424977,
+	"GB":     23424975,
+	"UK":     23424975,
+	"CA":     23424775,
+	"AU":     23424748,
+	"IN":     23424848,
+	"DE":     23424829,
+	"FR":     23424819,
+	"ES":     23424950,
+	"IT":     23424853,
+	"NL":     23424909,
+	"BR":     23424768,
+	"MX":     23424900,
+	"JP":     23424856,
+}
+
+// twitterTrendsTTL is kept short because X trends turn over much faster than other platforms
+const twitterTrendsTTL = 5 * time.Minute
+
 // fetchTwitterTrends fetches trending topics from Twitter/X
 func (s *IdeationService) fetchTwitterTrends(ctx context.Context, req *GetTrendingTopicsRequest) ([]*TrendingTopic, error) {
 	apiKey := s.apiKeys["twitter"]
@@ -342,9 +554,69 @@ func (s *IdeationService) fetchTwitterTrends(ctx context.Context, req *GetTrendi
 		return s.generateSimulatedTrends("twitter", req)
 	}
 
-	// Would use Twitter API v2 here
-	// For now, return simulated data
-	return s.generateSimulatedTrends("twitter", req)
+	woeid, ok := twitterWOEIDs[strings.ToUpper(req.Region)]
+	if !ok {
+		woeid = twitterWOEIDs["GLOBAL"]
+	}
+
+	cacheKey := fmt.Sprintf("twitter_trends_%d", woeid)
+	if cached := s.getCache(cacheKey); cached != nil {
+		return cached.([]*TrendingTopic), nil
+	}
+
+	u := fmt.Sprintf("https://api.twitter.com/2/trends/by/woeid/%d?max_trends=50", woeid)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return s.generateSimulatedTrends("twitter", req)
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return s.generateSimulatedTrends("twitter", req)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.generateSimulatedTrends("twitter", req)
+	}
+
+	var result struct {
+		Data []struct {
+			TrendName  string `json:"trend_name"`
+			TweetCount int    `json:"tweet_count"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return s.generateSimulatedTrends("twitter", req)
+	}
+
+	if len(result.Data) == 0 {
+		return s.generateSimulatedTrends("twitter", req)
+	}
+
+	var topics []*TrendingTopic
+	for _, trend := range result.Data {
+		topic := &TrendingTopic{
+			ID:          uuid.New().String(),
+			Title:       trend.TrendName,
+			Description: fmt.Sprintf("Trending on X with %d posts", trend.TweetCount),
+			Platform:    "twitter",
+			Category:    "trending",
+			Score:       float64(trend.TweetCount) / 1000000,
+			Volume:      trend.TweetCount,
+			URL:         "https://x.com/search?q=" + url.QueryEscape(trend.TrendName),
+		}
+		if strings.HasPrefix(trend.TrendName, "#") {
+			topic.Category = "hashtag"
+		}
+		topics = append(topics, topic)
+	}
+
+	s.setCacheWithTTL(cacheKey, topics, twitterTrendsTTL)
+	return topics, nil
 }
 
 // fetchRedditTrends fetches trending topics from Reddit
@@ -379,6 +651,7 @@ func (s *IdeationService) fetchRedditTrends(ctx context.Context, req *GetTrendin
 					Permalink string  `json:"permalink"`
 					Ups       int     `json:"ups"`
 					NumComments int   `json:"num_comments"`
+					Over18    bool    `json:"over_18"`
 				} `json:"data"`
 			} `json:"children"`
 		} `json:"data"`
@@ -399,6 +672,7 @@ func (s *IdeationService) fetchRedditTrends(ctx context.Context, req *GetTrendin
 			Score:       child.Data.Score,
 			Volume:      child.Data.Ups + child.Data.NumComments,
 			URL:         fmt.Sprintf("https://reddit.com%s", child.Data.Permalink),
+			NSFW:        child.Data.Over18,
 		}
 		topics = append(topics, topic)
 	}
@@ -407,6 +681,138 @@ func (s *IdeationService) fetchRedditTrends(ctx context.Context, req *GetTrendin
 	return topics, nil
 }
 
+// fetchGoogleTrends fetches daily trending searches from Google Trends
+func (s *IdeationService) fetchGoogleTrends(ctx context.Context, req *GetTrendingTopicsRequest) ([]*TrendingTopic, error) {
+	geo := countryCode(ctx, req.Region)
+
+	cacheKey := fmt.Sprintf("google_trends_%s", geo)
+	if cached := s.getCache(cacheKey); cached != nil {
+		return cached.([]*TrendingTopic), nil
+	}
+
+	u := fmt.Sprintf("%s?hl=en-US&tz=0&geo=%s&ns=15", s.googleTrendsURL, url.QueryEscape(geo))
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return s.generateSimulatedTrends("google", req)
+	}
+
+	httpReq.Header.Set("User-Agent", "Renderowl/2.0")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return s.generateSimulatedTrends("google", req)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.generateSimulatedTrends("google", req)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return s.generateSimulatedTrends("google", req)
+	}
+
+	// Google prefixes the JSON with an anti-XSSI guard line
+	if idx := bytes.IndexByte(body, '{'); idx > 0 {
+		body = body[idx:]
+	}
+
+	var result struct {
+		Default struct {
+			TrendingSearchesDays []struct {
+				Date             string `json:"date"`
+				TrendingSearches []struct {
+					Title struct {
+						Query string `json:"query"`
+					} `json:"title"`
+					FormattedTraffic string `json:"formattedTraffic"`
+					RelatedQueries   []struct {
+						Query string `json:"query"`
+					} `json:"relatedQueries"`
+					Image struct {
+						ImageURL string `json:"imageUrl"`
+					} `json:"image"`
+					Articles []struct {
+						Title   string `json:"title"`
+						URL     string `json:"url"`
+						Snippet string `json:"snippet"`
+					} `json:"articles"`
+				} `json:"trendingSearches"`
+			} `json:"trendingSearchesDays"`
+		} `json:"default"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return s.generateSimulatedTrends("google", req)
+	}
+
+	var topics []*TrendingTopic
+	for _, day := range result.Default.TrendingSearchesDays {
+		publishedAt, _ := time.Parse("20060102", day.Date)
+		hours := time.Since(publishedAt).Hours()
+		if hours < 1 {
+			hours = 1
+		}
+
+		for _, search := range day.TrendingSearches {
+			volume := parseTrafficVolume(search.FormattedTraffic)
+
+			topic := &TrendingTopic{
+				ID:           uuid.New().String(),
+				Title:        search.Title.Query,
+				Description:  fmt.Sprintf("%s searches on Google", search.FormattedTraffic),
+				Platform:     "google",
+				Category:     "search",
+				Score:        float64(volume) / 1000000,
+				Volume:       volume,
+				Velocity:     float64(volume) / hours / 1000, // thousand searches per hour
+				ThumbnailURL: search.Image.ImageURL,
+				PublishedAt:  &publishedAt,
+			}
+			for _, related := range search.RelatedQueries {
+				topic.RelatedTopics = append(topic.RelatedTopics, related.Query)
+			}
+			if len(search.Articles) > 0 {
+				topic.Description = truncateString(search.Articles[0].Title, 200)
+				topic.URL = search.Articles[0].URL
+			}
+			topics = append(topics, topic)
+		}
+	}
+
+	if len(topics) == 0 {
+		return s.generateSimulatedTrends("google", req)
+	}
+
+	s.setCache(cacheKey, topics)
+	return topics, nil
+}
+
+// parseTrafficVolume converts Google Trends traffic strings like "200K+" or "2,000+" to a number
+func parseTrafficVolume(traffic string) int {
+	traffic = strings.TrimSuffix(strings.ReplaceAll(strings.TrimSpace(traffic), ",", ""), "+")
+	if traffic == "" {
+		return 0
+	}
+
+	multiplier := 1.0
+	switch strings.ToUpper(traffic[len(traffic)-1:]) {
+	case "K":
+		multiplier = 1000
+		traffic = traffic[:len(traffic)-1]
+	case "M":
+		multiplier = 1000000
+		traffic = traffic[:len(traffic)-1]
+	}
+
+	value, err := strconv.ParseFloat(traffic, 64)
+	if err != nil {
+		return 0
+	}
+	return int(value * multiplier)
+}
+
 // generateSimulatedTrends generates simulated trending topics for development/testing
 func (s *IdeationService) generateSimulatedTrends(platform string, req *GetTrendingTopicsRequest) ([]*TrendingTopic, error) {
 	templates := map[string][]struct {
@@ -446,6 +852,13 @@ func (s *IdeationService) generateSimulatedTrends(platform string, req *GetTrend
 			{"What is something [Question]?", "discussion", 52000},
 			{"My [Achievement] after [Timeframe]", "progress", 67000},
 		},
+		"google": {
+			{"how to [Skill]", "education", 500000},
+			{"[Product] release date", "tech", 200000},
+			{"[Event] live stream", "entertainment", 1000000},
+			{"best [Product] 2025", "shopping", 100000},
+			{"[Celebrity] news", "news", 200000},
+		},
 	}
 
 	var topics []*TrendingTopic
@@ -472,8 +885,9 @@ func (s *IdeationService) generateSimulatedTrends(platform string, req *GetTrend
 	return topics, nil
 }
 
-// GetContentSuggestions generates AI-powered content suggestions
-func (s *IdeationService) GetContentSuggestions(ctx context.Context, req *GetContentSuggestionsRequest) ([]*ContentSuggestion, error) {
+// GetContentSuggestions generates AI-powered content suggestions, each with
+// the format the user's best videos suggest it should take
+func (s *IdeationService) GetContentSuggestions(ctx context.Context, userID string, req *GetContentSuggestionsRequest) ([]*ContentSuggestion, error) {
 	if req.Count == 0 {
 		req.Count = 10
 	}
@@ -586,25 +1000,34 @@ func (s *IdeationService) GetContentSuggestions(ctx context.Context, req *GetCon
 		templates = nicheTemplates["tech"]
 	}
 
+	history := s.userFormatHistory(ctx, userID)
+
 	var suggestions []*ContentSuggestion
 	for i, tmpl := range templates {
 		if i >= req.Count {
 			break
 		}
 
+		format, confidence, reason := recommendFormat(history, req.Niche, req.Format, suggestionShape{
+			outline:      tmpl.outline,
+			timeToCreate: tmpl.timeToCreate,
+		})
+
 		suggestion := &ContentSuggestion{
-			ID:             uuid.New().String(),
-			Title:          tmpl.title,
-			Description:    tmpl.description,
-			Niche:          req.Niche,
-			Format:         req.Format,
-			EstimatedViews: calculateEstimatedViews(tmpl.difficulty, req.Niche),
-			Difficulty:     tmpl.difficulty,
-			TimeToCreate:   tmpl.timeToCreate,
-			Hook:           tmpl.hook,
-			Outline:        tmpl.outline,
-			Tags:           tmpl.tags,
-			TrendingScore:  calculateTrendingScore(),
+			ID:               uuid.New().String(),
+			Title:            tmpl.title,
+			Description:      tmpl.description,
+			Niche:            req.Niche,
+			Format:           format,
+			FormatConfidence: confidence,
+			FormatReason:     reason,
+			EstimatedViews:   calculateEstimatedViews(tmpl.difficulty, req.Niche),
+			Difficulty:       tmpl.difficulty,
+			TimeToCreate:     tmpl.timeToCreate,
+			Hook:             tmpl.hook,
+			Outline:          tmpl.outline,
+			Tags:             tmpl.tags,
+			TrendingScore:    calculateTrendingScore(),
 		}
 		suggestions = append(suggestions, suggestion)
 	}
@@ -614,9 +1037,11 @@ func (s *IdeationService) GetContentSuggestions(ctx context.Context, req *GetCon
 
 // AnalyzeCompetitor analyzes a competitor's channel
 func (s *IdeationService) AnalyzeCompetitor(ctx context.Context, req *CompetitorAnalysisRequest) (*CompetitorAnalysis, error) {
-	// Parse channel URL to extract ID
-	channelID := extractChannelID(req.ChannelURL, req.Platform)
-	
+	channelID, err := s.resolveChannelID(ctx, req.ChannelURL, req.Platform)
+	if err != nil {
+		return nil, err
+	}
+
 	analysis := &CompetitorAnalysis{
 		ChannelID:   channelID,
 		Platform:    req.Platform,
@@ -694,7 +1119,7 @@ func (s *IdeationService) generateSimulatedAnalysis(analysis *CompetitorAnalysis
 }
 
 // GenerateContentCalendar generates a 30-day content calendar
-func (s *IdeationService) GenerateContentCalendar(ctx context.Context, req *GenerateCalendarRequest) (*ContentCalendar, error) {
+func (s *IdeationService) GenerateContentCalendar(ctx context.Context, userID string, req *GenerateCalendarRequest) (*ContentCalendar, error) {
 	if req.StartDate.IsZero() {
 		req.StartDate = time.Now()
 	}
@@ -717,7 +1142,7 @@ func (s *IdeationService) GenerateContentCalendar(ctx context.Context, req *Gene
 		Format: req.Format,
 		Count:  30,
 	}
-	suggestions, err := s.GetContentSuggestions(ctx, suggestionsReq)
+	suggestions, err := s.GetContentSuggestions(ctx, userID, suggestionsReq)
 	if err != nil {
 		return nil, err
 	}
@@ -768,7 +1193,11 @@ func (s *IdeationService) getCache(key string) interface{} {
 		return nil
 	}
 
-	if time.Since(entry.Timestamp) > s.cacheExpiry {
+	expiry := s.cacheExpiry
+	if entry.TTL > 0 {
+		expiry = entry.TTL
+	}
+	if time.Since(entry.Timestamp) > expiry {
 		return nil
 	}
 
@@ -785,32 +1214,49 @@ func (s *IdeationService) setCache(key string, data interface{}) {
 	}
 }
 
+func (s *IdeationService) setCacheWithTTL(key string, data interface{}, ttl time.Duration) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	s.cache[key] = &CacheEntry{
+		Data:      data,
+		Timestamp: time.Now(),
+		TTL:       ttl,
+	}
+}
+
+// difficultyBaseViews is the baseline view estimate for each difficulty;
+// other difficulties get defaultBaseViews
+var difficultyBaseViews = map[string]int{
+	"easy":   50000,
+	"medium": 150000,
+	"hard":   300000,
+}
+
+const defaultBaseViews = 100000
+
+// nicheViewMultipliers scale the baseline for niches with bigger audiences
+var nicheViewMultipliers = map[string]float64{
+	"tech":      1.5,
+	"gaming":    2.0,
+	"finance":   1.3,
+	"fitness":   1.4,
+	"education": 1.2,
+	"cooking":   1.1,
+	"travel":    1.0,
+}
+
 func calculateEstimatedViews(difficulty, niche string) int {
-	base := 100000
-	switch difficulty {
-	case "easy":
-		base = 50000
-	case "medium":
-		base = 150000
-	case "hard":
-		base = 300000
-	}
-	
+	base, ok := difficultyBaseViews[difficulty]
+	if !ok {
+		base = defaultBaseViews
+	}
+
 	// Niche multiplier
-	multipliers := map[string]float64{
-		"tech":       1.5,
-		"gaming":     2.0,
-		"finance":    1.3,
-		"fitness":    1.4,
-		"education":  1.2,
-		"cooking":    1.1,
-		"travel":     1.0,
-	}
-	
-	if m, ok := multipliers[niche]; ok {
+	if m, ok := nicheViewMultipliers[niche]; ok {
 		base = int(float64(base) * m)
 	}
-	
+
 	return base
 }
 
@@ -839,16 +1285,6 @@ func calculatePostDays(frequency int) []int {
 	}
 }
 
-func extractChannelID(url, platform string) string {
-	// Extract channel ID from URL
-	// This is a simplified version
-	parts := strings.Split(url, "/")
-	if len(parts) > 0 {
-		return parts[len(parts)-1]
-	}
-	return ""
-}
-
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -856,29 +1292,6 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-func getCategoryName(categoryID string) string {
-	categories := map[string]string{
-		"1":  "Film & Animation",
-		"2":  "Autos & Vehicles",
-		"10": "Music",
-		"15": "Pets & Animals",
-		"17": "Sports",
-		"19": "Travel & Events",
-		"20": "Gaming",
-		"22": "People & Blogs",
-		"23": "Comedy",
-		"24": "Entertainment",
-		"25": "News & Politics",
-		"26": "Howto & Style",
-		"27": "Education",
-		"28": "Science & Technology",
-	}
-	if name, ok := categories[categoryID]; ok {
-		return name
-	}
-	return "Unknown"
-}
-
 func contains(slice []int, item int) bool {
 	for _, s := range slice {
 		if s == item {
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	socialdomain "renderowl-api/internal/domain/social"
+)
+
+// youTubeCategoriesTTL is how long a region's category names are cached;
+// YouTube rarely changes them
+const youTubeCategoriesTTL = 24 * time.Hour
+
+// youTubeCategories returns the names of YouTube's video categories in a
+// region, in the given language, from the videoCategories API. Nil when the
+// API can't be reached, so callers fall back to the static English names.
+func (s *IdeationService) youTubeCategories(ctx context.Context, apiKey, regionCode, language string) map[string]string {
+	cacheKey := fmt.Sprintf("youtube_categories_%s_%s", regionCode, language)
+	if cached := s.getCache(cacheKey); cached != nil {
+		return cached.(map[string]string)
+	}
+
+	params := url.Values{
+		"part":       {"snippet"},
+		"regionCode": {regionCode},
+		"hl":         {language},
+		"key":        {apiKey},
+	}
+	u := "https://www.googleapis.com/youtube/v3/videoCategories?" + params.Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	var result struct {
+		Items []struct {
+			ID      string `json:"id"`
+			Snippet struct {
+				Title string `json:"title"`
+			} `json:"snippet"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || len(result.Items) == 0 {
+		return nil
+	}
+
+	categories := make(map[string]string, len(result.Items))
+	for _, item := range result.Items {
+		if item.Snippet.Title != "" {
+			categories[item.ID] = item.Snippet.Title
+		}
+	}
+	s.setCacheWithTTL(cacheKey, categories, youTubeCategoriesTTL)
+	return categories
+}
+
+// getCategoryName names a YouTube category, preferring the localized names
+// fetched for the region over the static English ones
+func getCategoryName(categories map[string]string, categoryID string) string {
+	if name, ok := categories[categoryID]; ok {
+		return name
+	}
+	if name, ok := socialdomain.YouTubeCategories[categoryID]; ok {
+		return name
+	}
+	return "Unknown"
+}
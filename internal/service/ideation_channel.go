@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Channel lookup errors
+var (
+	ErrInvalidChannel  = errors.New("unrecognized channel URL or handle")
+	ErrChannelNotFound = errors.New("channel not found")
+)
+
+// channelRefKind is how a channel was referred to
+type channelRefKind string
+
+const (
+	channelRefID       channelRefKind = "id"       // /channel/UC...
+	channelRefHandle   channelRefKind = "handle"   // /@handle or a bare @handle
+	channelRefUsername channelRefKind = "username" // legacy /user/Name
+	channelRefCustom   channelRefKind = "custom"   // /c/Name or youtube.com/Name
+)
+
+// channelRef is a parsed, not yet resolved, reference to a channel
+type channelRef struct {
+	Kind  channelRefKind
+	Value string
+}
+
+// youtubeReservedPaths are top-level YouTube paths that are not channel names
+var youtubeReservedPaths = map[string]bool{
+	"watch": true, "results": true, "feed": true, "playlist": true,
+	"shorts": true, "embed": true, "live": true, "hashtag": true,
+}
+
+// parseYouTubeChannel recognizes /channel/ID, /c/Name, /user/Name, /@handle
+// and youtube.com/Name URLs, as well as bare @handles and channel IDs
+func parseYouTubeChannel(input string) (channelRef, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return channelRef{}, ErrInvalidChannel
+	}
+	if strings.HasPrefix(input, "@") {
+		return channelRef{Kind: channelRefHandle, Value: input}, nil
+	}
+	if isYouTubeChannelID(input) {
+		return channelRef{Kind: channelRefID, Value: input}, nil
+	}
+
+	segments, ok := urlSegments(input, "youtube.com")
+	if !ok || len(segments) == 0 {
+		return channelRef{}, fmt.Errorf("%w: %q", ErrInvalidChannel, input)
+	}
+
+	first := segments[0]
+	switch {
+	case strings.HasPrefix(first, "@") && len(first) > 1:
+		return channelRef{Kind: channelRefHandle, Value: first}, nil
+	case first == "channel" && len(segments) > 1 && isYouTubeChannelID(segments[1]):
+		return channelRef{Kind: channelRefID, Value: segments[1]}, nil
+	case first == "c" && len(segments) > 1:
+		return channelRef{Kind: channelRefCustom, Value: segments[1]}, nil
+	case first == "user" && len(segments) > 1:
+		return channelRef{Kind: channelRefUsername, Value: segments[1]}, nil
+	case len(segments) == 1 && !youtubeReservedPaths[first]:
+		return channelRef{Kind: channelRefCustom, Value: first}, nil
+	}
+
+	return channelRef{}, fmt.Errorf("%w: %q", ErrInvalidChannel, input)
+}
+
+// isYouTubeChannelID reports whether s looks like a UC-prefixed channel ID
+func isYouTubeChannelID(s string) bool {
+	if len(s) != 24 || !strings.HasPrefix(s, "UC") {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// urlSegments returns the non-empty path segments of a URL on the given
+// domain, adding a scheme when the input omits one
+func urlSegments(input, domain string) ([]string, bool) {
+	if !strings.Contains(input, "://") {
+		input = "https://" + input
+	}
+	u, err := url.Parse(input)
+	if err != nil {
+		return nil, false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host != domain && !strings.HasSuffix(host, "."+domain) {
+		return nil, false
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments, true
+}
+
+// parseTikTokChannel recognizes tiktok.com/@user URLs and bare @users
+func parseTikTokChannel(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "@") && len(input) > 1 {
+		return input, nil
+	}
+
+	segments, ok := urlSegments(input, "tiktok.com")
+	if ok && len(segments) > 0 && strings.HasPrefix(segments[0], "@") && len(segments[0]) > 1 {
+		return segments[0], nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidChannel, input)
+}
+
+// resolveChannelID turns a channel URL or handle into the ID analysis runs on.
+// YouTube handles and custom names are looked up through the Data API when a
+// key is configured; without one the parsed name is used as is.
+func (s *IdeationService) resolveChannelID(ctx context.Context, input, platform string) (string, error) {
+	switch platform {
+	case "youtube":
+		ref, err := parseYouTubeChannel(input)
+		if err != nil {
+			return "", err
+		}
+		if ref.Kind == channelRefID || s.apiKeys["youtube"] == "" {
+			return ref.Value, nil
+		}
+		return s.lookupYouTubeChannel(ctx, ref)
+	case "tiktok":
+		return parseTikTokChannel(input)
+	default:
+		// Other platforms only need the last path segment
+		trimmed := strings.Trim(strings.TrimSpace(input), "/")
+		if trimmed == "" {
+			return "", fmt.Errorf("%w: %q", ErrInvalidChannel, input)
+		}
+		return trimmed[strings.LastIndex(trimmed, "/")+1:], nil
+	}
+}
+
+// lookupYouTubeChannel resolves a handle, username or custom name to a channel ID
+func (s *IdeationService) lookupYouTubeChannel(ctx context.Context, ref channelRef) (string, error) {
+	cacheKey := fmt.Sprintf("youtube_channel_%s_%s", ref.Kind, strings.ToLower(ref.Value))
+	if cached := s.getCache(cacheKey); cached != nil {
+		return cached.(string), nil
+	}
+
+	params := url.Values{"key": {s.apiKeys["youtube"]}}
+	endpoint := "channels"
+	switch ref.Kind {
+	case channelRefHandle:
+		params.Set("part", "id")
+		params.Set("forHandle", ref.Value)
+	case channelRefUsername:
+		params.Set("part", "id")
+		params.Set("forUsername", ref.Value)
+	default:
+		// Custom URLs have no lookup endpoint, so take the best search match
+		endpoint = "search"
+		params.Set("part", "snippet")
+		params.Set("type", "channel")
+		params.Set("maxResults", "1")
+		params.Set("q", ref.Value)
+	}
+
+	u := "https://www.googleapis.com/youtube/v3/" + endpoint + "?" + params.Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up channel: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to look up channel: YouTube API status %d", resp.StatusCode)
+	}
+
+	// channels returns the ID as a string, search as an object
+	var result struct {
+		Items []struct {
+			ID json.RawMessage `json:"id"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode channel lookup: %w", err)
+	}
+	if len(result.Items) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrChannelNotFound, ref.Value)
+	}
+
+	var channelID string
+	if err := json.Unmarshal(result.Items[0].ID, &channelID); err != nil {
+		var searchID struct {
+			ChannelID string `json:"channelId"`
+		}
+		if err := json.Unmarshal(result.Items[0].ID, &searchID); err != nil || searchID.ChannelID == "" {
+			return "", fmt.Errorf("%w: %s", ErrChannelNotFound, ref.Value)
+		}
+		channelID = searchID.ChannelID
+	}
+
+	s.setCacheWithTTL(cacheKey, channelID, 24*time.Hour)
+	return channelID, nil
+}
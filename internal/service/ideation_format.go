@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+)
+
+// minFormatHistory is how many of a user's top videos a format recommendation
+// needs; with fewer the requested format is kept
+const minFormatHistory = 5
+
+// formatForBucket maps the winning content duration buckets onto formats
+var formatForBucket = map[string]string{
+	"short_30s":       "short",
+	"medium_3min":     "short",
+	"long_10min":      "long",
+	"extended_10min+": "long",
+}
+
+// nicheFormatLean nudges niches whose audiences favour one format; positive
+// leans short, negative leans long
+var nicheFormatLean = map[string]float64{
+	"tech":      -0.1,
+	"gaming":    -0.15,
+	"education": -0.15,
+	"finance":   -0.1,
+	"fitness":   0.15,
+	"cooking":   0.15,
+	"travel":    0.05,
+}
+
+// suggestionShape is what a suggestion template says about its own format
+type suggestionShape struct {
+	outline      []string
+	timeToCreate int
+}
+
+// formatHistory is how a user's short and long top videos have performed
+type formatHistory struct {
+	videos     int
+	shortViews float64 // average views
+	longViews  float64
+}
+
+// shortLean compares the formats' views from -1 (long wins) to 1 (short wins)
+func (h *formatHistory) shortLean() float64 {
+	return (h.shortViews - h.longViews) / (h.shortViews + h.longViews)
+}
+
+// reason explains the history's lean in words
+func (h *formatHistory) reason() string {
+	winner, loser := "short", "long"
+	best, other := h.shortViews, h.longViews
+	if h.longViews > h.shortViews {
+		winner, loser = loser, winner
+		best, other = other, best
+	}
+	if other == 0 {
+		return fmt.Sprintf("your top videos are all %s", winner)
+	}
+	return fmt.Sprintf("your %s videos get %.0f%% more views than your %s ones", winner, (best/other-1)*100, loser)
+}
+
+// SetWinningContent lets content suggestions recommend formats from the
+// performance of a user's best videos, read from the optimizer's cached
+// analysis
+func (s *IdeationService) SetWinningContent(store WinningContentCacheStore) {
+	s.winningContent = store
+}
+
+// userFormatHistory returns how the user's formats have performed, or nil
+// when there is too little history to go on
+func (s *IdeationService) userFormatHistory(ctx context.Context, userID string) *formatHistory {
+	if s.winningContent == nil || userID == "" {
+		return nil
+	}
+	cache, err := s.winningContent.GetWinningContentCache(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	var analysis WinningContentAnalysis
+	if err := json.Unmarshal(cache.Analysis, &analysis); err != nil {
+		log.Printf("Failed to read winning content for user %s: %v", userID, err)
+		return nil
+	}
+
+	var videos int
+	views := map[string]float64{}
+	counts := map[string]int{}
+	for bucket, perf := range analysis.DurationPerformance {
+		format, ok := formatForBucket[bucket]
+		if !ok {
+			continue
+		}
+		videos += perf.Videos
+		views[format] += perf.AvgViews * float64(perf.Videos)
+		counts[format] += perf.Videos
+	}
+	if videos < minFormatHistory {
+		return nil
+	}
+
+	avg := func(format string) float64 {
+		if counts[format] == 0 {
+			return 0
+		}
+		return views[format] / float64(counts[format])
+	}
+	history := &formatHistory{videos: videos, shortViews: avg("short"), longViews: avg("long")}
+	if history.shortViews+history.longViews == 0 {
+		return nil
+	}
+	return history
+}
+
+// recommendFormat picks short, long or series for a suggestion from the
+// user's format history, the niche and the suggestion's own shape, with a
+// confidence from 0 to 1. Without history it keeps the requested format.
+func recommendFormat(history *formatHistory, niche, requested string, shape suggestionShape) (string, float64, string) {
+	if history == nil {
+		return requested, 0, "not enough of your videos to compare formats yet"
+	}
+
+	lean := 0.6*history.shortLean() + nicheFormatLean[niche]
+	switch {
+	case len(shape.outline) >= 7 || shape.timeToCreate >= 90:
+		lean -= 0.2
+	case len(shape.outline) <= 6 && shape.timeToCreate <= 60:
+		lean += 0.2
+	}
+	lean = math.Max(-1, math.Min(lean, 1))
+
+	// Confidence grows with the size of the lean and the history behind it
+	sample := math.Min(float64(history.videos)/20, 1)
+	confidence := math.Round(math.Abs(lean)*(0.5+0.5*sample)*100) / 100
+
+	reason := history.reason()
+	if isEpisodic(shape.outline) {
+		return "series", confidence, reason + "; the outline splits naturally into episodes"
+	}
+	if lean >= 0 {
+		return "short", confidence, reason
+	}
+	return "long", confidence, reason
+}
+
+// isEpisodic reports whether an outline tracks progress over days or weeks,
+// which plays better as a series of episodes than as one video
+func isEpisodic(outline []string) bool {
+	steps := 0
+	for _, item := range outline {
+		lower := strings.ToLower(item)
+		if strings.HasPrefix(lower, "day ") || strings.HasPrefix(lower, "week ") {
+			steps++
+		}
+	}
+	return steps >= 3
+}
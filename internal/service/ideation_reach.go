@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// trendMatchCoverage is how much of a trending topic's title a video title
+// must cover to ride that trend
+const trendMatchCoverage = 0.5
+
+// EstimateReachRequest describes an idea to estimate the views of
+type EstimateReachRequest struct {
+	Niche         string  `json:"niche" binding:"required"`
+	Format        string  `json:"format,omitempty" binding:"omitempty,oneof=short long series"` // defaults to short
+	Title         string  `json:"title" binding:"required"`
+	Difficulty    string  `json:"difficulty,omitempty"`    // easy, medium, hard
+	TrendingScore float64 `json:"trendingScore,omitempty"` // 0-100; looked up from recently fetched trends when left out
+}
+
+// ReachEstimate is an idea's estimated views with the factors behind them
+type ReachEstimate struct {
+	EstimatedViews int           `json:"estimatedViews"`
+	Factors        []ReachFactor `json:"factors"`
+}
+
+// ReachFactor is one input to a reach estimate and what it did to it
+type ReachFactor struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Effect string  `json:"effect"`
+}
+
+// EstimateReach estimates an idea's views. The niche baseline is blended
+// with the user's own average views for the format, weighted by how many
+// videos that average rests on, then scaled by how hard the title's topic is
+// trending.
+func (s *IdeationService) EstimateReach(ctx context.Context, userID string, req *EstimateReachRequest) (*ReachEstimate, error) {
+	if req.Format == "" {
+		req.Format = "short"
+	}
+	estimate := &ReachEstimate{}
+
+	base, ok := difficultyBaseViews[req.Difficulty]
+	difficulty := req.Difficulty
+	if !ok {
+		base, difficulty = defaultBaseViews, "unrated"
+	}
+	estimate.Factors = append(estimate.Factors, ReachFactor{
+		Name:   "baseline",
+		Value:  float64(base),
+		Effect: fmt.Sprintf("%s ideas start from %d views", difficulty, base),
+	})
+
+	views := float64(base)
+	if m, ok := nicheViewMultipliers[req.Niche]; ok {
+		views *= m
+		estimate.Factors = append(estimate.Factors, ReachFactor{
+			Name:   "niche",
+			Value:  m,
+			Effect: fmt.Sprintf("%s audiences scale the baseline by %.1fx", req.Niche, m),
+		})
+	}
+
+	if history := s.userFormatHistory(ctx, userID); history != nil {
+		own := history.shortViews
+		if req.Format != "short" {
+			own = history.longViews
+		}
+		if own > 0 {
+			// Trust the user's numbers more the more videos they rest on
+			weight := 0.7 * math.Min(float64(history.videos)/20, 1)
+			views = weight*own + (1-weight)*views
+			estimate.Factors = append(estimate.Factors, ReachFactor{
+				Name:   "your_history",
+				Value:  math.Round(own),
+				Effect: fmt.Sprintf("your %s videos average %.0f views, weighted %.0f%% from %d videos", formatFamily(req.Format), own, weight*100, history.videos),
+			})
+		}
+	}
+
+	score := req.TrendingScore
+	source := "given"
+	if score == 0 {
+		if topic, percentile := s.matchTrendingTopic(req.Title); topic != nil {
+			score, source = percentile, fmt.Sprintf("matches %q trending on %s", topic.Title, topic.Platform)
+		}
+	}
+	if score > 0 {
+		// 50 is an average trend; a hot one adds up to half again
+		multiplier := 1 + (math.Min(score, 100)-50)/100
+		views *= multiplier
+		estimate.Factors = append(estimate.Factors, ReachFactor{
+			Name:   "trending",
+			Value:  score,
+			Effect: fmt.Sprintf("trending score %.0f (%s) scales the estimate by %.2fx", score, source, multiplier),
+		})
+	}
+
+	estimate.EstimatedViews = int(math.Round(views))
+	return estimate, nil
+}
+
+// matchTrendingTopic finds the recently fetched trending topic the title
+// covers enough of that trends hardest. Platforms score trends on their own
+// scales, so its score is its percentile among the topics fetched with it.
+func (s *IdeationService) matchTrendingTopic(title string) (*TrendingTopic, float64) {
+	tokens := titleTokens(title)
+	if len(tokens) == 0 {
+		return nil, 0
+	}
+
+	s.cacheMutex.RLock()
+	defer s.cacheMutex.RUnlock()
+
+	var best *TrendingTopic
+	var bestScore float64
+	for _, entry := range s.cache {
+		expiry := s.cacheExpiry
+		if entry.TTL > 0 {
+			expiry = entry.TTL
+		}
+		topics, ok := entry.Data.([]*TrendingTopic)
+		if !ok || time.Since(entry.Timestamp) > expiry {
+			continue
+		}
+		for _, topic := range topics {
+			topicTokens := titleTokens(topic.Title)
+			if len(topicTokens) == 0 {
+				continue
+			}
+			covered := 0
+			for token := range topicTokens {
+				if tokens[token] {
+					covered++
+				}
+			}
+			if float64(covered)/float64(len(topicTokens)) < trendMatchCoverage {
+				continue
+			}
+
+			beaten := 0
+			for _, other := range topics {
+				if other.Score < topic.Score {
+					beaten++
+				}
+			}
+			score := 100 * float64(beaten+1) / float64(len(topics))
+			if score > bestScore {
+				best, bestScore = topic, score
+			}
+		}
+	}
+	return best, bestScore
+}
+
+// formatFamily names the history bucket a format is compared against
+func formatFamily(format string) string {
+	if format == "short" {
+		return "short"
+	}
+	return "long"
+}
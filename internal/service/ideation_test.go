@@ -0,0 +1,28 @@
+package service
+
+import "testing"
+
+func TestIsBlockedMatchesWholeWords(t *testing.T) {
+	s := NewIdeationService()
+	s.SetBlocklist([]string{"gore", "Self Harm", "porn"})
+
+	tests := []struct {
+		topic TrendingTopic
+		want  bool
+	}{
+		{topic: TrendingTopic{Title: "Gore warning: new horror trailer"}, want: true},
+		{topic: TrendingTopic{Title: "Horror", Description: "too much gore."}, want: true},
+		{topic: TrendingTopic{Title: "Talking about self harm safely"}, want: true},
+		{topic: TrendingTopic{Title: "Trending", Category: "porn"}, want: true},
+		{topic: TrendingTopic{Title: "Edward Gorey's illustrations"}, want: false},
+		{topic: TrendingTopic{Title: "Gored by a bull", Description: "Pamplona"}, want: false},
+		{topic: TrendingTopic{Title: "Pornography laws debated"}, want: false},
+		{topic: TrendingTopic{Title: "Selfharmony festival lineup"}, want: false},
+		{topic: TrendingTopic{Title: "Mångore village fair"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := s.isBlocked(&tt.topic); got != tt.want {
+			t.Errorf("isBlocked(%q, %q) = %v, want %v", tt.topic.Title, tt.topic.Description, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnknownPlatform is returned when a request targets a platform missing from PlatformSpecs
+var ErrUnknownPlatform = errors.New("unknown platform")
+
+// ImageSize is an image generation size in pixels
+type ImageSize struct {
+	Width  int
+	Height int
+}
+
+// String formats the size as WIDTHxHEIGHT, the form DALL-E takes
+func (s ImageSize) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// defaultImageSize is used when no platform is targeted
+var defaultImageSize = ImageSize{1024, 1024}
+
+// dalleSizes are the sizes DALL-E 3 generates
+var dalleSizes = []ImageSize{{1024, 1024}, {1792, 1024}, {1024, 1792}}
+
+// stabilitySizes are the dimensions Stability's SD3 models generate, one for
+// each aspect ratio they take
+var stabilitySizes = []ImageSize{
+	{1024, 1024},
+	{1152, 896}, {896, 1152},
+	{1216, 832}, {832, 1216},
+	{1344, 768}, {768, 1344},
+	{1536, 640}, {640, 1536},
+}
+
+// stabilityAspectRatios maps each of stabilitySizes to the aspect_ratio
+// Stability is asked for to generate it
+var stabilityAspectRatios = map[ImageSize]string{
+	{1024, 1024}: "1:1",
+	{1152, 896}:  "5:4",
+	{896, 1152}:  "4:5",
+	{1216, 832}:  "3:2",
+	{832, 1216}:  "2:3",
+	{1344, 768}:  "16:9",
+	{768, 1344}:  "9:16",
+	{1536, 640}:  "21:9",
+	{640, 1536}:  "9:21",
+}
+
+// togetherMaxSide and togetherStep bound FLUX sizes on Together, which take
+// any dimensions in multiples of 16
+const (
+	togetherMaxSide = 1024
+	togetherStep    = 16
+)
+
+// ImageSizeForPlatform returns the generation size closest to a platform's
+// aspect ratio that the image provider supports
+func ImageSizeForPlatform(platform string, provider ImageSource) (ImageSize, error) {
+	spec, ok := PlatformSpecs[platform]
+	if !ok {
+		return ImageSize{}, fmt.Errorf("%w: %s", ErrUnknownPlatform, platform)
+	}
+	return imageSizeForAspect(float64(spec.Width)/float64(spec.Height), provider), nil
+}
+
+// validateImagePlatform checks a requested platform has specs to size images from
+func validateImagePlatform(platform string) error {
+	if platform == "" {
+		return nil
+	}
+	if _, ok := PlatformSpecs[platform]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownPlatform, platform)
+	}
+	return nil
+}
+
+// imageSize returns the size to generate with a provider, for the platform
+// when one is targeted
+func imageSize(platform string, provider ImageSource) ImageSize {
+	if platform == "" {
+		return defaultImageSize
+	}
+	size, err := ImageSizeForPlatform(platform, provider)
+	if err != nil {
+		return defaultImageSize
+	}
+	return size
+}
+
+func imageSizeForAspect(aspect float64, provider ImageSource) ImageSize {
+	switch provider {
+	case SourceDALLE:
+		return nearestImageSize(dalleSizes, aspect)
+	case SourceStability:
+		return nearestImageSize(stabilitySizes, aspect)
+	}
+
+	// Fit the longer side to the maximum and round the other to the step
+	width, height := float64(togetherMaxSide), float64(togetherMaxSide)
+	if aspect > 1 {
+		height = width / aspect
+	} else {
+		width = height * aspect
+	}
+	round := func(v float64) int {
+		return int(math.Max(math.Round(v/togetherStep), 1)) * togetherStep
+	}
+	return ImageSize{round(width), round(height)}
+}
+
+// nearestImageSize picks the size whose aspect ratio is closest to aspect,
+// compared on a log scale so 2:1 and 1:2 are equally far from square
+func nearestImageSize(sizes []ImageSize, aspect float64) ImageSize {
+	best := sizes[0]
+	bestDiff := math.Inf(1)
+	for _, size := range sizes {
+		diff := math.Abs(math.Log(float64(size.Width) / float64(size.Height) / aspect))
+		if diff < bestDiff {
+			best, bestDiff = size, diff
+		}
+	}
+	return best
+}
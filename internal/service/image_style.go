@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ImageStylePreset is a named visual style for scene images. Its prompt
+// fragment and negative prompt are added to every image of a request, so a
+// scene set shares one look.
+type ImageStylePreset struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	PromptFragment string `json:"prompt_fragment"`
+	NegativePrompt string `json:"negative_prompt,omitempty"` // Stability/Together only
+}
+
+// ImageStylePresets are the visual styles scene requests can pick by ID
+var ImageStylePresets = []ImageStylePreset{
+	{
+		ID:             "photorealistic_35mm",
+		Name:           "Photorealistic 35mm",
+		Description:    "Natural photographs shot on film",
+		PromptFragment: "photorealistic, shot on 35mm film, natural lighting, shallow depth of field, subtle film grain",
+		NegativePrompt: "illustration, cartoon, painting, 3d render, cgi, oversaturated",
+	},
+	{
+		ID:             "cinematic",
+		Name:           "Cinematic",
+		Description:    "Film stills with dramatic light and grading",
+		PromptFragment: "cinematic film still, anamorphic widescreen composition, dramatic lighting, teal and orange color grading",
+		NegativePrompt: "cartoon, flat lighting, amateur snapshot",
+	},
+	{
+		ID:             "flat_vector",
+		Name:           "Flat Vector",
+		Description:    "Clean flat illustrations for explainers",
+		PromptFragment: "flat vector illustration, clean geometric shapes, bold limited color palette, no gradients, minimal detail",
+		NegativePrompt: "photograph, photorealistic, 3d render, texture, noise, gradients",
+	},
+	{
+		ID:             "anime",
+		Name:           "Anime",
+		Description:    "Japanese animation style",
+		PromptFragment: "anime style, cel shading, vibrant colors, detailed background art, expressive characters",
+		NegativePrompt: "photograph, photorealistic, 3d render, western cartoon",
+	},
+	{
+		ID:             "watercolor",
+		Name:           "Watercolor",
+		Description:    "Soft hand-painted watercolor",
+		PromptFragment: "watercolor painting, soft washes, visible paper texture, gentle color bleeding, hand-painted",
+		NegativePrompt: "photograph, photorealistic, 3d render, hard edges, digital art",
+	},
+	{
+		ID:             "3d_render",
+		Name:           "3D Render",
+		Description:    "Polished, stylized 3D scenes",
+		PromptFragment: "stylized 3d render, soft global illumination, smooth materials, clean studio lighting",
+		NegativePrompt: "photograph, flat illustration, sketch, noisy",
+	},
+	{
+		ID:             "isometric",
+		Name:           "Isometric",
+		Description:    "Isometric diagrams and miniature worlds",
+		PromptFragment: "isometric illustration, 45-degree view, miniature diorama, clean edges, pastel colors",
+		NegativePrompt: "photograph, perspective distortion, close-up, blurry",
+	},
+	{
+		ID:             "comic",
+		Name:           "Comic Book",
+		Description:    "Inked comic panels",
+		PromptFragment: "comic book art, bold ink outlines, halftone shading, dynamic composition, saturated colors",
+		NegativePrompt: "photograph, photorealistic, 3d render, soft focus",
+	},
+	{
+		ID:             "minimalist",
+		Name:           "Minimalist",
+		Description:    "Sparse compositions with lots of negative space",
+		PromptFragment: "minimalist composition, single subject, generous negative space, muted palette, soft even light",
+		NegativePrompt: "cluttered, busy background, text, many objects",
+	},
+}
+
+// ErrUnknownImageStyle is returned when a request names an image style that doesn't exist
+var ErrUnknownImageStyle = errors.New("unknown image style")
+
+// findImageStyle returns the preset with the given ID, or nil when id is empty
+func findImageStyle(id string) (*ImageStylePreset, error) {
+	if id == "" {
+		return nil, nil
+	}
+	for i := range ImageStylePresets {
+		if ImageStylePresets[i].ID == id {
+			return &ImageStylePresets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownImageStyle, id)
+}
+
+// styleImagePrompt adds the preset's fragment to an image prompt, unless the
+// prompt already carries it, and its negative prompt to the request's
+func styleImagePrompt(preset *ImageStylePreset, prompt string, opts imageOptions) (string, imageOptions) {
+	if preset == nil {
+		return prompt, opts
+	}
+	if prompt == "" {
+		prompt = preset.PromptFragment
+	} else if !strings.Contains(prompt, preset.PromptFragment) {
+		prompt = strings.TrimRight(prompt, " .,") + ", " + preset.PromptFragment
+	}
+	if preset.NegativePrompt != "" {
+		if opts.NegativePrompt == "" {
+			opts.NegativePrompt = preset.NegativePrompt
+		} else {
+			opts.NegativePrompt += ", " + preset.NegativePrompt
+		}
+	}
+	return prompt, opts
+}
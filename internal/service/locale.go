@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"renderowl-api/internal/domain"
+)
+
+// Locale defaults for work done outside a request, such as batch workers
+const (
+	fallbackLanguage = "en"
+	fallbackRegion   = "US"
+)
+
+// regionAliases maps request regions that aren't ISO country codes to one
+var regionAliases = map[string]string{
+	"UK": "GB",
+}
+
+// requestLocale returns the locale the request was made in, filling in
+// whatever it lacks with the fallbacks
+func requestLocale(ctx context.Context) domain.Locale {
+	locale, _ := domain.LocaleFromContext(ctx)
+	if locale.Language == "" {
+		locale.Language = fallbackLanguage
+	}
+	if locale.Region == "" {
+		locale.Region = fallbackRegion
+	}
+	return locale
+}
+
+// countryCode resolves a trends region to the ISO country code sources that
+// only report per country are queried with. GLOBAL and EU, which no single
+// country stands for, and an empty region use the request locale's region.
+func countryCode(ctx context.Context, region string) string {
+	region = strings.ToUpper(region)
+	switch region {
+	case "", "GLOBAL", "EU":
+		region = requestLocale(ctx).Region
+	}
+	if alias, ok := regionAliases[region]; ok {
+		return alias
+	}
+	return region
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/safehttp"
+)
+
+// Media probe errors
+var (
+	ErrProbeInput            = errors.New("set either url or key")
+	ErrNotMedia              = errors.New("not a readable media file")
+	ErrProbeTooLarge         = errors.New("media is too large to probe")
+	ErrUnknownSourceDuration = errors.New("duration is required when the source video can't be probed")
+)
+
+// probeCacheSize bounds the probe results kept in memory
+const probeCacheSize = 1024
+
+// MediaProber reads the metadata of a media file at a URL
+type MediaProber interface {
+	ProbeURL(ctx context.Context, rawURL string) (*MediaMetadata, error)
+}
+
+// MediaFileSource opens stored files and recognises the URLs they are
+// served from
+type MediaFileSource interface {
+	Open(key string) (*os.File, error)
+	KeyForURL(url string) (string, bool)
+}
+
+// ProbeMediaRequest names the media to probe: a URL or a storage key
+type ProbeMediaRequest struct {
+	URL string `json:"url"`
+	Key string `json:"key"`
+}
+
+// MediaMetadata describes a media file as ffprobe reads it
+type MediaMetadata struct {
+	Duration    float64 `json:"duration"` // seconds
+	Width       int     `json:"width,omitempty"`
+	Height      int     `json:"height,omitempty"`
+	FPS         float64 `json:"fps,omitempty"`
+	VideoCodec  string  `json:"videoCodec,omitempty"`
+	AudioCodec  string  `json:"audioCodec,omitempty"`
+	Container   string  `json:"container"`
+	Bitrate     int64   `json:"bitrate,omitempty"` // bits per second
+	Size        int64   `json:"size"`              // bytes
+	ContentHash string  `json:"contentHash"`       // SHA-256 of the file
+}
+
+// MediaProbeService reads media metadata with ffprobe. Results are cached by
+// content hash, so the same file behind different URLs is probed once.
+type MediaProbeService struct {
+	files      MediaFileSource
+	ffprobe    string
+	maxBytes   int64
+	httpClient *http.Client
+
+	cacheMutex sync.RWMutex
+	cache      map[string]*MediaMetadata
+}
+
+// ffprobeOutput is the part of ffprobe's JSON output that is read
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		Duration     string `json:"duration"`
+		Disposition  struct {
+			AttachedPic int `json:"attached_pic"`
+		} `json:"disposition"`
+	} `json:"streams"`
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+// NewMediaProbeService creates a media probe service. Files over maxBytes
+// are refused.
+func NewMediaProbeService(files MediaFileSource, ffprobePath string, maxBytes int64) *MediaProbeService {
+	return &MediaProbeService{
+		files:      files,
+		ffprobe:    ffprobePath,
+		maxBytes:   maxBytes,
+		httpClient: safehttp.NewClient(5 * time.Minute),
+		cache:      make(map[string]*MediaMetadata),
+	}
+}
+
+// Probe reads the metadata of a stored file or of a file at a URL
+func (s *MediaProbeService) Probe(ctx context.Context, req *ProbeMediaRequest) (*MediaMetadata, error) {
+	if (req.URL == "") == (req.Key == "") {
+		return nil, ErrProbeInput
+	}
+	if req.Key != "" {
+		return s.probeKey(ctx, req.Key)
+	}
+	return s.ProbeURL(ctx, req.URL)
+}
+
+// ProbeURL reads the metadata of a file at a URL. Files in storage are read
+// from disk; anything else is downloaded to a temporary file first, from
+// public addresses only.
+func (s *MediaProbeService) ProbeURL(ctx context.Context, rawURL string) (*MediaMetadata, error) {
+	if key, ok := s.files.KeyForURL(rawURL); ok {
+		return s.probeKey(ctx, key)
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: only http and https URLs can be probed", ErrMediaFetch)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMediaFetch, err)
+	}
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMediaFetch, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: server answered %d", ErrMediaFetch, resp.StatusCode)
+	}
+	if resp.ContentLength > s.maxBytes {
+		return nil, ErrProbeTooLarge
+	}
+
+	tmp, err := os.CreateTemp("", "probe-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(resp.Body, s.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMediaFetch, err)
+	}
+	if size > s.maxBytes {
+		return nil, ErrProbeTooLarge
+	}
+	return s.probeFile(ctx, tmp.Name(), hex.EncodeToString(hash.Sum(nil)), size)
+}
+
+// probeKey reads the metadata of a stored file
+func (s *MediaProbeService) probeKey(ctx context.Context, key string) (*MediaMetadata, error) {
+	file, err := s.files.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		return nil, ErrFileNotFound
+	}
+	if info.Size() > s.maxBytes {
+		return nil, ErrProbeTooLarge
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return s.probeFile(ctx, file.Name(), hex.EncodeToString(hash.Sum(nil)), info.Size())
+}
+
+// probeFile runs ffprobe on a local file, unless a file with the same
+// content was probed before
+func (s *MediaProbeService) probeFile(ctx context.Context, path, contentHash string, size int64) (*MediaMetadata, error) {
+	s.cacheMutex.RLock()
+	cached, ok := s.cache[contentHash]
+	s.cacheMutex.RUnlock()
+	if ok {
+		metadata := *cached
+		return &metadata, nil
+	}
+
+	out, err := exec.CommandContext(ctx, s.ffprobe, "-v", "error", "-print_format", "json", "-show_format", "-show_streams", path).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("%w: %s", ErrNotMedia, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to run ffprobe: %w", err)
+	}
+
+	metadata, err := parseProbeOutput(out)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Size = size
+	metadata.ContentHash = contentHash
+
+	s.cacheMutex.Lock()
+	if len(s.cache) >= probeCacheSize {
+		// Any entry will do; evicted files are just probed again
+		for hash := range s.cache {
+			delete(s.cache, hash)
+			break
+		}
+	}
+	s.cache[contentHash] = metadata
+	s.cacheMutex.Unlock()
+
+	result := *metadata
+	return &result, nil
+}
+
+// parseProbeOutput reads ffprobe's JSON output. Cover art attached to audio
+// files doesn't count as a video stream.
+func parseProbeOutput(out []byte) (*MediaMetadata, error) {
+	var probe ffprobeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	metadata := &MediaMetadata{Container: probe.Format.FormatName}
+	metadata.Duration, _ = strconv.ParseFloat(probe.Format.Duration, 64)
+	metadata.Bitrate, _ = strconv.ParseInt(probe.Format.BitRate, 10, 64)
+	for _, stream := range probe.Streams {
+		switch {
+		case stream.CodecType == "video" && stream.Disposition.AttachedPic == 0 && metadata.VideoCodec == "":
+			metadata.VideoCodec = stream.CodecName
+			metadata.Width = stream.Width
+			metadata.Height = stream.Height
+			metadata.FPS = parseFrameRate(stream.AvgFrameRate)
+			if metadata.Duration == 0 {
+				metadata.Duration, _ = strconv.ParseFloat(stream.Duration, 64)
+			}
+		case stream.CodecType == "audio" && metadata.AudioCodec == "":
+			metadata.AudioCodec = stream.CodecName
+		}
+	}
+	if metadata.VideoCodec == "" && metadata.AudioCodec == "" {
+		return nil, fmt.Errorf("%w: no audio or video streams", ErrNotMedia)
+	}
+	return metadata, nil
+}
+
+// parseFrameRate reads ffprobe's fractional frame rates, e.g. 30000/1001
+func parseFrameRate(rate string) float64 {
+	num, den, ok := strings.Cut(rate, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	if !ok {
+		return n
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return math.Round(n/d*100) / 100
+}
+
+// SetMediaProber enables probing source videos before variations are built
+func (s *VariationsService) SetMediaProber(prober MediaProber) {
+	s.prober = prober
+}
+
+// preflightSource probes the source video so unreachable or non-media
+// sources fail before any variation is built, and fills in its duration when
+// the request leaves it out
+func (s *VariationsService) preflightSource(ctx context.Context, req *CreateVariationsRequest) (*MediaMetadata, error) {
+	if s.prober != nil {
+		source, err := s.prober.ProbeURL(ctx, req.SourceVideoURL)
+		if err != nil {
+			return nil, err
+		}
+		if req.Duration <= 0 {
+			req.Duration = source.Duration
+		}
+		return source, nil
+	}
+	if req.Duration <= 0 {
+		return nil, ErrUnknownSourceDuration
+	}
+	return nil, nil
+}
+
+// SetMediaProber enables reading rendered videos' real duration, size and
+// resolution
+func (s *BatchService) SetMediaProber(prober MediaProber) {
+	s.prober = prober
+}
+
+// probeResult replaces a rendered video's planned duration and resolution
+// with what the file actually has
+func (s *BatchService) probeResult(ctx context.Context, video *domain.BatchVideo, result *domain.VideoResult) {
+	if s.prober == nil || result.VideoURL == "" {
+		return
+	}
+	metadata, err := s.prober.ProbeURL(ctx, result.VideoURL)
+	if err != nil {
+		logVideo(video, "probing the rendered video failed, keeping the planned metadata: %v", err)
+		return
+	}
+	result.Duration = metadata.Duration
+	result.Size = metadata.Size
+	if metadata.Width > 0 {
+		result.Metadata["resolution"] = fmt.Sprintf("%dx%d", metadata.Width, metadata.Height)
+	}
+	if metadata.FPS > 0 {
+		result.Metadata["fps"] = strconv.FormatFloat(metadata.FPS, 'f', -1, 64)
+	}
+	if metadata.VideoCodec != "" {
+		result.Metadata["videoCodec"] = metadata.VideoCodec
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"renderowl-api/internal/safehttp"
+)
+
+// noStoredFiles is a file source with nothing in storage
+type noStoredFiles struct{}
+
+func (noStoredFiles) Open(key string) (*os.File, error)   { return nil, ErrFileNotFound }
+func (noStoredFiles) KeyForURL(url string) (string, bool) { return "", false }
+
+func TestProbeURLRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("probe reached the internal server")
+	}))
+	defer server.Close()
+
+	probe := NewMediaProbeService(noStoredFiles{}, "ffprobe", 1<<20)
+	for _, rawURL := range []string{server.URL + "/video.mp4", "http://169.254.169.254/latest/meta-data/"} {
+		_, err := probe.ProbeURL(context.Background(), rawURL)
+		if !errors.Is(err, ErrMediaFetch) || !errors.Is(err, safehttp.ErrBlockedAddress) {
+			t.Errorf("ProbeURL(%s) = %v, want a blocked address fetch error", rawURL, err)
+		}
+	}
+}
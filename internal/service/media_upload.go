@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"renderowl-api/internal/domain"
+	socialdomain "renderowl-api/internal/domain/social"
+	"renderowl-api/internal/scheduler"
+)
+
+// JobDeleteUpload deletes a direct upload once its retention is over
+const JobDeleteUpload = "delete-upload"
+
+// Direct upload errors
+var (
+	ErrUploadTooLarge        = domain.NewAppError(domain.CodeValidation, http.StatusRequestEntityTooLarge, "upload too large")
+	ErrUnsupportedUploadType = domain.NewAppError(domain.CodeUnsupportedMediaType, http.StatusBadRequest, "unsupported upload type: send a video, an image or a PDF")
+)
+
+// MediaStorage stores media files and deletes them when they are no longer needed
+type MediaStorage interface {
+	StorageProvider
+	MediaFileSource
+	Delete(ctx context.Context, key string) error
+}
+
+// MediaUploadService keeps files users upload for publishing in storage, so
+// platforms can be handed a URL, and deletes them after publishing
+type MediaUploadService struct {
+	storage   MediaStorage
+	scheduler *scheduler.Scheduler
+	maxBytes  int64
+	retention time.Duration
+}
+
+// StoredUpload is a direct upload saved to storage
+type StoredUpload struct {
+	Key       string                 `json:"key"`
+	URL       string                 `json:"url"`
+	MediaType socialdomain.MediaType `json:"mediaType"`
+	Size      int64                  `json:"size"`
+}
+
+// deleteUploadJobData is the payload of a JobDeleteUpload job
+type deleteUploadJobData struct {
+	Key string `json:"key"`
+}
+
+// NewMediaUploadService creates a media upload service. Uploads over maxBytes
+// are refused, and published uploads are deleted after retention.
+func NewMediaUploadService(storage MediaStorage, sched *scheduler.Scheduler, maxBytes int64, retention time.Duration) *MediaUploadService {
+	return &MediaUploadService{
+		storage:   storage,
+		scheduler: sched,
+		maxBytes:  maxBytes,
+		retention: retention,
+	}
+}
+
+// Initialize registers the upload cleanup job handler
+func (s *MediaUploadService) Initialize() {
+	s.scheduler.RegisterHandler(JobDeleteUpload, s.handleDeleteJob)
+}
+
+// MaxBytes is the largest upload accepted
+func (s *MediaUploadService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Store saves an uploaded file under the user's uploads. The media type is
+// taken from the content type, falling back to the file extension.
+func (s *MediaUploadService) Store(ctx context.Context, userID, filename, contentType string, r io.Reader) (*StoredUpload, error) {
+	ext := strings.ToLower(path.Ext(filename))
+	mediaType, ok := uploadMediaType(contentType, ext)
+	if !ok {
+		return nil, ErrUnsupportedUploadType
+	}
+
+	if !safeExtension(ext) {
+		ext = ""
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, ErrUploadTooLarge
+	}
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+
+	key := uploadPrefix(userID) + uuid.New().String() + ext
+	url, err := s.storage.Upload(ctx, key, data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store upload: %w", err)
+	}
+
+	return &StoredUpload{
+		Key:       key,
+		URL:       url,
+		MediaType: mediaType,
+		Size:      int64(len(data)),
+	}, nil
+}
+
+// UploadSize returns the size of one of the user's uploads, named by its key
+// or the URL it's served from. It's false when ref isn't an upload of the
+// user's that is still in storage.
+func (s *MediaUploadService) UploadSize(userID, ref string) (int64, bool) {
+	file, ok := s.openUpload(userID, ref)
+	if !ok {
+		return 0, false
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, false
+	}
+	return info.Size(), true
+}
+
+// OpenUpload opens one of the user's uploads, named by its key or the URL
+// it's served from, for platforms that are sent the file itself
+func (s *MediaUploadService) OpenUpload(userID, ref string) (io.ReadCloser, bool) {
+	file, ok := s.openUpload(userID, ref)
+	if !ok {
+		return nil, false
+	}
+	return file, true
+}
+
+// UploadURL returns the URL one of the user's uploads, named by its key or
+// URL, is served from, for platforms that pull media from a URL
+func (s *MediaUploadService) UploadURL(userID, ref string) (string, bool) {
+	file, ok := s.openUpload(userID, ref)
+	if !ok {
+		return "", false
+	}
+	file.Close()
+	key, _ := userUploadKey(s.storage, userID, ref)
+	return s.storage.GetURL(key), true
+}
+
+// openUpload opens the file of one of the user's uploads, refusing keys
+// outside the user's uploads and anything that isn't a regular file
+func (s *MediaUploadService) openUpload(userID, ref string) (*os.File, bool) {
+	key, ok := userUploadKey(s.storage, userID, ref)
+	if !ok {
+		return nil, false
+	}
+	file, err := s.storage.Open(key)
+	if err != nil {
+		return nil, false
+	}
+	if info, err := file.Stat(); err != nil || !info.Mode().IsRegular() {
+		file.Close()
+		return nil, false
+	}
+	return file, true
+}
+
+// userUploadKey resolves a key or URL to the storage key of one of the
+// user's uploads
+func userUploadKey(files MediaFileSource, userID, ref string) (string, bool) {
+	key := ref
+	if urlKey, ok := files.KeyForURL(ref); ok {
+		key = urlKey
+	}
+	if path.Clean(key) != key || !strings.HasPrefix(key, uploadPrefix(userID)) {
+		return "", false
+	}
+	return key, true
+}
+
+// uploadPrefix is the storage key prefix of a user's uploads
+func uploadPrefix(userID string) string {
+	return "uploads/" + userID + "/"
+}
+
+// Release schedules a stored upload's deletion once its retention is over,
+// or deletes it straight away when uploads aren't retained
+func (s *MediaUploadService) Release(ctx context.Context, key string) {
+	if s.retention <= 0 {
+		if err := s.storage.Delete(ctx, key); err != nil && !errors.Is(err, ErrFileNotFound) {
+			log.Printf("Failed to delete upload %s: %v", key, err)
+		}
+		return
+	}
+
+	data, _ := json.Marshal(deleteUploadJobData{Key: key})
+	job := &scheduler.Job{
+		Name:  JobDeleteUpload,
+		Data:  data,
+		Delay: s.retention,
+	}
+	if err := s.scheduler.AddJob(ctx, job); err != nil {
+		log.Printf("Failed to schedule deletion of upload %s: %v", key, err)
+	}
+}
+
+func (s *MediaUploadService) handleDeleteJob(ctx context.Context, job *scheduler.Job) error {
+	var data deleteUploadJobData
+	if err := json.Unmarshal(job.Data, &data); err != nil {
+		return fmt.Errorf("failed to unmarshal job data: %w", err)
+	}
+
+	if err := s.storage.Delete(ctx, data.Key); err != nil && !errors.Is(err, ErrFileNotFound) {
+		return fmt.Errorf("failed to delete upload %s: %w", data.Key, err)
+	}
+	return nil
+}
+
+// uploadMediaType maps an upload's content type, or its extension when the
+// content type is missing or generic, to the media type it is published as
+func uploadMediaType(contentType, ext string) (socialdomain.MediaType, bool) {
+	switch {
+	case strings.HasPrefix(contentType, "video/"):
+		return socialdomain.MediaTypeVideo, true
+	case strings.HasPrefix(contentType, "image/"):
+		return socialdomain.MediaTypeImage, true
+	case contentType == "application/pdf":
+		return socialdomain.MediaTypeDocument, true
+	}
+
+	switch ext {
+	case ".mp4", ".mov", ".webm", ".m4v":
+		return socialdomain.MediaTypeVideo, true
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+		return socialdomain.MediaTypeImage, true
+	case ".pdf":
+		return socialdomain.MediaTypeDocument, true
+	}
+	return "", false
+}
+
+// safeExtension reports whether a client-supplied extension is fit to use in
+// a storage key
+func safeExtension(ext string) bool {
+	if len(ext) < 2 || len(ext) > 6 {
+		return false
+	}
+	for _, r := range ext[1:] {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
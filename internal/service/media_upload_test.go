@@ -0,0 +1,44 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestUploadSizeOnlyResolvesOwnUploads(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir(), "https://files.example.com")
+	uploads := NewMediaUploadService(storage, nil, 1<<20, 0)
+	stored, err := uploads.Store(context.Background(), "user-a", "clip.mp4", "video/mp4", bytes.NewReader([]byte("video")))
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	for _, ref := range []string{stored.Key, stored.URL} {
+		if size, ok := uploads.UploadSize("user-a", ref); !ok || size != 5 {
+			t.Errorf("UploadSize(user-a, %s) = %d, %v, want 5, true", ref, size, ok)
+		}
+		if _, ok := uploads.UploadSize("user-b", ref); ok {
+			t.Errorf("user-b resolved user-a's upload %s", ref)
+		}
+		if file, ok := uploads.OpenUpload("user-a", ref); !ok {
+			t.Errorf("OpenUpload(user-a, %s) failed", ref)
+		} else {
+			file.Close()
+		}
+		if _, ok := uploads.OpenUpload("user-b", ref); ok {
+			t.Errorf("user-b opened user-a's upload %s", ref)
+		}
+		if url, ok := uploads.UploadURL("user-a", ref); !ok || url != stored.URL {
+			t.Errorf("UploadURL(user-a, %s) = %q, %v, want %q", ref, url, ok, stored.URL)
+		}
+		if _, ok := uploads.UploadURL("user-b", ref); ok {
+			t.Errorf("user-b got the URL of user-a's upload %s", ref)
+		}
+	}
+
+	traversal := "uploads/user-b/../user-a/" + stored.Key[len("uploads/user-a/"):]
+	if _, ok := uploads.UploadSize("user-b", traversal); ok {
+		t.Errorf("user-b resolved %s", traversal)
+	}
+}
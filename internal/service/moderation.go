@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	socialdomain "renderowl-api/internal/domain/social"
+)
+
+const defaultModerationModel = "omni-moderation-latest"
+
+// ModerationService checks posts against a moderation API before they are
+// published. It speaks the OpenAI moderations API; MODERATION_BASE_URL and
+// MODERATION_API_KEY point it at another provider with the same API.
+type ModerationService struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+	// openAIAccount is who OpenAI calls are billed to
+	openAIAccount openAIAccount
+}
+
+// NewModerationService creates a new moderation service
+func NewModerationService() *ModerationService {
+	apiKey := os.Getenv("MODERATION_API_KEY")
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+	return &ModerationService{
+		apiKey:  apiKey,
+		baseURL: getBaseURL("MODERATION_BASE_URL", getBaseURL("OPENAI_BASE_URL", defaultOpenAIBaseURL)),
+		model:   getEnv("MODERATION_MODEL", defaultModerationModel),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Moderate runs a post's title and description, and its thumbnail when it
+// is a URL, through the moderation API
+func (s *ModerationService) Moderate(ctx context.Context, req *socialdomain.UploadRequest) (*socialdomain.ModerationResult, error) {
+	if s.apiKey == "" {
+		return nil, errors.New("no moderation API key configured")
+	}
+
+	inputs := []map[string]interface{}{}
+	if text := strings.TrimSpace(req.Title + "\n\n" + req.Description); text != "" {
+		inputs = append(inputs, map[string]interface{}{"type": "text", "text": text})
+	}
+	if strings.HasPrefix(req.ThumbnailURL, "http://") || strings.HasPrefix(req.ThumbnailURL, "https://") {
+		inputs = append(inputs, map[string]interface{}{
+			"type":      "image_url",
+			"image_url": map[string]string{"url": req.ThumbnailURL},
+		})
+	}
+	if len(inputs) == 0 {
+		return &socialdomain.ModerationResult{}, nil
+	}
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"model": s.model,
+		"input": inputs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/moderations", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	setOpenAIAuth(httpReq, s.apiKey, s.openAIAccount)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("moderation API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Results []struct {
+			Flagged    bool            `json:"flagged"`
+			Categories map[string]bool `json:"categories"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	moderation := &socialdomain.ModerationResult{}
+	flagged := map[string]bool{}
+	for _, r := range result.Results {
+		if !r.Flagged {
+			continue
+		}
+		moderation.Flagged = true
+		for category, hit := range r.Categories {
+			if hit && !flagged[category] {
+				flagged[category] = true
+				moderation.Categories = append(moderation.Categories, category)
+			}
+		}
+	}
+	sort.Strings(moderation.Categories)
+
+	return moderation, nil
+}
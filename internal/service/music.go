@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"renderowl-api/internal/domain"
+)
+
+// ErrMusicTrackNotFound is returned for a music track ID not in the library
+var ErrMusicTrackNotFound = errors.New("music track not found")
+
+// musicCatalog is the built-in music library. Files are served from the
+// music/ folder of storage, named after the track ID.
+var musicCatalog = []domain.MusicTrack{
+	{ID: "bright-morning", Title: "Bright Morning", Artist: "Renderowl", Genre: "pop", Mood: "upbeat", BPM: 118, Duration: 124},
+	{ID: "city-lights", Title: "City Lights", Artist: "Renderowl", Genre: "electronic", Mood: "upbeat", BPM: 124, Duration: 142},
+	{ID: "slow-tide", Title: "Slow Tide", Artist: "Renderowl", Genre: "ambient", Mood: "calm", BPM: 70, Duration: 180},
+	{ID: "paper-notes", Title: "Paper Notes", Artist: "Renderowl", Genre: "lofi", Mood: "calm", BPM: 82, Duration: 150},
+	{ID: "rising-stakes", Title: "Rising Stakes", Artist: "Renderowl", Genre: "cinematic", Mood: "dramatic", BPM: 96, Duration: 136},
+	{ID: "first-light", Title: "First Light", Artist: "Renderowl", Genre: "cinematic", Mood: "inspiring", BPM: 100, Duration: 165},
+	{ID: "open-road", Title: "Open Road", Artist: "Renderowl", Genre: "acoustic", Mood: "inspiring", BPM: 108, Duration: 132},
+	{ID: "bouncy-castle", Title: "Bouncy Castle", Artist: "Renderowl", Genre: "pop", Mood: "playful", BPM: 128, Duration: 98},
+}
+
+// MusicFilter narrows a music library listing; empty fields match everything
+type MusicFilter struct {
+	Genre string
+	Mood  string
+}
+
+// MusicLibrary is the catalogue of background music for generated videos
+type MusicLibrary struct {
+	tracks []domain.MusicTrack
+}
+
+// NewMusicLibrary creates the music library, with track URLs under the
+// storage base URL
+func NewMusicLibrary(storageBaseURL string) *MusicLibrary {
+	tracks := make([]domain.MusicTrack, len(musicCatalog))
+	for i, track := range musicCatalog {
+		track.URL = strings.TrimRight(storageBaseURL, "/") + "/music/" + track.ID + ".mp3"
+		track.License = "royalty-free"
+		tracks[i] = track
+	}
+	return &MusicLibrary{tracks: tracks}
+}
+
+// List returns the library's tracks matching the filter
+func (l *MusicLibrary) List(filter MusicFilter) []domain.MusicTrack {
+	tracks := make([]domain.MusicTrack, 0, len(l.tracks))
+	for _, track := range l.tracks {
+		if filter.Genre != "" && !strings.EqualFold(track.Genre, filter.Genre) {
+			continue
+		}
+		if filter.Mood != "" && !strings.EqualFold(track.Mood, filter.Mood) {
+			continue
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks
+}
+
+// Get returns a track by ID
+func (l *MusicLibrary) Get(id string) (*domain.MusicTrack, error) {
+	for _, track := range l.tracks {
+		if track.ID == id {
+			return &track, nil
+		}
+	}
+	return nil, ErrMusicTrackNotFound
+}
+
+// musicDucking is how background music is ducked under narration
+var musicDucking = domain.Ducking{AmountDB: 12, AttackMs: 250, ReleaseMs: 500}
+
+// musicClips lays a track end to end from the start of the timeline,
+// looping it to fill duration seconds and trimming the last loop
+func musicClips(track *domain.MusicTrack, trackID string, duration float64) []CreateClipRequest {
+	if track.Duration <= 0 || duration <= 0 {
+		return nil
+	}
+	var clips []CreateClipRequest
+	for start := 0.0; start < duration; start += track.Duration {
+		clips = append(clips, CreateClipRequest{
+			TrackID:      trackID,
+			Name:         track.Title,
+			Type:         "audio",
+			SourceURL:    track.URL,
+			StartTime:    start,
+			EndTime:      math.Min(start+track.Duration, duration),
+			SourceLength: track.Duration,
+		})
+	}
+	return clips
+}
@@ -4,41 +4,31 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"renderowl-api/internal/domain"
 )
 
 // OptimizerService handles content optimization based on performance analytics
 type OptimizerService struct {
-	analyticsRepo   AnalyticsRepository
-	timelineRepo    TimelineRepository
-	socialService   *SocialService
+	analytics       VideoAnalyticsSource
 	aiScriptService *AIScriptService
+	commentFetchers map[string]CommentFetcher
+	sentimentCache  map[string]*SentimentAnalysis
+	sentimentMu     sync.RWMutex
+	winningCache    WinningContentCacheStore
+	suggestionStore SuggestionStore
+	thumbnails      ThumbnailGenerator
 }
 
-// AnalyticsRepository defines the interface for analytics data
-type AnalyticsRepository interface {
-	GetVideoPerformance(videoID string, days int) (*VideoAnalytics, error)
-	GetTopPerforming(userID string, limit int) ([]*VideoAnalytics, error)
-	GetUnderperforming(userID string, limit int) ([]*VideoAnalytics, error)
-	GetComparativeMetrics(videoIDs []string) (map[string]*ComparativeMetrics, error)
-	GetTrendingTopics(days int) ([]*TrendingTopicData, error)
-}
-
-// TimelineRepository defines the interface for timeline data
-type TimelineRepository interface {
-	Get(id string, userID string) (*domain.Timeline, error)
-	Update(timeline *domain.Timeline) error
-	ListByUser(userID string, limit, offset int) ([]*domain.Timeline, error)
-}
-
-// SocialService handles social media operations
-type SocialService struct {
-	// Social media service implementation
+// VideoAnalyticsSource provides the per-video analytics the optimizer works
+// from, limited to the user's own videos
+type VideoAnalyticsSource interface {
+	GetVideoAnalytics(ctx context.Context, userID, videoID string, days int) (*VideoAnalytics, error)
+	GetTopPerforming(ctx context.Context, userID string, limit int) ([]*VideoAnalytics, error)
+	GetUnderperforming(ctx context.Context, userID string, limit int) ([]*VideoAnalytics, error)
 }
 
 // VideoAnalytics represents performance data for a video
@@ -60,6 +50,7 @@ type VideoAnalytics struct {
 	DaysSincePublish int                   `json:"daysSincePublish"`
 	EngagementRate  float64                `json:"engagementRate"`
 	ViralScore      float64                `json:"viralScore"`
+	DailyViews      []int                  `json:"dailyViews,omitempty"` // Oldest first
 }
 
 // ComparativeMetrics for A/B testing
@@ -97,6 +88,8 @@ type OptimizationSuggestion struct {
 	AutoApplicable bool                   `json:"autoApplicable"`
 	Applied        bool                   `json:"applied"`
 	AppliedAt      *time.Time             `json:"appliedAt,omitempty"`
+	Dismissed      bool                   `json:"dismissed"`
+	Feedback       int                    `json:"feedback"` // 1 helpful, -1 not helpful, 0 unrated
 	Result         *OptimizationResult    `json:"result,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
 	CreatedAt      time.Time              `json:"createdAt"`
@@ -150,6 +143,7 @@ type PerformanceReport struct {
 	Trends             []*TrendAnalysis         `json:"trends"`
 	Suggestions        []*OptimizationSuggestion `json:"suggestions"`
 	ComparativeResults []*ComparativeTest       `json:"comparativeResults"`
+	Sentiment          []*SentimentAnalysis     `json:"sentiment,omitempty"`
 	GeneratedAt        time.Time                `json:"generatedAt"`
 }
 
@@ -189,24 +183,25 @@ type ComparativeTest struct {
 }
 
 // NewOptimizerService creates a new optimizer service
-func NewOptimizerService(
-	analyticsRepo AnalyticsRepository,
-	timelineRepo TimelineRepository,
-	socialService *SocialService,
-	aiScriptService *AIScriptService,
-) *OptimizerService {
-	return &OptimizerService{
-		analyticsRepo:   analyticsRepo,
-		timelineRepo:    timelineRepo,
-		socialService:   socialService,
+func NewOptimizerService(analytics VideoAnalyticsSource, aiScriptService *AIScriptService) *OptimizerService {
+	s := &OptimizerService{
+		analytics:       analytics,
 		aiScriptService: aiScriptService,
+		commentFetchers: map[string]CommentFetcher{},
+		sentimentCache:  make(map[string]*SentimentAnalysis),
+	}
+
+	if key := getEnv("YOUTUBE_API_KEY", ""); key != "" {
+		s.commentFetchers["youtube"] = NewYouTubeCommentFetcher(key)
 	}
+
+	return s
 }
 
 // AnalyzeVideo analyzes a video's performance and generates suggestions
-func (s *OptimizerService) AnalyzeVideo(ctx context.Context, videoID string) ([]*OptimizationSuggestion, error) {
+func (s *OptimizerService) AnalyzeVideo(ctx context.Context, userID, videoID string) ([]*OptimizationSuggestion, error) {
 	// Get video analytics
-	analytics, err := s.analyticsRepo.GetVideoPerformance(videoID, 30)
+	analytics, err := s.analytics.GetVideoAnalytics(ctx, userID, videoID, 30)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get video analytics: %w", err)
 	}
@@ -229,20 +224,12 @@ func (s *OptimizerService) AnalyzeVideo(ctx context.Context, videoID string) ([]
 	engagementSuggestions := s.analyzeEngagement(ctx, analytics)
 	suggestions = append(suggestions, engagementSuggestions...)
 
-	// Sort by priority and expected impact
-	sort.Slice(suggestions, func(i, j int) bool {
-		priorityOrder := map[Priority]int{
-			PriorityHigh:   3,
-			PriorityMedium: 2,
-			PriorityLow:    1,
-		}
-		
-		if priorityOrder[suggestions[i].Priority] != priorityOrder[suggestions[j].Priority] {
-			return priorityOrder[suggestions[i].Priority] > priorityOrder[suggestions[j].Priority]
-		}
-		
-		return suggestions[i].ExpectedImpact > suggestions[j].ExpectedImpact
-	})
+	sentimentSuggestions := s.analyzeSentiment(ctx, analytics)
+	suggestions = append(suggestions, sentimentSuggestions...)
+
+	// Drop dismissed suggestions, then rank by priority, impact and past feedback
+	suggestions = s.reconcileSuggestions(ctx, userID, videoID, suggestions)
+	s.rankSuggestions(ctx, userID, suggestions)
 
 	return suggestions, nil
 }
@@ -251,8 +238,8 @@ func (s *OptimizerService) AnalyzeVideo(ctx context.Context, videoID string) ([]
 func (s *OptimizerService) analyzeTitle(ctx context.Context, analytics *VideoAnalytics) []*OptimizationSuggestion {
 	var suggestions []*OptimizationSuggestion
 
-	// Check if CTR is below average
-	if analytics.CTR < 4.0 {
+	// Check if CTR is below average; it's zero where the platform doesn't report impressions
+	if analytics.CTR > 0 && analytics.CTR < 4.0 {
 		suggestions = append(suggestions, &OptimizationSuggestion{
 			ID:             uuid.New().String(),
 			VideoID:        analytics.VideoID,
@@ -308,7 +295,7 @@ func (s *OptimizerService) analyzeThumbnail(ctx context.Context, analytics *Vide
 	var suggestions []*OptimizationSuggestion
 
 	// Low CTR often indicates thumbnail issues
-	if analytics.CTR < 3.5 {
+	if analytics.CTR > 0 && analytics.CTR < 3.5 {
 		suggestions = append(suggestions, &OptimizationSuggestion{
 			ID:             uuid.New().String(),
 			VideoID:        analytics.VideoID,
@@ -318,7 +305,7 @@ func (s *OptimizerService) analyzeThumbnail(ctx context.Context, analytics *Vide
 			Description:    "Low CTR suggests your thumbnail isn't capturing attention. Test a more contrasting, high-emotion thumbnail.",
 			ExpectedImpact: 20.0,
 			Confidence:     0.80,
-			AutoApplicable: false,
+			AutoApplicable: s.thumbnails != nil,
 			CreatedAt:      time.Now(),
 		})
 	}
@@ -433,14 +420,14 @@ func (s *OptimizerService) GeneratePerformanceReport(ctx context.Context, userID
 	}
 
 	// Get top performing videos
-	topVideos, err := s.analyticsRepo.GetTopPerforming(userID, 10)
+	topVideos, err := s.analytics.GetTopPerforming(ctx, userID, 10)
 	if err != nil {
 		return nil, err
 	}
 	report.TopVideos = topVideos
 
 	// Get underperforming videos
-	underperforming, err := s.analyticsRepo.GetUnderperforming(userID, 10)
+	underperforming, err := s.analytics.GetUnderperforming(ctx, userID, 10)
 	if err != nil {
 		return nil, err
 	}
@@ -454,13 +441,22 @@ func (s *OptimizerService) GeneratePerformanceReport(ctx context.Context, userID
 
 	// Generate suggestions for underperforming videos
 	for _, video := range underperforming {
-		suggestions, err := s.AnalyzeVideo(ctx, video.VideoID)
+		suggestions, err := s.AnalyzeVideo(ctx, userID, video.VideoID)
 		if err != nil {
 			continue
 		}
 		report.Suggestions = append(report.Suggestions, suggestions...)
 	}
 
+	// Summarize comment sentiment where the platform exposes comments
+	for _, video := range append(topVideos, underperforming...) {
+		sentiment, err := s.AnalyzeSentiment(ctx, video.VideoID, video.Platform)
+		if err != nil {
+			continue
+		}
+		report.Sentiment = append(report.Sentiment, sentiment)
+	}
+
 	return report, nil
 }
 
@@ -568,19 +564,37 @@ func (s *OptimizerService) AutoOptimizeTitle(ctx context.Context, videoID string
 	return selectedTemplate, nil
 }
 
-// AutoOptimizeThumbnail generates an optimized thumbnail
-func (s *OptimizerService) AutoOptimizeThumbnail(ctx context.Context, videoID string, currentThumbnailURL string) (string, error) {
-	// This would use AI to:
-	// 1. Analyze current thumbnail
-	// 2. Check contrast, faces, text readability
-	// 3. Generate improvements
-	
-	// For now, return suggestion
-	return "Generate high-contrast thumbnail with face and 3-5 words max", nil
+// ThumbnailGenerator generates thumbnail candidates for a video, ranked by
+// predicted CTR
+type ThumbnailGenerator interface {
+	GenerateAIThumbnails(ctx context.Context, sourceID, title string, keywords []string, count int) ([]ThumbnailVariation, error)
 }
 
-// ApplySuggestion applies an optimization suggestion
-func (s *OptimizerService) ApplySuggestion(ctx context.Context, suggestion *OptimizationSuggestion) error {
+// SetThumbnailGenerator enables AI thumbnails, which makes thumbnail
+// suggestions auto-applicable
+func (s *OptimizerService) SetThumbnailGenerator(thumbnails ThumbnailGenerator) {
+	s.thumbnails = thumbnails
+}
+
+// AutoOptimizeThumbnail generates thumbnails for a user's video from its
+// title, best predicted CTR first
+func (s *OptimizerService) AutoOptimizeThumbnail(ctx context.Context, userID, videoID, title string) ([]ThumbnailVariation, error) {
+	if s.thumbnails == nil {
+		return nil, ErrThumbnailsUnavailable
+	}
+	if title == "" {
+		analytics, err := s.analytics.GetVideoAnalytics(ctx, userID, videoID, 30)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get video title: %w", err)
+		}
+		title = analytics.Title
+	}
+
+	return s.thumbnails.GenerateAIThumbnails(ctx, videoID, title, nil, 3)
+}
+
+// ApplySuggestion applies an optimization suggestion for one of the user's videos
+func (s *OptimizerService) ApplySuggestion(ctx context.Context, userID string, suggestion *OptimizationSuggestion) error {
 	if suggestion.Applied {
 		return fmt.Errorf("suggestion already applied")
 	}
@@ -596,12 +610,16 @@ func (s *OptimizerService) ApplySuggestion(ctx context.Context, suggestion *Opti
 		suggestion.SuggestedValue = newTitle
 		
 	case SuggestionTypeThumbnail:
-		// Generate new thumbnail
-		thumbURL, err := s.AutoOptimizeThumbnail(ctx, suggestion.VideoID, "")
+		// Generate new thumbnails; the best is suggested, the rest kept to A/B test
+		thumbnails, err := s.AutoOptimizeThumbnail(ctx, userID, suggestion.VideoID, "")
 		if err != nil {
 			return err
 		}
-		suggestion.SuggestedValue = thumbURL
+		suggestion.SuggestedValue = thumbnails[0].URL
+		if suggestion.Metadata == nil {
+			suggestion.Metadata = map[string]interface{}{}
+		}
+		suggestion.Metadata["thumbnailCandidates"] = thumbnails
 		
 	default:
 		return fmt.Errorf("auto-apply not supported for suggestion type: %s", suggestion.Type)
@@ -614,55 +632,52 @@ func (s *OptimizerService) ApplySuggestion(ctx context.Context, suggestion *Opti
 	return nil
 }
 
-// GetWinningContent identifies top-performing content patterns
-func (s *OptimizerService) GetWinningContent(ctx context.Context, userID string) (*WinningContentAnalysis, error) {
+// computeWinningContent identifies top-performing content patterns
+func (s *OptimizerService) computeWinningContent(ctx context.Context, userID string) (*WinningContentAnalysis, error) {
 	// Get top performing videos
-	topVideos, err := s.analyticsRepo.GetTopPerforming(userID, 20)
+	topVideos, err := s.analytics.GetTopPerforming(ctx, userID, 20)
 	if err != nil {
 		return nil, err
 	}
 
 	analysis := &WinningContentAnalysis{
-		UserID: userID,
+		UserID:     userID,
+		ComputedAt: time.Now().UTC(),
 	}
 
 	// Analyze patterns
-	titlePatterns := make(map[string]int)
+	patternCounts := make(map[string]int)
 	durationBuckets := make(map[string]int)
+	durationPerformance := make(map[string]*DurationPerformance)
 	publishDayCounts := make(map[string]int)
 
 	for _, video := range topVideos {
 		// Extract title patterns
-		if strings.Contains(video.Title, "How") {
-			titlePatterns["how-to"]++
-		}
-		if strings.Contains(video.Title, "vs") || strings.Contains(video.Title, "Versus") {
-			titlePatterns["comparison"]++
-		}
-		if strings.ContainsAny(video.Title, "0123456789") {
-			titlePatterns["listicle"]++
+		for _, pattern := range titlePatterns(video.Title) {
+			patternCounts[pattern]++
 		}
 
 		// Duration buckets
-		duration := video.AvgWatchDuration
-		switch {
-		case duration < 60:
-			durationBuckets["short_30s"]++
-		case duration < 180:
-			durationBuckets["medium_3min"]++
-		case duration < 600:
-			durationBuckets["long_10min"]++
-		default:
-			durationBuckets["extended_10min+"]++
+		bucket := durationBucket(video.AvgWatchDuration)
+		durationBuckets[bucket]++
+		perf, ok := durationPerformance[bucket]
+		if !ok {
+			perf = &DurationPerformance{}
+			durationPerformance[bucket] = perf
 		}
+		perf.add(video)
 
 		// Publish day
 		day := video.PublishDate.Weekday().String()
 		publishDayCounts[day]++
 	}
 
-	analysis.TitlePatterns = titlePatterns
+	analysis.TitlePatterns = patternCounts
 	analysis.OptimalDuration = findMaxKey(durationBuckets)
+	analysis.DurationPerformance = make(map[string]DurationPerformance, len(durationPerformance))
+	for bucket, perf := range durationPerformance {
+		analysis.DurationPerformance[bucket] = *perf
+	}
 	analysis.BestPublishDay = findMaxKey(publishDayCounts)
 
 	return analysis, nil
@@ -676,6 +691,37 @@ type WinningContentAnalysis struct {
 	BestPublishDay  string         `json:"bestPublishDay"`
 	BestCTA         string         `json:"bestCta,omitempty"`
 	TopThumbnailStyle string       `json:"topThumbnailStyle,omitempty"`
+	DurationPerformance map[string]DurationPerformance `json:"durationPerformance,omitempty"`
+	ComputedAt      time.Time      `json:"computedAt"`
+}
+
+// DurationPerformance is how the top videos in one duration bucket performed
+type DurationPerformance struct {
+	Videos        int     `json:"videos"`
+	AvgViews      float64 `json:"avgViews"`
+	AvgEngagement float64 `json:"avgEngagement"`
+}
+
+// add folds a video into the bucket's running averages
+func (p *DurationPerformance) add(video *VideoAnalytics) {
+	p.Videos++
+	p.AvgViews += (float64(video.Views) - p.AvgViews) / float64(p.Videos)
+	p.AvgEngagement += (video.EngagementRate - p.AvgEngagement) / float64(p.Videos)
+}
+
+// durationBucket groups a watch duration in seconds into the buckets of
+// OptimalDuration
+func durationBucket(seconds float64) string {
+	switch {
+	case seconds < 60:
+		return "short_30s"
+	case seconds < 180:
+		return "medium_3min"
+	case seconds < 600:
+		return "long_10min"
+	default:
+		return "extended_10min+"
+	}
 }
 
 // Helper functions
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"renderowl-api/internal/domain"
+)
+
+// ErrSuggestionNotFound is returned when a suggestion doesn't exist or belongs to another user
+var ErrSuggestionNotFound = errors.New("suggestion not found")
+
+// ErrSuggestionApplied is returned when applying a suggestion that was already applied
+var ErrSuggestionApplied = errors.New("suggestion already applied")
+
+// feedbackPriorWeight is how many neutral reactions each suggestion type starts
+// with, so a single rating can't swing its ranking on its own
+const feedbackPriorWeight = 3
+
+// SuggestionStore persists optimizer suggestions with their dismissal and feedback
+type SuggestionStore interface {
+	ListSuggestions(ctx context.Context, userID, videoID string) ([]*domain.OptimizationSuggestionRecord, error)
+	CreateSuggestions(ctx context.Context, records []*domain.OptimizationSuggestionRecord) error
+	GetSuggestion(ctx context.Context, userID, id string) (*domain.OptimizationSuggestionRecord, error)
+	UpdateSuggestion(ctx context.Context, record *domain.OptimizationSuggestionRecord) error
+	GetSuggestionFeedback(ctx context.Context, userID string) ([]*domain.SuggestionTypeFeedback, error)
+}
+
+// SetSuggestionStore sets the store used to remember dismissed and rated suggestions
+func (s *OptimizerService) SetSuggestionStore(store SuggestionStore) {
+	s.suggestionStore = store
+}
+
+// DismissSuggestion hides a suggestion from future analyses of its video
+func (s *OptimizerService) DismissSuggestion(ctx context.Context, userID, id string) (*OptimizationSuggestion, error) {
+	return s.updateSuggestion(ctx, userID, id, func(record *domain.OptimizationSuggestionRecord) {
+		now := time.Now().UTC()
+		record.Dismissed = true
+		record.DismissedAt = &now
+	})
+}
+
+// RateSuggestion records whether the user found a suggestion helpful
+func (s *OptimizerService) RateSuggestion(ctx context.Context, userID, id string, helpful bool) (*OptimizationSuggestion, error) {
+	return s.updateSuggestion(ctx, userID, id, func(record *domain.OptimizationSuggestionRecord) {
+		record.Feedback = -1
+		if helpful {
+			record.Feedback = 1
+		}
+	})
+}
+
+func (s *OptimizerService) updateSuggestion(ctx context.Context, userID, id string, update func(*domain.OptimizationSuggestionRecord)) (*OptimizationSuggestion, error) {
+	if s.suggestionStore == nil {
+		return nil, ErrSuggestionNotFound
+	}
+
+	record, err := s.suggestionStore.GetSuggestion(ctx, userID, id)
+	if err != nil {
+		return nil, ErrSuggestionNotFound
+	}
+
+	update(record)
+	if err := s.suggestionStore.UpdateSuggestion(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to update suggestion: %w", err)
+	}
+
+	return suggestionFromRecord(record)
+}
+
+// reconcileSuggestions matches freshly generated suggestions against the stored
+// ones for the video. Suggestions that are still open keep their ID and
+// feedback, dismissed ones are dropped, and new ones, including ones that were
+// applied before and are suggested again, are stored. Storage failures only
+// log, since the analysis itself is still useful.
+func (s *OptimizerService) reconcileSuggestions(ctx context.Context, userID, videoID string, suggestions []*OptimizationSuggestion) []*OptimizationSuggestion {
+	if s.suggestionStore == nil {
+		return suggestions
+	}
+
+	records, err := s.suggestionStore.ListSuggestions(ctx, userID, videoID)
+	if err != nil {
+		log.Printf("Failed to load suggestions for video %s: %v", videoID, err)
+		return suggestions
+	}
+	open := make(map[string]*domain.OptimizationSuggestionRecord, len(records))
+	dismissed := make(map[string]bool)
+	for _, record := range records {
+		switch {
+		case record.Dismissed:
+			dismissed[record.Key] = true
+		case !record.Applied:
+			open[record.Key] = record
+		}
+	}
+
+	now := time.Now().UTC()
+	var kept []*OptimizationSuggestion
+	var created []*domain.OptimizationSuggestionRecord
+	for _, suggestion := range suggestions {
+		key := suggestionKey(suggestion)
+		if dismissed[key] {
+			continue
+		}
+		if record, ok := open[key]; ok {
+			suggestion.ID = record.ID
+			suggestion.Feedback = record.Feedback
+			suggestion.CreatedAt = record.CreatedAt
+			kept = append(kept, suggestion)
+
+			// Refresh the stored copy, since current values may have changed
+			record.LastSuggestedAt = &now
+			if data, err := json.Marshal(suggestion); err == nil {
+				record.Suggestion = data
+			}
+			if err := s.suggestionStore.UpdateSuggestion(ctx, record); err != nil {
+				log.Printf("Failed to update suggestion %s: %v", record.ID, err)
+			}
+			continue
+		}
+
+		data, err := json.Marshal(suggestion)
+		if err != nil {
+			log.Printf("Failed to marshal suggestion %q: %v", key, err)
+			kept = append(kept, suggestion)
+			continue
+		}
+		created = append(created, &domain.OptimizationSuggestionRecord{
+			ID:              suggestion.ID,
+			UserID:          userID,
+			VideoID:         videoID,
+			Key:             key,
+			Type:            string(suggestion.Type),
+			Suggestion:      data,
+			LastSuggestedAt: &now,
+		})
+		open[key] = created[len(created)-1]
+		kept = append(kept, suggestion)
+	}
+
+	if err := s.suggestionStore.CreateSuggestions(ctx, created); err != nil {
+		log.Printf("Failed to store suggestions for video %s: %v", videoID, err)
+	}
+
+	return kept
+}
+
+// SuggestionHistory is a video's stored suggestions, split into the ones still
+// open and the ones already applied or dismissed, newest first
+type SuggestionHistory struct {
+	VideoID string                    `json:"videoId"`
+	Current []*OptimizationSuggestion `json:"current"`
+	History []*OptimizationSuggestion `json:"history"`
+}
+
+// GetSuggestionHistory returns every suggestion made for a video. Applied
+// suggestions carry their result so far: the video's metrics when the
+// suggestion was applied compared with its metrics now.
+func (s *OptimizerService) GetSuggestionHistory(ctx context.Context, userID, videoID string) (*SuggestionHistory, error) {
+	history := &SuggestionHistory{
+		VideoID: videoID,
+		Current: []*OptimizationSuggestion{},
+		History: []*OptimizationSuggestion{},
+	}
+	if s.suggestionStore == nil {
+		return history, nil
+	}
+
+	records, err := s.suggestionStore.ListSuggestions(ctx, userID, videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suggestions: %w", err)
+	}
+
+	var current *VideoAnalytics
+	for _, record := range records {
+		suggestion, err := suggestionFromRecord(record)
+		if err != nil {
+			log.Printf("Skipping suggestion %s: %v", record.ID, err)
+			continue
+		}
+		if !suggestion.Applied && !suggestion.Dismissed {
+			history.Current = append(history.Current, suggestion)
+			continue
+		}
+
+		if suggestion.Applied && suggestion.Result != nil && suggestion.Result.BeforeMetrics != nil {
+			if current == nil {
+				if current, err = s.analytics.GetVideoAnalytics(ctx, userID, videoID, 30); err != nil {
+					log.Printf("Failed to get analytics for video %s: %v", videoID, err)
+				}
+			}
+			if current != nil {
+				measureOptimizationResult(suggestion.Result, current)
+			}
+		}
+		history.History = append(history.History, suggestion)
+	}
+
+	return history, nil
+}
+
+// MarkSuggestionApplied applies a stored suggestion, automatically when the
+// optimizer can, and records the video's metrics at that point so the result
+// can be measured later. Suggestions that can't be auto-applied are taken to
+// have been applied by hand.
+func (s *OptimizerService) MarkSuggestionApplied(ctx context.Context, userID, id string) (*OptimizationSuggestion, error) {
+	if s.suggestionStore == nil {
+		return nil, ErrSuggestionNotFound
+	}
+
+	record, err := s.suggestionStore.GetSuggestion(ctx, userID, id)
+	if err != nil {
+		return nil, ErrSuggestionNotFound
+	}
+	suggestion, err := suggestionFromRecord(record)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.Applied {
+		return nil, ErrSuggestionApplied
+	}
+
+	if suggestion.AutoApplicable {
+		if err := s.ApplySuggestion(ctx, userID, suggestion); err != nil {
+			return nil, fmt.Errorf("failed to apply suggestion: %w", err)
+		}
+	} else {
+		now := time.Now()
+		suggestion.Applied = true
+		suggestion.AppliedAt = &now
+	}
+
+	before, err := s.analytics.GetVideoAnalytics(ctx, userID, record.VideoID, 30)
+	if err != nil {
+		log.Printf("Failed to snapshot analytics for video %s: %v", record.VideoID, err)
+	} else {
+		suggestion.Result = &OptimizationResult{BeforeMetrics: before}
+	}
+
+	data, err := json.Marshal(suggestion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal suggestion: %w", err)
+	}
+	record.Suggestion = data
+	record.Applied = true
+	record.AppliedAt = suggestion.AppliedAt
+	if err := s.suggestionStore.UpdateSuggestion(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to update suggestion: %w", err)
+	}
+
+	return suggestion, nil
+}
+
+// measureOptimizationResult compares a video's current metrics with the ones
+// recorded when a suggestion was applied
+func measureOptimizationResult(result *OptimizationResult, after *VideoAnalytics) {
+	before := result.BeforeMetrics
+	result.AfterMetrics = after
+	result.ViewsIncrease = after.Views - before.Views
+	result.CTRChange = after.CTR - before.CTR
+	result.EngagementChange = after.EngagementRate - before.EngagementRate
+	if before.EngagementRate > 0 {
+		result.Improvement = result.EngagementChange / before.EngagementRate * 100
+	}
+}
+
+// suggestionTypeWeights turns a user's feedback into a ranking weight per
+// suggestion type, between 0.5 for types that are always dismissed or rated
+// unhelpful and 1.5 for types that are always rated helpful
+func (s *OptimizerService) suggestionTypeWeights(ctx context.Context, userID string) map[SuggestionType]float64 {
+	weights := make(map[SuggestionType]float64)
+	if s.suggestionStore == nil {
+		return weights
+	}
+
+	feedback, err := s.suggestionStore.GetSuggestionFeedback(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load suggestion feedback for user %s: %v", userID, err)
+		return weights
+	}
+
+	for _, f := range feedback {
+		score := float64(f.Helpful - f.Unhelpful)
+		weights[SuggestionType(f.Type)] = 1 + 0.5*score/float64(f.Total+feedbackPriorWeight)
+	}
+	return weights
+}
+
+// rankSuggestions sorts suggestions by priority and expected impact, scaled
+// by how the user has received each suggestion type so far
+func (s *OptimizerService) rankSuggestions(ctx context.Context, userID string, suggestions []*OptimizationSuggestion) {
+	priorityOrder := map[Priority]float64{
+		PriorityHigh:   3,
+		PriorityMedium: 2,
+		PriorityLow:    1,
+	}
+	weights := s.suggestionTypeWeights(ctx, userID)
+	weight := func(t SuggestionType) float64 {
+		if w, ok := weights[t]; ok {
+			return w
+		}
+		return 1
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		wi, wj := weight(suggestions[i].Type), weight(suggestions[j].Type)
+		pi, pj := priorityOrder[suggestions[i].Priority]*wi, priorityOrder[suggestions[j].Priority]*wj
+		if pi != pj {
+			return pi > pj
+		}
+		return suggestions[i].ExpectedImpact*wi > suggestions[j].ExpectedImpact*wj
+	})
+}
+
+// suggestionKey identifies a suggestion across analyses of the same video
+func suggestionKey(suggestion *OptimizationSuggestion) string {
+	return string(suggestion.Type) + ":" + suggestion.Title
+}
+
+func suggestionFromRecord(record *domain.OptimizationSuggestionRecord) (*OptimizationSuggestion, error) {
+	var suggestion OptimizationSuggestion
+	if err := json.Unmarshal(record.Suggestion, &suggestion); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal suggestion: %w", err)
+	}
+	suggestion.ID = record.ID
+	suggestion.Dismissed = record.Dismissed
+	suggestion.Feedback = record.Feedback
+	suggestion.Applied = record.Applied
+	suggestion.AppliedAt = record.AppliedAt
+	suggestion.CreatedAt = record.CreatedAt
+	return &suggestion, nil
+}
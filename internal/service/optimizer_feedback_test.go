@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"renderowl-api/internal/domain"
+)
+
+// fakeSuggestionStore keeps suggestion records in memory, handing out copies
+// like a database would
+type fakeSuggestionStore struct {
+	records map[string]*domain.OptimizationSuggestionRecord
+}
+
+func newFakeSuggestionStore() *fakeSuggestionStore {
+	return &fakeSuggestionStore{records: map[string]*domain.OptimizationSuggestionRecord{}}
+}
+
+func (f *fakeSuggestionStore) ListSuggestions(ctx context.Context, userID, videoID string) ([]*domain.OptimizationSuggestionRecord, error) {
+	var records []*domain.OptimizationSuggestionRecord
+	for _, record := range f.records {
+		if record.UserID == userID && record.VideoID == videoID {
+			copied := *record
+			records = append(records, &copied)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.After(records[j].CreatedAt) })
+	return records, nil
+}
+
+func (f *fakeSuggestionStore) CreateSuggestions(ctx context.Context, records []*domain.OptimizationSuggestionRecord) error {
+	for _, record := range records {
+		copied := *record
+		if copied.CreatedAt.IsZero() {
+			copied.CreatedAt = time.Now()
+		}
+		f.records[copied.ID] = &copied
+	}
+	return nil
+}
+
+func (f *fakeSuggestionStore) GetSuggestion(ctx context.Context, userID, id string) (*domain.OptimizationSuggestionRecord, error) {
+	record, ok := f.records[id]
+	if !ok || record.UserID != userID {
+		return nil, errors.New("record not found")
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (f *fakeSuggestionStore) UpdateSuggestion(ctx context.Context, record *domain.OptimizationSuggestionRecord) error {
+	copied := *record
+	f.records[copied.ID] = &copied
+	return nil
+}
+
+func (f *fakeSuggestionStore) GetSuggestionFeedback(ctx context.Context, userID string) ([]*domain.SuggestionTypeFeedback, error) {
+	byType := map[string]*domain.SuggestionTypeFeedback{}
+	for _, record := range f.records {
+		if record.UserID != userID {
+			continue
+		}
+		feedback, ok := byType[record.Type]
+		if !ok {
+			feedback = &domain.SuggestionTypeFeedback{Type: record.Type}
+			byType[record.Type] = feedback
+		}
+		feedback.Total++
+		switch {
+		case record.Feedback > 0:
+			feedback.Helpful++
+		case record.Feedback < 0, record.Dismissed:
+			feedback.Unhelpful++
+		}
+	}
+	var results []*domain.SuggestionTypeFeedback
+	for _, feedback := range byType {
+		results = append(results, feedback)
+	}
+	return results, nil
+}
+
+// fakeVideoAnalytics serves fixed analytics per user and video
+type fakeVideoAnalytics struct {
+	videos map[string]map[string]*VideoAnalytics
+}
+
+func (f *fakeVideoAnalytics) GetVideoAnalytics(ctx context.Context, userID, videoID string, days int) (*VideoAnalytics, error) {
+	video, ok := f.videos[userID][videoID]
+	if !ok {
+		return nil, domain.ErrVideoPerformanceNotFound
+	}
+	copied := *video
+	return &copied, nil
+}
+
+func (f *fakeVideoAnalytics) GetTopPerforming(ctx context.Context, userID string, limit int) ([]*VideoAnalytics, error) {
+	return f.list(userID), nil
+}
+
+func (f *fakeVideoAnalytics) GetUnderperforming(ctx context.Context, userID string, limit int) ([]*VideoAnalytics, error) {
+	return f.list(userID), nil
+}
+
+func (f *fakeVideoAnalytics) list(userID string) []*VideoAnalytics {
+	var videos []*VideoAnalytics
+	for _, video := range f.videos[userID] {
+		copied := *video
+		videos = append(videos, &copied)
+	}
+	return videos
+}
+
+// newTestOptimizer returns an optimizer over one video of user-a whose low CTR
+// and engagement produce title, thumbnail and CTA suggestions
+func newTestOptimizer() (*OptimizerService, *fakeSuggestionStore, *fakeVideoAnalytics) {
+	analytics := &fakeVideoAnalytics{videos: map[string]map[string]*VideoAnalytics{
+		"user-a": {
+			"video-1": {
+				VideoID:          "video-1",
+				Title:            "My video",
+				Platform:         "youtube",
+				Views:            1000,
+				CTR:              2.0,
+				EngagementRate:   1.0,
+				DaysSincePublish: 30,
+			},
+		},
+	}}
+	store := newFakeSuggestionStore()
+	optimizer := NewOptimizerService(analytics, nil)
+	optimizer.SetSuggestionStore(store)
+	return optimizer, store, analytics
+}
+
+func findSuggestion(suggestions []*OptimizationSuggestion, title string) *OptimizationSuggestion {
+	for _, suggestion := range suggestions {
+		if suggestion.Title == title {
+			return suggestion
+		}
+	}
+	return nil
+}
+
+func TestAnalyzeVideoKeepsSuggestionsAcrossAnalyses(t *testing.T) {
+	optimizer, store, _ := newTestOptimizer()
+	ctx := context.Background()
+
+	first, err := optimizer.AnalyzeVideo(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("AnalyzeVideo: %v", err)
+	}
+	if len(first) == 0 {
+		t.Fatal("expected suggestions for a low CTR video")
+	}
+	if len(store.records) != len(first) {
+		t.Fatalf("stored %d suggestions, want %d", len(store.records), len(first))
+	}
+
+	second, err := optimizer.AnalyzeVideo(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("AnalyzeVideo: %v", err)
+	}
+	if len(store.records) != len(first) {
+		t.Errorf("second analysis stored %d suggestions, want %d", len(store.records), len(first))
+	}
+	for _, suggestion := range first {
+		again := findSuggestion(second, suggestion.Title)
+		if again == nil {
+			t.Errorf("suggestion %q missing from second analysis", suggestion.Title)
+			continue
+		}
+		if again.ID != suggestion.ID {
+			t.Errorf("suggestion %q changed ID from %s to %s", suggestion.Title, suggestion.ID, again.ID)
+		}
+	}
+}
+
+func TestDismissSuggestionDropsItFromLaterAnalyses(t *testing.T) {
+	optimizer, _, _ := newTestOptimizer()
+	ctx := context.Background()
+
+	suggestions, err := optimizer.AnalyzeVideo(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("AnalyzeVideo: %v", err)
+	}
+	dismissed := suggestions[0]
+
+	result, err := optimizer.DismissSuggestion(ctx, "user-a", dismissed.ID)
+	if err != nil {
+		t.Fatalf("DismissSuggestion: %v", err)
+	}
+	if !result.Dismissed {
+		t.Error("DismissSuggestion returned a suggestion that isn't dismissed")
+	}
+
+	again, err := optimizer.AnalyzeVideo(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("AnalyzeVideo: %v", err)
+	}
+	if findSuggestion(again, dismissed.Title) != nil {
+		t.Errorf("dismissed suggestion %q came back", dismissed.Title)
+	}
+	if len(again) != len(suggestions)-1 {
+		t.Errorf("got %d suggestions after dismissing one of %d", len(again), len(suggestions))
+	}
+}
+
+func TestSuggestionsOfOtherUsersAreNotFound(t *testing.T) {
+	optimizer, _, _ := newTestOptimizer()
+	ctx := context.Background()
+
+	suggestions, err := optimizer.AnalyzeVideo(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("AnalyzeVideo: %v", err)
+	}
+	id := suggestions[0].ID
+
+	if _, err := optimizer.DismissSuggestion(ctx, "user-b", id); !errors.Is(err, ErrSuggestionNotFound) {
+		t.Errorf("DismissSuggestion by another user: got %v, want ErrSuggestionNotFound", err)
+	}
+	if _, err := optimizer.RateSuggestion(ctx, "user-b", id, true); !errors.Is(err, ErrSuggestionNotFound) {
+		t.Errorf("RateSuggestion by another user: got %v, want ErrSuggestionNotFound", err)
+	}
+	if _, err := optimizer.MarkSuggestionApplied(ctx, "user-b", id); !errors.Is(err, ErrSuggestionNotFound) {
+		t.Errorf("MarkSuggestionApplied by another user: got %v, want ErrSuggestionNotFound", err)
+	}
+	if _, err := optimizer.AnalyzeVideo(ctx, "user-b", "video-1"); !errors.Is(err, domain.ErrVideoPerformanceNotFound) {
+		t.Errorf("AnalyzeVideo of another user's video: got %v, want ErrVideoPerformanceNotFound", err)
+	}
+}
+
+func TestRateSuggestionWeighsItsType(t *testing.T) {
+	optimizer, _, _ := newTestOptimizer()
+	ctx := context.Background()
+
+	suggestions, err := optimizer.AnalyzeVideo(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("AnalyzeVideo: %v", err)
+	}
+	var cta *OptimizationSuggestion
+	for _, suggestion := range suggestions {
+		if suggestion.Type == SuggestionTypeCTA {
+			cta = suggestion
+		}
+	}
+	if cta == nil {
+		t.Fatal("expected a CTA suggestion for a low engagement video")
+	}
+
+	rated, err := optimizer.RateSuggestion(ctx, "user-a", cta.ID, true)
+	if err != nil {
+		t.Fatalf("RateSuggestion: %v", err)
+	}
+	if rated.Feedback != 1 {
+		t.Errorf("feedback = %d, want 1", rated.Feedback)
+	}
+
+	weights := optimizer.suggestionTypeWeights(ctx, "user-a")
+	if weights[SuggestionTypeCTA] <= 1 {
+		t.Errorf("CTA weight = %v after a helpful rating, want above 1", weights[SuggestionTypeCTA])
+	}
+}
+
+func TestMarkSuggestionAppliedOnlyOnce(t *testing.T) {
+	optimizer, _, _ := newTestOptimizer()
+	ctx := context.Background()
+
+	suggestions, err := optimizer.AnalyzeVideo(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("AnalyzeVideo: %v", err)
+	}
+	var manual *OptimizationSuggestion
+	for _, suggestion := range suggestions {
+		if !suggestion.AutoApplicable {
+			manual = suggestion
+			break
+		}
+	}
+	if manual == nil {
+		t.Fatal("expected a suggestion that is applied by hand")
+	}
+
+	applied, err := optimizer.MarkSuggestionApplied(ctx, "user-a", manual.ID)
+	if err != nil {
+		t.Fatalf("MarkSuggestionApplied: %v", err)
+	}
+	if !applied.Applied || applied.AppliedAt == nil {
+		t.Error("suggestion not marked applied")
+	}
+	if applied.Result == nil || applied.Result.BeforeMetrics == nil {
+		t.Error("applied suggestion has no metrics to measure against")
+	}
+
+	if _, err := optimizer.MarkSuggestionApplied(ctx, "user-a", manual.ID); !errors.Is(err, ErrSuggestionApplied) {
+		t.Errorf("second MarkSuggestionApplied: got %v, want ErrSuggestionApplied", err)
+	}
+}
+
+func TestGetSuggestionHistoryMeasuresAppliedSuggestions(t *testing.T) {
+	optimizer, _, analytics := newTestOptimizer()
+	ctx := context.Background()
+
+	suggestions, err := optimizer.AnalyzeVideo(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("AnalyzeVideo: %v", err)
+	}
+	if len(suggestions) < 3 {
+		t.Fatalf("got %d suggestions, want at least 3", len(suggestions))
+	}
+	var applied, dismissed *OptimizationSuggestion
+	for _, suggestion := range suggestions {
+		switch {
+		case applied == nil && !suggestion.AutoApplicable:
+			applied = suggestion
+		case dismissed == nil:
+			dismissed = suggestion
+		}
+	}
+	if _, err := optimizer.MarkSuggestionApplied(ctx, "user-a", applied.ID); err != nil {
+		t.Fatalf("MarkSuggestionApplied: %v", err)
+	}
+	if _, err := optimizer.DismissSuggestion(ctx, "user-a", dismissed.ID); err != nil {
+		t.Fatalf("DismissSuggestion: %v", err)
+	}
+
+	// The video does better after the change
+	analytics.videos["user-a"]["video-1"].Views = 1500
+	analytics.videos["user-a"]["video-1"].EngagementRate = 2.0
+
+	history, err := optimizer.GetSuggestionHistory(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("GetSuggestionHistory: %v", err)
+	}
+	if len(history.Current) != len(suggestions)-2 {
+		t.Errorf("got %d current suggestions, want %d", len(history.Current), len(suggestions)-2)
+	}
+	if len(history.History) != 2 {
+		t.Fatalf("got %d past suggestions, want 2", len(history.History))
+	}
+
+	past := findSuggestion(history.History, applied.Title)
+	if past == nil || past.Result == nil {
+		t.Fatalf("applied suggestion %q has no result in history", applied.Title)
+	}
+	if past.Result.ViewsIncrease != 500 {
+		t.Errorf("views increase = %d, want 500", past.Result.ViewsIncrease)
+	}
+	if past.Result.Improvement != 100 {
+		t.Errorf("improvement = %v%%, want 100%%", past.Result.Improvement)
+	}
+	if findSuggestion(history.History, dismissed.Title) == nil {
+		t.Errorf("dismissed suggestion %q missing from history", dismissed.Title)
+	}
+
+	other, err := optimizer.GetSuggestionHistory(ctx, "user-b", "video-1")
+	if err != nil {
+		t.Fatalf("GetSuggestionHistory by another user: %v", err)
+	}
+	if len(other.Current) != 0 || len(other.History) != 0 {
+		t.Error("another user can see the video's suggestions")
+	}
+}
+
+// fakeThumbnails returns thumbnails with fixed URLs, best first
+type fakeThumbnails struct {
+	titles []string
+}
+
+func (f *fakeThumbnails) GenerateAIThumbnails(ctx context.Context, sourceID, title string, keywords []string, count int) ([]ThumbnailVariation, error) {
+	f.titles = append(f.titles, title)
+	var thumbnails []ThumbnailVariation
+	for i := range count {
+		thumbnails = append(thumbnails, ThumbnailVariation{
+			SourceID: sourceID,
+			URL:      fmt.Sprintf("https://cdn.example.com/%s/%d.jpg", sourceID, i),
+		})
+	}
+	return thumbnails, nil
+}
+
+func TestMarkSuggestionAppliedGeneratesThumbnails(t *testing.T) {
+	optimizer, _, _ := newTestOptimizer()
+	thumbnails := &fakeThumbnails{}
+	optimizer.SetThumbnailGenerator(thumbnails)
+	ctx := context.Background()
+
+	suggestions, err := optimizer.AnalyzeVideo(ctx, "user-a", "video-1")
+	if err != nil {
+		t.Fatalf("AnalyzeVideo: %v", err)
+	}
+	var thumbnail *OptimizationSuggestion
+	for _, suggestion := range suggestions {
+		if suggestion.Type == SuggestionTypeThumbnail {
+			thumbnail = suggestion
+		}
+	}
+	if thumbnail == nil || !thumbnail.AutoApplicable {
+		t.Fatal("expected an auto-applicable thumbnail suggestion for a low CTR video")
+	}
+
+	applied, err := optimizer.MarkSuggestionApplied(ctx, "user-a", thumbnail.ID)
+	if err != nil {
+		t.Fatalf("MarkSuggestionApplied: %v", err)
+	}
+	if applied.SuggestedValue != "https://cdn.example.com/video-1/0.jpg" {
+		t.Errorf("suggested thumbnail = %q, want the best candidate", applied.SuggestedValue)
+	}
+	if len(thumbnails.titles) != 1 || thumbnails.titles[0] != "My video" {
+		t.Errorf("thumbnails generated for titles %q, want the video's title", thumbnails.titles)
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Layout constants for the PDF report (millimetres, A4 portrait)
+const (
+	pdfMargin         = 15.0
+	pdfContentWidth   = 180.0
+	pdfRowHeight      = 7.0
+	pdfSparklineWidth = 40.0
+)
+
+// RenderReportPDF renders a performance report as a PDF document
+func RenderReportPDF(report *PerformanceReport) ([]byte, error) {
+	if report == nil {
+		return nil, fmt.Errorf("report is required")
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-10)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	// Latin-1 fonts only; translate UTF-8 titles so they render instead of mojibake
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	// Header
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(0, 10, "Performance Report", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(96, 96, 96)
+	pdf.CellFormat(0, 6, fmt.Sprintf("%s (%s - %s)", report.Period,
+		report.StartDate.Format("Jan 2, 2006"), report.EndDate.Format("Jan 2, 2006")), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, "Generated "+report.GeneratedAt.Format("Jan 2, 2006 15:04 MST"), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	// Summary
+	if report.Summary != nil {
+		pdfSectionTitle(pdf, "Summary")
+		summary := [][2]string{
+			{"Total views", fmt.Sprintf("%d", report.Summary.TotalViews)},
+			{"Total likes", fmt.Sprintf("%d", report.Summary.TotalLikes)},
+			{"Total comments", fmt.Sprintf("%d", report.Summary.TotalComments)},
+			{"Total shares", fmt.Sprintf("%d", report.Summary.TotalShares)},
+			{"Avg engagement rate", fmt.Sprintf("%.1f%%", report.Summary.AvgEngagementRate)},
+			{"Avg CTR", fmt.Sprintf("%.1f%%", report.Summary.AvgCTR)},
+		}
+		pdf.SetFont("Helvetica", "", 10)
+		for _, row := range summary {
+			pdf.CellFormat(60, pdfRowHeight, row[0], "", 0, "L", false, 0, "")
+			pdf.CellFormat(0, pdfRowHeight, row[1], "", 1, "L", false, 0, "")
+		}
+		pdf.Ln(4)
+	}
+
+	pdfVideoTable(pdf, tr, "Top Videos", report.TopVideos)
+	pdfVideoTable(pdf, tr, "Underperforming Videos", report.Underperforming)
+
+	// Trends
+	if len(report.Trends) > 0 {
+		pdfSectionTitle(pdf, "Trends")
+		for _, trend := range report.Trends {
+			pdf.SetFont("Helvetica", "B", 10)
+			pdf.CellFormat(0, pdfRowHeight, tr(trend.Topic), "", 1, "L", false, 0, "")
+			pdf.SetFont("Helvetica", "", 9)
+			pdf.CellFormat(0, 5, fmt.Sprintf("Your avg views: %.0f   Market avg: %.0f   Opportunity: %.0f",
+				trend.YourPerformance, trend.MarketAverage, trend.Opportunity), "", 1, "L", false, 0, "")
+			if trend.Recommendation != "" {
+				pdf.MultiCell(0, 5, tr(trend.Recommendation), "", "L", false)
+			}
+			pdf.Ln(2)
+		}
+		pdf.Ln(2)
+	}
+
+	// Comment sentiment
+	if len(report.Sentiment) > 0 {
+		pdfSectionTitle(pdf, "Comment Sentiment")
+		pdf.SetFont("Helvetica", "", 9)
+		for _, sentiment := range report.Sentiment {
+			pdf.CellFormat(0, 5, fmt.Sprintf("%s (%s): %d comments - %d positive, %d negative, %d neutral (score %.2f)",
+				sentiment.VideoID, sentiment.Platform, sentiment.Total, sentiment.Positive, sentiment.Negative,
+				sentiment.Neutral, sentiment.Score), "", 1, "L", false, 0, "")
+			if len(sentiment.Themes) > 0 {
+				pdf.MultiCell(0, 5, tr("Themes: "+strings.Join(sentiment.Themes, ", ")), "", "L", false)
+			}
+			pdf.Ln(1)
+		}
+		pdf.Ln(3)
+	}
+
+	// Suggestions
+	if len(report.Suggestions) > 0 {
+		pdfSectionTitle(pdf, "Suggestions")
+		for _, suggestion := range report.Suggestions {
+			pdf.SetFont("Helvetica", "B", 10)
+			pdf.CellFormat(0, pdfRowHeight, tr(fmt.Sprintf("[%s] %s", suggestion.Priority, suggestion.Title)), "", 1, "L", false, 0, "")
+			pdf.SetFont("Helvetica", "", 9)
+			pdf.MultiCell(0, 5, tr(suggestion.Description), "", "L", false)
+			pdf.CellFormat(0, 5, fmt.Sprintf("Expected impact: +%.0f%%   Confidence: %.0f%%",
+				suggestion.ExpectedImpact, suggestion.Confidence*100), "", 1, "L", false, 0, "")
+			pdf.Ln(2)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render report PDF: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// pdfSectionTitle writes a section heading with an underline
+func pdfSectionTitle(pdf *fpdf.Fpdf, title string) {
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+	x, y := pdf.GetXY()
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.Line(x, y, x+pdfContentWidth, y)
+	pdf.Ln(2)
+}
+
+// pdfVideoTable writes a table of videos with a sparkline of daily views per row
+func pdfVideoTable(pdf *fpdf.Fpdf, tr func(string) string, title string, videos []*VideoAnalytics) {
+	if len(videos) == 0 {
+		return
+	}
+
+	pdfSectionTitle(pdf, title)
+
+	widths := []float64{70, 22, 22, 22, pdfSparklineWidth + 4}
+	headers := []string{"Title", "Views", "CTR", "Engagement", "Daily views"}
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(240, 240, 240)
+	for i, header := range headers {
+		pdf.CellFormat(widths[i], pdfRowHeight, header, "", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, video := range videos {
+		videoTitle := tr(video.Title)
+		for pdf.GetStringWidth(videoTitle) > widths[0]-2 && len(videoTitle) > 3 {
+			videoTitle = videoTitle[:len(videoTitle)-4] + "..."
+		}
+
+		pdf.CellFormat(widths[0], pdfRowHeight, videoTitle, "B", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], pdfRowHeight, fmt.Sprintf("%d", video.Views), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], pdfRowHeight, fmt.Sprintf("%.1f%%", video.CTR), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], pdfRowHeight, fmt.Sprintf("%.1f%%", video.EngagementRate), "B", 0, "L", false, 0, "")
+
+		x, y := pdf.GetXY()
+		pdf.CellFormat(widths[4], pdfRowHeight, "", "B", 1, "L", false, 0, "")
+		pdfSparkline(pdf, x+2, y+1, pdfSparklineWidth, pdfRowHeight-2, video.DailyViews)
+	}
+	pdf.Ln(4)
+}
+
+// pdfSparkline draws a minimal line chart of values inside the given box
+func pdfSparkline(pdf *fpdf.Fpdf, x, y, w, h float64, values []int) {
+	if len(values) < 2 {
+		return
+	}
+
+	minVal, maxVal := values[0], values[0]
+	for _, v := range values {
+		if v < minVal {
+			minVal = v
+		}
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+
+	span := float64(maxVal - minVal)
+	step := w / float64(len(values)-1)
+	pointY := func(v int) float64 {
+		if span == 0 {
+			return y + h/2
+		}
+		return y + h - (float64(v-minVal)/span)*h
+	}
+
+	pdf.SetDrawColor(37, 99, 235)
+	pdf.SetLineWidth(0.3)
+	for i := 1; i < len(values); i++ {
+		pdf.Line(x+step*float64(i-1), pointY(values[i-1]), x+step*float64(i), pointY(values[i]))
+	}
+	pdf.SetLineWidth(0.2)
+	pdf.SetDrawColor(0, 0, 0)
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sentiment labels produced by comment classification
+const (
+	SentimentPositive = "positive"
+	SentimentNegative = "negative"
+	SentimentNeutral  = "neutral"
+)
+
+const (
+	sentimentBatchSize   = 50
+	sentimentMaxComments = 200
+	sentimentMaxThemes   = 5
+	sentimentCacheTTL    = 6 * time.Hour
+	sentimentCacheSize   = 1024
+)
+
+// VideoComment represents a single viewer comment on a published video
+type VideoComment struct {
+	ID          string    `json:"id"`
+	Author      string    `json:"author"`
+	Text        string    `json:"text"`
+	Likes       int       `json:"likes"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Sentiment   string    `json:"sentiment,omitempty"`
+}
+
+// SentimentAnalysis summarizes the sentiment of a video's comments
+type SentimentAnalysis struct {
+	VideoID    string    `json:"videoId"`
+	Platform   string    `json:"platform"`
+	Total      int       `json:"total"`
+	Positive   int       `json:"positive"`
+	Negative   int       `json:"negative"`
+	Neutral    int       `json:"neutral"`
+	Score      float64   `json:"score"` // -1 (all negative) to 1 (all positive)
+	Themes     []string  `json:"themes"`
+	AnalyzedAt time.Time `json:"analyzedAt"`
+}
+
+// CommentFetcher retrieves comments for a video on a single platform
+type CommentFetcher interface {
+	FetchComments(ctx context.Context, videoID string, limit int) ([]*VideoComment, error)
+}
+
+// SetCommentFetcher registers the comment fetcher used for a platform
+func (s *OptimizerService) SetCommentFetcher(platform string, fetcher CommentFetcher) {
+	s.commentFetchers[platform] = fetcher
+}
+
+// FetchComments retrieves comments for a video from its platform, if supported
+func (s *OptimizerService) FetchComments(ctx context.Context, videoID, platform string) ([]*VideoComment, error) {
+	fetcher, ok := s.commentFetchers[platform]
+	if !ok {
+		return nil, fmt.Errorf("comments not available for platform: %s", platform)
+	}
+
+	return fetcher.FetchComments(ctx, videoID, sentimentMaxComments)
+}
+
+// AnalyzeSentiment classifies a video's comments and returns a sentiment breakdown
+func (s *OptimizerService) AnalyzeSentiment(ctx context.Context, videoID, platform string) (*SentimentAnalysis, error) {
+	cacheKey := platform + ":" + videoID
+	s.sentimentMu.RLock()
+	cached, ok := s.sentimentCache[cacheKey]
+	s.sentimentMu.RUnlock()
+	if ok && time.Since(cached.AnalyzedAt) < sentimentCacheTTL {
+		return cached, nil
+	}
+
+	if s.aiScriptService == nil {
+		return nil, fmt.Errorf("AI provider not configured")
+	}
+
+	comments, err := s.FetchComments(ctx, videoID, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &SentimentAnalysis{
+		VideoID:    videoID,
+		Platform:   platform,
+		Themes:     []string{},
+		AnalyzedAt: time.Now(),
+	}
+
+	themeCounts := make(map[string]int)
+	for start := 0; start < len(comments); start += sentimentBatchSize {
+		end := start + sentimentBatchSize
+		if end > len(comments) {
+			end = len(comments)
+		}
+
+		themes, err := s.classifyComments(ctx, comments[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to classify comments: %w", err)
+		}
+		for _, theme := range themes {
+			themeCounts[strings.ToLower(strings.TrimSpace(theme))]++
+		}
+	}
+
+	for _, comment := range comments {
+		switch comment.Sentiment {
+		case SentimentPositive:
+			analysis.Positive++
+		case SentimentNegative:
+			analysis.Negative++
+		default:
+			analysis.Neutral++
+		}
+	}
+	analysis.Total = len(comments)
+	if analysis.Total > 0 {
+		analysis.Score = float64(analysis.Positive-analysis.Negative) / float64(analysis.Total)
+	}
+	analysis.Themes = topThemes(themeCounts, sentimentMaxThemes)
+
+	s.cacheSentiment(cacheKey, analysis)
+
+	return analysis, nil
+}
+
+// cacheSentiment stores an analysis, dropping expired analyses once the cache
+// is full and any analysis if none have expired
+func (s *OptimizerService) cacheSentiment(key string, analysis *SentimentAnalysis) {
+	s.sentimentMu.Lock()
+	defer s.sentimentMu.Unlock()
+
+	if len(s.sentimentCache) >= sentimentCacheSize {
+		for cachedKey, cached := range s.sentimentCache {
+			if time.Since(cached.AnalyzedAt) >= sentimentCacheTTL {
+				delete(s.sentimentCache, cachedKey)
+			}
+		}
+	}
+	if len(s.sentimentCache) >= sentimentCacheSize {
+		// Any entry will do; evicted videos are just analyzed again
+		for cachedKey := range s.sentimentCache {
+			delete(s.sentimentCache, cachedKey)
+			break
+		}
+	}
+	s.sentimentCache[key] = analysis
+}
+
+// GetVideoSentiment analyzes comment sentiment for a user's video on the platform it was published to
+func (s *OptimizerService) GetVideoSentiment(ctx context.Context, userID, videoID string) (*SentimentAnalysis, error) {
+	analytics, err := s.analytics.GetVideoAnalytics(ctx, userID, videoID, 30)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video analytics: %w", err)
+	}
+
+	return s.AnalyzeSentiment(ctx, videoID, analytics.Platform)
+}
+
+// classifyComments labels a batch of comments in place with a single AI request and returns the themes it found
+func (s *OptimizerService) classifyComments(ctx context.Context, comments []*VideoComment) ([]string, error) {
+	systemPrompt := `You classify the sentiment of video comments.
+
+For each numbered comment decide whether it is "positive", "negative" or "neutral" towards the video.
+Also list up to 5 short recurring themes (2-4 words each) across all comments.
+
+Respond ONLY with a valid JSON object in this exact format:
+{
+  "results": [{"index": 1, "sentiment": "positive"}],
+  "themes": ["audio quality", "great editing"]
+}`
+
+	var b strings.Builder
+	for i, comment := range comments {
+		text := strings.ReplaceAll(comment.Text, "\n", " ")
+		fmt.Fprintf(&b, "%d. %s\n", i+1, text)
+	}
+
+	content, err := s.aiScriptService.CompleteJSON(ctx, systemPrompt, b.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Results []struct {
+			Index     int    `json:"index"`
+			Sentiment string `json:"sentiment"`
+		} `json:"results"`
+		Themes []string `json:"themes"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse sentiment JSON: %w", err)
+	}
+
+	for _, r := range result.Results {
+		if r.Index < 1 || r.Index > len(comments) {
+			continue
+		}
+		switch label := strings.ToLower(r.Sentiment); label {
+		case SentimentPositive, SentimentNegative, SentimentNeutral:
+			comments[r.Index-1].Sentiment = label
+		}
+	}
+
+	return result.Themes, nil
+}
+
+// analyzeSentiment turns a comment sentiment breakdown into suggestions
+func (s *OptimizerService) analyzeSentiment(ctx context.Context, analytics *VideoAnalytics) []*OptimizationSuggestion {
+	var suggestions []*OptimizationSuggestion
+
+	sentiment, err := s.AnalyzeSentiment(ctx, analytics.VideoID, analytics.Platform)
+	if err != nil || sentiment.Total == 0 {
+		return suggestions
+	}
+
+	negativeShare := float64(sentiment.Negative) / float64(sentiment.Total)
+	if negativeShare >= 0.3 {
+		description := fmt.Sprintf("%.0f%% of comments are negative.", negativeShare*100)
+		if len(sentiment.Themes) > 0 {
+			description += fmt.Sprintf(" Recurring themes: %s.", strings.Join(sentiment.Themes, ", "))
+		}
+		description += " Address the most common complaints in a pinned comment or your next video."
+
+		suggestions = append(suggestions, &OptimizationSuggestion{
+			ID:             uuid.New().String(),
+			VideoID:        analytics.VideoID,
+			Type:           SuggestionTypeContent,
+			Priority:       PriorityHigh,
+			Title:          "Respond to Negative Feedback",
+			Description:    description,
+			ExpectedImpact: 10.0,
+			Confidence:     0.70,
+			AutoApplicable: false,
+			Metadata: map[string]interface{}{
+				"sentiment": sentiment,
+			},
+			CreatedAt: time.Now(),
+		})
+	}
+
+	return suggestions
+}
+
+// topThemes returns the most frequent themes, most common first
+func topThemes(counts map[string]int, limit int) []string {
+	themes := make([]string, 0, len(counts))
+	for theme := range counts {
+		if theme != "" {
+			themes = append(themes, theme)
+		}
+	}
+
+	sort.Slice(themes, func(i, j int) bool {
+		if counts[themes[i]] != counts[themes[j]] {
+			return counts[themes[i]] > counts[themes[j]]
+		}
+		return themes[i] < themes[j]
+	})
+
+	if len(themes) > limit {
+		themes = themes[:limit]
+	}
+	return themes
+}
+
+// YouTubeCommentFetcher fetches comments through the YouTube Data API
+type YouTubeCommentFetcher struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewYouTubeCommentFetcher creates a new YouTube comment fetcher
+func NewYouTubeCommentFetcher(apiKey string) *YouTubeCommentFetcher {
+	return &YouTubeCommentFetcher{
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// FetchComments retrieves the most relevant top-level comments for a video
+func (f *YouTubeCommentFetcher) FetchComments(ctx context.Context, videoID string, limit int) ([]*VideoComment, error) {
+	var comments []*VideoComment
+	pageToken := ""
+
+	for len(comments) < limit {
+		params := url.Values{
+			"part":       {"snippet"},
+			"videoId":    {videoID},
+			"order":      {"relevance"},
+			"textFormat": {"plainText"},
+			"maxResults": {"100"},
+			"key":        {f.apiKey},
+		}
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", "https://www.googleapis.com/youtube/v3/commentThreads?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := f.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("YouTube API error (status %d): %s", resp.StatusCode, string(body))
+		}
+
+		var result struct {
+			NextPageToken string `json:"nextPageToken"`
+			Items         []struct {
+				ID      string `json:"id"`
+				Snippet struct {
+					TopLevelComment struct {
+						Snippet struct {
+							AuthorDisplayName string    `json:"authorDisplayName"`
+							TextDisplay       string    `json:"textDisplay"`
+							LikeCount         int       `json:"likeCount"`
+							PublishedAt       time.Time `json:"publishedAt"`
+						} `json:"snippet"`
+					} `json:"topLevelComment"`
+				} `json:"snippet"`
+			} `json:"items"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode comments: %w", err)
+		}
+
+		for _, item := range result.Items {
+			snippet := item.Snippet.TopLevelComment.Snippet
+			comments = append(comments, &VideoComment{
+				ID:          item.ID,
+				Author:      snippet.AuthorDisplayName,
+				Text:        snippet.TextDisplay,
+				Likes:       snippet.LikeCount,
+				PublishedAt: snippet.PublishedAt,
+			})
+		}
+
+		if result.NextPageToken == "" {
+			break
+		}
+		pageToken = result.NextPageToken
+	}
+
+	if len(comments) > limit {
+		comments = comments[:limit]
+	}
+	return comments, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSentimentCacheStaysBounded(t *testing.T) {
+	s := &OptimizerService{sentimentCache: make(map[string]*SentimentAnalysis)}
+	expired := time.Now().Add(-sentimentCacheTTL)
+	for i := range sentimentCacheSize {
+		analyzedAt := time.Now()
+		if i%2 == 0 {
+			analyzedAt = expired
+		}
+		s.cacheSentiment(fmt.Sprintf("youtube:video-%d", i), &SentimentAnalysis{AnalyzedAt: analyzedAt})
+	}
+
+	s.cacheSentiment("youtube:new", &SentimentAnalysis{AnalyzedAt: time.Now()})
+	if got, want := len(s.sentimentCache), sentimentCacheSize/2+1; got != want {
+		t.Fatalf("after caching into a full cache: %d analyses, want %d with the expired ones dropped", got, want)
+	}
+	for key, cached := range s.sentimentCache {
+		if cached.AnalyzedAt.Equal(expired) {
+			t.Errorf("expired analysis %s is still cached", key)
+		}
+	}
+
+	for i := range sentimentCacheSize {
+		s.cacheSentiment(fmt.Sprintf("tiktok:video-%d", i), &SentimentAnalysis{AnalyzedAt: time.Now()})
+	}
+	if len(s.sentimentCache) != sentimentCacheSize {
+		t.Errorf("cache of fresh analyses holds %d, want at most %d", len(s.sentimentCache), sentimentCacheSize)
+	}
+	if _, ok := s.sentimentCache[fmt.Sprintf("tiktok:video-%d", sentimentCacheSize-1)]; !ok {
+		t.Error("the latest analysis was not cached")
+	}
+}
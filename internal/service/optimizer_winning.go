@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/scheduler"
+)
+
+// JobComputeWinningContent recomputes and caches a user's winning content analysis
+const JobComputeWinningContent = "compute-winning-content"
+
+// WinningContentCacheStore persists precomputed winning content analyses
+type WinningContentCacheStore interface {
+	GetWinningContentCache(ctx context.Context, userID string) (*domain.WinningContentCache, error)
+	SaveWinningContentCache(ctx context.Context, cache *domain.WinningContentCache) error
+}
+
+// winningContentJobData is the payload of a JobComputeWinningContent job
+type winningContentJobData struct {
+	UserID string `json:"userId"`
+}
+
+// SetWinningContentCache sets the store used to cache winning content analyses
+func (s *OptimizerService) SetWinningContentCache(store WinningContentCacheStore) {
+	s.winningCache = store
+}
+
+// RegisterJobs registers the optimizer's background job handlers
+func (s *OptimizerService) RegisterJobs(sched *scheduler.Scheduler) {
+	sched.RegisterHandler(JobComputeWinningContent, s.handleWinningContentJob)
+}
+
+// GetWinningContent returns the cached winning content analysis for a user,
+// computing it live when there is no cache yet or force is set
+func (s *OptimizerService) GetWinningContent(ctx context.Context, userID string, force bool) (*WinningContentAnalysis, error) {
+	if !force && s.winningCache != nil {
+		if cache, err := s.winningCache.GetWinningContentCache(ctx, userID); err == nil {
+			var analysis WinningContentAnalysis
+			if err := json.Unmarshal(cache.Analysis, &analysis); err == nil {
+				analysis.ComputedAt = cache.ComputedAt
+				return &analysis, nil
+			}
+		}
+	}
+
+	return s.RefreshWinningContent(ctx, userID)
+}
+
+// RefreshWinningContent computes a user's winning content analysis and caches it
+func (s *OptimizerService) RefreshWinningContent(ctx context.Context, userID string) (*WinningContentAnalysis, error) {
+	analysis, err := s.computeWinningContent(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if s.winningCache == nil {
+		return analysis, nil
+	}
+
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+	cache := &domain.WinningContentCache{
+		UserID:     userID,
+		Analysis:   data,
+		ComputedAt: analysis.ComputedAt,
+	}
+	// A stale cache is better than failing the request, so only log
+	if err := s.winningCache.SaveWinningContentCache(ctx, cache); err != nil {
+		log.Printf("Failed to cache winning content for user %s: %v", userID, err)
+	}
+
+	return analysis, nil
+}
+
+func (s *OptimizerService) handleWinningContentJob(ctx context.Context, job *scheduler.Job) error {
+	var data winningContentJobData
+	if err := json.Unmarshal(job.Data, &data); err != nil {
+		return fmt.Errorf("failed to unmarshal job data: %w", err)
+	}
+
+	_, err := s.RefreshWinningContent(ctx, data.UserID)
+	return err
+}
+
+// scheduleWinningContent queues a winning content recompute for each user,
+// if an optimizer has registered to handle it
+func scheduleWinningContent(ctx context.Context, sched *scheduler.Scheduler, userIDs []string) {
+	if !sched.HasHandler(JobComputeWinningContent) {
+		return
+	}
+
+	for _, userID := range userIDs {
+		data, _ := json.Marshal(winningContentJobData{UserID: userID})
+		job := &scheduler.Job{
+			Name: JobComputeWinningContent,
+			Data: data,
+		}
+		if err := sched.AddJob(ctx, job); err != nil {
+			log.Printf("Failed to schedule winning content for user %s: %v", userID, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"renderowl-api/internal/domain"
+	socialdomain "renderowl-api/internal/domain/social"
+)
+
+// ErrNoPostCopySource is returned when there's neither a topic nor a
+// transcript to write post copy from
+var ErrNoPostCopySource = domain.NewAppError(domain.CodeValidation, http.StatusBadRequest, "a topic is required when the video can't be transcribed")
+
+// maxPostCopyTranscript caps the transcript characters sent to the model
+const maxPostCopyTranscript = 6000
+
+// PostCopyService writes social post copy for videos, from what the video
+// says when it can be transcribed and from the topic it was given
+type PostCopyService struct {
+	ai          *AIScriptService
+	transcriber *TranscriptionService
+}
+
+// NewPostCopyService creates a post copy service. transcriber may be nil,
+// in which case copy is written from the topic alone.
+func NewPostCopyService(ai *AIScriptService, transcriber *TranscriptionService) *PostCopyService {
+	return &PostCopyService{ai: ai, transcriber: transcriber}
+}
+
+// WritePostCopy writes a title, description and hashtags for a video within
+// the request's limits. A failed transcription is logged and the topic used,
+// but a video URL on a host that isn't public is refused.
+func (s *PostCopyService) WritePostCopy(ctx context.Context, req *socialdomain.PostCopyRequest) (*socialdomain.PostCopy, error) {
+	transcript := ""
+	if s.transcriber != nil && isHTTPURL(req.VideoURL) {
+		t, err := s.transcriber.Transcribe(ctx, &TranscribeRequest{URL: req.VideoURL})
+		if errors.Is(err, ErrMediaURLNotAllowed) {
+			return nil, domain.WrapError(err, domain.CodeValidation, http.StatusBadRequest)
+		}
+		if err != nil {
+			log.Printf("Failed to transcribe %s for post copy, using the topic: %v", req.VideoURL, err)
+		} else {
+			transcript = strings.TrimSpace(t.Text)
+		}
+	}
+	if transcript == "" && strings.TrimSpace(req.Topic) == "" {
+		return nil, ErrNoPostCopySource
+	}
+
+	platforms := make([]string, 0, len(req.Platforms))
+	for _, platform := range req.Platforms {
+		platforms = append(platforms, string(platform))
+	}
+
+	var limits []string
+	if req.MaxTitle > 0 {
+		limits = append(limits, fmt.Sprintf("- title at most %d characters", req.MaxTitle))
+	}
+	if req.MaxDescription > 0 {
+		limits = append(limits, fmt.Sprintf("- description at most %d characters", req.MaxDescription))
+	}
+	if req.MaxTags > 0 {
+		limits = append(limits, fmt.Sprintf("- at most %d hashtags", req.MaxTags))
+	}
+
+	systemPrompt := fmt.Sprintf(`You are a social media copywriter writing the post for a short video going to %s.
+
+Write a title that makes people want to watch, a description of one to three sentences with a call to action, and 3 to 8 relevant hashtags without the # sign.
+Base everything on what the video is about; never invent claims it doesn't make.
+%s
+
+Respond ONLY with a valid JSON object in this exact format:
+{
+  "title": "...",
+  "description": "...",
+  "tags": ["tag", "another"]
+}`, strings.Join(platforms, ", "), strings.Join(limits, "\n"))
+
+	var b strings.Builder
+	if req.Topic != "" {
+		fmt.Fprintf(&b, "Topic: %s\n", req.Topic)
+	}
+	if transcript != "" {
+		if runes := []rune(transcript); len(runes) > maxPostCopyTranscript {
+			transcript = string(runes[:maxPostCopyTranscript])
+		}
+		fmt.Fprintf(&b, "Transcript:\n%s\n", transcript)
+	}
+
+	content, err := s.ai.CompleteJSON(ctx, systemPrompt, b.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var postCopy socialdomain.PostCopy
+	if err := json.Unmarshal([]byte(content), &postCopy); err != nil {
+		return nil, fmt.Errorf("failed to parse post copy JSON: %w", err)
+	}
+	return &postCopy, nil
+}
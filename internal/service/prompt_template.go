@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"renderowl-api/internal/domain"
+)
+
+// Kinds of system prompt users can replace with their own template
+const (
+	PromptKindScript = "script"
+	PromptKindScene  = "scene"
+)
+
+// maxPromptTemplateLength bounds a template so it can't crowd the response
+// format instructions out of the model's context
+const maxPromptTemplateLength = 4000
+
+var (
+	ErrPromptOverridesDisabled = errors.New("system prompt overrides are not enabled")
+	ErrInvalidPromptTemplate   = errors.New("invalid prompt template")
+	ErrUnknownPromptKind       = errors.New("unknown prompt kind")
+	ErrPromptTemplateNotFound  = errors.New("prompt template not found")
+)
+
+// PromptTemplateStore persists users' own system prompt templates
+type PromptTemplateStore interface {
+	ListPromptTemplates(ctx context.Context, userID string) ([]*domain.PromptTemplate, error)
+	GetPromptTemplate(ctx context.Context, userID, kind string) (*domain.PromptTemplate, error)
+	SavePromptTemplate(ctx context.Context, template *domain.PromptTemplate) error
+	DeletePromptTemplate(ctx context.Context, userID, kind string) (bool, error)
+}
+
+// PromptPlaceholders lists the {{placeholders}} a kind of template may use
+// and the ones it must
+type PromptPlaceholders struct {
+	Allowed  []string `json:"allowed"`
+	Required []string `json:"required"`
+}
+
+// promptKinds are the placeholders of each kind of template
+var promptKinds = map[string]PromptPlaceholders{
+	PromptKindScript: {
+		Allowed:  []string{"style", "duration", "max_scenes", "language", "tone", "audience"},
+		Required: []string{"style", "duration", "language"},
+	},
+	PromptKindScene: {
+		Allowed:  []string{"style", "language", "audience"},
+		Required: []string{"style"},
+	},
+}
+
+// defaultPromptTemplates are the built-in system prompts. The instructions
+// on the JSON to respond with aren't part of them: they're appended to every
+// template, built-in or not, so responses can always be parsed.
+var defaultPromptTemplates = map[string]string{
+	PromptKindScript: `You are an expert video scriptwriter specializing in {{style}} content.
+
+Create a detailed video script with the following specifications:
+- Target Duration: {{duration}} seconds
+- Maximum Scenes: {{max_scenes}}
+- Style: {{style}}
+- Language: {{language}}
+- Tone: {{tone}}`,
+	PromptKindScene: `You are an expert cinematographer and visual designer specializing in {{style}} style.
+
+Enhance the scene description and create a detailed image generation prompt.`,
+}
+
+// promptPlaceholder matches a {{placeholder}} in a template
+var promptPlaceholder = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// PromptTemplates is how a user's templates are offered for editing: their
+// own, the built-in ones, and the placeholders of each kind
+type PromptTemplates struct {
+	Templates    []*domain.PromptTemplate      `json:"templates"`
+	Defaults     map[string]string             `json:"defaults"`
+	Placeholders map[string]PromptPlaceholders `json:"placeholders"`
+}
+
+// PromptTemplateService layers users' own system prompts, saved or sent
+// with a request, over the built-in ones. A nil service means overrides
+// aren't enabled: only the built-in prompts are used.
+type PromptTemplateService struct {
+	store PromptTemplateStore
+}
+
+// NewPromptTemplateService creates a prompt template service
+func NewPromptTemplateService(store PromptTemplateStore) *PromptTemplateService {
+	return &PromptTemplateService{store: store}
+}
+
+// ListTemplates returns the user's saved templates along with the built-in
+// ones and the placeholders templates can use
+func (s *PromptTemplateService) ListTemplates(ctx context.Context, userID string) (*PromptTemplates, error) {
+	if s == nil {
+		return nil, ErrPromptOverridesDisabled
+	}
+	templates, err := s.store.ListPromptTemplates(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+	return &PromptTemplates{Templates: templates, Defaults: defaultPromptTemplates, Placeholders: promptKinds}, nil
+}
+
+// SaveTemplate validates and saves the user's template of a kind, replacing
+// any they had
+func (s *PromptTemplateService) SaveTemplate(ctx context.Context, userID, kind, template string) (*domain.PromptTemplate, error) {
+	if s == nil {
+		return nil, ErrPromptOverridesDisabled
+	}
+	if err := ValidatePromptTemplate(kind, template); err != nil {
+		return nil, err
+	}
+	saved := &domain.PromptTemplate{UserID: userID, Kind: kind, Template: template}
+	if err := s.store.SavePromptTemplate(ctx, saved); err != nil {
+		return nil, fmt.Errorf("failed to save prompt template: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteTemplate removes the user's template of a kind, going back to the
+// built-in one
+func (s *PromptTemplateService) DeleteTemplate(ctx context.Context, userID, kind string) error {
+	if s == nil {
+		return ErrPromptOverridesDisabled
+	}
+	if _, ok := promptKinds[kind]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownPromptKind, kind)
+	}
+	deleted, err := s.store.DeletePromptTemplate(ctx, userID, kind)
+	if err != nil {
+		return fmt.Errorf("failed to delete prompt template: %w", err)
+	}
+	if !deleted {
+		return ErrPromptTemplateNotFound
+	}
+	return nil
+}
+
+// ValidatePromptTemplate checks a template of a kind only uses the kind's
+// placeholders and has all the required ones
+func ValidatePromptTemplate(kind, template string) error {
+	placeholders, ok := promptKinds[kind]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownPromptKind, kind)
+	}
+	if strings.TrimSpace(template) == "" {
+		return fmt.Errorf("%w: template is empty", ErrInvalidPromptTemplate)
+	}
+	if len(template) > maxPromptTemplateLength {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidPromptTemplate, maxPromptTemplateLength)
+	}
+
+	used := make(map[string]bool)
+	for _, match := range promptPlaceholder.FindAllStringSubmatch(template, -1) {
+		if !containsString(placeholders.Allowed, match[1]) {
+			return fmt.Errorf("%w: unknown placeholder {{%s}}, %s prompts can use %s",
+				ErrInvalidPromptTemplate, match[1], kind, formatPlaceholders(placeholders.Allowed))
+		}
+		used[match[1]] = true
+	}
+	var missing []string
+	for _, name := range placeholders.Required {
+		if !used[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing required placeholder %s", ErrInvalidPromptTemplate, formatPlaceholders(missing))
+	}
+	return nil
+}
+
+// template picks the system prompt template for a generation: the one sent
+// with the request, else the user's saved one, else the built-in one
+func (s *PromptTemplateService) template(ctx context.Context, kind, requested string) (string, error) {
+	if requested != "" {
+		if s == nil {
+			return "", ErrPromptOverridesDisabled
+		}
+		if err := ValidatePromptTemplate(kind, requested); err != nil {
+			return "", err
+		}
+		return requested, nil
+	}
+	if s != nil {
+		if userID, ok := domain.UserIDFromContext(ctx); ok {
+			// A template that can't be loaded falls back to the built-in one
+			// rather than failing the generation
+			if saved, err := s.store.GetPromptTemplate(ctx, userID, kind); err == nil {
+				return saved.Template, nil
+			}
+		}
+	}
+	return defaultPromptTemplates[kind], nil
+}
+
+// renderPromptTemplate fills in a template's placeholders. Values are put in
+// as they are, in one pass, so a value that looks like a placeholder stays
+// text.
+func renderPromptTemplate(template string, values map[string]string) string {
+	return promptPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := promptPlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// scriptPromptValues are the placeholder values of a script request
+func scriptPromptValues(req *GenerateScriptRequest) map[string]string {
+	return map[string]string{
+		"style":      string(req.Style),
+		"duration":   strconv.Itoa(req.Duration),
+		"max_scenes": strconv.Itoa(req.MaxScenes),
+		"language":   req.Language,
+		"tone":       req.Tone,
+		"audience":   req.TargetAudience,
+	}
+}
+
+func formatPlaceholders(names []string) string {
+	formatted := make([]string, len(names))
+	for i, name := range names {
+		formatted[i] = "{{" + name + "}}"
+	}
+	return strings.Join(formatted, ", ")
+}
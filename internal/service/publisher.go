@@ -3,10 +3,14 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"slices"
 	"time"
 
+	"renderowl-api/internal/domain"
 	socialdomain "renderowl-api/internal/domain/social"
 	"renderowl-api/internal/scheduler"
 	socialsvc "renderowl-api/internal/service/social"
@@ -21,24 +25,63 @@ type PostRepository interface {
 	Update(ctx context.Context, post *socialdomain.ScheduledPost) error
 	UpdateStatus(ctx context.Context, id string, status socialdomain.PostStatus, errorMsg string) error
 	Delete(ctx context.Context, id string) error
+	GetPublishedPlatformPosts(ctx context.Context) ([]*socialdomain.PlatformPost, error)
+	// Recurring post occurrences
+	GetRecurring(ctx context.Context) ([]*socialdomain.ScheduledPost, error)
+	ClaimOccurrence(ctx context.Context, postID string, scheduledAt time.Time) (bool, error)
+	LatestOccurrence(ctx context.Context, postID string) (*socialdomain.PostOccurrence, error)
+	CountOccurrences(ctx context.Context, postID string) (int64, error)
+	UpdateOccurrenceStatus(ctx context.Context, postID string, scheduledAt time.Time, status socialdomain.PostStatus) error
 }
 
+// PerformanceRecorder stores aggregated performance per internal video and
+// fires the alerts that new performance data crosses
+type PerformanceRecorder interface {
+	UpdateVideoPerformance(ctx context.Context, req *UpdateVideoPerformanceRequest) error
+	BatchTrackEngagement(ctx context.Context, records []domain.AnalyticsEngagement) error
+	CheckAlerts(ctx context.Context, userIDs []string) error
+}
+
+// StoredVariations reads the results of the user's stored variations runs
+type StoredVariations interface {
+	GetVariations(ctx context.Context, userID, jobID string) (*VariationsResult, error)
+}
+
+// ErrVariationsNotFound is returned when scheduling a variations run that
+// isn't stored or belongs to another user
+var ErrVariationsNotFound = domain.NewAppError(domain.CodeNotFound, http.StatusNotFound, "variations not found")
+
 // Publisher handles automatic publishing of scheduled content
 type Publisher struct {
 	socialService *socialsvc.Service
 	scheduler     *scheduler.Scheduler
 	postRepo      PostRepository
+	performance   PerformanceRecorder
+	variations    StoredVariations
 }
 
 // PublishJobData contains data for a publish job
 type PublishJobData struct {
-	PostID      string   `json:"postId"`
-	AccountID   string   `json:"accountId"`
-	VideoPath   string   `json:"videoPath"`
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	Tags        []string `json:"tags"`
-	Privacy     string   `json:"privacy"`
+	PostID       string                 `json:"postId"`
+	AccountID    string                 `json:"accountId"`
+	MediaType    socialdomain.MediaType `json:"mediaType,omitempty"`
+	VideoPath    string                 `json:"videoPath"`
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description"`
+	Tags         []string               `json:"tags"`
+	Privacy      string                 `json:"privacy"`
+	CategoryID   string                 `json:"categoryId,omitempty"`
+	FirstComment string                 `json:"firstComment,omitempty"`
+	ThumbnailURL string                 `json:"thumbnailUrl,omitempty"`
+	CoverFrameMs *int64                 `json:"coverFrameMs,omitempty"`
+	// ModerationOverride is the admin who force-published flagged content
+	ModerationOverride string `json:"moderationOverride,omitempty"`
+	// OccurrenceAt is the scheduled instant of the recurring post occurrence
+	// the job publishes; nil for one-off posts
+	OccurrenceAt *time.Time `json:"occurrenceAt,omitempty"`
+	// OptimalUntil is the end of the window the job picks its publishing
+	// time from when it first runs; nil once picked or for fixed times
+	OptimalUntil *time.Time `json:"optimalUntil,omitempty"`
 }
 
 // NewPublisher creates a new publisher instance
@@ -46,11 +89,13 @@ func NewPublisher(
 	socialService *socialsvc.Service,
 	scheduler *scheduler.Scheduler,
 	postRepo PostRepository,
+	performance PerformanceRecorder,
 ) *Publisher {
 	return &Publisher{
 		socialService: socialService,
 		scheduler:     scheduler,
 		postRepo:      postRepo,
+		performance:   performance,
 	}
 }
 
@@ -59,6 +104,13 @@ func (p *Publisher) Initialize() {
 	// Register the publish handler
 	p.scheduler.RegisterHandler("publish", p.handlePublishJob)
 	p.scheduler.RegisterHandler("crosspost", p.handleCrossPostJob)
+	p.scheduler.RegisterHandler("sync-analytics", p.handleAnalyticsSyncJob)
+}
+
+// ScheduleAnalyticsSync sets up the daily job that pulls metrics for published posts
+func (p *Publisher) ScheduleAnalyticsSync(ctx context.Context) error {
+	rule := &socialdomain.RecurringRule{Frequency: "daily", Interval: 1}
+	return p.scheduler.AddRecurringJob(ctx, "sync-analytics", nil, rule, p.handleAnalyticsSyncJob)
 }
 
 // SchedulePublish schedules a video for publishing
@@ -69,26 +121,61 @@ func (p *Publisher) SchedulePublish(ctx context.Context, post *socialdomain.Sche
 		return fmt.Errorf("failed to update post: %w", err)
 	}
 
-	// Schedule job for each platform
+	// Recurring posts record the occurrence, so restarts can tell it was
+	// enqueued. Scheduling explicitly always enqueues, even when recorded
+	// before, so failed occurrences can be retried.
+	if post.Recurring != nil {
+		occurrenceAt := occurrenceInstant(post.ScheduledAt)
+		if _, err := p.postRepo.ClaimOccurrence(ctx, post.ID, occurrenceAt); err != nil {
+			return fmt.Errorf("failed to record occurrence: %w", err)
+		}
+		return p.enqueuePublishJobs(ctx, post, &occurrenceAt, nil)
+	}
+	return p.enqueuePublishJobs(ctx, post, nil, nil)
+}
+
+// enqueuePublishJobs adds a publish job for each of the post's platforms, or
+// only the ones in accountIDs when it is non-nil. Jobs of a recurring post
+// occurrence get IDs derived from its key, so they can be looked up later.
+func (p *Publisher) enqueuePublishJobs(ctx context.Context, post *socialdomain.ScheduledPost, occurrenceAt *time.Time, accountIDs []string) error {
+	thumbnailURL, coverFrameMs := coverOf(post)
+	var optimalUntil *time.Time
+	if _, to, ok := socialsvc.OptimalWindowOf(post); ok && to.After(time.Now()) {
+		optimalUntil = &to
+	}
 	for _, platformPost := range post.Platforms {
+		if accountIDs != nil && !slices.Contains(accountIDs, platformPost.AccountID) {
+			continue
+		}
 		jobData := PublishJobData{
-			PostID:      post.ID,
-			AccountID:   platformPost.AccountID,
-			VideoPath:   post.Metadata["videoPath"].(string),
-			Title:       platformPost.CustomTitle,
-			Description: platformPost.CustomDesc,
-			Tags:        platformPost.Tags,
-			Privacy:     platformPost.Privacy,
+			PostID:             post.ID,
+			AccountID:          platformPost.AccountID,
+			MediaType:          mediaTypeOf(post),
+			VideoPath:          post.Metadata["videoPath"].(string),
+			Title:              platformPost.CustomTitle,
+			Description:        platformPost.CustomDesc,
+			Tags:               platformPost.Tags,
+			Privacy:            platformPost.Privacy,
+			CategoryID:         categoryOf(post),
+			FirstComment:       firstCommentOf(post),
+			ThumbnailURL:       thumbnailURL,
+			CoverFrameMs:       coverFrameMs,
+			ModerationOverride: moderationOverrideOf(post),
+			OccurrenceAt:       occurrenceAt,
+			OptimalUntil:       optimalUntil,
 		}
 
 		data, _ := json.Marshal(jobData)
 
 		job := &scheduler.Job{
-			Name:      "publish",
-			Data:      data,
-			RunAt:     post.ScheduledAt,
+			Name:       "publish",
+			Data:       data,
+			RunAt:      post.ScheduledAt,
 			MaxRetries: 3,
 		}
+		if occurrenceAt != nil {
+			job.ID = occurrenceJobID(post.ID, *occurrenceAt, platformPost.AccountID)
+		}
 
 		if err := p.scheduler.AddJob(ctx, job); err != nil {
 			return fmt.Errorf("failed to schedule job: %w", err)
@@ -98,15 +185,22 @@ func (p *Publisher) SchedulePublish(ctx context.Context, post *socialdomain.Sche
 	return nil
 }
 
-// PublishNow immediately publishes a post
-func (p *Publisher) PublishNow(ctx context.Context, postID string) error {
+// PublishNow immediately publishes a post owned by the user. A non-empty
+// moderationOverride is the admin publishing it despite moderation flags.
+func (p *Publisher) PublishNow(ctx context.Context, postID, userID, moderationOverride string) error {
 	post, err := p.postRepo.GetByID(ctx, postID)
-	if err != nil {
-		return fmt.Errorf("post not found: %w", err)
+	if err != nil || post.UserID != userID {
+		return socialsvc.ErrPostNotFound
 	}
 
 	// Update status to publishing
 	post.Status = socialdomain.PostStatusPublishing
+	if moderationOverride != "" {
+		if post.Metadata == nil {
+			post.Metadata = socialdomain.JSON{}
+		}
+		post.Metadata["moderationOverride"] = moderationOverride
+	}
 	if err := p.postRepo.Update(ctx, post); err != nil {
 		return err
 	}
@@ -119,6 +213,11 @@ func (p *Publisher) PublishNow(ctx context.Context, postID string) error {
 	return nil
 }
 
+// SetVariations enables scheduling stored variations runs
+func (p *Publisher) SetVariations(variations StoredVariations) {
+	p.variations = variations
+}
+
 // BulkSchedule schedules multiple posts at once
 func (p *Publisher) BulkSchedule(ctx context.Context, posts []*socialdomain.ScheduledPost) error {
 	for _, post := range posts {
@@ -130,21 +229,142 @@ func (p *Publisher) BulkSchedule(ctx context.Context, posts []*socialdomain.Sche
 	return nil
 }
 
+// ScheduleVariationsRequest attaches the platform variations of one of the
+// user's stored variations runs to the user's accounts
+type ScheduleVariationsRequest struct {
+	VariationJobID string                      `json:"variationJobId" binding:"required"` // the jobId of the variations result
+	AccountIDs     []string                    `json:"accountIds" binding:"required"`
+	Title          string                      `json:"title"`
+	Description    string                      `json:"description"`
+	Tags           []string                    `json:"tags"`
+	Privacy        string                      `json:"privacy"`
+	CategoryID     string                      `json:"categoryId"` // used by YouTube posts
+	ScheduledAt    time.Time                   `json:"scheduledAt"`
+	Timezone       string                      `json:"timezone"`
+	PublishNow     bool                        `json:"publishNow"` // publish straight away; ScheduledAt is ignored
+	Recurring      *socialdomain.RecurringRule `json:"recurring,omitempty"`
+	FirstComment   string                      `json:"firstComment"`
+	ThumbnailURL   string                      `json:"thumbnailUrl"` // overrides each variation's own thumbnail
+}
+
+// ScheduleVariations creates one scheduled post per completed platform
+// variation of a stored variations run, targeting the accounts on that
+// variation's platform. Variations without a matching account are skipped,
+// and each account is only used once.
+func (p *Publisher) ScheduleVariations(ctx context.Context, userID string, req *ScheduleVariationsRequest) ([]*socialdomain.ScheduledPost, error) {
+	if req.PublishNow {
+		req.ScheduledAt = time.Now()
+	} else if err := p.socialService.CheckScheduledTime(req.ScheduledAt); err != nil {
+		return nil, err
+	}
+
+	if p.variations == nil {
+		return nil, ErrVariationsNotFound
+	}
+	variations, err := p.variations.GetVariations(ctx, userID, req.VariationJobID)
+	if errors.Is(err, ErrVariationJobNotFound) {
+		return nil, ErrVariationsNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*socialdomain.SocialAccount, 0, len(req.AccountIDs))
+	for _, accountID := range req.AccountIDs {
+		account, err := p.socialService.GetAccount(ctx, accountID, userID)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	used := make(map[string]bool)
+	var posts []*socialdomain.ScheduledPost
+
+	for _, variation := range variations.Platforms {
+		if variation.Status != VariationStatusCompleted || variation.VideoURL == "" {
+			continue
+		}
+
+		platform := SocialPlatformFor(variation.Platform)
+		var platformPosts []socialdomain.PlatformPost
+		for _, account := range accounts {
+			if account.Platform != platform || used[account.ID] {
+				continue
+			}
+			used[account.ID] = true
+			platformPosts = append(platformPosts, socialdomain.PlatformPost{
+				AccountID:   account.ID,
+				Platform:    account.Platform,
+				CustomTitle: req.Title,
+				CustomDesc:  req.Description,
+				Tags:        req.Tags,
+				Privacy:     req.Privacy,
+			})
+		}
+		if len(platformPosts) == 0 {
+			continue
+		}
+
+		title := req.Title
+		if title == "" {
+			title = variation.Title
+		}
+		thumbnailURL := req.ThumbnailURL
+		if thumbnailURL == "" {
+			thumbnailURL = variation.ThumbnailURL
+		}
+
+		post := &socialdomain.ScheduledPost{
+			UserID:      userID,
+			VideoID:     variations.SourceID,
+			Title:       title,
+			Description: req.Description,
+			Platforms:   platformPosts,
+			ScheduledAt: req.ScheduledAt,
+			Timezone:    req.Timezone,
+			Recurring:   req.Recurring,
+			Metadata: socialdomain.JSON{
+				"videoPath":         variation.VideoURL,
+				"variationId":       variation.ID,
+				"variationPlatform": variation.Platform,
+				"width":             variation.Width,
+				"height":            variation.Height,
+				"aspectRatio":       variation.AspectRatio,
+				"firstComment":      req.FirstComment,
+				"thumbnailUrl":      thumbnailURL,
+				"categoryId":        req.CategoryID,
+			},
+		}
+
+		if err := p.socialService.SchedulePost(ctx, post); err != nil {
+			return posts, fmt.Errorf("failed to schedule %s variation: %w", variation.Platform, err)
+		}
+		if err := p.SchedulePublish(ctx, post); err != nil {
+			return posts, fmt.Errorf("failed to schedule %s variation: %w", variation.Platform, err)
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
 // GetPublishingQueue returns the current publishing queue
 func (p *Publisher) GetPublishingQueue(ctx context.Context, userID string) ([]*socialdomain.ScheduledPost, error) {
 	// Get pending posts
 	return p.postRepo.GetByUser(ctx, userID, 100, 0)
 }
 
-// RetryFailedPost retries a failed post
-func (p *Publisher) RetryFailedPost(ctx context.Context, postID string) error {
+// RetryFailedPost retries a failed post owned by the user
+func (p *Publisher) RetryFailedPost(ctx context.Context, postID, userID string) error {
 	post, err := p.postRepo.GetByID(ctx, postID)
-	if err != nil {
-		return err
+	if err != nil || post.UserID != userID {
+		return socialsvc.ErrPostNotFound
 	}
 
 	if post.Status != socialdomain.PostStatusFailed {
-		return fmt.Errorf("post is not in failed status")
+		return domain.NewAppError(domain.CodeConflict, http.StatusConflict, "post is not in failed status")
 	}
 
 	// Reset status and reschedule
@@ -165,6 +385,51 @@ func (p *Publisher) handlePublishJob(ctx context.Context, job *scheduler.Job) er
 	if err := json.Unmarshal(job.Data, &data); err != nil {
 		return fmt.Errorf("failed to unmarshal job data: %w", err)
 	}
+	if data.OccurrenceAt != nil {
+		due, err := p.occurrenceDue(ctx, &data)
+		if err != nil || !due {
+			return err
+		}
+	}
+
+	// Posts going out at the optimal time pick it now, from the analytics
+	// synced so far, and requeue for it
+	now := time.Now()
+	if data.OptimalUntil != nil {
+		at, err := p.socialService.ResolveOptimalTime(ctx, data.PostID, data.AccountID, *data.OptimalUntil)
+		if err != nil {
+			return err
+		}
+		data.OptimalUntil = nil
+		if at.After(now) {
+			resolved, _ := json.Marshal(data)
+			log.Printf("Post %s picked %s as the optimal time for account %s", data.PostID, at.Format(time.RFC3339), data.AccountID)
+			return p.scheduler.AddJob(ctx, &scheduler.Job{
+				ID:         job.ID,
+				Name:       job.Name,
+				Data:       resolved,
+				RunAt:      at,
+				MaxRetries: job.MaxRetries,
+			})
+		}
+	}
+
+	// Recurring runs, retries and rule changes can land outside the account's
+	// posting window; requeue for the window's next opening instead
+	next, err := p.socialService.NextPostingTime(ctx, data.AccountID, now)
+	if err != nil {
+		return err
+	}
+	if next.After(now) {
+		log.Printf("Post %s is outside account %s's posting window, requeued for %s", data.PostID, data.AccountID, next.Format(time.RFC3339))
+		return p.scheduler.AddJob(ctx, &scheduler.Job{
+			ID:         job.ID,
+			Name:       job.Name,
+			Data:       job.Data,
+			RunAt:      next,
+			MaxRetries: job.MaxRetries,
+		})
+	}
 
 	// Update post status to publishing
 	if err := p.postRepo.UpdateStatus(ctx, data.PostID, socialdomain.PostStatusPublishing, ""); err != nil {
@@ -173,18 +438,29 @@ func (p *Publisher) handlePublishJob(ctx context.Context, job *scheduler.Job) er
 
 	// Create upload request
 	req := &socialdomain.UploadRequest{
-		VideoPath:   data.VideoPath,
-		Title:       data.Title,
-		Description: data.Description,
-		Tags:        data.Tags,
-		Privacy:     data.Privacy,
+		MediaType:          data.MediaType,
+		VideoPath:          data.VideoPath,
+		Title:              data.Title,
+		Description:        data.Description,
+		Tags:               data.Tags,
+		Privacy:            data.Privacy,
+		CategoryID:         data.CategoryID,
+		FirstComment:       data.FirstComment,
+		ThumbnailURL:       data.ThumbnailURL,
+		CoverFrameMs:       data.CoverFrameMs,
+		ModerationOverride: data.ModerationOverride,
+		ScheduledPostID:    data.PostID,
 	}
 
 	// Upload to platform
-	resp, err := p.socialService.UploadVideo(ctx, data.AccountID, req)
+	resp, err := p.socialService.Upload(ctx, data.AccountID, req)
 	if err != nil {
 		// Update post status to failed
 		p.postRepo.UpdateStatus(ctx, data.PostID, socialdomain.PostStatusFailed, err.Error())
+		// A recurring post moves on once this was the last attempt
+		if data.OccurrenceAt != nil && job.Attempts >= job.MaxRetries {
+			p.failOccurrencePlatform(ctx, &data, err)
+		}
 		return fmt.Errorf("upload failed: %w", err)
 	}
 
@@ -196,6 +472,9 @@ func (p *Publisher) handlePublishJob(ctx context.Context, job *scheduler.Job) er
 			post.Platforms[i].PostURL = resp.PostURL
 			post.Platforms[i].Status = socialdomain.PostStatusPublished
 			post.Platforms[i].PublishedAt = &[]time.Time{time.Now()}[0]
+			recordFirstComment(&post.Platforms[i], resp)
+			recordThumbnail(&post.Platforms[i], resp)
+			recordCategory(&post.Platforms[i], resp)
 			break
 		}
 	}
@@ -214,18 +493,27 @@ func (p *Publisher) handlePublishJob(ctx context.Context, job *scheduler.Job) er
 		post.PublishedAt = &[]time.Time{time.Now()}[0]
 	}
 
-	return p.postRepo.Update(ctx, post)
+	if err := p.postRepo.Update(ctx, post); err != nil {
+		return err
+	}
+	if data.OccurrenceAt != nil {
+		p.finishOccurrence(ctx, post, *data.OccurrenceAt)
+	}
+	return nil
 }
 
 func (p *Publisher) handleCrossPostJob(ctx context.Context, job *scheduler.Job) error {
 	var data struct {
-		PostID      string   `json:"postId"`
-		AccountIDs  []string `json:"accountIds"`
-		VideoPath   string   `json:"videoPath"`
-		Title       string   `json:"title"`
-		Description string   `json:"description"`
-		Tags        []string `json:"tags"`
-		Privacy     string   `json:"privacy"`
+		PostID             string                 `json:"postId"`
+		AccountIDs         []string               `json:"accountIds"`
+		MediaType          socialdomain.MediaType `json:"mediaType,omitempty"`
+		VideoPath          string                 `json:"videoPath"`
+		Title              string                 `json:"title"`
+		Description        string                 `json:"description"`
+		Tags               []string               `json:"tags"`
+		Privacy            string                 `json:"privacy"`
+		CategoryID         string                 `json:"categoryId,omitempty"`
+		ModerationOverride string                 `json:"moderationOverride,omitempty"`
 	}
 
 	if err := json.Unmarshal(job.Data, &data); err != nil {
@@ -233,30 +521,127 @@ func (p *Publisher) handleCrossPostJob(ctx context.Context, job *scheduler.Job)
 	}
 
 	req := &socialdomain.UploadRequest{
-		VideoPath:   data.VideoPath,
-		Title:       data.Title,
-		Description: data.Description,
-		Tags:        data.Tags,
-		Privacy:     data.Privacy,
+		MediaType:          data.MediaType,
+		VideoPath:          data.VideoPath,
+		Title:              data.Title,
+		Description:        data.Description,
+		Tags:               data.Tags,
+		Privacy:            data.Privacy,
+		CategoryID:         data.CategoryID,
+		ModerationOverride: data.ModerationOverride,
+		ScheduledPostID:    data.PostID,
 	}
 
 	// Cross-post to all accounts
-	_, err := p.socialService.CrossPost(ctx, data.AccountIDs, req)
+	_, err := p.socialService.CrossPost(ctx, data.AccountIDs, req, nil)
 	return err
 }
 
+func (p *Publisher) handleAnalyticsSyncJob(ctx context.Context, job *scheduler.Job) error {
+	platformPosts, err := p.postRepo.GetPublishedPlatformPosts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load published posts: %w", err)
+	}
+
+	posts := make(map[string]*socialdomain.ScheduledPost)
+	videos := make(map[string]*UpdateVideoPerformanceRequest)
+	// Each video's engagement per platform, summed over its accounts there
+	var engagement []domain.AnalyticsEngagement
+	engagementIndex := make(map[[2]string]int)
+	for _, platformPost := range platformPosts {
+		post, ok := posts[platformPost.ScheduledPostID]
+		if !ok {
+			post, err = p.postRepo.GetByID(ctx, platformPost.ScheduledPostID)
+			if err != nil {
+				log.Printf("Failed to load post %s for analytics sync: %v", platformPost.ScheduledPostID, err)
+				continue
+			}
+			posts[platformPost.ScheduledPostID] = post
+		}
+
+		data, err := p.socialService.SyncPostAnalytics(ctx, platformPost)
+		if err != nil {
+			log.Printf("Failed to sync analytics for %s post %s: %v", platformPost.Platform, platformPost.PlatformPostID, err)
+			continue
+		}
+
+		if post.VideoID == "" {
+			continue
+		}
+		video, ok := videos[post.VideoID]
+		if !ok {
+			video = &UpdateVideoPerformanceRequest{
+				VideoID: post.VideoID,
+				UserID:  post.UserID,
+				Title:   post.Title,
+			}
+			videos[post.VideoID] = video
+		}
+		video.TotalViews += data.Views
+		video.TotalLikes += data.Likes
+		video.TotalComments += data.Comments
+		video.TotalShares += data.Shares
+		if !slices.Contains(video.Platforms, string(platformPost.Platform)) {
+			video.Platforms = append(video.Platforms, string(platformPost.Platform))
+		}
+
+		key := [2]string{post.VideoID, string(platformPost.Platform)}
+		i, ok := engagementIndex[key]
+		if !ok {
+			i = len(engagement)
+			engagementIndex[key] = i
+			engagement = append(engagement, domain.AnalyticsEngagement{VideoID: post.VideoID, Platform: string(platformPost.Platform)})
+		}
+		engagement[i].Likes += data.Likes
+		engagement[i].Comments += data.Comments
+		engagement[i].Shares += data.Shares
+	}
+
+	if err := p.performance.BatchTrackEngagement(ctx, engagement); err != nil {
+		log.Printf("Failed to record engagement for %d videos: %v", len(engagement), err)
+	}
+
+	var userIDs []string
+	for _, video := range videos {
+		if err := p.performance.UpdateVideoPerformance(ctx, video); err != nil {
+			log.Printf("Failed to update performance for video %s: %v", video.VideoID, err)
+			continue
+		}
+		if !slices.Contains(userIDs, video.UserID) {
+			userIDs = append(userIDs, video.UserID)
+		}
+	}
+
+	if err := p.performance.CheckAlerts(ctx, userIDs); err != nil {
+		log.Printf("Failed to check analytics alerts: %v", err)
+	}
+
+	// Fresh performance data changes what's winning
+	scheduleWinningContent(ctx, p.scheduler, userIDs)
+
+	return nil
+}
+
 // Private methods
 
 func (p *Publisher) publishToPlatform(ctx context.Context, post *socialdomain.ScheduledPost, platformPost *socialdomain.PlatformPost) {
+	thumbnailURL, coverFrameMs := coverOf(post)
 	req := &socialdomain.UploadRequest{
-		VideoPath:   post.Metadata["videoPath"].(string),
-		Title:       platformPost.CustomTitle,
-		Description: platformPost.CustomDesc,
-		Tags:        platformPost.Tags,
-		Privacy:     platformPost.Privacy,
+		MediaType:          mediaTypeOf(post),
+		VideoPath:          post.Metadata["videoPath"].(string),
+		Title:              platformPost.CustomTitle,
+		Description:        platformPost.CustomDesc,
+		Tags:               platformPost.Tags,
+		Privacy:            platformPost.Privacy,
+		CategoryID:         categoryOf(post),
+		FirstComment:       firstCommentOf(post),
+		ThumbnailURL:       thumbnailURL,
+		CoverFrameMs:       coverFrameMs,
+		ModerationOverride: moderationOverrideOf(post),
+		ScheduledPostID:    post.ID,
 	}
 
-	resp, err := p.socialService.UploadVideo(ctx, platformPost.AccountID, req)
+	resp, err := p.socialService.Upload(ctx, platformPost.AccountID, req)
 	if err != nil {
 		platformPost.Status = socialdomain.PostStatusFailed
 		platformPost.ErrorMsg = err.Error()
@@ -269,10 +654,87 @@ func (p *Publisher) publishToPlatform(ctx context.Context, post *socialdomain.Sc
 	platformPost.Status = socialdomain.PostStatusPublished
 	now := time.Now()
 	platformPost.PublishedAt = &now
+	recordFirstComment(platformPost, resp)
+	recordThumbnail(platformPost, resp)
+	recordCategory(platformPost, resp)
 
 	p.postRepo.Update(ctx, post)
 }
 
+// mediaTypeOf returns the media type recorded on a scheduled post
+func mediaTypeOf(post *socialdomain.ScheduledPost) socialdomain.MediaType {
+	mediaType, _ := post.Metadata["mediaType"].(string)
+	return socialdomain.MediaType(mediaType)
+}
+
+// firstCommentOf returns the first comment recorded on a scheduled post
+func firstCommentOf(post *socialdomain.ScheduledPost) string {
+	comment, _ := post.Metadata["firstComment"].(string)
+	return comment
+}
+
+// categoryOf returns the YouTube category recorded on a scheduled post
+func categoryOf(post *socialdomain.ScheduledPost) string {
+	categoryID, _ := post.Metadata["categoryId"].(string)
+	return categoryID
+}
+
+// moderationOverrideOf returns the admin who force-published a scheduled post, if any
+func moderationOverrideOf(post *socialdomain.ScheduledPost) string {
+	override, _ := post.Metadata["moderationOverride"].(string)
+	return override
+}
+
+// coverOf returns the thumbnail and cover frame recorded on a scheduled post.
+// The cover frame comes back from JSON storage as a float64.
+func coverOf(post *socialdomain.ScheduledPost) (string, *int64) {
+	thumbnailURL, _ := post.Metadata["thumbnailUrl"].(string)
+
+	var coverFrameMs *int64
+	switch v := post.Metadata["coverFrameMs"].(type) {
+	case int64:
+		coverFrameMs = &v
+	case float64:
+		ms := int64(v)
+		coverFrameMs = &ms
+	}
+	return thumbnailURL, coverFrameMs
+}
+
+// recordFirstComment stores the first comment outcome on the platform post
+func recordFirstComment(platformPost *socialdomain.PlatformPost, resp *socialdomain.UploadResponse) {
+	if resp.FirstComment == nil {
+		return
+	}
+	if platformPost.Metadata == nil {
+		platformPost.Metadata = socialdomain.JSON{}
+	}
+	platformPost.Metadata["firstComment"] = resp.FirstComment
+}
+
+// recordThumbnail stores the thumbnail outcome on the platform post
+func recordThumbnail(platformPost *socialdomain.PlatformPost, resp *socialdomain.UploadResponse) {
+	if resp.Thumbnail == nil {
+		return
+	}
+	if platformPost.Metadata == nil {
+		platformPost.Metadata = socialdomain.JSON{}
+	}
+	platformPost.Metadata["thumbnail"] = resp.Thumbnail
+}
+
+// recordCategory stores the category the upload was filed under on the
+// platform post
+func recordCategory(platformPost *socialdomain.PlatformPost, resp *socialdomain.UploadResponse) {
+	if resp.Category == nil {
+		return
+	}
+	if platformPost.Metadata == nil {
+		platformPost.Metadata = socialdomain.JSON{}
+	}
+	platformPost.Metadata["category"] = resp.Category
+}
+
 // FormatForPlatform formats content for a specific platform
 func FormatForPlatform(content string, platform socialdomain.SocialPlatform) string {
 	switch platform {
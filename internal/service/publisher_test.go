@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// storedVariations holds variations results by user and job ID
+type storedVariations map[string]map[string]*VariationsResult
+
+func (s storedVariations) GetVariations(ctx context.Context, userID, jobID string) (*VariationsResult, error) {
+	result, ok := s[userID][jobID]
+	if !ok {
+		return nil, ErrVariationJobNotFound
+	}
+	return result, nil
+}
+
+func TestScheduleVariationsOnlySchedulesOwnStoredRuns(t *testing.T) {
+	publisher := &Publisher{}
+	publisher.SetVariations(storedVariations{"user-a": {"job-a": {SourceID: "video-a"}}})
+
+	for _, jobID := range []string{"job-a", "job-missing"} {
+		_, err := publisher.ScheduleVariations(context.Background(), "user-b", &ScheduleVariationsRequest{VariationJobID: jobID, PublishNow: true})
+		if !errors.Is(err, ErrVariationsNotFound) {
+			t.Errorf("user-b scheduling %s: err = %v, want ErrVariationsNotFound", jobID, err)
+		}
+	}
+
+	posts, err := publisher.ScheduleVariations(context.Background(), "user-a", &ScheduleVariationsRequest{VariationJobID: "job-a", PublishNow: true})
+	if err != nil || len(posts) != 0 {
+		t.Errorf("user-a scheduling a run without platform variations = %v, %v, want no posts", posts, err)
+	}
+}
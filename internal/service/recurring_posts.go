@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	socialdomain "renderowl-api/internal/domain/social"
+)
+
+// occurrenceInstant normalizes an occurrence's scheduled time to whole
+// seconds in UTC, so its key compares equal however it was loaded
+func occurrenceInstant(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+// occurrenceJobID is the scheduler job ID of one platform's publish of a
+// recurring post occurrence
+func occurrenceJobID(postID string, occurrenceAt time.Time, accountID string) string {
+	return fmt.Sprintf("publish_%s_%d_%s", postID, occurrenceAt.Unix(), accountID)
+}
+
+// occurrenceDue reports whether a recurring post's publish job should run. It
+// must be for the post's current occurrence, on a platform that hasn't
+// published it yet; jobs enqueued twice for an occurrence are dropped here.
+func (p *Publisher) occurrenceDue(ctx context.Context, data *PublishJobData) (bool, error) {
+	post, err := p.postRepo.GetByID(ctx, data.PostID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load post: %w", err)
+	}
+	if post.Status == socialdomain.PostStatusCancelled || post.Status == socialdomain.PostStatusDraft {
+		log.Printf("Post %s is %s, dropping its %s occurrence", post.ID, post.Status, data.OccurrenceAt.Format(time.RFC3339))
+		return false, nil
+	}
+	if !occurrenceInstant(post.ScheduledAt).Equal(*data.OccurrenceAt) {
+		log.Printf("Post %s has moved on from its %s occurrence, dropping stale job", post.ID, data.OccurrenceAt.Format(time.RFC3339))
+		return false, nil
+	}
+	for _, platformPost := range post.Platforms {
+		if platformPost.AccountID == data.AccountID && platformPost.Status == socialdomain.PostStatusPublished {
+			log.Printf("Post %s occurrence %s already published to account %s, dropping duplicate job", post.ID, data.OccurrenceAt.Format(time.RFC3339), data.AccountID)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// failOccurrencePlatform marks a platform as failed for a recurring post's
+// occurrence after its last attempt, so the post can move on
+func (p *Publisher) failOccurrencePlatform(ctx context.Context, data *PublishJobData, cause error) {
+	post, err := p.postRepo.GetByID(ctx, data.PostID)
+	if err != nil {
+		log.Printf("Failed to load post %s to record its failure: %v", data.PostID, err)
+		return
+	}
+	for i := range post.Platforms {
+		if post.Platforms[i].AccountID == data.AccountID {
+			post.Platforms[i].Status = socialdomain.PostStatusFailed
+			post.Platforms[i].ErrorMsg = cause.Error()
+		}
+	}
+	if err := p.postRepo.Update(ctx, post); err != nil {
+		log.Printf("Failed to record failure of post %s: %v", post.ID, err)
+		return
+	}
+	p.finishOccurrence(ctx, post, *data.OccurrenceAt)
+}
+
+// finishOccurrence closes a recurring post's occurrence once every platform
+// has published or failed, and schedules the next one
+func (p *Publisher) finishOccurrence(ctx context.Context, post *socialdomain.ScheduledPost, occurrenceAt time.Time) {
+	status := socialdomain.PostStatusPublished
+	for _, platformPost := range post.Platforms {
+		switch platformPost.Status {
+		case socialdomain.PostStatusPublished:
+		case socialdomain.PostStatusFailed:
+			status = socialdomain.PostStatusFailed
+		default:
+			return
+		}
+	}
+
+	if err := p.postRepo.UpdateOccurrenceStatus(ctx, post.ID, occurrenceAt, status); err != nil {
+		log.Printf("Failed to close occurrence %s of post %s: %v", occurrenceAt.Format(time.RFC3339), post.ID, err)
+		return
+	}
+	if err := p.scheduleNextOccurrence(ctx, post, occurrenceAt); err != nil {
+		log.Printf("Failed to schedule next occurrence of post %s: %v", post.ID, err)
+	}
+}
+
+// scheduleNextOccurrence enqueues the occurrence of a recurring post that
+// follows the given one, unless its rule has ended. The occurrence is claimed
+// first, so it is skipped if it was already enqueued.
+func (p *Publisher) scheduleNextOccurrence(ctx context.Context, post *socialdomain.ScheduledPost, after time.Time) error {
+	count, err := p.postRepo.CountOccurrences(ctx, post.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count occurrences: %w", err)
+	}
+	next, ok := nextOccurrence(post.Recurring, post.Timezone, after, count)
+	if !ok {
+		log.Printf("Recurring post %s has ended", post.ID)
+		return nil
+	}
+
+	claimed, err := p.postRepo.ClaimOccurrence(ctx, post.ID, next)
+	if err != nil {
+		return fmt.Errorf("failed to record occurrence: %w", err)
+	}
+	if !claimed {
+		log.Printf("Occurrence %s of post %s was already enqueued, skipping", next.Format(time.RFC3339), post.ID)
+		return nil
+	}
+	return p.startOccurrence(ctx, post, next)
+}
+
+// startOccurrence moves a recurring post on to a claimed occurrence and
+// enqueues its publish jobs
+func (p *Publisher) startOccurrence(ctx context.Context, post *socialdomain.ScheduledPost, occurrenceAt time.Time) error {
+	post.ScheduledAt = occurrenceAt
+	post.Status = socialdomain.PostStatusScheduled
+	post.ErrorMsg = ""
+	post.PublishedAt = nil
+	for i := range post.Platforms {
+		post.Platforms[i].Status = socialdomain.PostStatusScheduled
+		post.Platforms[i].ErrorMsg = ""
+	}
+	if err := p.postRepo.Update(ctx, post); err != nil {
+		return fmt.Errorf("failed to update post: %w", err)
+	}
+	return p.enqueuePublishJobs(ctx, post, &occurrenceAt, nil)
+}
+
+// ReconcileRecurringPosts rebuilds the pending occurrences of recurring posts
+// from the database when the publisher starts. Occurrences whose publish
+// jobs were lost are re-enqueued, and posts whose last occurrence finished
+// before the next was scheduled get it now; nothing already enqueued is
+// enqueued again.
+func (p *Publisher) ReconcileRecurringPosts(ctx context.Context) error {
+	posts, err := p.postRepo.GetRecurring(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load recurring posts: %w", err)
+	}
+
+	for _, post := range posts {
+		if err := p.reconcileRecurringPost(ctx, post); err != nil {
+			log.Printf("Failed to reconcile recurring post %s: %v", post.ID, err)
+		}
+	}
+	return nil
+}
+
+func (p *Publisher) reconcileRecurringPost(ctx context.Context, post *socialdomain.ScheduledPost) error {
+	latest, err := p.postRepo.LatestOccurrence(ctx, post.ID)
+	if err != nil {
+		// Scheduled before occurrences were recorded
+		occurrenceAt := occurrenceInstant(post.ScheduledAt)
+		if post.Status == socialdomain.PostStatusPublished || post.Status == socialdomain.PostStatusFailed {
+			if _, err := p.postRepo.ClaimOccurrence(ctx, post.ID, occurrenceAt); err != nil {
+				return err
+			}
+			if err := p.postRepo.UpdateOccurrenceStatus(ctx, post.ID, occurrenceAt, post.Status); err != nil {
+				return err
+			}
+			return p.scheduleNextOccurrence(ctx, post, occurrenceAt)
+		}
+		latest = &socialdomain.PostOccurrence{PostID: post.ID, ScheduledAt: occurrenceAt, Status: socialdomain.PostStatusScheduled}
+		if _, err := p.postRepo.ClaimOccurrence(ctx, post.ID, occurrenceAt); err != nil {
+			return err
+		}
+	}
+	occurrenceAt := occurrenceInstant(latest.ScheduledAt)
+
+	if latest.Status != socialdomain.PostStatusScheduled {
+		// Finished, but the next occurrence may not have been scheduled
+		return p.scheduleNextOccurrence(ctx, post, occurrenceAt)
+	}
+	if !occurrenceInstant(post.ScheduledAt).Equal(occurrenceAt) {
+		// Claimed, but the post was never moved on to it
+		return p.startOccurrence(ctx, post, occurrenceAt)
+	}
+
+	// Re-enqueue the platforms whose jobs are missing
+	var missing []string
+	for _, platformPost := range post.Platforms {
+		if platformPost.Status == socialdomain.PostStatusPublished || platformPost.Status == socialdomain.PostStatusFailed {
+			continue
+		}
+		if _, err := p.scheduler.GetJobStatus(ctx, occurrenceJobID(post.ID, occurrenceAt, platformPost.AccountID)); err != nil {
+			missing = append(missing, platformPost.AccountID)
+		}
+	}
+	if len(missing) > 0 {
+		log.Printf("Re-enqueueing occurrence %s of post %s for %d platform(s)", occurrenceAt.Format(time.RFC3339), post.ID, len(missing))
+		if err := p.enqueuePublishJobs(ctx, post, &occurrenceAt, missing); err != nil {
+			return err
+		}
+	}
+
+	// Every platform may have finished without the occurrence being closed
+	p.finishOccurrence(ctx, post, occurrenceAt)
+	return nil
+}
+
+// nextOccurrence returns when a recurring post next runs after the given
+// occurrence, of which count have been scheduled so far, or false once its
+// rule has ended. Dates step in the post's timezone, so occurrences keep
+// their wall-clock time across daylight saving changes.
+func nextOccurrence(rule *socialdomain.RecurringRule, timezone string, after time.Time, count int64) (time.Time, bool) {
+	if rule == nil {
+		return time.Time{}, false
+	}
+	if rule.EndAfter != nil && count >= int64(*rule.EndAfter) {
+		return time.Time{}, false
+	}
+
+	loc := time.UTC
+	if timezone != "" {
+		if l, err := time.LoadLocation(timezone); err == nil {
+			loc = l
+		}
+	}
+	local := after.In(loc)
+	interval := max(rule.Interval, 1)
+
+	var next time.Time
+	switch rule.Frequency {
+	case "weekly":
+		next = nextWeeklyOccurrence(local, interval, rule.DaysOfWeek)
+	case "monthly":
+		next = local.AddDate(0, interval, 0)
+	default:
+		next = local.AddDate(0, 0, interval)
+	}
+
+	if rule.EndDate != nil {
+		if end, ok := parseRuleEndDate(*rule.EndDate, loc); ok && next.After(end) {
+			return time.Time{}, false
+		}
+	}
+	return occurrenceInstant(next), true
+}
+
+// nextWeeklyOccurrence returns the next of the week's days after t, or when
+// t is the last of them, the first day of the week interval weeks on. With
+// no days the post repeats on t's weekday.
+func nextWeeklyOccurrence(t time.Time, interval int, days []int) time.Time {
+	if len(days) == 0 {
+		return t.AddDate(0, 0, 7*interval)
+	}
+	weekday := int(t.Weekday())
+	for offset := 1; weekday+offset <= 6; offset++ {
+		if slices.Contains(days, weekday+offset) {
+			return t.AddDate(0, 0, offset)
+		}
+	}
+	first := slices.Min(days)
+	weekStart := t.AddDate(0, 0, -weekday+7*interval)
+	return weekStart.AddDate(0, 0, first)
+}
+
+// parseRuleEndDate reads a rule's end date, either a day (inclusive) or an
+// RFC 3339 instant
+func parseRuleEndDate(value string, loc *time.Location) (time.Time, bool) {
+	if day, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidSceneOrder is returned when a reorder doesn't name every scene
+// exactly once
+var ErrInvalidSceneOrder = errors.New("order must list every scene number exactly once")
+
+// ReorderScenesRequest puts generated scenes in a new order. Order lists the
+// scenes' current numbers in the order they should play.
+type ReorderScenesRequest struct {
+	Scenes []GeneratedScene `json:"scenes" binding:"required,min=1"`
+	Order  []int            `json:"order" binding:"required,min=1"`
+}
+
+// ReorderScenes returns the scenes in the requested order, numbered from 1
+// and with their start times reflowed to match. Nothing is regenerated; a
+// batch video given the scenes in its config is made from them in this order.
+func (s *AISceneService) ReorderScenes(req *ReorderScenesRequest) (*SceneGenerationResult, error) {
+	if len(req.Scenes) == 0 {
+		return nil, ErrNoScenes
+	}
+	if len(req.Order) != len(req.Scenes) {
+		return nil, fmt.Errorf("%w: got %d numbers for %d scenes", ErrInvalidSceneOrder, len(req.Order), len(req.Scenes))
+	}
+
+	byNumber := make(map[int]GeneratedScene, len(req.Scenes))
+	for _, scene := range req.Scenes {
+		if _, ok := byNumber[scene.Number]; ok {
+			return nil, fmt.Errorf("%w: scene number %d appears twice", ErrInvalidSceneOrder, scene.Number)
+		}
+		byNumber[scene.Number] = scene
+	}
+
+	result := &SceneGenerationResult{
+		Scenes:      make([]GeneratedScene, 0, len(req.Order)),
+		TotalScenes: len(req.Order),
+	}
+	for _, number := range req.Order {
+		scene, ok := byNumber[number]
+		if !ok {
+			return nil, fmt.Errorf("%w: no scene %d, or it is listed twice", ErrInvalidSceneOrder, number)
+		}
+		delete(byNumber, number)
+		scene.Number = len(result.Scenes) + 1
+		result.Scenes = append(result.Scenes, scene)
+	}
+	result.TotalDuration = reflowScenes(result.Scenes)
+
+	return result, nil
+}
+
+// reflowScenes sets each scene's start time to where the scenes before it
+// end, returning the total duration
+func reflowScenes(scenes []GeneratedScene) int {
+	total := 0
+	for i := range scenes {
+		scenes[i].StartTime = total
+		total += scenes[i].Duration
+	}
+	return total
+}
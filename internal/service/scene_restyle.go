@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+)
+
+// sceneConcurrency bounds the scenes enhanced and imaged at once
+const sceneConcurrency = 4
+
+// RestyleScenesRequest re-runs scene enhancement in another style over
+// scenes that were already generated
+type RestyleScenesRequest struct {
+	Scenes           []GeneratedScene `json:"scenes" binding:"required,min=1"`
+	Style            string           `json:"style" binding:"required"`
+	StylePreset      string           `json:"style_preset,omitempty"`      // ImageStylePresets ID
+	RegenerateImages bool             `json:"regenerate_images,omitempty"` // When false only descriptions and prompts change, which costs no image generations
+	ImageSource      ImageSource      `json:"image_source,omitempty"`
+	NegativePrompt   string           `json:"negative_prompt,omitempty"`
+	Language         string           `json:"language,omitempty"`
+	TargetAudience   string           `json:"target_audience,omitempty"`
+	Platform         string           `json:"platform,omitempty"`
+	SystemPrompt     string           `json:"system_prompt,omitempty"` // Template replacing the built-in enhancement system prompt
+}
+
+// RestyleScenes re-enhances scenes in a new style, keeping their number,
+// title and original description. Images are only replaced when
+// RegenerateImages is set; otherwise scenes keep theirs, with the new prompt
+// ready for a later regeneration. A scene that fails keeps what it had.
+func (s *AISceneService) RestyleScenes(ctx context.Context, req *RestyleScenesRequest) (*SceneGenerationResult, error) {
+	if len(req.Scenes) == 0 {
+		return nil, ErrNoScenes
+	}
+	autoSelected := req.ImageSource == ""
+	if autoSelected {
+		req.ImageSource = s.DefaultImageSource()
+	}
+	if req.Language == "" {
+		req.Language = requestLocale(ctx).Language
+	}
+	if err := validateImagePlatform(req.Platform); err != nil {
+		return nil, err
+	}
+	preset, err := findImageStyle(req.StylePreset)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.prompts.template(ctx, PromptKindScene, req.SystemPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SceneGenerationResult{
+		Scenes:      make([]GeneratedScene, len(req.Scenes)),
+		TotalScenes: len(req.Scenes),
+		StylePreset: req.StylePreset,
+	}
+	if req.RegenerateImages {
+		result.ImageSource = req.ImageSource
+		result.ImageSourceAutoSelected = autoSelected
+	}
+
+	audience := sceneAudience{Language: req.Language, TargetAudience: req.TargetAudience}
+	sem := make(chan struct{}, sceneConcurrency)
+	var wg sync.WaitGroup
+	for i, scene := range req.Scenes {
+		wg.Add(1)
+		go func(i int, scene GeneratedScene) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			result.Scenes[i] = s.restyleScene(ctx, req, scene, template, preset, audience)
+		}(i, scene)
+	}
+	wg.Wait()
+	result.TotalDuration = reflowScenes(result.Scenes)
+
+	return result, nil
+}
+
+// restyleScene restyles one scene of a RestyleScenes request
+func (s *AISceneService) restyleScene(ctx context.Context, req *RestyleScenesRequest, scene GeneratedScene, template string, preset *ImageStylePreset, audience sceneAudience) GeneratedScene {
+	// Generated scenes don't carry their keywords, so stock searches use the title
+	info := SceneInfo{
+		Number:      scene.Number,
+		Title:       scene.Title,
+		Description: scene.Description,
+		Keywords:    strings.Fields(scene.Title),
+	}
+
+	enhancement, err := s.enhanceSceneDescription(ctx, info, req.Style, template, preset, audience)
+	if err != nil {
+		log.Printf("Restyling scene %d failed, keeping it as it was: %v", scene.Number, err)
+		return scene
+	}
+	scene.EnhancedDesc = enhancement.EnhancedDesc
+	scene.Mood = s.extractMood(enhancement.EnhancedDesc)
+	scene.ColorPalette = s.extractColorPalette(enhancement.EnhancedDesc)
+
+	opts := imageOptions{NegativePrompt: req.NegativePrompt, Platform: req.Platform}
+	scene.ImagePrompt, opts = styleImagePrompt(preset, enhancement.ImagePrompt, opts)
+
+	if req.RegenerateImages {
+		count := min(max(len(scene.Images), 1), maxSceneImages)
+		images, err := s.generateSceneImages(ctx, req.ImageSource, scene.ImagePrompt, info.Keywords, opts, count)
+		if err != nil {
+			log.Printf("Regenerating images for scene %d failed, keeping the old ones: %v", scene.Number, err)
+		} else {
+			image := images[0]
+			scene.ImageURL = image.ImageURL
+			scene.ThumbnailURL = image.ThumbnailURL
+			scene.AltText = image.AltText
+			scene.ImageSource = image.ImageSource
+			scene.Seed = image.Seed
+			scene.ImageSize = image.ImageSize
+			scene.Images = make([]string, 0, len(images))
+			for _, image := range images {
+				scene.Images = append(scene.Images, image.ImageURL)
+			}
+			if enhancement.AltText != "" && (scene.AltText == "" || !audience.isEnglish()) {
+				scene.AltText = enhancement.AltText
+			}
+		}
+	}
+	return scene
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ErrNoScenes is returned when a scene request has neither scenes nor a script
+var ErrNoScenes = errors.New("scenes or a script are required")
+
+// defaultSceneSeconds sizes scenes when no scene count is asked for
+const defaultSceneSeconds = 8
+
+// maxSplitScenes caps the scenes a script is split into
+const maxSplitScenes = 20
+
+// maxSceneKeywords is how many keywords a split scene gets
+const maxSceneKeywords = 5
+
+// sceneStopWords are left out of scene keywords
+var sceneStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "your": true, "all": true, "any": true, "can": true, "had": true,
+	"her": true, "was": true, "one": true, "our": true, "out": true, "has": true,
+	"have": true, "this": true, "that": true, "with": true, "they": true, "them": true,
+	"from": true, "what": true, "when": true, "will": true, "just": true, "into": true,
+	"than": true, "then": true, "there": true, "their": true, "these": true, "those": true,
+	"about": true, "which": true, "would": true, "could": true, "should": true, "been": true,
+	"were": true, "more": true, "most": true, "some": true, "very": true, "also": true,
+	"here": true, "how": true, "why": true, "who": true, "its": true, "it's": true,
+	"don't": true, "let's": true, "we're": true, "you're": true, "because": true, "really": true,
+}
+
+// SplitScriptIntoScenes splits narration into scenes of roughly equal
+// duration without an AI call. Scenes break on paragraphs when there are
+// enough of them, otherwise on sentences. A targetScenes of 0 picks a count
+// from the duration, and a targetDuration over 0 scales the scene durations
+// to add up to it.
+func SplitScriptIntoScenes(text string, targetScenes, targetDuration int) []Scene {
+	units := splitParagraphs(text)
+	if len(units) == 0 {
+		return nil
+	}
+
+	total := estimateDuration(text, 0)
+	if targetScenes <= 0 {
+		duration := targetDuration
+		if duration <= 0 {
+			duration = total
+		}
+		targetScenes = max(1, (duration+defaultSceneSeconds/2)/defaultSceneSeconds)
+	}
+	targetScenes = min(targetScenes, maxSplitScenes)
+	if len(units) < targetScenes {
+		units = splitSentences(units)
+	}
+	targetScenes = min(targetScenes, len(units))
+
+	scenes := make([]Scene, 0, targetScenes)
+	for i, narration := range groupUnits(units, targetScenes) {
+		scenes = append(scenes, Scene{
+			Number:      i + 1,
+			Title:       sceneTitle(narration),
+			Description: firstSentence(narration),
+			Narration:   narration,
+			Duration:    max(1, estimateDuration(narration, 0)),
+			Keywords:    sceneKeywords(narration, maxSceneKeywords),
+		})
+	}
+
+	if targetDuration > 0 {
+		scaleSceneDurations(scenes, targetDuration)
+	}
+	return scenes
+}
+
+// groupUnits joins consecutive units into n groups with about the same
+// number of words each
+func groupUnits(units []string, n int) []string {
+	words := make([]int, len(units))
+	total := 0
+	for i, unit := range units {
+		words[i] = len(strings.Fields(unit))
+		total += words[i]
+	}
+
+	groups := make([]string, 0, n)
+	var current []string
+	done := 0
+	for i, unit := range units {
+		current = append(current, unit)
+		done += words[i]
+
+		remainingUnits := len(units) - i - 1
+		remainingGroups := n - len(groups) - 1
+		// Close the group once it reaches its share of the words, or when
+		// every remaining unit is needed to fill the remaining groups
+		if remainingGroups > 0 && (done*n >= (len(groups)+1)*total || remainingUnits == remainingGroups) {
+			groups = append(groups, strings.Join(current, " "))
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		groups = append(groups, strings.Join(current, " "))
+	}
+	return groups
+}
+
+// scaleSceneDurations scales scene durations to add up to target seconds,
+// keeping every scene at least a second long
+func scaleSceneDurations(scenes []Scene, target int) {
+	total := 0
+	for _, scene := range scenes {
+		total += scene.Duration
+	}
+	if total == 0 {
+		return
+	}
+
+	assigned := 0
+	for i := range scenes {
+		if i == len(scenes)-1 {
+			scenes[i].Duration = max(1, target-assigned)
+			break
+		}
+		scenes[i].Duration = max(1, scenes[i].Duration*target/total)
+		assigned += scenes[i].Duration
+	}
+}
+
+// splitParagraphs splits text on blank lines, collapsing whitespace
+func splitParagraphs(text string) []string {
+	var paragraphs []string
+	for _, block := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if paragraph := strings.Join(strings.Fields(block), " "); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return paragraphs
+}
+
+// splitSentences splits paragraphs into sentences, ending a sentence at
+// ., ! or ? followed by a space
+func splitSentences(paragraphs []string) []string {
+	var sentences []string
+	for _, paragraph := range paragraphs {
+		start := 0
+		for i := 0; i < len(paragraph)-1; i++ {
+			if strings.ContainsRune(".!?", rune(paragraph[i])) && paragraph[i+1] == ' ' {
+				sentences = append(sentences, strings.TrimSpace(paragraph[start:i+1]))
+				start = i + 2
+			}
+		}
+		if rest := strings.TrimSpace(paragraph[start:]); rest != "" {
+			sentences = append(sentences, rest)
+		}
+	}
+	return sentences
+}
+
+// firstSentence returns the narration's first sentence
+func firstSentence(narration string) string {
+	return splitSentences([]string{narration})[0]
+}
+
+// sceneTitle titles a scene with the first few words of its narration
+func sceneTitle(narration string) string {
+	words := strings.Fields(firstSentence(narration))
+	if len(words) > 6 {
+		return strings.Join(words[:6], " ") + "..."
+	}
+	return strings.TrimRight(strings.Join(words, " "), ".!?")
+}
+
+// sceneKeywords returns the narration's most frequent words, leaving out
+// stop words and short words; ties go to the word used first
+func sceneKeywords(narration string, limit int) []string {
+	counts := make(map[string]int)
+	var order []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(narration), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}) {
+		word = strings.Trim(word, "'")
+		if len([]rune(word)) < 3 || sceneStopWords[word] {
+			continue
+		}
+		if counts[word] == 0 {
+			order = append(order, word)
+		}
+		counts[word]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	if len(order) > limit {
+		order = order[:limit]
+	}
+	return order
+}
+
+// scenesFromScript splits a request's script into scenes for generation
+func scenesFromScript(req *GenerateScenesRequest) []SceneInfo {
+	scenes := SplitScriptIntoScenes(req.Script, req.TargetScenes, req.TargetDuration)
+	infos := make([]SceneInfo, len(scenes))
+	for i, scene := range scenes {
+		infos[i] = SceneInfo{
+			Number:      scene.Number,
+			Title:       scene.Title,
+			Description: scene.Narration,
+			Keywords:    scene.Keywords,
+			Duration:    scene.Duration,
+		}
+	}
+	return infos
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"renderowl-api/internal/domain"
+)
+
+// Search errors
+var (
+	ErrSearchTooShort    = errors.New("search query must be at least 2 characters")
+	ErrUnknownSearchType = errors.New("unknown search type")
+)
+
+// minSearchLength is the shortest query searched, so a single letter
+// doesn't match most of a library
+const minSearchLength = 2
+
+// searchTypes are the kinds of content searched when no types are given
+var searchTypes = []string{domain.SearchTypeTimeline, domain.SearchTypeBatch, domain.SearchTypeScheduledPost}
+
+// SearchStore finds a user's content by name
+type SearchStore interface {
+	Search(ctx context.Context, userID, term string, types []string, limit, offset int) ([]domain.SearchResult, int64, error)
+}
+
+// SearchService searches across a user's timelines, batches and scheduled posts
+type SearchService struct {
+	store SearchStore
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(store SearchStore) *SearchService {
+	return &SearchService{store: store}
+}
+
+// Search returns a page of the user's content matching the query, best
+// matches first, and the total. Types narrows the search; empty means all.
+func (s *SearchService) Search(ctx context.Context, userID, query string, types []string, limit, offset int) ([]domain.SearchResult, int64, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < minSearchLength {
+		return nil, 0, ErrSearchTooShort
+	}
+	if len(types) == 0 {
+		types = searchTypes
+	}
+	for _, t := range types {
+		if !slices.Contains(searchTypes, t) {
+			return nil, 0, fmt.Errorf("%w %q: use %s", ErrUnknownSearchType, t, strings.Join(searchTypes, ", "))
+		}
+	}
+	return s.store.Search(ctx, userID, query, types, limit, offset)
+}
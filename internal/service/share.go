@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/repository"
+)
+
+// Share link errors
+var (
+	ErrShareNotFound       = errors.New("share link not found")
+	ErrShareExpired        = errors.New("share link has expired or was revoked")
+	ErrReportSharesOff     = errors.New("performance reports are not enabled")
+	ErrInvalidShareExpiry  = errors.New("expiresInHours must be between 1 and 720")
+	ErrShareTargetNotFound = errors.New("timeline not found")
+)
+
+// Share link lifetimes
+const (
+	defaultShareTTL = 7 * 24 * time.Hour
+	maxShareTTL     = 30 * 24 * time.Hour
+)
+
+// ShareLinkStore persists share links so they can be listed and revoked
+type ShareLinkStore interface {
+	CreateShareLink(ctx context.Context, link *domain.ShareLink) error
+	GetShareLink(ctx context.Context, id string) (*domain.ShareLink, error)
+	ListShareLinks(ctx context.Context, userID string) ([]*domain.ShareLink, error)
+	RevokeShareLink(ctx context.Context, userID, id string) error
+}
+
+// ReportGenerator builds a user's performance report
+type ReportGenerator interface {
+	GeneratePerformanceReport(ctx context.Context, userID string, days int) (*PerformanceReport, error)
+}
+
+// SharedNoteSource lists the notes shown on preview shares of timelines
+type SharedNoteSource interface {
+	ListNotes(ctx context.Context, timelineID string, limit, offset int) ([]*domain.TimelineNote, int64, error)
+}
+
+// sharedNoteLimit caps the notes shown on a shared timeline
+const sharedNoteLimit = 100
+
+// ShareService creates signed, expiring links to timelines and performance
+// reports for people without an account
+type ShareService struct {
+	links        ShareLinkStore
+	timelineRepo *repository.TimelineRepository
+	reports      ReportGenerator
+	notes        SharedNoteSource
+	secret       []byte
+	baseURL      string
+}
+
+// ShareRequest sets up a share link
+type ShareRequest struct {
+	ExpiresInHours int    `json:"expiresInHours"`                               // defaults to a week, at most 30 days
+	Scope          string `json:"scope" binding:"omitempty,oneof=read preview"` // defaults to read
+}
+
+// ShareReportRequest sets up a share link to a performance report
+type ShareReportRequest struct {
+	ShareRequest
+	Days int `json:"days"` // report period, defaults to 30
+}
+
+// ShareLinkResponse is a share link with the URL to hand out
+type ShareLinkResponse struct {
+	*domain.ShareLink
+	URL string `json:"url,omitempty"` // only returned when the link is created
+}
+
+// SharedView is what a share link's visitor sees
+type SharedView struct {
+	ResourceType string             `json:"resourceType"`
+	Scope        string             `json:"scope"`
+	ExpiresAt    time.Time          `json:"expiresAt"`
+	Timeline     *SharedTimeline    `json:"timeline,omitempty"`
+	Report       *PerformanceReport `json:"report,omitempty"`
+}
+
+// SharedTimeline is the outline of a shared timeline
+type SharedTimeline struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Duration    float64       `json:"duration"`
+	Width       int           `json:"width"`
+	Height      int           `json:"height"`
+	FPS         int           `json:"fps"`
+	Tracks      []SharedTrack `json:"tracks"`
+	Notes       []SharedNote  `json:"notes,omitempty"` // preview shares only
+}
+
+// SharedTrack is a track of a shared timeline
+type SharedTrack struct {
+	Name  string       `json:"name"`
+	Type  string       `json:"type"`
+	Order int          `json:"order"`
+	Clips []SharedClip `json:"clips"`
+}
+
+// SharedClip is a clip of a shared timeline. SourceURL is only set for
+// preview shares.
+type SharedClip struct {
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	StartTime   float64 `json:"startTime"`
+	EndTime     float64 `json:"endTime"`
+	TextContent string  `json:"textContent,omitempty"`
+	SourceURL   string  `json:"sourceUrl,omitempty"`
+}
+
+// SharedNote is a note on a shared timeline, without its author
+type SharedNote struct {
+	Text      string    `json:"text"`
+	ClipName  string    `json:"clipName,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewShareService creates a share service. Tokens are signed with secret;
+// without one a random secret is used, so links stop working on restart.
+// Link URLs are baseURL followed by the token.
+func NewShareService(links ShareLinkStore, timelineRepo *repository.TimelineRepository, secret, baseURL string) *ShareService {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatalf("Failed to generate share signing secret: %v", err)
+		}
+		log.Printf("Warning: SHARE_SIGNING_SECRET not set, share links won't survive a restart")
+	}
+	return &ShareService{
+		links:        links,
+		timelineRepo: timelineRepo,
+		secret:       key,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SetReportGenerator enables sharing performance reports
+func (s *ShareService) SetReportGenerator(reports ReportGenerator) {
+	s.reports = reports
+}
+
+// SetTimelineNotes shows timeline notes on preview shares
+func (s *ShareService) SetTimelineNotes(notes SharedNoteSource) {
+	s.notes = notes
+}
+
+// ShareTimeline creates a share link to one of the user's timelines
+func (s *ShareService) ShareTimeline(ctx context.Context, userID, timelineID string, req *ShareRequest) (*ShareLinkResponse, error) {
+	if _, err := s.timelineRepo.GetByIDAndUser(timelineID, userID); err != nil {
+		return nil, ErrShareTargetNotFound
+	}
+	return s.create(ctx, &domain.ShareLink{
+		UserID:       userID,
+		ResourceType: domain.ShareTimeline,
+		ResourceID:   timelineID,
+	}, req)
+}
+
+// ShareReport creates a share link to the user's performance report. The
+// report is generated now and stored with the link, so opening the link
+// doesn't run the report's AI analysis for anonymous visitors.
+func (s *ShareService) ShareReport(ctx context.Context, userID string, req *ShareReportRequest) (*ShareLinkResponse, error) {
+	if s.reports == nil {
+		return nil, ErrReportSharesOff
+	}
+	days := req.Days
+	if days <= 0 {
+		days = 30
+	}
+	report, err := s.reports.GeneratePerformanceReport(ctx, userID, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate report: %w", err)
+	}
+	shared := *report
+	shared.UserID = ""
+	data, err := json.Marshal(&shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store report: %w", err)
+	}
+	return s.create(ctx, &domain.ShareLink{
+		UserID:       userID,
+		ResourceType: domain.ShareReport,
+		ReportDays:   days,
+		Report:       data,
+	}, &req.ShareRequest)
+}
+
+func (s *ShareService) create(ctx context.Context, link *domain.ShareLink, req *ShareRequest) (*ShareLinkResponse, error) {
+	ttl := defaultShareTTL
+	if req.ExpiresInHours != 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+		if ttl < time.Hour || ttl > maxShareTTL {
+			return nil, ErrInvalidShareExpiry
+		}
+	}
+	link.Scope = req.Scope
+	if link.Scope == "" {
+		link.Scope = domain.ShareScopeRead
+	}
+	// Whole seconds, as the token carries the expiry as a Unix time
+	link.ExpiresAt = time.Now().Add(ttl).Truncate(time.Second)
+
+	if err := s.links.CreateShareLink(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+	return &ShareLinkResponse{
+		ShareLink: link,
+		URL:       s.baseURL + "/" + s.signToken(link),
+	}, nil
+}
+
+// ListShares returns the user's share links that can still be opened
+func (s *ShareService) ListShares(ctx context.Context, userID string) ([]*domain.ShareLink, error) {
+	links, err := s.links.ListShareLinks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+	return links, nil
+}
+
+// RevokeShare kills one of the user's share links
+func (s *ShareService) RevokeShare(ctx context.Context, userID, id string) error {
+	if err := s.links.RevokeShareLink(ctx, userID, id); err != nil {
+		return ErrShareNotFound
+	}
+	return nil
+}
+
+// OpenShare validates a share token and returns the reduced view of what it
+// links to
+func (s *ShareService) OpenShare(ctx context.Context, token string) (*SharedView, error) {
+	id, expiresAt, ok := s.verifyToken(token)
+	if !ok {
+		return nil, ErrShareNotFound
+	}
+	if time.Now().After(expiresAt) {
+		return nil, ErrShareExpired
+	}
+	link, err := s.links.GetShareLink(ctx, id)
+	if err != nil {
+		return nil, ErrShareNotFound
+	}
+	if link.RevokedAt != nil {
+		return nil, ErrShareExpired
+	}
+
+	view := &SharedView{
+		ResourceType: link.ResourceType,
+		Scope:        link.Scope,
+		ExpiresAt:    link.ExpiresAt,
+	}
+	switch link.ResourceType {
+	case domain.ShareTimeline:
+		timeline, err := s.timelineRepo.GetByIDAndUser(link.ResourceID, link.UserID)
+		if err != nil {
+			return nil, ErrShareExpired
+		}
+		preview := link.Scope == domain.ShareScopePreview
+		view.Timeline = sharedTimeline(timeline, preview)
+		if preview && s.notes != nil {
+			notes, _, err := s.notes.ListNotes(ctx, timeline.ID, sharedNoteLimit, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load notes: %w", err)
+			}
+			view.Timeline.Notes = sharedNotes(timeline, notes)
+		}
+	case domain.ShareReport:
+		// Links from before reports were stored have nothing to show
+		if len(link.Report) == 0 {
+			return nil, ErrShareExpired
+		}
+		var report PerformanceReport
+		if err := json.Unmarshal(link.Report, &report); err != nil {
+			return nil, fmt.Errorf("failed to load report: %w", err)
+		}
+		view.Report = &report
+	default:
+		return nil, ErrShareNotFound
+	}
+	return view, nil
+}
+
+// signToken builds a link's token: its ID and expiry, signed
+func (s *ShareService) signToken(link *domain.ShareLink) string {
+	payload := link.ID + "." + strconv.FormatInt(link.ExpiresAt.Unix(), 10)
+	return payload + "." + s.sign(payload)
+}
+
+// verifyToken checks a token's signature and returns the link ID and expiry
+// it carries
+func (s *ShareService) verifyToken(token string) (string, time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, false
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(payload))) {
+		return "", time.Time{}, false
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return parts[0], time.Unix(expiry, 0), true
+}
+
+func (s *ShareService) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// sharedTimeline reduces a timeline to its outline, leaving out IDs, the
+// owner and, unless withMedia is set, media URLs
+func sharedTimeline(timeline *domain.Timeline, withMedia bool) *SharedTimeline {
+	shared := &SharedTimeline{
+		Name:        timeline.Name,
+		Description: timeline.Description,
+		Duration:    timeline.Duration,
+		Width:       timeline.Width,
+		Height:      timeline.Height,
+		FPS:         timeline.FPS,
+		Tracks:      make([]SharedTrack, 0, len(timeline.Tracks)),
+	}
+	for _, track := range timeline.Tracks {
+		sharedTrack := SharedTrack{
+			Name:  track.Name,
+			Type:  track.Type,
+			Order: track.Order,
+			Clips: make([]SharedClip, 0, len(track.Clips)),
+		}
+		for _, clip := range track.Clips {
+			sharedClip := SharedClip{
+				Name:        clip.Name,
+				Type:        clip.Type,
+				StartTime:   clip.StartTime,
+				EndTime:     clip.EndTime,
+				TextContent: clip.TextContent,
+			}
+			if withMedia {
+				sharedClip.SourceURL = clip.SourceURL
+			}
+			sharedTrack.Clips = append(sharedTrack.Clips, sharedClip)
+		}
+		shared.Tracks = append(shared.Tracks, sharedTrack)
+	}
+	return shared
+}
+
+// sharedNotes strips notes down to their text, naming the clip a note is
+// about rather than exposing its ID
+func sharedNotes(timeline *domain.Timeline, notes []*domain.TimelineNote) []SharedNote {
+	shared := make([]SharedNote, 0, len(notes))
+	for _, note := range notes {
+		sharedNote := SharedNote{Text: note.Text, CreatedAt: note.CreatedAt}
+		if note.ClipID != nil {
+			if clip := findClip(timeline, *note.ClipID); clip != nil {
+				sharedNote.ClipName = clip.Name
+			}
+		}
+		shared = append(shared, sharedNote)
+	}
+	return shared
+}
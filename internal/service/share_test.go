@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"renderowl-api/internal/domain"
+)
+
+// fakeShareLinks keeps share links in memory
+type fakeShareLinks struct {
+	links map[string]*domain.ShareLink
+}
+
+func (f *fakeShareLinks) CreateShareLink(ctx context.Context, link *domain.ShareLink) error {
+	link.ID = fmt.Sprintf("link-%d", len(f.links)+1)
+	f.links[link.ID] = link
+	return nil
+}
+
+func (f *fakeShareLinks) GetShareLink(ctx context.Context, id string) (*domain.ShareLink, error) {
+	link, ok := f.links[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return link, nil
+}
+
+func (f *fakeShareLinks) ListShareLinks(ctx context.Context, userID string) ([]*domain.ShareLink, error) {
+	var links []*domain.ShareLink
+	for _, link := range f.links {
+		if link.UserID == userID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (f *fakeShareLinks) RevokeShareLink(ctx context.Context, userID, id string) error {
+	return errors.New("not supported")
+}
+
+func TestSharedReportIsTheOptimizerReport(t *testing.T) {
+	optimizer, _, _ := newTestOptimizer()
+	shares := NewShareService(&fakeShareLinks{links: map[string]*domain.ShareLink{}}, nil, "secret", "https://app.example.com/share")
+	ctx := context.Background()
+
+	if _, err := shares.ShareReport(ctx, "user-a", &ShareReportRequest{}); !errors.Is(err, ErrReportSharesOff) {
+		t.Fatalf("ShareReport without reports: got %v, want ErrReportSharesOff", err)
+	}
+
+	shares.SetReportGenerator(optimizer)
+	link, err := shares.ShareReport(ctx, "user-a", &ShareReportRequest{Days: 7})
+	if err != nil {
+		t.Fatalf("ShareReport: %v", err)
+	}
+
+	// Visitors see the stored report; nothing is generated for them
+	shares.SetReportGenerator(nil)
+	token := link.URL[strings.LastIndex(link.URL, "/")+1:]
+	view, err := shares.OpenShare(ctx, token)
+	if err != nil {
+		t.Fatalf("OpenShare: %v", err)
+	}
+	if view.Report == nil {
+		t.Fatal("shared view has no report")
+	}
+	if view.Report.UserID != "" {
+		t.Errorf("shared report exposes user ID %q", view.Report.UserID)
+	}
+	if view.Report.Period != "Last 7 days" {
+		t.Errorf("period = %q, want the shared period", view.Report.Period)
+	}
+	if len(view.Report.TopVideos) != 1 || view.Report.TopVideos[0].VideoID != "video-1" {
+		t.Errorf("shared report top videos = %v, want user-a's video", view.Report.TopVideos)
+	}
+}
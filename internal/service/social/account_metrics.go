@@ -0,0 +1,142 @@
+package social
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+const (
+	// accountMetricsSnapshotInterval is the least time between two stored
+	// snapshots of an account's metrics, so frequent views don't flood the
+	// history
+	accountMetricsSnapshotInterval = time.Hour
+	// DefaultMetricsHistoryDays and MaxMetricsHistoryDays bound how far back
+	// an account's metrics history goes
+	DefaultMetricsHistoryDays = 30
+	MaxMetricsHistoryDays     = 365
+)
+
+// ErrAccountMetricsUnsupported means an account's platform doesn't report
+// account-level metrics
+var ErrAccountMetricsUnsupported = domain.NewAppError(domain.CodeValidation, http.StatusBadRequest, "account metrics are not available for this platform")
+
+// AccountMetricsClient fetches account-level metrics from platform
+// analytics APIs
+type AccountMetricsClient interface {
+	GetAccountMetrics(ctx context.Context, account *social.SocialAccount) (*social.AccountMetricsSnapshot, error)
+}
+
+// platformAccountMetrics reads account metrics from each account's own
+// platform, with the account's own token
+type platformAccountMetrics struct {
+	registry *PlatformRegistry
+}
+
+// NewPlatformAccountMetrics creates an account metrics client reading from
+// the registered platforms. Platforms without a metrics API are refused
+// with ErrAccountMetricsUnsupported.
+func NewPlatformAccountMetrics(registry *PlatformRegistry) AccountMetricsClient {
+	return &platformAccountMetrics{registry: registry}
+}
+
+func (m *platformAccountMetrics) GetAccountMetrics(ctx context.Context, account *social.SocialAccount) (*social.AccountMetricsSnapshot, error) {
+	p, ok := m.registry.Get(account.Platform)
+	if !ok {
+		return nil, errPlatformNotConfigured(account.Platform)
+	}
+	reader, ok := p.(AccountMetricsReader)
+	if !ok {
+		return nil, ErrAccountMetricsUnsupported
+	}
+	return reader.GetAccountMetrics(ctx, account)
+}
+
+// AccountMetricsRepository stores snapshots of accounts' metrics
+type AccountMetricsRepository interface {
+	Create(ctx context.Context, snapshot *social.AccountMetricsSnapshot) error
+	GetLatest(ctx context.Context, accountID string) (*social.AccountMetricsSnapshot, error)
+	ListSince(ctx context.Context, accountID string, since time.Time) ([]*social.AccountMetricsSnapshot, error)
+}
+
+// SetAccountMetrics enables account-level metrics, fetched with client and
+// snapshotted into snapshots to chart growth
+func (s *Service) SetAccountMetrics(client AccountMetricsClient, snapshots AccountMetricsRepository) {
+	s.metricsClient = client
+	s.metricsSnapshots = snapshots
+}
+
+// GetAccountMetrics returns an account's follower, view and video counts
+// with their history over the last days. The counts are stored as a
+// snapshot at most once an hour. When the platform call fails the last
+// stored counts are returned, marked stale; with none stored it fails.
+func (s *Service) GetAccountMetrics(ctx context.Context, accountID, userID string, days int) (*social.AccountMetrics, error) {
+	account, err := s.GetAccount(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if s.metricsClient == nil {
+		return nil, errPlatformNotConfigured(account.Platform)
+	}
+	if days <= 0 {
+		days = DefaultMetricsHistoryDays
+	}
+	days = min(days, MaxMetricsHistoryDays)
+
+	// The last known counts, if any, to fall back on
+	var latest *social.AccountMetricsSnapshot
+	if snapshot, err := s.metricsSnapshots.GetLatest(ctx, account.ID); err == nil {
+		latest = snapshot
+	}
+
+	result := &social.AccountMetrics{
+		AccountID:   account.ID,
+		Platform:    account.Platform,
+		AccountName: account.AccountName,
+	}
+	now := time.Now()
+	current, err := s.metricsClient.GetAccountMetrics(ctx, account)
+	if err != nil {
+		if latest == nil {
+			if _, ok := domain.AsAppError(err); ok {
+				return nil, err
+			}
+			return nil, domain.WrapError(err, domain.CodePlatformError, http.StatusBadGateway)
+		}
+		log.Printf("Failed to fetch metrics for account %s, using the last known values: %v", account.ID, err)
+		current = latest
+		result.Stale = true
+		result.Error = err.Error()
+	} else {
+		current.UserID = account.UserID
+		current.AccountID = account.ID
+		current.Platform = account.Platform
+		current.RecordedAt = now
+		if latest == nil || now.Sub(latest.RecordedAt) >= accountMetricsSnapshotInterval {
+			if err := s.metricsSnapshots.Create(ctx, current); err != nil {
+				log.Printf("Failed to store metrics snapshot for account %s: %v", account.ID, err)
+			}
+		}
+	}
+	result.Followers = current.Followers
+	result.TotalViews = current.TotalViews
+	result.VideoCount = current.VideoCount
+	result.FetchedAt = current.RecordedAt
+
+	history, err := s.metricsSnapshots.ListSince(ctx, account.ID, now.AddDate(0, 0, -days))
+	if err != nil {
+		log.Printf("Failed to list metrics history for account %s: %v", account.ID, err)
+	}
+	result.History = history
+	if result.History == nil {
+		result.History = []*social.AccountMetricsSnapshot{}
+	}
+	if len(history) > 0 {
+		result.FollowerGrowth = result.Followers - history[0].Followers
+	}
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"renderowl-api/internal/domain/social"
+)
+
+// fakePlatform is a registered platform; the methods tests don't reach are
+// left to the embedded interface
+type fakePlatform struct {
+	Platform
+	name social.SocialPlatform
+}
+
+func (p *fakePlatform) GetName() social.SocialPlatform { return p.name }
+
+// fakeMetricsPlatform reports each token's account metrics
+type fakeMetricsPlatform struct {
+	fakePlatform
+	followers map[string]int64
+}
+
+func (p *fakeMetricsPlatform) GetAccountMetrics(ctx context.Context, account *social.SocialAccount) (*social.AccountMetricsSnapshot, error) {
+	return &social.AccountMetricsSnapshot{Followers: p.followers[account.AccessToken]}, nil
+}
+
+// metricsAccounts holds accounts by ID
+type metricsAccounts struct {
+	AccountRepository
+	accounts map[string]*social.SocialAccount
+}
+
+func (r *metricsAccounts) GetByID(ctx context.Context, id string) (*social.SocialAccount, error) {
+	account, ok := r.accounts[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return account, nil
+}
+
+// memorySnapshots keeps metrics snapshots in memory
+type memorySnapshots struct {
+	snapshots []*social.AccountMetricsSnapshot
+}
+
+func (r *memorySnapshots) Create(ctx context.Context, snapshot *social.AccountMetricsSnapshot) error {
+	r.snapshots = append(r.snapshots, snapshot)
+	return nil
+}
+
+func (r *memorySnapshots) GetLatest(ctx context.Context, accountID string) (*social.AccountMetricsSnapshot, error) {
+	for i := len(r.snapshots) - 1; i >= 0; i-- {
+		if r.snapshots[i].AccountID == accountID {
+			return r.snapshots[i], nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memorySnapshots) ListSince(ctx context.Context, accountID string, since time.Time) ([]*social.AccountMetricsSnapshot, error) {
+	return nil, nil
+}
+
+func TestAccountMetricsComeFromEachAccountsPlatform(t *testing.T) {
+	registry := NewPlatformRegistry()
+	registry.Register(&fakeMetricsPlatform{
+		fakePlatform: fakePlatform{name: social.PlatformYouTube},
+		followers:    map[string]int64{"token-a": 120, "token-b": 7},
+	})
+	registry.Register(&fakePlatform{name: social.PlatformLinkedIn})
+
+	accounts := &metricsAccounts{accounts: map[string]*social.SocialAccount{
+		"yt-a": {ID: "yt-a", UserID: "user-a", Platform: social.PlatformYouTube, AccessToken: "token-a"},
+		"yt-b": {ID: "yt-b", UserID: "user-b", Platform: social.PlatformYouTube, AccessToken: "token-b"},
+		"li-a": {ID: "li-a", UserID: "user-a", Platform: social.PlatformLinkedIn, AccessToken: "token-c"},
+	}}
+	snapshots := &memorySnapshots{}
+	s := NewService(registry, accounts, nil, nil)
+	s.SetAccountMetrics(NewPlatformAccountMetrics(registry), snapshots)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		accountID, userID string
+		followers         int64
+	}{
+		{"yt-a", "user-a", 120},
+		{"yt-b", "user-b", 7},
+	} {
+		metrics, err := s.GetAccountMetrics(ctx, tt.accountID, tt.userID, 0)
+		if err != nil {
+			t.Fatalf("GetAccountMetrics(%s): %v", tt.accountID, err)
+		}
+		if metrics.Followers != tt.followers {
+			t.Errorf("%s followers = %d, want %d read with the account's token", tt.accountID, metrics.Followers, tt.followers)
+		}
+	}
+
+	if _, err := s.GetAccountMetrics(ctx, "li-a", "user-a", 0); !errors.Is(err, ErrAccountMetricsUnsupported) {
+		t.Errorf("LinkedIn account: got %v, want ErrAccountMetricsUnsupported", err)
+	}
+	for _, snapshot := range snapshots.snapshots {
+		if snapshot.AccountID == "li-a" {
+			t.Errorf("stored a snapshot for an account without metrics: %+v", snapshot)
+		}
+	}
+}
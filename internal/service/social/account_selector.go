@@ -0,0 +1,66 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// SelectAccounts resolves a selector to the user's accounts that can be
+// posted to. Matching accounts that aren't connected, or whose token has
+// expired with no refresh token to renew it, are skipped and reported.
+func (s *Service) SelectAccounts(ctx context.Context, userID string, selector *social.AccountSelector) ([]*social.SocialAccount, []social.SkippedAccount, error) {
+	if !selector.All && len(selector.Platforms) == 0 {
+		return nil, nil, domain.NewValidationError("account selector needs all or at least one platform")
+	}
+	wanted := make(map[social.SocialPlatform]bool, len(selector.Platforms))
+	for _, platform := range selector.Platforms {
+		if _, ok := s.registry.Get(platform); !ok {
+			return nil, nil, domain.NewValidationError(fmt.Sprintf("unsupported platform %q", platform))
+		}
+		wanted[platform] = true
+	}
+
+	accounts, err := s.accounts.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var selected []*social.SocialAccount
+	skipped := []social.SkippedAccount{}
+	for _, account := range accounts {
+		if !selector.All && !wanted[account.Platform] {
+			continue
+		}
+		if reason := unpostableReason(account); reason != "" {
+			skipped = append(skipped, social.SkippedAccount{
+				AccountID:   account.ID,
+				AccountName: account.AccountName,
+				Platform:    account.Platform,
+				Reason:      reason,
+			})
+			continue
+		}
+		selected = append(selected, account)
+	}
+
+	if len(selected) == 0 {
+		return nil, skipped, domain.NewValidationError("no connected accounts match the selector")
+	}
+	return selected, skipped, nil
+}
+
+// unpostableReason says why an account can't be posted to, or returns an
+// empty string when it can
+func unpostableReason(account *social.SocialAccount) string {
+	if account.Status != social.StatusConnected {
+		return fmt.Sprintf("account is %s", account.Status)
+	}
+	if account.TokenExpiry != nil && account.TokenExpiry.Before(time.Now()) && account.RefreshToken == "" {
+		return "access token expired; reconnect the account"
+	}
+	return ""
+}
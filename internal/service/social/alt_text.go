@@ -0,0 +1,109 @@
+package social
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"renderowl-api/internal/domain/social"
+)
+
+// maxAltTextLength is the longest alt text sent, Twitter's limit and well
+// under LinkedIn's
+const maxAltTextLength = 1000
+
+// AltTextGenerator describes a post's media, from its thumbnail or image,
+// when the upload has no alt text of its own
+type AltTextGenerator interface {
+	GenerateAltText(ctx context.Context, req *social.UploadRequest) (string, error)
+}
+
+// SetAltTextGenerator enables generating alt text for uploads that don't
+// carry any
+func (s *Service) SetAltTextGenerator(generator AltTextGenerator) {
+	s.altText = generator
+}
+
+// withAltText returns the request with its alt text filled in from the scene
+// alt text, or generated, when any of the target platforms would use it. A
+// failed generation is logged and the media is published without alt text.
+func (s *Service) withAltText(ctx context.Context, req *social.UploadRequest, platforms ...social.SocialPlatform) *social.UploadRequest {
+	if req.AltText != "" {
+		return req
+	}
+
+	wanted := false
+	for _, name := range platforms {
+		if p, ok := s.registry.Get(name); ok && supportsAltText(p, req.MediaType) {
+			wanted = true
+			break
+		}
+	}
+	if !wanted {
+		return req
+	}
+
+	text := sceneAltText(req.SceneAltText)
+	if text == "" && s.altText != nil {
+		generated, err := s.altText.GenerateAltText(ctx, req)
+		if err != nil {
+			log.Printf("Failed to generate alt text, publishing without: %v", err)
+			return req
+		}
+		text = generated
+	}
+	if text == "" {
+		return req
+	}
+
+	withAlt := *req
+	withAlt.AltText = truncateAltText(text)
+	return &withAlt
+}
+
+// altTextResult reports whether an upload's alt text was sent to the platform
+func altTextResult(p Platform, req *social.UploadRequest) *social.AltTextResult {
+	if req.AltText == "" {
+		return nil
+	}
+	if !supportsAltText(p, req.MediaType) {
+		return &social.AltTextResult{Status: social.AltTextStatusUnsupported}
+	}
+	return &social.AltTextResult{Status: social.AltTextStatusApplied, Text: req.AltText}
+}
+
+func supportsAltText(p Platform, mediaType social.MediaType) bool {
+	texter, ok := p.(AltTexter)
+	return ok && texter.SupportsAltText(mediaType)
+}
+
+// sceneAltText joins the alt text of a video's scenes into one description
+func sceneAltText(scenes []string) string {
+	var parts []string
+	for _, text := range scenes {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		if !strings.HasSuffix(text, ".") && !strings.HasSuffix(text, "!") && !strings.HasSuffix(text, "?") {
+			text += "."
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// truncateAltText cuts alt text to maxAltTextLength at a word boundary
+func truncateAltText(text string) string {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) <= maxAltTextLength {
+		return text
+	}
+	runes := []rune(text)[:maxAltTextLength]
+	cut := string(runes)
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return cut
+}
@@ -0,0 +1,90 @@
+package social
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"renderowl-api/internal/domain/social"
+)
+
+// clonedMetadata are the keys of a scheduled post's metadata that describe
+// one scheduling of it rather than its content, so clones don't inherit them
+var clonedMetadata = []string{"requestedAt", "skippedAccounts", "moderationOverride", "optimalTime", "autoScheduled"}
+
+// ClonePost schedules a fresh copy of one of the user's posts for
+// scheduledAt. The copy targets accountIDs, or the original's accounts when
+// empty. Each account keeps the copy the original had for it, or for its
+// platform, and falls back to the post's own title and description. Clones
+// never recur.
+func (s *Service) ClonePost(ctx context.Context, postID, userID string, scheduledAt time.Time, accountIDs []string) (*social.ScheduledPost, error) {
+	original, err := s.posts.GetByID(ctx, postID)
+	if err != nil || original.UserID != userID {
+		return nil, ErrPostNotFound
+	}
+
+	clone := &social.ScheduledPost{
+		UserID:      userID,
+		VideoID:     original.VideoID,
+		Title:       original.Title,
+		Description: original.Description,
+		ScheduledAt: scheduledAt,
+		Timezone:    original.Timezone,
+		Metadata:    maps.Clone(original.Metadata),
+	}
+	if clone.Metadata == nil {
+		clone.Metadata = social.JSON{}
+	}
+	for _, key := range clonedMetadata {
+		delete(clone.Metadata, key)
+	}
+	clone.Metadata["clonedFrom"] = original.ID
+	// A clone reposts the original's video on purpose
+	clone.Metadata["allowDuplicate"] = true
+
+	if len(accountIDs) == 0 {
+		for _, platformPost := range original.Platforms {
+			accountIDs = append(accountIDs, platformPost.AccountID)
+		}
+	}
+	for _, accountID := range accountIDs {
+		account, err := s.GetAccount(ctx, accountID, userID)
+		if err != nil {
+			return nil, err
+		}
+		platformPost := social.PlatformPost{
+			AccountID:   account.ID,
+			Platform:    account.Platform,
+			CustomTitle: original.Title,
+			CustomDesc:  original.Description,
+		}
+		if source := clonedCopyFor(original, account); source != nil {
+			platformPost.CustomTitle = source.CustomTitle
+			platformPost.CustomDesc = source.CustomDesc
+			platformPost.Tags = source.Tags
+			platformPost.Privacy = source.Privacy
+		}
+		clone.Platforms = append(clone.Platforms, platformPost)
+	}
+
+	if err := s.SchedulePost(ctx, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// clonedCopyFor returns the original's platform post for the account, or the
+// first one on its platform, or nil
+func clonedCopyFor(original *social.ScheduledPost, account *social.SocialAccount) *social.PlatformPost {
+	var samePlatform *social.PlatformPost
+	for i := range original.Platforms {
+		platformPost := &original.Platforms[i]
+		if platformPost.AccountID == account.ID {
+			return platformPost
+		}
+		if platformPost.Platform == account.Platform && samePlatform == nil {
+			samePlatform = platformPost
+		}
+	}
+	return samePlatform
+}
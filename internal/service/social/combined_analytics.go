@@ -0,0 +1,106 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"renderowl-api/internal/domain/social"
+)
+
+const (
+	// combinedAnalyticsConcurrency bounds how many accounts are fetched at once
+	combinedAnalyticsConcurrency = 4
+	// combinedAnalyticsPostLimit is how many of the user's most recent posts
+	// the combined analytics cover
+	combinedAnalyticsPostLimit = 100
+)
+
+// GetCombinedAnalytics fetches analytics for the user's recent published posts
+// across all their connected accounts, several accounts at a time. An account
+// that fails is reported in its own entry rather than failing the whole call.
+func (s *Service) GetCombinedAnalytics(ctx context.Context, userID string) (*social.CombinedAnalytics, error) {
+	accounts, err := s.accounts.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	posts, _, err := s.posts.List(ctx, userID, social.PostFilter{Limit: combinedAnalyticsPostLimit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	published := make(map[string][]string)
+	for _, post := range posts {
+		for _, platformPost := range post.Platforms {
+			if platformPost.PlatformPostID != "" {
+				published[platformPost.AccountID] = append(published[platformPost.AccountID], platformPost.PlatformPostID)
+			}
+		}
+	}
+
+	combined := &social.CombinedAnalytics{Accounts: []*social.AccountAnalytics{}}
+	for _, account := range accounts {
+		if account.Status == social.StatusConnected {
+			combined.Accounts = append(combined.Accounts, &social.AccountAnalytics{
+				AccountID:   account.ID,
+				Platform:    account.Platform,
+				AccountName: account.AccountName,
+			})
+		}
+	}
+
+	byID := make(map[string]*social.SocialAccount, len(accounts))
+	for _, account := range accounts {
+		byID[account.ID] = account
+	}
+
+	sem := make(chan struct{}, combinedAnalyticsConcurrency)
+	var wg sync.WaitGroup
+	for _, result := range combined.Accounts {
+		wg.Add(1)
+		go func(result *social.AccountAnalytics) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			s.fetchAccountAnalytics(ctx, byID[result.AccountID], published[result.AccountID], result)
+		}(result)
+	}
+	wg.Wait()
+
+	for _, result := range combined.Accounts {
+		combined.Views += result.Views
+		combined.Likes += result.Likes
+		combined.Comments += result.Comments
+		combined.Shares += result.Shares
+		combined.WatchTime += result.WatchTime
+		if result.Error != "" {
+			combined.Failed++
+		}
+	}
+	return combined, nil
+}
+
+// fetchAccountAnalytics adds up the analytics of an account's posts into
+// result. It stops at the first failing post, since that usually means the
+// platform or the account's token is down for every post.
+func (s *Service) fetchAccountAnalytics(ctx context.Context, account *social.SocialAccount, postIDs []string, result *social.AccountAnalytics) {
+	p, ok := s.registry.Get(account.Platform)
+	if !ok {
+		result.Error = errPlatformNotConfigured(account.Platform).Error()
+		return
+	}
+
+	for _, postID := range postIDs {
+		data, err := p.GetAnalytics(ctx, account, postID)
+		if err != nil {
+			result.Error = err.Error()
+			return
+		}
+		result.Posts++
+		result.Views += data.Views
+		result.Likes += data.Likes
+		result.Comments += data.Comments
+		result.Shares += data.Shares
+		result.WatchTime += data.WatchTime
+	}
+}
@@ -0,0 +1,136 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// ErrDuplicateContent means a near-duplicate of the video was recently
+// posted to the account
+var ErrDuplicateContent = domain.NewAppError(domain.CodeDuplicateContent, http.StatusConflict, "a near-duplicate of this video was recently posted to the account")
+
+// nearDuplicateSimilarity is how alike two fingerprints must be for their
+// videos to count as the same
+const nearDuplicateSimilarity = 0.9
+
+// Fingerprinter computes perceptual fingerprints of videos and compares them
+type Fingerprinter interface {
+	Fingerprint(ctx context.Context, userID, videoPath string) (string, error)
+	Similarity(a, b string) float64
+}
+
+// FingerprintRepository stores the fingerprints of posted videos
+type FingerprintRepository interface {
+	Create(ctx context.Context, fingerprint *social.VideoFingerprint) error
+	ListRecent(ctx context.Context, accountID string, since time.Time) ([]*social.VideoFingerprint, error)
+}
+
+// DuplicateError reports the recent post a video nearly duplicates. It
+// unwraps to ErrDuplicateContent.
+type DuplicateError struct {
+	Account   *social.SocialAccount
+	Duplicate *social.DuplicatePost
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("a near-duplicate of this video was posted to %s account %s on %s; set allowDuplicate to post it anyway",
+		e.Account.Platform, e.Account.AccountName, e.Duplicate.PostedAt.Format(time.RFC3339))
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicateContent
+}
+
+// SetDuplicateDetection enables the check that stops a video going out to
+// an account that had a near-duplicate of it posted within window
+func (s *Service) SetDuplicateDetection(fingerprinter Fingerprinter, fingerprints FingerprintRepository, window time.Duration) {
+	s.fingerprinter = fingerprinter
+	s.fingerprints = fingerprints
+	s.duplicateWindow = window
+}
+
+// fingerprint returns the fingerprint of a request's video, or "" when
+// duplicate detection is off or the video can't be read. An unreadable
+// video is left for the platform to reject rather than blocking the post.
+func (s *Service) fingerprint(ctx context.Context, userID string, mediaType social.MediaType, videoPath string) string {
+	if s.fingerprinter == nil || videoPath == "" || (mediaType != "" && mediaType != social.MediaTypeVideo) {
+		return ""
+	}
+	fingerprint, err := s.fingerprinter.Fingerprint(ctx, userID, videoPath)
+	if err != nil {
+		log.Printf("Failed to fingerprint %s, skipping the duplicate check: %v", videoPath, err)
+		return ""
+	}
+	return fingerprint
+}
+
+// checkDuplicate returns a *DuplicateError when a near-duplicate of the
+// fingerprinted video was posted to the account within the window
+func (s *Service) checkDuplicate(ctx context.Context, account *social.SocialAccount, fingerprint string) error {
+	if fingerprint == "" {
+		return nil
+	}
+	if duplicate := s.findDuplicate(ctx, account, fingerprint); duplicate != nil {
+		return &DuplicateError{Account: account, Duplicate: duplicate}
+	}
+	return nil
+}
+
+// findDuplicate returns the recent post to the account most like the
+// fingerprinted video, if one is alike enough. Scheduled posts that were
+// cancelled or failed don't count.
+func (s *Service) findDuplicate(ctx context.Context, account *social.SocialAccount, fingerprint string) *social.DuplicatePost {
+	recent, err := s.fingerprints.ListRecent(ctx, account.ID, time.Now().Add(-s.duplicateWindow))
+	if err != nil {
+		log.Printf("Failed to list recent fingerprints for account %s: %v", account.ID, err)
+		return nil
+	}
+
+	var best *social.DuplicatePost
+	for _, candidate := range recent {
+		similarity := s.fingerprinter.Similarity(fingerprint, candidate.Fingerprint)
+		if similarity < nearDuplicateSimilarity || (best != nil && similarity <= best.Similarity) {
+			continue
+		}
+		duplicate := &social.DuplicatePost{
+			AccountID:  account.ID,
+			PostID:     candidate.PostID,
+			PostURL:    candidate.PostURL,
+			PostedAt:   candidate.PostedAt,
+			Similarity: similarity,
+		}
+		if candidate.PostID != "" {
+			post, err := s.posts.GetByID(ctx, candidate.PostID)
+			if err != nil || post.Status == social.PostStatusCancelled || post.Status == social.PostStatusFailed {
+				continue
+			}
+			for _, platformPost := range post.Platforms {
+				if platformPost.AccountID == account.ID {
+					duplicate.PostURL = platformPost.PostURL
+				}
+			}
+		}
+		best = duplicate
+	}
+	return best
+}
+
+// recordFingerprint stores the fingerprint of a video posted, or scheduled,
+// to an account. Failing to only weakens later duplicate checks, so it's
+// logged.
+func (s *Service) recordFingerprint(ctx context.Context, account *social.SocialAccount, fingerprint *social.VideoFingerprint) {
+	if fingerprint.Fingerprint == "" {
+		return
+	}
+	fingerprint.UserID = account.UserID
+	fingerprint.AccountID = account.ID
+	if err := s.fingerprints.Create(ctx, fingerprint); err != nil {
+		log.Printf("Failed to record video fingerprint for account %s: %v", account.ID, err)
+	}
+}
@@ -142,6 +142,11 @@ func (f *FacebookPlatform) RefreshToken(ctx context.Context, account *social.Soc
 
 // UploadVideo uploads a video to Facebook Page
 func (f *FacebookPlatform) UploadVideo(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	privacy, err := resolvePrivacy(social.PlatformFacebook, req.Privacy)
+	if err != nil {
+		return nil, err
+	}
+
 	// Facebook video upload
 	uploadURL := fmt.Sprintf("%s/%s/videos", FacebookGraphURL, account.AccountID)
 
@@ -152,11 +157,7 @@ func (f *FacebookPlatform) UploadVideo(ctx context.Context, account *social.Soci
 		"description":  {req.Description},
 	}
 
-	if req.Privacy == "public" {
-		params.Set("published", "true")
-	} else {
-		params.Set("published", "false")
-	}
+	params.Set("published", privacy)
 
 	resp, err := f.makeRequest(ctx, "POST", uploadURL+"?"+params.Encode(), nil, nil)
 	if err != nil {
@@ -229,6 +230,13 @@ func (f *FacebookPlatform) DeletePost(ctx context.Context, account *social.Socia
 	return err
 }
 
+// RevokeToken removes the app's permissions for the account
+func (f *FacebookPlatform) RevokeToken(ctx context.Context, account *social.SocialAccount) error {
+	revokeURL := fmt.Sprintf("%s/me/permissions?access_token=%s", FacebookGraphURL, account.AccessToken)
+	_, err := f.makeRequest(ctx, "DELETE", revokeURL, nil, nil)
+	return err
+}
+
 // GetTrends retrieves trending topics (Facebook doesn't have a public trends API)
 func (f *FacebookPlatform) GetTrends(ctx context.Context, account *social.SocialAccount, region string) ([]*social.PlatformTrend, error) {
 	return []*social.PlatformTrend{
@@ -291,7 +299,7 @@ func (f *FacebookPlatform) makeRequest(ctx context.Context, method, url string,
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, platformAPIError(resp.StatusCode, respBody)
 	}
 
 	return respBody, nil
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"renderowl-api/internal/domain/social"
@@ -129,6 +130,10 @@ func (i *InstagramPlatform) RefreshToken(ctx context.Context, account *social.So
 
 // UploadVideo uploads a video to Instagram (as a Reel or post)
 func (i *InstagramPlatform) UploadVideo(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	if _, err := resolvePrivacy(social.PlatformInstagram, req.Privacy); err != nil {
+		return nil, err
+	}
+
 	// Instagram requires videos to be hosted at a URL
 	// For Reels API, we need to use the Facebook Graph API
 
@@ -145,6 +150,22 @@ func (i *InstagramPlatform) UploadVideo(ctx context.Context, account *social.Soc
 		params.Set("share_to_feed", "true")
 	}
 
+	// The cover is set on the container: an image URL, or a frame offset
+	var thumbnail *social.ThumbnailResult
+	switch {
+	case isRemoteMedia(req.ThumbnailURL):
+		params.Set("cover_url", req.ThumbnailURL)
+		thumbnail = &social.ThumbnailResult{Status: social.ThumbnailStatusSet}
+	case req.CoverFrameMs != nil:
+		params.Set("thumb_offset", strconv.FormatInt(*req.CoverFrameMs, 10))
+		thumbnail = &social.ThumbnailResult{Status: social.ThumbnailStatusSet}
+	case req.ThumbnailURL != "":
+		thumbnail = &social.ThumbnailResult{
+			Status: social.ThumbnailStatusUnsupported,
+			Error:  "Instagram needs a publicly accessible cover image URL",
+		}
+	}
+
 	resp, err := i.makeRequest(ctx, "POST", createURL+"?"+params.Encode(), nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("media creation failed: %w", err)
@@ -180,6 +201,61 @@ func (i *InstagramPlatform) UploadVideo(ctx context.Context, account *social.Soc
 		return nil, fmt.Errorf("failed to parse publish response: %w", err)
 	}
 
+	return &social.UploadResponse{
+		PlatformPostID: result.ID,
+		PostURL:        fmt.Sprintf("https://instagram.com/p/%s", result.ID),
+		Status:         "published",
+		Thumbnail:      thumbnail,
+	}, nil
+}
+
+// UploadImage publishes a single image post to Instagram
+func (i *InstagramPlatform) UploadImage(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	if _, err := resolvePrivacy(social.PlatformInstagram, req.Privacy); err != nil {
+		return nil, err
+	}
+
+	// Step 1: Create an image container
+	createURL := fmt.Sprintf("%s/%s/media", InstagramGraphAPIURL, account.AccountID)
+	params := url.Values{
+		"image_url":    {req.VideoPath}, // Must be a publicly accessible URL
+		"caption":      {req.Description},
+		"access_token": {account.AccessToken},
+	}
+
+	resp, err := i.makeRequest(ctx, "POST", createURL+"?"+params.Encode(), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("media creation failed: %w", err)
+	}
+
+	var createResp struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal(resp, &createResp); err != nil {
+		return nil, fmt.Errorf("failed to parse creation response: %w", err)
+	}
+
+	// Step 2: Publish the container
+	publishURL := fmt.Sprintf("%s/%s/media_publish", InstagramGraphAPIURL, account.AccountID)
+	publishParams := url.Values{
+		"creation_id":  {createResp.ID},
+		"access_token": {account.AccessToken},
+	}
+
+	publishResp, err := i.makeRequest(ctx, "POST", publishURL+"?"+publishParams.Encode(), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("media publish failed: %w", err)
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal(publishResp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse publish response: %w", err)
+	}
+
 	return &social.UploadResponse{
 		PlatformPostID: result.ID,
 		PostURL:        fmt.Sprintf("https://instagram.com/p/%s", result.ID),
@@ -187,6 +263,30 @@ func (i *InstagramPlatform) UploadVideo(ctx context.Context, account *social.Soc
 	}, nil
 }
 
+// PostComment comments on a published Instagram post
+func (i *InstagramPlatform) PostComment(ctx context.Context, account *social.SocialAccount, postID, text string) (string, error) {
+	commentURL := fmt.Sprintf("%s/%s/comments", InstagramGraphAPIURL, postID)
+	params := url.Values{
+		"message":      {text},
+		"access_token": {account.AccessToken},
+	}
+
+	resp, err := i.makeRequest(ctx, "POST", commentURL+"?"+params.Encode(), nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("comment creation failed: %w", err)
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return "", fmt.Errorf("failed to parse comment response: %w", err)
+	}
+
+	return result.ID, nil
+}
+
 // GetAnalytics retrieves analytics for a post
 func (i *InstagramPlatform) GetAnalytics(ctx context.Context, account *social.SocialAccount, postID string) (*social.AnalyticsData, error) {
 	// Instagram Insights API
@@ -234,6 +334,31 @@ func (i *InstagramPlatform) GetAnalytics(ctx context.Context, account *social.So
 	return analytics, nil
 }
 
+// GetAccountMetrics reads the account's follower and media counts with its
+// own token. Instagram doesn't report an account's total views.
+func (i *InstagramPlatform) GetAccountMetrics(ctx context.Context, account *social.SocialAccount) (*social.AccountMetricsSnapshot, error) {
+	metricsURL := fmt.Sprintf("%s/%s?fields=followers_count,media_count&access_token=%s",
+		InstagramGraphURL, account.AccountID, url.QueryEscape(account.AccessToken))
+
+	resp, err := i.makeRequest(ctx, "GET", metricsURL, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("account metrics fetch failed: %w", err)
+	}
+
+	var result struct {
+		FollowersCount int64 `json:"followers_count"`
+		MediaCount     int64 `json:"media_count"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse account metrics: %w", err)
+	}
+
+	return &social.AccountMetricsSnapshot{
+		Followers:  result.FollowersCount,
+		VideoCount: result.MediaCount,
+	}, nil
+}
+
 // DeletePost deletes a post
 func (i *InstagramPlatform) DeletePost(ctx context.Context, account *social.SocialAccount, postID string) error {
 	deleteURL := fmt.Sprintf("%s/%s?access_token=%s", InstagramGraphAPIURL, postID, account.AccessToken)
@@ -241,6 +366,13 @@ func (i *InstagramPlatform) DeletePost(ctx context.Context, account *social.Soci
 	return err
 }
 
+// RevokeToken removes the app's permissions for the account
+func (i *InstagramPlatform) RevokeToken(ctx context.Context, account *social.SocialAccount) error {
+	revokeURL := fmt.Sprintf("%s/me/permissions?access_token=%s", InstagramGraphAPIURL, account.AccessToken)
+	_, err := i.makeRequest(ctx, "DELETE", revokeURL, nil, nil)
+	return err
+}
+
 // GetTrends retrieves trending hashtags
 func (i *InstagramPlatform) GetTrends(ctx context.Context, account *social.SocialAccount, region string) ([]*social.PlatformTrend, error) {
 	// Instagram doesn't provide a direct trending API
@@ -304,7 +436,7 @@ func (i *InstagramPlatform) makeRequest(ctx context.Context, method, url string,
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, platformAPIError(resp.StatusCode, respBody)
 	}
 
 	return respBody, nil
@@ -323,7 +455,7 @@ func (i *InstagramPlatform) makeFormRequest(ctx context.Context, method, url str
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, platformAPIError(resp.StatusCode, respBody)
 	}
 
 	return respBody, nil
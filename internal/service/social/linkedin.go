@@ -19,14 +19,20 @@ type LinkedInPlatform struct {
 	clientSecret string
 	redirectURL  string
 	httpClient   *http.Client
+
+	// media opens the files to upload
+	media *mediaReader
 }
 
 // LinkedIn API endpoints
 const (
 	LinkedInAuthURL     = "https://www.linkedin.com/oauth/v2/authorization"
 	LinkedInTokenURL    = "https://www.linkedin.com/oauth/v2/accessToken"
+	LinkedInRevokeURL   = "https://www.linkedin.com/oauth/v2/revoke"
 	LinkedInAPIURL      = "https://api.linkedin.com/v2"
 	LinkedInUploadURL   = "https://api.linkedin.com/v2/assets?action=registerUpload"
+	LinkedInRestURL     = "https://api.linkedin.com/rest"
+	LinkedInVersion     = "202401"
 )
 
 // NewLinkedInPlatform creates a new LinkedIn platform instance
@@ -114,6 +120,11 @@ func (l *LinkedInPlatform) RefreshToken(ctx context.Context, account *social.Soc
 
 // UploadVideo uploads a video to LinkedIn
 func (l *LinkedInPlatform) UploadVideo(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	privacy, err := resolvePrivacy(social.PlatformLinkedIn, req.Privacy)
+	if err != nil {
+		return nil, err
+	}
+
 	// LinkedIn video upload process:
 	// 1. Register upload
 	// 2. Upload video bytes
@@ -174,17 +185,17 @@ func (l *LinkedInPlatform) UploadVideo(ctx context.Context, account *social.Soci
 				},
 				"shareMediaCategory": "VIDEO",
 				"media": []map[string]interface{}{
-					{
+					withAltText(map[string]interface{}{
 						"status":      "READY",
 						"description": map[string]string{"text": req.Title},
 						"media":       registerResp.Value.Asset,
 						"title":       map[string]string{"text": req.Title},
-					},
+					}, req.AltText),
 				},
 			},
 		},
 		"visibility": map[string]string{
-			"com.linkedin.ugc.MemberNetworkVisibility": req.Privacy,
+			"com.linkedin.ugc.MemberNetworkVisibility": privacy,
 		},
 	}
 
@@ -206,6 +217,195 @@ func (l *LinkedInPlatform) UploadVideo(ctx context.Context, account *social.Soci
 	}, nil
 }
 
+// UploadImage shares a single image to LinkedIn
+func (l *LinkedInPlatform) UploadImage(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	privacy, err := resolvePrivacy(social.PlatformLinkedIn, req.Privacy)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{
+		"Authorization":             "Bearer " + account.AccessToken,
+		"Content-Type":              "application/json",
+		"X-Restli-Protocol-Version": "2.0.0",
+	}
+
+	// Step 1: Register upload
+	registerData := map[string]interface{}{
+		"registerUploadRequest": map[string]interface{}{
+			"recipes": []string{"urn:li:digitalmediaRecipe:feedshare-image"},
+			"owner":   "urn:li:person:" + account.AccountID,
+			"serviceRelationships": []map[string]string{
+				{
+					"relationshipType": "OWNER",
+					"identifier":       "urn:li:userGeneratedContent",
+				},
+			},
+		},
+	}
+
+	jsonData, _ := json.Marshal(registerData)
+	resp, err := l.makeRequest(ctx, "POST", LinkedInUploadURL, jsonData, headers)
+	if err != nil {
+		return nil, fmt.Errorf("register upload failed: %w", err)
+	}
+
+	var registerResp struct {
+		Value struct {
+			Asset           string `json:"asset"`
+			UploadMechanism struct {
+				ComLinkedInDigitalmediaUploadingMediaUploadHttpRequest struct {
+					UploadURL string `json:"uploadUrl"`
+				} `json:"com.linkedin.digitalmedia.uploading.MediaUploadHttpRequest"`
+			} `json:"uploadMechanism"`
+		} `json:"value"`
+	}
+
+	if err := json.Unmarshal(resp, &registerResp); err != nil {
+		return nil, fmt.Errorf("failed to parse register response: %w", err)
+	}
+
+	// Step 2: Upload image bytes
+	uploadURL := registerResp.Value.UploadMechanism.ComLinkedInDigitalmediaUploadingMediaUploadHttpRequest.UploadURL
+	if err := l.uploadFile(ctx, account, uploadURL, req.VideoPath); err != nil {
+		return nil, fmt.Errorf("image upload failed: %w", err)
+	}
+
+	// Step 3: Create share
+	shareData := map[string]interface{}{
+		"author":         "urn:li:person:" + account.AccountID,
+		"lifecycleState": "PUBLISHED",
+		"specificContent": map[string]interface{}{
+			"com.linkedin.ugc.ShareContent": map[string]interface{}{
+				"shareCommentary": map[string]string{
+					"text": req.Description,
+				},
+				"shareMediaCategory": "IMAGE",
+				"media": []map[string]interface{}{
+					withAltText(map[string]interface{}{
+						"status": "READY",
+						"media":  registerResp.Value.Asset,
+						"title":  map[string]string{"text": req.Title},
+					}, req.AltText),
+				},
+			},
+		},
+		"visibility": map[string]string{
+			"com.linkedin.ugc.MemberNetworkVisibility": privacy,
+		},
+	}
+
+	shareJSON, _ := json.Marshal(shareData)
+	shareResp, err := l.makeRequest(ctx, "POST", LinkedInAPIURL+"/ugcPosts", shareJSON, headers)
+	if err != nil {
+		return nil, fmt.Errorf("share creation failed: %w", err)
+	}
+
+	shareID := extractURN(string(shareResp))
+
+	return &social.UploadResponse{
+		PlatformPostID: shareID,
+		PostURL:        fmt.Sprintf("https://www.linkedin.com/feed/update/%s", shareID),
+		Status:         "published",
+	}, nil
+}
+
+// SupportsAltText reports that shared images and videos take alt text
+func (l *LinkedInPlatform) SupportsAltText(mediaType social.MediaType) bool {
+	return mediaType != social.MediaTypeDocument
+}
+
+// withAltText adds alt text to a share's media entry when there is any
+func withAltText(media map[string]interface{}, altText string) map[string]interface{} {
+	if altText != "" {
+		media["altText"] = altText
+	}
+	return media
+}
+
+// UploadDocument shares a PDF document (carousel) to LinkedIn
+func (l *LinkedInPlatform) UploadDocument(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	privacy, err := resolvePrivacy(social.PlatformLinkedIn, req.Privacy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Documents use the versioned REST API:
+	// 1. Initialize upload
+	// 2. Upload document bytes
+	// 3. Create post referencing the document
+	owner := "urn:li:person:" + account.AccountID
+	headers := map[string]string{
+		"Authorization":             "Bearer " + account.AccessToken,
+		"Content-Type":              "application/json",
+		"LinkedIn-Version":          LinkedInVersion,
+		"X-Restli-Protocol-Version": "2.0.0",
+	}
+
+	// Step 1: Initialize upload
+	initData := map[string]interface{}{
+		"initializeUploadRequest": map[string]string{
+			"owner": owner,
+		},
+	}
+
+	jsonData, _ := json.Marshal(initData)
+	resp, err := l.makeRequest(ctx, "POST", LinkedInRestURL+"/documents?action=initializeUpload", jsonData, headers)
+	if err != nil {
+		return nil, fmt.Errorf("initialize upload failed: %w", err)
+	}
+
+	var initResp struct {
+		Value struct {
+			UploadURL string `json:"uploadUrl"`
+			Document  string `json:"document"`
+		} `json:"value"`
+	}
+
+	if err := json.Unmarshal(resp, &initResp); err != nil {
+		return nil, fmt.Errorf("failed to parse initialize response: %w", err)
+	}
+
+	// Step 2: Upload document bytes
+	if err := l.uploadFile(ctx, account, initResp.Value.UploadURL, req.VideoPath); err != nil {
+		return nil, fmt.Errorf("document upload failed: %w", err)
+	}
+
+	// Step 3: Create post
+	postData := map[string]interface{}{
+		"author":     owner,
+		"commentary": req.Description,
+		"visibility": privacy,
+		"distribution": map[string]interface{}{
+			"feedDistribution":               "MAIN_FEED",
+			"targetEntities":                 []string{},
+			"thirdPartyDistributionChannels": []string{},
+		},
+		"content": map[string]interface{}{
+			"media": map[string]string{
+				"title": req.Title,
+				"id":    initResp.Value.Document,
+			},
+		},
+		"lifecycleState":            "PUBLISHED",
+		"isReshareDisabledByAuthor": false,
+	}
+
+	postJSON, _ := json.Marshal(postData)
+	postResp, err := l.makeRequest(ctx, "POST", LinkedInRestURL+"/posts", postJSON, headers)
+	if err != nil {
+		return nil, fmt.Errorf("post creation failed: %w", err)
+	}
+
+	postID := extractURN(string(postResp))
+
+	return &social.UploadResponse{
+		PlatformPostID: postID,
+		PostURL:        fmt.Sprintf("https://www.linkedin.com/feed/update/%s", postID),
+		Status:         "published",
+	}, nil
+}
+
 // GetAnalytics retrieves analytics for a post
 func (l *LinkedInPlatform) GetAnalytics(ctx context.Context, account *social.SocialAccount, postID string) (*social.AnalyticsData, error) {
 	// LinkedIn analytics require organization access
@@ -231,6 +431,18 @@ func (l *LinkedInPlatform) DeletePost(ctx context.Context, account *social.Socia
 	return err
 }
 
+// RevokeToken revokes the account's access token
+func (l *LinkedInPlatform) RevokeToken(ctx context.Context, account *social.SocialAccount) error {
+	data := url.Values{
+		"client_id":     {l.clientID},
+		"client_secret": {l.clientSecret},
+		"token":         {account.AccessToken},
+	}
+
+	_, err := l.makeFormRequest(ctx, "POST", LinkedInRevokeURL, data)
+	return err
+}
+
 // GetTrends retrieves trending topics (LinkedIn doesn't have a public trends API)
 func (l *LinkedInPlatform) GetTrends(ctx context.Context, account *social.SocialAccount, region string) ([]*social.PlatformTrend, error) {
 	return []*social.PlatformTrend{
@@ -267,6 +479,23 @@ func (l *LinkedInPlatform) getUserInfo(ctx context.Context, accessToken string)
 	return result, nil
 }
 
+// uploadFile PUTs one of the user's uploads or a remote media file to a
+// LinkedIn upload URL
+func (l *LinkedInPlatform) uploadFile(ctx context.Context, account *social.SocialAccount, uploadURL, path string) error {
+	data, err := l.media.readMedia(ctx, account.UserID, path)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{
+		"Authorization": "Bearer " + account.AccessToken,
+		"Content-Type":  "application/octet-stream",
+	}
+
+	_, err = l.makeRequest(ctx, "PUT", uploadURL, data, headers)
+	return err
+}
+
 func (l *LinkedInPlatform) makeRequest(ctx context.Context, method, url string, body []byte, headers map[string]string) ([]byte, error) {
 	var bodyReader io.Reader
 	if body != nil {
@@ -294,7 +523,7 @@ func (l *LinkedInPlatform) makeRequest(ctx context.Context, method, url string,
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, platformAPIError(resp.StatusCode, respBody)
 	}
 
 	return respBody, nil
@@ -313,7 +542,7 @@ func (l *LinkedInPlatform) makeFormRequest(ctx context.Context, method, url stri
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, platformAPIError(resp.StatusCode, respBody)
 	}
 
 	return respBody, nil
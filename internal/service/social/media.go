@@ -0,0 +1,94 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+	"renderowl-api/internal/safehttp"
+)
+
+// mediaDownloadClient downloads remote media for platforms that are sent the
+// file itself. It only connects to public addresses, so a post's media URL
+// can't be used to reach the internal network.
+var mediaDownloadClient = safehttp.NewClient(30 * time.Minute)
+
+// isRemoteMedia reports whether a media path is a URL rather than a local file
+func isRemoteMedia(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// withUploadURLs names the user's uploads by the URL they're served from
+// for platforms that pull media from a URL, so a storage key passes
+// validation and publishing alike. Other requests are returned as they are.
+func (s *Service) withUploadURLs(account *social.SocialAccount, req *social.UploadRequest) *social.UploadRequest {
+	if s.mediaFiles == nil || uploadLimits[account.Platform].Media != mediaRemote {
+		return req
+	}
+	videoURL, videoOK := s.mediaFiles.UploadURL(account.UserID, req.VideoPath)
+	thumbnailURL, thumbnailOK := s.mediaFiles.UploadURL(account.UserID, req.ThumbnailURL)
+	if !videoOK && !thumbnailOK {
+		return req
+	}
+	resolved := *req
+	if videoOK {
+		resolved.VideoPath = videoURL
+	}
+	if thumbnailOK {
+		resolved.ThumbnailURL = thumbnailURL
+	}
+	return &resolved
+}
+
+// mediaReader opens the media of users' posts for platforms that upload
+// the bytes themselves. A path is either one of the user's own uploads or a
+// public URL; no other file on the server is ever opened.
+type mediaReader struct {
+	files MediaFiles
+}
+
+// openMedia opens one of the user's uploads, or downloads the media when the
+// path is a URL
+func (m *mediaReader) openMedia(ctx context.Context, userID, path string) (io.ReadCloser, error) {
+	if m != nil && m.files != nil {
+		if file, ok := m.files.OpenUpload(userID, path); ok {
+			return file, nil
+		}
+	}
+	if !isRemoteMedia(path) {
+		return nil, domain.NewValidationError(fmt.Sprintf("media %q is not one of your uploads", path))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := mediaDownloadClient.Do(req)
+	if errors.Is(err, safehttp.ErrBlockedAddress) {
+		return nil, domain.NewValidationError("media URL must be on a public host")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: status %d", path, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// readMedia reads the whole of one of the user's uploads or a remote file
+func (m *mediaReader) readMedia(ctx context.Context, userID, path string) ([]byte, error) {
+	media, err := m.openMedia(ctx, userID, path)
+	if err != nil {
+		return nil, err
+	}
+	defer media.Close()
+	return io.ReadAll(media)
+}
@@ -0,0 +1,44 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"renderowl-api/internal/domain"
+)
+
+func TestReadMediaOnlyOpensOwnUploadsAndPublicURLs(t *testing.T) {
+	media := &mediaReader{files: fakeMediaFiles{
+		"user-a": {"uploads/user-a/clip.mp4": 5},
+	}}
+	ctx := context.Background()
+
+	data, err := media.readMedia(ctx, "user-a", "uploads/user-a/clip.mp4")
+	if err != nil || len(data) != 5 {
+		t.Errorf("own upload: read %d bytes, %v, want 5 bytes", len(data), err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("internal server reached for %s", r.URL)
+	}))
+	defer server.Close()
+
+	for _, path := range []string{
+		"uploads/user-a/clip.mp4",
+		"/etc/passwd",
+		"../config.yaml",
+		server.URL + "/video.mp4",
+	} {
+		_, err := media.readMedia(ctx, "user-b", path)
+		if appErr, ok := domain.AsAppError(err); !ok || appErr.Code != domain.CodeValidation {
+			t.Errorf("user-b read %s: got %v, want a validation error", path, err)
+		}
+	}
+
+	var unset *mediaReader
+	if _, err := unset.readMedia(ctx, "user-a", "uploads/user-a/clip.mp4"); err == nil {
+		t.Error("a platform without uploads read a local path")
+	}
+}
@@ -0,0 +1,58 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// ErrContentFlagged means moderation flagged the post and publishing was blocked
+var ErrContentFlagged = domain.NewAppError(domain.CodeContentFlagged, http.StatusUnprocessableEntity, "content flagged by moderation")
+
+// Moderator checks a post's text, and thumbnail where supported, before it is published
+type Moderator interface {
+	Moderate(ctx context.Context, req *social.UploadRequest) (*social.ModerationResult, error)
+}
+
+// SetModerator enables the pre-publish moderation check. When enforce is
+// false flagged content is only logged and reported, not blocked.
+func (s *Service) SetModerator(moderator Moderator, enforce bool) {
+	s.moderator = moderator
+	s.enforceModeration = enforce
+}
+
+// moderate runs the moderation check for a post owned by userID. It returns
+// ErrContentFlagged when flagged content should be blocked. If the moderation
+// provider itself fails the post goes ahead, so an outage doesn't stop all
+// publishing.
+func (s *Service) moderate(ctx context.Context, userID string, req *social.UploadRequest) (*social.ModerationResult, error) {
+	if s.moderator == nil {
+		return nil, nil
+	}
+
+	result, err := s.moderator.Moderate(ctx, req)
+	if err != nil {
+		log.Printf("Moderation check failed for user %s, publishing unchecked: %v", userID, err)
+		return nil, nil
+	}
+	if !result.Flagged {
+		return result, nil
+	}
+
+	categories := strings.Join(result.Categories, ", ")
+	switch {
+	case !s.enforceModeration:
+		log.Printf("Moderation flagged content for user %s (%s), not enforced", userID, categories)
+	case req.ModerationOverride != "":
+		log.Printf("Moderation override: admin %s force-published content for user %s flagged for %s", req.ModerationOverride, userID, categories)
+		result.Overridden = true
+	default:
+		return result, fmt.Errorf("%w: %s", ErrContentFlagged, categories)
+	}
+	return result, nil
+}
@@ -0,0 +1,217 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// optimalTimePostLimit caps how many of the user's recent posts on the
+// account's platform are looked at to find when the account does best
+const optimalTimePostLimit = 100
+
+// minOptimalTimeSamples is how many analysed posts an account needs before
+// its own history is trusted over the platform's usual peak hours
+const minOptimalTimeSamples = 5
+
+// maxOptimalWindow bounds the range an optimal time is picked from
+const maxOptimalWindow = 7 * 24 * time.Hour
+
+// platformPeakHours are the local hours posts on each platform usually do
+// best, used until an account has enough history of its own
+var platformPeakHours = map[social.SocialPlatform][]int{
+	social.PlatformYouTube:   {12, 13, 14, 15, 17, 18, 19, 20},
+	social.PlatformTikTok:    {12, 19, 20, 21, 22},
+	social.PlatformInstagram: {11, 12, 13, 19, 20, 21},
+	social.PlatformTwitter:   {8, 9, 12, 17},
+	social.PlatformLinkedIn:  {8, 9, 10, 12},
+	social.PlatformFacebook:  {9, 12, 13, 15},
+}
+
+// defaultPeakHours are used for platforms without their own peak hours
+var defaultPeakHours = []int{12, 18, 19}
+
+// SetOptimalWindow makes a post go out at the best time within [from, to]
+// rather than at a fixed time. The time is picked per account when the post
+// comes due, so it uses the analytics synced by then.
+func SetOptimalWindow(post *social.ScheduledPost, from, to time.Time) error {
+	if !to.After(from) {
+		return domain.NewValidationError("the optimal time window must end after it starts")
+	}
+	if to.Sub(from) > maxOptimalWindow {
+		return domain.NewValidationError(fmt.Sprintf("the optimal time window can't be longer than %s", maxOptimalWindow))
+	}
+	if post.Recurring != nil {
+		return domain.NewValidationError("recurring posts need a fixed time")
+	}
+
+	if post.Metadata == nil {
+		post.Metadata = social.JSON{}
+	}
+	post.ScheduledAt = from
+	post.Metadata["optimalTime"] = map[string]interface{}{
+		"from": from.Format(time.RFC3339),
+		"to":   to.Format(time.RFC3339),
+	}
+	post.Metadata["autoScheduled"] = true
+	return nil
+}
+
+// OptimalWindowOf returns the window a post's time is to be picked from, or
+// false when it was scheduled for a fixed time
+func OptimalWindowOf(post *social.ScheduledPost) (from, to time.Time, ok bool) {
+	window, _ := post.Metadata["optimalTime"].(map[string]interface{})
+	if window == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	fromStr, _ := window["from"].(string)
+	toStr, _ := window["to"].(string)
+	from, err := time.Parse(time.RFC3339, fromStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	to, err = time.Parse(time.RFC3339, toStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// ResolveOptimalTime picks the best time from now until `until` to publish
+// a post to one of its accounts, records it on the post's platform entry and
+// returns it. The post's ScheduledAt becomes the earliest time picked so far.
+// Accounts of a post resolve concurrently, so only those fields are written.
+func (s *Service) ResolveOptimalTime(ctx context.Context, postID, accountID string, until time.Time) (time.Time, error) {
+	account, err := s.accounts.GetByID(ctx, accountID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrAccountNotFound, accountID)
+	}
+	if _, err := s.posts.GetByID(ctx, postID); err != nil {
+		return time.Time{}, ErrPostNotFound
+	}
+
+	from := time.Now()
+	at, err := s.bestPostingTime(ctx, account, from, until)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := s.posts.RecordOptimalTime(ctx, postID, accountID, at); err != nil {
+		return time.Time{}, fmt.Errorf("failed to record optimal time: %w", err)
+	}
+	return at, nil
+}
+
+// bestPostingTime returns the hour within [from, to] the account's posts do
+// best in, or from itself when that scores highest. Only times the account's
+// posting window allows are picked; when none are, the window's next opening
+// is used even if it's past to.
+func (s *Service) bestPostingTime(ctx context.Context, account *social.SocialAccount, from, to time.Time) (time.Time, error) {
+	loc := time.UTC
+	if rules := account.SchedulingRules; rules != nil {
+		if l, err := time.LoadLocation(rules.Timezone); err == nil {
+			loc = l
+		}
+	}
+	score, err := s.hourlyScores(ctx, account, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var best time.Time
+	bestScore := -1.0
+	consider := func(t time.Time) {
+		if !allowsPosting(account.SchedulingRules, t) {
+			return
+		}
+		if sc := score(t.In(loc)); sc > bestScore {
+			best, bestScore = t, sc
+		}
+	}
+
+	consider(from)
+	local := from.In(loc)
+	hour := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, loc)
+	for t := hour.Add(time.Hour); t.Before(to); t = t.Add(time.Hour) {
+		consider(t)
+	}
+
+	if best.IsZero() {
+		return nextPostingTime(account.SchedulingRules, from)
+	}
+	return best, nil
+}
+
+// hourlyScores returns how well the account's posts do by local hour of the
+// week, from their latest synced analytics. Hours of the week without posts
+// use the average for that hour of the day. Accounts with too little history
+// score the platform's peak hours instead.
+func (s *Service) hourlyScores(ctx context.Context, account *social.SocialAccount, loc *time.Location) (func(time.Time) float64, error) {
+	posts, _, err := s.posts.List(ctx, account.UserID, social.PostFilter{
+		Platform: account.Platform,
+		Limit:    optimalTimePostLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	var weekTotal, weekCount [7][24]float64
+	var dayTotal, dayCount [24]float64
+	samples := 0
+	for _, post := range posts {
+		for _, platformPost := range post.Platforms {
+			if platformPost.AccountID != account.ID || platformPost.PlatformPostID == "" || platformPost.PublishedAt == nil {
+				continue
+			}
+			data, err := s.analytics.GetLatestByPost(ctx, platformPost.PlatformPostID)
+			if err != nil {
+				continue
+			}
+			published := platformPost.PublishedAt.In(loc)
+			day, hour := published.Weekday(), published.Hour()
+			value := engagementScore(data)
+			weekTotal[day][hour] += value
+			weekCount[day][hour]++
+			dayTotal[hour] += value
+			dayCount[hour]++
+			samples++
+		}
+	}
+
+	if samples < minOptimalTimeSamples {
+		peak := make(map[int]bool)
+		hours, ok := platformPeakHours[account.Platform]
+		if !ok {
+			hours = defaultPeakHours
+		}
+		for _, hour := range hours {
+			peak[hour] = true
+		}
+		return func(t time.Time) float64 {
+			if peak[t.Hour()] {
+				return 1
+			}
+			return 0
+		}, nil
+	}
+
+	return func(t time.Time) float64 {
+		day, hour := t.Weekday(), t.Hour()
+		if weekCount[day][hour] > 0 {
+			return weekTotal[day][hour] / weekCount[day][hour]
+		}
+		if dayCount[hour] > 0 {
+			return dayTotal[hour] / dayCount[hour]
+		}
+		return 0
+	}, nil
+}
+
+// engagementScore weighs a post's interactions above its views, since a
+// like, comment or share says more about the audience being there
+func engagementScore(data *social.AnalyticsData) float64 {
+	return float64(data.Views) + 10*float64(data.Likes+data.Comments+data.Shares)
+}
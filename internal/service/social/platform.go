@@ -2,6 +2,10 @@ package social
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+
+	"renderowl-api/internal/domain"
 	"renderowl-api/internal/domain/social"
 )
 
@@ -32,6 +36,75 @@ type Platform interface {
 	GetTrends(ctx context.Context, account *social.SocialAccount, region string) ([]*social.PlatformTrend, error)
 }
 
+// TokenRevoker is implemented by platforms that can revoke an account's
+// OAuth grant, so disconnecting or erasing a user also invalidates tokens
+type TokenRevoker interface {
+	RevokeToken(ctx context.Context, account *social.SocialAccount) error
+}
+
+// ImageUploader is implemented by platforms that accept single-image posts
+type ImageUploader interface {
+	UploadImage(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error)
+}
+
+// DocumentUploader is implemented by platforms that accept document (PDF) posts
+type DocumentUploader interface {
+	UploadDocument(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error)
+}
+
+// Commenter is implemented by platforms that can comment on a published
+// post, used to post an upload's first comment
+type Commenter interface {
+	PostComment(ctx context.Context, account *social.SocialAccount, postID, text string) (string, error)
+}
+
+// ThumbnailSetter is implemented by platforms that set a custom thumbnail on
+// a video after it is uploaded. Platforms that take the cover as part of the
+// upload itself report the outcome on the UploadResponse instead.
+type ThumbnailSetter interface {
+	SetThumbnail(ctx context.Context, account *social.SocialAccount, postID, thumbnail string) error
+}
+
+// AccountMetricsReader is implemented by platforms that report an account's
+// follower, view and video counts, read with the account's own token
+type AccountMetricsReader interface {
+	GetAccountMetrics(ctx context.Context, account *social.SocialAccount) (*social.AccountMetricsSnapshot, error)
+}
+
+// AltTexter is implemented by platforms that attach alt text to uploaded
+// media. They send the request's AltText as part of the upload.
+type AltTexter interface {
+	SupportsAltText(mediaType social.MediaType) bool
+}
+
+// SupportsMediaType reports whether a platform can publish the given media type
+func SupportsMediaType(p Platform, mediaType social.MediaType) bool {
+	switch mediaType {
+	case "", social.MediaTypeVideo:
+		return true
+	case social.MediaTypeImage:
+		_, ok := p.(ImageUploader)
+		return ok
+	case social.MediaTypeDocument:
+		_, ok := p.(DocumentUploader)
+		return ok
+	}
+	return false
+}
+
+// platformAPIError classifies a failed platform API response so clients can
+// tell rate limiting and revoked tokens apart from other failures
+func platformAPIError(status int, body []byte) error {
+	message := fmt.Sprintf("API error %d: %s", status, string(body))
+	switch status {
+	case http.StatusTooManyRequests:
+		return domain.NewAppError(domain.CodeRateLimited, http.StatusTooManyRequests, message)
+	case http.StatusUnauthorized:
+		return domain.NewAppError(domain.CodePlatformExpired, http.StatusConflict, message)
+	}
+	return domain.NewAppError(domain.CodePlatformError, http.StatusBadGateway, message)
+}
+
 // PlatformRegistry manages all available platforms
 type PlatformRegistry struct {
 	platforms map[social.SocialPlatform]Platform
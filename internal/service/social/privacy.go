@@ -0,0 +1,90 @@
+package social
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// Platform-neutral privacy values accepted on upload requests
+const (
+	PrivacyPublic   = "public"
+	PrivacyUnlisted = "unlisted"
+	PrivacyPrivate  = "private"
+	PrivacyFriends  = "friends" // friends, mutual followers or connections, depending on the platform
+)
+
+// ErrUnsupportedPrivacy means the platform has no equivalent for the requested privacy
+var ErrUnsupportedPrivacy = domain.NewAppError(domain.CodeValidation, http.StatusBadRequest, "unsupported privacy")
+
+// privacyValues maps the neutral privacy values to what each platform's API expects
+var privacyValues = map[social.SocialPlatform]map[string]string{
+	social.PlatformYouTube: {
+		PrivacyPublic:   "public",
+		PrivacyUnlisted: "unlisted",
+		PrivacyPrivate:  "private",
+	},
+	social.PlatformTikTok: {
+		PrivacyPublic:  "PUBLIC_TO_EVERYONE",
+		PrivacyFriends: "MUTUAL_FOLLOW_FRIENDS",
+		PrivacyPrivate: "SELF_ONLY",
+	},
+	social.PlatformLinkedIn: {
+		PrivacyPublic:  "PUBLIC",
+		PrivacyFriends: "CONNECTIONS",
+	},
+	// Page videos are either published or kept as unpublished drafts
+	social.PlatformFacebook: {
+		PrivacyPublic:  "true",
+		PrivacyPrivate: "false",
+	},
+	// Instagram and X posts are always public
+	social.PlatformInstagram: {PrivacyPublic: "public"},
+	social.PlatformTwitter:   {PrivacyPublic: "public"},
+}
+
+// builtinPrivacy applies when neither the request nor the config sets a privacy.
+// YouTube and TikTok stay private so nothing goes live by accident.
+var builtinPrivacy = map[social.SocialPlatform]string{
+	social.PlatformYouTube: PrivacyPrivate,
+	social.PlatformTikTok:  PrivacyPrivate,
+}
+
+// resolvePrivacy maps a request's privacy to the platform's own value. It
+// accepts the neutral values, "connections" as an alias for friends, and the
+// platform's native value; anything else is rejected.
+func resolvePrivacy(platform social.SocialPlatform, privacy string) (string, error) {
+	privacy = strings.ToLower(strings.TrimSpace(privacy))
+	if privacy == "" {
+		privacy = PrivacyPublic
+		if builtin, ok := builtinPrivacy[platform]; ok {
+			privacy = builtin
+		}
+	}
+	if privacy == "connections" {
+		privacy = PrivacyFriends
+	}
+
+	values := privacyValues[platform]
+	if value, ok := values[privacy]; ok {
+		return value, nil
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, privacy) {
+			return value, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s does not support %q", ErrUnsupportedPrivacy, platform, privacy)
+}
+
+// privacyFor returns the request's privacy, falling back to the platform default from config
+func (s *Service) privacyFor(platform social.SocialPlatform, privacy string) string {
+	if privacy == "" {
+		return s.defaultPrivacy[platform]
+	}
+	return privacy
+}
@@ -0,0 +1,170 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// ErrPostCopyUnavailable means a quick publish came without copy and none
+// can be written for it
+var ErrPostCopyUnavailable = domain.NewAppError(domain.CodeValidation, http.StatusBadRequest, "title or description required: post copy generation is not configured")
+
+// PostCopyWriter writes a post's title, description and hashtags for a video
+type PostCopyWriter interface {
+	WritePostCopy(ctx context.Context, req *social.PostCopyRequest) (*social.PostCopy, error)
+}
+
+// SetPostCopyWriter enables quick publishing without copy of one's own
+func (s *Service) SetPostCopyWriter(writer PostCopyWriter) {
+	s.postCopy = writer
+}
+
+// QuickPublishResult is the outcome of a quick publish
+type QuickPublishResult struct {
+	Copy     *social.PostCopy                  `json:"copy"`
+	Results  map[string]*social.UploadResponse `json:"results"`
+	NotReady []*PlatformReadiness              `json:"notReady"` // accounts that failed validation and weren't posted to
+}
+
+// QuickPublish posts a video to the user's accounts straight away. With no
+// title or description given, the copy is written for it, seeded by topic
+// and fitted to the strictest of the target platforms; the video must then
+// pass the media check before it is read. Each account is validated first;
+// those that fail are reported and the rest published.
+func (s *Service) QuickPublish(ctx context.Context, userID string, accountIDs []string, req *social.UploadRequest, topic string) (*QuickPublishResult, error) {
+	accounts := make([]*social.SocialAccount, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		account, err := s.GetAccount(ctx, accountID, userID)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	postCopy := &social.PostCopy{Title: req.Title, Description: req.Description, Tags: req.Tags}
+	if req.Title == "" && req.Description == "" {
+		if s.postCopy == nil {
+			return nil, ErrPostCopyUnavailable
+		}
+		mediaCheck, _ := s.validateMedia(ctx, userID, req.VideoPath, req.MediaType, mediaAny)
+		if !mediaCheck.Passed {
+			return nil, domain.NewValidationError(mediaCheck.Message)
+		}
+		copyReq := postCopyRequest(s.copySource(userID, req.VideoPath), topic, accounts)
+		written, err := s.postCopy.WritePostCopy(ctx, copyReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write post copy: %w", err)
+		}
+		postCopy = fitPostCopy(written, copyReq)
+		postCopy.Generated = true
+
+		withCopy := *req
+		withCopy.Title = postCopy.Title
+		withCopy.Description = postCopy.Description
+		withCopy.Tags = postCopy.Tags
+		req = &withCopy
+	}
+
+	result := &QuickPublishResult{
+		Copy:     postCopy,
+		Results:  map[string]*social.UploadResponse{},
+		NotReady: []*PlatformReadiness{},
+	}
+	var ready []string
+	for _, account := range accounts {
+		readiness := s.ValidateUpload(ctx, account, req, time.Now())
+		if !readiness.Ready {
+			result.NotReady = append(result.NotReady, readiness)
+			continue
+		}
+		ready = append(ready, account.ID)
+	}
+	if len(ready) == 0 {
+		return result, nil
+	}
+
+	results, err := s.CrossPost(ctx, ready, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	result.Results = results
+	return result, nil
+}
+
+// copySource returns the URL the copy writer may read a validated video
+// from: the upload's own URL for one of the user's uploads, or else the URL
+// that passed the media check
+func (s *Service) copySource(userID, videoPath string) string {
+	if s.mediaFiles != nil {
+		if url, ok := s.mediaFiles.UploadURL(userID, videoPath); ok {
+			return url
+		}
+	}
+	return videoPath
+}
+
+// postCopyRequest asks for copy that fits every account's platform
+func postCopyRequest(videoURL, topic string, accounts []*social.SocialAccount) *social.PostCopyRequest {
+	req := &social.PostCopyRequest{VideoURL: videoURL, Topic: topic}
+	seen := make(map[social.SocialPlatform]bool)
+	for _, account := range accounts {
+		if seen[account.Platform] {
+			continue
+		}
+		seen[account.Platform] = true
+		req.Platforms = append(req.Platforms, account.Platform)
+
+		limits := uploadLimits[account.Platform]
+		req.MaxTitle = strictestLimit(req.MaxTitle, limits.MaxTitle)
+		req.MaxDescription = strictestLimit(req.MaxDescription, limits.MaxDescription)
+		req.MaxTags = strictestLimit(req.MaxTags, limits.MaxTags)
+	}
+	return req
+}
+
+// strictestLimit returns the lower of two limits, where zero means none
+func strictestLimit(current, limit int) int {
+	if current == 0 || (limit > 0 && limit < current) {
+		return limit
+	}
+	return current
+}
+
+// fitPostCopy cuts written copy down to the request's limits, in case the
+// writer overshot them
+func fitPostCopy(postCopy *social.PostCopy, req *social.PostCopyRequest) *social.PostCopy {
+	fitted := *postCopy
+	fitted.Title = truncateWords(strings.TrimSpace(fitted.Title), req.MaxTitle)
+	fitted.Description = truncateWords(strings.TrimSpace(fitted.Description), req.MaxDescription)
+	fitted.Tags = nil
+	for _, tag := range postCopy.Tags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag != "" {
+			fitted.Tags = append(fitted.Tags, tag)
+		}
+	}
+	if req.MaxTags > 0 && len(fitted.Tags) > req.MaxTags {
+		fitted.Tags = fitted.Tags[:req.MaxTags]
+	}
+	return &fitted
+}
+
+// truncateWords cuts text to at most limit characters at a word boundary;
+// a limit of zero leaves it whole
+func truncateWords(text string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	cut := string([]rune(text)[:limit])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut)
+}
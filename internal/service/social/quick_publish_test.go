@@ -0,0 +1,60 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// copyRecorder records the video URLs it is asked to write copy for
+type copyRecorder struct {
+	videoURLs []string
+}
+
+func (w *copyRecorder) WritePostCopy(ctx context.Context, req *social.PostCopyRequest) (*social.PostCopy, error) {
+	w.videoURLs = append(w.videoURLs, req.VideoURL)
+	return &social.PostCopy{Title: "Title", Description: "Description"}, nil
+}
+
+func TestQuickPublishOnlyWritesCopyFromValidatedMedia(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("quick publish reached the internal server")
+	}))
+	defer server.Close()
+
+	writer := &copyRecorder{}
+	s := &Service{
+		accounts: &metricsAccounts{accounts: map[string]*social.SocialAccount{
+			"account-a": {ID: "account-a", UserID: "user-a", Platform: social.PlatformYouTube},
+		}},
+		mediaFiles: fakeMediaFiles{"user-a": {"uploads/user-a/clip.mp4": 1024}},
+		postCopy:   writer,
+	}
+
+	for _, path := range []string{server.URL + "/video.mp4", "/etc/passwd", "uploads/user-b/clip.mp4"} {
+		_, err := s.QuickPublish(context.Background(), "user-a", []string{"account-a"}, &social.UploadRequest{VideoPath: path}, "topic")
+		appErr, ok := domain.AsAppError(err)
+		if !ok || appErr.Code != domain.CodeValidation {
+			t.Errorf("quick publish of %s: err = %v, want a validation error", path, err)
+		}
+	}
+	if len(writer.videoURLs) != 0 {
+		t.Fatalf("copy was written for unvalidated media %v", writer.videoURLs)
+	}
+
+	s.registry = NewPlatformRegistry()
+	result, err := s.QuickPublish(context.Background(), "user-a", []string{"account-a"}, &social.UploadRequest{VideoPath: "uploads/user-a/clip.mp4"}, "topic")
+	if err != nil {
+		t.Fatalf("quick publish of an upload: %v", err)
+	}
+	if len(writer.videoURLs) != 1 || writer.videoURLs[0] != "https://files.example.com/uploads/user-a/clip.mp4" {
+		t.Errorf("copy written from %v, want the upload's URL", writer.videoURLs)
+	}
+	if !result.Copy.Generated {
+		t.Error("copy was not marked generated")
+	}
+}
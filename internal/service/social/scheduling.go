@@ -0,0 +1,170 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// ErrOutsidePostingWindow means a post was scheduled outside an account's
+// posting window and the account's rules reject rather than shift it
+var ErrOutsidePostingWindow = domain.NewAppError(domain.CodeValidation, http.StatusBadRequest, "outside the account's posting window")
+
+// ErrScheduledInPast means a post was scheduled for a time that has passed,
+// or is too close to now to be scheduled
+var ErrScheduledInPast = domain.NewAppError(domain.CodeValidation, http.StatusBadRequest, "scheduledAt must be in the future")
+
+// defaultScheduleLead is how far ahead posts must be scheduled unless
+// SetMinScheduleLead says otherwise
+const defaultScheduleLead = time.Minute
+
+// maxWindowSearch bounds the search for the next allowed time; any valid
+// rule set has an allowed hour within a week
+const maxWindowSearch = 8 * 24 * time.Hour
+
+// SetMinScheduleLead sets how far ahead of now posts must be scheduled
+func (s *Service) SetMinScheduleLead(lead time.Duration) {
+	s.minScheduleLead = lead
+}
+
+// CheckScheduledTime rejects a scheduled time less than the minimum lead
+// time from now. Posts that should go out straight away are published now
+// rather than scheduled for a past time.
+func (s *Service) CheckScheduledTime(t time.Time) error {
+	if t.Before(s.now().Add(s.minScheduleLead)) {
+		return fmt.Errorf("%w: it must be at least %s from now", ErrScheduledInPast, s.minScheduleLead)
+	}
+	return nil
+}
+
+// validateSchedulingRules checks a rule set leaves some time to post in
+func validateSchedulingRules(rules *social.SchedulingRules) error {
+	if rules == nil {
+		return nil
+	}
+	if _, err := time.LoadLocation(rules.Timezone); err != nil {
+		return domain.NewValidationError(fmt.Sprintf("unknown timezone %q", rules.Timezone))
+	}
+	if w := rules.AllowedHours; w != nil {
+		if w.Start < 0 || w.Start > 23 || w.End < 1 || w.End > 24 || w.Start == w.End {
+			return domain.NewValidationError("allowedHours needs a start of 0-23 and a different end of 1-24")
+		}
+	}
+	blackout := make(map[int]bool)
+	for _, day := range rules.BlackoutDays {
+		if day < 0 || day > 6 {
+			return domain.NewValidationError("blackoutDays must be 0 (Sunday) to 6 (Saturday)")
+		}
+		blackout[day] = true
+	}
+	if len(blackout) == 7 {
+		return domain.NewValidationError("blackoutDays can't cover the whole week")
+	}
+	return nil
+}
+
+// allowsPosting reports whether t is inside the rules' posting window
+func allowsPosting(rules *social.SchedulingRules, t time.Time) bool {
+	if rules == nil {
+		return true
+	}
+	if loc, err := time.LoadLocation(rules.Timezone); err == nil {
+		t = t.In(loc)
+	}
+
+	for _, day := range rules.BlackoutDays {
+		if int(t.Weekday()) == day {
+			return false
+		}
+	}
+
+	w := rules.AllowedHours
+	if w == nil {
+		return true
+	}
+	hour := t.Hour()
+	if w.Start < w.End {
+		return hour >= w.Start && hour < w.End
+	}
+	return hour >= w.Start || hour < w.End
+}
+
+// nextPostingTime returns t if the rules allow posting then, otherwise the
+// start of the next allowed hour
+func nextPostingTime(rules *social.SchedulingRules, t time.Time) (time.Time, error) {
+	if allowsPosting(rules, t) {
+		return t, nil
+	}
+	// Windows open on the local hour, so only hour boundaries need checking
+	local := t
+	if loc, err := time.LoadLocation(rules.Timezone); err == nil {
+		local = t.In(loc)
+	}
+	hour := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, local.Location())
+	for next := hour.Add(time.Hour); next.Sub(t) <= maxWindowSearch; next = next.Add(time.Hour) {
+		if allowsPosting(rules, next) {
+			return next, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: no allowed time within a week", ErrOutsidePostingWindow)
+}
+
+// schedulingTime fits a post's time into the posting windows of all its
+// accounts, shifting it forward or rejecting it as each account's rules say
+func schedulingTime(accounts []*social.SocialAccount, t time.Time) (time.Time, error) {
+	// Shifting into one account's window can push the time out of another's,
+	// so repeat until every account accepts it
+	for deadline := t.Add(maxWindowSearch); !t.After(deadline); {
+		shifted := false
+		for _, account := range accounts {
+			rules := account.SchedulingRules
+			if allowsPosting(rules, t) {
+				continue
+			}
+			if rules.Reject {
+				return time.Time{}, fmt.Errorf("%w: %s account %s", ErrOutsidePostingWindow, account.Platform, account.AccountName)
+			}
+			next, err := nextPostingTime(rules, t)
+			if err != nil {
+				return time.Time{}, err
+			}
+			t, shifted = next, true
+		}
+		if !shifted {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: the accounts' windows don't overlap within a week", ErrOutsidePostingWindow)
+}
+
+// UpdateSchedulingRules sets or, with nil rules, clears an account's posting window
+func (s *Service) UpdateSchedulingRules(ctx context.Context, accountID, userID string, rules *social.SchedulingRules) (*social.SocialAccount, error) {
+	account, err := s.GetAccount(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSchedulingRules(rules); err != nil {
+		return nil, err
+	}
+
+	account.SchedulingRules = rules
+	if err := s.accounts.Update(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to save scheduling rules: %w", err)
+	}
+	return account, nil
+}
+
+// NextPostingTime returns the earliest time from t that the account's posting
+// window allows. Posts already queued are shifted rather than rejected, since
+// rejecting at publish time would silently drop them.
+func (s *Service) NextPostingTime(ctx context.Context, accountID string, t time.Time) (time.Time, error) {
+	account, err := s.accounts.GetByID(ctx, accountID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrAccountNotFound, err)
+	}
+	return nextPostingTime(account.SchedulingRules, t)
+}
@@ -0,0 +1,73 @@
+package social
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckScheduledTimeAcrossZones(t *testing.T) {
+	// Times are written with the offset each zone has at that instant, so
+	// the cases around daylight saving changes read as local wall clocks
+	tests := []struct {
+		name      string
+		zone      string
+		now       string
+		scheduled string
+		lead      time.Duration
+		wantErr   bool
+	}{
+		{name: "inside the lead", zone: "UTC", now: "2026-06-01T12:00:00Z", scheduled: "2026-06-01T12:00:30Z", lead: time.Minute, wantErr: true},
+		{name: "exactly the lead", zone: "UTC", now: "2026-06-01T12:00:00Z", scheduled: "2026-06-01T12:01:00Z", lead: time.Minute},
+		{name: "in the past", zone: "UTC", now: "2026-06-01T12:00:00Z", scheduled: "2026-06-01T11:00:00Z", lead: time.Minute, wantErr: true},
+		{name: "zone ahead of UTC", zone: "Asia/Tokyo", now: "2026-06-01T21:00:00+09:00", scheduled: "2026-06-01T21:05:00+09:00", lead: 10 * time.Minute, wantErr: true},
+		{name: "zone behind UTC", zone: "America/Los_Angeles", now: "2026-06-01T05:00:00-07:00", scheduled: "2026-06-01T05:30:00-07:00", lead: 10 * time.Minute},
+		{name: "half-hour offset", zone: "Asia/Kolkata", now: "2026-06-01T17:30:00+05:30", scheduled: "2026-06-01T17:45:00+05:30", lead: 10 * time.Minute},
+		// New York skips 02:00-03:00 on 8 March: 01:30 to 03:15 is 45 minutes
+		{name: "across spring forward, inside the lead", zone: "America/New_York", now: "2026-03-08T01:30:00-05:00", scheduled: "2026-03-08T03:15:00-04:00", lead: time.Hour, wantErr: true},
+		{name: "across spring forward, outside the lead", zone: "America/New_York", now: "2026-03-08T01:30:00-05:00", scheduled: "2026-03-08T03:15:00-04:00", lead: 30 * time.Minute},
+		// New York repeats 01:00-02:00 on 1 November: the second 01:20 is
+		// 30 minutes after the first 01:50
+		{name: "across fall back, earlier wall clock", zone: "America/New_York", now: "2026-11-01T01:50:00-04:00", scheduled: "2026-11-01T01:20:00-05:00", lead: 15 * time.Minute},
+		{name: "across fall back, later wall clock already passed", zone: "America/New_York", now: "2026-11-01T01:20:00-05:00", scheduled: "2026-11-01T01:50:00-04:00", lead: time.Minute, wantErr: true},
+		// London skips 01:00-02:00 on 29 March: 00:50 to 02:05 is 15 minutes
+		{name: "london spring forward", zone: "Europe/London", now: "2026-03-29T00:50:00Z", scheduled: "2026-03-29T02:05:00+01:00", lead: 20 * time.Minute, wantErr: true},
+		// Sydney repeats 02:00-03:00 on 5 April: the second 02:10 is 30
+		// minutes after the first 02:40
+		{name: "sydney fall back", zone: "Australia/Sydney", now: "2026-04-05T02:40:00+11:00", scheduled: "2026-04-05T02:10:00+10:00", lead: 20 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Skipf("no zone data for %s: %v", tt.zone, err)
+			}
+			now := mustParseInZone(t, tt.now, loc)
+			scheduled := mustParseInZone(t, tt.scheduled, loc)
+
+			s := &Service{minScheduleLead: tt.lead, now: func() time.Time { return now }}
+			err = s.CheckScheduledTime(scheduled)
+			if tt.wantErr && !errors.Is(err, ErrScheduledInPast) {
+				t.Errorf("CheckScheduledTime(%s) = %v, want ErrScheduledInPast", tt.scheduled, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("CheckScheduledTime(%s) = %v, want accepted", tt.scheduled, err)
+			}
+		})
+	}
+}
+
+// mustParseInZone parses an RFC 3339 time into loc, failing if the offset
+// written isn't the one loc has at that instant
+func mustParseInZone(t *testing.T, value string, loc *time.Location) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("parse %s: %v", value, err)
+	}
+	local := parsed.In(loc)
+	if got := local.Format(time.RFC3339); got != value {
+		t.Fatalf("%s is %s in %s", value, got, loc)
+	}
+	return local
+}
@@ -3,17 +3,57 @@ package social
 import (
 	"context"
 	"fmt"
+	"log"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"renderowl-api/internal/domain"
 	"renderowl-api/internal/domain/social"
 )
 
+// Ownership errors; callers should treat both as 404 so IDs can't be probed
+var (
+	ErrAccountNotFound = domain.NewAppError(domain.CodeNotFound, http.StatusNotFound, "account not found")
+	ErrPostNotFound    = domain.NewAppError(domain.CodeNotFound, http.StatusNotFound, "post not found")
+)
+
+// ErrUnsupportedMediaType means the platform can't publish the requested media type
+var ErrUnsupportedMediaType = domain.NewAppError(domain.CodeUnsupportedMediaType, http.StatusBadRequest, "unsupported media type")
+
+// ErrReconnectRequired means the token can't be refreshed and the user must go through OAuth again
+var ErrReconnectRequired = domain.NewAppError(domain.CodePlatformExpired, http.StatusConflict, "reconnect required")
+
 // Service manages all social media operations
 type Service struct {
-	registry  *PlatformRegistry
-	accounts  AccountRepository
-	posts     PostRepository
-	analytics AnalyticsRepository
+	registry       *PlatformRegistry
+	accounts       AccountRepository
+	posts          PostRepository
+	analytics      AnalyticsRepository
+	defaultPrivacy map[social.SocialPlatform]string
+
+	moderator         Moderator
+	enforceModeration bool
+
+	altText AltTextGenerator
+
+	postCopy PostCopyWriter
+
+	fingerprinter   Fingerprinter
+	fingerprints    FingerprintRepository
+	duplicateWindow time.Duration
+
+	metricsClient    AccountMetricsClient
+	metricsSnapshots AccountMetricsRepository
+
+	minScheduleLead time.Duration
+	// now is the clock scheduled times are checked against
+	now func() time.Time
+
+	mediaFiles MediaFiles
+	// media is shared with the platforms that upload files themselves
+	media *mediaReader
 }
 
 // AccountRepository defines account storage operations
@@ -31,9 +71,11 @@ type PostRepository interface {
 	Create(ctx context.Context, post *social.ScheduledPost) error
 	GetByID(ctx context.Context, id string) (*social.ScheduledPost, error)
 	GetByUser(ctx context.Context, userID string, limit, offset int) ([]*social.ScheduledPost, error)
+	List(ctx context.Context, userID string, filter social.PostFilter) ([]*social.ScheduledPost, int64, error)
 	GetPending(ctx context.Context, before string) ([]*social.ScheduledPost, error)
 	Update(ctx context.Context, post *social.ScheduledPost) error
 	UpdateStatus(ctx context.Context, id string, status social.PostStatus, errorMsg string) error
+	RecordOptimalTime(ctx context.Context, postID, accountID string, at time.Time) error
 	Delete(ctx context.Context, id string) error
 }
 
@@ -52,10 +94,14 @@ func NewService(
 	analytics AnalyticsRepository,
 ) *Service {
 	return &Service{
-		registry:  registry,
-		accounts:  accounts,
-		posts:     posts,
-		analytics: analytics,
+		registry:        registry,
+		accounts:        accounts,
+		posts:           posts,
+		analytics:       analytics,
+		defaultPrivacy:  make(map[social.SocialPlatform]string),
+		minScheduleLead: defaultScheduleLead,
+		now:             time.Now,
+		media:           &mediaReader{},
 	}
 }
 
@@ -68,6 +114,10 @@ func (s *Service) InitializePlatforms() {
 			os.Getenv("YOUTUBE_CLIENT_SECRET"),
 			os.Getenv("YOUTUBE_REDIRECT_URL"),
 		)
+		yt.media = s.media
+		if err := yt.SetDefaultCategory(os.Getenv("YOUTUBE_DEFAULT_CATEGORY")); err != nil {
+			log.Printf("Warning: ignoring default YouTube category: %v", err)
+		}
 		s.registry.Register(yt)
 	}
 
@@ -98,6 +148,7 @@ func (s *Service) InitializePlatforms() {
 			os.Getenv("TWITTER_CLIENT_SECRET"),
 			os.Getenv("TWITTER_REDIRECT_URL"),
 		)
+		tw.media = s.media
 		s.registry.Register(tw)
 	}
 
@@ -108,6 +159,7 @@ func (s *Service) InitializePlatforms() {
 			os.Getenv("LINKEDIN_CLIENT_SECRET"),
 			os.Getenv("LINKEDIN_REDIRECT_URL"),
 		)
+		li.media = s.media
 		s.registry.Register(li)
 	}
 
@@ -120,13 +172,31 @@ func (s *Service) InitializePlatforms() {
 		)
 		s.registry.Register(fb)
 	}
+
+	// Per-platform default privacy, e.g. YOUTUBE_DEFAULT_PRIVACY=unlisted
+	for _, platform := range s.registry.PlatformNames() {
+		privacy := os.Getenv(strings.ToUpper(string(platform)) + "_DEFAULT_PRIVACY")
+		if privacy == "" {
+			continue
+		}
+		if _, err := resolvePrivacy(platform, privacy); err != nil {
+			log.Printf("Warning: ignoring default privacy for %s: %v", platform, err)
+			continue
+		}
+		s.defaultPrivacy[platform] = privacy
+	}
+}
+
+// errPlatformNotConfigured reports a platform with no credentials set up
+func errPlatformNotConfigured(platform social.SocialPlatform) error {
+	return domain.NewAppError(domain.CodePlatformNotConfigured, http.StatusBadRequest, fmt.Sprintf("platform %s not configured", platform))
 }
 
 // GetAuthURL returns the OAuth URL for a platform
 func (s *Service) GetAuthURL(platform social.SocialPlatform, state string) (string, error) {
 	p, ok := s.registry.Get(platform)
 	if !ok {
-		return "", fmt.Errorf("platform %s not configured", platform)
+		return "", errPlatformNotConfigured(platform)
 	}
 	return p.GetAuthURL(state), nil
 }
@@ -135,7 +205,7 @@ func (s *Service) GetAuthURL(platform social.SocialPlatform, state string) (stri
 func (s *Service) ConnectAccount(ctx context.Context, platform social.SocialPlatform, code string, userID string) (*social.SocialAccount, error) {
 	p, ok := s.registry.Get(platform)
 	if !ok {
-		return nil, fmt.Errorf("platform %s not configured", platform)
+		return nil, errPlatformNotConfigured(platform)
 	}
 
 	account, err := p.ExchangeCode(ctx, code)
@@ -156,109 +226,514 @@ func (s *Service) GetAccounts(ctx context.Context, userID string) ([]*social.Soc
 	return s.accounts.GetByUser(ctx, userID)
 }
 
-// GetAccount returns a specific account
-func (s *Service) GetAccount(ctx context.Context, accountID string) (*social.SocialAccount, error) {
-	return s.accounts.GetByID(ctx, accountID)
+// GetAccount returns a specific account owned by the user
+func (s *Service) GetAccount(ctx context.Context, accountID, userID string) (*social.SocialAccount, error) {
+	account, err := s.accounts.GetByID(ctx, accountID)
+	if err != nil || account.UserID != userID {
+		return nil, ErrAccountNotFound
+	}
+	return account, nil
 }
 
-// DisconnectAccount removes a connected account
-func (s *Service) DisconnectAccount(ctx context.Context, accountID string) error {
+// VerifyAccountOwner checks that every account belongs to the user
+func (s *Service) VerifyAccountOwner(ctx context.Context, userID string, accountIDs ...string) error {
+	for _, accountID := range accountIDs {
+		if _, err := s.GetAccount(ctx, accountID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DisconnectAccount removes a connected account owned by the user
+func (s *Service) DisconnectAccount(ctx context.Context, accountID, userID string) error {
+	if _, err := s.GetAccount(ctx, accountID, userID); err != nil {
+		return err
+	}
 	return s.accounts.Delete(ctx, accountID)
 }
 
-// UploadVideo uploads a video to a platform
-func (s *Service) UploadVideo(ctx context.Context, accountID string, req *social.UploadRequest) (*social.UploadResponse, error) {
+// RevokeAccountToken revokes an account's OAuth grant on platforms that support it
+func (s *Service) RevokeAccountToken(ctx context.Context, account *social.SocialAccount) error {
+	p, ok := s.registry.Get(account.Platform)
+	if !ok {
+		return errPlatformNotConfigured(account.Platform)
+	}
+
+	revoker, ok := p.(TokenRevoker)
+	if !ok {
+		return nil
+	}
+
+	return revoker.RevokeToken(ctx, account)
+}
+
+// RefreshAccountToken forces a token refresh for an account owned by the user.
+// The account is returned with its updated status even when the refresh fails.
+func (s *Service) RefreshAccountToken(ctx context.Context, accountID, userID string) (*social.SocialAccount, error) {
+	account, err := s.GetAccount(ctx, accountID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := s.registry.Get(account.Platform)
+	if !ok {
+		return nil, errPlatformNotConfigured(account.Platform)
+	}
+
+	refreshErr := p.RefreshToken(ctx, account)
+	if err := s.accounts.Update(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to save account: %w", err)
+	}
+
+	if refreshErr != nil {
+		if account.Status == social.StatusExpired {
+			return account, fmt.Errorf("%w: %v", ErrReconnectRequired, refreshErr)
+		}
+		return account, refreshErr
+	}
+
+	return account, nil
+}
+
+// Upload publishes media to a platform once it passes moderation, routing to
+// the platform API that matches the request's media type
+func (s *Service) Upload(ctx context.Context, accountID string, req *social.UploadRequest) (*social.UploadResponse, error) {
 	account, err := s.accounts.GetByID(ctx, accountID)
 	if err != nil {
-		return nil, fmt.Errorf("account not found: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrAccountNotFound, err)
+	}
+
+	moderation, err := s.moderate(ctx, account.UserID, req)
+	if err != nil {
+		return nil, err
 	}
 
+	// Scheduled posts were checked for duplicates when they were scheduled
+	var fingerprint string
+	if req.ScheduledPostID == "" {
+		fingerprint = s.fingerprint(ctx, account.UserID, req.MediaType, req.VideoPath)
+		if !req.AllowDuplicate {
+			if err := s.checkDuplicate(ctx, account, fingerprint); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	req = s.withAltText(ctx, req, account.Platform)
+	resp, err := s.upload(ctx, account, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Moderation = moderation
+	s.recordFingerprint(ctx, account, &social.VideoFingerprint{
+		Fingerprint: fingerprint,
+		VideoPath:   req.VideoPath,
+		PostURL:     resp.PostURL,
+		PostedAt:    time.Now(),
+	})
+	return resp, nil
+}
+
+// upload publishes media to an account without moderating it
+func (s *Service) upload(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
 	p, ok := s.registry.Get(account.Platform)
 	if !ok {
-		return nil, fmt.Errorf("platform %s not configured", account.Platform)
+		return nil, errPlatformNotConfigured(account.Platform)
+	}
+
+	if !SupportsMediaType(p, req.MediaType) {
+		return nil, fmt.Errorf("%w: %s does not support %s posts", ErrUnsupportedMediaType, account.Platform, req.MediaType)
+	}
+
+	// Copy before applying the default so cross-posts don't share it
+	if privacy := s.privacyFor(account.Platform, req.Privacy); privacy != req.Privacy {
+		withDefault := *req
+		withDefault.Privacy = privacy
+		req = &withDefault
+	}
+	req = s.withUploadURLs(account, req)
+
+	var resp *social.UploadResponse
+	var err error
+	switch req.MediaType {
+	case social.MediaTypeImage:
+		resp, err = p.(ImageUploader).UploadImage(ctx, account, req)
+	case social.MediaTypeDocument:
+		resp, err = p.(DocumentUploader).UploadDocument(ctx, account, req)
+	default:
+		resp, err = p.UploadVideo(ctx, account, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FirstComment != "" {
+		resp.FirstComment = postFirstComment(ctx, p, account, resp.PlatformPostID, req.FirstComment)
+	}
+	if (req.ThumbnailURL != "" || req.CoverFrameMs != nil) && resp.Thumbnail == nil {
+		resp.Thumbnail = setThumbnail(ctx, p, account, resp.PlatformPostID, req.ThumbnailURL)
+	}
+	resp.AltText = altTextResult(p, req)
+
+	return resp, nil
+}
+
+// postFirstComment comments on a freshly published post. It is best-effort:
+// failures are reported in the result rather than failing the upload.
+func postFirstComment(ctx context.Context, p Platform, account *social.SocialAccount, postID, text string) *social.CommentResult {
+	commenter, ok := p.(Commenter)
+	if !ok {
+		return &social.CommentResult{Status: social.CommentStatusUnsupported}
+	}
+
+	commentID, err := commenter.PostComment(ctx, account, postID, text)
+	if err != nil {
+		log.Printf("Failed to post first comment on %s post %s: %v", account.Platform, postID, err)
+		return &social.CommentResult{Status: social.CommentStatusFailed, Error: err.Error()}
 	}
 
-	return p.UploadVideo(ctx, account, req)
+	return &social.CommentResult{Status: social.CommentStatusPosted, CommentID: commentID}
 }
 
-// CrossPost uploads a video to multiple platforms
-func (s *Service) CrossPost(ctx context.Context, accountIDs []string, req *social.UploadRequest) (map[string]*social.UploadResponse, error) {
+// setThumbnail sets a freshly uploaded video's cover. Like the first comment
+// it is best-effort, so failures are reported rather than failing the upload.
+func setThumbnail(ctx context.Context, p Platform, account *social.SocialAccount, postID, thumbnail string) *social.ThumbnailResult {
+	setter, ok := p.(ThumbnailSetter)
+	if !ok || thumbnail == "" {
+		return &social.ThumbnailResult{Status: social.ThumbnailStatusUnsupported}
+	}
+
+	if err := setter.SetThumbnail(ctx, account, postID, thumbnail); err != nil {
+		log.Printf("Failed to set thumbnail on %s post %s: %v", account.Platform, postID, err)
+		return &social.ThumbnailResult{Status: social.ThumbnailStatusFailed, Error: err.Error()}
+	}
+
+	return &social.ThumbnailResult{Status: social.ThumbnailStatusSet}
+}
+
+// CrossPost uploads media to multiple platforms. Overrides tailor the title,
+// description, tags or privacy per account or platform; every account's copy
+// is checked against its platform's limits before anything is published.
+func (s *Service) CrossPost(ctx context.Context, accountIDs []string, req *social.UploadRequest, overrides []social.PostOverride) (map[string]*social.UploadResponse, error) {
 	results := make(map[string]*social.UploadResponse)
 
+	accounts := make(map[string]*social.SocialAccount, len(accountIDs))
+	requests := make(map[string]*social.UploadRequest, len(accountIDs))
 	for _, accountID := range accountIDs {
-		resp, err := s.UploadVideo(ctx, accountID, req)
+		account, err := s.accounts.GetByID(ctx, accountID)
+		if err != nil {
+			continue
+		}
+		accountReq := applyPostOverride(req, account, overrides)
+		if limits, ok := uploadLimits[account.Platform]; ok {
+			if check := validateCaption(accountReq, account.Platform, limits); !check.Passed {
+				return nil, domain.NewValidationError(fmt.Sprintf("%s account %s: %s", account.Platform, account.AccountName, check.Message))
+			}
+		}
+		accounts[accountID] = account
+		requests[accountID] = accountReq
+	}
+
+	// Accounts sharing the same copy only need it moderated once
+	moderations := make(map[string]*social.ModerationResult)
+	if s.moderator != nil {
+		for _, accountID := range accountIDs {
+			account, ok := accounts[accountID]
+			if !ok {
+				continue
+			}
+			key := moderationKey(requests[accountID])
+			if _, done := moderations[key]; done {
+				continue
+			}
+			moderation, err := s.moderate(ctx, account.UserID, requests[accountID])
+			if err != nil {
+				return nil, err
+			}
+			moderations[key] = moderation
+		}
+	}
+
+	// Every account posts the same video, so it's fingerprinted once and
+	// checked against each account before anything is published. The
+	// accounts are all the same user's.
+	var userID string
+	for _, account := range accounts {
+		userID = account.UserID
+		break
+	}
+	var fingerprint string
+	if req.ScheduledPostID == "" {
+		fingerprint = s.fingerprint(ctx, userID, req.MediaType, req.VideoPath)
+		if !req.AllowDuplicate {
+			for _, accountID := range accountIDs {
+				if account, ok := accounts[accountID]; ok {
+					if err := s.checkDuplicate(ctx, account, fingerprint); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
+	// Every account posts the same media, so its alt text is generated once
+	platforms := make([]social.SocialPlatform, 0, len(accounts))
+	for _, account := range accounts {
+		platforms = append(platforms, account.Platform)
+	}
+	if withAlt := s.withAltText(ctx, req, platforms...); withAlt != req {
+		for accountID, accountReq := range requests {
+			tailored := *accountReq
+			tailored.AltText = withAlt.AltText
+			requests[accountID] = &tailored
+		}
+	}
+
+	for _, accountID := range accountIDs {
+		account, ok := accounts[accountID]
+		if !ok {
+			results[accountID] = &social.UploadResponse{
+				Status: "failed",
+			}
+			continue
+		}
+		resp, err := s.upload(ctx, account, requests[accountID])
 		if err != nil {
 			results[accountID] = &social.UploadResponse{
 				Status: "failed",
 			}
 		} else {
+			resp.Moderation = moderations[moderationKey(requests[accountID])]
 			results[accountID] = resp
+			s.recordFingerprint(ctx, account, &social.VideoFingerprint{
+				Fingerprint: fingerprint,
+				VideoPath:   req.VideoPath,
+				PostURL:     resp.PostURL,
+				PostedAt:    time.Now(),
+			})
 		}
 	}
 
 	return results, nil
 }
 
-// SchedulePost creates a scheduled post
+// applyPostOverride returns the request an account should be sent: the shared
+// request with the account's override applied, or its platform's when the
+// account has none
+func applyPostOverride(req *social.UploadRequest, account *social.SocialAccount, overrides []social.PostOverride) *social.UploadRequest {
+	var override *social.PostOverride
+	for i := range overrides {
+		o := &overrides[i]
+		if o.AccountID == account.ID {
+			override = o
+			break
+		}
+		if o.AccountID == "" && o.Platform == account.Platform && override == nil {
+			override = o
+		}
+	}
+	if override == nil {
+		return req
+	}
+
+	tailored := *req
+	if override.Title != "" {
+		tailored.Title = override.Title
+	}
+	if override.Description != "" {
+		tailored.Description = override.Description
+	}
+	if len(override.Tags) > 0 {
+		tailored.Tags = override.Tags
+	}
+	if override.Privacy != "" {
+		tailored.Privacy = override.Privacy
+	}
+	return &tailored
+}
+
+// moderationKey identifies the copy a moderation check looks at
+func moderationKey(req *social.UploadRequest) string {
+	return req.Title + "\x00" + req.Description
+}
+
+// SchedulePost creates a scheduled post, moving it into the accounts' posting
+// windows or rejecting it with ErrOutsidePostingWindow. Unless its metadata
+// sets allowDuplicate, a video nearly duplicating one recently posted to an
+// account is rejected with a *DuplicateError.
 func (s *Service) SchedulePost(ctx context.Context, post *social.ScheduledPost) error {
 	// Validate all accounts exist and belong to user
+	accounts := make([]*social.SocialAccount, 0, len(post.Platforms))
 	for _, platformPost := range post.Platforms {
 		account, err := s.accounts.GetByID(ctx, platformPost.AccountID)
 		if err != nil {
-			return fmt.Errorf("account %s not found", platformPost.AccountID)
+			return fmt.Errorf("%w: %s", ErrAccountNotFound, platformPost.AccountID)
 		}
 		if account.UserID != post.UserID {
-			return fmt.Errorf("account %s does not belong to user", platformPost.AccountID)
+			return fmt.Errorf("%w: %s", ErrAccountNotFound, platformPost.AccountID)
+		}
+		accounts = append(accounts, account)
+	}
+
+	videoPath, _ := post.Metadata["videoPath"].(string)
+	mediaType, _ := post.Metadata["mediaType"].(string)
+	fingerprint := s.fingerprint(ctx, post.UserID, social.MediaType(mediaType), videoPath)
+	if allow, _ := post.Metadata["allowDuplicate"].(bool); !allow {
+		for _, account := range accounts {
+			if err := s.checkDuplicate(ctx, account, fingerprint); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.schedule(ctx, post, accounts); err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		s.recordFingerprint(ctx, account, &social.VideoFingerprint{
+			Fingerprint: fingerprint,
+			VideoPath:   videoPath,
+			PostID:      post.ID,
+			PostedAt:    post.ScheduledAt,
+		})
+	}
+	return nil
+}
+
+// schedule fits a post into its accounts' posting windows and creates it
+func (s *Service) schedule(ctx context.Context, post *social.ScheduledPost, accounts []*social.SocialAccount) error {
+	// Posts going out at the optimal time have it picked within the
+	// accounts' windows when they come due
+	if from, to, ok := OptimalWindowOf(post); ok {
+		for _, account := range accounts {
+			if rules := account.SchedulingRules; rules != nil && rules.Reject {
+				if next, err := nextPostingTime(rules, from); err != nil || !next.Before(to) {
+					return fmt.Errorf("%w: %s account %s", ErrOutsidePostingWindow, account.Platform, account.AccountName)
+				}
+			}
+		}
+		post.Status = social.PostStatusScheduled
+		return s.posts.Create(ctx, post)
+	}
+
+	// Fit the time into the accounts' posting windows, keeping the requested
+	// time on the post when it had to move
+	scheduledAt, err := schedulingTime(accounts, post.ScheduledAt)
+	if err != nil {
+		return err
+	}
+	if !scheduledAt.Equal(post.ScheduledAt) {
+		if post.Metadata == nil {
+			post.Metadata = social.JSON{}
 		}
+		post.Metadata["requestedAt"] = post.ScheduledAt.Format(time.RFC3339)
+		post.ScheduledAt = scheduledAt
 	}
 
 	post.Status = social.PostStatusScheduled
 	return s.posts.Create(ctx, post)
 }
 
-// GetScheduledPosts returns scheduled posts for a user
-func (s *Service) GetScheduledPosts(ctx context.Context, userID string, limit, offset int) ([]*social.ScheduledPost, error) {
-	return s.posts.GetByUser(ctx, userID, limit, offset)
+// GetScheduledPosts returns a page of a user's scheduled posts matching the
+// filter, along with the total number of matches
+func (s *Service) GetScheduledPosts(ctx context.Context, userID string, filter social.PostFilter) ([]*social.ScheduledPost, int64, error) {
+	return s.posts.List(ctx, userID, filter)
 }
 
 // CancelScheduledPost cancels a scheduled post
 func (s *Service) CancelScheduledPost(ctx context.Context, postID string, userID string) error {
 	post, err := s.posts.GetByID(ctx, postID)
-	if err != nil {
-		return err
-	}
-	if post.UserID != userID {
-		return fmt.Errorf("unauthorized")
+	if err != nil || post.UserID != userID {
+		return ErrPostNotFound
 	}
 
 	return s.posts.UpdateStatus(ctx, postID, social.PostStatusCancelled, "")
 }
 
 // GetAnalytics retrieves analytics for a post
-func (s *Service) GetAnalytics(ctx context.Context, accountID string, postID string) (*social.AnalyticsData, error) {
-	account, err := s.accounts.GetByID(ctx, accountID)
+func (s *Service) GetAnalytics(ctx context.Context, accountID, userID string, postID string) (*social.AnalyticsData, error) {
+	account, err := s.GetAccount(ctx, accountID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("account not found: %w", err)
+		return nil, err
 	}
 
 	p, ok := s.registry.Get(account.Platform)
 	if !ok {
-		return nil, fmt.Errorf("platform %s not configured", account.Platform)
+		return nil, errPlatformNotConfigured(account.Platform)
 	}
 
 	return p.GetAnalytics(ctx, account, postID)
 }
 
+// SyncPostAnalytics pulls current metrics for a published platform post and
+// records them against the platform's post ID
+func (s *Service) SyncPostAnalytics(ctx context.Context, platformPost *social.PlatformPost) (*social.AnalyticsData, error) {
+	account, err := s.accounts.GetByID(ctx, platformPost.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, platformPost.AccountID)
+	}
+
+	p, ok := s.registry.Get(account.Platform)
+	if !ok {
+		return nil, errPlatformNotConfigured(account.Platform)
+	}
+
+	data, err := p.GetAnalytics(ctx, account, platformPost.PlatformPostID)
+	if err != nil {
+		return nil, err
+	}
+
+	data.PostID = platformPost.PlatformPostID
+	data.Platform = platformPost.Platform
+	data.RecordedAt = time.Now()
+	if err := s.analytics.Create(ctx, data); err != nil {
+		return nil, fmt.Errorf("failed to save analytics: %w", err)
+	}
+	return data, nil
+}
+
+// GetPostAnalytics returns the latest synced analytics for each published
+// instance of a scheduled post owned by the user
+func (s *Service) GetPostAnalytics(ctx context.Context, postID, userID string) ([]*social.PublishedPostAnalytics, error) {
+	post, err := s.posts.GetByID(ctx, postID)
+	if err != nil || post.UserID != userID {
+		return nil, ErrPostNotFound
+	}
+
+	results := make([]*social.PublishedPostAnalytics, 0, len(post.Platforms))
+	for _, platformPost := range post.Platforms {
+		if platformPost.PlatformPostID == "" {
+			continue
+		}
+
+		result := &social.PublishedPostAnalytics{
+			PlatformPostID: platformPost.PlatformPostID,
+			Platform:       platformPost.Platform,
+			AccountID:      platformPost.AccountID,
+			PostURL:        platformPost.PostURL,
+			PublishedAt:    platformPost.PublishedAt,
+		}
+		// Posts that haven't been synced yet are returned without analytics
+		if data, err := s.analytics.GetLatestByPost(ctx, platformPost.PlatformPostID); err == nil {
+			result.Analytics = data
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
 // GetTrends retrieves trends for a platform
-func (s *Service) GetTrends(ctx context.Context, accountID string, region string) ([]*social.PlatformTrend, error) {
-	account, err := s.accounts.GetByID(ctx, accountID)
+func (s *Service) GetTrends(ctx context.Context, accountID, userID string, region string) ([]*social.PlatformTrend, error) {
+	account, err := s.GetAccount(ctx, accountID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("account not found: %w", err)
+		return nil, err
 	}
 
 	p, ok := s.registry.Get(account.Platform)
 	if !ok {
-		return nil, fmt.Errorf("platform %s not configured", account.Platform)
+		return nil, errPlatformNotConfigured(account.Platform)
 	}
 
 	return p.GetTrends(ctx, account, region)
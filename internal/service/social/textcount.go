@@ -0,0 +1,125 @@
+package social
+
+import (
+	"regexp"
+	"unicode"
+
+	"renderowl-api/internal/domain/social"
+)
+
+// Twitter counts every URL as a t.co link of this length
+const twitterURLLength = 23
+
+var urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s]+|\b(?:[a-z0-9-]+\.)+(?:com|net|org|io|co|me|ly|tv|gg|app|dev)(?:/[^\s]*)?`)
+
+// captionLength counts a caption the way the platform does: Twitter's
+// weighted count, and grapheme clusters everywhere else
+func captionLength(platform social.SocialPlatform, text string) int {
+	if platform == social.PlatformTwitter {
+		return twitterLength(text)
+	}
+	return len(graphemes(text))
+}
+
+// twitterLength implements twitter-text's weighted length: URLs count as 23,
+// emoji as 2, Latin-range characters as 1 and everything else as 2
+func twitterLength(text string) int {
+	length := 0
+	last := 0
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		length += twitterWeight(text[last:loc[0]]) + twitterURLLength
+		last = loc[1]
+	}
+	return length + twitterWeight(text[last:])
+}
+
+func twitterWeight(text string) int {
+	weight := 0
+	for _, cluster := range graphemes(text) {
+		if isEmojiCluster(cluster) {
+			weight += 2
+			continue
+		}
+		for _, r := range cluster {
+			weight += twitterRuneWeight(r)
+		}
+	}
+	return weight
+}
+
+// twitterRuneWeight mirrors the light-weight ranges in twitter-text's v3 config
+func twitterRuneWeight(r rune) int {
+	switch {
+	case r <= 0x10FF,
+		r >= 0x2000 && r <= 0x200D,
+		r >= 0x2010 && r <= 0x201F,
+		r >= 0x2032 && r <= 0x2037:
+		return 1
+	}
+	return 2
+}
+
+// graphemes splits text into user-perceived characters. It covers the
+// cases that matter for captions: combining marks, variation selectors,
+// skin tones, ZWJ emoji sequences, keycaps, tag sequences and flags.
+func graphemes(text string) [][]rune {
+	var clusters [][]rune
+	joinNext := false
+	for _, r := range text {
+		n := len(clusters)
+		switch {
+		case n == 0:
+		case joinNext, extendsCluster(r):
+			clusters[n-1] = append(clusters[n-1], r)
+			joinNext = r == 0x200D
+			continue
+		case isRegionalIndicator(r) && len(clusters[n-1]) == 1 && isRegionalIndicator(clusters[n-1][0]):
+			clusters[n-1] = append(clusters[n-1], r)
+			continue
+		}
+		clusters = append(clusters, []rune{r})
+		joinNext = false
+	}
+	return clusters
+}
+
+// extendsCluster reports whether r attaches to the preceding character
+func extendsCluster(r rune) bool {
+	switch {
+	case r == 0x200D, // zero width joiner
+		r >= 0xFE00 && r <= 0xFE0F,   // variation selectors
+		r >= 0x1F3FB && r <= 0x1F3FF, // skin tone modifiers
+		r >= 0xE0020 && r <= 0xE007F: // tag characters
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isEmojiCluster reports whether a grapheme renders as an emoji, including
+// keycaps and text characters forced to emoji presentation
+func isEmojiCluster(cluster []rune) bool {
+	if isEmoji(cluster[0]) {
+		return true
+	}
+	for _, r := range cluster[1:] {
+		if r == 0xFE0F || r == 0x20E3 {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF,
+		r >= 0x2600 && r <= 0x27BF,
+		r >= 0x2B00 && r <= 0x2BFF,
+		r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122:
+		return true
+	}
+	return false
+}
@@ -8,7 +8,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"time"
 
 	"renderowl-api/internal/domain/social"
@@ -26,6 +25,7 @@ type TikTokPlatform struct {
 const (
 	TikTokAuthURL        = "https://www.tiktok.com/v2/auth/authorize/"
 	TikTokTokenURL       = "https://open.tiktokapis.com/v2/oauth/token/"
+	TikTokRevokeURL      = "https://open.tiktokapis.com/v2/oauth/revoke/"
 	TikTokUploadURL      = "https://open.tiktokapis.com/v2/post/publish/video/init/"
 	TikTokQueryUploadURL = "https://open.tiktokapis.com/v2/post/publish/video/status/"
 	TikTokUserInfoURL    = "https://open.tiktokapis.com/v2/user/info/"
@@ -51,7 +51,7 @@ func (t *TikTokPlatform) GetAuthURL(state string) string {
 	params := map[string]string{
 		"client_key":    t.clientKey,
 		"redirect_uri":  t.redirectURL,
-		"scope":         "video.publish,user.info.basic",
+		"scope":         "video.publish,user.info.basic,user.info.stats",
 		"response_type": "code",
 		"state":         state,
 	}
@@ -150,6 +150,11 @@ func (t *TikTokPlatform) RefreshToken(ctx context.Context, account *social.Socia
 
 // UploadVideo uploads a video to TikTok
 func (t *TikTokPlatform) UploadVideo(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	privacy, err := resolvePrivacy(social.PlatformTikTok, req.Privacy)
+	if err != nil {
+		return nil, err
+	}
+
 	// Refresh token if needed
 	if account.TokenExpiry != nil && account.TokenExpiry.Before(time.Now()) {
 		if err := t.RefreshToken(ctx, account); err != nil {
@@ -157,24 +162,36 @@ func (t *TikTokPlatform) UploadVideo(ctx context.Context, account *social.Social
 		}
 	}
 
-	// Get file info (for future use with file size validation)
-	_, err := os.Stat(req.VideoPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to stat video file: %w", err)
+	// TikTok pulls the video itself, so it has to be reachable by URL
+	if !isRemoteMedia(req.VideoPath) {
+		return nil, fmt.Errorf("TikTok needs the video as a public URL, not a local file")
+	}
+
+	postInfo := map[string]interface{}{
+		"title":       req.Title,
+		"description": req.Description,
+		"privacy_level": privacy,
+	}
+
+	// TikTok picks the cover from a frame of the video; it takes no custom image
+	var thumbnail *social.ThumbnailResult
+	switch {
+	case req.CoverFrameMs != nil:
+		postInfo["video_cover_timestamp_ms"] = *req.CoverFrameMs
+		thumbnail = &social.ThumbnailResult{Status: social.ThumbnailStatusSet}
+	case req.ThumbnailURL != "":
+		thumbnail = &social.ThumbnailResult{
+			Status: social.ThumbnailStatusUnsupported,
+			Error:  "TikTok only supports choosing a cover frame",
+		}
 	}
-	
-	// fileSize := fileInfo.Size() // Available for future size validation
 
 	// Initialize upload
 	initData := map[string]interface{}{
-		"post_info": map[string]string{
-			"title":       req.Title,
-			"description": req.Description,
-			"privacy_level": req.Privacy,
-		},
+		"post_info": postInfo,
 		"source_info": map[string]interface{}{
 			"source": "PULL_FROM_URL",
-			"url":    req.VideoPath, // For direct upload, we'd need to host the file first
+			"url":    req.VideoPath,
 		},
 	}
 
@@ -205,6 +222,7 @@ func (t *TikTokPlatform) UploadVideo(ctx context.Context, account *social.Social
 		PlatformPostID: uploadResp.Data.PublishID,
 		PostURL:        fmt.Sprintf("https://tiktok.com/@%s/video/%s", account.AccountName, uploadResp.Data.PublishID),
 		Status:         "processing",
+		Thumbnail:      thumbnail,
 	}, nil
 }
 
@@ -226,6 +244,34 @@ func (t *TikTokPlatform) DeletePost(ctx context.Context, account *social.SocialA
 	return fmt.Errorf("TikTok does not support deleting posts via API")
 }
 
+// RevokeToken revokes the account's access token
+func (t *TikTokPlatform) RevokeToken(ctx context.Context, account *social.SocialAccount) error {
+	data := url.Values{
+		"client_key":    {t.clientKey},
+		"client_secret": {t.clientSecret},
+		"token":         {account.AccessToken},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", TikTokRevokeURL, bytes.NewBufferString(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return platformAPIError(resp.StatusCode, body)
+	}
+
+	return nil
+}
+
 // GetTrends retrieves trending sounds and hashtags
 func (t *TikTokPlatform) GetTrends(ctx context.Context, account *social.SocialAccount, region string) ([]*social.PlatformTrend, error) {
 	// TikTok Research API or unofficial endpoints would be needed
@@ -257,6 +303,36 @@ func (t *TikTokPlatform) GetTrends(ctx context.Context, account *social.SocialAc
 
 // Helper methods
 
+// GetAccountMetrics reads the account's follower and video counts with its
+// own token. TikTok doesn't report an account's total views.
+func (t *TikTokPlatform) GetAccountMetrics(ctx context.Context, account *social.SocialAccount) (*social.AccountMetricsSnapshot, error) {
+	headers := map[string]string{
+		"Authorization": fmt.Sprintf("Bearer %s", account.AccessToken),
+	}
+
+	resp, err := t.makeRequest(ctx, "GET", TikTokUserInfoURL+"?fields=follower_count,video_count", nil, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Data struct {
+			User struct {
+				FollowerCount int64 `json:"follower_count"`
+				VideoCount    int64 `json:"video_count"`
+			} `json:"user"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse user info: %w", err)
+	}
+
+	return &social.AccountMetricsSnapshot{
+		Followers:  result.Data.User.FollowerCount,
+		VideoCount: result.Data.User.VideoCount,
+	}, nil
+}
+
 func (t *TikTokPlatform) getUserInfo(ctx context.Context, accessToken string) (map[string]interface{}, error) {
 	headers := map[string]string{
 		"Authorization": fmt.Sprintf("Bearer %s", accessToken),
@@ -312,7 +388,7 @@ func (t *TikTokPlatform) makeRequest(ctx context.Context, method, url string, bo
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, platformAPIError(resp.StatusCode, respBody)
 	}
 
 	return respBody, nil
@@ -9,7 +9,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"time"
 
 	"renderowl-api/internal/domain/social"
@@ -23,14 +22,19 @@ type TwitterPlatform struct {
 	clientSecret string
 	redirectURL  string
 	httpClient   *http.Client
+
+	// media opens the files to upload
+	media *mediaReader
 }
 
 // Twitter API v2 endpoints
 const (
-	TwitterAuthURL      = "https://twitter.com/i/oauth2/authorize"
-	TwitterTokenURL     = "https://api.twitter.com/2/oauth2/token"
-	TwitterAPIURL       = "https://api.twitter.com/2"
-	TwitterUploadURL    = "https://upload.twitter.com/1.1/media/upload.json"
+	TwitterAuthURL          = "https://twitter.com/i/oauth2/authorize"
+	TwitterTokenURL         = "https://api.twitter.com/2/oauth2/token"
+	TwitterRevokeURL        = "https://api.twitter.com/2/oauth2/revoke"
+	TwitterAPIURL           = "https://api.twitter.com/2"
+	TwitterUploadURL        = "https://upload.twitter.com/1.1/media/upload.json"
+	TwitterMediaMetadataURL = "https://upload.twitter.com/1.1/media/metadata/create.json"
 )
 
 // NewTwitterPlatform creates a new Twitter/X platform instance
@@ -160,8 +164,41 @@ func (t *TwitterPlatform) RefreshToken(ctx context.Context, account *social.Soci
 	return nil
 }
 
+// RevokeToken revokes the account's access and refresh tokens
+func (t *TwitterPlatform) RevokeToken(ctx context.Context, account *social.SocialAccount) error {
+	auth := base64.StdEncoding.EncodeToString([]byte(t.clientID + ":" + t.clientSecret))
+	headers := map[string]string{
+		"Authorization": "Basic " + auth,
+		"Content-Type":  "application/x-www-form-urlencoded",
+	}
+
+	tokens := map[string]string{
+		"access_token":  account.AccessToken,
+		"refresh_token": account.RefreshToken,
+	}
+	for hint, token := range tokens {
+		if token == "" {
+			continue
+		}
+		data := url.Values{
+			"token":           {token},
+			"token_type_hint": {hint},
+			"client_id":       {t.clientID},
+		}
+		if _, err := t.makeRequest(ctx, "POST", TwitterRevokeURL, []byte(data.Encode()), headers); err != nil {
+			return fmt.Errorf("token revoke failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // UploadVideo uploads a video to Twitter
 func (t *TwitterPlatform) UploadVideo(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	if _, err := resolvePrivacy(social.PlatformTwitter, req.Privacy); err != nil {
+		return nil, err
+	}
+
 	// Refresh token if needed
 	if account.TokenExpiry != nil && account.TokenExpiry.Before(time.Now()) {
 		if err := t.RefreshToken(ctx, account); err != nil {
@@ -174,11 +211,70 @@ func (t *TwitterPlatform) UploadVideo(ctx context.Context, account *social.Socia
 	if err != nil {
 		return nil, fmt.Errorf("video upload failed: %w", err)
 	}
+	if err := t.setAltText(ctx, account, mediaID, req.AltText); err != nil {
+		return nil, err
+	}
 
 	// Step 2: Create tweet with media
+	return t.createTweet(ctx, account, req.Description, mediaID)
+}
+
+// UploadImage tweets a single image
+func (t *TwitterPlatform) UploadImage(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	if _, err := resolvePrivacy(social.PlatformTwitter, req.Privacy); err != nil {
+		return nil, err
+	}
+
+	// Refresh token if needed
+	if account.TokenExpiry != nil && account.TokenExpiry.Before(time.Now()) {
+		if err := t.RefreshToken(ctx, account); err != nil {
+			return nil, err
+		}
+	}
+
+	// Images fit in a single simple upload
+	mediaID, err := t.uploadImage(ctx, account, req.VideoPath)
+	if err != nil {
+		return nil, fmt.Errorf("image upload failed: %w", err)
+	}
+	if err := t.setAltText(ctx, account, mediaID, req.AltText); err != nil {
+		return nil, err
+	}
+
+	return t.createTweet(ctx, account, req.Description, mediaID)
+}
+
+// SupportsAltText reports that tweeted images and videos take alt text
+func (t *TwitterPlatform) SupportsAltText(mediaType social.MediaType) bool {
+	return mediaType != social.MediaTypeDocument
+}
+
+// setAltText attaches alt text to uploaded media before it is tweeted
+func (t *TwitterPlatform) setAltText(ctx context.Context, account *social.SocialAccount, mediaID, altText string) error {
+	if altText == "" {
+		return nil
+	}
+
+	jsonData, _ := json.Marshal(map[string]interface{}{
+		"media_id": mediaID,
+		"alt_text": map[string]string{"text": altText},
+	})
+	headers := map[string]string{
+		"Authorization": "Bearer " + account.AccessToken,
+		"Content-Type":  "application/json",
+	}
+
+	if _, err := t.makeRequest(ctx, "POST", TwitterMediaMetadataURL, jsonData, headers); err != nil {
+		return fmt.Errorf("alt text failed: %w", err)
+	}
+	return nil
+}
+
+// createTweet posts a tweet with a single attached media ID
+func (t *TwitterPlatform) createTweet(ctx context.Context, account *social.SocialAccount, text, mediaID string) (*social.UploadResponse, error) {
 	tweetURL := TwitterAPIURL + "/tweets"
 	tweetData := map[string]interface{}{
-		"text": req.Description,
+		"text": text,
 		"media": map[string]interface{}{
 			"media_ids": []string{mediaID},
 		},
@@ -212,6 +308,39 @@ func (t *TwitterPlatform) UploadVideo(ctx context.Context, account *social.Socia
 	}, nil
 }
 
+// PostComment replies to a published tweet
+func (t *TwitterPlatform) PostComment(ctx context.Context, account *social.SocialAccount, postID, text string) (string, error) {
+	tweetData := map[string]interface{}{
+		"text": text,
+		"reply": map[string]string{
+			"in_reply_to_tweet_id": postID,
+		},
+	}
+
+	jsonData, _ := json.Marshal(tweetData)
+	headers := map[string]string{
+		"Authorization": "Bearer " + account.AccessToken,
+		"Content-Type":  "application/json",
+	}
+
+	resp, err := t.makeRequest(ctx, "POST", TwitterAPIURL+"/tweets", jsonData, headers)
+	if err != nil {
+		return "", fmt.Errorf("reply creation failed: %w", err)
+	}
+
+	var tweetResp struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(resp, &tweetResp); err != nil {
+		return "", fmt.Errorf("failed to parse reply response: %w", err)
+	}
+
+	return tweetResp.Data.ID, nil
+}
+
 // GetAnalytics retrieves analytics for a tweet
 func (t *TwitterPlatform) GetAnalytics(ctx context.Context, account *social.SocialAccount, postID string) (*social.AnalyticsData, error) {
 	// Twitter API v2 requires Elevated access for analytics
@@ -257,18 +386,12 @@ func (t *TwitterPlatform) GetTrends(ctx context.Context, account *social.SocialA
 // Helper methods
 
 func (t *TwitterPlatform) uploadVideoChunked(ctx context.Context, account *social.SocialAccount, videoPath string) (string, error) {
-	file, err := os.Open(videoPath)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	fileInfo, err := file.Stat()
+	buf, err := t.media.readMedia(ctx, account.UserID, videoPath)
 	if err != nil {
 		return "", err
 	}
 
-	fileSize := fileInfo.Size()
+	fileSize := len(buf)
 
 	// Step 1: INIT
 	initParams := url.Values{
@@ -295,9 +418,6 @@ func (t *TwitterPlatform) uploadVideoChunked(ctx context.Context, account *socia
 
 	// Step 2: APPEND (simplified - would need chunking for large files)
 	// For production, split into 5MB chunks
-	buf := make([]byte, fileSize)
-	file.Read(buf)
-
 	appendParams := url.Values{
 		"command":       {"APPEND"},
 		"media_id":      {initResult.MediaID},
@@ -325,6 +445,37 @@ func (t *TwitterPlatform) uploadVideoChunked(ctx context.Context, account *socia
 	return initResult.MediaID, nil
 }
 
+func (t *TwitterPlatform) uploadImage(ctx context.Context, account *social.SocialAccount, imagePath string) (string, error) {
+	data, err := t.media.readMedia(ctx, account.UserID, imagePath)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"media_category": {"tweet_image"},
+		"media_data":     {base64.StdEncoding.EncodeToString(data)},
+	}
+
+	headers := map[string]string{
+		"Authorization": "Bearer " + account.AccessToken,
+		"Content-Type":  "application/x-www-form-urlencoded",
+	}
+
+	resp, err := t.makeRequest(ctx, "POST", TwitterUploadURL, []byte(params.Encode()), headers)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		MediaID string `json:"media_id_string"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return "", err
+	}
+
+	return result.MediaID, nil
+}
+
 func (t *TwitterPlatform) getUserInfo(ctx context.Context, accessToken string) (map[string]interface{}, error) {
 	userURL := TwitterAPIURL + "/users/me?user.fields=public_metrics,verified"
 	headers := map[string]string{
@@ -373,7 +524,7 @@ func (t *TwitterPlatform) makeRequest(ctx context.Context, method, url string, b
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, platformAPIError(resp.StatusCode, respBody)
 	}
 
 	return respBody, nil
@@ -0,0 +1,339 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"renderowl-api/internal/domain/social"
+	"renderowl-api/internal/safehttp"
+)
+
+// Readiness check names
+const (
+	CheckToken    = "token"
+	CheckCaption  = "caption"
+	CheckMedia    = "media"
+	CheckPlatform = "platform"
+)
+
+// ReadinessCheck is the outcome of a single pre-publish check
+type ReadinessCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// PlatformReadiness reports whether an upload to one account would succeed
+type PlatformReadiness struct {
+	AccountID string                `json:"accountId"`
+	Platform  social.SocialPlatform `json:"platform"`
+	Ready     bool                  `json:"ready"`
+	Checks    []ReadinessCheck      `json:"checks"`
+}
+
+// ReadinessReport is the result of a dry run across all target platforms
+type ReadinessReport struct {
+	Ready     bool                 `json:"ready"`
+	Platforms []*PlatformReadiness `json:"platforms"`
+}
+
+// mediaSource describes how a platform receives the video file
+type mediaSource int
+
+const (
+	mediaAny    mediaSource = iota // sent by the app: an upload, or a URL it downloads
+	mediaRemote                    // pulled by the platform from a public URL
+)
+
+// platformLimits holds the publishing constraints enforced by each platform
+type platformLimits struct {
+	MaxTitle       int
+	MaxDescription int
+	MaxTags        int
+	MaxTagsLength  int
+	MaxFileSize    int64
+	Media          mediaSource
+}
+
+var uploadLimits = map[social.SocialPlatform]platformLimits{
+	social.PlatformYouTube: {
+		MaxTitle:       100,
+		MaxDescription: 5000,
+		MaxTagsLength:  500,
+		MaxFileSize:    256 * 1024 * 1024 * 1024, // 256GB
+	},
+	social.PlatformTikTok: {
+		MaxTitle:       2200,
+		MaxDescription: 2200,
+		MaxFileSize:    287 * 1024 * 1024, // 287MB
+		Media:          mediaRemote,
+	},
+	social.PlatformInstagram: {
+		MaxDescription: 2200,
+		MaxTags:        30,
+		MaxFileSize:    4 * 1024 * 1024 * 1024, // 4GB
+		Media:          mediaRemote,
+	},
+	social.PlatformTwitter: {
+		MaxDescription: 280,
+		MaxFileSize:    512 * 1024 * 1024, // 512MB
+	},
+	social.PlatformLinkedIn: {
+		MaxTitle:       200,
+		MaxDescription: 3000,
+		MaxFileSize:    5 * 1024 * 1024 * 1024, // 5GB
+	},
+	social.PlatformFacebook: {
+		MaxTitle:       255,
+		MaxDescription: 63206,
+		MaxFileSize:    10 * 1024 * 1024 * 1024, // 10GB
+		Media:          mediaRemote,
+	},
+}
+
+// ValidateUpload runs every pre-publish check for an upload without calling
+// the platform's publish endpoints. publishAt is when the upload will happen.
+func (s *Service) ValidateUpload(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest, publishAt time.Time) *PlatformReadiness {
+	readiness := &PlatformReadiness{
+		AccountID: account.ID,
+		Platform:  account.Platform,
+	}
+
+	limits, known := uploadLimits[account.Platform]
+	p, configured := s.registry.Get(account.Platform)
+	isVideo := req.MediaType == "" || req.MediaType == social.MediaTypeVideo
+
+	_, privacyErr := resolvePrivacy(account.Platform, s.privacyFor(account.Platform, req.Privacy))
+
+	platformCheck := ReadinessCheck{Name: CheckPlatform, Passed: true}
+	switch {
+	case !configured:
+		platformCheck = ReadinessCheck{Name: CheckPlatform, Message: fmt.Sprintf("platform %s not configured", account.Platform)}
+	case !SupportsMediaType(p, req.MediaType):
+		platformCheck = ReadinessCheck{Name: CheckPlatform, Message: fmt.Sprintf("%s does not support %s posts", account.Platform, req.MediaType)}
+	case privacyErr != nil:
+		platformCheck = ReadinessCheck{Name: CheckPlatform, Message: privacyErr.Error()}
+	}
+
+	readiness.Checks = append(readiness.Checks, validateToken(account, publishAt))
+	if known {
+		readiness.Checks = append(readiness.Checks, validateCaption(req, account.Platform, limits))
+	}
+
+	mediaCheck, size := s.validateMedia(ctx, account.UserID, req.VideoPath, req.MediaType, limits.Media)
+	readiness.Checks = append(readiness.Checks, mediaCheck)
+
+	if platformCheck.Passed && isVideo && known && limits.MaxFileSize > 0 && size > limits.MaxFileSize {
+		platformCheck = ReadinessCheck{
+			Name:    CheckPlatform,
+			Message: fmt.Sprintf("video is %d bytes, %s allows at most %d", size, account.Platform, limits.MaxFileSize),
+		}
+	}
+	readiness.Checks = append(readiness.Checks, platformCheck)
+
+	readiness.Ready = true
+	for _, check := range readiness.Checks {
+		if !check.Passed {
+			readiness.Ready = false
+			break
+		}
+	}
+
+	return readiness
+}
+
+// ValidateScheduledPost dry-runs a scheduled post against every target account
+func (s *Service) ValidateScheduledPost(ctx context.Context, post *social.ScheduledPost) (*ReadinessReport, error) {
+	videoPath, _ := post.Metadata["videoPath"].(string)
+	mediaType, _ := post.Metadata["mediaType"].(string)
+	categoryID, _ := post.Metadata["categoryId"].(string)
+
+	report := &ReadinessReport{Ready: true}
+	for _, platformPost := range post.Platforms {
+		account, err := s.GetAccount(ctx, platformPost.AccountID, post.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Mirror the request the publisher will send
+		req := &social.UploadRequest{
+			MediaType:   social.MediaType(mediaType),
+			VideoPath:   videoPath,
+			Title:       platformPost.CustomTitle,
+			Description: platformPost.CustomDesc,
+			Tags:        platformPost.Tags,
+			Privacy:     platformPost.Privacy,
+			CategoryID:  categoryID,
+		}
+
+		readiness := s.ValidateUpload(ctx, account, req, post.ScheduledAt)
+		if !readiness.Ready {
+			report.Ready = false
+		}
+		report.Platforms = append(report.Platforms, readiness)
+	}
+
+	return report, nil
+}
+
+// validateToken checks the account will still hold a usable token at publish time
+func validateToken(account *social.SocialAccount, publishAt time.Time) ReadinessCheck {
+	check := ReadinessCheck{Name: CheckToken}
+
+	if account.Status != social.StatusConnected && account.Status != social.StatusExpired {
+		check.Message = fmt.Sprintf("account is %s", account.Status)
+		return check
+	}
+
+	if publishAt.IsZero() || publishAt.Before(time.Now()) {
+		publishAt = time.Now()
+	}
+
+	expired := account.Status == social.StatusExpired ||
+		(account.TokenExpiry != nil && account.TokenExpiry.Before(publishAt))
+	if expired {
+		if account.RefreshToken == "" {
+			check.Message = "token expires before publish time, reconnect required"
+			return check
+		}
+		check.Message = "token will be refreshed before publishing"
+	}
+
+	check.Passed = true
+	return check
+}
+
+// validateCaption checks title, description and tags against platform limits,
+// counting characters the way the platform does
+func validateCaption(req *social.UploadRequest, platform social.SocialPlatform, limits platformLimits) ReadinessCheck {
+	check := ReadinessCheck{Name: CheckCaption}
+
+	var problems []string
+	if n := captionLength(platform, req.Title); limits.MaxTitle > 0 && n > limits.MaxTitle {
+		problems = append(problems, fmt.Sprintf("title is %d characters (max %d)", n, limits.MaxTitle))
+	}
+	if n := captionLength(platform, req.Description); limits.MaxDescription > 0 && n > limits.MaxDescription {
+		problems = append(problems, fmt.Sprintf("description is %d characters (max %d)", n, limits.MaxDescription))
+	}
+	if limits.MaxTags > 0 && len(req.Tags) > limits.MaxTags {
+		problems = append(problems, fmt.Sprintf("%d tags (max %d)", len(req.Tags), limits.MaxTags))
+	}
+	if platform == social.PlatformYouTube {
+		if err := validateYouTubeCategory(req.CategoryID); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if limits.MaxTagsLength > 0 {
+		total := 0
+		for _, tag := range req.Tags {
+			total += captionLength(platform, tag)
+		}
+		if total > limits.MaxTagsLength {
+			problems = append(problems, fmt.Sprintf("tags total %d characters (max %d)", total, limits.MaxTagsLength))
+		}
+	}
+
+	if len(problems) > 0 {
+		check.Message = strings.Join(problems, "; ")
+		return check
+	}
+
+	check.Passed = true
+	return check
+}
+
+// MediaFiles resolves media users uploaded to the app's own storage, named by
+// storage key or URL
+type MediaFiles interface {
+	UploadSize(userID, ref string) (int64, bool)
+	OpenUpload(userID, ref string) (io.ReadCloser, bool)
+	UploadURL(userID, ref string) (string, bool)
+}
+
+// mediaCheckClient checks remote media. It only connects to public
+// addresses, so a media URL can't be used to probe the internal network.
+var mediaCheckClient = safehttp.NewClient(10 * time.Second)
+
+// SetMediaFiles lets pre-publish checks and platform uploads find media
+// users uploaded to storage
+func (s *Service) SetMediaFiles(files MediaFiles) {
+	s.mediaFiles = files
+	if s.media != nil {
+		s.media.files = files
+	}
+}
+
+// validateMedia checks the media file can be reached the way the platform
+// expects and returns its size in bytes when known. The user's own uploads,
+// by key or URL, always can: they're sent as files or handed over by URL.
+// Other paths must be URLs; files elsewhere on the server are never looked
+// at.
+func (s *Service) validateMedia(ctx context.Context, userID, videoPath string, mediaType social.MediaType, source mediaSource) (ReadinessCheck, int64) {
+	check := ReadinessCheck{Name: CheckMedia}
+
+	noun, contentPrefix := "video", "video/"
+	switch mediaType {
+	case social.MediaTypeImage:
+		noun, contentPrefix = "image", "image/"
+	case social.MediaTypeDocument:
+		noun, contentPrefix = "document", "application/pdf"
+	}
+
+	if videoPath == "" {
+		check.Message = fmt.Sprintf("no %s attached", noun)
+		return check, 0
+	}
+
+	if s.mediaFiles != nil {
+		if size, ok := s.mediaFiles.UploadSize(userID, videoPath); ok {
+			check.Passed = true
+			return check, size
+		}
+	}
+	if !isRemoteMedia(videoPath) {
+		if source == mediaRemote {
+			check.Message = fmt.Sprintf("platform requires a publicly accessible %s URL or one of your uploads", noun)
+		} else {
+			check.Message = fmt.Sprintf("%s file not found in your uploads", noun)
+		}
+		return check, 0
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, videoPath, nil)
+	if err != nil {
+		check.Message = fmt.Sprintf("invalid %s URL: %v", noun, err)
+		return check, 0
+	}
+
+	resp, err := mediaCheckClient.Do(req)
+	if errors.Is(err, safehttp.ErrBlockedAddress) {
+		check.Message = fmt.Sprintf("%s URL must be on a public host", noun)
+		return check, 0
+	}
+	if err != nil {
+		check.Message = fmt.Sprintf("%s URL not reachable", noun)
+		return check, 0
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		check.Message = fmt.Sprintf("%s URL returned status %d", noun, resp.StatusCode)
+		return check, 0
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "application/octet-stream") && !strings.HasPrefix(contentType, contentPrefix) {
+		check.Message = fmt.Sprintf("%s URL serves %s, not a %s", noun, contentType, noun)
+		return check, 0
+	}
+
+	size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	check.Passed = true
+	return check, size
+}
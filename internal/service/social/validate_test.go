@@ -0,0 +1,133 @@
+package social
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"renderowl-api/internal/domain/social"
+)
+
+// fakeMediaFiles holds each user's uploads by key, with their sizes
+type fakeMediaFiles map[string]map[string]int64
+
+func (f fakeMediaFiles) UploadSize(userID, ref string) (int64, bool) {
+	size, ok := f[userID][ref]
+	return size, ok
+}
+
+func (f fakeMediaFiles) UploadURL(userID, ref string) (string, bool) {
+	if _, ok := f[userID][ref]; !ok {
+		return "", false
+	}
+	return "https://files.example.com/" + ref, true
+}
+
+func (f fakeMediaFiles) OpenUpload(userID, ref string) (io.ReadCloser, bool) {
+	size, ok := f[userID][ref]
+	if !ok {
+		return nil, false
+	}
+	return io.NopCloser(strings.NewReader(strings.Repeat("x", int(size)))), true
+}
+
+func TestValidateMediaOnlyReadsOwnUploads(t *testing.T) {
+	s := &Service{mediaFiles: fakeMediaFiles{
+		"user-a": {"uploads/user-a/clip.mp4": 1024},
+	}}
+	ctx := context.Background()
+
+	check, size := s.validateMedia(ctx, "user-a", "uploads/user-a/clip.mp4", social.MediaTypeVideo, mediaAny)
+	if !check.Passed || size != 1024 {
+		t.Errorf("own upload: passed %v with size %d, want passed with size 1024", check.Passed, size)
+	}
+
+	for _, path := range []string{"uploads/user-a/clip.mp4", "/etc/passwd", "../../etc/passwd"} {
+		check, _ := s.validateMedia(ctx, "user-b", path, social.MediaTypeVideo, mediaAny)
+		if check.Passed {
+			t.Errorf("user-b passed the media check with %s", path)
+		}
+		if check.Message != "video file not found in your uploads" {
+			t.Errorf("message for %s = %q, want the generic not found", path, check.Message)
+		}
+	}
+}
+
+func TestValidateMediaRefusesInternalURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("media check reached the internal server")
+	}))
+	defer server.Close()
+
+	s := &Service{}
+	for _, url := range []string{server.URL + "/video.mp4", "http://169.254.169.254/latest/meta-data/"} {
+		check, _ := s.validateMedia(context.Background(), "user-a", url, social.MediaTypeVideo, mediaAny)
+		if check.Passed || !strings.Contains(check.Message, "public host") {
+			t.Errorf("media check of %s: passed %v, message %q", url, check.Passed, check.Message)
+		}
+	}
+}
+
+func TestValidateMediaAcceptsUploadsOnEveryPlatform(t *testing.T) {
+	s := &Service{mediaFiles: fakeMediaFiles{"user-a": {
+		"uploads/user-a/clip.mp4":                           1024,
+		"https://files.example.com/uploads/user-a/clip.mp4": 1024,
+	}}}
+	for platform, limits := range uploadLimits {
+		for _, ref := range []string{"uploads/user-a/clip.mp4", "https://files.example.com/uploads/user-a/clip.mp4"} {
+			check, _ := s.validateMedia(context.Background(), "user-a", ref, social.MediaTypeVideo, limits.Media)
+			if !check.Passed {
+				t.Errorf("%s upload %s: %s", platform, ref, check.Message)
+			}
+		}
+	}
+}
+
+// uploadCapture is a platform recording the requests it's sent
+type uploadCapture struct {
+	fakePlatform
+	got *social.UploadRequest
+}
+
+func (p *uploadCapture) UploadVideo(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	p.got = req
+	return &social.UploadResponse{PlatformPostID: "post-1"}, nil
+}
+
+func TestUploadHandsRemotePlatformsTheUploadURL(t *testing.T) {
+	tiktok := &uploadCapture{fakePlatform: fakePlatform{name: social.PlatformTikTok}}
+	youtube := &uploadCapture{fakePlatform: fakePlatform{name: social.PlatformYouTube}}
+	registry := NewPlatformRegistry()
+	registry.Register(tiktok)
+	registry.Register(youtube)
+	s := NewService(registry, nil, nil, nil)
+	s.SetMediaFiles(fakeMediaFiles{"user-a": {"uploads/user-a/clip.mp4": 1024}})
+
+	req := &social.UploadRequest{VideoPath: "uploads/user-a/clip.mp4"}
+	for _, platform := range []*uploadCapture{tiktok, youtube} {
+		account := &social.SocialAccount{UserID: "user-a", Platform: platform.name}
+		if _, err := s.upload(context.Background(), account, req); err != nil {
+			t.Fatalf("upload to %s: %v", platform.name, err)
+		}
+	}
+	if tiktok.got.VideoPath != "https://files.example.com/uploads/user-a/clip.mp4" {
+		t.Errorf("TikTok was sent %q, want the upload's URL", tiktok.got.VideoPath)
+	}
+	if youtube.got.VideoPath != "uploads/user-a/clip.mp4" {
+		t.Errorf("YouTube was sent %q, want the upload's key to read it by", youtube.got.VideoPath)
+	}
+	if req.VideoPath != "uploads/user-a/clip.mp4" {
+		t.Errorf("the caller's request was changed to %q", req.VideoPath)
+	}
+
+	other := &social.SocialAccount{UserID: "user-b", Platform: social.PlatformTikTok}
+	if _, err := s.upload(context.Background(), other, req); err != nil {
+		t.Fatalf("upload to user-b's TikTok: %v", err)
+	}
+	if tiktok.got.VideoPath != "uploads/user-a/clip.mp4" {
+		t.Errorf("user-b's post was handed user-a's upload URL %q", tiktok.got.VideoPath)
+	}
+}
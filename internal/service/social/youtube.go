@@ -3,7 +3,8 @@ package social
 import (
 	"context"
 	"fmt"
-	"os"
+	"net/http"
+	"net/url"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -20,6 +21,11 @@ type YouTubePlatform struct {
 	clientSecret string
 	redirectURL  string
 	config       *oauth2.Config
+
+	defaultCategory string
+
+	// media opens the videos and thumbnails to upload
+	media *mediaReader
 }
 
 // NewYouTubePlatform creates a new YouTube platform instance
@@ -125,6 +131,15 @@ func (y *YouTubePlatform) RefreshToken(ctx context.Context, account *social.Soci
 
 // UploadVideo uploads a video to YouTube
 func (y *YouTubePlatform) UploadVideo(ctx context.Context, account *social.SocialAccount, req *social.UploadRequest) (*social.UploadResponse, error) {
+	privacy, err := resolvePrivacy(social.PlatformYouTube, req.Privacy)
+	if err != nil {
+		return nil, err
+	}
+	categoryID, err := y.categoryFor(req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Refresh token if needed
 	if account.TokenExpiry != nil && account.TokenExpiry.Before(time.Now()) {
 		if err := y.RefreshToken(ctx, account); err != nil {
@@ -143,8 +158,8 @@ func (y *YouTubePlatform) UploadVideo(ctx context.Context, account *social.Socia
 		return nil, fmt.Errorf("failed to create YouTube service: %w", err)
 	}
 
-	// Open video file
-	file, err := os.Open(req.VideoPath)
+	// Open the video, downloading it when it is a URL
+	file, err := y.media.openMedia(ctx, account.UserID, req.VideoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open video file: %w", err)
 	}
@@ -154,6 +169,7 @@ func (y *YouTubePlatform) UploadVideo(ctx context.Context, account *social.Socia
 	snippet := &youtube.VideoSnippet{
 		Title:       req.Title,
 		Description: req.Description,
+		CategoryId:  categoryID,
 	}
 
 	if len(req.Tags) > 0 {
@@ -161,17 +177,7 @@ func (y *YouTubePlatform) UploadVideo(ctx context.Context, account *social.Socia
 	}
 
 	// Set privacy status
-	status := &youtube.VideoStatus{}
-	switch req.Privacy {
-	case "public":
-		status.PrivacyStatus = "public"
-	case "unlisted":
-		status.PrivacyStatus = "unlisted"
-	case "private":
-		status.PrivacyStatus = "private"
-	default:
-		status.PrivacyStatus = "private"
-	}
+	status := &youtube.VideoStatus{PrivacyStatus: privacy}
 
 	// Create video resource
 	video := &youtube.Video{
@@ -191,6 +197,106 @@ func (y *YouTubePlatform) UploadVideo(ctx context.Context, account *social.Socia
 		PlatformPostID: response.Id,
 		PostURL:        fmt.Sprintf("https://youtube.com/watch?v=%s", response.Id),
 		Status:         "published",
+		Category: &social.CategoryResult{
+			ID:   categoryID,
+			Name: social.YouTubeCategories[categoryID],
+		},
+	}, nil
+}
+
+// PostComment posts a top-level comment on a YouTube video
+func (y *YouTubePlatform) PostComment(ctx context.Context, account *social.SocialAccount, postID, text string) (string, error) {
+	token := &oauth2.Token{
+		AccessToken:  account.AccessToken,
+		RefreshToken: account.RefreshToken,
+	}
+
+	client := y.config.Client(ctx, token)
+	service, err := youtube.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return "", fmt.Errorf("failed to create YouTube service: %w", err)
+	}
+
+	thread := &youtube.CommentThread{
+		Snippet: &youtube.CommentThreadSnippet{
+			ChannelId: account.AccountID,
+			VideoId:   postID,
+			TopLevelComment: &youtube.Comment{
+				Snippet: &youtube.CommentSnippet{
+					TextOriginal: text,
+				},
+			},
+		},
+	}
+
+	response, err := service.CommentThreads.Insert([]string{"snippet"}, thread).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to post comment: %w", err)
+	}
+
+	return response.Id, nil
+}
+
+// SetThumbnail uploads a custom thumbnail for a YouTube video. The thumbnail
+// may be one of the user's uploads or a URL, which is downloaded first.
+func (y *YouTubePlatform) SetThumbnail(ctx context.Context, account *social.SocialAccount, postID, thumbnail string) error {
+	token := &oauth2.Token{
+		AccessToken:  account.AccessToken,
+		RefreshToken: account.RefreshToken,
+	}
+
+	client := y.config.Client(ctx, token)
+	service, err := youtube.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return fmt.Errorf("failed to create YouTube service: %w", err)
+	}
+
+	image, err := y.media.openMedia(ctx, account.UserID, thumbnail)
+	if err != nil {
+		return fmt.Errorf("failed to open thumbnail: %w", err)
+	}
+	defer image.Close()
+
+	if _, err := service.Thumbnails.Set(postID).Media(image).Do(); err != nil {
+		return fmt.Errorf("failed to set thumbnail: %w", err)
+	}
+
+	return nil
+}
+
+// GetAccountMetrics reads the channel's subscriber, view and video counts
+// with the account's own token
+func (y *YouTubePlatform) GetAccountMetrics(ctx context.Context, account *social.SocialAccount) (*social.AccountMetricsSnapshot, error) {
+	if account.TokenExpiry != nil && account.TokenExpiry.Before(time.Now()) {
+		if err := y.RefreshToken(ctx, account); err != nil {
+			return nil, err
+		}
+	}
+
+	token := &oauth2.Token{
+		AccessToken:  account.AccessToken,
+		RefreshToken: account.RefreshToken,
+	}
+
+	client := y.config.Client(ctx, token)
+	service, err := youtube.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create YouTube service: %w", err)
+	}
+
+	response, err := service.Channels.List([]string{"statistics"}).Mine(true).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel statistics: %w", err)
+	}
+	if len(response.Items) == 0 || response.Items[0].Statistics == nil {
+		return nil, fmt.Errorf("no YouTube channel found")
+	}
+
+	stats := response.Items[0].Statistics
+	return &social.AccountMetricsSnapshot{
+		Followers:  int64(stats.SubscriberCount),
+		TotalViews: int64(stats.ViewCount),
+		VideoCount: int64(stats.VideoCount),
 	}, nil
 }
 
@@ -264,6 +370,32 @@ func (y *YouTubePlatform) DeletePost(ctx context.Context, account *social.Social
 	return call.Do()
 }
 
+// RevokeToken revokes the account's Google OAuth grant
+func (y *YouTubePlatform) RevokeToken(ctx context.Context, account *social.SocialAccount) error {
+	token := account.RefreshToken
+	if token == "" {
+		token = account.AccessToken
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://oauth2.googleapis.com/revoke?token="+url.QueryEscape(token), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("token revoke failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token revoke failed: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 // GetTrends retrieves trending videos
 func (y *YouTubePlatform) GetTrends(ctx context.Context, account *social.SocialAccount, region string) ([]*social.PlatformTrend, error) {
 	token := &oauth2.Token{
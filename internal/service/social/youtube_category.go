@@ -0,0 +1,52 @@
+package social
+
+import (
+	"fmt"
+	"net/http"
+
+	"renderowl-api/internal/domain"
+	"renderowl-api/internal/domain/social"
+)
+
+// ErrUnknownCategory means the requested YouTube category doesn't exist
+var ErrUnknownCategory = domain.NewAppError(domain.CodeValidation, http.StatusBadRequest, "unknown YouTube category")
+
+// defaultYouTubeCategory is the category YouTube itself files uploads under
+// when none is given: People & Blogs
+const defaultYouTubeCategory = "22"
+
+// validateYouTubeCategory checks a category ID against the categories
+// YouTube accepts; an empty ID is valid and means the default
+func validateYouTubeCategory(categoryID string) error {
+	if categoryID == "" {
+		return nil
+	}
+	if _, ok := social.YouTubeCategories[categoryID]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownCategory, categoryID)
+	}
+	return nil
+}
+
+// SetDefaultCategory sets the category uploads without one are filed under
+func (y *YouTubePlatform) SetDefaultCategory(categoryID string) error {
+	if err := validateYouTubeCategory(categoryID); err != nil {
+		return err
+	}
+	y.defaultCategory = categoryID
+	return nil
+}
+
+// categoryFor returns the category an upload is filed under: the requested
+// one, or the configured default
+func (y *YouTubePlatform) categoryFor(categoryID string) (string, error) {
+	if err := validateYouTubeCategory(categoryID); err != nil {
+		return "", err
+	}
+	if categoryID != "" {
+		return categoryID, nil
+	}
+	if y.defaultCategory != "" {
+		return y.defaultCategory, nil
+	}
+	return defaultYouTubeCategory, nil
+}
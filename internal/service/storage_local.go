@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrFileNotFound is returned when a stored file doesn't exist
+var ErrFileNotFound = errors.New("file not found")
+
+// StoredFile is a file kept in storage
+type StoredFile struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// LocalStorage stores files on local disk and serves them from baseURL,
+// which should point at the /files route
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage creates a local storage provider rooted at dir
+func NewLocalStorage(dir, baseURL string) *LocalStorage {
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Upload writes data under key and returns its URL
+func (s *LocalStorage) Upload(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	return s.GetURL(key), nil
+}
+
+// GetURL returns the URL a stored file is served from
+func (s *LocalStorage) GetURL(key string) string {
+	return s.baseURL + "/" + strings.TrimLeft(key, "/")
+}
+
+// KeyForURL returns the key of a file served from this storage, or false
+// when the URL points elsewhere
+func (s *LocalStorage) KeyForURL(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, s.baseURL+"/")
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+// Open opens a stored file for reading
+func (s *LocalStorage) Open(key string) (*os.File, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+	return file, err
+}
+
+// Delete removes a stored file
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrFileNotFound
+	}
+	return err
+}
+
+// List returns the files stored under a key prefix, e.g. "thumbnails/"
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]StoredFile, error) {
+	root, err := s.path(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []StoredFile
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, StoredFile{
+			Key:     filepath.ToSlash(rel),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	return files, err
+}
+
+// path maps a key to a file under the storage directory, refusing keys that
+// would escape it
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", ErrFileNotFound
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
@@ -2,12 +2,17 @@ package service
 
 import (
 	"fmt"
+	"log"
 	"time"
 
 	"renderowl-api/internal/domain"
 	"renderowl-api/internal/repository"
 )
 
+// ErrTemplateNotFound is returned for a template that doesn't exist or has
+// been retired
+var ErrTemplateNotFound = domain.ErrTemplateNotFound
+
 // TemplateService handles template business logic
 type TemplateService struct {
 	templateRepo *repository.TemplateRepository
@@ -32,8 +37,8 @@ func NewTemplateService(
 }
 
 // ListTemplates retrieves all templates with filtering
-func (s *TemplateService) ListTemplates(filter domain.TemplateFilter) ([]*domain.Template, error) {
-	return s.templateRepo.List(filter)
+func (s *TemplateService) ListTemplates(filter domain.TemplateFilter) ([]*domain.Template, int64, error) {
+	return s.templateRepo.ListWithCount(filter)
 }
 
 // GetTemplate retrieves a single template by ID
@@ -82,6 +87,10 @@ func (s *TemplateService) UseTemplate(templateID, userID string, req *domain.Use
 		return nil, fmt.Errorf("failed to create timeline content: %w", err)
 	}
 
+	if err := s.templateRepo.IncrementUsage(templateID); err != nil {
+		log.Printf("Failed to count use of template %s: %v", templateID, err)
+	}
+
 	return &domain.UseTemplateResponse{
 		TimelineID: timeline.ID,
 		Message:    "Timeline created successfully from template",
@@ -90,6 +99,20 @@ func (s *TemplateService) UseTemplate(templateID, userID string, req *domain.Use
 	}, nil
 }
 
+// AddFavorite pins an active template for the user
+func (s *TemplateService) AddFavorite(userID, templateID string) error {
+	template, err := s.templateRepo.GetByID(templateID)
+	if err != nil || !template.IsActive {
+		return ErrTemplateNotFound
+	}
+	return s.templateRepo.AddFavorite(userID, templateID)
+}
+
+// RemoveFavorite unpins a template for the user
+func (s *TemplateService) RemoveFavorite(userID, templateID string) error {
+	return s.templateRepo.RemoveFavorite(userID, templateID)
+}
+
 // createTimelineFromTemplate creates tracks and clips based on template scenes
 func (s *TemplateService) createTimelineFromTemplate(timelineID string, template *domain.Template, customData map[string]interface{}) error {
 	// Create a video track
@@ -156,6 +179,7 @@ func (s *TemplateService) templateClipToDomainClip(
 		Scale:       tc.Scale,
 		Rotation:    tc.Rotation,
 		Opacity:     tc.Opacity,
+		Volume:      1,
 	}
 
 	// Determine track based on clip type
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"renderowl-api/internal/domain"
+)
+
+// Template preview montage layout
+const (
+	previewTileWidth = 384
+	previewColumns   = 3
+	previewGap       = 8
+)
+
+var (
+	previewBackground = color.RGBA{0x0f, 0x17, 0x2a, 0xff}
+	previewText       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	// Placeholder media is drawn as translucent boxes, tinted by clip type
+	previewClipColors = map[string]color.RGBA{
+		"video": {0xff, 0xff, 0xff, 0x40},
+		"image": {0xff, 0xff, 0xff, 0x66},
+		"text":  {0x00, 0x00, 0x00, 0x73},
+	}
+)
+
+// tailwindColors maps the Tailwind color names used in template gradients to
+// their 500 shade, which is close enough for a preview
+var tailwindColors = map[string]color.RGBA{
+	"slate":   {0x64, 0x74, 0x8b, 0xff},
+	"gray":    {0x6b, 0x72, 0x80, 0xff},
+	"red":     {0xef, 0x44, 0x44, 0xff},
+	"orange":  {0xf9, 0x73, 0x16, 0xff},
+	"amber":   {0xf5, 0x9e, 0x0b, 0xff},
+	"yellow":  {0xea, 0xb3, 0x08, 0xff},
+	"green":   {0x22, 0xc5, 0x5e, 0xff},
+	"teal":    {0x14, 0xb8, 0xa6, 0xff},
+	"cyan":    {0x06, 0xb6, 0xd4, 0xff},
+	"blue":    {0x3b, 0x82, 0xf6, 0xff},
+	"indigo":  {0x63, 0x66, 0xf1, 0xff},
+	"violet":  {0x8b, 0x5c, 0xf6, 0xff},
+	"purple":  {0xa8, 0x55, 0xf7, 0xff},
+	"fuchsia": {0xd9, 0x46, 0xef, 0xff},
+	"pink":    {0xec, 0x48, 0x99, 0xff},
+}
+
+// GetTemplatePreview returns a PNG montage of the template's scenes with
+// placeholder media. It is rendered on first request and cached on the
+// template until the template's version changes.
+func (s *TemplateService) GetTemplatePreview(id string) ([]byte, error) {
+	template, err := s.templateRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, version, err := s.templateRepo.GetPreview(id)
+	if err == nil && len(preview) > 0 && version == template.Version {
+		return preview, nil
+	}
+
+	preview, err = renderTemplatePreview(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render preview: %w", err)
+	}
+	// The preview can always be rendered again, so only log
+	if err := s.templateRepo.SavePreview(id, preview, template.Version); err != nil {
+		log.Printf("Failed to cache preview for template %s: %v", id, err)
+	}
+
+	return preview, nil
+}
+
+// renderTemplatePreview draws one tile per scene, laid out in a grid. Each
+// tile shows the template gradient, its clips as boxes at their positions
+// and the scene name.
+func renderTemplatePreview(template *domain.Template) ([]byte, error) {
+	scenes := template.Scenes
+	if len(scenes) == 0 {
+		scenes = []domain.TemplateScene{{Name: template.Name}}
+	}
+
+	width, height := template.Width, template.Height
+	if width <= 0 || height <= 0 {
+		width, height = 1920, 1080
+	}
+	tileWidth := previewTileWidth
+	tileHeight := tileWidth * height / width
+
+	columns := min(len(scenes), previewColumns)
+	rows := (len(scenes) + columns - 1) / columns
+	img := image.NewRGBA(image.Rect(0, 0,
+		columns*tileWidth+(columns+1)*previewGap,
+		rows*tileHeight+(rows+1)*previewGap,
+	))
+	draw.Draw(img, img.Bounds(), &image.Uniform{previewBackground}, image.Point{}, draw.Src)
+
+	from, to := gradientColors(template.Gradient)
+	scale := float64(tileWidth) / float64(width)
+	for i, scene := range scenes {
+		x := previewGap + (i%columns)*(tileWidth+previewGap)
+		y := previewGap + (i/columns)*(tileHeight+previewGap)
+		tile := image.Rect(x, y, x+tileWidth, y+tileHeight)
+
+		drawGradient(img, tile, from, to)
+		for _, clip := range scene.Clips {
+			drawClip(img, tile, clip, scale)
+		}
+		drawLabel(img, tile.Min.X+8, tile.Max.Y-8, fmt.Sprintf("%d. %s", i+1, scene.Name))
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gradientColors parses a Tailwind gradient such as "from-red-500 to-pink-600"
+func gradientColors(gradient string) (color.RGBA, color.RGBA) {
+	from, to := tailwindColors["slate"], tailwindColors["gray"]
+	for _, part := range strings.Fields(gradient) {
+		fields := strings.Split(part, "-")
+		if len(fields) < 2 {
+			continue
+		}
+		c, ok := tailwindColors[fields[1]]
+		if !ok {
+			continue
+		}
+		switch fields[0] {
+		case "from":
+			from = c
+		case "to":
+			to = c
+		}
+	}
+	return from, to
+}
+
+// drawGradient fills r with a vertical gradient
+func drawGradient(img *image.RGBA, r image.Rectangle, from, to color.RGBA) {
+	span := max(r.Dy()-1, 1)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		t := float64(y-r.Min.Y) / float64(span)
+		c := color.RGBA{
+			R: uint8(float64(from.R) + t*(float64(to.R)-float64(from.R))),
+			G: uint8(float64(from.G) + t*(float64(to.G)-float64(from.G))),
+			B: uint8(float64(from.B) + t*(float64(to.B)-float64(from.B))),
+			A: 0xff,
+		}
+		draw.Draw(img, image.Rect(r.Min.X, y, r.Max.X, y+1), &image.Uniform{c}, image.Point{}, draw.Src)
+	}
+}
+
+// drawClip draws a clip as a box offset from the tile center by its
+// position. Media is sized by its scale, with a full-scale clip covering the
+// tile; text clips are sized to, and show, their placeholder text.
+func drawClip(img *image.RGBA, tile image.Rectangle, clip domain.TemplateClip, scale float64) {
+	c, ok := previewClipColors[clip.Type]
+	if !ok {
+		return
+	}
+
+	text := clip.TextContent
+	if text == "" {
+		text = clip.Name
+	}
+
+	var w, h int
+	if clip.Type == "text" {
+		w, h = utf8.RuneCountInString(text)*7+12, 21
+	} else {
+		clipScale := clip.Scale
+		if clipScale <= 0 {
+			clipScale = 1
+		}
+		w, h = int(float64(tile.Dx())*clipScale), int(float64(tile.Dy())*clipScale)
+		if clipScale < 1 {
+			w, h = w/2, h/2
+		}
+	}
+
+	centerX := tile.Min.X + tile.Dx()/2 + int(clip.PositionX*scale)
+	centerY := tile.Min.Y + tile.Dy()/2 + int(clip.PositionY*scale)
+	box := image.Rect(centerX-w/2, centerY-h/2, centerX+w/2, centerY+h/2).Intersect(tile)
+	draw.Draw(img, box, &image.Uniform{c}, image.Point{}, draw.Over)
+
+	if clip.Type == "text" && !box.Empty() {
+		drawLabel(img, box.Min.X+6, box.Max.Y-5, text)
+	}
+}
+
+// drawLabel draws text with its baseline at (x, y)
+func drawLabel(img *image.RGBA, x, y int, text string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{previewText},
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
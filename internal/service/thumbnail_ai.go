@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrThumbnailsUnavailable is returned when no AI image provider is set up
+// for thumbnail generation
+var ErrThumbnailsUnavailable = errors.New("AI thumbnail generation is not configured")
+
+// maxThumbnailImageBytes bounds the provider images downloaded for thumbnails
+const maxThumbnailImageBytes = 20 << 20
+
+// ThumbnailBackgroundGenerator generates the images AI thumbnails are
+// composited on
+type ThumbnailBackgroundGenerator interface {
+	GenerateThumbnailBackground(ctx context.Context, prompt string) (image.Image, error)
+}
+
+// thumbnailStyle is one way of framing a thumbnail's subject
+type thumbnailStyle struct {
+	Name   string
+	Prompt string // %s is the video's subject
+}
+
+// thumbnailStyles are tried in order. The faces and contrast they ask for are
+// what clickable thumbnails tend to share.
+var thumbnailStyles = []thumbnailStyle{
+	{"face_focus", "close-up of a person with a strong, surprised facial expression looking straight at the camera, about %s, face on one side of the frame, clean background on the other"},
+	{"bold_contrast", "single bold subject representing %s against a plain, saturated complementary-colour background, dramatic rim lighting"},
+	{"reaction", "person pointing at %s with an excited expression, bright studio lighting, vivid colours"},
+	{"minimal", "striking minimal composition about %s, one subject, lots of negative space, punchy colours"},
+}
+
+const thumbnailPromptSuffix = ", YouTube thumbnail, high contrast, sharp focus, vibrant saturated colours, no text"
+
+const thumbnailNegativePrompt = "text, letters, watermark, logo, blurry, low contrast, cluttered, dark"
+
+// SetThumbnailBackgrounds enables AI thumbnail generation
+func (s *VariationsService) SetThumbnailBackgrounds(backgrounds ThumbnailBackgroundGenerator) {
+	s.thumbnailBackgrounds = backgrounds
+}
+
+// GenerateAIThumbnails generates up to count thumbnails for a video: an AI
+// image per style from its title and keywords, with the title composited on
+// top. They are uploaded and returned ranked by predicted CTR, best first.
+func (s *VariationsService) GenerateAIThumbnails(ctx context.Context, sourceID, title string, keywords []string, count int) ([]ThumbnailVariation, error) {
+	if count <= 0 {
+		count = 3
+	}
+	count = min(count, len(thumbnailStyles))
+	return s.generateAIThumbnails(ctx, sourceID, title, keywords, thumbnailStyles[:count])
+}
+
+// generateAIThumbnails generates a thumbnail in each of the styles, which
+// may repeat to get several takes on one style
+func (s *VariationsService) generateAIThumbnails(ctx context.Context, sourceID, title string, keywords []string, styles []thumbnailStyle) ([]ThumbnailVariation, error) {
+	if s.thumbnailBackgrounds == nil {
+		return nil, ErrThumbnailsUnavailable
+	}
+
+	subject := title
+	if len(keywords) > 0 {
+		subject = strings.Join(keywords, ", ")
+	}
+	spec := PlatformSpecs["youtube"]
+
+	var variations []ThumbnailVariation
+	var lastErr error
+	for _, style := range styles {
+		prompt := fmt.Sprintf(style.Prompt, subject) + thumbnailPromptSuffix
+		background, err := s.thumbnailBackgrounds.GenerateThumbnailBackground(ctx, prompt)
+		if err != nil {
+			log.Printf("Thumbnail background %s failed: %v", style.Name, err)
+			lastErr = err
+			continue
+		}
+
+		variation := ThumbnailVariation{
+			ID:          uuid.New().String(),
+			SourceID:    sourceID,
+			Style:       style.Name,
+			Width:       spec.Width,
+			Height:      spec.Height,
+			TextOverlay: thumbnailText(title),
+			GeneratedAt: time.Now(),
+		}
+		data, err := s.generateThumbnailImage(&variation, background)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		variation.CTR = predictThumbnailCTR(background, variation.TextOverlay, style.Name)
+
+		key := fmt.Sprintf("thumbnails/%s/%s.jpg", sourceID, variation.ID)
+		url, err := s.storage.Upload(ctx, key, data, "image/jpeg")
+		if err != nil {
+			lastErr = fmt.Errorf("failed to upload thumbnail: %w", err)
+			continue
+		}
+		variation.URL = url
+		variations = append(variations, variation)
+	}
+	if len(variations) == 0 {
+		return nil, fmt.Errorf("failed to generate thumbnails: %w", lastErr)
+	}
+
+	sort.SliceStable(variations, func(i, j int) bool { return variations[i].CTR > variations[j].CTR })
+	for i := range variations {
+		variations[i].Variant = string(rune('A' + i))
+	}
+	return variations, nil
+}
+
+// thumbnailText shortens a title to the few words a thumbnail can carry
+func thumbnailText(title string) string {
+	words := strings.Fields(title)
+	if len(words) > 5 {
+		words = words[:5]
+	}
+	return strings.ToUpper(strings.Join(words, " "))
+}
+
+// predictThumbnailCTR estimates a thumbnail's click-through rate, in percent,
+// from what tends to make thumbnails clickable: contrast, saturated colour,
+// three to five words of text and a face. It is a heuristic for ranking
+// candidates against each other, not a calibrated prediction.
+func predictThumbnailCTR(background image.Image, text, style string) float64 {
+	contrast, saturation := imageContrastAndSaturation(background)
+
+	ctr := 4.0
+	ctr += math.Min(contrast/0.25, 1) * 2.5  // luminance spread; 0.25 and up is punchy
+	ctr += math.Min(saturation/0.5, 1) * 1.5 // average saturation
+	switch words := len(strings.Fields(text)); {
+	case words >= 3 && words <= 5:
+		ctr += 1.0
+	case words > 0 && words < 3:
+		ctr += 0.5
+	}
+	if style == "face_focus" || style == "reaction" {
+		ctr += 1.0
+	}
+	return math.Round(ctr*10) / 10
+}
+
+// imageContrastAndSaturation samples an image and returns the standard
+// deviation of its luminance and its mean saturation, both 0-1
+func imageContrastAndSaturation(img image.Image) (contrast, saturation float64) {
+	bounds := img.Bounds()
+	step := max(1, min(bounds.Dx(), bounds.Dy())/64)
+
+	var sum, sumSq, satSum float64
+	var n int
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, _ := img.At(x, y).RGBA()
+			rf, gf, bf := float64(r)/0xffff, float64(g)/0xffff, float64(b)/0xffff
+			lum := 0.2126*rf + 0.7152*gf + 0.0722*bf
+			sum += lum
+			sumSq += lum * lum
+
+			hi, lo := math.Max(rf, math.Max(gf, bf)), math.Min(rf, math.Min(gf, bf))
+			if hi > 0 {
+				satSum += (hi - lo) / hi
+			}
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	mean := sum / float64(n)
+	return math.Sqrt(math.Max(0, sumSq/float64(n)-mean*mean)), satSum / float64(n)
+}
+
+// GenerateThumbnailBackground generates a 16:9 image for an AI thumbnail with
+// the configured AI image providers, falling back through them in turn
+func (s *AISceneService) GenerateThumbnailBackground(ctx context.Context, prompt string) (image.Image, error) {
+	source := s.DefaultImageSource()
+	if source == SourceUnsplash || source == SourcePexels {
+		source = SourceDALLE
+	}
+	if len(s.imageProviderChain(source)) == 0 {
+		return nil, ErrThumbnailsUnavailable
+	}
+
+	opts := imageOptions{NegativePrompt: thumbnailNegativePrompt, Platform: "youtube"}
+	generated, err := s.generateSceneImage(ctx, source, prompt, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.fetchImage(ctx, generated.ImageURL)
+}
+
+// fetchImage downloads and decodes an image, which providers return either
+// as a URL or inline as a data URL
+func (s *AISceneService) fetchImage(ctx context.Context, imageURL string) (image.Image, error) {
+	var r io.Reader
+	if rest, ok := strings.CutPrefix(imageURL, "data:"); ok {
+		_, payload, found := strings.Cut(rest, ";base64,")
+		if !found {
+			return nil, fmt.Errorf("unsupported image data URL")
+		}
+		r = base64.NewDecoder(base64.StdEncoding, strings.NewReader(payload))
+	} else {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download image: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to download image: status %d", resp.StatusCode)
+		}
+		r = resp.Body
+	}
+
+	img, _, err := image.Decode(io.LimitReader(r, maxThumbnailImageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
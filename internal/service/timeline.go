@@ -1,13 +1,17 @@
 package service
 
 import (
+	"context"
+	"log"
+
 	"renderowl-api/internal/domain"
 	"renderowl-api/internal/repository"
 )
 
 // TimelineService handles timeline business logic
 type TimelineService struct {
-	repo *repository.TimelineRepository
+	repo  *repository.TimelineRepository
+	notes TimelineNoteStore
 }
 
 // NewTimelineService creates a new timeline service
@@ -43,20 +47,26 @@ func (s *TimelineService) Create(userID string, req *CreateTimelineRequest) (*do
 	if err := s.repo.Create(timeline); err != nil {
 		return nil, err
 	}
+	s.recordActivity(context.Background(), timeline, userID, domain.TimelineCreated, "")
 	return timeline, nil
 }
 
-// Get retrieves a timeline by ID
+// Get retrieves a timeline by ID, with its recent activity
 func (s *TimelineService) Get(id, userID string) (*domain.Timeline, error) {
-	return s.repo.GetByIDAndUser(id, userID)
+	timeline, err := s.repo.GetByIDAndUser(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.attachActivity(context.Background(), timeline)
+	return timeline, nil
 }
 
 // List retrieves all timelines for a user
-func (s *TimelineService) List(userID string, limit, offset int) ([]*domain.Timeline, error) {
+func (s *TimelineService) List(userID string, limit, offset int) ([]*domain.Timeline, int64, error) {
 	if limit == 0 {
 		limit = 20
 	}
-	return s.repo.ListByUser(userID, limit, offset)
+	return s.repo.ListByUserWithCount(userID, limit, offset)
 }
 
 // Update updates a timeline
@@ -79,16 +89,37 @@ func (s *TimelineService) Update(id, userID string, req *UpdateTimelineRequest)
 	if err := s.repo.Update(timeline); err != nil {
 		return nil, err
 	}
+	s.recordActivity(context.Background(), timeline, userID, domain.TimelineUpdated, "")
 	return timeline, nil
 }
 
-// Delete deletes a timeline
+// Delete deletes a timeline and its notes
 func (s *TimelineService) Delete(id, userID string) error {
-	_, err := s.repo.GetByIDAndUser(id, userID)
+	timeline, err := s.repo.GetByIDAndUser(id, userID)
 	if err != nil {
 		return err
 	}
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if s.notes != nil {
+		if err := s.notes.DeleteNotes(ctx, id); err != nil {
+			log.Printf("Failed to delete notes of timeline %s: %v", id, err)
+		}
+	}
+	s.recordActivity(ctx, timeline, userID, domain.TimelineDeleted, "")
+	return nil
+}
+
+// GetAudioMix builds the ffmpeg audio mix for a timeline, ducking music under narration
+func (s *TimelineService) GetAudioMix(id, userID string) (*AudioMix, error) {
+	timeline, err := s.repo.GetByIDAndUser(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return BuildAudioMix(timeline)
 }
 
 // Request types
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+
+	"renderowl-api/internal/domain"
+)
+
+// ErrTooFewTimelines is returned when a merge names fewer than two timelines
+var ErrTooFewTimelines = errors.New("at least two timelines are needed to merge")
+
+// MergeTimelinesRequest concatenates timelines, in the order given, into a
+// new timeline
+type MergeTimelinesRequest struct {
+	TimelineIDs []string `json:"timelineIds" binding:"required,min=2,max=20"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+}
+
+// MergedSegment is where one source timeline plays in a merged timeline.
+// Scale is what the renderer scales the segment's frames by to fit the
+// merged resolution; 1 when the source already has it.
+type MergedSegment struct {
+	TimelineID string  `json:"timelineId"`
+	Name       string  `json:"name"`
+	StartTime  float64 `json:"startTime"`
+	EndTime    float64 `json:"endTime"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	Scale      float64 `json:"scale"`
+}
+
+// MergeTimelinesResult is a merged timeline and the segments it is made of
+type MergeTimelinesResult struct {
+	Timeline *domain.Timeline `json:"timeline"`
+	Segments []MergedSegment  `json:"segments"`
+}
+
+// MergeTimelines concatenates the user's timelines into a new one. Each
+// source's clips are shifted by the lengths of the sources before it, and
+// tracks are matched up by type, role and position. The merged timeline
+// takes the first source's resolution and frame rate; sources with another
+// resolution get a note recording the scale the renderer should fit them to.
+func (s *TimelineService) MergeTimelines(ctx context.Context, userID string, req *MergeTimelinesRequest) (*MergeTimelinesResult, error) {
+	if len(req.TimelineIDs) < 2 {
+		return nil, ErrTooFewTimelines
+	}
+
+	sources := make([]*domain.Timeline, 0, len(req.TimelineIDs))
+	for _, id := range req.TimelineIDs {
+		source, err := s.repo.GetByIDAndUser(id, userID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrTimelineNotFound, id)
+		}
+		sources = append(sources, source)
+	}
+
+	first := sources[0]
+	merged := &domain.Timeline{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Width:       first.Width,
+		Height:      first.Height,
+		FPS:         first.FPS,
+	}
+	if merged.Name == "" {
+		merged.Name = first.Name + " (merged)"
+	}
+
+	// Tracks are keyed by type and role, and numbered within each, so the
+	// second video track of every source lands on the same merged track
+	trackIndex := make(map[string]int)
+	segments := make([]MergedSegment, 0, len(sources))
+	offset := 0.0
+	for _, source := range sources {
+		seen := make(map[string]int)
+		for _, track := range source.Tracks {
+			kind := track.Type + "/" + track.Role
+			key := fmt.Sprintf("%s/%d", kind, seen[kind])
+			seen[kind]++
+
+			i, ok := trackIndex[key]
+			if !ok {
+				i = len(merged.Tracks)
+				trackIndex[key] = i
+				merged.Tracks = append(merged.Tracks, domain.Track{
+					Name:    track.Name,
+					Type:    track.Type,
+					Order:   i,
+					Muted:   track.Muted,
+					Solo:    track.Solo,
+					Role:    track.Role,
+					Ducking: track.Ducking,
+				})
+			}
+			for _, clip := range track.Clips {
+				clip.StartTime += offset
+				clip.EndTime += offset
+				merged.Tracks[i].Clips = append(merged.Tracks[i].Clips, clip)
+			}
+		}
+
+		length := timelineLength(source)
+		segments = append(segments, MergedSegment{
+			TimelineID: source.ID,
+			Name:       source.Name,
+			StartTime:  offset,
+			EndTime:    offset + length,
+			Width:      source.Width,
+			Height:     source.Height,
+			Scale:      fitScale(source.Width, source.Height, merged.Width, merged.Height),
+		})
+		offset += length
+	}
+	merged.Duration = offset
+
+	if err := s.repo.CreateWithTracks(merged); err != nil {
+		return nil, fmt.Errorf("failed to create merged timeline: %w", err)
+	}
+	s.recordActivity(ctx, merged, userID, domain.TimelineCreated, fmt.Sprintf("merged from %d timelines", len(sources)))
+	s.addScaleNotes(ctx, merged, userID, segments)
+
+	return &MergeTimelinesResult{Timeline: merged, Segments: segments}, nil
+}
+
+// addScaleNotes leaves a note on the merged timeline for each segment whose
+// resolution differs from it, telling the renderer how to fit its frames
+func (s *TimelineService) addScaleNotes(ctx context.Context, merged *domain.Timeline, userID string, segments []MergedSegment) {
+	if s.notes == nil {
+		return
+	}
+	for i, segment := range segments {
+		if segment.Width == merged.Width && segment.Height == merged.Height {
+			continue
+		}
+		note := &domain.TimelineNote{
+			TimelineID: merged.ID,
+			UserID:     merged.UserID,
+			AuthorID:   userID,
+			Text: fmt.Sprintf("Segment %d (%.2fs-%.2fs, from %q) is %dx%d: scale by %.4g and center to fit %dx%d",
+				i+1, segment.StartTime, segment.EndTime, segment.Name, segment.Width, segment.Height, segment.Scale, merged.Width, merged.Height),
+		}
+		if err := s.notes.CreateNote(ctx, note); err != nil {
+			log.Printf("Failed to add scale note to merged timeline %s: %v", merged.ID, err)
+		}
+	}
+}
+
+// timelineLength is how long a timeline plays: its duration, or the end of
+// its last clip when clips run past the duration
+func timelineLength(timeline *domain.Timeline) float64 {
+	length := timeline.Duration
+	for _, track := range timeline.Tracks {
+		for _, clip := range track.Clips {
+			length = math.Max(length, clip.EndTime)
+		}
+	}
+	return length
+}
+
+// fitScale returns the scale that fits a width x height frame inside the
+// target frame without cropping
+func fitScale(width, height, targetWidth, targetHeight int) float64 {
+	if width <= 0 || height <= 0 {
+		return 1
+	}
+	return math.Min(float64(targetWidth)/float64(width), float64(targetHeight)/float64(height))
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"testing"
+
+	"renderowl-api/internal/domain"
+)
+
+func TestMergedSourcesAreAsLongAsTheirClips(t *testing.T) {
+	timeline := &domain.Timeline{
+		Duration: 60,
+		Tracks: []domain.Track{
+			{Clips: []domain.Clip{{StartTime: 0, EndTime: 40}}},
+			{Clips: []domain.Clip{{StartTime: 30, EndTime: 95.5}}},
+		},
+	}
+	if got := timelineLength(timeline); got != 95.5 {
+		t.Errorf("timeline with clips past its duration: length %v, want 95.5", got)
+	}
+
+	timeline.Tracks[1].Clips[0].EndTime = 50
+	if got := timelineLength(timeline); got != 60 {
+		t.Errorf("timeline with clips inside its duration: length %v, want 60", got)
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"renderowl-api/internal/domain"
+)
+
+// Timeline note errors
+var (
+	ErrTimelineNotFound = errors.New("timeline not found")
+	ErrNoteClipNotFound = errors.New("clip not found on this timeline")
+	ErrEmptyNote        = errors.New("note text is required")
+)
+
+// recentTimelineActivity is how much activity is returned with a timeline
+const recentTimelineActivity = 20
+
+// TimelineNoteStore persists timeline notes and the activity log
+type TimelineNoteStore interface {
+	CreateNote(ctx context.Context, note *domain.TimelineNote) error
+	ListNotes(ctx context.Context, timelineID string, limit, offset int) ([]*domain.TimelineNote, int64, error)
+	DeleteNotes(ctx context.Context, timelineID string) error
+	RecordActivity(ctx context.Context, activity *domain.TimelineActivity) error
+	ListActivity(ctx context.Context, timelineID string, limit int) ([]domain.TimelineActivity, error)
+}
+
+// AddNoteRequest represents a request to add a note to a timeline
+type AddNoteRequest struct {
+	Text   string `json:"text" binding:"required,max=4000"`
+	ClipID string `json:"clipId"` // optional clip the note is about
+}
+
+// SetNoteStore enables timeline notes and the activity log
+func (s *TimelineService) SetNoteStore(notes TimelineNoteStore) {
+	s.notes = notes
+}
+
+// AddNote adds a note to one of the user's timelines
+func (s *TimelineService) AddNote(ctx context.Context, timelineID, userID string, req *AddNoteRequest) (*domain.TimelineNote, error) {
+	timeline, err := s.repo.GetByIDAndUser(timelineID, userID)
+	if err != nil {
+		return nil, ErrTimelineNotFound
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, ErrEmptyNote
+	}
+
+	note := &domain.TimelineNote{
+		TimelineID: timeline.ID,
+		UserID:     timeline.UserID,
+		AuthorID:   userID,
+		Text:       text,
+	}
+	if req.ClipID != "" {
+		if findClip(timeline, req.ClipID) == nil {
+			return nil, ErrNoteClipNotFound
+		}
+		note.ClipID = &req.ClipID
+	}
+
+	if s.notes == nil {
+		return nil, fmt.Errorf("timeline notes are not enabled")
+	}
+	if err := s.notes.CreateNote(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to add note: %w", err)
+	}
+	s.recordActivity(ctx, timeline, userID, domain.TimelineNoteAdded, "")
+	return note, nil
+}
+
+// ListNotes returns a page of the notes on one of the user's timelines,
+// newest first, and the total
+func (s *TimelineService) ListNotes(ctx context.Context, timelineID, userID string, limit, offset int) ([]*domain.TimelineNote, int64, error) {
+	if _, err := s.repo.GetByIDAndUser(timelineID, userID); err != nil {
+		return nil, 0, ErrTimelineNotFound
+	}
+	if s.notes == nil {
+		return []*domain.TimelineNote{}, 0, nil
+	}
+	return s.notes.ListNotes(ctx, timelineID, limit, offset)
+}
+
+// RecordRender logs that a timeline was rendered to a video
+func (s *TimelineService) RecordRender(ctx context.Context, timeline *domain.Timeline, detail string) {
+	s.recordActivity(ctx, timeline, timeline.UserID, domain.TimelineRendered, detail)
+}
+
+// recordActivity adds an entry to a timeline's activity log. The log is
+// informational, so failures are only logged.
+func (s *TimelineService) recordActivity(ctx context.Context, timeline *domain.Timeline, actorID, action, detail string) {
+	if s.notes == nil {
+		return
+	}
+	activity := &domain.TimelineActivity{
+		TimelineID: timeline.ID,
+		UserID:     timeline.UserID,
+		ActorID:    actorID,
+		Action:     action,
+		Detail:     detail,
+	}
+	if err := s.notes.RecordActivity(ctx, activity); err != nil {
+		log.Printf("Failed to record %s activity on timeline %s: %v", action, timeline.ID, err)
+	}
+}
+
+// attachActivity fills in a timeline's recent activity
+func (s *TimelineService) attachActivity(ctx context.Context, timeline *domain.Timeline) {
+	if s.notes == nil {
+		return
+	}
+	activity, err := s.notes.ListActivity(ctx, timeline.ID, recentTimelineActivity)
+	if err != nil {
+		log.Printf("Failed to load activity of timeline %s: %v", timeline.ID, err)
+		return
+	}
+	timeline.Activity = activity
+}
+
+// findClip returns the timeline's clip with the given ID, or nil
+func findClip(timeline *domain.Timeline, clipID string) *domain.Clip {
+	for i := range timeline.Tracks {
+		for j := range timeline.Tracks[i].Clips {
+			if timeline.Tracks[i].Clips[j].ID == clipID {
+				return &timeline.Tracks[i].Clips[j]
+			}
+		}
+	}
+	return nil
+}
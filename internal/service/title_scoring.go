@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// titleTemplates seed title variations; [Topic] is replaced with the source
+// video's keywords
+var titleTemplates = []struct {
+	style string
+	title string
+}{
+	{"question", "Is This the Future of [Topic]?"},
+	{"list", "7 [Topic] Secrets Experts Don't Want You to Know"},
+	{"how-to", "How to Master [Topic] in 30 Days"},
+	{"controversy", "Why Everything You Know About [Topic] is Wrong"},
+	{"urgency", "Stop Doing [Topic] Wrong (Do This Instead)"},
+	{"story", "I Tried [Topic] for 30 Days. Here's What Happened"},
+}
+
+// maxTitleTopics caps how many keywords titles are generated for
+const maxTitleTopics = 3
+
+// titlePowerWords are words that tend to lift click-through on titles
+var titlePowerWords = map[string]bool{
+	"secret": true, "secrets": true, "truth": true, "mistake": true, "mistakes": true,
+	"proven": true, "ultimate": true, "best": true, "worst": true, "wrong": true,
+	"stop": true, "never": true, "instantly": true, "easy": true, "fast": true,
+	"shocking": true, "master": true, "insane": true, "finally": true, "why": true,
+}
+
+// TitleCritic reviews title candidates with an AI model
+type TitleCritic interface {
+	CompleteJSON(ctx context.Context, systemPrompt, userPrompt string) (string, error)
+}
+
+// SetWinningContent lets title scoring favour the patterns of a user's best
+// performing videos, read from the optimizer's cached analysis
+func (s *VariationsService) SetWinningContent(store WinningContentCacheStore) {
+	s.winningContent = store
+}
+
+// SetTitleCritic enables the AI critique pass on title variations
+func (s *VariationsService) SetTitleCritic(critic TitleCritic) {
+	s.titleCritic = critic
+}
+
+// CreateTitleVariations generates title A/B test variations for the source
+// video's keywords, scored and sorted best first
+func (s *VariationsService) CreateTitleVariations(ctx context.Context, userID string, req *CreateVariationsRequest) ([]TitleVariation, error) {
+	return s.createTitleVariations(ctx, userID, req, "")
+}
+
+// createTitleVariations generates title variations, only in the given style
+// unless it's empty
+func (s *VariationsService) createTitleVariations(ctx context.Context, userID string, req *CreateVariationsRequest, style string) ([]TitleVariation, error) {
+	count := req.TitleCount
+	if count == 0 {
+		count = 5
+	}
+
+	patterns := s.winningTitlePatterns(ctx, userID)
+	topics := titleTopics(req)
+
+	var variations []TitleVariation
+	seen := make(map[string]bool)
+	for _, topic := range topics {
+		for _, tmpl := range titleTemplates {
+			if style != "" && tmpl.style != style {
+				continue
+			}
+			title := strings.ReplaceAll(tmpl.title, "[Topic]", topic)
+			if seen[strings.ToLower(title)] {
+				continue
+			}
+			seen[strings.ToLower(title)] = true
+
+			score, reasons := scoreTitle(title, patterns)
+			variations = append(variations, TitleVariation{
+				ID:       uuid.New().String(),
+				SourceID: req.SourceVideoID,
+				Title:    title,
+				Style:    tmpl.style,
+				Score:    score,
+				Reason:   strings.Join(reasons, "; "),
+				Keywords: []string{topic, tmpl.style},
+			})
+		}
+	}
+	sortTitles(variations)
+
+	if req.CritiqueTitles && s.titleCritic != nil {
+		// Only the front runners are worth a critique
+		shortlist := variations[:min(len(variations), count*2)]
+		if err := s.critiqueTitles(ctx, shortlist); err != nil {
+			log.Printf("Title critique failed, keeping heuristic scores: %v", err)
+		} else {
+			sortTitles(variations)
+		}
+	}
+
+	if len(variations) > count {
+		variations = variations[:count]
+	}
+	for i := range variations {
+		variations[i].PredictedViews = int(variations[i].Score * 10000)
+	}
+	return variations, nil
+}
+
+// titleTopics returns the topics to fill templates with: the request's first
+// keywords, or its title when it has none
+func titleTopics(req *CreateVariationsRequest) []string {
+	var topics []string
+	for _, keyword := range req.Keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			topics = append(topics, titleCase(keyword))
+		}
+		if len(topics) == maxTitleTopics {
+			break
+		}
+	}
+	if len(topics) == 0 && strings.TrimSpace(req.Title) != "" {
+		topics = append(topics, strings.TrimSpace(req.Title))
+	}
+	if len(topics) == 0 {
+		topics = append(topics, "This")
+	}
+	return topics
+}
+
+// scoreTitle rates a title from 0 to 10 on its length, numbers, power words
+// and questions, plus how well it matches the patterns of the user's winning
+// videos. It returns the reasons behind the score.
+func scoreTitle(title string, patterns map[string]int) (float64, []string) {
+	score := 5.0
+	var reasons []string
+
+	switch length := len([]rune(title)); {
+	case length >= 40 && length <= 60:
+		score += 1.5
+		reasons = append(reasons, fmt.Sprintf("%d characters, the ideal length", length))
+	case length >= 30 && length <= 70:
+		score += 0.5
+		reasons = append(reasons, fmt.Sprintf("%d characters, a good length", length))
+	case length > 70:
+		score -= 1.5
+		reasons = append(reasons, fmt.Sprintf("%d characters, truncated on mobile", length))
+	default:
+		score -= 1
+		reasons = append(reasons, fmt.Sprintf("%d characters, too short to say much", length))
+	}
+
+	if strings.ContainsAny(title, "0123456789") {
+		score += 1
+		reasons = append(reasons, "has a number")
+	}
+
+	var power []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		if titlePowerWords[word] {
+			power = append(power, word)
+		}
+	}
+	if len(power) > 0 {
+		score += math.Min(0.5*float64(len(power)), 1.5)
+		reasons = append(reasons, "power words: "+strings.Join(power, ", "))
+	}
+
+	if strings.HasSuffix(strings.TrimSpace(title), "?") {
+		score += 0.75
+		reasons = append(reasons, "asks a question")
+	}
+
+	total := 0
+	for _, n := range patterns {
+		total += n
+	}
+	if total > 0 {
+		for _, pattern := range titlePatterns(title) {
+			if n := patterns[pattern]; n > 0 {
+				score += 2 * float64(n) / float64(total)
+				reasons = append(reasons, fmt.Sprintf("matches your winning %s pattern", pattern))
+			}
+		}
+	}
+
+	return math.Round(math.Max(0, math.Min(score, 10))*10) / 10, reasons
+}
+
+// titlePatterns returns the winning content patterns a title shows
+func titlePatterns(title string) []string {
+	var patterns []string
+	if strings.Contains(title, "How") {
+		patterns = append(patterns, "how-to")
+	}
+	if strings.Contains(title, "vs") || strings.Contains(title, "Versus") {
+		patterns = append(patterns, "comparison")
+	}
+	if strings.ContainsAny(title, "0123456789") {
+		patterns = append(patterns, "listicle")
+	}
+	return patterns
+}
+
+// winningTitlePatterns returns the title patterns of the user's best videos,
+// or nil when no analysis has been cached
+func (s *VariationsService) winningTitlePatterns(ctx context.Context, userID string) map[string]int {
+	if s.winningContent == nil || userID == "" {
+		return nil
+	}
+	cache, err := s.winningContent.GetWinningContentCache(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	var analysis WinningContentAnalysis
+	if err := json.Unmarshal(cache.Analysis, &analysis); err != nil {
+		log.Printf("Failed to read winning content for user %s: %v", userID, err)
+		return nil
+	}
+	return analysis.TitlePatterns
+}
+
+// critiqueTitles asks the AI critic to adjust each title's score by up to two
+// points either way, and adds its reason to the title's
+func (s *VariationsService) critiqueTitles(ctx context.Context, titles []TitleVariation) error {
+	systemPrompt := `You are a YouTube title strategist reviewing title candidates.
+
+For each numbered title, adjust its score between -2 and 2: raise titles that are specific, curiosity-driven and honest, lower ones that are vague, clickbait or awkward.
+Give a reason of at most 12 words.
+
+Respond ONLY with a valid JSON object in this exact format:
+{
+  "results": [{"index": 1, "adjustment": 0.5, "reason": "specific promise with a clear payoff"}]
+}`
+
+	var b strings.Builder
+	for i, title := range titles {
+		fmt.Fprintf(&b, "%d. %s (score %.1f)\n", i+1, title.Title, title.Score)
+	}
+
+	content, err := s.titleCritic.CompleteJSON(ctx, systemPrompt, b.String())
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Results []struct {
+			Index      int     `json:"index"`
+			Adjustment float64 `json:"adjustment"`
+			Reason     string  `json:"reason"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return fmt.Errorf("failed to parse critique JSON: %w", err)
+	}
+
+	for _, r := range result.Results {
+		if r.Index < 1 || r.Index > len(titles) {
+			continue
+		}
+		title := &titles[r.Index-1]
+		adjusted := title.Score + math.Max(-2, math.Min(r.Adjustment, 2))
+		title.Score = math.Round(math.Max(0, math.Min(adjusted, 10))*10) / 10
+		if reason := strings.TrimSpace(r.Reason); reason != "" {
+			title.Reason += "; AI: " + reason
+		}
+	}
+	return nil
+}
+
+// sortTitles orders titles best first
+func sortTitles(titles []TitleVariation) {
+	sort.SliceStable(titles, func(i, j int) bool { return titles[i].Score > titles[j].Score })
+}
+
+// titleCase capitalizes the first letter of each word
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
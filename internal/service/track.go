@@ -2,11 +2,15 @@ package service
 
 import (
 	"errors"
+	"fmt"
 
 	"renderowl-api/internal/domain"
 	"renderowl-api/internal/repository"
 )
 
+// ErrInvalidTrackSettings is returned when a track update has an unknown role or out of range ducking
+var ErrInvalidTrackSettings = errors.New("invalid track settings")
+
 // TrackService handles track business logic
 type TrackService struct {
 	trackRepo    *repository.TrackRepository
@@ -84,6 +88,23 @@ func (s *TrackService) Update(userID, trackID string, req *UpdateTrackRequest) (
 	if req.Name != "" {
 		track.Name = req.Name
 	}
+	if req.Role != nil {
+		switch *req.Role {
+		case "", domain.TrackRoleMusic, domain.TrackRoleNarration, domain.TrackRoleSFX:
+			track.Role = *req.Role
+		default:
+			return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidTrackSettings, *req.Role)
+		}
+	}
+	if req.Ducking != nil {
+		if err := validateDucking(req.Ducking); err != nil {
+			return nil, err
+		}
+		track.Ducking = req.Ducking
+		if req.Ducking.AmountDB == 0 {
+			track.Ducking = nil
+		}
+	}
 
 	if err := s.trackRepo.Update(track); err != nil {
 		return nil, err
@@ -136,7 +157,22 @@ type CreateTrackRequest struct {
 }
 
 type UpdateTrackRequest struct {
-	Name string `json:"name"`
+	Name    string          `json:"name"`
+	Role    *string         `json:"role,omitempty"`
+	Ducking *domain.Ducking `json:"ducking,omitempty"` // an amountDb of 0 turns ducking off
+}
+
+// validateDucking checks ducking settings are within what a mix can use
+func validateDucking(d *domain.Ducking) error {
+	switch {
+	case d.AmountDB < 0 || d.AmountDB > 60:
+		return fmt.Errorf("%w: ducking amountDb must be between 0 and 60", ErrInvalidTrackSettings)
+	case d.AttackMs < 0 || d.AttackMs > 5000:
+		return fmt.Errorf("%w: ducking attackMs must be between 0 and 5000", ErrInvalidTrackSettings)
+	case d.ReleaseMs < 0 || d.ReleaseMs > 5000:
+		return fmt.Errorf("%w: ducking releaseMs must be between 0 and 5000", ErrInvalidTrackSettings)
+	}
+	return nil
 }
 
 type ReorderTracksRequest struct {
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"renderowl-api/internal/safehttp"
+)
+
+const (
+	defaultTranscriptionModel = "whisper-1"
+
+	// maxTranscriptionBytes is the largest file the transcriptions API accepts
+	maxTranscriptionBytes = 25 << 20
+)
+
+// ErrMediaFetch is returned when the media to transcribe can't be downloaded
+var ErrMediaFetch = errors.New("failed to fetch media")
+
+// ErrMediaURLNotAllowed is returned when the media to transcribe is on a
+// host that isn't public
+var ErrMediaURLNotAllowed = errors.New("media URL must be on a public host")
+
+// ErrMediaTooLarge is returned when the media to transcribe is over the API's size limit
+var ErrMediaTooLarge = errors.New("media is larger than 25MB")
+
+// Caption formats a transcript can be rendered in
+const (
+	CaptionFormatSRT = "srt"
+	CaptionFormatVTT = "vtt"
+)
+
+// TranscriptionService transcribes existing videos and audio with the OpenAI
+// transcriptions (Whisper) API
+type TranscriptionService struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+	// mediaClient downloads the media to transcribe, from public addresses
+	// only, so a media URL can't be used to reach the internal network
+	mediaClient *http.Client
+
+	// timeouts bounds each provider call; nil uses the defaults
+	timeouts aiTimeouts
+	// openAIAccount is who OpenAI calls are billed to
+	openAIAccount openAIAccount
+}
+
+// TranscribeRequest represents a transcription request
+type TranscribeRequest struct {
+	URL      string `json:"url" binding:"required,url"`
+	Language string `json:"language,omitempty"` // ISO-639-1 code; detected when empty
+	Prompt   string `json:"prompt,omitempty"`   // spellings and context to guide the model
+	Format   string `json:"format,omitempty" binding:"omitempty,oneof=srt vtt"`
+}
+
+// TranscriptSegment is a timed piece of a transcript, in seconds from the start
+type TranscriptSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// Transcript is the transcription of a video or audio file
+type Transcript struct {
+	Language string              `json:"language"`
+	Duration float64             `json:"duration"`
+	Text     string              `json:"text"`
+	Segments []TranscriptSegment `json:"segments"`
+	Format   string              `json:"format,omitempty"`
+	Captions string              `json:"captions,omitempty"` // the segments as SRT or VTT
+}
+
+// NewTranscriptionService creates a new transcription service
+func NewTranscriptionService() *TranscriptionService {
+	return &TranscriptionService{
+		apiKey:  os.Getenv("OPENAI_API_KEY"),
+		baseURL: getBaseURL("OPENAI_BASE_URL", defaultOpenAIBaseURL),
+		model:   getEnv("TRANSCRIPTION_MODEL", defaultTranscriptionModel),
+		httpClient: &http.Client{
+			Timeout: 300 * time.Second,
+		},
+		mediaClient: safehttp.NewClient(5 * time.Minute),
+	}
+}
+
+// Transcribe downloads the media at req.URL and transcribes it into timed
+// segments, rendered as captions too when a format is requested
+func (s *TranscriptionService) Transcribe(ctx context.Context, req *TranscribeRequest) (*Transcript, error) {
+	return withTimeout(ctx, s.timeouts, AIOpTranscription, func(ctx context.Context) (*Transcript, error) {
+		return s.transcribe(ctx, req)
+	})
+}
+
+// transcribe fetches the media and sends it to the transcriptions API
+func (s *TranscriptionService) transcribe(ctx context.Context, req *TranscribeRequest) (*Transcript, error) {
+	if s.apiKey == "" {
+		return nil, fmt.Errorf("OpenAI API key not configured")
+	}
+
+	media, filename, err := s.fetchMedia(ctx, req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(media); err != nil {
+		return nil, fmt.Errorf("failed to write form file: %w", err)
+	}
+	fields := map[string]string{
+		"model":                     s.model,
+		"response_format":           "verbose_json",
+		"timestamp_granularities[]": "segment",
+		"language":                  req.Language,
+		"prompt":                    req.Prompt,
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("failed to write form field: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close form: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/audio/transcriptions", &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	setOpenAIAuth(httpReq, s.apiKey, s.openAIAccount)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Language string  `json:"language"`
+		Duration float64 `json:"duration"`
+		Text     string  `json:"text"`
+		Segments []struct {
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+			Text  string  `json:"text"`
+		} `json:"segments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	transcript := &Transcript{
+		Language: result.Language,
+		Duration: result.Duration,
+		Text:     strings.TrimSpace(result.Text),
+		Segments: make([]TranscriptSegment, 0, len(result.Segments)),
+		Format:   req.Format,
+	}
+	for _, seg := range result.Segments {
+		transcript.Segments = append(transcript.Segments, TranscriptSegment{
+			Start: seg.Start,
+			End:   seg.End,
+			Text:  strings.TrimSpace(seg.Text),
+		})
+	}
+
+	switch req.Format {
+	case CaptionFormatSRT:
+		transcript.Captions = transcript.SRT()
+	case CaptionFormatVTT:
+		transcript.Captions = transcript.VTT()
+	}
+
+	return transcript, nil
+}
+
+// fetchMedia downloads the media to transcribe, returning it with a filename
+// whose extension tells the API its format
+func (s *TranscriptionService) fetchMedia(ctx context.Context, mediaURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", mediaURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrMediaFetch, err)
+	}
+
+	resp, err := s.mediaClient.Do(req)
+	if errors.Is(err, safehttp.ErrBlockedAddress) {
+		return nil, "", ErrMediaURLNotAllowed
+	}
+	if err != nil {
+		return nil, "", ErrMediaFetch
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", ErrMediaFetch
+	}
+	if resp.ContentLength > maxTranscriptionBytes {
+		return nil, "", ErrMediaTooLarge
+	}
+
+	media, err := io.ReadAll(io.LimitReader(resp.Body, maxTranscriptionBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrMediaFetch, err)
+	}
+	if len(media) > maxTranscriptionBytes {
+		return nil, "", ErrMediaTooLarge
+	}
+
+	filename := "media.mp4"
+	if u, err := url.Parse(mediaURL); err == nil {
+		if base := path.Base(u.Path); path.Ext(base) != "" {
+			filename = base
+		}
+	}
+
+	return media, filename, nil
+}
+
+// Between returns the segments overlapping [start, end), clipped to it and
+// shifted so times are relative to start. Shorts cut from a longer video use
+// it to caption just their part.
+func (t *Transcript) Between(start, end float64) []TranscriptSegment {
+	var segments []TranscriptSegment
+	for _, seg := range t.Segments {
+		if seg.End <= start || seg.Start >= end {
+			continue
+		}
+		segments = append(segments, TranscriptSegment{
+			Start: math.Max(seg.Start, start) - start,
+			End:   math.Min(seg.End, end) - start,
+			Text:  seg.Text,
+		})
+	}
+	return segments
+}
+
+// SRT renders the transcript as SubRip captions
+func (t *Transcript) SRT() string {
+	var b strings.Builder
+	for i, seg := range t.Segments {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1,
+			formatCaptionTime(seg.Start, ","), formatCaptionTime(seg.End, ","), seg.Text)
+	}
+	return b.String()
+}
+
+// VTT renders the transcript as WebVTT captions
+func (t *Transcript) VTT() string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, seg := range t.Segments {
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n",
+			formatCaptionTime(seg.Start, "."), formatCaptionTime(seg.End, "."), seg.Text)
+	}
+	return b.String()
+}
+
+// formatCaptionTime formats seconds as hh:mm:ss followed by sep and milliseconds
+func formatCaptionTime(seconds float64, sep string) string {
+	ms := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
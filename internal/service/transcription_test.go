@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	socialdomain "renderowl-api/internal/domain/social"
+)
+
+func TestTranscribeOnlyFetchesPublicMedia(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "internal", http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	transcriber := NewTranscriptionService()
+	transcriber.apiKey = "openai-key"
+	_, err := transcriber.Transcribe(context.Background(), &TranscribeRequest{URL: server.URL + "/audio.mp3"})
+	if !errors.Is(err, ErrMediaURLNotAllowed) {
+		t.Errorf("internal media URL: got %v, want ErrMediaURLNotAllowed", err)
+	}
+	if requests != 0 {
+		t.Errorf("internal server got %d requests", requests)
+	}
+	if err != nil && strings.Contains(err.Error(), "418") {
+		t.Errorf("error %q reveals the internal server's answer", err)
+	}
+}
+
+func TestWritePostCopyRefusesInternalMedia(t *testing.T) {
+	transcriber := NewTranscriptionService()
+	transcriber.apiKey = "openai-key"
+	writer := NewPostCopyService(nil, transcriber)
+
+	_, err := writer.WritePostCopy(context.Background(), &socialdomain.PostCopyRequest{VideoURL: "http://127.0.0.1:9/video.mp4", Topic: "cats"})
+	if !errors.Is(err, ErrMediaURLNotAllowed) {
+		t.Errorf("internal video URL: got %v, want ErrMediaURLNotAllowed", err)
+	}
+}
@@ -11,13 +11,24 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"renderowl-api/internal/domain"
 )
 
 // TTSService handles text-to-speech generation
 type TTSService struct {
-	elevenLabsKey string
-	openAIKey     string
-	httpClient    *http.Client
+	elevenLabsKey     string
+	openAIKey         string
+	elevenLabsBaseURL string
+	openAIBaseURL     string
+	httpClient        *http.Client
+	voiceStore        ClonedVoiceStore
+	usage             *UsageService
+
+	// timeouts bounds each provider call; nil uses the defaults
+	timeouts aiTimeouts
+	// openAIAccount is who OpenAI calls are billed to
+	openAIAccount openAIAccount
 }
 
 // TTSProvider represents the TTS provider
@@ -41,6 +52,7 @@ type Voice struct {
 	PreviewURL  string            `json:"preview_url,omitempty"`
 	Category    string            `json:"category,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
+	Cloned      bool              `json:"cloned,omitempty"` // A clone the requesting user made
 }
 
 // GenerateVoiceRequest represents a voice generation request
@@ -127,14 +139,21 @@ func (b *SSMLBuilder) AddSentence(text string) {
 // NewTTSService creates a new TTS service
 func NewTTSService() *TTSService {
 	return &TTSService{
-		elevenLabsKey: os.Getenv("ELEVENLABS_API_KEY"),
-		openAIKey:     os.Getenv("OPENAI_API_KEY"),
+		elevenLabsKey:     os.Getenv("ELEVENLABS_API_KEY"),
+		openAIKey:         os.Getenv("OPENAI_API_KEY"),
+		elevenLabsBaseURL: getBaseURL("ELEVENLABS_BASE_URL", defaultElevenLabsBaseURL),
+		openAIBaseURL:     getBaseURL("OPENAI_BASE_URL", defaultOpenAIBaseURL),
 		httpClient: &http.Client{
 			Timeout: 120 * time.Second,
 		},
 	}
 }
 
+// SetUsage records the characters each generation is charged for
+func (s *TTSService) SetUsage(usage *UsageService) {
+	s.usage = usage
+}
+
 // ListVoices returns available voices from all configured providers
 func (s *TTSService) ListVoices(ctx context.Context) ([]Voice, error) {
 	var voices []Voice
@@ -158,7 +177,7 @@ func (s *TTSService) ListVoices(ctx context.Context) ([]Voice, error) {
 
 // listElevenLabsVoices fetches voices from ElevenLabs
 func (s *TTSService) listElevenLabsVoices(ctx context.Context) ([]Voice, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", "https://api.elevenlabs.io/v1/voices", nil)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", s.elevenLabsBaseURL+"/voices", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +192,7 @@ func (s *TTSService) listElevenLabsVoices(ctx context.Context) ([]Voice, error)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("elevenlabs error: %s", string(body))
+		return nil, &ProviderError{StatusCode: resp.StatusCode, Err: fmt.Errorf("elevenlabs error: %s", string(body))}
 	}
 
 	var result struct {
@@ -237,7 +256,8 @@ func (s *TTSService) getOpenAIVoices() []Voice {
 
 // GenerateVoice generates voice audio from text
 func (s *TTSService) GenerateVoice(ctx context.Context, req *GenerateVoiceRequest) (*GenerateVoiceResponse, error) {
-	// Set defaults
+	// Set defaults; only a defaulted provider may fall back to another one
+	canFallback := req.Provider == "" && s.elevenLabsKey != "" && s.openAIKey != ""
 	if req.Provider == "" {
 		if s.elevenLabsKey != "" {
 			req.Provider = ProviderElevenLabs
@@ -256,14 +276,37 @@ func (s *TTSService) GenerateVoice(ctx context.Context, req *GenerateVoiceReques
 
 	switch req.Provider {
 	case ProviderElevenLabs:
-		return s.generateWithElevenLabs(ctx, req)
+		calls := []providerCall[*GenerateVoiceResponse]{{providerElevenLabs, func() (*GenerateVoiceResponse, error) {
+			return withTimeout(ctx, s.timeouts, AIOpTTS, func(ctx context.Context) (*GenerateVoiceResponse, error) {
+				return s.generateWithElevenLabs(ctx, req)
+			})
+		}}}
+		if canFallback {
+			// ElevenLabs voice IDs mean nothing to OpenAI, so use its default voice
+			fallback := *req
+			fallback.Provider = ProviderOpenAI
+			fallback.VoiceID = openAIFallbackVoice
+			calls = append(calls, providerCall[*GenerateVoiceResponse]{providerOpenAI, func() (*GenerateVoiceResponse, error) {
+				return withTimeout(ctx, s.timeouts, AIOpTTS, func(ctx context.Context) (*GenerateVoiceResponse, error) {
+					return s.generateWithOpenAI(ctx, &fallback)
+				})
+			}})
+		}
+		return withFallback(calls...)
 	case ProviderOpenAI:
-		return s.generateWithOpenAI(ctx, req)
+		return withBreaker(providerOpenAI, func() (*GenerateVoiceResponse, error) {
+			return withTimeout(ctx, s.timeouts, AIOpTTS, func(ctx context.Context) (*GenerateVoiceResponse, error) {
+				return s.generateWithOpenAI(ctx, req)
+			})
+		})
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", req.Provider)
 	}
 }
 
+// openAIFallbackVoice is used when a defaulted ElevenLabs request falls back to OpenAI
+const openAIFallbackVoice = "alloy"
+
 // generateWithElevenLabs generates voice using ElevenLabs
 func (s *TTSService) generateWithElevenLabs(ctx context.Context, req *GenerateVoiceRequest) (*GenerateVoiceResponse, error) {
 	if s.elevenLabsKey == "" {
@@ -294,7 +337,7 @@ func (s *TTSService) generateWithElevenLabs(ctx context.Context, req *GenerateVo
 		return nil, err
 	}
 
-	url := fmt.Sprintf("https://api.elevenlabs.io/v1/text-to-speech/%s", req.VoiceID)
+	url := fmt.Sprintf("%s/text-to-speech/%s", s.elevenLabsBaseURL, req.VoiceID)
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, err
@@ -311,7 +354,7 @@ func (s *TTSService) generateWithElevenLabs(ctx context.Context, req *GenerateVo
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("elevenlabs error: %s", string(body))
+		return nil, &ProviderError{StatusCode: resp.StatusCode, Err: fmt.Errorf("elevenlabs error: %s", string(body))}
 	}
 
 	// Read audio data
@@ -320,6 +363,8 @@ func (s *TTSService) generateWithElevenLabs(ctx context.Context, req *GenerateVo
 		return nil, err
 	}
 
+	s.usage.Record(ctx, domain.UsageEvent{Service: usageServiceTTS, Provider: providerElevenLabs, Model: req.Model, Characters: len(req.Text)})
+
 	// Estimate duration (rough estimate: ~150 words per minute)
 	wordCount := len(bytes.Fields([]byte(req.Text)))
 	duration := float64(wordCount) / 150.0 * 60.0
@@ -355,13 +400,13 @@ func (s *TTSService) generateWithOpenAI(ctx context.Context, req *GenerateVoiceR
 		return nil, err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/audio/speech", bytes.NewBuffer(jsonBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.openAIBaseURL+"/audio/speech", bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, err
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+s.openAIKey)
+	setOpenAIAuth(httpReq, s.openAIKey, s.openAIAccount)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
@@ -371,7 +416,7 @@ func (s *TTSService) generateWithOpenAI(ctx context.Context, req *GenerateVoiceR
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("openai error: %s", string(body))
+		return nil, &ProviderError{StatusCode: resp.StatusCode, Err: fmt.Errorf("openai error: %s", string(body))}
 	}
 
 	// Read audio data
@@ -380,6 +425,8 @@ func (s *TTSService) generateWithOpenAI(ctx context.Context, req *GenerateVoiceR
 		return nil, err
 	}
 
+	s.usage.Record(ctx, domain.UsageEvent{Service: usageServiceTTS, Provider: providerOpenAI, Model: "tts-1-hd", Characters: len(req.Text)})
+
 	// Estimate duration
 	wordCount := len(bytes.Fields([]byte(req.Text)))
 	duration := float64(wordCount) / 150.0 * 60.0
@@ -394,29 +441,6 @@ func (s *TTSService) generateWithOpenAI(ctx context.Context, req *GenerateVoiceR
 	}, nil
 }
 
-// CloneVoice creates a voice clone from audio samples (ElevenLabs only)
-func (s *TTSService) CloneVoice(ctx context.Context, name string, description string, sampleFiles [][]byte) (*Voice, error) {
-	if s.elevenLabsKey == "" {
-		return nil, fmt.Errorf("elevenlabs not configured")
-	}
-
-	// Build multipart form
-	body := &bytes.Buffer{}
-	writer := bytes.NewBuffer(nil) // We'll use a different approach
-
-	_ = body
-	_ = writer
-
-	// For now, return a placeholder - full implementation would use multipart form
-	return &Voice{
-		ID:          "cloned_" + name,
-		Name:        name,
-		Provider:    ProviderElevenLabs,
-		Category:    "cloned",
-		Description: description,
-	}, fmt.Errorf("voice cloning not fully implemented")
-}
-
 // ValidateSSML validates SSML markup
 func (s *TTSService) ValidateSSML(ssml string) error {
 	if !strings.HasPrefix(ssml, "<speak>") {
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"renderowl-api/internal/domain"
+)
+
+// Usage errors
+var (
+	ErrInvalidUsagePeriod = errors.New("period must be day, week or month")
+	ErrInvalidUsageRange  = errors.New("usage range must end after it starts")
+)
+
+// Services provider usage is recorded under
+const (
+	usageServiceScript  = "script"
+	usageServiceScene   = "scene"
+	usageServiceImage   = "image"
+	usageServiceAltText = "alt_text"
+	usageServiceTTS     = "tts"
+)
+
+// Usage events are written in batches by a background writer. Events
+// recorded while the buffer is full are dropped rather than slowing the
+// request that made them.
+const (
+	usageBufferSize    = 1024
+	usageBatchSize     = 100
+	usageFlushInterval = 5 * time.Second
+	usageFlushTimeout  = 10 * time.Second
+)
+
+// usagePeriods are the periods usage can be summarized by
+var usagePeriods = map[string]bool{"day": true, "week": true, "month": true}
+
+// usagePrice is what a provider charges for a model, in USD
+type usagePrice struct {
+	PromptPerMillion     float64 // per million prompt tokens
+	CompletionPerMillion float64 // per million completion tokens
+	CharactersPerMillion float64
+	PerImage             float64
+}
+
+// usagePrices are list prices keyed by "provider/model", or by provider
+// alone for providers that charge the same for every model. Usage of a model
+// missing here is recorded without a cost estimate.
+var usagePrices = map[string]usagePrice{
+	"openai/gpt-4o-mini": {PromptPerMillion: 0.15, CompletionPerMillion: 0.60},
+	"together/meta-llama/Llama-3.3-70B-Instruct-Turbo": {PromptPerMillion: 0.88, CompletionPerMillion: 0.88},
+	"openai/dall-e-3": {PerImage: 0.04},
+	"together/black-forest-labs/FLUX.1-schnell": {PerImage: 0.003},
+	"stability":       {PerImage: 0.065},
+	"openai/tts-1-hd": {CharactersPerMillion: 30},
+	"elevenlabs":      {CharactersPerMillion: 300},
+}
+
+// estimateCost prices a usage event from usagePrices
+func estimateCost(event *domain.UsageEvent) float64 {
+	price, ok := usagePrices[event.Provider+"/"+event.Model]
+	if !ok {
+		price = usagePrices[event.Provider]
+	}
+	return (float64(event.PromptTokens)*price.PromptPerMillion+
+		float64(event.CompletionTokens)*price.CompletionPerMillion+
+		float64(event.Characters)*price.CharactersPerMillion)/1e6 +
+		float64(event.Images)*price.PerImage
+}
+
+// chatUsage is the token count OpenAI-compatible chat APIs return with each
+// completion
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// UsageStore persists provider usage events
+type UsageStore interface {
+	CreateUsageEvents(ctx context.Context, events []*domain.UsageEvent) error
+	SummarizeUsage(ctx context.Context, userID, period string, from, to time.Time) ([]domain.UsageTotal, error)
+}
+
+// UsageService records which user spent what at which AI provider, and
+// summarizes it for them
+type UsageService struct {
+	store  UsageStore
+	events chan *domain.UsageEvent
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewUsageService creates a usage service. Events are only written between
+// Start and Close.
+func NewUsageService(store UsageStore) *UsageService {
+	return &UsageService{
+		store:  store,
+		events: make(chan *domain.UsageEvent, usageBufferSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Record queues a usage event for writing, attributing it to the user ctx
+// carries and estimating its cost. It never blocks, and does nothing on a
+// nil UsageService so services can record without checking one is set.
+func (s *UsageService) Record(ctx context.Context, event domain.UsageEvent) {
+	if s == nil {
+		return
+	}
+	event.UserID, _ = domain.UserIDFromContext(ctx)
+	event.EstimatedCost = estimateCost(&event)
+	event.CreatedAt = time.Now()
+
+	select {
+	case s.events <- &event:
+	default:
+		log.Printf("Warning: usage buffer full, dropping %s usage event from %s", event.Service, event.Provider)
+	}
+}
+
+// recordChat records a chat completion's token usage
+func (s *UsageService) recordChat(ctx context.Context, service, provider, model string, usage chatUsage) {
+	s.Record(ctx, domain.UsageEvent{
+		Service:          service,
+		Provider:         provider,
+		Model:            model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+	})
+}
+
+// Start writes queued usage events in the background until Close
+func (s *UsageService) Start() {
+	go s.run()
+}
+
+// Close writes the events still queued and stops the background writer,
+// waiting for it until ctx is done
+func (s *UsageService) Close(ctx context.Context) error {
+	close(s.stop)
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *UsageService) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*domain.UsageEvent, 0, usageBatchSize)
+	for {
+		select {
+		case event := <-s.events:
+			batch = append(batch, event)
+			if len(batch) >= usageBatchSize {
+				batch = s.flush(batch)
+			}
+		case <-ticker.C:
+			batch = s.flush(batch)
+		case <-s.stop:
+			for {
+				select {
+				case event := <-s.events:
+					batch = append(batch, event)
+				default:
+					s.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes a batch of events, returning the emptied batch. Failed
+// writes are logged and dropped.
+func (s *UsageService) flush(batch []*domain.UsageEvent) []*domain.UsageEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), usageFlushTimeout)
+	defer cancel()
+	if err := s.store.CreateUsageEvents(ctx, batch); err != nil {
+		log.Printf("Failed to write %d usage events: %v", len(batch), err)
+	}
+	return batch[:0]
+}
+
+// UsageSummary is a user's provider usage over a date range
+type UsageSummary struct {
+	Period        string              `json:"period"`
+	From          time.Time           `json:"from"`
+	To            time.Time           `json:"to"`
+	Totals        []domain.UsageTotal `json:"totals"`
+	EstimatedCost float64             `json:"estimatedCost"`
+}
+
+// Summarize totals a user's usage in [from, to) per provider and per day,
+// week or month
+func (s *UsageService) Summarize(ctx context.Context, userID, period string, from, to time.Time) (*UsageSummary, error) {
+	if !usagePeriods[period] {
+		return nil, ErrInvalidUsagePeriod
+	}
+	if !from.Before(to) {
+		return nil, ErrInvalidUsageRange
+	}
+
+	totals, err := s.store.SummarizeUsage(ctx, userID, period, from, to)
+	if err != nil {
+		return nil, err
+	}
+	summary := &UsageSummary{Period: period, From: from, To: to, Totals: totals}
+	if summary.Totals == nil {
+		summary.Totals = []domain.UsageTotal{}
+	}
+	for _, total := range totals {
+		summary.EstimatedCost += total.EstimatedCost
+	}
+	return summary, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"renderowl-api/internal/repository"
+	socialsvc "renderowl-api/internal/service/social"
+)
+
+// UserVoices deletes a user's voice clones from the voice provider
+type UserVoices interface {
+	DeleteUserVoices(ctx context.Context, userID string) error
+}
+
+// UserDataService handles data export and erasure requests for a user
+type UserDataService struct {
+	repo          *repository.UserDataRepository
+	socialService *socialsvc.Service
+	voices        UserVoices
+}
+
+// NewUserDataService creates a new user data service
+func NewUserDataService(repo *repository.UserDataRepository, socialService *socialsvc.Service) *UserDataService {
+	return &UserDataService{
+		repo:          repo,
+		socialService: socialService,
+	}
+}
+
+// SetVoices makes erasure delete the user's voice clones from the provider,
+// not only the records of them
+func (s *UserDataService) SetVoices(voices UserVoices) {
+	s.voices = voices
+}
+
+// ExportUserData returns everything stored for a user
+func (s *UserDataService) ExportUserData(ctx context.Context, userID string) (*repository.UserDataExport, error) {
+	return s.repo.Export(ctx, userID)
+}
+
+// ExportUserDataArchive returns the user's data as a zip containing export.json
+func (s *UserDataService) ExportUserDataArchive(ctx context.Context, userID string) ([]byte, error) {
+	export, err := s.repo.Export(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("export.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DeleteUserData deletes the user's voice clones, revokes their platform
+// tokens and erases their data. A clone that can't be deleted fails the
+// erasure, so it can be retried rather than the clone being forgotten while
+// the provider still has it.
+func (s *UserDataService) DeleteUserData(ctx context.Context, userID string) error {
+	if s.voices != nil {
+		if err := s.voices.DeleteUserVoices(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete voice clones: %w", err)
+		}
+	}
+
+	accounts, err := s.socialService.GetAccounts(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load social accounts: %w", err)
+	}
+
+	// Revocation is best effort: an unreachable platform must not block erasure
+	for _, account := range accounts {
+		if err := s.socialService.RevokeAccountToken(ctx, account); err != nil {
+			log.Printf("Failed to revoke %s token for account %s: %v", account.Platform, account.ID, err)
+		}
+	}
+
+	return s.repo.Delete(ctx, userID)
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"renderowl-api/internal/domain"
+)
+
+// ErrVariationJobNotFound is returned when a variations run doesn't exist or
+// belongs to another user
+var ErrVariationJobNotFound = errors.New("variations job not found")
+
+// ErrUnknownVariationStyle is returned when regenerating in a style there
+// are no templates for
+var ErrUnknownVariationStyle = errors.New("unknown style")
+
+// VariationJobStore persists variations runs so their titles and thumbnails
+// can be regenerated
+type VariationJobStore interface {
+	CreateVariationJob(ctx context.Context, job *domain.VariationJob) error
+	GetVariationJob(ctx context.Context, userID, id string) (*domain.VariationJob, error)
+	UpdateVariationJobResult(ctx context.Context, job *domain.VariationJob) error
+}
+
+// SetJobStore enables storing variations runs for later regeneration
+func (s *VariationsService) SetJobStore(store VariationJobStore) {
+	s.jobStore = store
+}
+
+// GetVariations returns the result of one of the user's stored variations runs
+func (s *VariationsService) GetVariations(ctx context.Context, userID, jobID string) (*VariationsResult, error) {
+	_, _, result, err := s.loadJob(ctx, userID, jobID)
+	return result, err
+}
+
+// RegenerateVariationsRequest asks for fresh titles or thumbnails for a
+// stored variations run
+type RegenerateVariationsRequest struct {
+	Count int    `json:"count,omitempty" binding:"omitempty,min=1,max=10"` // defaults to the run's count
+	Style string `json:"style,omitempty"`                                  // only regenerate this style
+}
+
+// saveJob stores a variations run and sets the result's job ID. Failing to
+// store it only costs the ability to regenerate, so it's logged.
+func (s *VariationsService) saveJob(ctx context.Context, userID string, req *CreateVariationsRequest, result *VariationsResult) {
+	if s.jobStore == nil {
+		return
+	}
+	request, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Failed to encode variations request: %v", err)
+		return
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("Failed to encode variations result: %v", err)
+		return
+	}
+	job := &domain.VariationJob{UserID: userID, SourceID: req.SourceVideoID, Request: request, Result: encoded}
+	if err := s.jobStore.CreateVariationJob(ctx, job); err != nil {
+		log.Printf("Failed to store variations job for %s: %v", req.SourceVideoID, err)
+		return
+	}
+	result.JobID = job.ID
+}
+
+// RegenerateTitles generates new titles for a stored variations run and
+// merges them into its result: they replace the titles of their style, or
+// all of them when no style is given. It returns the run's updated titles.
+func (s *VariationsService) RegenerateTitles(ctx context.Context, userID, jobID string, req *RegenerateVariationsRequest) ([]TitleVariation, error) {
+	if req.Style != "" && !isTitleStyle(req.Style) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownVariationStyle, req.Style)
+	}
+	job, source, result, err := s.loadJob(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Count > 0 {
+		source.TitleCount = req.Count
+	}
+
+	titles, err := s.createTitleVariations(ctx, userID, source, req.Style)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create titles: %w", err)
+	}
+	result.Titles = append(keepOtherStyles(result.Titles, req.Style, func(t TitleVariation) string { return t.Style }), titles...)
+	sortTitles(result.Titles)
+
+	if err := s.saveResult(ctx, job, result); err != nil {
+		return nil, err
+	}
+	return result.Titles, nil
+}
+
+// RegenerateThumbnails generates new thumbnails for a stored variations run,
+// with AI backgrounds when they're set up, and merges them into its result:
+// they replace the thumbnails of their style, or all of them when no style
+// is given. It returns the run's updated thumbnails, best first.
+func (s *VariationsService) RegenerateThumbnails(ctx context.Context, userID, jobID string, req *RegenerateVariationsRequest) ([]ThumbnailVariation, error) {
+	job, source, result, err := s.loadJob(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	count := req.Count
+	if count == 0 {
+		count = source.ThumbnailCount
+	}
+
+	var thumbnails []ThumbnailVariation
+	if s.thumbnailBackgrounds != nil {
+		thumbnails, err = s.regenerateAIThumbnails(ctx, source, req.Style, count)
+	} else {
+		thumbnails, err = s.regenerateTemplateThumbnails(ctx, source, req.Style, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Thumbnails = append(keepOtherStyles(result.Thumbnails, req.Style, func(t ThumbnailVariation) string { return t.Style }), thumbnails...)
+	sort.SliceStable(result.Thumbnails, func(i, j int) bool { return result.Thumbnails[i].CTR > result.Thumbnails[j].CTR })
+	for i := range result.Thumbnails {
+		result.Thumbnails[i].Variant = string(rune('A' + i))
+	}
+
+	if err := s.saveResult(ctx, job, result); err != nil {
+		return nil, err
+	}
+	return result.Thumbnails, nil
+}
+
+// regenerateAIThumbnails generates AI thumbnails, each in a style of its own,
+// or all in the given style
+func (s *VariationsService) regenerateAIThumbnails(ctx context.Context, source *CreateVariationsRequest, style string, count int) ([]ThumbnailVariation, error) {
+	if count <= 0 {
+		count = 3
+	}
+	styles := thumbnailStyles[:min(count, len(thumbnailStyles))]
+	if style != "" {
+		styles = nil
+		for _, candidate := range thumbnailStyles {
+			if candidate.Name == style {
+				for range count {
+					styles = append(styles, candidate)
+				}
+				break
+			}
+		}
+		if styles == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownVariationStyle, style)
+		}
+	}
+	return s.generateAIThumbnails(ctx, source.SourceVideoID, source.Title, source.Keywords, styles)
+}
+
+// regenerateTemplateThumbnails renders the template thumbnails again. The
+// templates have one thumbnail per style, so a style gets just that one.
+func (s *VariationsService) regenerateTemplateThumbnails(ctx context.Context, source *CreateVariationsRequest, style string, count int) ([]ThumbnailVariation, error) {
+	if style == "" {
+		return s.CreateThumbnailVariations(ctx, source.SourceVideoID, count)
+	}
+	all, err := s.CreateThumbnailVariations(ctx, source.SourceVideoID, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	var thumbnails []ThumbnailVariation
+	for _, thumbnail := range all {
+		if thumbnail.Style == style {
+			thumbnails = append(thumbnails, thumbnail)
+		}
+	}
+	if len(thumbnails) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownVariationStyle, style)
+	}
+	return thumbnails, nil
+}
+
+// loadJob gets one of the user's variations runs with its decoded request
+// and result
+func (s *VariationsService) loadJob(ctx context.Context, userID, jobID string) (*domain.VariationJob, *CreateVariationsRequest, *VariationsResult, error) {
+	if s.jobStore == nil {
+		return nil, nil, nil, ErrVariationJobNotFound
+	}
+	job, err := s.jobStore.GetVariationJob(ctx, userID, jobID)
+	if err != nil {
+		return nil, nil, nil, ErrVariationJobNotFound
+	}
+	var source CreateVariationsRequest
+	if err := json.Unmarshal(job.Request, &source); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to decode variations request: %w", err)
+	}
+	var result VariationsResult
+	if err := json.Unmarshal(job.Result, &result); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to decode variations result: %w", err)
+	}
+	result.JobID = job.ID
+	return job, &source, &result, nil
+}
+
+// saveResult stores a variations run's result after part of it changed
+func (s *VariationsService) saveResult(ctx context.Context, job *domain.VariationJob, result *VariationsResult) error {
+	result.TotalCount = len(result.Platforms) + len(result.Shorts) + len(result.Thumbnails) + len(result.Titles)
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode variations result: %w", err)
+	}
+	job.Result = encoded
+	if err := s.jobStore.UpdateVariationJobResult(ctx, job); err != nil {
+		return fmt.Errorf("failed to save variations result: %w", err)
+	}
+	return nil
+}
+
+// keepOtherStyles returns the variations not in style, or none when style
+// is empty, since then they're all being replaced
+func keepOtherStyles[T any](variations []T, style string, styleOf func(T) string) []T {
+	var kept []T
+	if style == "" {
+		return kept
+	}
+	for _, variation := range variations {
+		if styleOf(variation) != style {
+			kept = append(kept, variation)
+		}
+	}
+	return kept
+}
+
+func isTitleStyle(style string) bool {
+	for _, tmpl := range titleTemplates {
+		if tmpl.style == style {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"image"
 	"image/color"
+	"image/jpeg"
 	"log"
 	"math"
 	"strings"
@@ -12,12 +15,27 @@ import (
 
 	"github.com/fogleman/gg"
 	"github.com/google/uuid"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+
+	socialdomain "renderowl-api/internal/domain/social"
 )
 
 // VariationsService handles content variation generation
 type VariationsService struct {
 	storage       StorageProvider
 	renderService *RenderService
+	transcriber   *TranscriptionService
+
+	winningContent WinningContentCacheStore
+	titleCritic    TitleCritic
+
+	thumbnailBackgrounds ThumbnailBackgroundGenerator
+	prober               MediaProber
+
+	jobStore VariationJobStore
 }
 
 // StorageProvider defines the interface for file storage
@@ -178,11 +196,20 @@ var PlatformSpecs = map[string]PlatformSpec{
 	},
 }
 
+// SocialPlatformFor maps a variation platform key (e.g. "instagram_reels") to
+// the social platform it is published on
+func SocialPlatformFor(platform string) socialdomain.SocialPlatform {
+	if i := strings.Index(platform, "_"); i > 0 {
+		platform = platform[:i]
+	}
+	return socialdomain.SocialPlatform(platform)
+}
+
 // CreateVariationsRequest represents a request to create variations
 type CreateVariationsRequest struct {
 	SourceVideoID string   `json:"sourceVideoId" binding:"required"`
 	SourceVideoURL string  `json:"sourceVideoUrl" binding:"required"`
-	Duration      float64  `json:"duration" binding:"required"`
+	Duration      float64  `json:"duration,omitempty"` // probed from the source when left out
 	Platforms     []string `json:"platforms,omitempty"`
 	GenerateShorts bool    `json:"generateShorts,omitempty"`
 	ShortCount    int      `json:"shortCount,omitempty"`
@@ -190,11 +217,17 @@ type CreateVariationsRequest struct {
 	ThumbnailCount int     `json:"thumbnailCount,omitempty"`
 	GenerateTitles  bool   `json:"generateTitles,omitempty"`
 	TitleCount     int     `json:"titleCount,omitempty"`
+	Title          string   `json:"title,omitempty"`          // source video title, the title topic when there are no keywords
+	Keywords       []string `json:"keywords,omitempty"`       // source video keywords, substituted into title templates
+	CritiqueTitles bool     `json:"critiqueTitles,omitempty"` // run an AI critique over the scored titles
+	Transcript     *Transcript `json:"transcript,omitempty"` // from /ai/transcribe, used to caption shorts
 }
 
 // VariationsResult contains all generated variations
 type VariationsResult struct {
+	JobID          string            `json:"jobId,omitempty"` // regenerate titles or thumbnails with it
 	SourceID       string            `json:"sourceId"`
+	Source         *MediaMetadata    `json:"source,omitempty"` // the probed source video
 	Platforms      []VideoVariation  `json:"platforms,omitempty"`
 	Shorts         []VideoVariation  `json:"shorts,omitempty"`
 	Thumbnails     []ThumbnailVariation `json:"thumbnails,omitempty"`
@@ -226,6 +259,7 @@ type TitleVariation struct {
 	Score       float64 `json:"score"` // Quality score
 	Keywords    []string `json:"keywords,omitempty"`
 	PredictedViews int  `json:"predictedViews,omitempty"`
+	Reason      string  `json:"reason,omitempty"` // why the title scored as it did
 }
 
 // ShortSegment represents a segment for a short video
@@ -245,9 +279,14 @@ func NewVariationsService(storage StorageProvider) *VariationsService {
 }
 
 // CreateVariations creates all requested variations
-func (s *VariationsService) CreateVariations(ctx context.Context, req *CreateVariationsRequest) (*VariationsResult, error) {
+func (s *VariationsService) CreateVariations(ctx context.Context, userID string, req *CreateVariationsRequest) (*VariationsResult, error) {
+	source, err := s.preflightSource(ctx, req)
+	if err != nil {
+		return nil, err
+	}
 	result := &VariationsResult{
 		SourceID: req.SourceVideoID,
+		Source:   source,
 	}
 
 	var wg sync.WaitGroup
@@ -307,7 +346,7 @@ func (s *VariationsService) CreateVariations(ctx context.Context, req *CreateVar
 
 	// Generate titles
 	if req.GenerateTitles {
-		titles, err := s.CreateTitleVariations(ctx, req.SourceVideoID, req.TitleCount)
+		titles, err := s.CreateTitleVariations(ctx, userID, req)
 		if err != nil {
 			log.Printf("Failed to create titles: %v", err)
 		} else {
@@ -325,6 +364,7 @@ func (s *VariationsService) CreateVariations(ctx context.Context, req *CreateVar
 		log.Printf("Variation error: %v", err)
 	}
 
+	s.saveJob(ctx, userID, req, result)
 	return result, nil
 }
 
@@ -404,6 +444,9 @@ func (s *VariationsService) CreateShortVariations(ctx context.Context, req *Crea
 				"captions":   true,
 			},
 		}
+		if req.Transcript != nil {
+			variation.Settings["captionCues"] = BuildCaptionCues(req.Transcript.Between(segment.StartTime, segment.EndTime), CaptionOptions{})
+		}
 
 		// Process short
 		outputURL, err := s.processShort(ctx, req.SourceVideoURL, segment, spec)
@@ -471,7 +514,7 @@ func (s *VariationsService) CreateThumbnailVariations(ctx context.Context, sourc
 
 	// Generate actual thumbnail images
 	for i := range variations {
-		thumbnailData, err := s.generateThumbnailImage(&variations[i])
+		thumbnailData, err := s.generateThumbnailImage(&variations[i], nil)
 		if err != nil {
 			log.Printf("Failed to generate thumbnail %s: %v", variations[i].Variant, err)
 			continue
@@ -491,46 +534,6 @@ func (s *VariationsService) CreateThumbnailVariations(ctx context.Context, sourc
 	return variations, nil
 }
 
-// CreateTitleVariations generates title A/B test variations
-func (s *VariationsService) CreateTitleVariations(ctx context.Context, sourceID string, count int) ([]TitleVariation, error) {
-	if count == 0 {
-		count = 5
-	}
-
-	templates := []struct {
-		style string
-		title string
-		score float64
-	}{
-		{"question", "Is This the Future of [Topic]?", 9.2},
-		{"list", "7 [Topic] Secrets Experts Don't Want You to Know", 8.8},
-		{"how-to", "How to Master [Topic] in 30 Days", 8.5},
-		{"controversy", "Why Everything You Know About [Topic] is Wrong", 9.5},
-		{"urgency", "Stop Doing [Topic] Wrong (Do This Instead)", 8.3},
-		{"story", "I Tried [Topic] for 30 Days. Here's What Happened", 8.7},
-	}
-
-	var variations []TitleVariation
-	for i, tmpl := range templates {
-		if i >= count {
-			break
-		}
-
-		variation := TitleVariation{
-			ID:       uuid.New().String(),
-			SourceID: sourceID,
-			Title:    tmpl.title,
-			Style:    tmpl.style,
-			Score:    tmpl.score,
-			Keywords: []string{"viral", "engaging", tmpl.style},
-			PredictedViews: int(tmpl.score * 10000),
-		}
-		variations = append(variations, variation)
-	}
-
-	return variations, nil
-}
-
 // analyzeVideoForShorts analyzes a video to find the best short segments
 func (s *VariationsService) analyzeVideoForShorts(ctx context.Context, videoURL string, duration float64, count int) ([]ShortSegment, error) {
 	// This would use video analysis to find:
@@ -575,7 +578,7 @@ func (s *VariationsService) processShort(ctx context.Context, sourceURL string,
 	// This would use ffmpeg to:
 	// - Extract segment
 	// - Crop to 9:16
-	// - Add captions
+	// - Burn in Settings["captionCues"]
 	// - Add hook overlay
 	// - Optimize for mobile
 
@@ -583,60 +586,90 @@ func (s *VariationsService) processShort(ctx context.Context, sourceURL string,
 	return fmt.Sprintf("%s_short_%.0f_%.0f.mp4", sourceURL, segment.StartTime, segment.EndTime), nil
 }
 
-// generateThumbnailImage generates a thumbnail image
-func (s *VariationsService) generateThumbnailImage(variation *ThumbnailVariation) ([]byte, error) {
-	// Create canvas
-	dc := gg.NewContext(variation.Width, variation.Height)
-
-	// Fill background with gradient
-	grad := gg.NewLinearGradient(0, 0, float64(variation.Width), float64(variation.Height))
-	grad.AddColorStop(0, color.RGBA{255, 100, 100, 255})
-	grad.AddColorStop(1, color.RGBA{100, 100, 255, 255})
-	dc.SetFillStyle(grad)
-	dc.DrawRectangle(0, 0, float64(variation.Width), float64(variation.Height))
-	dc.Fill()
+// generateThumbnailImage renders a thumbnail as a JPEG: the background image,
+// cropped to fill the frame, or a gradient when there is none, with the text
+// overlay drawn in outlined bold type over a darkened band
+func (s *VariationsService) generateThumbnailImage(variation *ThumbnailVariation, background image.Image) ([]byte, error) {
+	width, height := float64(variation.Width), float64(variation.Height)
+	canvas := image.NewRGBA(image.Rect(0, 0, variation.Width, variation.Height))
+	dc := gg.NewContextForRGBA(canvas)
+
+	if background != nil {
+		xdraw.CatmullRom.Scale(canvas, canvas.Bounds(), background, coverCrop(background.Bounds(), variation.Width, variation.Height), xdraw.Src, nil)
+	} else {
+		grad := gg.NewLinearGradient(0, 0, width, height)
+		grad.AddColorStop(0, color.RGBA{255, 100, 100, 255})
+		grad.AddColorStop(1, color.RGBA{100, 100, 255, 255})
+		dc.SetFillStyle(grad)
+		dc.DrawRectangle(0, 0, width, height)
+		dc.Fill()
+	}
 
-	// Add text overlay
-	dc.SetRGB(1, 1, 1)
-	dc.LoadFontFace("/System/Library/Fonts/Helvetica.ttc", 48)
-	
 	text := variation.TextOverlay
 	if text == "" {
 		text = "CLICK HERE"
 	}
-	
-	// Center text
-	w, h := dc.MeasureString(text)
-	x := (float64(variation.Width) - w) / 2
-	y := (float64(variation.Height) + h) / 2
-	
-	// Draw text shadow
+
+	face, err := thumbnailFontFace(height / 8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load thumbnail font: %w", err)
+	}
+	dc.SetFontFace(face)
+
+	// Text sits in the lower third, over a band dark enough to read on any image
+	textWidth := width * 0.85
+	lines := dc.WordWrap(text, textWidth)
+	lineHeight := dc.FontHeight() * 1.2
+	blockHeight := lineHeight * float64(len(lines))
+	centerY := height * 0.72
+	dc.SetRGBA(0, 0, 0, 0.45)
+	dc.DrawRectangle(0, centerY-blockHeight/2-lineHeight*0.3, width, blockHeight+lineHeight*0.6)
+	dc.Fill()
+
+	// Outline, then fill
+	outline := math.Max(2, height/180)
 	dc.SetRGB(0, 0, 0)
-	dc.DrawString(text, x+2, y+2)
-	
-	// Draw text
-	dc.SetRGB(1, 1, 1)
-	dc.DrawString(text, x, y)
-
-	// Add border
-	dc.SetRGB(1, 1, 1)
-	dc.SetLineWidth(10)
-	dc.DrawRectangle(10, 10, float64(variation.Width-20), float64(variation.Height-20))
-	dc.Stroke()
-
-	// Encode to JPEG
-	img := dc.Image()
-	
-	var buf strings.Builder
-	// Use image/jpeg to encode
-	_ = make([]byte, 0)
-	
-	// Simple encoding - in production use proper JPEG encoding
-	_ = img
-	_ = buf
-	
-	// Return placeholder data
-	return []byte("thumbnail_data_placeholder"), nil
+	for dx := -outline; dx <= outline; dx += outline {
+		for dy := -outline; dy <= outline; dy += outline {
+			dc.DrawStringWrapped(text, width/2+dx, centerY+dy, 0.5, 0.5, textWidth, 1.2, gg.AlignCenter)
+		}
+	}
+	dc.SetRGB(1, 1, 0.2)
+	dc.DrawStringWrapped(text, width/2, centerY, 0.5, 0.5, textWidth, 1.2, gg.AlignCenter)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// thumbnailFont is the typeface thumbnail text is set in, embedded so
+// rendering doesn't depend on the fonts installed on the host
+var thumbnailFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(gobold.TTF)
+})
+
+func thumbnailFontFace(size float64) (font.Face, error) {
+	f, err := thumbnailFont()
+	if err != nil {
+		return nil, err
+	}
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+// coverCrop returns the largest centered part of bounds with the aspect ratio
+// of width x height, so scaling it fills the frame without distortion
+func coverCrop(bounds image.Rectangle, width, height int) image.Rectangle {
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW*height > srcH*width {
+		cropW := srcH * width / height
+		x := bounds.Min.X + (srcW-cropW)/2
+		return image.Rect(x, bounds.Min.Y, x+cropW, bounds.Max.Y)
+	}
+	cropH := srcW * height / width
+	y := bounds.Min.Y + (srcH-cropH)/2
+	return image.Rect(bounds.Min.X, y, bounds.Max.X, y+cropH)
 }
 
 // generateHookForSegment generates a hook text for a short segment
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"renderowl-api/internal/domain"
+)
+
+// Voice cloning errors
+var (
+	ErrVoiceNotFound          = errors.New("voice not found")
+	ErrVoiceCloningDisabled   = errors.New("voice cloning is not configured")
+	ErrVoiceSamplesRequired   = errors.New("at least one audio sample is required")
+	ErrVoiceCloneNameRequired = errors.New("a voice name is required")
+)
+
+// clonedCategory is the ElevenLabs category of voice clones
+const clonedCategory = "cloned"
+
+// ClonedVoiceStore persists which user owns each voice clone
+type ClonedVoiceStore interface {
+	CreateClonedVoice(ctx context.Context, voice *domain.ClonedVoice) error
+	ListClonedVoices(ctx context.Context) ([]*domain.ClonedVoice, error)
+	ListUserClonedVoices(ctx context.Context, userID string) ([]*domain.ClonedVoice, error)
+	GetClonedVoice(ctx context.Context, userID, voiceID string) (*domain.ClonedVoice, error)
+	DeleteClonedVoice(ctx context.Context, id string) error
+}
+
+// VoiceSample is an audio recording a voice is cloned from
+type VoiceSample struct {
+	Filename string
+	Data     []byte
+}
+
+// SetVoiceStore enables voice cloning, recording each clone's owner so
+// users only see and delete their own clones
+func (s *TTSService) SetVoiceStore(store ClonedVoiceStore) {
+	s.voiceStore = store
+}
+
+// ListUserVoices returns the voices a user can narrate with: the providers'
+// stock voices and the user's own clones, marked as cloned. Clones made by
+// other users are left out. With clonedOnly set only the user's clones are
+// returned.
+func (s *TTSService) ListUserVoices(ctx context.Context, userID string, clonedOnly bool) ([]Voice, error) {
+	voices, err := s.ListVoices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.voiceStore == nil {
+		if clonedOnly {
+			return []Voice{}, nil
+		}
+		return voices, nil
+	}
+
+	clones, err := s.voiceStore.ListClonedVoices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cloned voices: %w", err)
+	}
+	owners := make(map[string]string, len(clones))
+	for _, clone := range clones {
+		owners[clone.VoiceID] = clone.UserID
+	}
+
+	filtered := make([]Voice, 0, len(voices))
+	for _, voice := range voices {
+		owner, recorded := owners[voice.ID]
+		switch {
+		case recorded && owner == userID:
+			voice.Cloned = true
+		case recorded || voice.Category == clonedCategory:
+			continue
+		}
+		if clonedOnly && !voice.Cloned {
+			continue
+		}
+		filtered = append(filtered, voice)
+	}
+	return filtered, nil
+}
+
+// CloneVoice creates an ElevenLabs voice clone from audio samples and records
+// the user as its owner
+func (s *TTSService) CloneVoice(ctx context.Context, userID, name, description string, samples []VoiceSample) (*Voice, error) {
+	return withTimeout(ctx, s.timeouts, AIOpVoiceClone, func(ctx context.Context) (*Voice, error) {
+		return s.cloneVoice(ctx, userID, name, description, samples)
+	})
+}
+
+// cloneVoice uploads the samples to ElevenLabs and records the new voice
+func (s *TTSService) cloneVoice(ctx context.Context, userID, name, description string, samples []VoiceSample) (*Voice, error) {
+	if s.elevenLabsKey == "" || s.voiceStore == nil {
+		return nil, ErrVoiceCloningDisabled
+	}
+	if name == "" {
+		return nil, ErrVoiceCloneNameRequired
+	}
+	if len(samples) == 0 {
+		return nil, ErrVoiceSamplesRequired
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("name", name)
+	if description != "" {
+		writer.WriteField("description", description)
+	}
+	for _, sample := range samples {
+		part, err := writer.CreateFormFile("files", sample.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add sample: %w", err)
+		}
+		if _, err := part.Write(sample.Data); err != nil {
+			return nil, fmt.Errorf("failed to add sample: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.elevenLabsBaseURL+"/voices/add", body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("xi-api-key", s.elevenLabsKey)
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("elevenlabs error: %s", string(respBody))
+	}
+
+	var result struct {
+		VoiceID string `json:"voice_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	clone := &domain.ClonedVoice{
+		UserID:   userID,
+		VoiceID:  result.VoiceID,
+		Provider: string(ProviderElevenLabs),
+		Name:     name,
+	}
+	if err := s.voiceStore.CreateClonedVoice(ctx, clone); err != nil {
+		return nil, fmt.Errorf("failed to record cloned voice: %w", err)
+	}
+
+	return &Voice{
+		ID:          result.VoiceID,
+		Name:        name,
+		Provider:    ProviderElevenLabs,
+		Category:    clonedCategory,
+		Description: description,
+		Cloned:      true,
+	}, nil
+}
+
+// DeleteVoice deletes one of the user's voice clones from ElevenLabs.
+// Voices the user didn't clone are reported as not found.
+func (s *TTSService) DeleteVoice(ctx context.Context, userID, voiceID string) error {
+	if s.elevenLabsKey == "" || s.voiceStore == nil {
+		return ErrVoiceCloningDisabled
+	}
+	clone, err := s.voiceStore.GetClonedVoice(ctx, userID, voiceID)
+	if err != nil {
+		return ErrVoiceNotFound
+	}
+
+	if err := s.deleteElevenLabsVoice(ctx, voiceID); err != nil {
+		return err
+	}
+
+	if err := s.voiceStore.DeleteClonedVoice(ctx, clone.ID); err != nil {
+		return fmt.Errorf("failed to forget cloned voice: %w", err)
+	}
+	return nil
+}
+
+// DeleteUserVoices deletes all of a user's voice clones from ElevenLabs, for
+// erasing the user's data. It carries on past a failed deletion and reports
+// every failure. The clones' records are left for the erasure to remove.
+func (s *TTSService) DeleteUserVoices(ctx context.Context, userID string) error {
+	if s.voiceStore == nil {
+		return nil
+	}
+	clones, err := s.voiceStore.ListUserClonedVoices(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list cloned voices: %w", err)
+	}
+	if len(clones) > 0 && s.elevenLabsKey == "" {
+		return ErrVoiceCloningDisabled
+	}
+
+	var errs []error
+	for _, clone := range clones {
+		if err := s.deleteElevenLabsVoice(ctx, clone.VoiceID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete voice %s: %w", clone.VoiceID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deleteElevenLabsVoice deletes a voice from ElevenLabs. A voice that is
+// already gone counts as deleted.
+func (s *TTSService) deleteElevenLabsVoice(ctx context.Context, voiceID string) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", s.elevenLabsBaseURL+"/voices/"+url.PathEscape(voiceID), nil)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("xi-api-key", s.elevenLabsKey)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("elevenlabs error: %s", string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"renderowl-api/internal/domain"
+)
+
+// fakeVoiceStore keeps voice clone records in memory
+type fakeVoiceStore struct {
+	clones []*domain.ClonedVoice
+}
+
+func (f *fakeVoiceStore) CreateClonedVoice(ctx context.Context, voice *domain.ClonedVoice) error {
+	f.clones = append(f.clones, voice)
+	return nil
+}
+
+func (f *fakeVoiceStore) ListClonedVoices(ctx context.Context) ([]*domain.ClonedVoice, error) {
+	return f.clones, nil
+}
+
+func (f *fakeVoiceStore) ListUserClonedVoices(ctx context.Context, userID string) ([]*domain.ClonedVoice, error) {
+	var clones []*domain.ClonedVoice
+	for _, clone := range f.clones {
+		if clone.UserID == userID {
+			clones = append(clones, clone)
+		}
+	}
+	return clones, nil
+}
+
+func (f *fakeVoiceStore) GetClonedVoice(ctx context.Context, userID, voiceID string) (*domain.ClonedVoice, error) {
+	for _, clone := range f.clones {
+		if clone.UserID == userID && clone.VoiceID == voiceID {
+			return clone, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (f *fakeVoiceStore) DeleteClonedVoice(ctx context.Context, id string) error {
+	f.clones = slices.DeleteFunc(f.clones, func(clone *domain.ClonedVoice) bool { return clone.ID == id })
+	return nil
+}
+
+func TestDeleteUserVoicesDeletesOnlyTheUsersClones(t *testing.T) {
+	var deleted []string
+	elevenLabs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.Header.Get("xi-api-key") != "key" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		deleted = append(deleted, r.URL.Path)
+		if r.URL.Path == "/voices/gone" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer elevenLabs.Close()
+
+	s := &TTSService{elevenLabsKey: "key", elevenLabsBaseURL: elevenLabs.URL, httpClient: http.DefaultClient}
+	s.SetVoiceStore(&fakeVoiceStore{clones: []*domain.ClonedVoice{
+		{ID: "1", UserID: "user-a", VoiceID: "voice-a"},
+		{ID: "2", UserID: "user-a", VoiceID: "gone"},
+		{ID: "3", UserID: "user-b", VoiceID: "voice-b"},
+	}})
+
+	if err := s.DeleteUserVoices(context.Background(), "user-a"); err != nil {
+		t.Fatalf("DeleteUserVoices: %v", err)
+	}
+	if want := []string{"/voices/voice-a", "/voices/gone"}; !slices.Equal(deleted, want) {
+		t.Errorf("deleted %v, want %v", deleted, want)
+	}
+}
+
+func TestDeleteUserVoicesReportsProviderFailures(t *testing.T) {
+	elevenLabs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer elevenLabs.Close()
+
+	s := &TTSService{elevenLabsKey: "key", elevenLabsBaseURL: elevenLabs.URL, httpClient: http.DefaultClient}
+	s.SetVoiceStore(&fakeVoiceStore{clones: []*domain.ClonedVoice{{ID: "1", UserID: "user-a", VoiceID: "voice-a"}}})
+	if err := s.DeleteUserVoices(context.Background(), "user-a"); err == nil {
+		t.Error("DeleteUserVoices succeeded with ElevenLabs down")
+	}
+
+	s.elevenLabsKey = ""
+	if err := s.DeleteUserVoices(context.Background(), "user-a"); !errors.Is(err, ErrVoiceCloningDisabled) {
+		t.Errorf("DeleteUserVoices without a key = %v, want ErrVoiceCloningDisabled", err)
+	}
+}
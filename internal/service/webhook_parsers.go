@@ -0,0 +1,189 @@
+package service
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"renderowl-api/internal/domain"
+)
+
+// WebhookUpdate is a normalized view or engagement update extracted from a
+// webhook event. A stats update only names a video whose statistics have to
+// be fetched from the platform.
+type WebhookUpdate struct {
+	Kind     string // view, engagement, stats
+	VideoID  string
+	Views    int64
+	Likes    int64
+	Comments int64
+	Shares   int64
+}
+
+// webhookParser converts a platform-specific webhook payload into updates
+type webhookParser func(event *domain.WebhookEvent) ([]*WebhookUpdate, error)
+
+// webhookParsers maps platforms with a native webhook format to their parser.
+// Events from other platforms use the generic view/engagement payload.
+var webhookParsers = map[string]webhookParser{
+	"youtube": parseYouTubeWebhook,
+	"tiktok":  parseTikTokWebhook,
+}
+
+// youtubeAtomFeed is the Atom document YouTube pushes via PubSubHubbub
+type youtubeAtomFeed struct {
+	XMLName xml.Name `xml:"feed"`
+	Entries []struct {
+		VideoID   string `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+		ChannelID string `xml:"http://www.youtube.com/xml/schemas/2015 channelId"`
+		Title     string `xml:"title"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+		Group     struct {
+			Community struct {
+				StarRating struct {
+					Count int64 `xml:"count,attr"`
+				} `xml:"starRating"`
+				Statistics struct {
+					Views int64 `xml:"views,attr"`
+				} `xml:"statistics"`
+			} `xml:"community"`
+		} `xml:"http://search.yahoo.com/mrss/ group"`
+	} `xml:"entry"`
+	Deleted []struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"http://purl.org/atompub/tombstones/1.0 deleted-entry"`
+}
+
+// parseYouTubeWebhook parses a PubSubHubbub Atom notification. Push
+// notifications of published or updated videos carry only the video and
+// channel IDs, so their entries become stats updates; entries that include
+// the media:community block of the channel feed are read directly. Deleted
+// videos produce no updates.
+func parseYouTubeWebhook(event *domain.WebhookEvent) ([]*WebhookUpdate, error) {
+	atom, ok := event.Payload["atom"].(string)
+	if !ok {
+		return nil, fmt.Errorf("youtube webhook payload has no atom document")
+	}
+
+	var feed youtubeAtomFeed
+	if err := xml.Unmarshal([]byte(atom), &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse youtube atom feed: %w", err)
+	}
+
+	var updates []*WebhookUpdate
+	for _, entry := range feed.Entries {
+		if entry.VideoID == "" {
+			continue
+		}
+
+		stats := entry.Group.Community
+		if stats.Statistics.Views == 0 && stats.StarRating.Count == 0 {
+			updates = append(updates, &WebhookUpdate{
+				Kind:    "stats",
+				VideoID: entry.VideoID,
+			})
+			continue
+		}
+		if stats.Statistics.Views > 0 {
+			updates = append(updates, &WebhookUpdate{
+				Kind:    "view",
+				VideoID: entry.VideoID,
+				Views:   stats.Statistics.Views,
+			})
+		}
+		if stats.StarRating.Count > 0 {
+			updates = append(updates, &WebhookUpdate{
+				Kind:    "engagement",
+				VideoID: entry.VideoID,
+				Likes:   stats.StarRating.Count,
+			})
+		}
+	}
+
+	return updates, nil
+}
+
+// tiktokWebhookBody is the envelope TikTok posts for webhook events
+type tiktokWebhookBody struct {
+	ClientKey  string `json:"client_key"`
+	Event      string `json:"event"`
+	CreateTime int64  `json:"create_time"`
+	UserOpenID string `json:"user_openid"`
+	Content    string `json:"content"` // JSON-encoded event content
+}
+
+// tiktokWebhookContent holds the fields of the decoded content we act on
+type tiktokWebhookContent struct {
+	VideoID      string `json:"video_id"`
+	ShareID      string `json:"share_id"`
+	PublishID    string `json:"publish_id"`
+	ViewCount    int64  `json:"view_count"`
+	LikeCount    int64  `json:"like_count"`
+	CommentCount int64  `json:"comment_count"`
+	ShareCount   int64  `json:"share_count"`
+}
+
+// parseTikTokWebhook parses a TikTok webhook body. Lifecycle events such as
+// video.publish.complete identify the video; metric fields, when present in
+// the content, become view and engagement updates.
+func parseTikTokWebhook(event *domain.WebhookEvent) ([]*WebhookUpdate, error) {
+	raw, err := json.Marshal(event.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var body tiktokWebhookBody
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse tiktok webhook: %w", err)
+	}
+
+	var content tiktokWebhookContent
+	if body.Content != "" {
+		if err := json.Unmarshal([]byte(body.Content), &content); err != nil {
+			return nil, fmt.Errorf("failed to parse tiktok webhook content: %w", err)
+		}
+	}
+
+	videoID := content.VideoID
+	if videoID == "" {
+		videoID = tiktokVideoIDFromShareID(content.ShareID)
+	}
+	if videoID == "" {
+		videoID = event.VideoID
+	}
+	if videoID == "" {
+		return nil, nil
+	}
+
+	var updates []*WebhookUpdate
+	if content.ViewCount > 0 {
+		updates = append(updates, &WebhookUpdate{
+			Kind:    "view",
+			VideoID: videoID,
+			Views:   content.ViewCount,
+		})
+	}
+	if content.LikeCount > 0 || content.CommentCount > 0 || content.ShareCount > 0 {
+		updates = append(updates, &WebhookUpdate{
+			Kind:     "engagement",
+			VideoID:  videoID,
+			Likes:    content.LikeCount,
+			Comments: content.CommentCount,
+			Shares:   content.ShareCount,
+		})
+	}
+
+	return updates, nil
+}
+
+// tiktokVideoIDFromShareID extracts the numeric video ID from a share ID like
+// "video.6974245311675353080.VDCxrcMJ"
+func tiktokVideoIDFromShareID(shareID string) string {
+	parts := strings.Split(shareID, ".")
+	if len(parts) >= 2 && parts[0] == "video" {
+		return parts[1]
+	}
+	return ""
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"renderowl-api/internal/domain"
+)
+
+// youtubePush is a PubSubHubbub notification as YouTube sends it for a new
+// or updated video
+const youtubePush = `<?xml version='1.0' encoding='UTF-8'?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">
+  <link rel="hub" href="https://pubsubhubbub.appspot.com"/>
+  <link rel="self" href="https://www.youtube.com/xml/feeds/videos.xml?channel_id=UCXuqSBlHAE6Xw-yeJA0Tunw"/>
+  <title>YouTube video feed</title>
+  <updated>2024-03-09T19:05:24.552394234+00:00</updated>
+  <entry>
+    <id>yt:video:dQw4w9WgXcQ</id>
+    <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+    <yt:channelId>UCXuqSBlHAE6Xw-yeJA0Tunw</yt:channelId>
+    <title>Video title</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+    <author>
+      <name>Channel title</name>
+      <uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri>
+    </author>
+    <published>2024-03-06T21:40:57+00:00</published>
+    <updated>2024-03-09T19:05:24.552394234+00:00</updated>
+  </entry>
+</feed>`
+
+// youtubeDeletion is the notification YouTube sends for a deleted video
+const youtubeDeletion = `<?xml version='1.0' encoding='UTF-8'?>
+<feed xmlns:at="http://purl.org/atompub/tombstones/1.0" xmlns="http://www.w3.org/2005/Atom">
+  <at:deleted-entry ref="yt:video:dQw4w9WgXcQ" when="2024-03-09T19:05:24.552394234+00:00">
+    <link href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+    <at:by>
+      <name>Channel title</name>
+      <uri>https://www.youtube.com/channel/UCXuqSBlHAE6Xw-yeJA0Tunw</uri>
+    </at:by>
+  </at:deleted-entry>
+</feed>`
+
+// youtubeVideosResponse is a YouTube Data API videos.list response with
+// statistics; counts are strings and a hidden like count is left out
+const youtubeVideosResponse = `{
+  "kind": "youtube#videoListResponse",
+  "etag": "etag",
+  "items": [
+    {
+      "kind": "youtube#video",
+      "etag": "etag",
+      "id": "dQw4w9WgXcQ",
+      "statistics": {"viewCount": "1523", "likeCount": "87", "favoriteCount": "0", "commentCount": "12"}
+    },
+    {
+      "kind": "youtube#video",
+      "etag": "etag",
+      "id": "hiddenLikes",
+      "statistics": {"viewCount": "40", "favoriteCount": "0", "commentCount": "3"}
+    }
+  ],
+  "pageInfo": {"totalResults": 2, "resultsPerPage": 2}
+}`
+
+// fakeVideoStats returns fixed statistics, or fails
+type fakeVideoStats struct {
+	stats map[string]VideoStats
+	err   error
+	asked [][]string
+}
+
+func (f *fakeVideoStats) VideoStats(ctx context.Context, videoIDs []string) (map[string]VideoStats, error) {
+	f.asked = append(f.asked, videoIDs)
+	return f.stats, f.err
+}
+
+func youtubeEvent(atom string) *domain.WebhookEvent {
+	return &domain.WebhookEvent{ID: "event-1", Platform: "youtube", Payload: domain.JSON{"atom": atom}}
+}
+
+func TestYouTubePushFetchesStatistics(t *testing.T) {
+	updates, err := parseYouTubeWebhook(youtubeEvent(youtubePush))
+	if err != nil {
+		t.Fatalf("parseYouTubeWebhook: %v", err)
+	}
+	if len(updates) != 1 || updates[0].Kind != "stats" || updates[0].VideoID != "dQw4w9WgXcQ" {
+		t.Fatalf("updates = %+v, want one stats update for dQw4w9WgXcQ", updates)
+	}
+
+	stats := &fakeVideoStats{stats: map[string]VideoStats{"dQw4w9WgXcQ": {Views: 1523, Likes: 87, Comments: 12}}}
+	s := &AnalyticsService{}
+	s.SetVideoStatsSource("youtube", stats)
+	updates, retry, err := s.webhookUpdates(context.Background(), youtubeEvent(youtubePush), parseYouTubeWebhook)
+	if err != nil || retry {
+		t.Fatalf("webhookUpdates: %v (retry %v)", err, retry)
+	}
+	if len(stats.asked) != 1 || len(stats.asked[0]) != 1 || stats.asked[0][0] != "dQw4w9WgXcQ" {
+		t.Errorf("fetched statistics of %v, want [[dQw4w9WgXcQ]]", stats.asked)
+	}
+	want := []WebhookUpdate{
+		{Kind: "view", VideoID: "dQw4w9WgXcQ", Views: 1523},
+		{Kind: "engagement", VideoID: "dQw4w9WgXcQ", Likes: 87, Comments: 12},
+	}
+	if len(updates) != len(want) {
+		t.Fatalf("updates = %+v, want %+v", updates, want)
+	}
+	for i := range want {
+		if *updates[i] != want[i] {
+			t.Errorf("update %d = %+v, want %+v", i, *updates[i], want[i])
+		}
+	}
+}
+
+func TestYouTubeDeletionHasNoUpdates(t *testing.T) {
+	updates, err := parseYouTubeWebhook(youtubeEvent(youtubeDeletion))
+	if err != nil {
+		t.Fatalf("parseYouTubeWebhook: %v", err)
+	}
+	if len(updates) != 0 {
+		t.Errorf("updates = %+v, want none", updates)
+	}
+}
+
+func TestWebhookFailuresAreClassified(t *testing.T) {
+	s := &AnalyticsService{}
+	s.SetVideoStatsSource("youtube", &fakeVideoStats{err: errors.New("quota exceeded")})
+
+	// A payload that doesn't parse won't parse on the next run either
+	_, retry, err := s.webhookUpdates(context.Background(), youtubeEvent("<feed><entry>"), parseYouTubeWebhook)
+	if err == nil || retry {
+		t.Errorf("malformed payload: err %v, retry %v; want an error not worth retrying", err, retry)
+	}
+	_, retry, err = s.webhookUpdates(context.Background(), &domain.WebhookEvent{Platform: "youtube", Payload: domain.JSON{}}, parseYouTubeWebhook)
+	if err == nil || retry {
+		t.Errorf("payload without atom: err %v, retry %v; want an error not worth retrying", err, retry)
+	}
+
+	// A failed fetch is
+	_, retry, err = s.webhookUpdates(context.Background(), youtubeEvent(youtubePush), parseYouTubeWebhook)
+	if err == nil || !retry {
+		t.Errorf("failed fetch: err %v, retry %v; want an error worth retrying", err, retry)
+	}
+}
+
+func TestYouTubeStatsFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/videos" || query.Get("part") != "statistics" || query.Get("key") != "key" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if ids := query.Get("id"); ids != "dQw4w9WgXcQ,hiddenLikes,deleted" {
+			t.Errorf("id = %q", ids)
+		}
+		w.Write([]byte(youtubeVideosResponse))
+	}))
+	defer server.Close()
+
+	fetcher := NewYouTubeStatsFetcher("key")
+	fetcher.baseURL = server.URL
+	stats, err := fetcher.VideoStats(context.Background(), []string{"dQw4w9WgXcQ", "hiddenLikes", "deleted"})
+	if err != nil {
+		t.Fatalf("VideoStats: %v", err)
+	}
+	want := map[string]VideoStats{
+		"dQw4w9WgXcQ": {Views: 1523, Likes: 87, Comments: 12},
+		"hiddenLikes": {Views: 40, Comments: 3},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("stats = %+v, want %+v", stats, want)
+	}
+	for id, videoStats := range want {
+		if stats[id] != videoStats {
+			t.Errorf("stats[%s] = %+v, want %+v", id, stats[id], videoStats)
+		}
+	}
+}
+
+func TestTikTokWebhook(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []WebhookUpdate
+	}{
+		{
+			name: "publish complete carries no metrics",
+			body: `{"client_key":"bwo2m45353a6k85","event":"video.publish.complete","create_time":1615338610,` +
+				`"user_openid":"act.example12345Example12345Example","content":"{\"share_id\":\"video.6974245311675353080.VDCxrcMJ\",\"publish_type\":\"DIRECT_PUBLISH\"}"}`,
+		},
+		{
+			name: "metrics in the content",
+			body: `{"client_key":"bwo2m45353a6k85","event":"video.metrics.update","create_time":1615338610,` +
+				`"user_openid":"act.example12345Example12345Example","content":"{\"share_id\":\"video.6974245311675353080.VDCxrcMJ\",` +
+				`\"view_count\":2048,\"like_count\":300,\"comment_count\":25,\"share_count\":9}"}`,
+			want: []WebhookUpdate{
+				{Kind: "view", VideoID: "6974245311675353080", Views: 2048},
+				{Kind: "engagement", VideoID: "6974245311675353080", Likes: 300, Comments: 25, Shares: 9},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload domain.JSON
+			if err := json.Unmarshal([]byte(tt.body), &payload); err != nil {
+				t.Fatal(err)
+			}
+			updates, err := parseTikTokWebhook(&domain.WebhookEvent{Platform: "tiktok", Payload: payload})
+			if err != nil {
+				t.Fatalf("parseTikTokWebhook: %v", err)
+			}
+			if len(updates) != len(tt.want) {
+				t.Fatalf("updates = %+v, want %+v", updates, tt.want)
+			}
+			for i := range tt.want {
+				if *updates[i] != tt.want[i] {
+					t.Errorf("update %d = %+v, want %+v", i, *updates[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	_, err := parseTikTokWebhook(&domain.WebhookEvent{Platform: "tiktok", Payload: domain.JSON{"content": "{not json"}})
+	if err == nil {
+		t.Error("parseTikTokWebhook accepted malformed content")
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// youtubeVideosPerRequest is the most video IDs the YouTube Data API takes
+// in one videos.list call
+const youtubeVideosPerRequest = 50
+
+// VideoStats are a video's lifetime counts as its platform reports them
+type VideoStats struct {
+	Views    int64
+	Likes    int64
+	Comments int64
+}
+
+// VideoStatsSource fetches the current statistics of videos on a platform,
+// for webhooks that only say which videos changed
+type VideoStatsSource interface {
+	VideoStats(ctx context.Context, videoIDs []string) (map[string]VideoStats, error)
+}
+
+// SetVideoStatsSource lets webhook events from a platform that carry no
+// statistics be completed by fetching them from source
+func (s *AnalyticsService) SetVideoStatsSource(platform string, source VideoStatsSource) {
+	if s.statsSources == nil {
+		s.statsSources = make(map[string]VideoStatsSource)
+	}
+	s.statsSources[platform] = source
+}
+
+// YouTubeStatsFetcher fetches public video statistics through the YouTube
+// Data API
+type YouTubeStatsFetcher struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewYouTubeStatsFetcher creates a new YouTube statistics fetcher
+func NewYouTubeStatsFetcher(apiKey string) *YouTubeStatsFetcher {
+	return &YouTubeStatsFetcher{
+		apiKey:     apiKey,
+		baseURL:    "https://www.googleapis.com/youtube/v3",
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// VideoStats returns the view, like and comment counts of videos by ID.
+// Videos YouTube doesn't return, e.g. deleted or private ones, are left out.
+func (f *YouTubeStatsFetcher) VideoStats(ctx context.Context, videoIDs []string) (map[string]VideoStats, error) {
+	stats := make(map[string]VideoStats, len(videoIDs))
+	for start := 0; start < len(videoIDs); start += youtubeVideosPerRequest {
+		ids := videoIDs[start:min(start+youtubeVideosPerRequest, len(videoIDs))]
+		params := url.Values{
+			"part": {"statistics"},
+			"id":   {strings.Join(ids, ",")},
+			"key":  {f.apiKey},
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", f.baseURL+"/videos?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := f.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			resp.Body.Close()
+			return nil, fmt.Errorf("YouTube API error (status %d): %s", resp.StatusCode, string(body))
+		}
+
+		// Counts are strings, and hidden ones are missing
+		var result struct {
+			Items []struct {
+				ID         string `json:"id"`
+				Statistics struct {
+					ViewCount    int64 `json:"viewCount,string"`
+					LikeCount    int64 `json:"likeCount,string"`
+					CommentCount int64 `json:"commentCount,string"`
+				} `json:"statistics"`
+			} `json:"items"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode video statistics: %w", err)
+		}
+
+		for _, item := range result.Items {
+			stats[item.ID] = VideoStats{
+				Views:    item.Statistics.ViewCount,
+				Likes:    item.Statistics.LikeCount,
+				Comments: item.Statistics.CommentCount,
+			}
+		}
+	}
+	return stats, nil
+}